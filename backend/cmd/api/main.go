@@ -19,6 +19,7 @@ import (
 	"github.com/ceialmilk/api/internal/middleware"
 	"github.com/ceialmilk/api/internal/models"
 	"github.com/ceialmilk/api/internal/observability"
+	"github.com/ceialmilk/api/internal/oidc"
 	apidocs "github.com/ceialmilk/api/internal/openapi"
 	"github.com/ceialmilk/api/internal/repository"
 	"github.com/ceialmilk/api/internal/service"
@@ -108,7 +109,8 @@ func main() {
 	slog.Info("Rotas OpenAPI integracoes registradas: /api/v1/integracoes/openapi.yaml, /docs")
 
 	var apiRoutesRegistered bool
-	var alertasCronCancel context.CancelFunc
+	var jobsCancel context.CancelFunc
+	var eventosCancel context.CancelFunc
 	if cfg.DatabaseURL == "" {
 		slog.Warn("DATABASE_URL não definida: apenas /health disponível")
 	} else {
@@ -168,6 +170,8 @@ func main() {
 					folgasRepo := repository.NewFolgasRepository(pool)
 					folgasSvc := service.NewFolgasService(folgasRepo, fazendaSvc)
 					folgasHandler := handlers.NewFolgasHandler(folgasSvc)
+					calendarioSvc := service.NewCalendarioService(repository.NewCalendarioRepository(pool), folgasRepo, userRepo, fazendaSvc)
+					calendarioHandler := handlers.NewCalendarioHandler(calendarioSvc)
 					animalSaudeSvc := service.NewAnimalSaudeService(animalSaudeRepo, animalRepo)
 					animalSvc := service.NewAnimalService(animalRepo, fazendaRepo, gestacaoRepo, animalSaudeRepo)
 					animalVacinaRepo := repository.NewAnimalVacinaRepository(pool)
@@ -187,11 +191,32 @@ func main() {
 					restricaoLeiteRepo := repository.NewRestricaoLeiteRepository(pool)
 					restricaoLeiteSvc := service.NewRestricaoLeiteService(restricaoLeiteRepo, animalRepo, lactacaoRepo)
 					alertaRepo := repository.NewAlertaRepository(pool)
-					alertaSvc := service.NewAlertaService(alertaRepo, animalRepo)
+					alertaAtividadeRepo := repository.NewAlertaAtividadeRepository(pool)
+					alertaSvc := service.NewAlertaService(alertaRepo, animalRepo, alertaAtividadeRepo, fazendaRepo)
 					pushSubRepo := repository.NewPushSubscriptionRepository(pool)
 					pushSvc := service.NewPushNotificationService(cfg, pushSubRepo, fazendaRepo, alertaRepo)
 					alertaSvc.SetPushNotificationService(pushSvc)
+					// Tarefas (BR-TAREFA-001..004): consultam a escala de folgas e convertem alertas.
+					tarefaRepo := repository.NewTarefaRepository(pool)
+					tarefaSvc := service.NewTarefaService(tarefaRepo, folgasRepo, alertaRepo, alertaAtividadeRepo, fazendaRepo)
+					tarefaSvc.SetPushNotificationService(pushSvc)
+					tarefaHandler := handlers.NewTarefaHandler(tarefaSvc, fazendaSvc)
+					calendarioSvc.SetTarefaRepository(tarefaRepo)
 					pushHandler := handlers.NewPushHandler(pushSvc)
+					// Canais de notificação (BR-ALERTA-021): preferências por utilizador decidem canal, silêncio e resumo.
+					notificacaoRepo := repository.NewNotificacaoRepository(pool)
+					notificacaoSvc := service.NewNotificacaoService(cfg, notificacaoRepo, userRepo)
+					notificacaoSvc.RegistrarCanal(pushSvc)
+					notificacaoSvc.RegistrarCanal(service.NewSMTPSender(cfg))
+					notificacaoSvc.RegistrarCanal(service.NewHTTPMensagemSender(models.NotificacaoCanalSMS, cfg.SMSAPIURL, cfg.SMSAPIToken, cfg.AppBaseURL))
+					notificacaoSvc.RegistrarCanal(service.NewHTTPMensagemSender(models.NotificacaoCanalWhatsApp, cfg.WhatsAppAPIURL, cfg.WhatsAppAPIToken, cfg.AppBaseURL))
+					pushSvc.SetNotificacaoService(notificacaoSvc)
+					folgasSvc.SetNotificador(notificacaoSvc)
+					// Agendador de jobs (BR-JOBS-001): cada slot corre numa só réplica; histórico em jobs_execucoes.
+					jobScheduler := service.NewJobScheduler(repository.NewJobExecucaoRepository(pool))
+					jobScheduler.Registrar(service.NewJobPurgarHistoricoJobs(cfg, jobScheduler))
+					jobScheduler.Registrar(service.NewJobDigestNotificacoes(cfg, notificacaoSvc))
+					notificacaoHandler := handlers.NewNotificacaoHandler(notificacaoSvc)
 					animalBaixaSvc := service.NewAnimalBaixaService(pool, animalRepo, lactacaoRepo, gestacaoRepo, restricaoLeiteRepo)
 					refreshTokenSvc := service.NewRefreshTokenService(refreshTokenRepo)
 					cookieSameSite := http.SameSiteStrictMode
@@ -202,6 +227,13 @@ func main() {
 					fazendaHandler := handlers.NewFazendaHandler(fazendaSvc)
 					resumoPecuarioSvc := service.NewResumoPecuarioService(gestacaoRepo, restricaoLeiteRepo, producaoRepo, animalRepo)
 					resumoPecuarioHandler := handlers.NewResumoPecuarioHandler(resumoPecuarioSvc, fazendaSvc)
+					// Resumo periódico de alertas (BR-ALERTA-022): enviado de hora a hora pelo cron de alertas.
+					resumoAlertasRepo := repository.NewResumoAlertasRepository(pool)
+					resumoAlertasSvc := service.NewResumoAlertasService(cfg, resumoAlertasRepo, alertaRepo, fazendaRepo, resumoPecuarioSvc, animalHormonioRepo, animalVacinaRepo, notificacaoSvc)
+					resumoAlertasHandler := handlers.NewResumoAlertasHandler(resumoAlertasSvc)
+					rebanhoSnapshotRepo := repository.NewRebanhoSnapshotRepository(pool)
+					rebanhoSnapshotSvc := service.NewRebanhoSnapshotService(rebanhoSnapshotRepo, producaoRepo)
+					rebanhoSnapshotHandler := handlers.NewRebanhoSnapshotHandler(rebanhoSnapshotSvc, fazendaSvc)
 					restricaoLeiteHandler := handlers.NewRestricaoLeiteHandler(restricaoLeiteSvc, fazendaSvc)
 					alertaHandler := handlers.NewAlertaHandler(alertaSvc, fazendaSvc)
 					producaoHandler := handlers.NewProducaoHandler(producaoSvc, animalSvc, fazendaSvc, lactacaoSvc)
@@ -209,10 +241,18 @@ func main() {
 					adminHandler := handlers.NewAdminHandler(usuarioSvc, fazendaSvc)
 
 					loteSvc := service.NewLoteService(loteRepo, fazendaRepo)
-					movimentacaoLoteSvc := service.NewMovimentacaoLoteService(movimentacaoLoteRepo, animalRepo, loteRepo)
+					movimentacaoLoteSvc := service.NewMovimentacaoLoteService(pool, movimentacaoLoteRepo, animalRepo, loteRepo)
 					cioSvc := service.NewCioService(cioRepo, animalRepo, fazendaRepo)
 					loteHandler := handlers.NewLoteHandler(loteSvc, fazendaSvc)
 					movimentacaoLoteHandler := handlers.NewMovimentacaoLoteHandler(movimentacaoLoteSvc, animalSvc, fazendaSvc)
+					loteDesempenhoSvc := service.NewLoteDesempenhoService(repository.NewLoteDesempenhoRepository(pool), loteRepo, fazendaRepo)
+					loteDesempenhoHandler := handlers.NewLoteDesempenhoHandler(loteDesempenhoSvc, loteSvc, fazendaSvc)
+					// Ciclo de vida (BR-LOTE-005/006): transições de categoria diárias e propostas de lote aprovadas pela gestão.
+					animalPesagemSvc := service.NewAnimalPesagemService(repository.NewAnimalPesagemRepository(pool), animalRepo)
+					animalPesagemHandler := handlers.NewAnimalPesagemHandler(animalPesagemSvc, animalSvc, fazendaSvc)
+					cicloVidaSvc := service.NewCicloVidaService(repository.NewCicloVidaRepository(pool), loteRepo, animalRepo, movimentacaoLoteSvc, fazendaRepo)
+					jobScheduler.Registrar(service.NewJobCicloVida(cfg, cicloVidaSvc))
+					cicloVidaHandler := handlers.NewCicloVidaHandler(cicloVidaSvc, fazendaSvc)
 					cioHandler := handlers.NewCioHandler(cioSvc, fazendaSvc)
 					protocoloIatfSvc := service.NewProtocoloIATFService(protocoloIatfRepo, fazendaRepo)
 					coberturaSvc := service.NewCoberturaService(coberturaRepo, animalRepo, fazendaRepo, gestacaoRepo, diagnosticoGestacaoRepo, cioRepo)
@@ -223,10 +263,18 @@ func main() {
 					conformidadeSvc := service.NewConformidadeService(pool)
 					conformidadeHandler := handlers.NewConformidadeHandler(conformidadeSvc, fazendaSvc)
 					alertasEstadoRepo := repository.NewAlertasGeracaoEstadoRepository(pool)
+					alertaRegraRepo := repository.NewAlertaRegraRepository(pool)
+					alertaRegraSvc := service.NewAlertaRegraService(alertaRegraRepo)
+					alertaRegraHandler := handlers.NewAlertaRegraHandler(alertaRegraSvc, fazendaSvc)
+					alertaRegraCustomRepo := repository.NewAlertaRegraCustomRepository(pool)
+					alertaRegraCustomSvc := service.NewAlertaRegraCustomService(alertaRegraCustomRepo)
+					alertaRegraCustomHandler := handlers.NewAlertaRegraCustomHandler(alertaRegraCustomSvc, fazendaSvc)
 					alertaGeracaoLoc, locErr := time.LoadLocation(cfg.AlertasTZ)
 					if locErr != nil || cfg.AlertasTZ == "" {
 						alertaGeracaoLoc, _ = time.LoadLocation("America/Sao_Paulo")
 					}
+					jobScheduler.Registrar(service.NewJobResumoAlertas(cfg, resumoAlertasSvc))
+					jobScheduler.Registrar(service.NewJobEscalonarAlertas(cfg, alertaSvc))
 					var alertaGeracaoSvc *service.AlertaGeracaoService
 					alertaGeracaoSvc, geracaoErr := service.NewAlertaGeracaoService(
 						alertaRepo,
@@ -244,15 +292,15 @@ func main() {
 						slog.Warn("Geração automática de alertas indisponível", "error", geracaoErr)
 					} else {
 						alertaGeracaoSvc.SetPushNotificationService(pushSvc)
+						alertaGeracaoSvc.SetAlertaRegraService(alertaRegraSvc)
+						alertaGeracaoSvc.SetAlertaRegraCustomService(alertaRegraCustomSvc)
 						alertaGeracaoSvc.SetAnimalVacinaRepo(animalVacinaRepo)
 						alertaGeracaoSvc.SetAnimalHormonioLactacaoRepo(animalHormonioRepo)
 						animalSaudeSvc.SetAlertaAutoResolver(alertaGeracaoSvc)
 						animalVacinaSvc.SetAlertaAutoResolver(alertaGeracaoSvc)
 						animalHormonioSvc.SetAlertaAutoResolver(alertaGeracaoSvc)
 						restricaoLeiteSvc.SetAlertaAutoResolver(alertaGeracaoSvc)
-						cronCtx, cancel := context.WithCancel(context.Background())
-						alertasCronCancel = cancel
-						service.RunAlertasCron(cronCtx, cfg, alertaGeracaoSvc)
+						jobScheduler.Registrar(service.NewJobGerarAlertas(cfg, alertaGeracaoSvc))
 					}
 					var alertaAdminHandler *handlers.AlertaAdminHandler
 					if alertaGeracaoSvc != nil {
@@ -269,12 +317,140 @@ func main() {
 					if alertaGeracaoSvc != nil {
 						secagemSvc.SetAlertaAutoResolver(alertaGeracaoSvc)
 					}
+					// Trilha de auditoria (BR-AUDIT-012): services com mutações registam antes/depois.
+					auditoriaRepo := repository.NewAuditoriaRepository(pool)
+					auditoriaSvc := service.NewAuditoriaService(auditoriaRepo)
+					animalSvc.SetAuditoria(auditoriaSvc)
+					animalBaixaSvc.SetAuditoria(auditoriaSvc)
+					animalSaudeSvc.SetAuditoria(auditoriaSvc)
+					animalVacinaSvc.SetAuditoria(auditoriaSvc)
+					animalHormonioSvc.SetAuditoria(auditoriaSvc)
+					producaoSvc.SetAuditoria(auditoriaSvc)
+					lactacaoSvc.SetAuditoria(auditoriaSvc)
+					restricaoLeiteSvc.SetAuditoria(auditoriaSvc)
+					usuarioSvc.SetAuditoria(auditoriaSvc)
+					usuarioSvc.SetSessaoRevoker(refreshTokenSvc)
+					// Contas (BR-ACESSO-026): e-mails pelo SMTP dos alertas; fora de produção, sem SMTP, o link vai para o log.
+					var contaMailer service.MailSender = service.NewSMTPSender(cfg)
+					if !contaMailer.Enabled() && cfg.Env != "production" {
+						contaMailer = service.LogMailSender{}
+					}
+					contaSvc := service.NewContaService(repository.NewTokenContaRepository(pool), userRepo, contaMailer, cfg.AppBaseURL)
+					contaSvc.SetAuditoria(auditoriaSvc)
+					authHandler.SetContaService(contaSvc)
+					// 2FA TOTP (BR-ACESSO-027). Sem TOTP_ENCRYPTION_KEY a cifra deriva da chave JWT: rodar essa chave
+					// invalida os segredos já cadastrados.
+					jwtSvc.SetPerfisExigemDoisFatores(strings.Split(cfg.Auth2FAPerfisObrigatorios, ","))
+					chaveDoisFatores := cfg.TOTPEncryptionKey
+					if chaveDoisFatores == "" {
+						if cfg.Env == "production" {
+							slog.Warn("TOTP_ENCRYPTION_KEY não definida: segredos 2FA cifrados com chave derivada de JWT_PRIVATE_KEY")
+						}
+						chaveDoisFatores = privateKey
+					}
+					doisFatoresSvc, err := service.NewDoisFatoresService(repository.NewDoisFatoresRepository(pool), userRepo, chaveDoisFatores)
+					if err != nil {
+						slog.Error("Falha ao inicializar 2FA", "error", err)
+						os.Exit(1)
+					}
+					doisFatoresSvc.SetAuditoria(auditoriaSvc)
+					doisFatoresSvc.SetSessaoRevoker(refreshTokenSvc)
+					doisFatoresSvc.SetExigeDoisFatores(jwtSvc.ExigeDoisFatores)
+					authHandler.SetDoisFatoresService(doisFatoresSvc)
+					adminHandler.SetDoisFatoresService(doisFatoresSvc)
+					// Sessões por dispositivo (BR-ACESSO-028).
+					sessaoSvc := service.NewSessaoService(refreshTokenRepo, userRepo)
+					sessaoSvc.SetAuditoria(auditoriaSvc)
+					authHandler.SetSessaoService(sessaoSvc)
+					adminHandler.SetSessaoService(sessaoSvc)
+					// Login único OIDC (BR-ACESSO-029): provedor indisponível no arranque só desliga o botão.
+					if cfg.OIDCIssuer != "" {
+						gruposPerfis, err := service.ParseGruposPerfis(cfg.OIDCGruposPerfis)
+						if err != nil {
+							slog.Error("OIDC_GRUPOS_PERFIS inválido", "error", err)
+							os.Exit(1)
+						}
+						var scopes []string
+						for _, s := range strings.Split(cfg.OIDCScopes, ",") {
+							if s = strings.TrimSpace(s); s != "" {
+								scopes = append(scopes, s)
+							}
+						}
+						ctxOIDC, cancelOIDC := context.WithTimeout(context.Background(), 10*time.Second)
+						provedorOIDC, err := oidc.NewProvider(ctxOIDC, oidc.Config{
+							Issuer:       cfg.OIDCIssuer,
+							ClientID:     cfg.OIDCClientID,
+							ClientSecret: cfg.OIDCClientSecret,
+							RedirectURL:  cfg.OIDCRedirectURL,
+							Scopes:       scopes,
+						}, nil)
+						cancelOIDC()
+						if err != nil {
+							slog.Warn("Login único OIDC desligado: falha na descoberta do emissor", "issuer", cfg.OIDCIssuer, "error", err)
+						} else {
+							ssoSvc := service.NewSSOService(provedorOIDC, repository.NewIdentidadeOIDCRepository(pool), userRepo, service.MapeamentoSSO{
+								ClaimEmail:   cfg.OIDCClaimEmail,
+								ClaimNome:    cfg.OIDCClaimNome,
+								ClaimGrupos:  cfg.OIDCClaimGrupos,
+								GruposPerfis: gruposPerfis,
+							})
+							ssoSvc.SetAuditoria(auditoriaSvc)
+							frontendURL := cfg.AppBaseURL
+							if frontendURL == "" {
+								frontendURL = cfg.CORSOrigin
+							}
+							authHandler.SetSSOService(ssoSvc, cfg.OIDCNomeProvedor, frontendURL)
+							slog.Info("Login único OIDC habilitado", "issuer", provedorOIDC.Issuer())
+						}
+					}
+					conviteSvc := service.NewConviteService(repository.NewConviteRepository(pool), fazendaRepo, userRepo)
+					conviteSvc.SetAuditoria(auditoriaSvc)
+					authHandler.SetConviteService(conviteSvc)
+					conviteHandler := handlers.NewConviteHandler(conviteSvc)
+					fazendaSvc.SetAuditoria(auditoriaSvc)
+					loteSvc.SetAuditoria(auditoriaSvc)
+					movimentacaoLoteSvc.SetAuditoria(auditoriaSvc)
+					animalPesagemSvc.SetAuditoria(auditoriaSvc)
+					cicloVidaSvc.SetAuditoria(auditoriaSvc)
+					cioSvc.SetAuditoria(auditoriaSvc)
+					coberturaSvc.SetAuditoria(auditoriaSvc)
+					diagnosticoGestacaoSvc.SetAuditoria(auditoriaSvc)
+					gestacaoSvc.SetAuditoria(auditoriaSvc)
+					criaSvc.SetAuditoria(auditoriaSvc)
+					partoSvc.SetAuditoria(auditoriaSvc)
+					secagemSvc.SetAuditoria(auditoriaSvc)
+					// Eventos em tempo real (BR-ALERTA-024): NOTIFY no Postgres, cada réplica escuta e alimenta os seus streams SSE.
+					eventosSvc := service.NewEventosService(pool)
+					eventosCtx, eventosCtxCancel := context.WithCancel(context.Background())
+					eventosCancel = eventosCtxCancel
+					eventosSvc.Run(eventosCtx)
+					alertaSvc.SetEventos(eventosSvc)
+					if alertaGeracaoSvc != nil {
+						alertaGeracaoSvc.SetEventos(eventosSvc)
+					}
+					producaoSvc.SetEventos(eventosSvc)
+					restricaoLeiteSvc.SetEventos(eventosSvc)
+					eventosHandler := handlers.NewEventosHandler(eventosSvc, fazendaSvc)
+					auditoriaHandler := handlers.NewAuditoriaHandler(auditoriaSvc, animalSvc, fazendaSvc)
+					// Lixeira (BR-CICLO-020): partos, cios, coberturas e produção excluídos são restauráveis durante a retenção.
+					lixeiraRepo := repository.NewLixeiraRepository(pool)
+					lixeiraSvc := service.NewLixeiraService(lixeiraRepo, partoSvc, cioSvc, coberturaSvc, producaoSvc, cfg.LixeiraRetencaoDias)
+					jobScheduler.Registrar(service.NewJobPurgarLixeira(cfg, lixeiraSvc))
+					lixeiraHandler := handlers.NewLixeiraHandler(lixeiraSvc, fazendaSvc)
 					coberturaHandler := handlers.NewCoberturaHandler(coberturaSvc, fazendaSvc)
 					diagnosticoGestacaoHandler := handlers.NewDiagnosticoGestacaoHandler(diagnosticoGestacaoSvc, fazendaSvc, animalSvc)
 					integracaoRepo := repository.NewIntegracaoRepository(pool)
 					integracaoSvc := service.NewIntegracaoService(integracaoRepo, userRepo)
+					integracaoSvc.SetPoliticaChaves(cfg.IntegrationKeyValidadeDias, cfg.IntegrationKeyGraceHours, cfg.IntegrationKeyAvisoDias)
+					integracaoSvc.SetPushNotificationService(pushSvc)
+					jobScheduler.Registrar(service.NewJobAvisarChavesIntegracao(cfg, integracaoSvc))
+					jobsCtx, jobsCtxCancel := context.WithCancel(context.Background())
+					jobsCancel = jobsCtxCancel
+					jobScheduler.Run(jobsCtx)
+					jobAdminHandler := handlers.NewJobAdminHandler(jobScheduler)
 					integracaoHandler := handlers.NewIntegracaoHandler(integracaoSvc, animalSvc, diagnosticoGestacaoSvc, coberturaSvc, animalSaudeSvc, alertaSvc)
 					integracaoAdminHandler := handlers.NewIntegracaoAdminHandler(integracaoSvc)
+					integracaoOAuthHandler := handlers.NewIntegracaoOAuthHandler(integracaoSvc, jwtSvc, time.Duration(cfg.IntegrationTokenTTLMinutes)*time.Minute)
 					gestacaoHandler := handlers.NewGestacaoHandler(gestacaoSvc, fazendaSvc)
 					partoHandler := handlers.NewPartoHandler(partoSvc, fazendaSvc)
 					criaHandler := handlers.NewCriaHandler(criaSvc, fazendaSvc)
@@ -316,6 +492,10 @@ func main() {
 					if refreshLimit <= 0 {
 						refreshLimit = 30
 					}
+					resetLimit := cfg.AuthPasswordResetRateLimit
+					if resetLimit <= 0 {
+						resetLimit = 5
+					}
 					authPublic.POST("/register",
 						middleware.AuthRateLimit(middleware.AuthRateLimitConfig{Limit: registerLimit, Window: time.Hour}),
 						authHandler.Register,
@@ -324,6 +504,21 @@ func main() {
 						middleware.AuthRateLimit(middleware.AuthRateLimitConfig{Limit: loginLimit, Window: loginWindow}),
 						authHandler.Login,
 					)
+					// Segundo passo do login com 2FA (BR-ACESSO-027); mesmo limite do login contra tentativa de códigos
+					authPublic.POST("/login/2fa",
+						middleware.AuthRateLimit(middleware.AuthRateLimitConfig{Limit: loginLimit, Window: loginWindow}),
+						authHandler.LoginDoisFatores,
+					)
+					// Login único OIDC (BR-ACESSO-029); ida e volta do provedor no mesmo limite do login
+					authPublic.GET("/oidc", authHandler.OIDCConfig)
+					authPublic.GET("/oidc/login",
+						middleware.AuthRateLimit(middleware.AuthRateLimitConfig{Limit: loginLimit, Window: loginWindow}),
+						authHandler.OIDCLogin,
+					)
+					authPublic.GET("/oidc/callback",
+						middleware.AuthRateLimit(middleware.AuthRateLimitConfig{Limit: loginLimit, Window: loginWindow}),
+						authHandler.OIDCCallback,
+					)
 					authPublic.POST("/logout",
 						middleware.AuthRateLimit(middleware.AuthRateLimitConfig{Limit: refreshLimit * 2, Window: time.Hour}),
 						authHandler.Logout,
@@ -332,6 +527,25 @@ func main() {
 						middleware.AuthRateLimit(middleware.AuthRateLimitConfig{Limit: refreshLimit, Window: time.Hour}),
 						authHandler.Refresh,
 					)
+					// Prévia do convite no registro/login (BR-ACESSO-010); mesmo limite do login contra tentativa de códigos
+					authPublic.GET("/convites/:codigo",
+						middleware.AuthRateLimit(middleware.AuthRateLimitConfig{Limit: loginLimit, Window: loginWindow}),
+						conviteHandler.Preview,
+					)
+					// Recuperação de senha e verificação de e-mail (BR-ACESSO-026): o pedido envia e-mail, limite baixo;
+					// o consumo do token usa o limite do login contra tentativa de tokens
+					authPublic.POST("/forgot-password",
+						middleware.AuthRateLimit(middleware.AuthRateLimitConfig{Limit: resetLimit, Window: time.Hour}),
+						authHandler.ForgotPassword,
+					)
+					authPublic.POST("/reset-password",
+						middleware.AuthRateLimit(middleware.AuthRateLimitConfig{Limit: loginLimit, Window: loginWindow}),
+						authHandler.ResetPassword,
+					)
+					authPublic.POST("/verify-email",
+						middleware.AuthRateLimit(middleware.AuthRateLimitConfig{Limit: loginLimit, Window: loginWindow}),
+						authHandler.VerifyEmail,
+					)
 					// validate é chamado em cada carga de página; limite generoso só para conter abuso
 					authPublic.POST("/validate",
 						middleware.AuthRateLimit(middleware.AuthRateLimitConfig{Limit: refreshLimit * 20, Window: time.Hour}),
@@ -342,14 +556,53 @@ func main() {
 					me := api.Group("/v1/me", auth.AuthMiddleware(jwtSvc), auth.RequirePerfilAPIAccess())
 					{
 						me.GET("", authHandler.Me)
+						me.PUT("/senha",
+							middleware.AuthRateLimit(middleware.AuthRateLimitConfig{Limit: loginLimit, Window: loginWindow}),
+							authHandler.AlterarSenha,
+						)
+						me.POST("/verificacao-email",
+							middleware.AuthRateLimit(middleware.AuthRateLimitConfig{Limit: resetLimit, Window: time.Hour}),
+							authHandler.ReenviarVerificacao,
+						)
+						// 2FA TOTP (BR-ACESSO-027): liberado mesmo a perfis obrigados ainda sem segundo fator
+						me.GET("/2fa", authHandler.DoisFatoresStatus)
+						doisFatoresLimit := middleware.AuthRateLimit(middleware.AuthRateLimitConfig{Limit: loginLimit, Window: loginWindow})
+						me.POST("/2fa/iniciar", doisFatoresLimit, authHandler.IniciarDoisFatores)
+						me.POST("/2fa/ativar", doisFatoresLimit, authHandler.AtivarDoisFatores)
+						me.POST("/2fa/codigos-recuperacao", doisFatoresLimit, authHandler.RegenerarCodigosDoisFatores)
+						me.POST("/2fa/desativar", doisFatoresLimit, authHandler.DesativarDoisFatores)
+						me.GET("/sessoes", authHandler.ListSessoes)
+						me.DELETE("/sessoes", authHandler.RevogarSessoes)
+						me.DELETE("/sessoes/:sessaoId", authHandler.RevogarSessao)
 						me.GET("/fazendas", fazendaHandler.GetMinhasFazendas)
 						me.POST("/fazendas", fazendaHandler.CreateMinha)
 						me.PUT("/fazenda-ativa", pushHandler.UpdateFazendaAtiva)
 						me.GET("/push/vapid-public-key", pushHandler.GetVapidPublicKey)
 						me.PUT("/push-subscription", pushHandler.UpsertSubscription)
 						me.DELETE("/push-subscription", pushHandler.DeleteSubscription)
+						me.GET("/notificacoes", notificacaoHandler.GetPreferencias)
+						me.PUT("/notificacoes", notificacaoHandler.PutPreferencias)
+						me.POST("/notificacoes/teste", notificacaoHandler.EnviarTeste)
+						me.GET("/resumo-alertas", resumoAlertasHandler.GetConfig)
+						me.PUT("/resumo-alertas", resumoAlertasHandler.PutConfig)
+						me.GET("/resumo-alertas/previa", resumoAlertasHandler.Previa)
+						me.GET("/eventos", eventosHandler.Stream)
+						me.GET("/calendario", calendarioHandler.GetFeeds)
+						me.POST("/calendario", calendarioHandler.PostFeed)
+						me.DELETE("/calendario", calendarioHandler.DeleteFeeds)
+						me.DELETE("/calendario/:feedId", calendarioHandler.DeleteFeed)
+						me.POST("/convites/resgatar",
+							middleware.AuthRateLimit(middleware.AuthRateLimitConfig{Limit: loginLimit, Window: loginWindow}),
+							conviteHandler.Resgatar,
+						)
 					}
 
+					// Assinatura iCal (BR-FOLGAS-011): o token da URL é a credencial, sem JWT
+					api.GET("/v1/calendario/:token",
+						middleware.AuthRateLimit(middleware.AuthRateLimitConfig{Limit: refreshLimit * 20, Window: time.Hour}),
+						calendarioHandler.GetICS,
+					)
+
 					v1 := api.Group("/v1/fazendas", auth.AuthMiddleware(jwtSvc), auth.RequirePerfilAPIAccess())
 					{
 						v1.GET("", auth.RequireAdmin(), fazendaHandler.GetAll)
@@ -360,8 +613,17 @@ func main() {
 						v1.GET("/search/by-vacas-min", auth.RequireAdmin(), fazendaHandler.SearchByVacasMin)
 						v1.GET("/search/by-vacas-range", auth.RequireAdmin(), fazendaHandler.SearchByVacasRange)
 						v1.GET("/:id/usuarios-vinculados", fazendaHandler.GetUsuariosVinculados)
+						// Convites (BR-ACESSO-010): ADMIN/DEVELOPER ou PROPRIETARIO titular — validado no service
+						v1.GET("/:id/convites", conviteHandler.List)
+						v1.POST("/:id/convites", conviteHandler.Create)
+						v1.POST("/:id/convites/:conviteId/revogar", conviteHandler.Revogar)
 						v1.GET("/:id/resumo-pecuario", resumoPecuarioHandler.GetByFazendaID)
+						v1.GET("/:id/rebanho/snapshot", rebanhoSnapshotHandler.Get)
+						v1.GET("/:id/lotes/desempenho", loteDesempenhoHandler.GetFazenda)
 						v1.GET("/:id/auditoria/conformidade", conformidadeHandler.GetConformidade)
+						v1.GET("/:id/auditoria/eventos", auditoriaHandler.ListByFazenda)
+						v1.GET("/:id/lixeira", lixeiraHandler.List)
+						v1.POST("/:id/lixeira/:entidade/:itemId/restaurar", lixeiraHandler.Restaurar)
 						v1.GET("/:id", fazendaHandler.GetByID)
 						// Criar e editar fazendas requerem perfil ADMIN ou DEVELOPER; excluir: ADMIN/DEVELOPER/GESTAO/PROPRIETARIO
 						v1.POST("", auth.RequireAdmin(), fazendaHandler.Create)
@@ -383,9 +645,31 @@ func main() {
 						// Alertas proativos
 						v1.GET("/:id/alertas", alertaHandler.List)
 						v1.POST("/:id/alertas", alertaHandler.Create)
+						v1.GET("/:id/alertas/regras", alertaRegraHandler.List)
+						v1.PUT("/:id/alertas/regras/:tipo", alertaRegraHandler.Put)
+						v1.DELETE("/:id/alertas/regras/:tipo", alertaRegraHandler.Reset)
+						v1.GET("/:id/alertas/regras-custom", alertaRegraCustomHandler.List)
+						v1.POST("/:id/alertas/regras-custom", alertaRegraCustomHandler.Create)
+						v1.GET("/:id/alertas/regras-custom/variaveis", alertaRegraCustomHandler.Variaveis)
+						v1.POST("/:id/alertas/regras-custom/preview", alertaRegraCustomHandler.Preview)
+						v1.PUT("/:id/alertas/regras-custom/:regraId", alertaRegraCustomHandler.Update)
+						v1.DELETE("/:id/alertas/regras-custom/:regraId", alertaRegraCustomHandler.Delete)
 						v1.GET("/:id/alertas/:alertaId", alertaHandler.GetByID)
 						v1.PATCH("/:id/alertas/:alertaId/status", alertaHandler.UpdateStatus)
+						v1.PATCH("/:id/alertas/:alertaId/responsavel", alertaHandler.UpdateResponsavel)
+						v1.GET("/:id/alertas/:alertaId/atividades", alertaHandler.ListAtividades)
+						v1.POST("/:id/alertas/:alertaId/comentarios", alertaHandler.Comentar)
 						v1.DELETE("/:id/alertas/:alertaId", alertaHandler.Delete)
+						v1.POST("/:id/alertas/:alertaId/tarefa", tarefaHandler.ConverterAlerta)
+						// Tarefas / ordens de serviço (BR-TAREFA-001..004)
+						v1.GET("/:id/tarefas", tarefaHandler.List)
+						v1.POST("/:id/tarefas", tarefaHandler.Create)
+						v1.GET("/:id/tarefas/minhas-hoje", tarefaHandler.MinhasHoje)
+						v1.GET("/:id/tarefas/:tarefaId", tarefaHandler.GetByID)
+						v1.PUT("/:id/tarefas/:tarefaId", tarefaHandler.Update)
+						v1.DELETE("/:id/tarefas/:tarefaId", tarefaHandler.Delete)
+						v1.PATCH("/:id/tarefas/:tarefaId/status", tarefaHandler.UpdateStatus)
+						v1.PATCH("/:id/tarefas/:tarefaId/checklist/:itemId", tarefaHandler.MarcarChecklist)
 						// Módulo agrícola: fornecedores e áreas por fazenda
 						v1.GET("/:id/fornecedores/comparativo/:ano", resultadoAgricolaHandler.GetComparativoFornecedores)
 						v1.GET("/:id/fornecedores", fornecedorHandler.GetByFazendaID)
@@ -403,6 +687,22 @@ func main() {
 						v1.GET("/:id/folgas/alteracoes", folgasHandler.GetAlteracoes)
 						v1.GET("/:id/folgas/alertas", folgasHandler.GetAlertas)
 						v1.GET("/:id/folgas/resumo-equidade", folgasHandler.GetResumoEquidade)
+						v1.GET("/:id/folgas/trocas", folgasHandler.GetTrocas)
+						v1.POST("/:id/folgas/trocas", folgasHandler.PostTroca)
+						v1.POST("/:id/folgas/trocas/:trocaId/resposta", folgasHandler.PostTrocaResposta)
+						v1.POST("/:id/folgas/trocas/:trocaId/decisao", auth.RequireGestaoFolgas(), folgasHandler.PostTrocaDecisao)
+						v1.POST("/:id/folgas/trocas/:trocaId/cancelar", folgasHandler.PostTrocaCancelar)
+						v1.GET("/:id/folgas/ausencias", folgasHandler.GetAusencias)
+						v1.POST("/:id/folgas/ausencias", auth.RequireGestaoFolgas(), folgasHandler.PostAusencia)
+						v1.DELETE("/:id/folgas/ausencias/:ausenciaId", auth.RequireGestaoFolgas(), folgasHandler.DeleteAusencia)
+						v1.GET("/:id/folgas/ferias-saldo", folgasHandler.GetFeriasSaldo)
+						v1.PUT("/:id/folgas/ferias-saldo/:usuarioId", auth.RequireGestaoFolgas(), folgasHandler.PutFeriasDireito)
+						v1.GET("/:id/ciclo-vida/config", cicloVidaHandler.GetConfig)
+						v1.PUT("/:id/ciclo-vida/config", cicloVidaHandler.PutConfig)
+						v1.POST("/:id/ciclo-vida/executar", cicloVidaHandler.Executar)
+						v1.GET("/:id/movimentacoes-lote/propostas", cicloVidaHandler.ListPropostas)
+						v1.POST("/:id/movimentacoes-lote/propostas/aprovar", cicloVidaHandler.AprovarPropostas)
+						v1.POST("/:id/movimentacoes-lote/propostas/rejeitar", cicloVidaHandler.RejeitarPropostas)
 						v1.GET("/:id/hormonios-lactacao/pendentes", animalHormonioHandler.ListPendentes)
 					}
 
@@ -420,9 +720,13 @@ func main() {
 						animais.POST("/reclassificar-categoria", animalHandler.RunReclassificacaoPorIdade)
 						animais.GET("/:id/contexto", animalHandler.GetContextoByID)
 						animais.GET("/:id/timeline", animalHandler.GetTimelineByID)
+						animais.GET("/:id/auditoria/eventos", auditoriaHandler.ListByAnimal)
 						animais.GET("/:id/saude", animalSaudeHandler.List)
 						animais.GET("/:id/saude/:saudeId", animalSaudeHandler.GetByID)
 						animais.POST("/:id/saude", animalSaudeHandler.Create)
+						animais.GET("/:id/pesagens", animalPesagemHandler.List)
+						animais.POST("/:id/pesagens", animalPesagemHandler.Create)
+						animais.DELETE("/:id/pesagens/:pesagemId", animalPesagemHandler.Delete)
 						animais.PUT("/:id/saude/:saudeId", animalSaudeHandler.Update)
 						animais.DELETE("/:id/saude/:saudeId", animalSaudeHandler.Delete)
 						animais.GET("/:id/vacinas", animalVacinaHandler.List)
@@ -472,6 +776,9 @@ func main() {
 						lotes.POST("", loteHandler.Create)
 						lotes.PUT("/:id", loteHandler.Update)
 						lotes.DELETE("/:id", loteHandler.Delete)
+						lotes.POST("/:id/movimentar", movimentacaoLoteHandler.MovimentarEmLote)
+						lotes.GET("/:id/ocupacao", movimentacaoLoteHandler.Ocupacao)
+						lotes.GET("/:id/desempenho", loteDesempenhoHandler.GetLote)
 					}
 					// Movimentar animal de lote
 					animais.POST("/:id/movimentar-lote", movimentacaoLoteHandler.Movimentar)
@@ -585,19 +892,31 @@ func main() {
 						admin.POST("/usuarios", adminHandler.CreateUsuario)
 						admin.PUT("/usuarios/:id", adminHandler.UpdateUsuario)
 						admin.PATCH("/usuarios/:id/toggle-enabled", adminHandler.ToggleEnabled)
+						admin.DELETE("/usuarios/:id/2fa", adminHandler.RedefinirDoisFatores)
+						admin.GET("/usuarios/:id/sessoes", adminHandler.ListSessoesUsuario)
+						admin.DELETE("/usuarios/:id/sessoes", adminHandler.RevogarSessoesUsuario)
+						admin.DELETE("/usuarios/:id/sessoes/:sessaoId", adminHandler.RevogarSessoesUsuario)
 						admin.GET("/usuarios/:id/fazendas", adminHandler.GetUsuarioFazendas)
+						admin.GET("/auditoria/usuarios/:id", auditoriaHandler.ListByUsuario)
 						admin.PUT("/usuarios/:id/fazendas", adminHandler.SetUsuarioFazendas)
 						admin.GET("/integracoes", integracaoAdminHandler.List)
 						admin.POST("/integracoes", integracaoAdminHandler.Create)
+						admin.GET("/integracoes/rejeicoes", integracaoAdminHandler.ListRejeicoes)
 						admin.GET("/integracoes/:id", integracaoAdminHandler.GetByID)
 						admin.PATCH("/integracoes/:id", integracaoAdminHandler.Update)
 						admin.POST("/integracoes/:id/rotacionar-chave", integracaoAdminHandler.RotacionarChave)
 						admin.POST("/integracoes/:id/revogar", integracaoAdminHandler.Revogar)
 						admin.POST("/integracoes/:id/reativar", integracaoAdminHandler.Reativar)
 						admin.GET("/integracoes/:id/chamadas", integracaoAdminHandler.ListChamadas)
+						admin.POST("/integracoes/:id/oauth/credenciais", integracaoAdminHandler.GerarCredenciaisOAuth)
+						admin.POST("/integracoes/:id/oauth/revogar-tokens", integracaoAdminHandler.RevogarTokensOAuth)
 						if alertaAdminHandler != nil {
 							admin.POST("/alertas/gerar", alertaAdminHandler.GerarAlertasDiarios)
 						}
+						admin.GET("/jobs", jobAdminHandler.List)
+						admin.GET("/jobs/execucoes", jobAdminHandler.ListExecucoes)
+						admin.GET("/jobs/execucoes/:id", jobAdminHandler.GetExecucao)
+						admin.POST("/jobs/:nome/executar", jobAdminHandler.Executar)
 					}
 					slog.Info("Rotas de Admin registradas")
 
@@ -605,10 +924,20 @@ func main() {
 					if integracaoRL <= 0 {
 						integracaoRL = 300
 					}
+					// OAuth2 client credentials: público (autentica pelo client_id/secret), limitado por IP
+					api.POST("/v1/integracoes/oauth/token",
+						middleware.AuthRateLimit(middleware.AuthRateLimitConfig{Limit: integracaoRL, Window: time.Hour}),
+						middleware.IntegrationAuditMiddleware(integracaoSvc),
+						integracaoOAuthHandler.Token,
+					)
+					// Auditoria antes da autenticação: rejeições também ficam registadas (BR-INTEG-016).
+					// O limite por IP vem primeiro para que rejeições sem cliente não virem INSERTs ilimitados;
+					// folga de 4x para vários clientes atrás do mesmo IP.
 					integ := api.Group("/v1/integracoes",
-						auth.IntegrationAuthMiddleware(integracaoSvc),
-						middleware.IntegrationRateLimit(integracaoRL),
+						middleware.AuthRateLimit(middleware.AuthRateLimitConfig{Limit: integracaoRL * 4, Window: time.Hour}),
 						middleware.IntegrationAuditMiddleware(integracaoSvc),
+						auth.IntegrationAuthMiddleware(integracaoSvc, jwtSvc),
+						middleware.IntegrationRateLimit(integracaoRL),
 					)
 					{
 						integ.GET("/me", integracaoHandler.Me)
@@ -764,8 +1093,11 @@ func main() {
 	<-quit
 
 	slog.Info("Encerrando servidor...")
-	if alertasCronCancel != nil {
-		alertasCronCancel()
+	if jobsCancel != nil {
+		jobsCancel()
+	}
+	if eventosCancel != nil {
+		eventosCancel()
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
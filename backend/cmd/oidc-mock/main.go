@@ -0,0 +1,49 @@
+// Comando oidc-mock: emissor OpenID Connect local para testar o login único (BR-ACESSO-029) sem um
+// provedor real. Aprova todo pedido de autorização com as claims das variáveis OIDC_MOCK_*.
+//
+//	go run ./cmd/oidc-mock
+//	OIDC_ISSUER=http://localhost:9099 OIDC_CLIENT_ID=ceialmilk \
+//	OIDC_REDIRECT_URL=http://localhost:8080/api/auth/oidc/callback go run ./cmd/api
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/ceialmilk/api/internal/oidc/oidctest"
+)
+
+func main() {
+	addr := getEnv("OIDC_MOCK_ADDR", ":9099")
+	issuer := getEnv("OIDC_MOCK_ISSUER", "http://localhost"+addr)
+	iss, err := oidctest.New(strings.TrimSuffix(issuer, "/"), getEnv("OIDC_CLIENT_ID", "ceialmilk"), os.Getenv("OIDC_CLIENT_SECRET"))
+	if err != nil {
+		slog.Error("Falha ao gerar a chave do emissor", "error", err)
+		os.Exit(1)
+	}
+	claims := map[string]interface{}{
+		"sub":            getEnv("OIDC_MOCK_SUB", "mock-produtor"),
+		"email":          getEnv("OIDC_MOCK_EMAIL", "produtor@example.com"),
+		"email_verified": getEnv("OIDC_MOCK_EMAIL_VERIFIED", "true") == "true",
+		"name":           getEnv("OIDC_MOCK_NAME", "Produtor Teste"),
+	}
+	if grupos := os.Getenv("OIDC_MOCK_GROUPS"); grupos != "" {
+		claims["groups"] = strings.Split(grupos, ",")
+	}
+	iss.SetClaims(claims)
+
+	slog.Info("Emissor OIDC de teste", "issuer", iss.URL, "addr", addr, "email", claims["email"])
+	if err := http.ListenAndServe(addr, iss); err != nil {
+		slog.Error("Emissor OIDC de teste encerrado", "error", err)
+		os.Exit(1)
+	}
+}
+
+func getEnv(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
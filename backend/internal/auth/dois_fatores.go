@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// DesafioDoisFatoresAudience token intermédio do login com 2FA: prova que a senha foi validada e
+// só serve para POST /api/auth/login/2fa (ValidateToken recusa tokens com audience).
+const DesafioDoisFatoresAudience = "ceialmilk-2fa"
+
+// DesafioDoisFatoresTTL tempo para digitar o código do autenticador depois da senha.
+const DesafioDoisFatoresTTL = 5 * time.Minute
+
+// GenerateDesafioDoisFatores assina o desafio do segundo passo do login. O jti e a expiração devolvidos
+// são registados pelo chamador: o desafio é de uso único e com tentativas limitadas.
+func (j *JWTService) GenerateDesafioDoisFatores(userID int64) (token, jti string, expiraEm time.Time, err error) {
+	now := time.Now()
+	jti = uuid.New().String()
+	expiraEm = now.Add(DesafioDoisFatoresTTL)
+	claims := jwt.RegisteredClaims{
+		Subject:   strconv.FormatInt(userID, 10),
+		Audience:  jwt.ClaimStrings{DesafioDoisFatoresAudience},
+		ID:        jti,
+		ExpiresAt: jwt.NewNumericDate(expiraEm),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+	}
+	token, err = jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(j.privateKey)
+	return token, jti, expiraEm, err
+}
+
+// ValidateDesafioDoisFatores devolve o usuário e o jti do desafio com assinatura e prazo válidos.
+func (j *JWTService) ValidateDesafioDoisFatores(tokenString string) (int64, string, error) {
+	if j.publicKey == nil {
+		return 0, "", errors.New("desafio inválido")
+	}
+	token, err := jwt.ParseWithClaims(tokenString, &jwt.RegisteredClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, errors.New("método de assinatura inválido")
+		}
+		return j.publicKey, nil
+	}, jwt.WithAudience(DesafioDoisFatoresAudience))
+	if err != nil {
+		return 0, "", err
+	}
+	claims, ok := token.Claims.(*jwt.RegisteredClaims)
+	if !ok || !token.Valid || claims.ID == "" {
+		return 0, "", errors.New("desafio inválido")
+	}
+	userID, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil || userID <= 0 {
+		return 0, "", errors.New("desafio inválido")
+	}
+	return userID, claims.ID, nil
+}
+
+// SetPerfisExigemDoisFatores perfis obrigados a 2FA (AUTH_2FA_PERFIS_OBRIGATORIOS).
+func (j *JWTService) SetPerfisExigemDoisFatores(perfis []string) {
+	j.perfisExigemDoisFatores = map[string]bool{}
+	for _, p := range perfis {
+		if p = strings.ToUpper(strings.TrimSpace(p)); p != "" {
+			j.perfisExigemDoisFatores[p] = true
+		}
+	}
+}
+
+// ExigeDoisFatores indica se o perfil só acede à API com sessão AAL 2.
+func (j *JWTService) ExigeDoisFatores(perfil string) bool {
+	return j != nil && j.perfisExigemDoisFatores[perfil]
+}
+
+// rotaLiberadaSemDoisFatores o que uma sessão de perfil obrigado a 2FA, ainda sem o segundo fator,
+// pode chamar: o próprio perfil, a inscrição no 2FA, a troca de senha e as sessões (BR-ACESSO-028).
+func rotaLiberadaSemDoisFatores(method, path string) bool {
+	if isMeProfileRoute(method, path) {
+		return true
+	}
+	for _, prefixo := range []string{"/api/v1/me/2fa", "/api/v1/me/sessoes"} {
+		if path == prefixo || strings.HasPrefix(path, prefixo+"/") {
+			return true
+		}
+	}
+	return path == "/api/v1/me/senha"
+}
+
+// GetAAL nível de garantia da sessão posto pelo AuthMiddleware.
+func GetAAL(c *gin.Context) int {
+	if v, ok := c.Get("aal"); ok {
+		if aal, ok := v.(int); ok {
+			return aal
+		}
+	}
+	return AALSenha
+}
@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/oidc"
+	"github.com/gin-gonic/gin"
+)
+
+func TestDesafioDoisFatores(t *testing.T) {
+	svc := newTestJWTService(t)
+	desafio, jti, expiraEm, err := svc.GenerateDesafioDoisFatores(42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Until(expiraEm) > DesafioDoisFatoresTTL || time.Until(expiraEm) < DesafioDoisFatoresTTL-time.Minute {
+		t.Errorf("expira_em = %v", expiraEm)
+	}
+	userID, gotJTI, err := svc.ValidateDesafioDoisFatores(desafio)
+	if err != nil || userID != 42 || gotJTI != jti || jti == "" {
+		t.Fatalf("desafio: user %d jti %q (want %q) err %v", userID, gotJTI, jti, err)
+	}
+	if _, err := svc.ValidateToken(desafio); err == nil {
+		t.Error("desafio de 2FA não pode autenticar rotas de utilizador")
+	}
+	access, _ := svc.GenerateToken(42, "a@b.pt", models.PerfilAdmin)
+	if _, _, err := svc.ValidateDesafioDoisFatores(access); err == nil {
+		t.Error("access token não pode servir de desafio")
+	}
+}
+
+func TestGenerateTokenAAL(t *testing.T) {
+	svc := newTestJWTService(t)
+	tok, _ := svc.GenerateTokenAAL(1, "a@b.pt", models.PerfilAdmin, AALDoisFatores)
+	claims, err := svc.ValidateToken(tok)
+	if err != nil || claims.NivelGarantia() != AALDoisFatores {
+		t.Fatalf("aal = %v err %v", claims, err)
+	}
+	tok, _ = svc.GenerateToken(1, "a@b.pt", models.PerfilAdmin)
+	claims, _ = svc.ValidateToken(tok)
+	if claims.NivelGarantia() != AALSenha {
+		t.Errorf("GenerateToken deve emitir AAL 1, got %d", claims.NivelGarantia())
+	}
+}
+
+func TestAuthMiddleware_DoisFatoresObrigatorio(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := newTestJWTService(t)
+	svc.SetPerfisExigemDoisFatores([]string{"admin", " GESTAO "})
+	senha, _ := svc.GenerateToken(1, "a@b.pt", models.PerfilAdmin)
+	totp, _ := svc.GenerateTokenAAL(1, "a@b.pt", models.PerfilAdmin, AALDoisFatores)
+	gerente, _ := svc.GenerateToken(2, "g@b.pt", models.PerfilGerente)
+
+	casos := []struct {
+		nome, token, method, path string
+		want                      int
+	}{
+		{"admin sem 2FA bloqueado", senha, http.MethodGet, "/api/v1/fazendas", http.StatusForbidden},
+		{"admin sem 2FA vê o perfil", senha, http.MethodGet, "/api/v1/me", http.StatusOK},
+		{"admin sem 2FA inscreve-se", senha, http.MethodPost, "/api/v1/me/2fa/iniciar", http.StatusOK},
+		{"admin sem 2FA encerra sessões", senha, http.MethodDelete, "/api/v1/me/sessoes/3", http.StatusOK},
+		{"admin sem 2FA não usa outras rotas /me", senha, http.MethodGet, "/api/v1/me/fazendas", http.StatusForbidden},
+		{"admin com 2FA", totp, http.MethodGet, "/api/v1/fazendas", http.StatusOK},
+		{"perfil não obrigado", gerente, http.MethodGet, "/api/v1/fazendas", http.StatusOK},
+	}
+	for _, c := range casos {
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request = httptest.NewRequest(c.method, c.path, nil)
+		ctx.Request.Header.Set("Authorization", "Bearer "+c.token)
+		AuthMiddleware(svc)(ctx)
+		got := http.StatusOK
+		if ctx.IsAborted() {
+			got = w.Code
+		}
+		if got != c.want {
+			t.Errorf("%s: status %d, want %d", c.nome, got, c.want)
+		}
+	}
+}
+
+func TestFluxoOIDC(t *testing.T) {
+	svc := newTestJWTService(t)
+	f := oidc.Fluxo{State: "s", Nonce: "n", Verifier: "v", Redirect: "/animais"}
+	tok, err := svc.GenerateFluxoOIDC(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := svc.ValidateFluxoOIDC(tok)
+	if err != nil || got != f {
+		t.Fatalf("fluxo = %+v err %v", got, err)
+	}
+	if _, err := svc.ValidateToken(tok); err == nil {
+		t.Error("cookie do fluxo SSO não pode autenticar rotas de utilizador")
+	}
+	desafio, _, _, _ := svc.GenerateDesafioDoisFatores(1)
+	if _, err := svc.ValidateFluxoOIDC(desafio); err == nil {
+		t.Error("desafio de 2FA não pode servir de fluxo SSO")
+	}
+}
@@ -1,48 +1,119 @@
 package auth
 
 import (
+	"errors"
 	"strings"
 
 	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/requestctx"
 	"github.com/ceialmilk/api/internal/response"
 	"github.com/ceialmilk/api/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
 const (
-	ContextAuthKind             = "auth_kind"
-	ContextIntegrationClientID  = "integration_client_id"
-	ContextIntegrationScopes    = "integration_scopes"
+	ContextAuthKind              = "auth_kind"
+	ContextIntegrationClientID   = "integration_client_id"
+	ContextIntegrationScopes     = "integration_scopes"
 	ContextIntegrationFazendaIDs = "integration_fazenda_ids"
-	AuthKindIntegration         = "integration"
-	AuthKindJWT                 = "jwt"
+	AuthKindIntegration          = "integration"
+	AuthKindJWT                  = "jwt"
+	ContextIntegrationAuthMethod = "integration_auth_method"
+	IntegrationAuthMethodAPIKey  = "api_key"
+	IntegrationAuthMethodOAuth   = "oauth"
+	// Rejeição M2M: motivo (models.IntegracaoRejeicao*) e cliente, quando identificado, lidos pela auditoria.
+	ContextIntegrationRejeicaoMotivo    = "integration_rejeicao_motivo"
+	ContextIntegrationRejeicaoClienteID = "integration_rejeicao_cliente_id"
 )
 
-// IntegrationAuthMiddleware autentica clientes M2M via Bearer cmk_live_...
-func IntegrationAuthMiddleware(integracaoSvc *service.IntegracaoService) gin.HandlerFunc {
+// SetIntegrationRejeicao marca o pedido como rejeitado para IntegrationAuditMiddleware (clienteID 0 = desconhecido).
+func SetIntegrationRejeicao(c *gin.Context, clienteID int64, motivo string) {
+	c.Set(ContextIntegrationRejeicaoMotivo, motivo)
+	if clienteID > 0 {
+		c.Set(ContextIntegrationRejeicaoClienteID, clienteID)
+	}
+}
+
+// GetIntegrationRejeicao devolve o motivo da rejeição e o cliente (0 se não identificado).
+func GetIntegrationRejeicao(c *gin.Context) (motivo string, clienteID int64, ok bool) {
+	v, exists := c.Get(ContextIntegrationRejeicaoMotivo)
+	if !exists {
+		return "", 0, false
+	}
+	motivo, _ = v.(string)
+	if idv, exists := c.Get(ContextIntegrationRejeicaoClienteID); exists {
+		clienteID, _ = idv.(int64)
+	}
+	return motivo, clienteID, motivo != ""
+}
+
+// abortIntegrationRejeicao responde 401 (ou 403 para origem não permitida) e regista o motivo.
+func abortIntegrationRejeicao(c *gin.Context, err error, motivoPadrao, msg string) {
+	motivo, clienteID := motivoPadrao, int64(0)
+	var rej *service.IntegracaoRejeicaoError
+	if errors.As(err, &rej) {
+		motivo, clienteID = rej.Motivo, rej.ClienteID
+	}
+	SetIntegrationRejeicao(c, clienteID, motivo)
+	switch motivo {
+	case models.IntegracaoRejeicaoIPNaoPermitido:
+		response.ErrorForbidden(c, "Origem nao permitida para este cliente de integracao")
+	case models.IntegracaoRejeicaoChaveExpirada, models.IntegracaoRejeicaoChaveAnteriorExpirada:
+		response.ErrorUnauthorized(c, "Chave de integracao expirada")
+	default:
+		response.ErrorUnauthorized(c, msg)
+	}
+	c.Abort()
+}
+
+// IntegrationAuthMiddleware autentica clientes M2M via Bearer cmk_live_... (API key) ou
+// access token OAuth2 emitido em /api/v1/integracoes/oauth/token. jwtSvc nil → só API key.
+// Após autenticar aplica a allowlist de IPs do cliente; toda rejeição fica marcada para a auditoria.
+func IntegrationAuthMiddleware(integracaoSvc *service.IntegracaoService, jwtSvc *JWTService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			response.ErrorUnauthorized(c, "Token de integracao nao fornecido")
-			c.Abort()
+			abortIntegrationRejeicao(c, nil, models.IntegracaoRejeicaoTokenAusente, "Token de integracao nao fornecido")
 			return
 		}
 		parts := strings.SplitN(authHeader, " ", 2)
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			response.ErrorUnauthorized(c, "Formato de token invalido")
-			c.Abort()
+			abortIntegrationRejeicao(c, nil, models.IntegracaoRejeicaoFormatoInvalido, "Formato de token invalido")
 			return
 		}
 		token := parts[1]
-		if !service.ValidateAPIKeyFormat(token) {
-			response.ErrorUnauthorized(c, "Chave de integracao invalida")
-			c.Abort()
-			return
+		var cliente *models.IntegracaoCliente
+		if service.ValidateAPIKeyFormat(token) {
+			resolved, err := integracaoSvc.ResolveClienteByAPIKey(c.Request.Context(), token)
+			if err != nil {
+				abortIntegrationRejeicao(c, err, models.IntegracaoRejeicaoErroInterno, "Chave de integracao invalida ou revogada")
+				return
+			}
+			cliente = resolved
+			c.Set(ContextIntegrationAuthMethod, IntegrationAuthMethodAPIKey)
+		} else {
+			if jwtSvc == nil {
+				abortIntegrationRejeicao(c, nil, models.IntegracaoRejeicaoFormatoInvalido, "Chave de integracao invalida")
+				return
+			}
+			claims, err := jwtSvc.ValidateIntegrationToken(token)
+			if err != nil {
+				abortIntegrationRejeicao(c, nil, models.IntegracaoRejeicaoTokenInvalido, "Token de integracao invalido ou expirado")
+				return
+			}
+			resolved, err := integracaoSvc.ResolveClienteByAccessToken(c.Request.Context(), claims.ClienteID, claims.EmitidoEm())
+			if err != nil {
+				abortIntegrationRejeicao(c, err, models.IntegracaoRejeicaoErroInterno, "Token de integracao revogado ou cliente inativo")
+				return
+			}
+			// O token nunca amplia permissões: vale a interseção com o estado atual do cliente.
+			resolved.Scopes = service.IntersectScopes(claims.Scopes(), resolved.Scopes)
+			resolved.FazendaIDs = service.IntersectFazendaIDs(claims.FazendaIDs, resolved.FazendaIDs)
+			cliente = resolved
+			c.Set(ContextIntegrationAuthMethod, IntegrationAuthMethodOAuth)
 		}
-		cliente, err := integracaoSvc.ResolveClienteByAPIKey(c.Request.Context(), token)
-		if err != nil {
-			response.ErrorUnauthorized(c, "Chave de integracao invalida ou revogada")
-			c.Abort()
+		if err := integracaoSvc.VerificarOrigem(cliente, c.ClientIP()); err != nil {
+			abortIntegrationRejeicao(c, err, models.IntegracaoRejeicaoIPNaoPermitido, "Origem nao permitida")
 			return
 		}
 		c.Set(ContextAuthKind, AuthKindIntegration)
@@ -51,6 +122,7 @@ func IntegrationAuthMiddleware(integracaoSvc *service.IntegracaoService) gin.Han
 		c.Set(ContextIntegrationClientID, cliente.ID)
 		c.Set(ContextIntegrationScopes, cliente.Scopes)
 		c.Set(ContextIntegrationFazendaIDs, cliente.FazendaIDs)
+		c.Request = c.Request.WithContext(requestctx.WithAtor(c.Request.Context(), requestctx.Ator{UsuarioID: cliente.ActorUserID, Perfil: models.PerfilIntegracao}))
 		c.Next()
 	}
 }
@@ -71,6 +143,9 @@ func RequireIntegrationScope(scope string) gin.HandlerFunc {
 				return
 			}
 		}
+		if clienteID, ok := GetIntegrationClientID(c); ok {
+			SetIntegrationRejeicao(c, clienteID, models.IntegracaoRejeicaoScopeInsuficiente)
+		}
 		response.ErrorForbidden(c, "Scope necessario: "+scope)
 		c.Abort()
 	}
@@ -23,3 +23,22 @@ func TestHasIntegrationScope(t *testing.T) {
 		t.Fatal("expected no coberturas:read")
 	}
 }
+
+func TestRequireIntegrationScope_MarcaRejeicao(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/v1/integracoes/alertas", nil)
+	c.Set(ContextIntegrationClientID, int64(3))
+	c.Set(ContextIntegrationScopes, []string{models.ScopeAnimaisRead})
+
+	RequireIntegrationScope(models.ScopeAlertasRead)(c)
+
+	if w.Code != 403 {
+		t.Fatalf("expected 403, got %d", w.Code)
+	}
+	motivo, clienteID, ok := GetIntegrationRejeicao(c)
+	if !ok || motivo != models.IntegracaoRejeicaoScopeInsuficiente || clienteID != 3 {
+		t.Fatalf("expected rejeição SCOPE_INSUFICIENTE do cliente 3, got %q %d %v", motivo, clienteID, ok)
+	}
+}
@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// IntegrationTokenAudience distingue access tokens OAuth de integração dos JWT de utilizador
+// (mesmo par de chaves RSA): um não é aceito no lugar do outro.
+const IntegrationTokenAudience = "ceialmilk-integracoes"
+
+// IntegrationClaims claims do access token emitido em POST /api/v1/integracoes/oauth/token.
+type IntegrationClaims struct {
+	ClienteID  int64   `json:"cliente_id"`
+	FazendaIDs []int64 `json:"fazenda_ids"`
+	Scope      string  `json:"scope"`
+	// IssuedAtMicro repete o iat em microssegundos: o iat padrão tem resolução de segundo e não
+	// separa um token emitido no mesmo segundo de tokens_validos_desde.
+	IssuedAtMicro int64 `json:"iat_us,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// EmitidoEm devolve o instante de emissão com a maior precisão disponível (iat_us ou, em tokens
+// sem o claim, o iat em segundos).
+func (c *IntegrationClaims) EmitidoEm() time.Time {
+	if c.IssuedAtMicro > 0 {
+		return time.UnixMicro(c.IssuedAtMicro)
+	}
+	return c.IssuedAt.Time
+}
+
+// Scopes devolve a lista de scopes do claim `scope` (separado por espaços).
+func (c *IntegrationClaims) Scopes() []string {
+	return strings.Fields(c.Scope)
+}
+
+// GenerateIntegrationToken assina um access token de curta duração para o cliente M2M.
+func (j *JWTService) GenerateIntegrationToken(clienteID int64, fazendaIDs []int64, scopes []string, ttl time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	claims := IntegrationClaims{
+		ClienteID:     clienteID,
+		FazendaIDs:    fazendaIDs,
+		Scope:         strings.Join(scopes, " "),
+		IssuedAtMicro: now.UnixMicro(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   fmt.Sprintf("integracao:%d", clienteID),
+			Audience:  jwt.ClaimStrings{IntegrationTokenAudience},
+			ID:        uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	signed, err := token.SignedString(j.privateKey)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// ValidateIntegrationToken valida assinatura, expiração e audience do access token M2M.
+// Revogação (cliente revogado / tokens_validos_desde) é verificada depois, no IntegracaoService.
+func (j *JWTService) ValidateIntegrationToken(tokenString string) (*IntegrationClaims, error) {
+	if j.publicKey == nil {
+		return nil, errors.New("token inválido")
+	}
+	token, err := jwt.ParseWithClaims(tokenString, &IntegrationClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, errors.New("método de assinatura inválido")
+		}
+		return j.publicKey, nil
+	}, jwt.WithAudience(IntegrationTokenAudience), jwt.WithIssuedAt())
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := token.Claims.(*IntegrationClaims)
+	if !ok || !token.Valid || claims.ClienteID <= 0 || claims.IssuedAt == nil {
+		return nil, errors.New("token inválido")
+	}
+	return claims, nil
+}
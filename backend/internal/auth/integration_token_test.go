@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ceialmilk/api/internal/config"
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/service"
+)
+
+func newTestJWTService(t *testing.T) *JWTService {
+	t.Helper()
+	priv, pub := config.DevJWTKeys()
+	svc, err := NewJWTService(priv, pub)
+	if err != nil {
+		t.Fatalf("NewJWTService: %v", err)
+	}
+	return svc
+}
+
+func TestIntegrationToken_RoundTrip(t *testing.T) {
+	svc := newTestJWTService(t)
+	token, exp, err := svc.GenerateIntegrationToken(7, []int64{1, 3}, []string{models.ScopeAnimaisRead, models.ScopeToquesWrite}, 10*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Until(exp) > 10*time.Minute || time.Until(exp) < 9*time.Minute {
+		t.Fatalf("unexpected expiry %v", exp)
+	}
+	claims, err := svc.ValidateIntegrationToken(token)
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if claims.ClienteID != 7 || len(claims.FazendaIDs) != 2 || claims.FazendaIDs[1] != 3 {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+	if claims.IssuedAtMicro == 0 || claims.EmitidoEm().Unix() != claims.IssuedAt.Unix() {
+		t.Fatalf("iat_us inesperado: %d (iat %v)", claims.IssuedAtMicro, claims.IssuedAt)
+	}
+	scopes := claims.Scopes()
+	if len(scopes) != 2 || scopes[0] != models.ScopeAnimaisRead || scopes[1] != models.ScopeToquesWrite {
+		t.Fatalf("unexpected scopes: %v", scopes)
+	}
+}
+
+func TestIntegrationToken_Expired(t *testing.T) {
+	svc := newTestJWTService(t)
+	token, _, err := svc.GenerateIntegrationToken(7, []int64{1}, []string{models.ScopeAnimaisRead}, -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.ValidateIntegrationToken(token); err == nil {
+		t.Fatal("expected expired token to fail")
+	}
+}
+
+// Access token M2M não autentica rotas JWT de utilizador, e vice-versa.
+func TestIntegrationToken_AudienceSeparation(t *testing.T) {
+	svc := newTestJWTService(t)
+	m2m, _, err := svc.GenerateIntegrationToken(7, []int64{1}, []string{models.ScopeAnimaisRead}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.ValidateToken(m2m); err == nil {
+		t.Fatal("integration token must not validate as user token")
+	}
+	user, err := svc.GenerateToken(42, "a@b.com", models.PerfilAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.ValidateIntegrationToken(user); err == nil {
+		t.Fatal("user token must not validate as integration token")
+	}
+	if _, err := svc.ValidateToken(user); err != nil {
+		t.Fatalf("user token should still validate: %v", err)
+	}
+}
+
+func TestIntegrationToken_RevogadoNoMesmoSegundo(t *testing.T) {
+	svc := newTestJWTService(t)
+	token, _, err := svc.GenerateIntegrationToken(7, []int64{1}, []string{models.ScopeAnimaisRead}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := svc.ValidateIntegrationToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Revogação no fim do mesmo segundo em que o token foi emitido: o iat (segundos) empata, iat_us não.
+	revogado := time.Unix(claims.IssuedAt.Unix(), 999_999_000)
+	if !claims.EmitidoEm().Before(revogado) {
+		t.Fatalf("emissão %v deveria ser anterior à revogação %v", claims.EmitidoEm(), revogado)
+	}
+	if !service.TokenEmitidoAntesDaRevogacao(claims.EmitidoEm(), &revogado) {
+		t.Fatal("token emitido no mesmo segundo, antes da revogação, deve ser rejeitado")
+	}
+
+	// Token novo (emitido depois da revogação) continua válido.
+	revogado = claims.EmitidoEm()
+	time.Sleep(time.Millisecond)
+	novo, _, err := svc.GenerateIntegrationToken(7, []int64{1}, []string{models.ScopeAnimaisRead}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	novoClaims, err := svc.ValidateIntegrationToken(novo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service.TokenEmitidoAntesDaRevogacao(novoClaims.EmitidoEm(), &revogado) {
+		t.Fatal("token emitido depois da revogação deve ser aceito")
+	}
+}
@@ -11,6 +11,8 @@ import (
 type JWTService struct {
 	privateKey *rsa.PrivateKey
 	publicKey  *rsa.PublicKey
+	// perfisExigemDoisFatores perfis cujos tokens só valem com AAL 2 (BR-ACESSO-027).
+	perfisExigemDoisFatores map[string]bool
 }
 
 func NewJWTService(privateKeyPEM, publicKeyPEM string) (*JWTService, error) {
@@ -30,18 +32,39 @@ func NewJWTService(privateKeyPEM, publicKeyPEM string) (*JWTService, error) {
 	}, nil
 }
 
+// Níveis de garantia da sessão (claim aal): senha, ou senha + segundo fator TOTP.
+const (
+	AALSenha       = 1
+	AALDoisFatores = 2
+)
+
 type Claims struct {
 	UserID int64  `json:"user_id"`
 	Email  string `json:"email"`
 	Perfil string `json:"perfil"`
+	AAL    int    `json:"aal,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// NivelGarantia AAL do token; tokens sem o claim (emitidos antes do 2FA) valem como senha.
+func (c *Claims) NivelGarantia() int {
+	if c.AAL < AALSenha {
+		return AALSenha
+	}
+	return c.AAL
+}
+
 func (j *JWTService) GenerateToken(userID int64, email, perfil string) (string, error) {
+	return j.GenerateTokenAAL(userID, email, perfil, AALSenha)
+}
+
+// GenerateTokenAAL access token com o nível de garantia da sessão que o emite.
+func (j *JWTService) GenerateTokenAAL(userID int64, email, perfil string, aal int) (string, error) {
 	claims := Claims{
 		UserID: userID,
 		Email:  email,
 		Perfil: perfil,
+		AAL:    aal,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(15 * time.Minute)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -65,7 +88,8 @@ func (j *JWTService) ValidateToken(tokenString string) (*Claims, error) {
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+	// Tokens com audience (M2M de integração, desafio de 2FA) não autenticam rotas de utilizador.
+	if claims, ok := token.Claims.(*Claims); ok && token.Valid && len(claims.Audience) == 0 {
 		return claims, nil
 	}
 
@@ -1,13 +1,18 @@
 package auth
 
 import (
+	"net/http"
 	"strings"
 
 	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/requestctx"
 	"github.com/ceialmilk/api/internal/response"
 	"github.com/gin-gonic/gin"
 )
 
+// ContextTokenExpiraEm expiração (time.Time) do access token do pedido; streams longos encerram nela.
+const ContextTokenExpiraEm = "token_expira_em"
+
 func AuthMiddleware(jwtService *JWTService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var token string
@@ -41,10 +46,25 @@ func AuthMiddleware(jwtService *JWTService) gin.HandlerFunc {
 			return
 		}
 
+		// BR-ACESSO-027: perfil obrigado a 2FA com sessão só de senha fica restrito à inscrição.
+		if jwtService.ExigeDoisFatores(claims.Perfil) && claims.NivelGarantia() < AALDoisFatores &&
+			!rotaLiberadaSemDoisFatores(c.Request.Method, c.Request.URL.Path) {
+			response.Error(c, http.StatusForbidden, response.CodeTwoFactorRequired,
+				"Ative a autenticação em dois fatores para continuar", nil)
+			c.Abort()
+			return
+		}
+
 		// Adicionar claims ao contexto
 		c.Set("user_id", claims.UserID)
 		c.Set("email", claims.Email)
 		c.Set("perfil", claims.Perfil)
+		c.Set("aal", claims.NivelGarantia())
+		if claims.ExpiresAt != nil {
+			c.Set(ContextTokenExpiraEm, claims.ExpiresAt.Time)
+		}
+		// Ator no context.Context do request (auditoria nos services)
+		c.Request = c.Request.WithContext(requestctx.WithAtor(c.Request.Context(), requestctx.Ator{UsuarioID: claims.UserID, Perfil: claims.Perfil}))
 
 		c.Next()
 	}
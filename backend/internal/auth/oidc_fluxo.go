@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ceialmilk/api/internal/oidc"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// FluxoOIDCAudience token do cookie de login SSO em curso (BR-ACESSO-029): guarda state, nonce e o
+// verificador PKCE até o callback; ValidateToken recusa tokens com audience.
+const FluxoOIDCAudience = "ceialmilk-oidc"
+
+// FluxoOIDCTTL tempo para concluir o login no provedor.
+const FluxoOIDCTTL = 10 * time.Minute
+
+// FluxoOIDCCookie nome do cookie do fluxo (SameSite=Lax: volta do provedor por navegação de topo).
+const FluxoOIDCCookie = "ceialmilk_oidc"
+
+type fluxoOIDCClaims struct {
+	jwt.RegisteredClaims
+	Fluxo oidc.Fluxo `json:"fluxo"`
+}
+
+// GenerateFluxoOIDC assina o fluxo para o cookie do navegador.
+func (j *JWTService) GenerateFluxoOIDC(f oidc.Fluxo) (string, error) {
+	now := time.Now()
+	claims := fluxoOIDCClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{FluxoOIDCAudience},
+			ID:        uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(FluxoOIDCTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+		Fluxo: f,
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(j.privateKey)
+}
+
+// ValidateFluxoOIDC devolve o fluxo do cookie ainda válido.
+func (j *JWTService) ValidateFluxoOIDC(tokenString string) (oidc.Fluxo, error) {
+	if j.publicKey == nil {
+		return oidc.Fluxo{}, errors.New("fluxo inválido")
+	}
+	token, err := jwt.ParseWithClaims(tokenString, &fluxoOIDCClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, errors.New("método de assinatura inválido")
+		}
+		return j.publicKey, nil
+	}, jwt.WithAudience(FluxoOIDCAudience))
+	if err != nil {
+		return oidc.Fluxo{}, err
+	}
+	claims, ok := token.Claims.(*fluxoOIDCClaims)
+	if !ok || !token.Valid || claims.Fluxo.State == "" || claims.Fluxo.Verifier == "" {
+		return oidc.Fluxo{}, errors.New("fluxo inválido")
+	}
+	return claims.Fluxo, nil
+}
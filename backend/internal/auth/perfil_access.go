@@ -39,10 +39,12 @@ var funcionarioCriasPath = regexp.MustCompile(`^/api/v1/crias(/.*)?$`)
 var funcionarioAnimaisPath = regexp.MustCompile(`^/api/v1/animais(/.*)?$`)
 var funcionarioAnimaisBaixaPath = regexp.MustCompile(`^/api/v1/animais/[0-9]+/baixa$`)
 var funcionarioAnimaisSaudePath = regexp.MustCompile(`^/api/v1/animais/[0-9]+/saude(/[0-9]+)?$`)
+var funcionarioAnimaisPesagensPath = regexp.MustCompile(`^/api/v1/animais/[0-9]+/pesagens(/[0-9]+)?$`)
 var funcionarioAnimaisVacinasPath = regexp.MustCompile(`^/api/v1/animais/[0-9]+/vacinas(/[0-9]+(/aplicar)?)?$`)
 var funcionarioAnimaisHormoniosPath = regexp.MustCompile(`^/api/v1/animais/[0-9]+/hormonios-lactacao(/[0-9]+|/protocolo(/encerrar)?)?$`)
 var funcionarioFazendaHormoniosPendentesPath = regexp.MustCompile(`^/api/v1/fazendas/[0-9]+/hormonios-lactacao/pendentes$`)
-var funcionarioAlertasPath = regexp.MustCompile(`^/api/v1/fazendas/[0-9]+/alertas(/[0-9]+(/status)?)?$`)
+var funcionarioAlertasPath = regexp.MustCompile(`^/api/v1/fazendas/[0-9]+/alertas(/[0-9]+(/status|/responsavel|/atividades|/comentarios)?)?$`)
+var funcionarioTarefasPath = regexp.MustCompile(`^/api/v1/fazendas/[0-9]+/tarefas(/minhas-hoje|/[0-9]+(/status|/checklist/[0-9]+)?)?$`)
 var funcionarioResumoPecuarioPath = regexp.MustCompile(`^/api/v1/fazendas/[0-9]+/resumo-pecuario$`)
 var funcionarioAssistentePath = regexp.MustCompile(`^/api/v1/assistente(/.*)?$`)
 
@@ -117,6 +119,10 @@ func requestAllowedForFuncionario(method, path string) bool {
 		}
 		return false
 	}
+	// Pesagens (BR-LOTE-005): GET + POST (registrar); DELETE → 403.
+	if funcionarioAnimaisPesagensPath.MatchString(path) {
+		return method == http.MethodGet || (method == http.MethodPost && strings.HasSuffix(path, "/pesagens"))
+	}
 	// Hormônios lactação (BR-ACESSO-025): GET + POST (registrar); PUT/DELETE/PATCH encerrar → 403.
 	if funcionarioAnimaisHormoniosPath.MatchString(path) {
 		if method == http.MethodGet {
@@ -153,11 +159,23 @@ func requestAllowedForFuncionario(method, path string) bool {
 		if method == http.MethodGet {
 			return true
 		}
-		if method == http.MethodPatch && strings.HasSuffix(path, "/status") {
+		if method == http.MethodPatch && (strings.HasSuffix(path, "/status") || strings.HasSuffix(path, "/responsavel")) {
+			return true
+		}
+		// BR-ALERTA-023: FUNCIONARIO comenta e assume alertas (o serviço restringe a atribuição a si próprio).
+		if method == http.MethodPost && strings.HasSuffix(path, "/comentarios") {
 			return true
 		}
 		return false
 	}
+	// BR-TAREFA-002: FUNCIONARIO consulta, inicia/conclui e marca checklist (o serviço restringe às
+	// próprias tarefas ou às sem responsável); criar, editar e excluir são da gestão.
+	if funcionarioTarefasPath.MatchString(path) {
+		if method == http.MethodGet {
+			return true
+		}
+		return method == http.MethodPatch && (strings.HasSuffix(path, "/status") || strings.Contains(path, "/checklist/"))
+	}
 	if method == http.MethodGet && funcionarioResumoPecuarioPath.MatchString(path) {
 		return true
 	}
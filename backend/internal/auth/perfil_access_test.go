@@ -46,8 +46,16 @@ func TestRequestAllowedForUser(t *testing.T) {
 		{http.MethodGet, "/api/v1/me/fazendas", true},
 		{http.MethodGet, "/api/v1/me/fazenda-ativa", true},
 		{http.MethodPost, "/api/v1/me/fazendas", false},
+		{http.MethodPost, "/api/v1/me/convites/resgatar", true},
+		{http.MethodPut, "/api/v1/me/senha", true},
+		{http.MethodPost, "/api/v1/me/verificacao-email", true},
+		{http.MethodGet, "/api/v1/me/2fa", true},
+		{http.MethodPost, "/api/v1/me/2fa/ativar", true},
+		{http.MethodGet, "/api/v1/me/sessoes", true},
+		{http.MethodDelete, "/api/v1/me/sessoes/12", true},
 		{http.MethodGet, "/api/v1/animais", false},
 		{http.MethodPost, "/api/v1/fazendas/1/alertas", false},
+		{http.MethodPost, "/api/v1/fazendas/1/convites", false},
 	}
 
 	for _, tt := range tests {
@@ -142,6 +150,32 @@ func TestRequestAllowedForFuncionario_Crias(t *testing.T) {
 	}
 }
 
+func TestRequestAllowedForFuncionario_AnimaisPesagens(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		method string
+		path   string
+		want   bool
+	}{
+		{http.MethodGet, "/api/v1/animais/1/pesagens", true},
+		{http.MethodPost, "/api/v1/animais/1/pesagens", true},
+		{http.MethodDelete, "/api/v1/animais/1/pesagens/42", false},
+		{http.MethodPost, "/api/v1/animais/1/pesagens/42", false},
+		{http.MethodGet, "/api/v1/fazendas/1/movimentacoes-lote/propostas", false},
+		{http.MethodPost, "/api/v1/fazendas/1/movimentacoes-lote/propostas/aprovar", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			t.Parallel()
+			if got := requestAllowedForFuncionario(tt.method, tt.path); got != tt.want {
+				t.Errorf("requestAllowedForFuncionario(%q, %q) = %v, want %v", tt.method, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestRequestAllowedForFuncionario_AnimaisSaude(t *testing.T) {
 	t.Parallel()
 
@@ -307,6 +341,35 @@ func TestRequestAllowedForFuncionario_GestaoLeituraEProducaoGet(t *testing.T) {
 	}
 }
 
+func TestRequestAllowedForFuncionario_Tarefas(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		method string
+		path   string
+		want   bool
+	}{
+		{http.MethodGet, "/api/v1/fazendas/1/tarefas", true},
+		{http.MethodGet, "/api/v1/fazendas/1/tarefas/minhas-hoje", true},
+		{http.MethodGet, "/api/v1/fazendas/1/tarefas/9", true},
+		{http.MethodPatch, "/api/v1/fazendas/1/tarefas/9/status", true},
+		{http.MethodPatch, "/api/v1/fazendas/1/tarefas/9/checklist/3", true},
+		{http.MethodPost, "/api/v1/fazendas/1/tarefas", false},
+		{http.MethodPut, "/api/v1/fazendas/1/tarefas/9", false},
+		{http.MethodDelete, "/api/v1/fazendas/1/tarefas/9", false},
+		{http.MethodPatch, "/api/v1/fazendas/1/tarefas/minhas-hoje", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			t.Parallel()
+			if got := requestAllowedForFuncionario(tt.method, tt.path); got != tt.want {
+				t.Errorf("requestAllowedForFuncionario(%q, %q) = %v, want %v", tt.method, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestRequestAllowedForFuncionario_Alertas(t *testing.T) {
 	t.Parallel()
 
@@ -321,6 +384,11 @@ func TestRequestAllowedForFuncionario_Alertas(t *testing.T) {
 		{http.MethodPost, "/api/v1/fazendas/1/alertas", false},
 		{http.MethodDelete, "/api/v1/fazendas/1/alertas/42", false},
 		{http.MethodPatch, "/api/v1/fazendas/1/alertas/42", false},
+		{http.MethodPatch, "/api/v1/fazendas/1/alertas/42/responsavel", true},
+		{http.MethodGet, "/api/v1/fazendas/1/alertas/42/atividades", true},
+		{http.MethodPost, "/api/v1/fazendas/1/alertas/42/comentarios", true},
+		{http.MethodPost, "/api/v1/fazendas/1/alertas/42/atividades", false},
+		{http.MethodPost, "/api/v1/fazendas/1/alertas/42/tarefa", false},
 	}
 
 	for _, tt := range tests {
@@ -24,16 +24,45 @@ type Config struct {
 	GitHubRepo                  string
 	GitHubContextBranch         string // branch de produção para contexto Dev Studio (default: main)
 	IntegrationRateLimitPerHour int    // rate limit M2M por cliente (default: 300)
+	IntegrationTokenTTLMinutes  int    // validade do access token OAuth M2M em minutos (default: 15)
+	IntegrationKeyValidadeDias  int    // validade de novas API keys M2M em dias (default: 0 = sem expiração)
+	IntegrationKeyGraceHours    int    // horas em que a chave anterior continua válida após rotação (default: 24)
+	IntegrationKeyAvisoDias     int    // antecedência do aviso de expiração aos admins (default: 14)
 	AuthLoginRateLimit          int    // tentativas de login por IP por janela (default: 10)
 	AuthLoginRateWindowMinutes  int    // janela do login em minutos (default: 15)
 	AuthRegisterRateLimit       int    // registos por IP por hora (default: 5)
 	AuthRefreshRateLimit        int    // refresh por IP por hora (default: 30)
+	AuthPasswordResetRateLimit  int    // pedidos de redefinição de senha e reenvios de verificação por IP por hora (default: 5)
+	Auth2FAPerfisObrigatorios   string // CSV de perfis obrigados a 2FA (ex.: ADMIN,DEVELOPER); vazio = 2FA opcional para todos
+	TOTPEncryptionKey           string // chave que cifra os segredos TOTP; vazio = derivada de JWT_PRIVATE_KEY
+	OIDCIssuer                  string // emissor OpenID Connect do login único; vazio = SSO desligado
+	OIDCClientID                string
+	OIDCClientSecret            string // vazio = cliente público (só PKCE)
+	OIDCRedirectURL             string // callback registado no provedor (ex.: https://api.ceialmilk.com/api/auth/oidc/callback)
+	OIDCScopes                  string // CSV de scopes (default: openid,email,profile)
+	OIDCNomeProvedor            string // rótulo do botão "Entrar com …" (default: SSO)
+	OIDCClaimEmail              string // claim do e-mail (default: email)
+	OIDCClaimNome               string // claim do nome (default: name)
+	OIDCClaimGrupos             string // claim dos grupos (default: groups)
+	OIDCGruposPerfis            string // CSV grupo=PERFIL aplicado a contas USER (ex.: coop-gerentes=GERENTE); vazio = sem mapeamento
 	AlertasCronEnabled          bool   // geração diária de alertas (default: true)
 	AlertasCronHour             int    // hora local do disparo (default: 6)
 	AlertasTZ                   string // timezone do cron (default: America/Sao_Paulo)
+	LixeiraRetencaoDias         int    // dias em que registos excluídos podem ser restaurados (default: 30)
 	VAPIDPublicKey              string // chave pública Web Push (VAPID)
 	VAPIDPrivateKey             string // chave privada Web Push (VAPID)
 	VAPIDSubject                string // contact URI (ex.: mailto:suporte@ceialmilk.com)
+	AppBaseURL                  string // URL pública do frontend para links em e-mail/SMS (ex.: https://app.ceialmilk.com)
+	SMTPHost                    string // servidor SMTP dos alertas por e-mail; vazio = canal EMAIL desligado
+	SMTPPort                    int    // porta SMTP (default: 587)
+	SMTPUsername                string // utilizador SMTP; vazio = sem autenticação (ex.: Mailpit local)
+	SMTPPassword                string
+	SMTPFrom                    string // remetente (ex.: alertas@ceialmilk.com)
+	SMSAPIURL                   string // endpoint HTTP do fornecedor de SMS; vazio = canal SMS desligado
+	SMSAPIToken                 string
+	WhatsAppAPIURL              string // endpoint HTTP do fornecedor de WhatsApp; vazio = canal WHATSAPP desligado
+	WhatsAppAPIToken            string
+	NotificacoesDigestHora      int    // hora local do envio do resumo diário (default: 7)
 	MetricsToken                string // token Bearer para proteger /metrics (obrigatório em produção)
 	TrustedProxies              string // CSV de CIDRs confiáveis para X-Forwarded-For (default: ranges privados)
 }
@@ -89,16 +118,45 @@ func Load() *Config {
 		GitHubRepo:                  getEnv("GITHUB_REPO", ""),
 		GitHubContextBranch:         getEnv("GITHUB_CONTEXT_BRANCH", "main"),
 		IntegrationRateLimitPerHour: getEnvInt("INTEGRATION_RATE_LIMIT_PER_HOUR", 300),
+		IntegrationTokenTTLMinutes:  getEnvInt("INTEGRATION_TOKEN_TTL_MINUTES", 15),
+		IntegrationKeyValidadeDias:  getEnvInt("INTEGRATION_KEY_VALIDADE_DIAS", 0),
+		IntegrationKeyGraceHours:    getEnvInt("INTEGRATION_KEY_GRACE_HOURS", 24),
+		IntegrationKeyAvisoDias:     getEnvInt("INTEGRATION_KEY_AVISO_DIAS", 14),
 		AuthLoginRateLimit:          getEnvInt("AUTH_LOGIN_RATE_LIMIT", 10),
 		AuthLoginRateWindowMinutes:  getEnvInt("AUTH_LOGIN_RATE_WINDOW_MINUTES", 15),
 		AuthRegisterRateLimit:       getEnvInt("AUTH_REGISTER_RATE_LIMIT", 5),
 		AuthRefreshRateLimit:        getEnvInt("AUTH_REFRESH_RATE_LIMIT", 30),
+		AuthPasswordResetRateLimit:  getEnvInt("AUTH_PASSWORD_RESET_RATE_LIMIT", 5),
+		Auth2FAPerfisObrigatorios:   getEnv("AUTH_2FA_PERFIS_OBRIGATORIOS", ""),
+		TOTPEncryptionKey:           getEnv("TOTP_ENCRYPTION_KEY", ""),
+		OIDCIssuer:                  getEnv("OIDC_ISSUER", ""),
+		OIDCClientID:                getEnv("OIDC_CLIENT_ID", ""),
+		OIDCClientSecret:            getEnv("OIDC_CLIENT_SECRET", ""),
+		OIDCRedirectURL:             getEnv("OIDC_REDIRECT_URL", ""),
+		OIDCScopes:                  getEnv("OIDC_SCOPES", "openid,email,profile"),
+		OIDCNomeProvedor:            getEnv("OIDC_NOME_PROVEDOR", "SSO"),
+		OIDCClaimEmail:              getEnv("OIDC_CLAIM_EMAIL", "email"),
+		OIDCClaimNome:               getEnv("OIDC_CLAIM_NOME", "name"),
+		OIDCClaimGrupos:             getEnv("OIDC_CLAIM_GRUPOS", "groups"),
+		OIDCGruposPerfis:            getEnv("OIDC_GRUPOS_PERFIS", ""),
 		AlertasCronEnabled:          getEnvBool("ALERTAS_CRON_ENABLED", true),
 		AlertasCronHour:             getEnvInt("ALERTAS_CRON_HOUR", 6),
 		AlertasTZ:                   getEnv("ALERTAS_TZ", "America/Sao_Paulo"),
+		LixeiraRetencaoDias:         getEnvInt("LIXEIRA_RETENCAO_DIAS", 30),
 		VAPIDPublicKey:              getEnv("VAPID_PUBLIC_KEY", ""),
 		VAPIDPrivateKey:             getEnv("VAPID_PRIVATE_KEY", ""),
 		VAPIDSubject:                getEnv("VAPID_SUBJECT", "mailto:suporte@ceialmilk.com"),
+		AppBaseURL:                  getEnv("APP_BASE_URL", ""),
+		SMTPHost:                    getEnv("SMTP_HOST", ""),
+		SMTPPort:                    getEnvInt("SMTP_PORT", 587),
+		SMTPUsername:                getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:                getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:                    getEnv("SMTP_FROM", ""),
+		SMSAPIURL:                   getEnv("SMS_API_URL", ""),
+		SMSAPIToken:                 getEnv("SMS_API_TOKEN", ""),
+		WhatsAppAPIURL:              getEnv("WHATSAPP_API_URL", ""),
+		WhatsAppAPIToken:            getEnv("WHATSAPP_API_TOKEN", ""),
+		NotificacoesDigestHora:      getEnvInt("NOTIFICACOES_DIGEST_HORA", 7),
 		MetricsToken:                getEnv("METRICS_TOKEN", ""),
 		TrustedProxies:              getEnv("TRUSTED_PROXIES", ""),
 	}
@@ -68,21 +68,31 @@ func ValidateFazendaAccess(c *gin.Context, fazendaSvc fazendaAccessQuerier, faze
 		return false
 	}
 
-	// Buscar fazendas do usuário
-	fazendas, err := fazendaSvc.GetByUsuarioID(c.Request.Context(), userID)
+	vinculado, err := usuarioVinculadoFazenda(c.Request.Context(), fazendaSvc, userID, fazendaID)
 	if err != nil {
 		response.ErrorInternal(c, "Erro ao validar acesso à fazenda", err.Error())
 		return false
 	}
+	if vinculado {
+		return true
+	}
+
+	response.ErrorForbidden(c, "Você não tem acesso a esta fazenda")
+	return false
+}
 
+// usuarioVinculadoFazenda consulta o vínculo sem escrever resposta (ex.: revalidação em streams abertos).
+func usuarioVinculadoFazenda(ctx context.Context, fazendaSvc fazendaAccessQuerier, userID, fazendaID int64) (bool, error) {
+	fazendas, err := fazendaSvc.GetByUsuarioID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
 	for _, f := range fazendas {
 		if f.ID == fazendaID {
-			return true
+			return true, nil
 		}
 	}
-
-	response.ErrorForbidden(c, "Você não tem acesso a esta fazenda")
-	return false
+	return false, nil
 }
 
 // ValidateFazendaAccessOrGestao permite ADMIN/DEVELOPER/GESTAO a qualquer fazenda existente;
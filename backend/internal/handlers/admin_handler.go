@@ -13,6 +13,10 @@ import (
 type AdminHandler struct {
 	usuarioSvc *service.UsuarioService
 	fazendaSvc *service.FazendaService
+	// doisFatoresSvc opcional: redefinição do 2FA de um usuário (BR-ACESSO-027).
+	doisFatoresSvc *service.DoisFatoresService
+	// sessaoSvc opcional: sessões ativas de um usuário (BR-ACESSO-028).
+	sessaoSvc *service.SessaoService
 }
 
 func NewAdminHandler(usuarioSvc *service.UsuarioService, fazendaSvc *service.FazendaService) *AdminHandler {
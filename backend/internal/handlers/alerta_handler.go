@@ -64,8 +64,12 @@ func (h *AlertaHandler) mapAlertaError(c *gin.Context, err error, internalMsg st
 		errors.Is(err, service.ErrAlertaSeveridadeInvalida),
 		errors.Is(err, service.ErrAlertaStatusInvalido),
 		errors.Is(err, service.ErrAlertaTituloObrigatorio),
-		errors.Is(err, service.ErrAlertaPeriodoInvalido):
+		errors.Is(err, service.ErrAlertaPeriodoInvalido),
+		errors.Is(err, service.ErrAlertaResponsavelInvalido),
+		errors.Is(err, service.ErrAlertaComentarioInvalido):
 		response.ErrorValidation(c, err.Error(), nil)
+	case errors.Is(err, service.ErrAlertaEncerrado):
+		response.ErrorConflict(c, err.Error(), nil)
 	case errors.Is(err, service.ErrAlertaAnimalFazenda):
 		response.ErrorForbidden(c, "Animal não pertence a esta fazenda.")
 	case errors.Is(err, service.ErrAnimalNotFound):
@@ -121,15 +125,38 @@ func (h *AlertaHandler) List(c *gin.Context) {
 		return
 	}
 
-	list, total, err := h.svc.ListByFazenda(c.Request.Context(), fazendaID, service.AlertaListQuery{
+	q := service.AlertaListQuery{
 		Status:      c.Query("status"),
 		Tipo:        c.Query("tipo"),
 		Severidade:  c.Query("severidade"),
 		PeriodStart: periodStart,
 		PeriodEnd:   periodEnd,
+		Atrasados:   c.Query("atrasados") == "true",
 		Limit:       limit,
 		Offset:      offset,
-	})
+	}
+	// responsavel=me (meus alertas) | nenhum (sem responsável) | <usuario_id>
+	switch responsavel := strings.TrimSpace(c.Query("responsavel")); responsavel {
+	case "":
+	case "me":
+		actorID, ok := GetActorUserID(c)
+		if !ok {
+			response.ErrorUnauthorized(c, "Usuário não autenticado")
+			return
+		}
+		q.ResponsavelID = &actorID
+	case "nenhum":
+		q.SemResponsavel = true
+	default:
+		id, err := strconv.ParseInt(responsavel, 10, 64)
+		if err != nil || id <= 0 {
+			response.ErrorValidation(c, "responsavel deve ser me, nenhum ou um id de utilizador", nil)
+			return
+		}
+		q.ResponsavelID = &id
+	}
+
+	list, total, err := h.svc.ListByFazenda(c.Request.Context(), fazendaID, q)
 	if h.mapAlertaError(c, err, "Erro ao listar alertas") {
 		return
 	}
@@ -275,3 +302,106 @@ func (h *AlertaHandler) Delete(c *gin.Context) {
 	}
 	response.SuccessOK(c, nil, "Alerta excluído")
 }
+
+type updateAlertaResponsavelRequest struct {
+	ResponsavelID *int64 `json:"responsavel_id"`
+}
+
+// UpdateResponsavel PATCH /api/v1/fazendas/:id/alertas/:alertaId/responsavel
+func (h *AlertaHandler) UpdateResponsavel(c *gin.Context) {
+	fazendaID, ok := parseAlertaFazendaID(c)
+	if !ok {
+		return
+	}
+	if !ValidateFazendaAccess(c, h.fazendaSvc, fazendaID) {
+		return
+	}
+	alertaID, ok := parseAlertaID(c)
+	if !ok {
+		return
+	}
+
+	var req updateAlertaResponsavelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorBadRequest(c, "Corpo da requisição inválido", nil)
+		return
+	}
+
+	actorID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não autenticado")
+		return
+	}
+
+	row, err := h.svc.Atribuir(c.Request.Context(), fazendaID, alertaID, service.AtribuirAlertaInput{
+		ResponsavelID: req.ResponsavelID,
+		ActorUserID:   actorID,
+		Perfil:        getActorPerfil(c),
+	})
+	if h.mapAlertaError(c, err, "Erro ao atribuir alerta") {
+		return
+	}
+	response.SuccessOK(c, row, "Responsável do alerta atualizado")
+}
+
+// ListAtividades GET /api/v1/fazendas/:id/alertas/:alertaId/atividades
+func (h *AlertaHandler) ListAtividades(c *gin.Context) {
+	fazendaID, ok := parseAlertaFazendaID(c)
+	if !ok {
+		return
+	}
+	if !ValidateFazendaAccess(c, h.fazendaSvc, fazendaID) {
+		return
+	}
+	alertaID, ok := parseAlertaID(c)
+	if !ok {
+		return
+	}
+
+	list, err := h.svc.ListAtividades(c.Request.Context(), fazendaID, alertaID)
+	if h.mapAlertaError(c, err, "Erro ao listar atividades do alerta") {
+		return
+	}
+	response.SuccessOK(c, list, "Atividades do alerta listadas")
+}
+
+type comentarAlertaRequest struct {
+	Texto string `json:"texto" binding:"required"`
+}
+
+// Comentar POST /api/v1/fazendas/:id/alertas/:alertaId/comentarios
+func (h *AlertaHandler) Comentar(c *gin.Context) {
+	fazendaID, ok := parseAlertaFazendaID(c)
+	if !ok {
+		return
+	}
+	if !ValidateFazendaAccess(c, h.fazendaSvc, fazendaID) {
+		return
+	}
+	alertaID, ok := parseAlertaID(c)
+	if !ok {
+		return
+	}
+
+	var req comentarAlertaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorBadRequest(c, "Corpo da requisição inválido", nil)
+		return
+	}
+
+	actorID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não autenticado")
+		return
+	}
+
+	row, err := h.svc.Comentar(c.Request.Context(), fazendaID, alertaID, service.ComentarAlertaInput{
+		Texto:       req.Texto,
+		ActorUserID: actorID,
+		Perfil:      getActorPerfil(c),
+	})
+	if h.mapAlertaError(c, err, "Erro ao comentar alerta") {
+		return
+	}
+	response.SuccessCreated(c, row, "Comentário adicionado")
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/ceialmilk/api/internal/response"
+	"github.com/ceialmilk/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type AlertaRegraCustomHandler struct {
+	svc        *service.AlertaRegraCustomService
+	fazendaSvc *service.FazendaService
+}
+
+func NewAlertaRegraCustomHandler(svc *service.AlertaRegraCustomService, fazendaSvc *service.FazendaService) *AlertaRegraCustomHandler {
+	return &AlertaRegraCustomHandler{svc: svc, fazendaSvc: fazendaSvc}
+}
+
+// alertaRegraCustomRequest: ativo omitido = true; severidade omitida = MEDIA; push_perfis omitido/null =
+// perfis operacionais, [] desliga o push.
+type alertaRegraCustomRequest struct {
+	Nome       string   `json:"nome"`
+	Descricao  *string  `json:"descricao"`
+	Expressao  string   `json:"expressao"`
+	Severidade string   `json:"severidade"`
+	Ativo      *bool    `json:"ativo"`
+	PushPerfis []string `json:"push_perfis"`
+}
+
+func (r alertaRegraCustomRequest) input() service.AlertaRegraCustomInput {
+	ativo := true
+	if r.Ativo != nil {
+		ativo = *r.Ativo
+	}
+	return service.AlertaRegraCustomInput{
+		Nome:       r.Nome,
+		Descricao:  r.Descricao,
+		Expressao:  r.Expressao,
+		Severidade: r.Severidade,
+		Ativo:      ativo,
+		PushPerfis: r.PushPerfis,
+	}
+}
+
+type previewAlertaRegraCustomRequest struct {
+	Expressao string `json:"expressao"`
+	Data      string `json:"data"` // YYYY-MM-DD; vazio = hoje
+}
+
+func parseAlertaRegraCustomID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("regraId"), 10, 64)
+	if err != nil || id <= 0 {
+		response.ErrorBadRequest(c, "regra_id inválido", nil)
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *AlertaRegraCustomHandler) mapAlertaRegraCustomError(c *gin.Context, err error, internalMsg string) bool {
+	if err == nil {
+		return false
+	}
+	switch {
+	case errors.Is(err, service.ErrAlertaRegraForbidden):
+		response.ErrorForbidden(c, err.Error())
+	case errors.Is(err, service.ErrAlertaRegraCustomNotFound):
+		response.ErrorNotFound(c, err.Error())
+	case errors.Is(err, service.ErrAlertaRegraCustomLimite):
+		response.ErrorConflict(c, err.Error(), nil)
+	case errors.Is(err, service.ErrAlertaExpressaoInvalida),
+		errors.Is(err, service.ErrAlertaRegraCustomNome),
+		errors.Is(err, service.ErrAlertaRegraCustomDataFutura),
+		errors.Is(err, service.ErrAlertaRegraPushPerfil),
+		errors.Is(err, service.ErrAlertaSeveridadeInvalida):
+		response.ErrorValidation(c, err.Error(), nil)
+	default:
+		response.ErrorInternal(c, internalMsg, err.Error())
+	}
+	return true
+}
+
+// List GET /api/v1/fazendas/:id/alertas/regras-custom
+func (h *AlertaRegraCustomHandler) List(c *gin.Context) {
+	fazendaID, ok := parseAlertaFazendaID(c)
+	if !ok {
+		return
+	}
+	if !ValidateFazendaAccess(c, h.fazendaSvc, fazendaID) {
+		return
+	}
+	regras, err := h.svc.List(c.Request.Context(), fazendaID)
+	if h.mapAlertaRegraCustomError(c, err, "Erro ao listar regras personalizadas") {
+		return
+	}
+	response.SuccessOK(c, regras, "Regras de alerta personalizadas")
+}
+
+// Variaveis GET /api/v1/fazendas/:id/alertas/regras-custom/variaveis
+func (h *AlertaRegraCustomHandler) Variaveis(c *gin.Context) {
+	fazendaID, ok := parseAlertaFazendaID(c)
+	if !ok {
+		return
+	}
+	if !ValidateFazendaAccess(c, h.fazendaSvc, fazendaID) {
+		return
+	}
+	response.SuccessOK(c, service.AlertaExpressaoVariaveis(), "Variáveis disponíveis nas expressões")
+}
+
+// Create POST /api/v1/fazendas/:id/alertas/regras-custom
+func (h *AlertaRegraCustomHandler) Create(c *gin.Context) {
+	fazendaID, ok := parseAlertaFazendaID(c)
+	if !ok {
+		return
+	}
+	if !ValidateFazendaAccess(c, h.fazendaSvc, fazendaID) {
+		return
+	}
+	var req alertaRegraCustomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorBadRequest(c, "Corpo da requisição inválido", nil)
+		return
+	}
+	actorID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não autenticado")
+		return
+	}
+	regra, err := h.svc.Create(c.Request.Context(), fazendaID, req.input(), actorID, getActorPerfil(c))
+	if h.mapAlertaRegraCustomError(c, err, "Erro ao criar regra personalizada") {
+		return
+	}
+	response.SuccessCreated(c, regra, "Regra personalizada criada")
+}
+
+// Update PUT /api/v1/fazendas/:id/alertas/regras-custom/:regraId
+func (h *AlertaRegraCustomHandler) Update(c *gin.Context) {
+	fazendaID, ok := parseAlertaFazendaID(c)
+	if !ok {
+		return
+	}
+	regraID, ok := parseAlertaRegraCustomID(c)
+	if !ok {
+		return
+	}
+	if !ValidateFazendaAccess(c, h.fazendaSvc, fazendaID) {
+		return
+	}
+	var req alertaRegraCustomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorBadRequest(c, "Corpo da requisição inválido", nil)
+		return
+	}
+	actorID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não autenticado")
+		return
+	}
+	regra, err := h.svc.Update(c.Request.Context(), fazendaID, regraID, req.input(), actorID, getActorPerfil(c))
+	if h.mapAlertaRegraCustomError(c, err, "Erro ao atualizar regra personalizada") {
+		return
+	}
+	response.SuccessOK(c, regra, "Regra personalizada atualizada")
+}
+
+// Delete DELETE /api/v1/fazendas/:id/alertas/regras-custom/:regraId
+func (h *AlertaRegraCustomHandler) Delete(c *gin.Context) {
+	fazendaID, ok := parseAlertaFazendaID(c)
+	if !ok {
+		return
+	}
+	regraID, ok := parseAlertaRegraCustomID(c)
+	if !ok {
+		return
+	}
+	if !ValidateFazendaAccess(c, h.fazendaSvc, fazendaID) {
+		return
+	}
+	err := h.svc.Delete(c.Request.Context(), fazendaID, regraID, getActorPerfil(c))
+	if h.mapAlertaRegraCustomError(c, err, "Erro ao excluir regra personalizada") {
+		return
+	}
+	response.SuccessOK(c, nil, "Regra personalizada excluída")
+}
+
+// Preview POST /api/v1/fazendas/:id/alertas/regras-custom/preview — animais que satisfazem a expressão (nada é gravado).
+func (h *AlertaRegraCustomHandler) Preview(c *gin.Context) {
+	fazendaID, ok := parseAlertaFazendaID(c)
+	if !ok {
+		return
+	}
+	if !ValidateFazendaAccess(c, h.fazendaSvc, fazendaID) {
+		return
+	}
+	var req previewAlertaRegraCustomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorBadRequest(c, "Corpo da requisição inválido", nil)
+		return
+	}
+	data := time.Now()
+	if req.Data != "" {
+		var err error
+		data, err = time.ParseInLocation("2006-01-02", req.Data, time.Local)
+		if err != nil {
+			response.ErrorBadRequest(c, "Data inválida (use YYYY-MM-DD)", nil)
+			return
+		}
+	}
+	preview, err := h.svc.Preview(c.Request.Context(), fazendaID, req.Expressao, data)
+	if h.mapAlertaRegraCustomError(c, err, "Erro ao avaliar expressão") {
+		return
+	}
+	response.SuccessOK(c, preview, "Pré-visualização da regra")
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/ceialmilk/api/internal/response"
+	"github.com/ceialmilk/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type AlertaRegraHandler struct {
+	svc        *service.AlertaRegraService
+	fazendaSvc *service.FazendaService
+}
+
+func NewAlertaRegraHandler(svc *service.AlertaRegraService, fazendaSvc *service.FazendaService) *AlertaRegraHandler {
+	return &AlertaRegraHandler{svc: svc, fazendaSvc: fazendaSvc}
+}
+
+// putAlertaRegraRequest: campos omitidos ou null usam o padrão da regra; push_perfis [] desliga o push.
+type putAlertaRegraRequest struct {
+	Ativo      *bool    `json:"ativo"`
+	Dias       *int     `json:"dias"`
+	Severidade *string  `json:"severidade"`
+	PushPerfis []string `json:"push_perfis"`
+}
+
+func (h *AlertaRegraHandler) mapAlertaRegraError(c *gin.Context, err error, internalMsg string) bool {
+	if err == nil {
+		return false
+	}
+	switch {
+	case errors.Is(err, service.ErrAlertaRegraForbidden):
+		response.ErrorForbidden(c, err.Error())
+	case errors.Is(err, service.ErrAlertaRegraTipoInvalido):
+		response.ErrorNotFound(c, "Regra de alerta não encontrada")
+	case errors.Is(err, service.ErrAlertaRegraDiasInvalidos),
+		errors.Is(err, service.ErrAlertaRegraSemDias),
+		errors.Is(err, service.ErrAlertaRegraPushPerfil),
+		errors.Is(err, service.ErrAlertaSeveridadeInvalida):
+		response.ErrorValidation(c, err.Error(), nil)
+	default:
+		response.ErrorInternal(c, internalMsg, err.Error())
+	}
+	return true
+}
+
+// List GET /api/v1/fazendas/:id/alertas/regras
+func (h *AlertaRegraHandler) List(c *gin.Context) {
+	fazendaID, ok := parseAlertaFazendaID(c)
+	if !ok {
+		return
+	}
+	if !ValidateFazendaAccess(c, h.fazendaSvc, fazendaID) {
+		return
+	}
+	regras, err := h.svc.List(c.Request.Context(), fazendaID)
+	if h.mapAlertaRegraError(c, err, "Erro ao listar regras de alerta") {
+		return
+	}
+	response.SuccessOK(c, regras, "Regras de alerta")
+}
+
+// Put PUT /api/v1/fazendas/:id/alertas/regras/:tipo
+func (h *AlertaRegraHandler) Put(c *gin.Context) {
+	fazendaID, ok := parseAlertaFazendaID(c)
+	if !ok {
+		return
+	}
+	if !ValidateFazendaAccess(c, h.fazendaSvc, fazendaID) {
+		return
+	}
+	var req putAlertaRegraRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorBadRequest(c, "Corpo da requisição inválido", nil)
+		return
+	}
+	actorID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não autenticado")
+		return
+	}
+	ativo := true
+	if req.Ativo != nil {
+		ativo = *req.Ativo
+	}
+	regra, err := h.svc.Put(c.Request.Context(), fazendaID, c.Param("tipo"), service.AlertaRegraInput{
+		Ativo:      ativo,
+		Dias:       req.Dias,
+		Severidade: req.Severidade,
+		PushPerfis: req.PushPerfis,
+	}, actorID, getActorPerfil(c))
+	if h.mapAlertaRegraError(c, err, "Erro ao salvar regra de alerta") {
+		return
+	}
+	response.SuccessOK(c, regra, "Regra de alerta atualizada")
+}
+
+// Reset DELETE /api/v1/fazendas/:id/alertas/regras/:tipo (volta ao padrão)
+func (h *AlertaRegraHandler) Reset(c *gin.Context) {
+	fazendaID, ok := parseAlertaFazendaID(c)
+	if !ok {
+		return
+	}
+	if !ValidateFazendaAccess(c, h.fazendaSvc, fazendaID) {
+		return
+	}
+	regra, err := h.svc.Reset(c.Request.Context(), fazendaID, c.Param("tipo"), getActorPerfil(c))
+	if h.mapAlertaRegraError(c, err, "Erro ao restaurar regra de alerta") {
+		return
+	}
+	response.SuccessOK(c, regra, "Regra de alerta restaurada ao padrão")
+}
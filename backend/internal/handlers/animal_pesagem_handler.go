@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/response"
+	"github.com/ceialmilk/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type AnimalPesagemHandler struct {
+	svc        *service.AnimalPesagemService
+	animalSvc  *service.AnimalService
+	fazendaSvc *service.FazendaService
+}
+
+func NewAnimalPesagemHandler(svc *service.AnimalPesagemService, animalSvc *service.AnimalService, fazendaSvc *service.FazendaService) *AnimalPesagemHandler {
+	return &AnimalPesagemHandler{svc: svc, animalSvc: animalSvc, fazendaSvc: fazendaSvc}
+}
+
+// List GET /api/v1/animais/:id/pesagens
+func (h *AnimalPesagemHandler) List(c *gin.Context) {
+	animalID, ok := h.resolveAnimalIDAndAccess(c)
+	if !ok {
+		return
+	}
+	list, err := h.svc.ListByAnimalID(c.Request.Context(), animalID)
+	if err != nil {
+		response.ErrorInternal(c, "Erro ao listar pesagens do animal", err.Error())
+		return
+	}
+	if list == nil {
+		list = []*models.AnimalPesagem{}
+	}
+	response.SuccessOK(c, list, "Pesagens listadas com sucesso")
+}
+
+// Create POST /api/v1/animais/:id/pesagens
+func (h *AnimalPesagemHandler) Create(c *gin.Context) {
+	animalID, ok := h.resolveAnimalIDAndAccess(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Data       string  `json:"data" binding:"required"`
+		PesoKg     float64 `json:"peso_kg" binding:"required"`
+		Observacao *string `json:"observacao"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorBadRequest(c, "Corpo da requisição inválido", nil)
+		return
+	}
+	data, err := time.Parse("2006-01-02", req.Data)
+	if err != nil {
+		response.ErrorBadRequest(c, "data deve estar no formato YYYY-MM-DD", nil)
+		return
+	}
+	in := service.CreateAnimalPesagemInput{Data: data, PesoKg: req.PesoKg, Observacao: req.Observacao}
+	if actorID, exists := GetActorUserID(c); exists {
+		in.CreatedBy = &actorID
+	}
+	row, err := h.svc.Create(c.Request.Context(), animalID, in)
+	if err != nil {
+		if h.respondCommonErrors(c, err) {
+			return
+		}
+		response.ErrorInternal(c, "Erro ao registrar pesagem", err.Error())
+		return
+	}
+	response.SuccessCreated(c, row, "Pesagem registrada com sucesso")
+}
+
+// Delete DELETE /api/v1/animais/:id/pesagens/:pesagemId
+func (h *AnimalPesagemHandler) Delete(c *gin.Context) {
+	animalID, ok := h.resolveAnimalIDAndAccess(c)
+	if !ok {
+		return
+	}
+	pesagemID, err := strconv.ParseInt(c.Param("pesagemId"), 10, 64)
+	if err != nil || pesagemID <= 0 {
+		response.ErrorBadRequest(c, "pesagem_id inválido", nil)
+		return
+	}
+	if err := h.svc.Delete(c.Request.Context(), animalID, pesagemID); err != nil {
+		if h.respondCommonErrors(c, err) {
+			return
+		}
+		response.ErrorInternal(c, "Erro ao excluir pesagem", err.Error())
+		return
+	}
+	response.SuccessOK(c, nil, "Pesagem excluída com sucesso")
+}
+
+func (h *AnimalPesagemHandler) resolveAnimalIDAndAccess(c *gin.Context) (int64, bool) {
+	animalID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || animalID <= 0 {
+		response.ErrorBadRequest(c, "animal_id inválido", nil)
+		return 0, false
+	}
+	animal, err := h.animalSvc.GetByID(c.Request.Context(), animalID)
+	if err != nil {
+		if errors.Is(err, service.ErrAnimalNotFound) {
+			response.ErrorNotFound(c, "Animal não encontrado")
+			return 0, false
+		}
+		response.ErrorInternal(c, "Erro ao validar animal", err.Error())
+		return 0, false
+	}
+	if !ValidateFazendaAccess(c, h.fazendaSvc, animal.FazendaID) {
+		return 0, false
+	}
+	return animalID, true
+}
+
+func (h *AnimalPesagemHandler) respondCommonErrors(c *gin.Context, err error) bool {
+	if RespondIfDomainWriteError(c, err) {
+		return true
+	}
+	switch {
+	case errors.Is(err, service.ErrAnimalNotFound):
+		response.ErrorNotFound(c, "Animal não encontrado")
+	case errors.Is(err, service.ErrAnimalPesagemNotFound):
+		response.ErrorNotFound(c, "Pesagem não encontrada")
+	case errors.Is(err, service.ErrAnimalPesagemPesoInvalido),
+		errors.Is(err, service.ErrAnimalPesagemDataFutura):
+		response.ErrorValidation(c, err.Error(), nil)
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/response"
+	"github.com/ceialmilk/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type AuditoriaHandler struct {
+	svc        *service.AuditoriaService
+	animalSvc  *service.AnimalService
+	fazendaSvc *service.FazendaService
+}
+
+func NewAuditoriaHandler(svc *service.AuditoriaService, animalSvc *service.AnimalService, fazendaSvc *service.FazendaService) *AuditoriaHandler {
+	return &AuditoriaHandler{svc: svc, animalSvc: animalSvc, fazendaSvc: fazendaSvc}
+}
+
+// ListByAnimal GET /api/v1/animais/:id/auditoria/eventos
+func (h *AuditoriaHandler) ListByAnimal(c *gin.Context) {
+	animalID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || animalID <= 0 {
+		response.ErrorBadRequest(c, "animal_id inválido", nil)
+		return
+	}
+	animal, err := h.animalSvc.GetByID(c.Request.Context(), animalID)
+	if err != nil {
+		if errors.Is(err, service.ErrAnimalNotFound) {
+			response.ErrorNotFound(c, "Animal não encontrado")
+			return
+		}
+		response.ErrorInternal(c, "Erro ao validar animal", err.Error())
+		return
+	}
+	if !ValidateFazendaAccess(c, h.fazendaSvc, animal.FazendaID) {
+		return
+	}
+	f, ok := parseAuditoriaFiltro(c)
+	if !ok {
+		return
+	}
+	f.AnimalID = animalID
+	h.list(c, f)
+}
+
+// ListByFazenda GET /api/v1/fazendas/:id/auditoria/eventos?animal_id= (inclui animais já excluídos)
+func (h *AuditoriaHandler) ListByFazenda(c *gin.Context) {
+	fazendaID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || fazendaID <= 0 {
+		response.ErrorBadRequest(c, "fazenda_id inválido", nil)
+		return
+	}
+	if !ValidateFazendaAccess(c, h.fazendaSvc, fazendaID) {
+		return
+	}
+	f, ok := parseAuditoriaFiltro(c)
+	if !ok {
+		return
+	}
+	f.FazendaID = fazendaID
+	if v := c.Query("animal_id"); v != "" {
+		animalID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || animalID <= 0 {
+			response.ErrorValidation(c, "animal_id inválido", nil)
+			return
+		}
+		f.AnimalID = animalID
+	}
+	h.list(c, f)
+}
+
+// ListByUsuario GET /api/v1/admin/auditoria/usuarios/:id?fazenda_id=
+func (h *AuditoriaHandler) ListByUsuario(c *gin.Context) {
+	usuarioID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || usuarioID <= 0 {
+		response.ErrorBadRequest(c, "usuario_id inválido", nil)
+		return
+	}
+	f, ok := parseAuditoriaFiltro(c)
+	if !ok {
+		return
+	}
+	f.UsuarioID = usuarioID
+	if v := c.Query("fazenda_id"); v != "" {
+		fazendaID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || fazendaID <= 0 {
+			response.ErrorValidation(c, "fazenda_id inválido", nil)
+			return
+		}
+		f.FazendaID = fazendaID
+	}
+	h.list(c, f)
+}
+
+func (h *AuditoriaHandler) list(c *gin.Context, f models.AuditoriaFiltro) {
+	eventos, total, err := h.svc.List(c.Request.Context(), f)
+	if err != nil {
+		if errors.Is(err, service.ErrAuditoriaFiltroObrigatorio) || errors.Is(err, service.ErrAuditoriaAcaoInvalida) {
+			response.ErrorValidation(c, err.Error(), nil)
+			return
+		}
+		response.ErrorInternal(c, "Erro ao consultar auditoria", err.Error())
+		return
+	}
+	response.SuccessOK(c, gin.H{"eventos": eventos, "total": total}, "Auditoria listada")
+}
+
+// parseAuditoriaFiltro lê entidade, acao, de, ate (YYYY-MM-DD ou RFC3339), limit e offset.
+func parseAuditoriaFiltro(c *gin.Context) (models.AuditoriaFiltro, bool) {
+	f := models.AuditoriaFiltro{
+		Entidade: c.Query("entidade"),
+		Acao:     c.Query("acao"),
+		Limit:    parseQueryIntPositiveDef(c.Query("limit"), 50),
+		Offset:   parseQueryIntNonNeg(c.DefaultQuery("offset", "0"), 0),
+	}
+	if f.Limit > 200 {
+		f.Limit = 200
+	}
+	de, err := parseFlexibleDateTime(c.Query("de"), false)
+	if err != nil {
+		response.ErrorValidation(c, "de inválido (use YYYY-MM-DD ou RFC3339)", nil)
+		return f, false
+	}
+	ate, err := parseFlexibleDateTime(c.Query("ate"), true)
+	if err != nil {
+		response.ErrorValidation(c, "ate inválido (use YYYY-MM-DD ou RFC3339)", nil)
+		return f, false
+	}
+	f.De, f.Ate = de, ate
+	return f, true
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/ceialmilk/api/internal/auth"
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/observability"
+	"github.com/ceialmilk/api/internal/response"
+	"github.com/ceialmilk/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// Recuperação de senha, troca de senha e verificação de e-mail (BR-ACESSO-026).
+
+// SetContaService liga os fluxos de conta; sem ele as rotas respondem 503.
+func (h *AuthHandler) SetContaService(svc *service.ContaService) {
+	h.contaSvc = svc
+}
+
+func contaError(c *gin.Context, err error, msg string) {
+	switch {
+	case errors.Is(err, service.ErrContaTokenInvalido),
+		errors.Is(err, service.ErrContaSenhaCurta),
+		errors.Is(err, service.ErrContaSenhaIgual),
+		errors.Is(err, service.ErrContaSenhaAtualIncorreta):
+		response.ErrorValidation(c, err.Error(), nil)
+	case errors.Is(err, service.ErrContaEmailJaVerificado):
+		response.ErrorConflict(c, err.Error(), nil)
+	case errors.Is(err, service.ErrContaLimiteEnvios):
+		response.ErrorTooManyRequests(c, err.Error())
+	case errors.Is(err, service.ErrContaMailIndisponivel):
+		response.ErrorServiceUnavailable(c, err.Error(), nil)
+	case errors.Is(err, service.ErrUsuarioNotFound):
+		response.ErrorUnauthorized(c, "Usuário não encontrado")
+	default:
+		observability.CaptureHandlerError(c, err, map[string]string{"operation": "conta"})
+		response.ErrorInternal(c, msg, err.Error())
+	}
+}
+
+func (h *AuthHandler) contaDisponivel(c *gin.Context) bool {
+	if h.contaSvc == nil {
+		response.ErrorServiceUnavailable(c, service.ErrContaMailIndisponivel.Error(), nil)
+		return false
+	}
+	return true
+}
+
+// enviarVerificacaoRegistro envia o link de confirmação logo após o registro. Falhas não desfazem a conta:
+// o usuário pode pedir outro envio depois (POST /api/v1/me/verificacao-email).
+func (h *AuthHandler) enviarVerificacaoRegistro(c *gin.Context, user *models.Usuario, data gin.H) {
+	if h.contaSvc == nil {
+		return
+	}
+	err := h.contaSvc.EnviarVerificacao(c.Request.Context(), user.ID)
+	if err != nil && !errors.Is(err, service.ErrContaMailIndisponivel) {
+		observability.CaptureHandlerError(c, err, map[string]string{"operation": "enviar_verificacao_registro"})
+	}
+	data["email_verificacao_enviada"] = err == nil
+}
+
+type forgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ForgotPassword POST /api/auth/forgot-password — resposta igual exista ou não a conta.
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	if !h.contaDisponivel(c) {
+		return
+	}
+	var req forgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Dados inválidos", err.Error())
+		return
+	}
+	if err := h.contaSvc.SolicitarResetSenha(c.Request.Context(), req.Email); err != nil {
+		contaError(c, err, "Erro ao solicitar redefinição de senha")
+		return
+	}
+	response.SuccessOK(c, nil, "Se o e-mail estiver cadastrado, enviaremos um link para redefinir a senha")
+}
+
+type resetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=8"`
+}
+
+// ResetPassword POST /api/auth/reset-password — consome o token e encerra todas as sessões da conta.
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	if !h.contaDisponivel(c) {
+		return
+	}
+	var req resetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Dados inválidos", err.Error())
+		return
+	}
+	if err := h.contaSvc.RedefinirSenha(c.Request.Context(), req.Token, req.Password); err != nil {
+		contaError(c, err, "Erro ao redefinir senha")
+		return
+	}
+	auth.ClearCookie(c, "ceialmilk_token", h.cookieSameSite)
+	auth.ClearCookie(c, "ceialmilk_refresh_token", h.cookieSameSite)
+	response.SuccessOK(c, nil, "Senha redefinida; entre com a nova senha")
+}
+
+type verifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// VerifyEmail POST /api/auth/verify-email — público, o link pode ser aberto noutro dispositivo.
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	if !h.contaDisponivel(c) {
+		return
+	}
+	var req verifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Dados inválidos", err.Error())
+		return
+	}
+	if err := h.contaSvc.VerificarEmail(c.Request.Context(), req.Token); err != nil {
+		contaError(c, err, "Erro ao verificar e-mail")
+		return
+	}
+	response.SuccessOK(c, gin.H{"email_verificado": true}, "E-mail confirmado")
+}
+
+// ReenviarVerificacao POST /api/v1/me/verificacao-email
+func (h *AuthHandler) ReenviarVerificacao(c *gin.Context) {
+	if !h.contaDisponivel(c) {
+		return
+	}
+	userID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não identificado")
+		return
+	}
+	if err := h.contaSvc.EnviarVerificacao(c.Request.Context(), userID); err != nil {
+		contaError(c, err, "Erro ao enviar verificação de e-mail")
+		return
+	}
+	response.SuccessOK(c, nil, "Link de confirmação enviado")
+}
+
+type alterarSenhaRequest struct {
+	SenhaAtual string `json:"senha_atual" binding:"required"`
+	NovaSenha  string `json:"nova_senha" binding:"required,min=8"`
+}
+
+// AlterarSenha PUT /api/v1/me/senha — revoga todos os refresh tokens e abre uma sessão nova só para
+// este dispositivo; os demais saem no próximo refresh.
+func (h *AuthHandler) AlterarSenha(c *gin.Context) {
+	if !h.contaDisponivel(c) {
+		return
+	}
+	userID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não identificado")
+		return
+	}
+	var req alterarSenhaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Dados inválidos", err.Error())
+		return
+	}
+	if err := h.contaSvc.AlterarSenha(c.Request.Context(), userID, req.SenhaAtual, req.NovaSenha); err != nil {
+		contaError(c, err, "Erro ao alterar senha")
+		return
+	}
+
+	user, err := h.userRepo.GetByID(c.Request.Context(), userID)
+	if err != nil {
+		response.ErrorInternal(c, "Erro ao buscar usuário", err.Error())
+		return
+	}
+	// A sessão nova mantém o nível de garantia da atual (senha ou senha + TOTP).
+	if !h.emitirSessao(c, user, auth.GetAAL(c)) {
+		return
+	}
+	response.SuccessOK(c, nil, "Senha alterada; as outras sessões foram encerradas")
+}
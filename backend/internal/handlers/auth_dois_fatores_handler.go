@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/ceialmilk/api/internal/auth"
+	"github.com/ceialmilk/api/internal/observability"
+	"github.com/ceialmilk/api/internal/response"
+	"github.com/ceialmilk/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// Autenticação em dois fatores por TOTP (BR-ACESSO-027).
+
+// SetDoisFatoresService liga o 2FA; sem ele o login é só por senha e as rotas /me/2fa respondem 503.
+func (h *AuthHandler) SetDoisFatoresService(svc *service.DoisFatoresService) {
+	h.doisFatoresSvc = svc
+}
+
+func doisFatoresError(c *gin.Context, err error, msg string) {
+	switch {
+	case errors.Is(err, service.ErrDoisFatoresSenhaIncorreta),
+		errors.Is(err, service.ErrDoisFatoresCodigoInvalido):
+		response.ErrorValidation(c, err.Error(), nil)
+	case errors.Is(err, service.ErrDoisFatoresJaAtivo),
+		errors.Is(err, service.ErrDoisFatoresInativo),
+		errors.Is(err, service.ErrDoisFatoresSemInscricao):
+		response.ErrorConflict(c, err.Error(), nil)
+	case errors.Is(err, service.ErrDoisFatoresObrigatorio):
+		response.ErrorForbidden(c, err.Error())
+	case errors.Is(err, service.ErrUsuarioNotFound):
+		response.ErrorNotFound(c, "Usuário não encontrado")
+	default:
+		observability.CaptureHandlerError(c, err, map[string]string{"operation": "dois_fatores"})
+		response.ErrorInternal(c, msg, err.Error())
+	}
+}
+
+func (h *AuthHandler) doisFatoresDisponivel(c *gin.Context) bool {
+	if h.doisFatoresSvc == nil {
+		response.ErrorServiceUnavailable(c, "Autenticação em dois fatores não configurada no servidor", nil)
+		return false
+	}
+	return true
+}
+
+// exigeSegundoFator responde ao login com o desafio quando a conta tem 2FA ativo (sem cookies de sessão).
+// Devolve true se já respondeu.
+func (h *AuthHandler) exigeSegundoFator(c *gin.Context, userID int64) bool {
+	if h.doisFatoresSvc == nil {
+		return false
+	}
+	ativo, err := h.doisFatoresSvc.Ativo(c.Request.Context(), userID)
+	if err != nil {
+		observability.CaptureHandlerError(c, err, map[string]string{"operation": "dois_fatores_ativo"})
+		response.ErrorInternal(c, "Erro ao verificar autenticação em dois fatores", err.Error())
+		return true
+	}
+	if !ativo {
+		return false
+	}
+	desafio, err := h.emitirDesafioDoisFatores(c, userID)
+	if err != nil {
+		response.ErrorInternal(c, "Erro ao gerar desafio", err.Error())
+		return true
+	}
+	response.SuccessOK(c, gin.H{"segundo_fator": true, "desafio": desafio}, "Informe o código do autenticador")
+	return true
+}
+
+// emitirDesafioDoisFatores assina o desafio e regista o seu jti (uso único, tentativas limitadas).
+func (h *AuthHandler) emitirDesafioDoisFatores(c *gin.Context, userID int64) (string, error) {
+	desafio, jti, expiraEm, err := h.jwt.GenerateDesafioDoisFatores(userID)
+	if err != nil {
+		return "", err
+	}
+	if err := h.doisFatoresSvc.RegistrarDesafio(c.Request.Context(), jti, userID, expiraEm); err != nil {
+		return "", err
+	}
+	return desafio, nil
+}
+
+type loginDoisFatoresRequest struct {
+	Desafio string `json:"desafio" binding:"required"`
+	Codigo  string `json:"codigo" binding:"required"`
+	// Convite código do primeiro passo, resgatado só depois do segundo fator (BR-ACESSO-010).
+	Convite string `json:"convite"`
+}
+
+// LoginDoisFatores POST /api/auth/login/2fa — troca desafio + código TOTP (ou de recuperação) pela sessão AAL 2.
+func (h *AuthHandler) LoginDoisFatores(c *gin.Context) {
+	if !h.doisFatoresDisponivel(c) {
+		return
+	}
+	var req loginDoisFatoresRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Dados inválidos", err.Error())
+		return
+	}
+	userID, jti, err := h.jwt.ValidateDesafioDoisFatores(req.Desafio)
+	if err != nil {
+		response.ErrorUnauthorized(c, "Sessão de login expirada; entre novamente")
+		return
+	}
+	user, err := h.userRepo.GetByID(c.Request.Context(), userID)
+	if err != nil {
+		response.ErrorUnauthorized(c, "Usuário não encontrado")
+		return
+	}
+	if !user.Enabled {
+		response.ErrorUnauthorized(c, "Usuário desativado")
+		return
+	}
+	if err := h.doisFatoresSvc.VerificarDesafio(c.Request.Context(), jti, user.ID, req.Codigo); err != nil {
+		if errors.Is(err, service.ErrDoisFatoresDesafioInvalido) {
+			response.ErrorUnauthorized(c, "Sessão de login expirada; entre novamente")
+			return
+		}
+		if errors.Is(err, service.ErrDoisFatoresCodigoInvalido) || errors.Is(err, service.ErrDoisFatoresInativo) {
+			response.ErrorUnauthorized(c, "Código inválido")
+			return
+		}
+		doisFatoresError(c, err, "Erro ao verificar código")
+		return
+	}
+	h.concluirLogin(c, user, req.Convite, auth.AALDoisFatores)
+}
+
+// DoisFatoresStatus GET /api/v1/me/2fa
+func (h *AuthHandler) DoisFatoresStatus(c *gin.Context) {
+	if !h.doisFatoresDisponivel(c) {
+		return
+	}
+	userID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não identificado")
+		return
+	}
+	st, err := h.doisFatoresSvc.Status(c.Request.Context(), userID, getActorPerfil(c))
+	if err != nil {
+		doisFatoresError(c, err, "Erro ao consultar autenticação em dois fatores")
+		return
+	}
+	st.Nivel = auth.GetAAL(c)
+	response.SuccessOK(c, st, "")
+}
+
+type iniciarDoisFatoresRequest struct {
+	Senha string `json:"senha" binding:"required"`
+}
+
+// IniciarDoisFatores POST /api/v1/me/2fa/iniciar — segredo e URI otpauth:// para o autenticador.
+func (h *AuthHandler) IniciarDoisFatores(c *gin.Context) {
+	if !h.doisFatoresDisponivel(c) {
+		return
+	}
+	userID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não identificado")
+		return
+	}
+	var req iniciarDoisFatoresRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Dados inválidos", err.Error())
+		return
+	}
+	insc, err := h.doisFatoresSvc.Iniciar(c.Request.Context(), userID, req.Senha)
+	if err != nil {
+		doisFatoresError(c, err, "Erro ao iniciar autenticação em dois fatores")
+		return
+	}
+	response.SuccessOK(c, insc, "Cadastre a conta no autenticador e confirme com um código")
+}
+
+type codigoDoisFatoresRequest struct {
+	Codigo string `json:"codigo" binding:"required"`
+}
+
+// AtivarDoisFatores POST /api/v1/me/2fa/ativar — devolve os códigos de recuperação (só nesta resposta),
+// encerra as outras sessões e promove a atual a AAL 2.
+func (h *AuthHandler) AtivarDoisFatores(c *gin.Context) {
+	if !h.doisFatoresDisponivel(c) {
+		return
+	}
+	userID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não identificado")
+		return
+	}
+	var req codigoDoisFatoresRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Dados inválidos", err.Error())
+		return
+	}
+	codigos, err := h.doisFatoresSvc.Ativar(c.Request.Context(), userID, req.Codigo)
+	if err != nil {
+		doisFatoresError(c, err, "Erro ao ativar autenticação em dois fatores")
+		return
+	}
+	user, err := h.userRepo.GetByID(c.Request.Context(), userID)
+	if err != nil {
+		response.ErrorInternal(c, "Erro ao buscar usuário", err.Error())
+		return
+	}
+	if !h.emitirSessao(c, user, auth.AALDoisFatores) {
+		return
+	}
+	response.SuccessOK(c, gin.H{"codigos_recuperacao": codigos},
+		"Autenticação em dois fatores ativada; guarde os códigos de recuperação, eles não são exibidos de novo")
+}
+
+// RegenerarCodigosDoisFatores POST /api/v1/me/2fa/codigos-recuperacao — invalida os anteriores.
+func (h *AuthHandler) RegenerarCodigosDoisFatores(c *gin.Context) {
+	if !h.doisFatoresDisponivel(c) {
+		return
+	}
+	userID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não identificado")
+		return
+	}
+	var req codigoDoisFatoresRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Dados inválidos", err.Error())
+		return
+	}
+	codigos, err := h.doisFatoresSvc.RegenerarCodigos(c.Request.Context(), userID, req.Codigo)
+	if err != nil {
+		doisFatoresError(c, err, "Erro ao gerar códigos de recuperação")
+		return
+	}
+	response.SuccessOK(c, gin.H{"codigos_recuperacao": codigos}, "Novos códigos gerados; os anteriores deixaram de valer")
+}
+
+type desativarDoisFatoresRequest struct {
+	Senha  string `json:"senha" binding:"required"`
+	Codigo string `json:"codigo" binding:"required"`
+}
+
+// DesativarDoisFatores POST /api/v1/me/2fa/desativar — 403 se o perfil exige 2FA.
+func (h *AuthHandler) DesativarDoisFatores(c *gin.Context) {
+	if !h.doisFatoresDisponivel(c) {
+		return
+	}
+	userID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não identificado")
+		return
+	}
+	var req desativarDoisFatoresRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Dados inválidos", err.Error())
+		return
+	}
+	if err := h.doisFatoresSvc.Desativar(c.Request.Context(), userID, req.Senha, req.Codigo); err != nil {
+		doisFatoresError(c, err, "Erro ao desativar autenticação em dois fatores")
+		return
+	}
+	response.SuccessOK(c, nil, "Autenticação em dois fatores desativada")
+}
+
+// SetDoisFatoresService liga a redefinição de 2FA pelo administrador.
+func (h *AdminHandler) SetDoisFatoresService(svc *service.DoisFatoresService) {
+	h.doisFatoresSvc = svc
+}
+
+// RedefinirDoisFatores DELETE /api/v1/admin/usuarios/:id/2fa — para quem perdeu o autenticador e os
+// códigos de recuperação; encerra as sessões do usuário. A própria conta desativa por /me/2fa/desativar.
+func (h *AdminHandler) RedefinirDoisFatores(c *gin.Context) {
+	if h.doisFatoresSvc == nil {
+		response.ErrorServiceUnavailable(c, "Autenticação em dois fatores não configurada no servidor", nil)
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.ErrorBadRequest(c, "ID inválido", nil)
+		return
+	}
+	if actorID, ok := GetActorUserID(c); ok && actorID == id {
+		response.ErrorForbidden(c, "Use a desativação na sua própria conta (senha e código)")
+		return
+	}
+	if err := h.doisFatoresSvc.Redefinir(c.Request.Context(), id); err != nil {
+		doisFatoresError(c, err, "Erro ao redefinir autenticação em dois fatores")
+		return
+	}
+	response.SuccessOK(c, nil, "Autenticação em dois fatores redefinida; as sessões do usuário foram encerradas")
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"io"
 	"net/http"
 
@@ -27,6 +28,18 @@ type AuthHandler struct {
 	jwt             *auth.JWTService
 	refreshTokenSvc *service.RefreshTokenService
 	cookieSameSite  http.SameSite
+	// conviteSvc opcional: resgate de convite no registro e no login (BR-ACESSO-010).
+	conviteSvc *service.ConviteService
+	// contaSvc opcional: redefinição de senha e verificação de e-mail (BR-ACESSO-026).
+	contaSvc *service.ContaService
+	// doisFatoresSvc opcional: segundo passo TOTP no login (BR-ACESSO-027).
+	doisFatoresSvc *service.DoisFatoresService
+	// sessaoSvc opcional: listagem e encerramento de sessões por dispositivo (BR-ACESSO-028).
+	sessaoSvc *service.SessaoService
+	// ssoSvc opcional: login único por OpenID Connect (BR-ACESSO-029).
+	ssoSvc         *service.SSOService
+	ssoNome        string
+	ssoFrontendURL string
 }
 
 func NewAuthHandler(
@@ -43,15 +56,41 @@ func NewAuthHandler(
 	}
 }
 
+func (h *AuthHandler) SetConviteService(svc *service.ConviteService) {
+	h.conviteSvc = svc
+}
+
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required"`
 	Password string `json:"password" binding:"required"`
+	// Convite código opcional resgatado após autenticar (BR-ACESSO-010).
+	Convite string `json:"convite"`
 }
 
 type RegisterRequest struct {
 	Nome     string `json:"nome" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=8"`
+	// Convite código opcional: validado antes de criar a conta e resgatado logo depois.
+	Convite string `json:"convite"`
+}
+
+// resgatarConviteAuth resgata o convite do registro ou login. A falha não desfaz a autenticação:
+// volta como convite_erro para a UI orientar (a conta segue pendente, como no registro sem convite).
+func (h *AuthHandler) resgatarConviteAuth(c *gin.Context, codigo string, userID int64, data gin.H) *models.ConviteResgate {
+	if codigo == "" || h.conviteSvc == nil {
+		return nil
+	}
+	res, err := h.conviteSvc.Resgatar(c.Request.Context(), codigo, userID)
+	if err != nil {
+		if !errors.Is(err, service.ErrConviteInvalido) && !errors.Is(err, service.ErrConviteJaVinculado) {
+			observability.CaptureHandlerError(c, err, map[string]string{"operation": "resgatar_convite"})
+		}
+		data["convite_erro"] = err.Error()
+		return nil
+	}
+	data["convite"] = res
+	return res
 }
 
 func (h *AuthHandler) Register(c *gin.Context) {
@@ -61,6 +100,17 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
+	if req.Convite != "" && h.conviteSvc != nil {
+		if _, err := h.conviteSvc.Preview(c.Request.Context(), req.Convite); err != nil {
+			if errors.Is(err, service.ErrConviteInvalido) {
+				response.ErrorValidation(c, "Convite inválido", err.Error())
+				return
+			}
+			response.ErrorInternal(c, "Erro ao validar convite", err.Error())
+			return
+		}
+	}
+
 	// Verificar se email já existe
 	exists, err := h.userRepo.ExistsByEmail(c.Request.Context(), req.Email, 0)
 	if err != nil {
@@ -81,7 +131,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	// Registo público: perfil USER, sem vínculo a fazendas (provisão pelo admin).
+	// Registo público: perfil USER, sem vínculo a fazendas (provisão pelo admin ou por convite).
 	user := &models.Usuario{
 		Nome:    req.Nome,
 		Email:   req.Email,
@@ -101,6 +151,8 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		"nome":  user.Nome,
 		"email": user.Email,
 	}
+	h.resgatarConviteAuth(c, req.Convite, user.ID, registerData)
+	h.enviarVerificacaoRegistro(c, user, registerData)
 	response.SuccessCreated(c, registerData, "Usuário registrado com sucesso")
 }
 
@@ -132,35 +184,52 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// Gerar access token
-	accessToken, err := h.jwt.GenerateToken(user.ID, user.Email, user.Perfil)
+	// BR-ACESSO-027: com 2FA ativo a senha só rende um desafio; a sessão sai em POST /api/auth/login/2fa.
+	if h.exigeSegundoFator(c, user.ID) {
+		return
+	}
+
+	h.concluirLogin(c, user, req.Convite, auth.AALSenha)
+}
+
+// concluirLogin resgata o convite (se houver), abre a sessão com o nível de garantia aal e responde.
+func (h *AuthHandler) concluirLogin(c *gin.Context, user *models.Usuario, convite string, aal int) {
+	loginData := gin.H{}
+	if res := h.resgatarConviteAuth(c, convite, user.ID, loginData); res != nil {
+		// O token já sai com o perfil atribuído pelo convite.
+		user.Perfil = res.Perfil
+	}
+
+	if !h.emitirSessao(c, user, aal) {
+		return
+	}
+
+	// Tokens viajam apenas nos cookies HttpOnly — nunca no corpo JSON (reduz superfície de XSS).
+	loginData["email"] = user.Email
+	loginData["perfil"] = user.Perfil
+	loginData["nome"] = user.Nome
+	loginData["dois_fatores_pendente"] = h.jwt.ExigeDoisFatores(user.Perfil) && aal < auth.AALDoisFatores
+	response.SuccessOK(c, loginData, "Login realizado com sucesso")
+}
+
+// emitirSessao grava os cookies de access token (15 minutos) e refresh token (7 dias) com o nível de
+// garantia aal. Em caso de erro já respondeu e devolve false.
+func (h *AuthHandler) emitirSessao(c *gin.Context, user *models.Usuario, aal int) bool {
+	accessToken, err := h.jwt.GenerateTokenAAL(user.ID, user.Email, user.Perfil, aal)
 	if err != nil {
 		observability.CaptureHandlerError(c, err, map[string]string{"operation": "generate_token"})
 		response.ErrorInternal(c, "Erro ao gerar token", err.Error())
-		return
+		return false
 	}
-
-	// Gerar refresh token
-	refreshToken, err := h.refreshTokenSvc.Create(c.Request.Context(), user.ID)
+	refreshToken, err := h.refreshTokenSvc.Create(c.Request.Context(), user.ID, aal, origemSessao(c))
 	if err != nil {
 		observability.CaptureHandlerError(c, err, map[string]string{"operation": "create_refresh_token"})
 		response.ErrorInternal(c, "Erro ao gerar refresh token", err.Error())
-		return
+		return false
 	}
-
-	// Configurar cookie HttpOnly para o access token (15 minutos)
 	auth.SetSecureCookie(c, "ceialmilk_token", accessToken, 15*60, h.cookieSameSite)
-
-	// Configurar cookie HttpOnly para o refresh token (7 dias)
 	auth.SetSecureCookie(c, "ceialmilk_refresh_token", refreshToken.Token, 7*24*60*60, h.cookieSameSite)
-
-	// Tokens viajam apenas nos cookies HttpOnly — nunca no corpo JSON (reduz superfície de XSS).
-	loginData := gin.H{
-		"email":  user.Email,
-		"perfil": user.Perfil,
-		"nome":   user.Nome,
-	}
-	response.SuccessOK(c, loginData, "Login realizado com sucesso")
+	return true
 }
 
 func (h *AuthHandler) Validate(c *gin.Context) {
@@ -217,6 +286,10 @@ func (h *AuthHandler) Validate(c *gin.Context) {
 		"perfil":  user.Perfil,
 		"user_id": claims.UserID,
 		"nome":    user.Nome,
+		// email_verificado alimenta o aviso de confirmação na UI (BR-ACESSO-026).
+		"email_verificado": user.EmailVerificadoEm != nil,
+		// dois_fatores_pendente: perfil obrigado a 2FA numa sessão só de senha (BR-ACESSO-027).
+		"dois_fatores_pendente": h.jwt.ExigeDoisFatores(user.Perfil) && claims.NivelGarantia() < auth.AALDoisFatores,
 	}
 	response.SuccessOK(c, validateData, "Token válido")
 }
@@ -276,15 +349,15 @@ func (h *AuthHandler) Refresh(c *gin.Context) {
 		return
 	}
 
-	// Gerar novo access token
-	accessToken, err := h.jwt.GenerateToken(user.ID, user.Email, user.Perfil)
+	// Gerar novo access token, com o nível de garantia com que a sessão foi aberta (BR-ACESSO-027)
+	accessToken, err := h.jwt.GenerateTokenAAL(user.ID, user.Email, user.Perfil, rt.AAL)
 	if err != nil {
 		response.ErrorInternal(c, "Erro ao gerar token", err.Error())
 		return
 	}
 
 	// Rotação: revogar o refresh token usado e emitir um novo (limita janela de reuso em caso de roubo)
-	newRefreshToken, err := h.refreshTokenSvc.Rotate(c.Request.Context(), refreshTokenStr, user.ID)
+	newRefreshToken, err := h.refreshTokenSvc.Rotate(c.Request.Context(), refreshTokenStr, rt, origemSessao(c))
 	if err != nil {
 		observability.CaptureHandlerError(c, err, map[string]string{"operation": "rotate_refresh_token"})
 		response.ErrorInternal(c, "Erro ao renovar sessão", err.Error())
@@ -332,10 +405,13 @@ func (h *AuthHandler) Me(c *gin.Context) {
 	}
 
 	meData := gin.H{
-		"id":     user.ID,
-		"nome":   user.Nome,
-		"email":  user.Email,
-		"perfil": user.Perfil,
+		"id":               user.ID,
+		"nome":             user.Nome,
+		"email":            user.Email,
+		"perfil":           user.Perfil,
+		"email_verificado": user.EmailVerificadoEm != nil,
+		// aal nível de garantia da sessão (1 senha, 2 senha + TOTP; BR-ACESSO-027).
+		"aal": auth.GetAAL(c),
 	}
 	response.SuccessOK(c, meData, "OK")
 }
@@ -5,9 +5,15 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/ceialmilk/api/internal/auth"
+	"github.com/ceialmilk/api/internal/config"
 	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/oidc"
+	"github.com/ceialmilk/api/internal/oidc/oidctest"
+	"github.com/ceialmilk/api/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
 )
@@ -71,10 +77,12 @@ func TestAuthHandler_Me_OK(t *testing.T) {
 
 	var resp struct {
 		Data struct {
-			ID     int64  `json:"id"`
-			Nome   string `json:"nome"`
-			Email  string `json:"email"`
-			Perfil string `json:"perfil"`
+			ID              int64  `json:"id"`
+			Nome            string `json:"nome"`
+			Email           string `json:"email"`
+			Perfil          string `json:"perfil"`
+			EmailVerificado *bool  `json:"email_verificado"`
+			AAL             int    `json:"aal"`
 		} `json:"data"`
 	}
 	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
@@ -83,6 +91,12 @@ func TestAuthHandler_Me_OK(t *testing.T) {
 	if resp.Data.ID != 42 || resp.Data.Nome != "Maria" || resp.Data.Email != "maria@example.com" || resp.Data.Perfil != models.PerfilGerente {
 		t.Fatalf("unexpected data: %+v", resp.Data)
 	}
+	if resp.Data.EmailVerificado == nil || *resp.Data.EmailVerificado {
+		t.Fatalf("email_verificado = %v, want false", resp.Data.EmailVerificado)
+	}
+	if resp.Data.AAL != 1 {
+		t.Fatalf("aal = %d, want 1 (sessão só de senha)", resp.Data.AAL)
+	}
 }
 
 func TestAuthHandler_Me_MissingUserID(t *testing.T) {
@@ -132,3 +146,103 @@ func TestAuthHandler_Me_UserDisabled(t *testing.T) {
 		t.Fatalf("expected 401, got %d", w.Code)
 	}
 }
+
+// BR-ACESSO-027: o segundo passo só aceita o desafio emitido pelo login, nunca um access token.
+func TestAuthHandler_LoginDoisFatores_DesafioInvalido(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	priv, pub := config.DevJWTKeys()
+	jwtSvc, err := auth.NewJWTService(priv, pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doisFatoresSvc, err := service.NewDoisFatoresService(nil, nil, "chave-de-teste")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &AuthHandler{userRepo: &stubAuthUsuarioRepository{}, jwt: jwtSvc}
+	h.SetDoisFatoresService(doisFatoresSvc)
+
+	access, _ := jwtSvc.GenerateToken(42, "maria@example.com", models.PerfilAdmin)
+	for _, desafio := range []string{access, "lixo"} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		body := `{"desafio":"` + desafio + `","codigo":"123456"}`
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/auth/login/2fa", strings.NewReader(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		h.LoginDoisFatores(c)
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("desafio %q: expected 401, got %d body=%s", desafio[:4], w.Code, w.Body.String())
+		}
+		if len(w.Result().Cookies()) != 0 {
+			t.Fatal("nenhum cookie de sessão pode sair sem o segundo fator")
+		}
+	}
+}
+
+// BR-ACESSO-029: o callback só aceita o state do cookie assinado pelo próprio navegador.
+func TestAuthHandler_OIDC_LoginECallback(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	priv, pub := config.DevJWTKeys()
+	jwtSvc, err := auth.NewJWTService(priv, pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	iss, srv := oidctest.NewServer("ceialmilk", "")
+	defer srv.Close()
+	provedor, err := oidc.NewProvider(context.Background(), oidc.Config{
+		Issuer: iss.URL, ClientID: "ceialmilk", RedirectURL: "http://api.local/api/auth/oidc/callback",
+	}, srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &AuthHandler{userRepo: &stubAuthUsuarioRepository{}, jwt: jwtSvc}
+	h.SetSSOService(service.NewSSOService(provedor, nil, nil, service.MapeamentoSSO{}), "Cooperativa", "http://app.local/")
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/auth/oidc/login?redirect=//evil.example.com", nil)
+	h.OIDCLogin(c)
+	if w.Code != http.StatusFound || !strings.HasPrefix(w.Header().Get("Location"), iss.URL+"/authorize?") {
+		t.Fatalf("login: %d %s", w.Code, w.Header().Get("Location"))
+	}
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != auth.FluxoOIDCCookie || cookies[0].SameSite != http.SameSiteLaxMode {
+		t.Fatalf("cookie do fluxo = %+v", cookies)
+	}
+	fluxo, err := jwtSvc.ValidateFluxoOIDC(cookies[0].Value)
+	if err != nil || fluxo.Redirect != "" {
+		t.Fatalf("fluxo = %+v err %v (redirect externo deve ser descartado)", fluxo, err)
+	}
+
+	casos := []struct {
+		nome, query string
+		cookie      bool
+		want        string
+	}{
+		{"sem cookie", "?code=x&state=" + fluxo.State, false, "sso_erro=sessao_expirada"},
+		{"state de outro fluxo", "?code=x&state=outro", true, "sso_erro=sessao_expirada"},
+		{"recusa no provedor", "?error=access_denied&state=" + fluxo.State, true, "sso_erro=negado"},
+	}
+	for _, cs := range casos {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/auth/oidc/callback"+cs.query, nil)
+		if cs.cookie {
+			c.Request.AddCookie(cookies[0])
+		}
+		h.OIDCCallback(c)
+		loc := w.Header().Get("Location")
+		if w.Code != http.StatusFound || !strings.HasPrefix(loc, "http://app.local/login?") || !strings.Contains(loc, cs.want) {
+			t.Errorf("%s: %d %s", cs.nome, w.Code, loc)
+		}
+		for _, ck := range w.Result().Cookies() {
+			if ck.Name == "ceialmilk_token" || ck.Name == "ceialmilk_refresh_token" {
+				t.Errorf("%s: sessão aberta sem login concluído", cs.nome)
+			}
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ceialmilk/api/internal/auth"
+	"github.com/ceialmilk/api/internal/observability"
+	"github.com/ceialmilk/api/internal/response"
+	"github.com/ceialmilk/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// Login único por OpenID Connect (BR-ACESSO-029). O navegador vai ao provedor e volta ao callback da API,
+// que abre a sessão nos mesmos cookies do login por senha e redireciona para o frontend.
+
+// SetSSOService liga o login SSO; nome é o rótulo do botão ("Entrar com …") e frontendURL a base para
+// onde o callback redireciona. Sem ele GET /api/auth/oidc responde habilitado=false.
+func (h *AuthHandler) SetSSOService(svc *service.SSOService, nome, frontendURL string) {
+	h.ssoSvc = svc
+	h.ssoNome = nome
+	h.ssoFrontendURL = strings.TrimSuffix(frontendURL, "/")
+}
+
+// ssoErroCodigo código curto devolvido ao frontend em /login?sso_erro=.
+func ssoErroCodigo(err error) string {
+	switch {
+	case errors.Is(err, service.ErrSSOEmailAusente):
+		return "email_ausente"
+	case errors.Is(err, service.ErrSSOEmailNaoVerificado):
+		return "email_nao_verificado"
+	case errors.Is(err, service.ErrSSOUsuarioDesativado):
+		return "usuario_desativado"
+	case errors.Is(err, service.ErrSSOContaPrivilegiada):
+		return "conta_privilegiada"
+	case errors.Is(err, service.ErrSSOProvedor):
+		return "provedor"
+	default:
+		return "interno"
+	}
+}
+
+// redirectSSO só caminhos internos do frontend ("/x", nunca "//host" nem "/\host").
+func redirectSSO(r string) string {
+	if !strings.HasPrefix(r, "/") || strings.HasPrefix(r, "//") || strings.HasPrefix(r, "/\\") || len(r) > 512 {
+		return ""
+	}
+	return r
+}
+
+// voltarAoLogin redireciona para a página de login do frontend com os parâmetros dados.
+func (h *AuthHandler) voltarAoLogin(c *gin.Context, q url.Values, fragmento string) {
+	dest := h.ssoFrontendURL + "/login"
+	if enc := q.Encode(); enc != "" {
+		dest += "?" + enc
+	}
+	if fragmento != "" {
+		dest += "#" + fragmento
+	}
+	c.Redirect(http.StatusFound, dest)
+}
+
+// OIDCConfig GET /api/auth/oidc — se o botão de SSO aparece na tela de login.
+func (h *AuthHandler) OIDCConfig(c *gin.Context) {
+	if h.ssoSvc == nil {
+		response.SuccessOK(c, gin.H{"habilitado": false}, "Login único não configurado")
+		return
+	}
+	response.SuccessOK(c, gin.H{"habilitado": true, "nome": h.ssoNome}, "Login único disponível")
+}
+
+// OIDCLogin GET /api/auth/oidc/login?redirect= — guarda state, nonce e PKCE num cookie assinado e
+// envia o navegador ao provedor.
+func (h *AuthHandler) OIDCLogin(c *gin.Context) {
+	if h.ssoSvc == nil {
+		response.ErrorServiceUnavailable(c, "Login único não configurado no servidor", nil)
+		return
+	}
+	f, authURL, err := h.ssoSvc.Iniciar(redirectSSO(c.Query("redirect")))
+	if err != nil {
+		observability.CaptureHandlerError(c, err, map[string]string{"operation": "oidc_iniciar"})
+		response.ErrorInternal(c, "Erro ao iniciar login único", err.Error())
+		return
+	}
+	token, err := h.jwt.GenerateFluxoOIDC(f)
+	if err != nil {
+		response.ErrorInternal(c, "Erro ao iniciar login único", err.Error())
+		return
+	}
+	auth.SetSecureCookie(c, auth.FluxoOIDCCookie, token, int(auth.FluxoOIDCTTL.Seconds()), http.SameSiteLaxMode)
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// OIDCCallback GET /api/auth/oidc/callback — volta do provedor: confere o state, troca o código e abre a
+// sessão (AAL 1). Com 2FA ativo devolve o desafio ao frontend no fragmento (não vai a logs nem Referer).
+func (h *AuthHandler) OIDCCallback(c *gin.Context) {
+	if h.ssoSvc == nil {
+		response.ErrorServiceUnavailable(c, "Login único não configurado no servidor", nil)
+		return
+	}
+	cookie, _ := c.Cookie(auth.FluxoOIDCCookie)
+	auth.ClearCookie(c, auth.FluxoOIDCCookie, http.SameSiteLaxMode)
+	f, err := h.jwt.ValidateFluxoOIDC(cookie)
+	state := c.Query("state")
+	if err != nil || state == "" || subtle.ConstantTimeCompare([]byte(state), []byte(f.State)) != 1 {
+		h.voltarAoLogin(c, url.Values{"sso_erro": {"sessao_expirada"}}, "")
+		return
+	}
+	q := url.Values{}
+	if f.Redirect != "" {
+		q.Set("redirect", f.Redirect)
+	}
+	// Recusa no provedor (ex.: o usuário cancelou o consentimento).
+	if c.Query("error") != "" || c.Query("code") == "" {
+		q.Set("sso_erro", "negado")
+		h.voltarAoLogin(c, q, "")
+		return
+	}
+
+	res, err := h.ssoSvc.Concluir(c.Request.Context(), c.Query("code"), f)
+	if err != nil {
+		codigo := ssoErroCodigo(err)
+		if codigo == "interno" || codigo == "provedor" {
+			observability.CaptureHandlerError(c, err, map[string]string{"operation": "oidc_concluir"})
+		}
+		q.Set("sso_erro", codigo)
+		h.voltarAoLogin(c, q, "")
+		return
+	}
+	user := res.Usuario
+
+	// BR-ACESSO-027: o provedor conta como o primeiro fator; quem tem 2FA ativo ainda digita o código.
+	if h.doisFatoresSvc != nil {
+		ativo, err := h.doisFatoresSvc.Ativo(c.Request.Context(), user.ID)
+		if err != nil {
+			observability.CaptureHandlerError(c, err, map[string]string{"operation": "dois_fatores_ativo"})
+			q.Set("sso_erro", "interno")
+			h.voltarAoLogin(c, q, "")
+			return
+		}
+		if ativo {
+			desafio, err := h.emitirDesafioDoisFatores(c, user.ID)
+			if err != nil {
+				observability.CaptureHandlerError(c, err, map[string]string{"operation": "dois_fatores_desafio"})
+				q.Set("sso_erro", "interno")
+				h.voltarAoLogin(c, q, "")
+				return
+			}
+			h.voltarAoLogin(c, q, url.Values{"desafio": {desafio}}.Encode())
+			return
+		}
+	}
+
+	if !h.emitirSessao(c, user, auth.AALSenha) {
+		return
+	}
+	// A página de login, já autenticada, leva ao destino permitido para o perfil (ou ao onboarding).
+	q.Set("sso", "1")
+	h.voltarAoLogin(c, q, "")
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/ceialmilk/api/internal/auth"
+	"github.com/ceialmilk/api/internal/observability"
+	"github.com/ceialmilk/api/internal/response"
+	"github.com/ceialmilk/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// Sessões ativas por dispositivo (BR-ACESSO-028).
+
+// origemSessao dispositivo do pedido, gravado no refresh token no login e a cada renovação.
+func origemSessao(c *gin.Context) service.OrigemSessao {
+	return service.OrigemSessao{UserAgent: c.Request.UserAgent(), IP: c.ClientIP()}
+}
+
+// SetSessaoService liga a gestão de sessões; sem ele as rotas /me/sessoes respondem 503.
+func (h *AuthHandler) SetSessaoService(svc *service.SessaoService) {
+	h.sessaoSvc = svc
+}
+
+func sessaoError(c *gin.Context, err error, msg string) {
+	switch {
+	case errors.Is(err, service.ErrSessaoNotFound):
+		response.ErrorNotFound(c, err.Error())
+	case errors.Is(err, service.ErrUsuarioNotFound):
+		response.ErrorNotFound(c, "Usuário não encontrado")
+	case errors.Is(err, service.ErrSessaoUsuarioProtegido):
+		response.ErrorForbidden(c, err.Error())
+	default:
+		observability.CaptureHandlerError(c, err, map[string]string{"operation": "sessoes"})
+		response.ErrorInternal(c, msg, err.Error())
+	}
+}
+
+func sessaoDisponivel(c *gin.Context, svc *service.SessaoService) bool {
+	if svc == nil {
+		response.ErrorServiceUnavailable(c, "Gestão de sessões não configurada no servidor", nil)
+		return false
+	}
+	return true
+}
+
+func parseSessaoID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("sessaoId"), 10, 64)
+	if err != nil || id <= 0 {
+		response.ErrorBadRequest(c, "sessaoId inválido", nil)
+		return 0, false
+	}
+	return id, true
+}
+
+// sessaoAtual família do refresh token do cookie (0 se ausente ou já inválido).
+func (h *AuthHandler) sessaoAtual(c *gin.Context) int64 {
+	token, err := c.Cookie("ceialmilk_refresh_token")
+	if err != nil || token == "" {
+		return 0
+	}
+	rt, err := h.refreshTokenSvc.Validate(c.Request.Context(), token)
+	if err != nil {
+		return 0
+	}
+	return rt.FamiliaID
+}
+
+// encerrarCookies desliga o próprio dispositivo quando a sessão atual foi encerrada.
+func (h *AuthHandler) encerrarCookies(c *gin.Context) {
+	auth.ClearCookie(c, "ceialmilk_token", h.cookieSameSite)
+	auth.ClearCookie(c, "ceialmilk_refresh_token", h.cookieSameSite)
+}
+
+// ListSessoes GET /api/v1/me/sessoes — dispositivos com sessão ativa; `atual` marca o do pedido.
+func (h *AuthHandler) ListSessoes(c *gin.Context) {
+	if !sessaoDisponivel(c, h.sessaoSvc) {
+		return
+	}
+	userID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não identificado")
+		return
+	}
+	list, err := h.sessaoSvc.List(c.Request.Context(), userID, h.sessaoAtual(c))
+	if err != nil {
+		sessaoError(c, err, "Erro ao listar sessões")
+		return
+	}
+	response.SuccessOK(c, list, "")
+}
+
+// RevogarSessao DELETE /api/v1/me/sessoes/:sessaoId — encerrar a atual equivale ao logout.
+func (h *AuthHandler) RevogarSessao(c *gin.Context) {
+	if !sessaoDisponivel(c, h.sessaoSvc) {
+		return
+	}
+	userID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não identificado")
+		return
+	}
+	sessaoID, ok := parseSessaoID(c)
+	if !ok {
+		return
+	}
+	atual := h.sessaoAtual(c)
+	if err := h.sessaoSvc.Revogar(c.Request.Context(), userID, sessaoID); err != nil {
+		sessaoError(c, err, "Erro ao encerrar sessão")
+		return
+	}
+	if sessaoID == atual {
+		h.encerrarCookies(c)
+	}
+	response.SuccessOK(c, gin.H{"atual": sessaoID == atual}, "Sessão encerrada")
+}
+
+// RevogarSessoes DELETE /api/v1/me/sessoes — encerra as outras sessões; ?incluir_atual=true encerra
+// também a do pedido.
+func (h *AuthHandler) RevogarSessoes(c *gin.Context) {
+	if !sessaoDisponivel(c, h.sessaoSvc) {
+		return
+	}
+	userID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não identificado")
+		return
+	}
+	incluirAtual := c.Query("incluir_atual") == "true"
+	var manter int64
+	if !incluirAtual {
+		manter = h.sessaoAtual(c)
+	}
+	n, err := h.sessaoSvc.RevogarTodas(c.Request.Context(), userID, manter)
+	if err != nil {
+		sessaoError(c, err, "Erro ao encerrar sessões")
+		return
+	}
+	if incluirAtual {
+		h.encerrarCookies(c)
+	}
+	response.SuccessOK(c, gin.H{"revogadas": n}, "Sessões encerradas")
+}
+
+// SetSessaoService liga a consulta e o encerramento das sessões de um usuário pelo administrador.
+func (h *AdminHandler) SetSessaoService(svc *service.SessaoService) {
+	h.sessaoSvc = svc
+}
+
+// ListSessoesUsuario GET /api/v1/admin/usuarios/:id/sessoes
+func (h *AdminHandler) ListSessoesUsuario(c *gin.Context) {
+	if !sessaoDisponivel(c, h.sessaoSvc) {
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.ErrorBadRequest(c, "ID inválido", nil)
+		return
+	}
+	list, err := h.sessaoSvc.ListUsuario(c.Request.Context(), id)
+	if err != nil {
+		sessaoError(c, err, "Erro ao listar sessões")
+		return
+	}
+	response.SuccessOK(c, list, "")
+}
+
+// RevogarSessoesUsuario DELETE /api/v1/admin/usuarios/:id/sessoes[/:sessaoId] — uma sessão ou todas
+// (ex.: telemóvel perdido); o usuário volta a entrar com senha.
+func (h *AdminHandler) RevogarSessoesUsuario(c *gin.Context) {
+	if !sessaoDisponivel(c, h.sessaoSvc) {
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.ErrorBadRequest(c, "ID inválido", nil)
+		return
+	}
+	var sessaoID int64
+	if c.Param("sessaoId") != "" {
+		var ok bool
+		if sessaoID, ok = parseSessaoID(c); !ok {
+			return
+		}
+	}
+	n, err := h.sessaoSvc.RevogarUsuario(c.Request.Context(), id, sessaoID)
+	if err != nil {
+		sessaoError(c, err, "Erro ao encerrar sessões")
+		return
+	}
+	response.SuccessOK(c, gin.H{"revogadas": n}, "Sessões encerradas")
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/response"
+	"github.com/ceialmilk/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type CalendarioHandler struct {
+	svc *service.CalendarioService
+}
+
+func NewCalendarioHandler(svc *service.CalendarioService) *CalendarioHandler {
+	return &CalendarioHandler{svc: svc}
+}
+
+func calendarioError(c *gin.Context, err error, msg string) {
+	switch {
+	case errors.Is(err, service.ErrCalendarioEscopo):
+		response.ErrorValidation(c, err.Error(), nil)
+	case errors.Is(err, service.ErrCalendarioSemAcesso),
+		errors.Is(err, service.ErrCalendarioSoGestao):
+		response.ErrorForbidden(c, err.Error())
+	case errors.Is(err, service.ErrCalendarioLimite):
+		response.ErrorConflict(c, err.Error(), nil)
+	case errors.Is(err, service.ErrCalendarioFeedNotFound):
+		response.ErrorNotFound(c, err.Error())
+	default:
+		response.ErrorInternal(c, msg, err.Error())
+	}
+}
+
+// calendarioFeedPath caminho público do .ics; o frontend prefixa com a URL da API.
+func calendarioFeedPath(token string) string {
+	return "/api/v1/calendario/" + token + ".ics"
+}
+
+// GetFeeds GET /api/v1/me/calendario
+func (h *CalendarioHandler) GetFeeds(c *gin.Context) {
+	userID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não identificado")
+		return
+	}
+	list, err := h.svc.ListFeeds(c.Request.Context(), userID)
+	if err != nil {
+		calendarioError(c, err, "Erro ao listar assinaturas de calendário")
+		return
+	}
+	response.SuccessOK(c, list, "")
+}
+
+type calendarioFeedRequest struct {
+	FazendaID int64  `json:"fazenda_id" binding:"required"`
+	Escopo    string `json:"escopo"`
+}
+
+// PostFeed POST /api/v1/me/calendario — o token só aparece nesta resposta.
+func (h *CalendarioHandler) PostFeed(c *gin.Context) {
+	userID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não identificado")
+		return
+	}
+	var req calendarioFeedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Dados inválidos", err.Error())
+		return
+	}
+	if req.Escopo == "" {
+		req.Escopo = models.CalendarioEscopoPessoal
+	}
+	perfil, _ := c.Get("perfil")
+	p, _ := perfil.(string)
+	feed, token, err := h.svc.CriarFeed(c.Request.Context(), userID, p, req.FazendaID, req.Escopo)
+	if err != nil {
+		calendarioError(c, err, "Erro ao criar assinatura de calendário")
+		return
+	}
+	response.SuccessCreated(c, gin.H{
+		"feed":     feed,
+		"token":    token,
+		"url_path": calendarioFeedPath(token),
+	}, "Assinatura criada; guarde o link, ele não é exibido de novo")
+}
+
+// DeleteFeed DELETE /api/v1/me/calendario/:feedId
+func (h *CalendarioHandler) DeleteFeed(c *gin.Context) {
+	userID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não identificado")
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("feedId"), 10, 64)
+	if err != nil || id <= 0 {
+		response.ErrorBadRequest(c, "feedId inválido", nil)
+		return
+	}
+	if err := h.svc.RevogarFeed(c.Request.Context(), userID, id); err != nil {
+		calendarioError(c, err, "Erro ao revogar assinatura de calendário")
+		return
+	}
+	response.SuccessOK(c, nil, "Assinatura revogada")
+}
+
+// DeleteFeeds DELETE /api/v1/me/calendario — revoga todas as assinaturas do usuário.
+func (h *CalendarioHandler) DeleteFeeds(c *gin.Context) {
+	userID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não identificado")
+		return
+	}
+	n, err := h.svc.RevogarTodos(c.Request.Context(), userID)
+	if err != nil {
+		calendarioError(c, err, "Erro ao revogar assinaturas de calendário")
+		return
+	}
+	response.SuccessOK(c, gin.H{"revogadas": n}, "Assinaturas revogadas")
+}
+
+// GetICS GET /api/v1/calendario/:token (público; aceita o sufixo .ics que os apps esperam).
+func (h *CalendarioHandler) GetICS(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+	ics, err := h.svc.GerarICS(c.Request.Context(), token)
+	if err != nil {
+		calendarioError(c, err, "Erro ao gerar calendário")
+		return
+	}
+	c.Header("Cache-Control", "private, max-age=900")
+	c.Header("Content-Disposition", `inline; filename="folgas.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(ics))
+}
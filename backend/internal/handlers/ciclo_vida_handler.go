@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/response"
+	"github.com/ceialmilk/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type CicloVidaHandler struct {
+	svc        *service.CicloVidaService
+	fazendaSvc *service.FazendaService
+}
+
+func NewCicloVidaHandler(svc *service.CicloVidaService, fazendaSvc *service.FazendaService) *CicloVidaHandler {
+	return &CicloVidaHandler{svc: svc, fazendaSvc: fazendaSvc}
+}
+
+func (h *CicloVidaHandler) resolveFazenda(c *gin.Context) (int64, bool) {
+	fazendaID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || fazendaID <= 0 {
+		response.ErrorBadRequest(c, "fazenda_id inválido", nil)
+		return 0, false
+	}
+	if !ValidateFazendaAccess(c, h.fazendaSvc, fazendaID) {
+		return 0, false
+	}
+	return fazendaID, true
+}
+
+func (h *CicloVidaHandler) mapCicloVidaError(c *gin.Context, err error, internalMsg string) {
+	switch {
+	case errors.Is(err, service.ErrCicloVidaForbidden):
+		response.ErrorForbidden(c, err.Error())
+	case errors.Is(err, service.ErrCicloVidaConfigInvalida),
+		errors.Is(err, service.ErrPropostaLoteIDsInvalidos):
+		response.ErrorValidation(c, err.Error(), nil)
+	default:
+		response.ErrorInternal(c, internalMsg, err.Error())
+	}
+}
+
+// GetConfig GET /api/v1/fazendas/:id/ciclo-vida/config
+func (h *CicloVidaHandler) GetConfig(c *gin.Context) {
+	fazendaID, ok := h.resolveFazenda(c)
+	if !ok {
+		return
+	}
+	cfg, err := h.svc.GetConfig(c.Request.Context(), fazendaID)
+	if err != nil {
+		response.ErrorInternal(c, "Erro ao carregar configuração do ciclo de vida", err.Error())
+		return
+	}
+	response.SuccessOK(c, cfg, "Configuração do ciclo de vida carregada")
+}
+
+// PutConfig PUT /api/v1/fazendas/:id/ciclo-vida/config
+func (h *CicloVidaHandler) PutConfig(c *gin.Context) {
+	fazendaID, ok := h.resolveFazenda(c)
+	if !ok {
+		return
+	}
+	actorID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuario nao identificado")
+		return
+	}
+	var req struct {
+		NovilhaIdadeMeses int      `json:"novilha_idade_meses" binding:"required"`
+		NovilhaPesoKg     *float64 `json:"novilha_peso_kg"`
+		MachoIdadeMeses   int      `json:"macho_idade_meses" binding:"required"`
+		MachoCategoria    string   `json:"macho_categoria" binding:"required"`
+		PrePartoDias      int      `json:"pre_parto_dias" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Dados invalidos", err.Error())
+		return
+	}
+	cfg, err := h.svc.UpdateConfig(c.Request.Context(), fazendaID, service.UpdateCicloVidaConfigInput{
+		NovilhaIdadeMeses: req.NovilhaIdadeMeses,
+		NovilhaPesoKg:     req.NovilhaPesoKg,
+		MachoIdadeMeses:   req.MachoIdadeMeses,
+		MachoCategoria:    req.MachoCategoria,
+		PrePartoDias:      req.PrePartoDias,
+		ActorUserID:       actorID,
+		Perfil:            getActorPerfil(c),
+	})
+	if err != nil {
+		h.mapCicloVidaError(c, err, "Erro ao salvar configuração do ciclo de vida")
+		return
+	}
+	response.SuccessOK(c, cfg, "Configuração do ciclo de vida salva")
+}
+
+// Executar POST /api/v1/fazendas/:id/ciclo-vida/executar — corre o motor já, sem esperar o job diário.
+func (h *CicloVidaHandler) Executar(c *gin.Context) {
+	fazendaID, ok := h.resolveFazenda(c)
+	if !ok {
+		return
+	}
+	if !models.PodeGerirCicloVida(getActorPerfil(c)) {
+		response.ErrorForbidden(c, service.ErrCicloVidaForbidden.Error())
+		return
+	}
+	res, err := h.svc.ExecutarFazenda(c.Request.Context(), fazendaID, time.Now())
+	if err != nil {
+		response.ErrorInternal(c, "Erro ao executar o ciclo de vida", err.Error())
+		return
+	}
+	response.SuccessOK(c, res, "Ciclo de vida executado")
+}
+
+// ListPropostas GET /api/v1/fazendas/:id/movimentacoes-lote/propostas?status=
+func (h *CicloVidaHandler) ListPropostas(c *gin.Context) {
+	fazendaID, ok := h.resolveFazenda(c)
+	if !ok {
+		return
+	}
+	status := c.DefaultQuery("status", models.PropostaLoteStatusPendente)
+	if status == "TODAS" {
+		status = ""
+	}
+	list, err := h.svc.ListPropostas(c.Request.Context(), fazendaID, status)
+	if err != nil {
+		response.ErrorInternal(c, "Erro ao listar propostas de movimentação", err.Error())
+		return
+	}
+	response.SuccessOK(c, list, "Propostas de movimentação listadas")
+}
+
+type decidirPropostasRequest struct {
+	IDs []int64 `json:"ids" binding:"required"`
+}
+
+// AprovarPropostas POST /api/v1/fazendas/:id/movimentacoes-lote/propostas/aprovar
+func (h *CicloVidaHandler) AprovarPropostas(c *gin.Context) {
+	h.decidirPropostas(c, h.svc.AprovarPropostas, "Propostas aprovadas")
+}
+
+// RejeitarPropostas POST /api/v1/fazendas/:id/movimentacoes-lote/propostas/rejeitar
+func (h *CicloVidaHandler) RejeitarPropostas(c *gin.Context) {
+	h.decidirPropostas(c, h.svc.RejeitarPropostas, "Propostas rejeitadas")
+}
+
+func (h *CicloVidaHandler) decidirPropostas(c *gin.Context, decidir func(ctx context.Context, fazendaID int64, in service.DecidirPropostasInput) (*service.ResultadoDecisaoPropostas, error), msg string) {
+	fazendaID, ok := h.resolveFazenda(c)
+	if !ok {
+		return
+	}
+	actorID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuario nao identificado")
+		return
+	}
+	var req decidirPropostasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Dados invalidos", err.Error())
+		return
+	}
+	res, err := decidir(c.Request.Context(), fazendaID, service.DecidirPropostasInput{IDs: req.IDs, ActorUserID: actorID, Perfil: getActorPerfil(c)})
+	if err != nil {
+		h.mapCicloVidaError(c, err, "Erro ao decidir propostas de movimentação")
+		return
+	}
+	response.SuccessOK(c, res, msg)
+}
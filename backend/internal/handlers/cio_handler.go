@@ -155,6 +155,11 @@ func (h *CioHandler) Delete(c *gin.Context) {
 	}
 	if !ValidateFazendaAccess(c, h.fazendaSvc, cio.FazendaID) { return }
 	if err := h.svc.Delete(c.Request.Context(), id); err != nil {
+		if errors.Is(err, service.ErrCioNotFound) { response.ErrorNotFound(c, "Cio nao encontrado"); return }
+		if errors.Is(err, service.ErrCioTemVinculos) {
+			response.ErrorConflict(c, "Cio possui cobertura vinculada", nil)
+			return
+		}
 		if RespondIfAnimalForaRebanho(c, err) {
 			return
 		}
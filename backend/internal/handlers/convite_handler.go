@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/ceialmilk/api/internal/response"
+	"github.com/ceialmilk/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ConviteHandler struct {
+	svc *service.ConviteService
+}
+
+func NewConviteHandler(svc *service.ConviteService) *ConviteHandler {
+	return &ConviteHandler{svc: svc}
+}
+
+// conviteLinkPath caminho do frontend que abre o registro com o código preenchido.
+func conviteLinkPath(codigo string) string {
+	return "/registro?convite=" + codigo
+}
+
+func conviteError(c *gin.Context, err error, msg string) {
+	switch {
+	case errors.Is(err, service.ErrFazendaNotFound):
+		response.ErrorNotFound(c, "Fazenda não encontrada")
+	case errors.Is(err, service.ErrConviteNotFound):
+		response.ErrorNotFound(c, err.Error())
+	case errors.Is(err, service.ErrConviteForbidden):
+		response.ErrorForbidden(c, err.Error())
+	case errors.Is(err, service.ErrConvitePerfilInvalido),
+		errors.Is(err, service.ErrConvitePapelInvalido),
+		errors.Is(err, service.ErrConviteValidadeInvalida),
+		errors.Is(err, service.ErrConviteObservacaoLonga),
+		errors.Is(err, service.ErrConviteInvalido):
+		response.ErrorValidation(c, err.Error(), nil)
+	case errors.Is(err, service.ErrConviteNaoPendente),
+		errors.Is(err, service.ErrConviteJaVinculado):
+		response.ErrorConflict(c, err.Error(), nil)
+	case errors.Is(err, service.ErrConviteUsuarioIndisponivel):
+		response.ErrorForbidden(c, err.Error())
+	default:
+		response.ErrorInternal(c, msg, err.Error())
+	}
+}
+
+func parseConviteFazendaID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.ErrorBadRequest(c, "ID da fazenda inválido", nil)
+		return 0, false
+	}
+	return id, true
+}
+
+// List GET /api/v1/fazendas/:id/convites
+func (h *ConviteHandler) List(c *gin.Context) {
+	fazendaID, ok := parseConviteFazendaID(c)
+	if !ok {
+		return
+	}
+	userID, _ := GetActorUserID(c)
+	list, err := h.svc.List(c.Request.Context(), fazendaID, userID, getActorPerfil(c))
+	if err != nil {
+		conviteError(c, err, "Erro ao listar convites")
+		return
+	}
+	response.SuccessOK(c, list, "")
+}
+
+type criarConviteRequest struct {
+	Perfil       string  `json:"perfil" binding:"required"`
+	Papel        string  `json:"papel"`
+	ValidadeDias int     `json:"validade_dias"`
+	Observacao   *string `json:"observacao"`
+}
+
+// Create POST /api/v1/fazendas/:id/convites — o código só aparece nesta resposta.
+func (h *ConviteHandler) Create(c *gin.Context) {
+	fazendaID, ok := parseConviteFazendaID(c)
+	if !ok {
+		return
+	}
+	userID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não identificado")
+		return
+	}
+	var req criarConviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Dados inválidos", err.Error())
+		return
+	}
+	convite, codigo, err := h.svc.Criar(c.Request.Context(), fazendaID, service.CriarConviteInput{
+		Perfil:       req.Perfil,
+		Papel:        req.Papel,
+		ValidadeDias: req.ValidadeDias,
+		Observacao:   req.Observacao,
+		ActorUserID:  userID,
+		ActorPerfil:  getActorPerfil(c),
+	})
+	if err != nil {
+		conviteError(c, err, "Erro ao criar convite")
+		return
+	}
+	response.SuccessCreated(c, gin.H{
+		"convite":   convite,
+		"codigo":    codigo,
+		"link_path": conviteLinkPath(codigo),
+	}, "Convite criado; guarde o código, ele não é exibido de novo")
+}
+
+// Revogar POST /api/v1/fazendas/:id/convites/:conviteId/revogar
+func (h *ConviteHandler) Revogar(c *gin.Context) {
+	fazendaID, ok := parseConviteFazendaID(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("conviteId"), 10, 64)
+	if err != nil || id <= 0 {
+		response.ErrorBadRequest(c, "conviteId inválido", nil)
+		return
+	}
+	userID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não identificado")
+		return
+	}
+	convite, err := h.svc.Revogar(c.Request.Context(), fazendaID, id, userID, getActorPerfil(c))
+	if err != nil {
+		conviteError(c, err, "Erro ao revogar convite")
+		return
+	}
+	response.SuccessOK(c, convite, "Convite revogado")
+}
+
+// Preview GET /api/auth/convites/:codigo (público) — fazenda, perfil e papel antes do registro ou login.
+func (h *ConviteHandler) Preview(c *gin.Context) {
+	preview, err := h.svc.Preview(c.Request.Context(), c.Param("codigo"))
+	if err != nil {
+		conviteError(c, err, "Erro ao consultar convite")
+		return
+	}
+	response.SuccessOK(c, preview, "")
+}
+
+type resgatarConviteRequest struct {
+	Codigo string `json:"codigo" binding:"required"`
+}
+
+// Resgatar POST /api/v1/me/convites/resgatar — usuário já autenticado. Se o perfil mudou, o cliente
+// deve renovar a sessão (POST /api/auth/refresh) para o token refletir o novo perfil.
+func (h *ConviteHandler) Resgatar(c *gin.Context) {
+	userID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não identificado")
+		return
+	}
+	var req resgatarConviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Dados inválidos", err.Error())
+		return
+	}
+	res, err := h.svc.Resgatar(c.Request.Context(), req.Codigo, userID)
+	if err != nil {
+		conviteError(c, err, "Erro ao resgatar convite")
+		return
+	}
+	response.SuccessOK(c, res, "Convite aceito")
+}
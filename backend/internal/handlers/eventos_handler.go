@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ceialmilk/api/internal/auth"
+	"github.com/ceialmilk/api/internal/response"
+	"github.com/ceialmilk/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// eventosHeartbeat comentário SSE periódico: mantém a ligação viva atrás de proxies com timeout de
+	// inatividade; a cada batida o vínculo à fazenda é revalidado.
+	eventosHeartbeat = 25 * time.Second
+	// eventosDuracaoMax teto de vida do stream: o cliente reconecta e passa de novo pela autenticação.
+	eventosDuracaoMax = 15 * time.Minute
+)
+
+type EventosHandler struct {
+	svc        *service.EventosService
+	fazendaSvc fazendaAccessQuerier
+	// heartbeat e duracaoMax: eventosHeartbeat e eventosDuracaoMax (encurtados nos testes).
+	heartbeat  time.Duration
+	duracaoMax time.Duration
+}
+
+func NewEventosHandler(svc *service.EventosService, fazendaSvc *service.FazendaService) *EventosHandler {
+	return &EventosHandler{svc: svc, fazendaSvc: fazendaSvc, heartbeat: eventosHeartbeat, duracaoMax: eventosDuracaoMax}
+}
+
+// prazo devolve quando o stream deve encerrar: na expiração do access token, limitada a duracaoMax.
+func (h *EventosHandler) prazo(c *gin.Context, agora time.Time) time.Time {
+	prazo := agora.Add(h.duracaoMax)
+	if v, ok := c.Get(auth.ContextTokenExpiraEm); ok {
+		if exp, ok := v.(time.Time); ok && exp.Before(prazo) {
+			return exp
+		}
+	}
+	return prazo
+}
+
+// Stream GET /api/v1/me/eventos?fazenda_id= (Server-Sent Events)
+func (h *EventosHandler) Stream(c *gin.Context) {
+	fazendaID, err := strconv.ParseInt(c.Query("fazenda_id"), 10, 64)
+	if err != nil || fazendaID <= 0 {
+		response.ErrorValidation(c, "fazenda_id é obrigatório", nil)
+		return
+	}
+	if !ValidateFazendaAccess(c, h.fazendaSvc, fazendaID) {
+		return
+	}
+	userID, _ := GetActorUserID(c)
+
+	// O stream vive para além do WriteTimeout do servidor.
+	rc := http.NewResponseController(c.Writer)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	assinatura := h.svc.Assinar(fazendaID)
+	defer h.svc.Cancelar(assinatura)
+
+	w := c.Writer
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: 5000\nevent: conectado\ndata: {\"fazenda_id\":%d}\n\n", fazendaID)
+	w.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+	expira := time.NewTimer(time.Until(h.prazo(c, time.Now())))
+	defer expira.Stop()
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-expira.C:
+			// Sessão a expirar: o cliente renova o token e reabre o stream.
+			fmt.Fprint(w, "event: reautenticar\ndata: {}\n\n")
+			w.Flush()
+			return
+		case <-heartbeat.C:
+			// Vínculo removido com o stream aberto: encerra; a reconexão recebe 403.
+			// Erro de consulta não derruba o stream (fica para a próxima batida).
+			if vinculado, err := usuarioVinculadoFazenda(ctx, h.fazendaSvc, userID, fazendaID); err == nil && !vinculado {
+				return
+			}
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case ev, ok := <-assinatura.C:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(ev)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Entidade, data); err != nil {
+				return
+			}
+		}
+		w.Flush()
+	}
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ceialmilk/api/internal/auth"
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+func newEventosTestHandler(fazendas *stubFazendaAccessQuerier) *EventosHandler {
+	return &EventosHandler{
+		svc:        service.NewEventosService(nil),
+		fazendaSvc: fazendas,
+		heartbeat:  10 * time.Millisecond,
+		duracaoMax: time.Minute,
+	}
+}
+
+func newEventosTestContext(tokenExpiraEm time.Time) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/me/eventos?fazenda_id=3", nil)
+	c.Set("user_id", int64(7))
+	c.Set(auth.ContextTokenExpiraEm, tokenExpiraEm)
+	return c, w
+}
+
+// streamAteEncerrar corre o Stream e falha se ele não terminar sozinho.
+func streamAteEncerrar(t *testing.T, h *EventosHandler, c *gin.Context) {
+	t.Helper()
+	fim := make(chan struct{})
+	go func() {
+		defer close(fim)
+		h.Stream(c)
+	}()
+	select {
+	case <-fim:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream não encerrou")
+	}
+}
+
+func TestEventosPrazo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := &EventosHandler{duracaoMax: eventosDuracaoMax}
+	agora := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	if got := h.prazo(c, agora); !got.Equal(agora.Add(eventosDuracaoMax)) {
+		t.Errorf("sem expiração no contexto: prazo = %v", got)
+	}
+
+	c.Set(auth.ContextTokenExpiraEm, agora.Add(3*time.Minute))
+	if got := h.prazo(c, agora); !got.Equal(agora.Add(3 * time.Minute)) {
+		t.Errorf("token a expirar antes do teto: prazo = %v", got)
+	}
+
+	c.Set(auth.ContextTokenExpiraEm, agora.Add(time.Hour))
+	if got := h.prazo(c, agora); !got.Equal(agora.Add(eventosDuracaoMax)) {
+		t.Errorf("token longo: prazo = %v, want teto de %v", got, eventosDuracaoMax)
+	}
+}
+
+func TestEventosStream_EncerraNaExpiracaoDoToken(t *testing.T) {
+	h := newEventosTestHandler(&stubFazendaAccessQuerier{
+		getByUsuarioID: func(context.Context, int64) ([]*models.Fazenda, error) {
+			return []*models.Fazenda{{ID: 3}}, nil
+		},
+	})
+	c, w := newEventosTestContext(time.Now().Add(80 * time.Millisecond))
+
+	streamAteEncerrar(t, h, c)
+
+	corpo := w.Body.String()
+	if !strings.Contains(corpo, "event: conectado") || !strings.HasSuffix(corpo, "event: reautenticar\ndata: {}\n\n") {
+		t.Fatalf("corpo inesperado: %q", corpo)
+	}
+}
+
+func TestEventosStream_EncerraQuandoAcessoRevogado(t *testing.T) {
+	var consultas atomic.Int32
+	h := newEventosTestHandler(&stubFazendaAccessQuerier{
+		getByUsuarioID: func(context.Context, int64) ([]*models.Fazenda, error) {
+			// Abertura e primeira batida com vínculo; depois o usuário é desvinculado.
+			if consultas.Add(1) <= 2 {
+				return []*models.Fazenda{{ID: 3}}, nil
+			}
+			return []*models.Fazenda{{ID: 9}}, nil
+		},
+	})
+	c, w := newEventosTestContext(time.Now().Add(time.Minute))
+
+	streamAteEncerrar(t, h, c)
+
+	corpo := w.Body.String()
+	if strings.Contains(corpo, "reautenticar") {
+		t.Fatalf("stream deve encerrar pela revogação, não pela expiração: %q", corpo)
+	}
+	if n := consultas.Load(); n != 3 {
+		t.Fatalf("consultas de vínculo = %d, want 3", n)
+	}
+}
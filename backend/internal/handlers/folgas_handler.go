@@ -60,7 +60,7 @@ func (h *FolgasHandler) GetConfig(c *gin.Context) {
 	response.SuccessOK(c, cfg, "Configuração de folgas")
 }
 
-// PutConfig PUT /api/v1/fazendas/:id/folgas/config
+// PutConfig PUT /api/v1/fazendas/:id/folgas/config — substitui as equipes de rodízio (BR-FOLGAS-008).
 func (h *FolgasHandler) PutConfig(c *gin.Context) {
 	fazendaID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil || fazendaID <= 0 {
@@ -80,29 +80,58 @@ func (h *FolgasHandler) PutConfig(c *gin.Context) {
 	userID, _ := uid.(int64)
 
 	var req struct {
-		DataAnchor   string `json:"data_anchor" binding:"required"`
-		UsuarioSlot0 int64  `json:"usuario_slot_0" binding:"required"`
-		UsuarioSlot1 int64  `json:"usuario_slot_1" binding:"required"`
-		UsuarioSlot2 int64  `json:"usuario_slot_2" binding:"required"`
+		Equipes []struct {
+			ID             int64  `json:"id"`
+			Nome           string `json:"nome"`
+			DataAnchor     string `json:"data_anchor"`
+			CicloDias      int    `json:"ciclo_dias"`
+			FolgasPorCiclo int    `json:"folgas_por_ciclo"`
+			Participantes  []struct {
+				UsuarioID    int64 `json:"usuario_id"`
+				Deslocamento *int  `json:"deslocamento"`
+			} `json:"participantes"`
+		} `json:"equipes" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		response.ErrorValidation(c, "Dados inválidos", err.Error())
 		return
 	}
-	da, err := parseDateBody(req.DataAnchor)
-	if err != nil {
-		response.ErrorValidation(c, "data_anchor inválida", err.Error())
-		return
+	in := make([]service.FolgasEquipeInput, 0, len(req.Equipes))
+	for _, e := range req.Equipes {
+		da, err := parseDateBody(e.DataAnchor)
+		if err != nil {
+			response.ErrorValidation(c, "data_anchor inválida", err.Error())
+			return
+		}
+		eq := service.FolgasEquipeInput{
+			ID:             e.ID,
+			Nome:           e.Nome,
+			DataAnchor:     da,
+			CicloDias:      e.CicloDias,
+			FolgasPorCiclo: e.FolgasPorCiclo,
+		}
+		for _, p := range e.Participantes {
+			eq.Participantes = append(eq.Participantes, service.FolgasParticipanteInput{UsuarioID: p.UsuarioID, Deslocamento: p.Deslocamento})
+		}
+		in = append(in, eq)
 	}
-	if err := h.svc.PutConfig(c.Request.Context(), fazendaID, da, req.UsuarioSlot0, req.UsuarioSlot1, req.UsuarioSlot2, userID, p); err != nil {
+	if err := h.svc.PutConfig(c.Request.Context(), fazendaID, in, userID, p); err != nil {
 		if errors.Is(err, service.ErrFolgasSemPermissao) {
 			response.ErrorForbidden(c, err.Error())
 			return
 		}
-		if errors.Is(err, service.ErrFolgasSlotsInvalidos) {
+		if errors.Is(err, service.ErrFolgasSemEquipes) ||
+			errors.Is(err, service.ErrFolgasEquipeNome) ||
+			errors.Is(err, service.ErrFolgasEquipeCiclo) ||
+			errors.Is(err, service.ErrFolgasEquipeParticipantes) ||
+			errors.Is(err, service.ErrFolgasEquipeDeslocamento) {
 			response.ErrorValidation(c, err.Error(), nil)
 			return
 		}
+		if errors.Is(err, service.ErrFolgasEquipeNotFound) {
+			response.ErrorNotFound(c, err.Error())
+			return
+		}
 		if errors.Is(err, service.ErrFolgasPerfilNaoPermitido) {
 			response.ErrorValidation(c, err.Error(), map[string]string{"hint": "Selecione usuários com perfil FUNCIONARIO, GERENTE, PROPRIETARIO (ou GESTAO)."})
 			return
@@ -413,3 +442,373 @@ func (h *FolgasHandler) GetAlertas(c *gin.Context) {
 	}
 	response.SuccessOK(c, list, "Alertas calculados")
 }
+
+func folgasTrocaError(c *gin.Context, err error, msg string) {
+	switch {
+	case errors.Is(err, service.ErrFolgasSemPermissao), errors.Is(err, service.ErrFolgasTrocaAcaoNaoPermitida):
+		response.ErrorForbidden(c, err.Error())
+	case errors.Is(err, service.ErrFolgasTrocaNotFound):
+		response.ErrorNotFound(c, err.Error())
+	case errors.Is(err, service.ErrFolgasTrocaMotivo),
+		errors.Is(err, service.ErrFolgasTrocaMesmoUsuario),
+		errors.Is(err, service.ErrFolgasTrocaDatas):
+		response.ErrorValidation(c, err.Error(), nil)
+	case errors.Is(err, service.ErrFolgasPerfilNaoPermitido),
+		errors.Is(err, service.ErrFolgasTrocaSemFolga),
+		errors.Is(err, service.ErrFolgasTrocaDestinoOcupado),
+		errors.Is(err, service.ErrFolgasTrocaPendenteExiste),
+		errors.Is(err, service.ErrFolgasTrocaStatusInvalido),
+		errors.Is(err, service.ErrFolgasTrocaVencida),
+		errors.Is(err, service.ErrFolgasTrocaEscalaMudou):
+		response.ErrorBadRequest(c, err.Error(), nil)
+	default:
+		response.ErrorInternal(c, msg, err.Error())
+	}
+}
+
+func folgasTrocaParams(c *gin.Context) (fazendaID, trocaID int64, ok bool) {
+	fazendaID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || fazendaID <= 0 {
+		response.ErrorBadRequest(c, "fazenda_id inválido", nil)
+		return 0, 0, false
+	}
+	trocaID, err = strconv.ParseInt(c.Param("trocaId"), 10, 64)
+	if err != nil || trocaID <= 0 {
+		response.ErrorBadRequest(c, "troca_id inválido", nil)
+		return 0, 0, false
+	}
+	return fazendaID, trocaID, true
+}
+
+// GetTrocas GET /api/v1/fazendas/:id/folgas/trocas?status=
+func (h *FolgasHandler) GetTrocas(c *gin.Context) {
+	fazendaID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || fazendaID <= 0 {
+		response.ErrorBadRequest(c, "fazenda_id inválido", nil)
+		return
+	}
+	if !ValidateFazendaAccessOrGestao(c, h.svc.FazendaService(), fazendaID) {
+		return
+	}
+	perfil, _ := c.Get("perfil")
+	p, _ := perfil.(string)
+	uid, _ := c.Get("user_id")
+	userID, _ := uid.(int64)
+	list, err := h.svc.ListTrocas(c.Request.Context(), fazendaID, c.Query("status"), p, userID)
+	if err != nil {
+		folgasTrocaError(c, err, "Erro ao listar trocas")
+		return
+	}
+	response.SuccessOK(c, list, "Trocas de folga")
+}
+
+// PostTroca POST /api/v1/fazendas/:id/folgas/trocas
+func (h *FolgasHandler) PostTroca(c *gin.Context) {
+	fazendaID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || fazendaID <= 0 {
+		response.ErrorBadRequest(c, "fazenda_id inválido", nil)
+		return
+	}
+	if !ValidateFazendaAccessOrGestao(c, h.svc.FazendaService(), fazendaID) {
+		return
+	}
+	perfil, _ := c.Get("perfil")
+	p, _ := perfil.(string)
+	uid, _ := c.Get("user_id")
+	userID, _ := uid.(int64)
+
+	var req struct {
+		ColegaID        int64  `json:"colega_id" binding:"required"`
+		DataSolicitante string `json:"data_solicitante" binding:"required"`
+		DataColega      string `json:"data_colega" binding:"required"`
+		Motivo          string `json:"motivo" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Dados inválidos", err.Error())
+		return
+	}
+	dSol, err := parseDateBody(req.DataSolicitante)
+	if err != nil {
+		response.ErrorValidation(c, "data_solicitante inválida", err.Error())
+		return
+	}
+	dCol, err := parseDateBody(req.DataColega)
+	if err != nil {
+		response.ErrorValidation(c, "data_colega inválida", err.Error())
+		return
+	}
+	t, err := h.svc.SolicitarTroca(c.Request.Context(), fazendaID, service.FolgaTrocaInput{
+		ColegaID:        req.ColegaID,
+		DataSolicitante: dSol,
+		DataColega:      dCol,
+		Motivo:          req.Motivo,
+	}, userID, p)
+	if err != nil {
+		folgasTrocaError(c, err, "Erro ao solicitar troca")
+		return
+	}
+	response.SuccessCreated(c, t, "Troca solicitada; aguardando o colega")
+}
+
+// PostTrocaResposta POST /api/v1/fazendas/:id/folgas/trocas/:trocaId/resposta
+func (h *FolgasHandler) PostTrocaResposta(c *gin.Context) {
+	fazendaID, trocaID, ok := folgasTrocaParams(c)
+	if !ok || !ValidateFazendaAccessOrGestao(c, h.svc.FazendaService(), fazendaID) {
+		return
+	}
+	perfil, _ := c.Get("perfil")
+	p, _ := perfil.(string)
+	uid, _ := c.Get("user_id")
+	userID, _ := uid.(int64)
+
+	var req struct {
+		Aceitar *bool `json:"aceitar" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Dados inválidos", err.Error())
+		return
+	}
+	t, err := h.svc.ResponderTroca(c.Request.Context(), fazendaID, trocaID, *req.Aceitar, userID, p)
+	if err != nil {
+		folgasTrocaError(c, err, "Erro ao responder troca")
+		return
+	}
+	response.SuccessOK(c, t, "Resposta registrada")
+}
+
+// PostTrocaDecisao POST /api/v1/fazendas/:id/folgas/trocas/:trocaId/decisao
+func (h *FolgasHandler) PostTrocaDecisao(c *gin.Context) {
+	fazendaID, trocaID, ok := folgasTrocaParams(c)
+	if !ok || !ValidateFazendaAccessOrGestao(c, h.svc.FazendaService(), fazendaID) {
+		return
+	}
+	perfil, _ := c.Get("perfil")
+	p, _ := perfil.(string)
+	uid, _ := c.Get("user_id")
+	userID, _ := uid.(int64)
+
+	var req struct {
+		Aprovar    *bool  `json:"aprovar" binding:"required"`
+		Observacao string `json:"observacao"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Dados inválidos", err.Error())
+		return
+	}
+	t, err := h.svc.DecidirTroca(c.Request.Context(), fazendaID, trocaID, *req.Aprovar, req.Observacao, userID, p)
+	if err != nil {
+		folgasTrocaError(c, err, "Erro ao decidir troca")
+		return
+	}
+	response.SuccessOK(c, t, "Decisão registrada")
+}
+
+// PostTrocaCancelar POST /api/v1/fazendas/:id/folgas/trocas/:trocaId/cancelar
+func (h *FolgasHandler) PostTrocaCancelar(c *gin.Context) {
+	fazendaID, trocaID, ok := folgasTrocaParams(c)
+	if !ok || !ValidateFazendaAccessOrGestao(c, h.svc.FazendaService(), fazendaID) {
+		return
+	}
+	perfil, _ := c.Get("perfil")
+	p, _ := perfil.(string)
+	uid, _ := c.Get("user_id")
+	userID, _ := uid.(int64)
+	t, err := h.svc.CancelarTroca(c.Request.Context(), fazendaID, trocaID, userID, p)
+	if err != nil {
+		folgasTrocaError(c, err, "Erro ao cancelar troca")
+		return
+	}
+	response.SuccessOK(c, t, "Troca cancelada")
+}
+
+func folgasAusenciaError(c *gin.Context, err error, msg string) {
+	switch {
+	case errors.Is(err, service.ErrFolgasSemPermissao):
+		response.ErrorForbidden(c, err.Error())
+	case errors.Is(err, service.ErrFolgasAusenciaNotFound):
+		response.ErrorNotFound(c, err.Error())
+	case errors.Is(err, service.ErrFolgasAusenciaTipo),
+		errors.Is(err, service.ErrFolgasAusenciaPeriodo),
+		errors.Is(err, service.ErrFolgasFeriasDireito):
+		response.ErrorValidation(c, err.Error(), nil)
+	case errors.Is(err, service.ErrFolgasPerfilNaoPermitido),
+		errors.Is(err, service.ErrFolgasAusenciaSobreposta):
+		response.ErrorBadRequest(c, err.Error(), nil)
+	default:
+		response.ErrorInternal(c, msg, err.Error())
+	}
+}
+
+// GetAusencias GET /api/v1/fazendas/:id/folgas/ausencias?inicio&fim
+func (h *FolgasHandler) GetAusencias(c *gin.Context) {
+	fazendaID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || fazendaID <= 0 {
+		response.ErrorBadRequest(c, "fazenda_id inválido", nil)
+		return
+	}
+	if !ValidateFazendaAccessOrGestao(c, h.svc.FazendaService(), fazendaID) {
+		return
+	}
+	inicio, err := parseDateQuery(c, "inicio")
+	if err != nil {
+		response.ErrorBadRequest(c, "Parâmetro inicio obrigatório", nil)
+		return
+	}
+	fim, err := parseDateQuery(c, "fim")
+	if err != nil {
+		response.ErrorBadRequest(c, "Parâmetro fim obrigatório", nil)
+		return
+	}
+	perfil, _ := c.Get("perfil")
+	p, _ := perfil.(string)
+	uid, _ := c.Get("user_id")
+	userID, _ := uid.(int64)
+	list, err := h.svc.ListAusencias(c.Request.Context(), fazendaID, inicio, fim, p, userID)
+	if err != nil {
+		folgasAusenciaError(c, err, "Erro ao listar ausências")
+		return
+	}
+	response.SuccessOK(c, list, "Ausências")
+}
+
+// PostAusencia POST /api/v1/fazendas/:id/folgas/ausencias
+func (h *FolgasHandler) PostAusencia(c *gin.Context) {
+	fazendaID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || fazendaID <= 0 {
+		response.ErrorBadRequest(c, "fazenda_id inválido", nil)
+		return
+	}
+	if !ValidateFazendaAccessOrGestao(c, h.svc.FazendaService(), fazendaID) {
+		return
+	}
+	perfil, _ := c.Get("perfil")
+	p, _ := perfil.(string)
+	uid, _ := c.Get("user_id")
+	userID, _ := uid.(int64)
+
+	var req struct {
+		UsuarioID  int64  `json:"usuario_id" binding:"required"`
+		Tipo       string `json:"tipo" binding:"required"`
+		DataInicio string `json:"data_inicio" binding:"required"`
+		DataFim    string `json:"data_fim" binding:"required"`
+		AnexoRef   string `json:"anexo_ref"`
+		Observacao string `json:"observacao"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Dados inválidos", err.Error())
+		return
+	}
+	inicio, err := parseDateBody(req.DataInicio)
+	if err != nil {
+		response.ErrorValidation(c, "data_inicio inválida", err.Error())
+		return
+	}
+	fim, err := parseDateBody(req.DataFim)
+	if err != nil {
+		response.ErrorValidation(c, "data_fim inválida", err.Error())
+		return
+	}
+	a, err := h.svc.RegistrarAusencia(c.Request.Context(), fazendaID, service.FolgaAusenciaInput{
+		UsuarioID:  req.UsuarioID,
+		Tipo:       req.Tipo,
+		DataInicio: inicio,
+		DataFim:    fim,
+		AnexoRef:   req.AnexoRef,
+		Observacao: req.Observacao,
+	}, userID, p)
+	if err != nil {
+		folgasAusenciaError(c, err, "Erro ao registrar ausência")
+		return
+	}
+	response.SuccessCreated(c, a, "Ausência registrada")
+}
+
+// DeleteAusencia DELETE /api/v1/fazendas/:id/folgas/ausencias/:ausenciaId
+func (h *FolgasHandler) DeleteAusencia(c *gin.Context) {
+	fazendaID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || fazendaID <= 0 {
+		response.ErrorBadRequest(c, "fazenda_id inválido", nil)
+		return
+	}
+	ausenciaID, err := strconv.ParseInt(c.Param("ausenciaId"), 10, 64)
+	if err != nil || ausenciaID <= 0 {
+		response.ErrorBadRequest(c, "ausencia_id inválido", nil)
+		return
+	}
+	if !ValidateFazendaAccessOrGestao(c, h.svc.FazendaService(), fazendaID) {
+		return
+	}
+	perfil, _ := c.Get("perfil")
+	p, _ := perfil.(string)
+	uid, _ := c.Get("user_id")
+	userID, _ := uid.(int64)
+	if err := h.svc.ExcluirAusencia(c.Request.Context(), fazendaID, ausenciaID, userID, p); err != nil {
+		folgasAusenciaError(c, err, "Erro ao excluir ausência")
+		return
+	}
+	response.SuccessOK(c, nil, "Ausência excluída")
+}
+
+// GetFeriasSaldo GET /api/v1/fazendas/:id/folgas/ferias-saldo?ano=
+func (h *FolgasHandler) GetFeriasSaldo(c *gin.Context) {
+	fazendaID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || fazendaID <= 0 {
+		response.ErrorBadRequest(c, "fazenda_id inválido", nil)
+		return
+	}
+	if !ValidateFazendaAccessOrGestao(c, h.svc.FazendaService(), fazendaID) {
+		return
+	}
+	ano := time.Now().Year()
+	if v := c.Query("ano"); v != "" {
+		if ano, err = strconv.Atoi(v); err != nil {
+			response.ErrorBadRequest(c, "ano inválido", nil)
+			return
+		}
+	}
+	perfil, _ := c.Get("perfil")
+	p, _ := perfil.(string)
+	uid, _ := c.Get("user_id")
+	userID, _ := uid.(int64)
+	list, err := h.svc.SaldoFerias(c.Request.Context(), fazendaID, ano, p, userID)
+	if err != nil {
+		folgasAusenciaError(c, err, "Erro ao calcular saldo de férias")
+		return
+	}
+	response.SuccessOK(c, list, "Saldo de férias")
+}
+
+// PutFeriasDireito PUT /api/v1/fazendas/:id/folgas/ferias-saldo/:usuarioId
+func (h *FolgasHandler) PutFeriasDireito(c *gin.Context) {
+	fazendaID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || fazendaID <= 0 {
+		response.ErrorBadRequest(c, "fazenda_id inválido", nil)
+		return
+	}
+	usuarioID, err := strconv.ParseInt(c.Param("usuarioId"), 10, 64)
+	if err != nil || usuarioID <= 0 {
+		response.ErrorBadRequest(c, "usuario_id inválido", nil)
+		return
+	}
+	if !ValidateFazendaAccessOrGestao(c, h.svc.FazendaService(), fazendaID) {
+		return
+	}
+	perfil, _ := c.Get("perfil")
+	p, _ := perfil.(string)
+	uid, _ := c.Get("user_id")
+	userID, _ := uid.(int64)
+
+	var req struct {
+		Ano  int  `json:"ano" binding:"required"`
+		Dias *int `json:"dias" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Dados inválidos", err.Error())
+		return
+	}
+	if err := h.svc.DefinirFeriasDireito(c.Request.Context(), fazendaID, usuarioID, req.Ano, *req.Dias, userID, p); err != nil {
+		folgasAusenciaError(c, err, "Erro ao salvar dias de férias")
+		return
+	}
+	response.SuccessOK(c, nil, "Dias de férias atualizados")
+}
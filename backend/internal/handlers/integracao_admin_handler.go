@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"errors"
 	"strconv"
+	"time"
 
 	"github.com/ceialmilk/api/internal/response"
 	"github.com/ceialmilk/api/internal/service"
@@ -17,9 +19,11 @@ func NewIntegracaoAdminHandler(integracaoSvc *service.IntegracaoService) *Integr
 }
 
 type createIntegracaoRequest struct {
-	Nome       string   `json:"nome" binding:"required"`
-	FazendaIDs []int64  `json:"fazenda_ids" binding:"required"`
-	Scopes     []string `json:"scopes" binding:"required"`
+	Nome          string     `json:"nome" binding:"required"`
+	FazendaIDs    []int64    `json:"fazenda_ids" binding:"required"`
+	Scopes        []string   `json:"scopes" binding:"required"`
+	ChaveExpiraEm *time.Time `json:"chave_expira_em"`
+	IPAllowlist   []string   `json:"ip_allowlist"`
 }
 
 type updateIntegracaoRequest struct {
@@ -27,6 +31,14 @@ type updateIntegracaoRequest struct {
 	Ativo      *bool    `json:"ativo"`
 	FazendaIDs []int64  `json:"fazenda_ids"`
 	Scopes     []string `json:"scopes"`
+	// IPAllowlist presente (mesmo vazia) substitui a lista; ausente mantém.
+	IPAllowlist   *[]string  `json:"ip_allowlist"`
+	ChaveExpiraEm *time.Time `json:"chave_expira_em"`
+	SemExpiracao  bool       `json:"sem_expiracao"`
+}
+
+type rotacionarChaveRequest struct {
+	PeriodoGracaHoras *int `json:"periodo_graca_horas"`
 }
 
 func (h *IntegracaoAdminHandler) List(c *gin.Context) {
@@ -54,7 +66,7 @@ func (h *IntegracaoAdminHandler) Create(c *gin.Context) {
 		response.ErrorUnauthorized(c, "Admin nao identificado")
 		return
 	}
-	cliente, apiKey, err := h.integracaoSvc.CreateCliente(c.Request.Context(), req.Nome, req.FazendaIDs, req.Scopes, adminID)
+	cliente, apiKey, err := h.integracaoSvc.CreateCliente(c.Request.Context(), req.Nome, req.FazendaIDs, req.Scopes, adminID, req.ChaveExpiraEm, req.IPAllowlist)
 	if err != nil {
 		response.ErrorValidation(c, err.Error(), nil)
 		return
@@ -99,10 +111,26 @@ func (h *IntegracaoAdminHandler) Update(c *gin.Context) {
 	if req.Scopes != nil {
 		scopes = req.Scopes
 	}
+	if req.ChaveExpiraEm != nil && req.SemExpiracao {
+		response.ErrorValidation(c, "Use chave_expira_em ou sem_expiracao, nao ambos", nil)
+		return
+	}
 	if err := h.integracaoSvc.UpdateCliente(c.Request.Context(), id, nome, req.Ativo, fazendaIDs, scopes); err != nil {
 		response.ErrorValidation(c, err.Error(), nil)
 		return
 	}
+	if req.IPAllowlist != nil {
+		if err := h.integracaoSvc.SetIPAllowlist(c.Request.Context(), id, *req.IPAllowlist); err != nil {
+			response.ErrorValidation(c, err.Error(), nil)
+			return
+		}
+	}
+	if req.ChaveExpiraEm != nil || req.SemExpiracao {
+		if err := h.integracaoSvc.SetChaveExpiracao(c.Request.Context(), id, req.ChaveExpiraEm); err != nil {
+			response.ErrorValidation(c, err.Error(), nil)
+			return
+		}
+	}
 	cliente, _ := h.integracaoSvc.GetByID(c.Request.Context(), id)
 	if cliente != nil {
 		cliente.KeyHash = ""
@@ -110,14 +138,33 @@ func (h *IntegracaoAdminHandler) Update(c *gin.Context) {
 	response.SuccessOK(c, cliente, "Cliente atualizado")
 }
 
+// RotacionarChave emite nova chave; body opcional {"periodo_graca_horas": N} mantém a anterior válida por N horas.
 func (h *IntegracaoAdminHandler) RotacionarChave(c *gin.Context) {
 	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
-	apiKey, err := h.integracaoSvc.RotacionarChave(c.Request.Context(), id)
+	var req rotacionarChaveRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.ErrorValidation(c, "Dados invalidos", err.Error())
+			return
+		}
+	}
+	apiKey, cliente, err := h.integracaoSvc.RotacionarChave(c.Request.Context(), id, req.PeriodoGracaHoras)
 	if err != nil {
-		response.ErrorNotFound(c, "Cliente nao encontrado")
+		switch {
+		case errors.Is(err, service.ErrIntegracaoClienteNotFound):
+			response.ErrorNotFound(c, "Cliente nao encontrado")
+		case errors.Is(err, service.ErrIntegracaoPeriodoGracaInvalido):
+			response.ErrorValidation(c, err.Error(), nil)
+		default:
+			response.ErrorInternal(c, "Erro ao rotacionar chave", err.Error())
+		}
 		return
 	}
-	response.SuccessOK(c, gin.H{"api_key": apiKey}, "Nova chave gerada. Guarde a api_key — nao sera exibida novamente.")
+	response.SuccessOK(c, gin.H{
+		"api_key":                  apiKey,
+		"chave_expira_em":          cliente.ChaveExpiraEm,
+		"chave_anterior_expira_em": cliente.ChaveAnteriorExpiraEm,
+	}, "Nova chave gerada. Guarde a api_key — nao sera exibida novamente.")
 }
 
 func (h *IntegracaoAdminHandler) Revogar(c *gin.Context) {
@@ -150,3 +197,43 @@ func (h *IntegracaoAdminHandler) ListChamadas(c *gin.Context) {
 	}
 	response.SuccessOK(c, gin.H{"chamadas": chamadas}, "OK")
 }
+
+// ListRejeicoes GET /admin/integracoes/rejeicoes?motivo=&limit=&offset= — tentativas rejeitadas de todos os clientes.
+func (h *IntegracaoAdminHandler) ListRejeicoes(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	rejeicoes, err := h.integracaoSvc.ListRejeicoes(c.Request.Context(), c.Query("motivo"), limit, offset)
+	if err != nil {
+		response.ErrorInternal(c, "Erro ao listar rejeicoes", err.Error())
+		return
+	}
+	response.SuccessOK(c, gin.H{"rejeicoes": rejeicoes}, "OK")
+}
+
+func (h *IntegracaoAdminHandler) GerarCredenciaisOAuth(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	clientID, clientSecret, err := h.integracaoSvc.GerarCredenciaisOAuth(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrIntegracaoClienteNotFound) {
+			response.ErrorNotFound(c, "Cliente nao encontrado")
+			return
+		}
+		response.ErrorInternal(c, "Erro ao gerar credenciais OAuth", err.Error())
+		return
+	}
+	response.SuccessOK(c, gin.H{"client_id": clientID, "client_secret": clientSecret},
+		"Credenciais OAuth geradas. Guarde o client_secret — nao sera exibido novamente.")
+}
+
+func (h *IntegracaoAdminHandler) RevogarTokensOAuth(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err := h.integracaoSvc.RevogarTokensOAuth(c.Request.Context(), id); err != nil {
+		if errors.Is(err, service.ErrIntegracaoClienteNotFound) {
+			response.ErrorNotFound(c, "Cliente nao encontrado")
+			return
+		}
+		response.ErrorInternal(c, "Erro ao revogar tokens", err.Error())
+		return
+	}
+	response.SuccessOK(c, nil, "Access tokens OAuth revogados")
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ceialmilk/api/internal/auth"
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const defaultIntegrationTokenTTL = 15 * time.Minute
+
+// IntegracaoOAuthHandler emite access tokens OAuth2 (grant client_credentials) para clientes M2M.
+// As respostas seguem o formato RFC 6749 (sem o envelope data/message) para compatibilidade
+// com bibliotecas OAuth de mercado.
+type IntegracaoOAuthHandler struct {
+	integracaoSvc *service.IntegracaoService
+	jwt           *auth.JWTService
+	tokenTTL      time.Duration
+}
+
+func NewIntegracaoOAuthHandler(integracaoSvc *service.IntegracaoService, jwt *auth.JWTService, tokenTTL time.Duration) *IntegracaoOAuthHandler {
+	if tokenTTL <= 0 {
+		tokenTTL = defaultIntegrationTokenTTL
+	}
+	return &IntegracaoOAuthHandler{integracaoSvc: integracaoSvc, jwt: jwt, tokenTTL: tokenTTL}
+}
+
+func oauthError(c *gin.Context, status int, code, description string) {
+	if status == http.StatusUnauthorized {
+		c.Header("WWW-Authenticate", `Basic realm="ceialmilk-integracoes"`)
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+	c.JSON(status, gin.H{"error": code, "error_description": description})
+}
+
+// Token POST /api/v1/integracoes/oauth/token (application/x-www-form-urlencoded).
+// Credenciais via HTTP Basic (client_secret_basic) ou campos client_id/client_secret (client_secret_post).
+// Rejeições são marcadas para IntegrationAuditMiddleware; emissões bem-sucedidas também ficam registadas.
+func (h *IntegracaoOAuthHandler) Token(c *gin.Context) {
+	if grant := c.PostForm("grant_type"); grant != "client_credentials" {
+		auth.SetIntegrationRejeicao(c, 0, models.IntegracaoRejeicaoFormatoInvalido)
+		if grant == "" {
+			oauthError(c, http.StatusBadRequest, "invalid_request", "grant_type obrigatorio")
+			return
+		}
+		oauthError(c, http.StatusBadRequest, "unsupported_grant_type", "apenas client_credentials e suportado")
+		return
+	}
+	clientID, clientSecret, hasBasic := c.Request.BasicAuth()
+	if !hasBasic {
+		clientID = strings.TrimSpace(c.PostForm("client_id"))
+		clientSecret = c.PostForm("client_secret")
+	}
+	cliente, err := h.integracaoSvc.AutenticarClientCredentials(c.Request.Context(), clientID, clientSecret)
+	if err != nil {
+		var rej *service.IntegracaoRejeicaoError
+		if errors.As(err, &rej) {
+			auth.SetIntegrationRejeicao(c, rej.ClienteID, rej.Motivo)
+		} else {
+			auth.SetIntegrationRejeicao(c, 0, models.IntegracaoRejeicaoErroInterno)
+		}
+		if errors.Is(err, service.ErrIntegracaoCredenciaisInvalidas) || errors.Is(err, service.ErrIntegracaoClienteInativo) {
+			oauthError(c, http.StatusUnauthorized, "invalid_client", "credenciais invalidas ou cliente revogado")
+			return
+		}
+		oauthError(c, http.StatusInternalServerError, "server_error", "erro ao autenticar cliente")
+		return
+	}
+	if err := h.integracaoSvc.VerificarOrigem(cliente, c.ClientIP()); err != nil {
+		auth.SetIntegrationRejeicao(c, cliente.ID, models.IntegracaoRejeicaoIPNaoPermitido)
+		oauthError(c, http.StatusUnauthorized, "invalid_client", "origem nao permitida para este cliente")
+		return
+	}
+	scopes, err := service.RestringirScopesOAuth(cliente.Scopes, service.ParseOAuthScope(c.PostForm("scope")))
+	if err != nil {
+		auth.SetIntegrationRejeicao(c, cliente.ID, models.IntegracaoRejeicaoScopeInsuficiente)
+		oauthError(c, http.StatusBadRequest, "invalid_scope", "scope nao atribuido a este cliente")
+		return
+	}
+	accessToken, _, err := h.jwt.GenerateIntegrationToken(cliente.ID, cliente.FazendaIDs, scopes, h.tokenTTL)
+	if err != nil {
+		oauthError(c, http.StatusInternalServerError, "server_error", "erro ao emitir token")
+		return
+	}
+	c.Set(auth.ContextIntegrationClientID, cliente.ID)
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+	c.JSON(http.StatusOK, models.IntegracaoTokenResposta{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(h.tokenTTL.Seconds()),
+		Scope:       strings.Join(scopes, " "),
+	})
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/response"
+	"github.com/ceialmilk/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type JobAdminHandler struct {
+	scheduler *service.JobScheduler
+}
+
+func NewJobAdminHandler(scheduler *service.JobScheduler) *JobAdminHandler {
+	return &JobAdminHandler{scheduler: scheduler}
+}
+
+// List GET /admin/jobs — jobs registados com agenda, próxima e última execução.
+func (h *JobAdminHandler) List(c *gin.Context) {
+	jobs, err := h.scheduler.ListJobs(c.Request.Context())
+	if err != nil {
+		response.ErrorInternal(c, "Erro ao listar jobs", err.Error())
+		return
+	}
+	response.SuccessOK(c, gin.H{"jobs": jobs}, "OK")
+}
+
+// ListExecucoes GET /admin/jobs/execucoes?job=&status=&limit=&offset= — histórico de execuções.
+func (h *JobAdminHandler) ListExecucoes(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	execucoes, err := h.scheduler.ListExecucoes(c.Request.Context(), models.JobExecucaoFiltro{
+		Job:    c.Query("job"),
+		Status: c.Query("status"),
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		response.ErrorInternal(c, "Erro ao listar execuções", err.Error())
+		return
+	}
+	response.SuccessOK(c, gin.H{"execucoes": execucoes}, "OK")
+}
+
+// GetExecucao GET /admin/jobs/execucoes/:id
+func (h *JobAdminHandler) GetExecucao(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ErrorValidation(c, "ID inválido", nil)
+		return
+	}
+	execucao, err := h.scheduler.GetExecucao(c.Request.Context(), id)
+	if err != nil {
+		response.ErrorInternal(c, "Erro ao buscar execução", err.Error())
+		return
+	}
+	if execucao == nil {
+		response.ErrorNotFound(c, "Execução não encontrada")
+		return
+	}
+	response.SuccessOK(c, execucao, "OK")
+}
+
+// Executar POST /admin/jobs/:nome/executar — dispara o job fora da agenda; 202 com a execução iniciada.
+func (h *JobAdminHandler) Executar(c *gin.Context) {
+	actorID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não autenticado")
+		return
+	}
+	execucao, err := h.scheduler.Disparar(c.Request.Context(), c.Param("nome"), actorID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrJobNaoEncontrado):
+			response.ErrorNotFound(c, "Job não encontrado")
+		case errors.Is(err, service.ErrJobEmExecucao):
+			response.ErrorConflict(c, "Job já em execução", nil)
+		default:
+			response.ErrorInternal(c, "Erro ao disparar job", err.Error())
+		}
+		return
+	}
+	response.Success(c, http.StatusAccepted, execucao, "Execução iniciada")
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/ceialmilk/api/internal/response"
+	"github.com/ceialmilk/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type LixeiraHandler struct {
+	svc        *service.LixeiraService
+	fazendaSvc *service.FazendaService
+}
+
+func NewLixeiraHandler(svc *service.LixeiraService, fazendaSvc *service.FazendaService) *LixeiraHandler {
+	return &LixeiraHandler{svc: svc, fazendaSvc: fazendaSvc}
+}
+
+// List GET /api/v1/fazendas/:id/lixeira?entidade=&limit=&offset=
+func (h *LixeiraHandler) List(c *gin.Context) {
+	fazendaID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || fazendaID <= 0 {
+		response.ErrorBadRequest(c, "fazenda_id inválido", nil)
+		return
+	}
+	if !ValidateFazendaAccess(c, h.fazendaSvc, fazendaID) {
+		return
+	}
+	limit := parseQueryIntPositiveDef(c.Query("limit"), 50)
+	if limit > 200 {
+		limit = 200
+	}
+	offset := parseQueryIntNonNeg(c.DefaultQuery("offset", "0"), 0)
+	itens, total, err := h.svc.List(c.Request.Context(), fazendaID, c.Query("entidade"), limit, offset)
+	if err != nil {
+		if errors.Is(err, service.ErrLixeiraEntidadeInvalida) {
+			response.ErrorValidation(c, err.Error(), nil)
+			return
+		}
+		response.ErrorInternal(c, "Erro ao listar lixeira", err.Error())
+		return
+	}
+	response.SuccessOK(c, gin.H{"itens": itens, "total": total}, "Lixeira listada")
+}
+
+// Restaurar POST /api/v1/fazendas/:id/lixeira/:entidade/:itemId/restaurar
+func (h *LixeiraHandler) Restaurar(c *gin.Context) {
+	fazendaID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || fazendaID <= 0 {
+		response.ErrorBadRequest(c, "fazenda_id inválido", nil)
+		return
+	}
+	itemID, err := strconv.ParseInt(c.Param("itemId"), 10, 64)
+	if err != nil || itemID <= 0 {
+		response.ErrorBadRequest(c, "id do item inválido", nil)
+		return
+	}
+	if !ValidateFazendaAccess(c, h.fazendaSvc, fazendaID) {
+		return
+	}
+	restaurado, err := h.svc.Restaurar(c.Request.Context(), fazendaID, c.Param("entidade"), itemID)
+	if err != nil {
+		if RespondIfDomainWriteError(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, service.ErrLixeiraEntidadeInvalida):
+			response.ErrorValidation(c, err.Error(), nil)
+		case errors.Is(err, service.ErrLixeiraItemNotFound),
+			errors.Is(err, service.ErrPartoNotFound),
+			errors.Is(err, service.ErrCioNotFound),
+			errors.Is(err, service.ErrCoberturaNotFound),
+			errors.Is(err, service.ErrProducaoNotFound):
+			response.ErrorNotFound(c, "Item nao encontrado na lixeira")
+		case errors.Is(err, service.ErrLixeiraRetencaoExpirada):
+			response.ErrorConflict(c, "Prazo de restauracao expirado", nil)
+		case errors.Is(err, service.ErrCriaIdentificacaoEmUso):
+			response.ErrorConflict(c, err.Error(), nil)
+		case errors.Is(err, service.ErrAnimalNotFound):
+			response.ErrorNotFound(c, "Animal nao encontrado")
+		default:
+			response.ErrorInternal(c, "Erro ao restaurar item", err.Error())
+		}
+		return
+	}
+	response.SuccessOK(c, restaurado, "Item restaurado")
+}
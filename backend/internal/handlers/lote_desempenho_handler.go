@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/ceialmilk/api/internal/response"
+	"github.com/ceialmilk/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type LoteDesempenhoHandler struct {
+	svc        *service.LoteDesempenhoService
+	loteSvc    *service.LoteService
+	fazendaSvc *service.FazendaService
+}
+
+func NewLoteDesempenhoHandler(svc *service.LoteDesempenhoService, loteSvc *service.LoteService, fazendaSvc *service.FazendaService) *LoteDesempenhoHandler {
+	return &LoteDesempenhoHandler{svc: svc, loteSvc: loteSvc, fazendaSvc: fazendaSvc}
+}
+
+// periodoDesempenho lê ?de=&ate= (YYYY-MM-DD); padrão: últimos 30 dias até hoje.
+func periodoDesempenho(c *gin.Context) (time.Time, time.Time, bool) {
+	ate := time.Now()
+	if q := c.Query("ate"); q != "" {
+		t, err := time.ParseInLocation("2006-01-02", q, time.Local)
+		if err != nil {
+			response.ErrorValidation(c, "ate inválida (use YYYY-MM-DD)", nil)
+			return time.Time{}, time.Time{}, false
+		}
+		ate = t
+	}
+	de := ate.AddDate(0, 0, -29)
+	if q := c.Query("de"); q != "" {
+		t, err := time.ParseInLocation("2006-01-02", q, time.Local)
+		if err != nil {
+			response.ErrorValidation(c, "de inválida (use YYYY-MM-DD)", nil)
+			return time.Time{}, time.Time{}, false
+		}
+		de = t
+	}
+	return de, ate, true
+}
+
+func respondLoteDesempenhoError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrLoteDesempenhoPeriodoInvalido),
+		errors.Is(err, service.ErrLoteDesempenhoMarcoInvalido):
+		response.ErrorValidation(c, err.Error(), nil)
+	case errors.Is(err, service.ErrLoteNotFound):
+		response.ErrorNotFound(c, "Lote não encontrado")
+	case errors.Is(err, service.ErrFazendaNotFound):
+		response.ErrorNotFound(c, "Fazenda não encontrada")
+	default:
+		response.ErrorInternal(c, "Erro ao carregar desempenho do lote", err.Error())
+	}
+}
+
+// GetLote GET /api/v1/lotes/:id/desempenho?de=&ate=&marco= — marco compara antes/depois (ex.: troca de dieta).
+func (h *LoteDesempenhoHandler) GetLote(c *gin.Context) {
+	loteID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || loteID <= 0 {
+		response.ErrorBadRequest(c, "ID do lote inválido", nil)
+		return
+	}
+	lote, err := h.loteSvc.GetByID(c.Request.Context(), loteID)
+	if err != nil {
+		respondLoteDesempenhoError(c, err)
+		return
+	}
+	if !ValidateFazendaAccess(c, h.fazendaSvc, lote.FazendaID) {
+		return
+	}
+	de, ate, ok := periodoDesempenho(c)
+	if !ok {
+		return
+	}
+	var marco *time.Time
+	if q := c.Query("marco"); q != "" {
+		t, err := time.ParseInLocation("2006-01-02", q, time.Local)
+		if err != nil {
+			response.ErrorValidation(c, "marco inválido (use YYYY-MM-DD)", nil)
+			return
+		}
+		marco = &t
+	}
+	out, err := h.svc.DesempenhoLote(c.Request.Context(), loteID, de, ate, marco)
+	if err != nil {
+		respondLoteDesempenhoError(c, err)
+		return
+	}
+	response.SuccessOK(c, out, "Desempenho do lote carregado com sucesso")
+}
+
+// GetFazenda GET /api/v1/fazendas/:id/lotes/desempenho?de=&ate=
+func (h *LoteDesempenhoHandler) GetFazenda(c *gin.Context) {
+	fazendaID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || fazendaID <= 0 {
+		response.ErrorBadRequest(c, "ID da fazenda inválido", nil)
+		return
+	}
+	if !ValidateFazendaAccess(c, h.fazendaSvc, fazendaID) {
+		return
+	}
+	de, ate, ok := periodoDesempenho(c)
+	if !ok {
+		return
+	}
+	out, err := h.svc.DesempenhoFazenda(c.Request.Context(), fazendaID, de, ate)
+	if err != nil {
+		respondLoteDesempenhoError(c, err)
+		return
+	}
+	response.SuccessOK(c, out, "Desempenho dos lotes carregado com sucesso")
+}
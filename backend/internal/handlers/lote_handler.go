@@ -26,6 +26,7 @@ func (h *LoteHandler) Create(c *gin.Context) {
 		Tipo       *string  `json:"tipo"`
 		Descricao  *string  `json:"descricao"`
 		Ativo      *bool    `json:"ativo"`
+		Capacidade *int     `json:"capacidade"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		response.ErrorValidation(c, "Dados invalidos", err.Error())
@@ -34,11 +35,15 @@ func (h *LoteHandler) Create(c *gin.Context) {
 	if !ValidateFazendaAccess(c, h.fazendaSvc, req.FazendaID) {
 		return
 	}
-	lote := &models.Lote{Nome: req.Nome, FazendaID: req.FazendaID, Tipo: req.Tipo, Descricao: req.Descricao, Ativo: true}
+	lote := &models.Lote{Nome: req.Nome, FazendaID: req.FazendaID, Tipo: req.Tipo, Descricao: req.Descricao, Capacidade: req.Capacidade, Ativo: true}
 	if req.Ativo != nil {
 		lote.Ativo = *req.Ativo
 	}
 	if err := h.svc.Create(c.Request.Context(), lote); err != nil {
+		if errors.Is(err, service.ErrLoteCapacidadeInvalida) {
+			response.ErrorValidation(c, err.Error(), nil)
+			return
+		}
 		response.ErrorInternal(c, "Erro ao criar lote", err.Error())
 		return
 	}
@@ -106,6 +111,8 @@ func (h *LoteHandler) Update(c *gin.Context) {
 		Tipo      *string `json:"tipo"`
 		Descricao *string `json:"descricao"`
 		Ativo     *bool   `json:"ativo"`
+		// Capacidade nil remove o limite do lote (BR-LOTE-008).
+		Capacidade *int `json:"capacidade"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		response.ErrorValidation(c, "Dados invalidos", err.Error())
@@ -114,11 +121,16 @@ func (h *LoteHandler) Update(c *gin.Context) {
 	lote.Nome = req.Nome
 	lote.Tipo = req.Tipo
 	lote.Descricao = req.Descricao
+	lote.Capacidade = req.Capacidade
 	if req.Ativo != nil {
 		lote.Ativo = *req.Ativo
 	}
 	lote.ID = id
 	if err := h.svc.Update(c.Request.Context(), lote); err != nil {
+		if errors.Is(err, service.ErrLoteCapacidadeInvalida) {
+			response.ErrorValidation(c, err.Error(), nil)
+			return
+		}
 		response.ErrorInternal(c, "Erro ao atualizar lote", err.Error())
 		return
 	}
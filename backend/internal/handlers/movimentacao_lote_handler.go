@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"strconv"
 	"time"
 
@@ -78,5 +79,146 @@ func (h *MovimentacaoLoteHandler) Movimentar(c *gin.Context) {
 		response.ErrorInternal(c, "Erro ao movimentar animal", err.Error())
 		return
 	}
-	response.SuccessCreated(c, m, "Animal movimentado com sucesso")
+	response.SuccessCreated(c, movimentacaoComAvisos{
+		MovimentacaoLote: m,
+		Avisos:           h.svc.AvisosCapacidade(c.Request.Context(), m.LoteDestinoID),
+	}, "Animal movimentado com sucesso")
+}
+
+// movimentacaoComAvisos resposta da movimentação individual com os avisos de capacidade (BR-LOTE-008).
+type movimentacaoComAvisos struct {
+	*models.MovimentacaoLote
+	Avisos []string `json:"avisos,omitempty"`
+}
+
+// resolveLote lê o lote de :id e valida o acesso à fazenda dele.
+func (h *MovimentacaoLoteHandler) resolveLote(c *gin.Context) (int64, bool) {
+	loteID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || loteID <= 0 {
+		response.ErrorBadRequest(c, "ID do lote invalido", nil)
+		return 0, false
+	}
+	fazendaID, err := h.svc.FazendaDoLote(c.Request.Context(), loteID)
+	if err != nil {
+		if errors.Is(err, service.ErrLoteNotFound) {
+			response.ErrorNotFound(c, "Lote nao encontrado")
+			return 0, false
+		}
+		response.ErrorInternal(c, "Erro ao buscar lote", err.Error())
+		return 0, false
+	}
+	if !ValidateFazendaAccess(c, h.fazendaSvc, fazendaID) {
+		return 0, false
+	}
+	return loteID, true
+}
+
+// MovimentarEmLote POST /api/v1/lotes/:id/movimentar — move vários animais para o lote :id (BR-LOTE-009).
+func (h *MovimentacaoLoteHandler) MovimentarEmLote(c *gin.Context) {
+	loteID, ok := h.resolveLote(c)
+	if !ok {
+		return
+	}
+	actorID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuario nao identificado")
+		return
+	}
+	var req struct {
+		AnimalIDs []int64 `json:"animal_ids"`
+		Filtro    *struct {
+			LoteOrigemID      *int64  `json:"lote_origem_id"`
+			Categoria         *string `json:"categoria"`
+			Sexo              *string `json:"sexo"`
+			StatusReprodutivo *string `json:"status_reprodutivo"`
+			StatusSaude       *string `json:"status_saude"`
+		} `json:"filtro"`
+		Data   *string `json:"data"`
+		Motivo *string `json:"motivo"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Dados invalidos", err.Error())
+		return
+	}
+	in := service.MovimentarEmLoteInput{
+		LoteDestinoID: loteID,
+		AnimalIDs:     req.AnimalIDs,
+		Motivo:        req.Motivo,
+		UsuarioID:     actorID,
+		Data:          time.Now(),
+	}
+	if req.Data != nil && *req.Data != "" {
+		data, err := time.Parse("2006-01-02", *req.Data)
+		if err != nil {
+			response.ErrorBadRequest(c, "data deve estar no formato YYYY-MM-DD", nil)
+			return
+		}
+		in.Data = data
+	}
+	if f := req.Filtro; f != nil {
+		in.Filtro = &service.MovimentacaoLoteFiltro{
+			LoteOrigemID:      f.LoteOrigemID,
+			Categoria:         f.Categoria,
+			Sexo:              f.Sexo,
+			StatusReprodutivo: f.StatusReprodutivo,
+			StatusSaude:       f.StatusSaude,
+		}
+	}
+	res, err := h.svc.MovimentarEmLote(c.Request.Context(), in)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrMovimentacaoLoteInvalida):
+			response.ErrorValidation(c, err.Error(), res.Falhas)
+		case errors.Is(err, service.ErrLoteNotFound):
+			response.ErrorNotFound(c, "Lote de destino nao encontrado")
+		case errors.Is(err, service.ErrLoteInativo),
+			errors.Is(err, service.ErrMovimentacaoLoteSemAnimais),
+			errors.Is(err, service.ErrMovimentacaoLoteLimite),
+			errors.Is(err, service.ErrMovimentacaoLoteDataFutura):
+			response.ErrorValidation(c, err.Error(), nil)
+		default:
+			response.ErrorInternal(c, "Erro ao movimentar animais", err.Error())
+		}
+		return
+	}
+	response.SuccessOK(c, res, "Animais movimentados com sucesso")
+}
+
+// Ocupacao GET /api/v1/lotes/:id/ocupacao?de=&ate= — série diária e eventos (BR-LOTE-010); padrão últimos 90 dias.
+func (h *MovimentacaoLoteHandler) Ocupacao(c *gin.Context) {
+	loteID, ok := h.resolveLote(c)
+	if !ok {
+		return
+	}
+	ate := time.Now()
+	if v := c.Query("ate"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			response.ErrorBadRequest(c, "ate deve estar no formato YYYY-MM-DD", nil)
+			return
+		}
+		ate = t
+	}
+	de := ate.AddDate(0, 0, -89)
+	if v := c.Query("de"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			response.ErrorBadRequest(c, "de deve estar no formato YYYY-MM-DD", nil)
+			return
+		}
+		de = t
+	}
+	hist, err := h.svc.HistoricoOcupacao(c.Request.Context(), loteID, de, ate)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrLoteOcupacaoPeriodoInvalido):
+			response.ErrorValidation(c, err.Error(), nil)
+		case errors.Is(err, service.ErrLoteNotFound):
+			response.ErrorNotFound(c, "Lote nao encontrado")
+		default:
+			response.ErrorInternal(c, "Erro ao carregar ocupacao do lote", err.Error())
+		}
+		return
+	}
+	response.SuccessOK(c, hist, "Ocupacao do lote carregada")
 }
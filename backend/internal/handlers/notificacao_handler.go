@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/ceialmilk/api/internal/response"
+	"github.com/ceialmilk/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type NotificacaoHandler struct {
+	svc *service.NotificacaoService
+}
+
+func NewNotificacaoHandler(svc *service.NotificacaoService) *NotificacaoHandler {
+	return &NotificacaoHandler{svc: svc}
+}
+
+// GetPreferencias GET /api/v1/me/notificacoes
+func (h *NotificacaoHandler) GetPreferencias(c *gin.Context) {
+	userID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não identificado")
+		return
+	}
+	prefs, err := h.svc.GetPreferencias(c.Request.Context(), userID)
+	if err != nil {
+		response.ErrorInternal(c, "Erro ao carregar preferências de notificação", err.Error())
+		return
+	}
+	response.SuccessOK(c, prefs, "")
+}
+
+type notificacaoCanalRequest struct {
+	Canal            string   `json:"canal" binding:"required"`
+	Ativo            bool     `json:"ativo"`
+	SeveridadeMinima string   `json:"severidade_minima"`
+	Tipos            []string `json:"tipos"`
+	Modo             string   `json:"modo"`
+}
+
+type notificacaoPreferenciasRequest struct {
+	Telefone               *string                   `json:"telefone"`
+	SilencioInicio         *string                   `json:"silencio_inicio"`
+	SilencioFim            *string                   `json:"silencio_fim"`
+	SilencioPermiteCritica *bool                     `json:"silencio_permite_critica"`
+	Canais                 []notificacaoCanalRequest `json:"canais"`
+}
+
+// PutPreferencias PUT /api/v1/me/notificacoes
+func (h *NotificacaoHandler) PutPreferencias(c *gin.Context) {
+	userID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não identificado")
+		return
+	}
+	var req notificacaoPreferenciasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Dados inválidos", err.Error())
+		return
+	}
+	in := service.NotificacaoPreferenciasInput{
+		Telefone:               req.Telefone,
+		SilencioInicio:         req.SilencioInicio,
+		SilencioFim:            req.SilencioFim,
+		SilencioPermiteCritica: req.SilencioPermiteCritica,
+	}
+	for _, cr := range req.Canais {
+		in.Canais = append(in.Canais, service.NotificacaoCanalInput{
+			Canal:            cr.Canal,
+			Ativo:            cr.Ativo,
+			SeveridadeMinima: cr.SeveridadeMinima,
+			Tipos:            cr.Tipos,
+			Modo:             cr.Modo,
+		})
+	}
+	prefs, err := h.svc.PutPreferencias(c.Request.Context(), userID, in)
+	if err != nil {
+		if isNotificacaoValidationErr(err) {
+			response.ErrorValidation(c, err.Error(), nil)
+			return
+		}
+		response.ErrorInternal(c, "Erro ao salvar preferências de notificação", err.Error())
+		return
+	}
+	response.SuccessOK(c, prefs, "Preferências de notificação atualizadas")
+}
+
+type notificacaoTesteRequest struct {
+	Canal string `json:"canal" binding:"required"`
+}
+
+// EnviarTeste POST /api/v1/me/notificacoes/teste
+func (h *NotificacaoHandler) EnviarTeste(c *gin.Context) {
+	userID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não identificado")
+		return
+	}
+	var req notificacaoTesteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Dados inválidos", err.Error())
+		return
+	}
+	err := h.svc.EnviarTeste(c.Request.Context(), userID, req.Canal)
+	switch {
+	case err == nil:
+		response.SuccessOK(c, gin.H{"canal": req.Canal, "enviado": true}, "Notificação de teste enviada")
+	case errors.Is(err, service.ErrNotificacaoCanalIndisponivel):
+		response.ErrorServiceUnavailable(c, err.Error(), nil)
+	case isNotificacaoValidationErr(err):
+		response.ErrorValidation(c, err.Error(), nil)
+	default:
+		response.ErrorInternal(c, "Erro ao enviar notificação de teste", err.Error())
+	}
+}
+
+func isNotificacaoValidationErr(err error) bool {
+	return errors.Is(err, service.ErrNotificacaoCanalInvalido) ||
+		errors.Is(err, service.ErrNotificacaoModoInvalido) ||
+		errors.Is(err, service.ErrNotificacaoTipoInvalido) ||
+		errors.Is(err, service.ErrNotificacaoHorarioInvalido) ||
+		errors.Is(err, service.ErrNotificacaoTelefoneInvalido) ||
+		errors.Is(err, service.ErrNotificacaoSemContato) ||
+		errors.Is(err, service.ErrAlertaSeveridadeInvalida)
+}
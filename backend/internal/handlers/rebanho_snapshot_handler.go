@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/ceialmilk/api/internal/response"
+	"github.com/ceialmilk/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type RebanhoSnapshotHandler struct {
+	svc        *service.RebanhoSnapshotService
+	fazendaSvc *service.FazendaService
+}
+
+func NewRebanhoSnapshotHandler(svc *service.RebanhoSnapshotService, fazendaSvc *service.FazendaService) *RebanhoSnapshotHandler {
+	return &RebanhoSnapshotHandler{svc: svc, fazendaSvc: fazendaSvc}
+}
+
+// Get GET /api/v1/fazendas/:id/rebanho/snapshot?data=YYYY-MM-DD (default: hoje)
+func (h *RebanhoSnapshotHandler) Get(c *gin.Context) {
+	fazendaID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || fazendaID <= 0 {
+		response.ErrorBadRequest(c, "ID da fazenda inválido", nil)
+		return
+	}
+	if !ValidateFazendaAccess(c, h.fazendaSvc, fazendaID) {
+		return
+	}
+
+	data := time.Now()
+	if q := c.Query("data"); q != "" {
+		data, err = time.ParseInLocation("2006-01-02", q, time.Local)
+		if err != nil {
+			response.ErrorValidation(c, "Data inválida (use YYYY-MM-DD)", nil)
+			return
+		}
+	}
+
+	snapshot, err := h.svc.Build(c.Request.Context(), fazendaID, data)
+	if err != nil {
+		if errors.Is(err, service.ErrRebanhoSnapshotDataFutura) {
+			response.ErrorValidation(c, "A data não pode ser futura", nil)
+			return
+		}
+		response.ErrorInternal(c, "Erro ao reconstruir rebanho na data", err.Error())
+		return
+	}
+	response.SuccessOK(c, snapshot, "Rebanho na data carregado com sucesso")
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/ceialmilk/api/internal/response"
+	"github.com/ceialmilk/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ResumoAlertasHandler struct {
+	svc *service.ResumoAlertasService
+}
+
+func NewResumoAlertasHandler(svc *service.ResumoAlertasService) *ResumoAlertasHandler {
+	return &ResumoAlertasHandler{svc: svc}
+}
+
+// GetConfig GET /api/v1/me/resumo-alertas
+func (h *ResumoAlertasHandler) GetConfig(c *gin.Context) {
+	userID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não identificado")
+		return
+	}
+	cfg, err := h.svc.GetConfig(c.Request.Context(), userID)
+	if err != nil {
+		response.ErrorInternal(c, "Erro ao carregar resumo de alertas", err.Error())
+		return
+	}
+	response.SuccessOK(c, cfg, "")
+}
+
+type resumoAlertasConfigRequest struct {
+	Ativo      bool     `json:"ativo"`
+	Frequencia string   `json:"frequencia"`
+	Hora       *int     `json:"hora" binding:"required"`
+	DiaSemana  *int     `json:"dia_semana"`
+	Canais     []string `json:"canais"`
+}
+
+// PutConfig PUT /api/v1/me/resumo-alertas
+func (h *ResumoAlertasHandler) PutConfig(c *gin.Context) {
+	userID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não identificado")
+		return
+	}
+	var req resumoAlertasConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Dados inválidos", err.Error())
+		return
+	}
+	cfg, err := h.svc.PutConfig(c.Request.Context(), userID, service.ResumoAlertasConfigInput{
+		Ativo:      req.Ativo,
+		Frequencia: req.Frequencia,
+		Hora:       *req.Hora,
+		DiaSemana:  req.DiaSemana,
+		Canais:     req.Canais,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrResumoAlertasFrequencia) ||
+			errors.Is(err, service.ErrResumoAlertasHora) ||
+			errors.Is(err, service.ErrResumoAlertasDiaSemana) ||
+			errors.Is(err, service.ErrResumoAlertasCanais) {
+			response.ErrorValidation(c, err.Error(), nil)
+			return
+		}
+		response.ErrorInternal(c, "Erro ao salvar resumo de alertas", err.Error())
+		return
+	}
+	response.SuccessOK(c, cfg, "Resumo de alertas atualizado")
+}
+
+// Previa GET /api/v1/me/resumo-alertas/previa
+func (h *ResumoAlertasHandler) Previa(c *gin.Context) {
+	userID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não identificado")
+		return
+	}
+	resumo, err := h.svc.Previa(c.Request.Context(), userID)
+	if err != nil {
+		response.ErrorInternal(c, "Erro ao montar resumo de alertas", err.Error())
+		return
+	}
+	response.SuccessOK(c, resumo, "")
+}
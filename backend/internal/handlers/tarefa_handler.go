@@ -0,0 +1,415 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ceialmilk/api/internal/response"
+	"github.com/ceialmilk/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type TarefaHandler struct {
+	svc        *service.TarefaService
+	fazendaSvc *service.FazendaService
+}
+
+func NewTarefaHandler(svc *service.TarefaService, fazendaSvc *service.FazendaService) *TarefaHandler {
+	return &TarefaHandler{svc: svc, fazendaSvc: fazendaSvc}
+}
+
+func parseTarefaID(c *gin.Context, param string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param(param), 10, 64)
+	if err != nil || id <= 0 {
+		response.ErrorBadRequest(c, param+" inválido", nil)
+		return 0, false
+	}
+	return id, true
+}
+
+// parseTarefaData YYYY-MM-DD opcional; nil quando vazio.
+func parseTarefaData(v *string) (*time.Time, bool) {
+	if v == nil || strings.TrimSpace(*v) == "" {
+		return nil, true
+	}
+	t, err := time.Parse("2006-01-02", strings.TrimSpace(*v))
+	if err != nil {
+		return nil, false
+	}
+	return &t, true
+}
+
+func (h *TarefaHandler) mapTarefaError(c *gin.Context, err error, internalMsg string) bool {
+	if err == nil {
+		return false
+	}
+	if RespondIfDomainWriteError(c, err) {
+		return true
+	}
+	switch {
+	case errors.Is(err, service.ErrTarefaNotFound):
+		response.ErrorNotFound(c, "Tarefa não encontrada")
+	case errors.Is(err, service.ErrTarefaChecklistItemNotFound):
+		response.ErrorNotFound(c, err.Error())
+	case errors.Is(err, service.ErrAlertaNotFound):
+		response.ErrorNotFound(c, "Alerta não encontrado")
+	case errors.Is(err, service.ErrTarefaForbidden):
+		response.ErrorForbidden(c, err.Error())
+	case errors.Is(err, service.ErrTarefaTituloInvalido),
+		errors.Is(err, service.ErrTarefaDataObrigatoria),
+		errors.Is(err, service.ErrTarefaRecorrenciaInvalida),
+		errors.Is(err, service.ErrTarefaChecklistInvalida),
+		errors.Is(err, service.ErrTarefaStatusInvalido),
+		errors.Is(err, service.ErrTarefaTransicaoInvalida),
+		errors.Is(err, service.ErrTarefaResponsavelInvalido),
+		errors.Is(err, service.ErrTarefaVinculoFazenda):
+		response.ErrorValidation(c, err.Error(), nil)
+	case errors.Is(err, service.ErrTarefaEncerrada),
+		errors.Is(err, service.ErrTarefaAlertaEncerrado),
+		errors.Is(err, service.ErrTarefaAlertaJaConvertido):
+		response.ErrorConflict(c, err.Error(), nil)
+	default:
+		response.ErrorInternal(c, internalMsg, err.Error())
+	}
+	return true
+}
+
+// List GET /api/v1/fazendas/:id/tarefas
+func (h *TarefaHandler) List(c *gin.Context) {
+	fazendaID, ok := parseTarefaID(c, "id")
+	if !ok {
+		return
+	}
+	if !ValidateFazendaAccessOrGestao(c, h.fazendaSvc, fazendaID) {
+		return
+	}
+	limit := parseQueryIntPositiveDef(c.Query("limit"), 50)
+	if limit > 200 {
+		limit = 200
+	}
+	inicioStr, fimStr := c.Query("inicio"), c.Query("fim")
+	inicio, ok1 := parseTarefaData(&inicioStr)
+	fim, ok2 := parseTarefaData(&fimStr)
+	if !ok1 || !ok2 {
+		response.ErrorValidation(c, "inicio e fim devem estar no formato YYYY-MM-DD", nil)
+		return
+	}
+	q := service.TarefaListQuery{
+		Status:  c.Query("status"),
+		Abertas: c.Query("abertas") == "true",
+		Inicio:  inicio,
+		Fim:     fim,
+		Limit:   limit,
+		Offset:  parseQueryIntNonNeg(c.DefaultQuery("offset", "0"), 0),
+	}
+	// responsavel=me (minhas tarefas) | nenhum (sem responsável) | <usuario_id>
+	switch responsavel := strings.TrimSpace(c.Query("responsavel")); responsavel {
+	case "":
+	case "me":
+		actorID, ok := GetActorUserID(c)
+		if !ok {
+			response.ErrorUnauthorized(c, "Usuário não autenticado")
+			return
+		}
+		q.ResponsavelID = &actorID
+	case "nenhum":
+		q.SemResponsavel = true
+	default:
+		id, err := strconv.ParseInt(responsavel, 10, 64)
+		if err != nil || id <= 0 {
+			response.ErrorValidation(c, "responsavel deve ser me, nenhum ou um id de utilizador", nil)
+			return
+		}
+		q.ResponsavelID = &id
+	}
+	list, total, err := h.svc.ListByFazenda(c.Request.Context(), fazendaID, q)
+	if h.mapTarefaError(c, err, "Erro ao listar tarefas") {
+		return
+	}
+	response.SuccessOK(c, gin.H{"tarefas": list, "total": total}, "Tarefas listadas")
+}
+
+// MinhasHoje GET /api/v1/fazendas/:id/tarefas/minhas-hoje?data=YYYY-MM-DD (BR-TAREFA-004)
+func (h *TarefaHandler) MinhasHoje(c *gin.Context) {
+	fazendaID, ok := parseTarefaID(c, "id")
+	if !ok {
+		return
+	}
+	if !ValidateFazendaAccessOrGestao(c, h.fazendaSvc, fazendaID) {
+		return
+	}
+	actorID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não autenticado")
+		return
+	}
+	dataStr := c.Query("data")
+	data, ok := parseTarefaData(&dataStr)
+	if !ok {
+		response.ErrorValidation(c, "data deve estar no formato YYYY-MM-DD", nil)
+		return
+	}
+	out, err := h.svc.MinhasHoje(c.Request.Context(), fazendaID, actorID, data)
+	if h.mapTarefaError(c, err, "Erro ao listar tarefas do dia") {
+		return
+	}
+	response.SuccessOK(c, out, "")
+}
+
+// GetByID GET /api/v1/fazendas/:id/tarefas/:tarefaId
+func (h *TarefaHandler) GetByID(c *gin.Context) {
+	fazendaID, ok := parseTarefaID(c, "id")
+	if !ok {
+		return
+	}
+	if !ValidateFazendaAccessOrGestao(c, h.fazendaSvc, fazendaID) {
+		return
+	}
+	id, ok := parseTarefaID(c, "tarefaId")
+	if !ok {
+		return
+	}
+	row, err := h.svc.GetByID(c.Request.Context(), fazendaID, id)
+	if h.mapTarefaError(c, err, "Erro ao buscar tarefa") {
+		return
+	}
+	response.SuccessOK(c, row, "Tarefa encontrada")
+}
+
+type tarefaRequest struct {
+	Titulo        string   `json:"titulo" binding:"required"`
+	Descricao     *string  `json:"descricao"`
+	AnimalID      *int64   `json:"animal_id"`
+	LoteID        *int64   `json:"lote_id"`
+	AreaID        *int64   `json:"area_id"`
+	ResponsavelID *int64   `json:"responsavel_id"`
+	DataPrevista  *string  `json:"data_prevista"`
+	Recorrencia   string   `json:"recorrencia"`
+	Checklist     []string `json:"checklist"`
+}
+
+func (h *TarefaHandler) bindTarefaInput(c *gin.Context) (service.TarefaInput, bool) {
+	var req tarefaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorBadRequest(c, "Corpo da requisição inválido", nil)
+		return service.TarefaInput{}, false
+	}
+	data, ok := parseTarefaData(req.DataPrevista)
+	if !ok {
+		response.ErrorBadRequest(c, "data_prevista deve estar no formato YYYY-MM-DD", nil)
+		return service.TarefaInput{}, false
+	}
+	actorID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não autenticado")
+		return service.TarefaInput{}, false
+	}
+	return service.TarefaInput{
+		Titulo:        req.Titulo,
+		Descricao:     req.Descricao,
+		AnimalID:      req.AnimalID,
+		LoteID:        req.LoteID,
+		AreaID:        req.AreaID,
+		ResponsavelID: req.ResponsavelID,
+		DataPrevista:  data,
+		Recorrencia:   req.Recorrencia,
+		Checklist:     req.Checklist,
+		ActorUserID:   actorID,
+		Perfil:        getActorPerfil(c),
+	}, true
+}
+
+// Create POST /api/v1/fazendas/:id/tarefas
+func (h *TarefaHandler) Create(c *gin.Context) {
+	fazendaID, ok := parseTarefaID(c, "id")
+	if !ok {
+		return
+	}
+	if !ValidateFazendaAccessOrGestao(c, h.fazendaSvc, fazendaID) {
+		return
+	}
+	in, ok := h.bindTarefaInput(c)
+	if !ok {
+		return
+	}
+	row, err := h.svc.Create(c.Request.Context(), fazendaID, in)
+	if h.mapTarefaError(c, err, "Erro ao criar tarefa") {
+		return
+	}
+	response.SuccessCreated(c, row, "Tarefa criada")
+}
+
+// Update PUT /api/v1/fazendas/:id/tarefas/:tarefaId (checklist ausente mantém os itens)
+func (h *TarefaHandler) Update(c *gin.Context) {
+	fazendaID, ok := parseTarefaID(c, "id")
+	if !ok {
+		return
+	}
+	if !ValidateFazendaAccessOrGestao(c, h.fazendaSvc, fazendaID) {
+		return
+	}
+	id, ok := parseTarefaID(c, "tarefaId")
+	if !ok {
+		return
+	}
+	in, ok := h.bindTarefaInput(c)
+	if !ok {
+		return
+	}
+	row, err := h.svc.Update(c.Request.Context(), fazendaID, id, in)
+	if h.mapTarefaError(c, err, "Erro ao atualizar tarefa") {
+		return
+	}
+	response.SuccessOK(c, row, "Tarefa atualizada")
+}
+
+type updateTarefaStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+}
+
+// UpdateStatus PATCH /api/v1/fazendas/:id/tarefas/:tarefaId/status
+func (h *TarefaHandler) UpdateStatus(c *gin.Context) {
+	fazendaID, ok := parseTarefaID(c, "id")
+	if !ok {
+		return
+	}
+	if !ValidateFazendaAccessOrGestao(c, h.fazendaSvc, fazendaID) {
+		return
+	}
+	id, ok := parseTarefaID(c, "tarefaId")
+	if !ok {
+		return
+	}
+	var req updateTarefaStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorBadRequest(c, "Corpo da requisição inválido", nil)
+		return
+	}
+	actorID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não autenticado")
+		return
+	}
+	row, err := h.svc.UpdateStatus(c.Request.Context(), fazendaID, id, service.UpdateTarefaStatusInput{
+		Status:      req.Status,
+		ActorUserID: actorID,
+		Perfil:      getActorPerfil(c),
+	})
+	if h.mapTarefaError(c, err, "Erro ao atualizar status da tarefa") {
+		return
+	}
+	response.SuccessOK(c, row, "Status da tarefa atualizado")
+}
+
+type marcarChecklistRequest struct {
+	Feito *bool `json:"feito" binding:"required"`
+}
+
+// MarcarChecklist PATCH /api/v1/fazendas/:id/tarefas/:tarefaId/checklist/:itemId
+func (h *TarefaHandler) MarcarChecklist(c *gin.Context) {
+	fazendaID, ok := parseTarefaID(c, "id")
+	if !ok {
+		return
+	}
+	if !ValidateFazendaAccessOrGestao(c, h.fazendaSvc, fazendaID) {
+		return
+	}
+	id, ok := parseTarefaID(c, "tarefaId")
+	if !ok {
+		return
+	}
+	itemID, ok := parseTarefaID(c, "itemId")
+	if !ok {
+		return
+	}
+	var req marcarChecklistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorBadRequest(c, "Corpo da requisição inválido", nil)
+		return
+	}
+	actorID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não autenticado")
+		return
+	}
+	row, err := h.svc.MarcarChecklist(c.Request.Context(), fazendaID, id, itemID, service.MarcarChecklistInput{
+		Feito:       *req.Feito,
+		ActorUserID: actorID,
+		Perfil:      getActorPerfil(c),
+	})
+	if h.mapTarefaError(c, err, "Erro ao atualizar checklist") {
+		return
+	}
+	response.SuccessOK(c, row, "Checklist atualizada")
+}
+
+// Delete DELETE /api/v1/fazendas/:id/tarefas/:tarefaId
+func (h *TarefaHandler) Delete(c *gin.Context) {
+	fazendaID, ok := parseTarefaID(c, "id")
+	if !ok {
+		return
+	}
+	if !ValidateFazendaAccessOrGestao(c, h.fazendaSvc, fazendaID) {
+		return
+	}
+	id, ok := parseTarefaID(c, "tarefaId")
+	if !ok {
+		return
+	}
+	err := h.svc.Delete(c.Request.Context(), fazendaID, id, getActorPerfil(c))
+	if h.mapTarefaError(c, err, "Erro ao excluir tarefa") {
+		return
+	}
+	response.SuccessOK(c, nil, "Tarefa excluída")
+}
+
+type converterAlertaRequest struct {
+	ResponsavelID *int64   `json:"responsavel_id"`
+	DataPrevista  *string  `json:"data_prevista"`
+	Checklist     []string `json:"checklist"`
+}
+
+// ConverterAlerta POST /api/v1/fazendas/:id/alertas/:alertaId/tarefa (BR-TAREFA-003)
+func (h *TarefaHandler) ConverterAlerta(c *gin.Context) {
+	fazendaID, ok := parseTarefaID(c, "id")
+	if !ok {
+		return
+	}
+	if !ValidateFazendaAccessOrGestao(c, h.fazendaSvc, fazendaID) {
+		return
+	}
+	alertaID, ok := parseAlertaID(c)
+	if !ok {
+		return
+	}
+	var req converterAlertaRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.ErrorBadRequest(c, "Corpo da requisição inválido", nil)
+			return
+		}
+	}
+	data, ok := parseTarefaData(req.DataPrevista)
+	if !ok {
+		response.ErrorBadRequest(c, "data_prevista deve estar no formato YYYY-MM-DD", nil)
+		return
+	}
+	actorID, ok := GetActorUserID(c)
+	if !ok {
+		response.ErrorUnauthorized(c, "Usuário não autenticado")
+		return
+	}
+	row, err := h.svc.ConverterAlerta(c.Request.Context(), fazendaID, alertaID, service.ConverterAlertaInput{
+		ResponsavelID: req.ResponsavelID,
+		DataPrevista:  data,
+		Checklist:     req.Checklist,
+		ActorUserID:   actorID,
+		Perfil:        getActorPerfil(c),
+	})
+	if h.mapTarefaError(c, err, "Erro ao converter alerta em tarefa") {
+		return
+	}
+	response.SuccessCreated(c, row, "Alerta convertido em tarefa")
+}
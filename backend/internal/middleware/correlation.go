@@ -25,9 +25,9 @@ func CorrelationIDMiddleware() gin.HandlerFunc {
 		// Adicionar ao header de resposta para o cliente poder rastrear
 		c.Header(requestctx.CorrelationIDHeader, correlationID)
 
-		// Adicionar ao contexto do request para uso em logs
+		// Adicionar ao contexto do request para uso em logs e auditoria nos services
 		c.Request = c.Request.WithContext(
-			c.Request.Context(),
+			requestctx.WithCorrelationID(c.Request.Context(), correlationID),
 		)
 
 		// Criar logger com correlation ID
@@ -10,15 +10,29 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// IntegrationAuditMiddleware regista chamadas M2M após o handler.
+// IntegrationAuditMiddleware regista chamadas M2M após o handler. Deve envolver a autenticação
+// (registado antes dela) para que rejeições — inclusive de clientes não identificados — fiquem registadas com o motivo.
 func IntegrationAuditMiddleware(integracaoSvc *service.IntegracaoService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
 		c.Next()
+		motivo, rejeitadoID, rejeitado := auth.GetIntegrationRejeicao(c)
 		clientID, ok := auth.GetIntegrationClientID(c)
 		if !ok || clientID <= 0 {
+			clientID = rejeitadoID
+		}
+		if clientID <= 0 && !rejeitado {
 			return
 		}
+		var clientePtr *int64
+		if clientID > 0 {
+			clientePtr = &clientID
+		}
+		var motivoPtr *string
+		if rejeitado {
+			motivoPtr = &motivo
+		}
+		ip := c.ClientIP()
 		var corr *string
 		if v, ok := c.Get(requestctx.CorrelationIDKey); ok {
 			if s, ok := v.(string); ok && s != "" {
@@ -33,6 +47,9 @@ func IntegrationAuditMiddleware(integracaoSvc *service.IntegracaoService) gin.Ha
 		if erro == "" && c.Writer.Status() >= 400 {
 			erro = c.Errors.String()
 		}
+		if erro == "" && rejeitado {
+			erro = "rejeitado: " + motivo
+		}
 		var erroPtr *string
 		if erro != "" {
 			if len(erro) > 500 {
@@ -41,7 +58,7 @@ func IntegrationAuditMiddleware(integracaoSvc *service.IntegracaoService) gin.Ha
 			erroPtr = &erro
 		}
 		ch := &models.IntegracaoChamada{
-			ClienteID:      clientID,
+			ClienteID:      clientePtr,
 			Method:         c.Request.Method,
 			Path:           c.Request.URL.Path,
 			StatusCode:     c.Writer.Status(),
@@ -49,6 +66,8 @@ func IntegrationAuditMiddleware(integracaoSvc *service.IntegracaoService) gin.Ha
 			IdempotencyKey: idem,
 			DuracaoMs:      int(time.Since(start).Milliseconds()),
 			ErroResumo:     erroPtr,
+			MotivoRejeicao: motivoPtr,
+			IP:             &ip,
 		}
 		_ = integracaoSvc.LogChamada(c.Request.Context(), ch)
 	}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/ceialmilk/api/internal/auth"
+	"github.com/ceialmilk/api/internal/models"
 	"github.com/ceialmilk/api/internal/response"
 	"github.com/gin-gonic/gin"
 )
@@ -19,6 +20,7 @@ func IntegrationRateLimit(requestsPerHour int) gin.HandlerFunc {
 			return
 		}
 		if !limiter.GetLimiter(clientID).Allow() {
+			auth.SetIntegrationRejeicao(c, clientID, models.IntegracaoRejeicaoRateLimit)
 			response.ErrorTooManyRequests(c, fmt.Sprintf("Limite de requisicoes excedido. Maximo %d por hora.", requestsPerHour))
 			c.Abort()
 			return
@@ -12,6 +12,7 @@ const (
 	AlertaTipoVacinaVencida        = "VACINA_VENCIDA"
 	AlertaTipoVacinaReforcoVencido = "VACINA_REFORCO_VENCIDA"
 	AlertaTipoHormonioLactacaoPendente = "HORMONIO_LACTACAO_PENDENTE"
+	AlertaTipoCustom               = "CUSTOM"
 	AlertaTipoManual               = "MANUAL"
 )
 
@@ -42,6 +43,14 @@ type Alerta struct {
 	ResolvidoPor *int64     `json:"resolvido_por,omitempty" db:"resolvido_por"`
 	ResolvidoEm  *time.Time `json:"resolvido_em,omitempty" db:"resolvido_em"`
 	CreatedBy    int64      `json:"created_by" db:"created_by"`
+	// RegraCustomID regra personalizada que originou o alerta (tipo CUSTOM — BR-ALERTA-020).
+	RegraCustomID *int64    `json:"regra_custom_id,omitempty" db:"regra_custom_id"`
+	// Atribuição e prazo (BR-ALERTA-023). EscalonarEm = próximo escalonamento enquanto ABERTO.
+	ResponsavelID      *int64     `json:"responsavel_id,omitempty" db:"responsavel_id"`
+	AtribuidoEm        *time.Time `json:"atribuido_em,omitempty" db:"atribuido_em"`
+	PrazoEm            *time.Time `json:"prazo_em,omitempty" db:"prazo_em"`
+	EscalonamentoNivel int        `json:"escalonamento_nivel" db:"escalonamento_nivel"`
+	EscalonarEm        *time.Time `json:"escalonar_em,omitempty" db:"escalonar_em"`
 	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
 }
@@ -51,6 +60,9 @@ type AlertaWithNames struct {
 	AnimalIdentificacao *string `json:"animal_identificacao,omitempty" db:"animal_identificacao"`
 	CreatedByNome       *string `json:"created_by_nome,omitempty" db:"created_by_nome"`
 	ResolvidoPorNome    *string `json:"resolvido_por_nome,omitempty" db:"resolvido_por_nome"`
+	ResponsavelNome     *string `json:"responsavel_nome,omitempty" db:"responsavel_nome"`
+	// Atrasado: ABERTO/EM_ANDAMENTO com prazo_em ultrapassado (calculado na consulta).
+	Atrasado bool `json:"atrasado" db:"atrasado"`
 }
 
 func ValidAlertaTipos() []string {
@@ -64,6 +76,7 @@ func ValidAlertaTipos() []string {
 		AlertaTipoVacinaVencida,
 		AlertaTipoVacinaReforcoVencido,
 		AlertaTipoHormonioLactacaoPendente,
+		AlertaTipoCustom,
 		AlertaTipoManual,
 	}
 }
@@ -191,6 +204,8 @@ func LabelTipoAlerta(tipo string) string {
 		return "Reforço de vacina vencido"
 	case AlertaTipoHormonioLactacaoPendente:
 		return "Hormônio lactação pendente"
+	case AlertaTipoCustom:
+		return "Regra personalizada"
 	case AlertaTipoManual:
 		return "Manual"
 	default:
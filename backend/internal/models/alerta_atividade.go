@@ -0,0 +1,67 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Tipos de entrada no histórico de um alerta (BR-ALERTA-023).
+const (
+	AlertaAtividadeComentario    = "COMENTARIO"
+	AlertaAtividadeStatus        = "STATUS"
+	AlertaAtividadeAtribuicao    = "ATRIBUICAO"
+	AlertaAtividadeEscalonamento = "ESCALONAMENTO"
+	AlertaAtividadeTarefa        = "TAREFA" // conversão em tarefa e conclusão dela (BR-TAREFA-003)
+)
+
+// AlertaComentarioMaxLen tamanho máximo de um comentário (caracteres).
+const AlertaComentarioMaxLen = 2000
+
+// AlertaEscalonamentoMaxNivel último nível de escalonamento; depois dele o alerta não volta a escalonar.
+const AlertaEscalonamentoMaxNivel = 2
+
+// AlertaAtividade entrada do histórico: comentário de utilizador ou evento (status, atribuição,
+// escalonamento). UsuarioID nil = evento do sistema.
+type AlertaAtividade struct {
+	ID          int64           `json:"id" db:"id"`
+	AlertaID    int64           `json:"alerta_id" db:"alerta_id"`
+	FazendaID   int64           `json:"fazenda_id" db:"fazenda_id"`
+	UsuarioID   *int64          `json:"usuario_id,omitempty" db:"usuario_id"`
+	UsuarioNome *string         `json:"usuario_nome,omitempty" db:"usuario_nome"`
+	Tipo        string          `json:"tipo" db:"tipo"`
+	Texto       string          `json:"texto" db:"texto"`
+	Dados       json.RawMessage `json:"dados,omitempty" db:"dados"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+}
+
+// AlertaSLA prazo de resposta a partir da criação, por severidade.
+func AlertaSLA(severidade string) time.Duration {
+	switch severidade {
+	case AlertaSeveridadeCritica:
+		return 4 * time.Hour
+	case AlertaSeveridadeAlta:
+		return 24 * time.Hour
+	case AlertaSeveridadeMedia:
+		return 72 * time.Hour
+	default:
+		return 7 * 24 * time.Hour
+	}
+}
+
+// AlertaEscalonamentoPerfis perfis notificados em cada nível: 1 = gestão da fazenda, 2 = proprietário.
+func AlertaEscalonamentoPerfis(nivel int) []string {
+	switch nivel {
+	case 1:
+		return []string{PerfilGerente, PerfilGestao}
+	case 2:
+		return []string{PerfilProprietario}
+	default:
+		return nil
+	}
+}
+
+// PodeAtribuirAlerta atribui o alerta a qualquer utilizador da fazenda; os demais perfis operacionais
+// só assumem (ou largam) o alerta para si.
+func PodeAtribuirAlerta(perfil string) bool {
+	return PodeGerenciarFolgas(perfil)
+}
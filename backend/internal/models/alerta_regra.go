@@ -0,0 +1,145 @@
+package models
+
+import "time"
+
+// AlertaRegraConfig é a personalização de uma regra de geração automática numa fazenda (BR-ALERTA-019).
+// Campos nil usam o padrão da regra; PushPerfis vazio (não nil) desliga o push da regra.
+type AlertaRegraConfig struct {
+	FazendaID  int64     `json:"fazenda_id" db:"fazenda_id"`
+	Tipo       string    `json:"tipo" db:"tipo"`
+	Ativo      bool      `json:"ativo" db:"ativo"`
+	Dias       *int      `json:"dias,omitempty" db:"dias"`
+	Severidade *string   `json:"severidade,omitempty" db:"severidade"`
+	PushPerfis []string  `json:"push_perfis,omitempty" db:"push_perfis"`
+	UpdatedBy  *int64    `json:"updated_by,omitempty" db:"updated_by"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// AlertaRegraPadrao descreve uma regra automática e o parâmetro de dias que aceita.
+// DiasPadrao nil = regra sem parâmetro de dias.
+type AlertaRegraPadrao struct {
+	Tipo          string
+	DiasPadrao    *int
+	DiasMin       int
+	DiasMax       int
+	DiasDescricao string
+}
+
+// AlertaRegra é a regra efetiva de uma fazenda (padrão + personalização).
+type AlertaRegra struct {
+	Tipo             string     `json:"tipo"`
+	Label            string     `json:"label"`
+	Ativo            bool       `json:"ativo"`
+	Dias             *int       `json:"dias,omitempty"`
+	DiasPadrao       *int       `json:"dias_padrao,omitempty"`
+	DiasMin          *int       `json:"dias_min,omitempty"`
+	DiasMax          *int       `json:"dias_max,omitempty"`
+	DiasDescricao    string     `json:"dias_descricao,omitempty"`
+	Severidade       string     `json:"severidade"`
+	SeveridadePadrao string     `json:"severidade_padrao"`
+	PushPerfis       []string   `json:"push_perfis"`
+	Personalizada    bool       `json:"personalizada"`
+	UpdatedAt        *time.Time `json:"updated_at,omitempty"`
+}
+
+func diasRegra(d int) *int { return &d }
+
+var alertaRegrasPadrao = []AlertaRegraPadrao{
+	{Tipo: AlertaTipoTratamentoVencido, DiasPadrao: diasRegra(14), DiasMin: 1, DiasMax: 365,
+		DiasDescricao: "Tratamento sem data de fim iniciado há mais de N dias"},
+	{Tipo: AlertaTipoPartoPrevisto, DiasPadrao: diasRegra(14), DiasMin: 1, DiasMax: 90,
+		DiasDescricao: "Antecedência (dias) em relação à data prevista de parto"},
+	{Tipo: AlertaTipoRestricaoLeiteAtiva, DiasPadrao: diasRegra(7), DiasMin: 1, DiasMax: 90,
+		DiasDescricao: "Restrição aguardando laboratório há N dias ou mais"},
+	{Tipo: AlertaTipoNaoConformidade},
+	{Tipo: AlertaTipoGestacaoSemSecagem, DiasPadrao: diasRegra(250), DiasMin: 150, DiasMax: 300,
+		DiasDescricao: "Gestação confirmada há N dias sem secagem registada"},
+	{Tipo: AlertaTipoCioDetectado},
+	{Tipo: AlertaTipoVacinaVencida, DiasPadrao: diasRegra(7), DiasMin: 0, DiasMax: 180,
+		DiasDescricao: "Vacina prevista em atraso há mais de N dias"},
+	{Tipo: AlertaTipoVacinaReforcoVencido, DiasPadrao: diasRegra(7), DiasMin: 0, DiasMax: 180,
+		DiasDescricao: "Reforço vencido há mais de N dias sem nova dose"},
+	{Tipo: AlertaTipoHormonioLactacaoPendente},
+}
+
+// AlertaRegrasPadrao devolve as regras automáticas na ordem de execução da geração diária.
+func AlertaRegrasPadrao() []AlertaRegraPadrao {
+	out := make([]AlertaRegraPadrao, len(alertaRegrasPadrao))
+	copy(out, alertaRegrasPadrao)
+	return out
+}
+
+// AlertaRegraPadraoPorTipo devolve a regra automática do tipo (false para MANUAL ou tipo desconhecido).
+func AlertaRegraPadraoPorTipo(tipo string) (AlertaRegraPadrao, bool) {
+	for _, r := range alertaRegrasPadrao {
+		if r.Tipo == tipo {
+			return r, true
+		}
+	}
+	return AlertaRegraPadrao{}, false
+}
+
+// AlertaPushPerfisPadrao perfis operacionais que recebem push por omissão (BR-ALERTA-012).
+func AlertaPushPerfisPadrao() []string {
+	return []string{PerfilAdmin, PerfilDeveloper, PerfilFuncionario, PerfilGerente, PerfilGestao, PerfilProprietario}
+}
+
+// IsValidAlertaPushPerfil indica se o perfil pode ser destinatário de push de alerta.
+func IsValidAlertaPushPerfil(perfil string) bool {
+	for _, p := range AlertaPushPerfisPadrao() {
+		if p == perfil {
+			return true
+		}
+	}
+	return false
+}
+
+// PodeConfigurarRegrasAlerta perfis que alteram a configuração de regras automáticas da fazenda.
+func PodeConfigurarRegrasAlerta(perfil string) bool {
+	return PodeGerenciarFolgas(perfil)
+}
+
+// ResolverAlertaRegra combina o padrão da regra com a personalização da fazenda (cfg nil = padrão).
+func ResolverAlertaRegra(p AlertaRegraPadrao, cfg *AlertaRegraConfig) AlertaRegra {
+	severidade, _ := SeveridadePadraoPorTipo(p.Tipo)
+	r := AlertaRegra{
+		Tipo:             p.Tipo,
+		Label:            LabelTipoAlerta(p.Tipo),
+		Ativo:            true,
+		Dias:             p.DiasPadrao,
+		DiasPadrao:       p.DiasPadrao,
+		DiasDescricao:    p.DiasDescricao,
+		Severidade:       severidade,
+		SeveridadePadrao: severidade,
+		PushPerfis:       AlertaPushPerfisPadrao(),
+	}
+	if p.DiasPadrao != nil {
+		r.DiasMin = diasRegra(p.DiasMin)
+		r.DiasMax = diasRegra(p.DiasMax)
+	}
+	if cfg == nil {
+		return r
+	}
+	r.Personalizada = true
+	r.Ativo = cfg.Ativo
+	if cfg.Dias != nil && p.DiasPadrao != nil {
+		r.Dias = diasRegra(*cfg.Dias)
+	}
+	if cfg.Severidade != nil {
+		r.Severidade = *cfg.Severidade
+	}
+	if cfg.PushPerfis != nil {
+		r.PushPerfis = append([]string{}, cfg.PushPerfis...)
+	}
+	updatedAt := cfg.UpdatedAt
+	r.UpdatedAt = &updatedAt
+	return r
+}
+
+// DiasEfetivos devolve os dias efetivos da regra (0 quando a regra não tem parâmetro de dias).
+func (r AlertaRegra) DiasEfetivos() int {
+	if r.Dias == nil {
+		return 0
+	}
+	return *r.Dias
+}
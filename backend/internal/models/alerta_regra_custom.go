@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// AlertaRegraCustom é uma regra de alerta definida pela fazenda (BR-ALERTA-020): a expressão é avaliada
+// sobre cada animal ativo na geração diária e gera alertas do tipo CUSTOM.
+// PushPerfis nil = perfis operacionais (BR-ALERTA-012); vazio desliga o push.
+type AlertaRegraCustom struct {
+	ID         int64     `json:"id" db:"id"`
+	FazendaID  int64     `json:"fazenda_id" db:"fazenda_id"`
+	Nome       string    `json:"nome" db:"nome"`
+	Descricao  *string   `json:"descricao,omitempty" db:"descricao"`
+	Expressao  string    `json:"expressao" db:"expressao"`
+	Severidade string    `json:"severidade" db:"severidade"`
+	Ativo      bool      `json:"ativo" db:"ativo"`
+	PushPerfis []string  `json:"push_perfis" db:"push_perfis"`
+	CreatedBy  *int64    `json:"created_by,omitempty" db:"created_by"`
+	UpdatedBy  *int64    `json:"updated_by,omitempty" db:"updated_by"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+const (
+	AlertaRegraCustomNomeMaxLen      = 120
+	AlertaRegraCustomExpressaoMaxLen = 1000
+	AlertaRegraCustomMaxPorFazenda   = 50
+)
+
+// AlertaRegraEfetiva adapta a regra personalizada ao formato usado pela geração de alertas.
+func (r *AlertaRegraCustom) AlertaRegraEfetiva() AlertaRegra {
+	perfis := AlertaPushPerfisPadrao()
+	if r.PushPerfis != nil {
+		perfis = append([]string{}, r.PushPerfis...)
+	}
+	return AlertaRegra{
+		Tipo:             AlertaTipoCustom,
+		Label:            r.Nome,
+		Ativo:            r.Ativo,
+		Severidade:       r.Severidade,
+		SeveridadePadrao: AlertaSeveridadeMedia,
+		PushPerfis:       perfis,
+		Personalizada:    true,
+	}
+}
+
+// AlertaExpressaoVariavel descreve uma variável disponível nas expressões das regras personalizadas.
+type AlertaExpressaoVariavel struct {
+	Nome      string `json:"nome"`
+	Tipo      string `json:"tipo"` // NUMERO, TEXTO ou BOOLEANO
+	Descricao string `json:"descricao"`
+}
+
+// AlertaRegraCustomAnimal é um animal que satisfaz a expressão (pré-visualização e geração).
+type AlertaRegraCustomAnimal struct {
+	AnimalID      int64  `json:"animal_id"`
+	Identificacao string `json:"identificacao"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// AnimalPesagem registo de peso do animal; a mais recente alimenta a transição BEZERRA → NOVILHA por peso (BR-LOTE-005).
+type AnimalPesagem struct {
+	ID         int64     `json:"id" db:"id"`
+	AnimalID   int64     `json:"animal_id" db:"animal_id"`
+	FazendaID  int64     `json:"fazenda_id" db:"fazenda_id"`
+	Data       time.Time `json:"data" db:"data"`
+	PesoKg     float64   `json:"peso_kg" db:"peso_kg"`
+	Observacao *string   `json:"observacao,omitempty" db:"observacao"`
+	CreatedBy  *int64    `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
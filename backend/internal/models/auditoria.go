@@ -0,0 +1,98 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Ações da trilha de auditoria.
+const (
+	AuditoriaAcaoCreate  = "CREATE"
+	AuditoriaAcaoUpdate  = "UPDATE"
+	AuditoriaAcaoDelete  = "DELETE"
+	AuditoriaAcaoRestore = "RESTORE"
+)
+
+// Entidades auditadas (auditoria_eventos.entidade).
+const (
+	AuditoriaEntidadeAnimal           = "ANIMAL"
+	AuditoriaEntidadeCobertura        = "COBERTURA"
+	AuditoriaEntidadeParto            = "PARTO"
+	AuditoriaEntidadeCria             = "CRIA"
+	AuditoriaEntidadeProducaoLeite    = "PRODUCAO_LEITE"
+	AuditoriaEntidadeCio              = "CIO"
+	AuditoriaEntidadeToque            = "DIAGNOSTICO_GESTACAO"
+	AuditoriaEntidadeGestacao         = "GESTACAO"
+	AuditoriaEntidadeLactacao         = "LACTACAO"
+	AuditoriaEntidadeSecagem          = "SECAGEM"
+	AuditoriaEntidadeAnimalSaude      = "ANIMAL_SAUDE"
+	AuditoriaEntidadeAnimalVacina     = "ANIMAL_VACINA"
+	AuditoriaEntidadeAnimalPesagem    = "ANIMAL_PESAGEM"
+	AuditoriaEntidadeHormonioLactacao = "HORMONIO_LACTACAO"
+	AuditoriaEntidadeRestricaoLeite   = "RESTRICAO_LEITE"
+	AuditoriaEntidadeLote             = "LOTE"
+	AuditoriaEntidadeMovimentacaoLote = "MOVIMENTACAO_LOTE"
+	AuditoriaEntidadeFazenda          = "FAZENDA"
+	AuditoriaEntidadeUsuario          = "USUARIO"
+	AuditoriaEntidadeAlerta           = "ALERTA"
+	AuditoriaEntidadeConvite          = "CONVITE"
+)
+
+// PerfilSistema identifica mutações sem utilizador autenticado (cron, jobs) na auditoria.
+const PerfilSistema = "SISTEMA"
+
+// AuditoriaRegistro é o que um service informa ao registar uma mutação.
+// FazendaID/AnimalID 0 = não aplicável (a fazenda é resolvida pelo animal quando omitida).
+// Antes é nil em CREATE; Depois é nil em DELETE.
+type AuditoriaRegistro struct {
+	Acao       string
+	Entidade   string
+	EntidadeID int64
+	FazendaID  int64
+	AnimalID   int64
+	Antes      interface{}
+	Depois     interface{}
+}
+
+// AuditoriaDiff campos alterados: valores anteriores e novos (apenas chaves que mudaram em UPDATE).
+type AuditoriaDiff struct {
+	Antes  map[string]json.RawMessage `json:"antes,omitempty"`
+	Depois map[string]json.RawMessage `json:"depois,omitempty"`
+}
+
+// AuditoriaEvento linha de auditoria_eventos.
+type AuditoriaEvento struct {
+	ID            int64           `json:"id" db:"id"`
+	UsuarioID     *int64          `json:"usuario_id,omitempty" db:"usuario_id"`
+	UsuarioNome   *string         `json:"usuario_nome,omitempty" db:"-"`
+	Perfil        string          `json:"perfil" db:"perfil"`
+	FazendaID     *int64          `json:"fazenda_id,omitempty" db:"fazenda_id"`
+	AnimalID      *int64          `json:"animal_id,omitempty" db:"animal_id"`
+	Entidade      string          `json:"entidade" db:"entidade"`
+	EntidadeID    int64           `json:"entidade_id" db:"entidade_id"`
+	Acao          string          `json:"acao" db:"acao"`
+	Diff          json.RawMessage `json:"diff" db:"diff"`
+	CorrelationID *string         `json:"correlation_id,omitempty" db:"correlation_id"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+}
+
+// AuditoriaFiltro filtros da consulta; exatamente um entre AnimalID, UsuarioID e FazendaID é obrigatório no service.
+type AuditoriaFiltro struct {
+	AnimalID  int64
+	UsuarioID int64
+	FazendaID int64
+	Entidade  string
+	Acao      string
+	De        *time.Time
+	Ate       *time.Time
+	Limit     int
+	Offset    int
+}
+
+func IsValidAuditoriaAcao(acao string) bool {
+	switch acao {
+	case AuditoriaAcaoCreate, AuditoriaAcaoUpdate, AuditoriaAcaoDelete, AuditoriaAcaoRestore:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Escopos da assinatura iCal da escala (BR-FOLGAS-011).
+const (
+	CalendarioEscopoPessoal = "PESSOAL" // folgas e ausências do próprio usuário
+	CalendarioEscopoFazenda = "FAZENDA" // escala completa da fazenda (gestão)
+)
+
+func IsValidCalendarioEscopo(v string) bool {
+	return v == CalendarioEscopoPessoal || v == CalendarioEscopoFazenda
+}
+
+// CalendarioFeed assinatura .ics do usuário; o token em claro só é devolvido na criação.
+type CalendarioFeed struct {
+	ID           int64      `json:"id"`
+	UsuarioID    int64      `json:"usuario_id"`
+	FazendaID    int64      `json:"fazenda_id"`
+	FazendaNome  string     `json:"fazenda_nome"`
+	Escopo       string     `json:"escopo"`
+	TokenPrefixo string     `json:"token_prefixo"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UltimoUsoEm  *time.Time `json:"ultimo_uso_em,omitempty"`
+}
@@ -0,0 +1,83 @@
+package models
+
+import "time"
+
+// CicloVidaConfig limites do motor de ciclo de vida por fazenda (BR-LOTE-005). NovilhaPesoKg nil = só idade.
+type CicloVidaConfig struct {
+	FazendaID         int64      `json:"fazenda_id" db:"fazenda_id"`
+	NovilhaIdadeMeses int        `json:"novilha_idade_meses" db:"novilha_idade_meses"`
+	NovilhaPesoKg     *float64   `json:"novilha_peso_kg" db:"novilha_peso_kg"`
+	MachoIdadeMeses   int        `json:"macho_idade_meses" db:"macho_idade_meses"`
+	MachoCategoria    string     `json:"macho_categoria" db:"macho_categoria"`
+	PrePartoDias      int        `json:"pre_parto_dias" db:"pre_parto_dias"`
+	Personalizada     bool       `json:"personalizada"`
+	UpdatedBy         *int64     `json:"updated_by,omitempty" db:"updated_by"`
+	UpdatedAt         *time.Time `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// CicloVidaConfigPadrao valores usados enquanto a fazenda não personaliza.
+func CicloVidaConfigPadrao(fazendaID int64) *CicloVidaConfig {
+	return &CicloVidaConfig{
+		FazendaID:         fazendaID,
+		NovilhaIdadeMeses: 12,
+		MachoIdadeMeses:   12,
+		MachoCategoria:    CategoriaBoi,
+		PrePartoDias:      21,
+	}
+}
+
+// AnimalCicloVida estado do animal no rebanho lido pelo motor (categoria, lote, última pesagem, gestação ativa).
+type AnimalCicloVida struct {
+	ID                int64
+	FazendaID         int64
+	Identificacao     string
+	Sexo              *string
+	Categoria         *string
+	DataNascimento    *time.Time
+	LoteID            *int64
+	LoteTipo          *string
+	PesoKg            *float64
+	TemParto          bool
+	GestacaoID        *int64
+	DataPrevistaParto *time.Time
+}
+
+// Motivos de proposta de movimentação gerada pelo motor.
+const (
+	PropostaLoteMotivoPreParto = "PRE_PARTO"
+	PropostaLoteMotivoRecria   = "RECRIA"
+	PropostaLoteMotivoEngorda  = "ENGORDA"
+)
+
+// Estados de uma proposta de movimentação.
+const (
+	PropostaLoteStatusPendente  = "PENDENTE"
+	PropostaLoteStatusAprovada  = "APROVADA"
+	PropostaLoteStatusRejeitada = "REJEITADA"
+	PropostaLoteStatusObsoleta  = "OBSOLETA" // a situação deixou de se aplicar antes da decisão
+)
+
+// MovimentacaoLoteProposta sugestão de mudança de lote aguardando decisão da gestão (BR-LOTE-006).
+type MovimentacaoLoteProposta struct {
+	ID                  int64      `json:"id" db:"id"`
+	FazendaID           int64      `json:"fazenda_id" db:"fazenda_id"`
+	AnimalID            int64      `json:"animal_id" db:"animal_id"`
+	AnimalIdentificacao string     `json:"animal_identificacao" db:"animal_identificacao"`
+	LoteOrigemID        *int64     `json:"lote_origem_id,omitempty" db:"lote_origem_id"`
+	LoteOrigemNome      *string    `json:"lote_origem_nome,omitempty" db:"lote_origem_nome"`
+	LoteDestinoID       int64      `json:"lote_destino_id" db:"lote_destino_id"`
+	LoteDestinoNome     string     `json:"lote_destino_nome" db:"lote_destino_nome"`
+	Motivo              string     `json:"motivo" db:"motivo"`
+	Referencia          string     `json:"referencia" db:"referencia"`
+	Descricao           string     `json:"descricao" db:"descricao"`
+	Status              string     `json:"status" db:"status"`
+	MovimentacaoID      *int64     `json:"movimentacao_id,omitempty" db:"movimentacao_id"`
+	DecididoPor         *int64     `json:"decidido_por,omitempty" db:"decidido_por"`
+	DecididoEm          *time.Time `json:"decidido_em,omitempty" db:"decidido_em"`
+	CreatedAt           time.Time  `json:"created_at" db:"created_at"`
+}
+
+// PodeGerirCicloVida perfis que configuram o motor e decidem propostas de movimentação (área de lotes).
+func PodeGerirCicloVida(perfil string) bool {
+	return PodeGerenciarFolgas(perfil)
+}
@@ -0,0 +1,116 @@
+package models
+
+import "time"
+
+// Situação derivada de um convite (BR-ACESSO-010); não é coluna: vem de usado_em, revogado_em e expira_em.
+const (
+	ConviteStatusPendente = "PENDENTE"
+	ConviteStatusUsado    = "USADO"
+	ConviteStatusRevogado = "REVOGADO"
+	ConviteStatusExpirado = "EXPIRADO"
+)
+
+const (
+	ConviteValidadeDiasPadrao = 7
+	ConviteValidadeDiasMax    = 30
+	ConviteObservacaoMaxLen   = 200
+)
+
+// Convite código de uso único que vincula quem o resgata a uma fazenda com o perfil e o papel indicados.
+// O código em claro só é devolvido na criação.
+type Convite struct {
+	ID            int64      `json:"id"`
+	FazendaID     int64      `json:"fazenda_id"`
+	FazendaNome   string     `json:"fazenda_nome"`
+	Perfil        string     `json:"perfil"`
+	Papel         string     `json:"papel"`
+	CodigoPrefixo string     `json:"codigo_prefixo"`
+	Observacao    *string    `json:"observacao,omitempty"`
+	ExpiraEm      time.Time  `json:"expira_em"`
+	CriadoPor     *int64     `json:"criado_por,omitempty"`
+	CriadoPorNome *string    `json:"criado_por_nome,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UsadoPor      *int64     `json:"usado_por,omitempty"`
+	UsadoPorNome  *string    `json:"usado_por_nome,omitempty"`
+	UsadoEm       *time.Time `json:"usado_em,omitempty"`
+	RevogadoPor   *int64     `json:"revogado_por,omitempty"`
+	RevogadoEm    *time.Time `json:"revogado_em,omitempty"`
+	Status        string     `json:"status"`
+}
+
+// StatusEm calcula a situação do convite no instante informado (uso e revogação têm precedência).
+func (c *Convite) StatusEm(agora time.Time) string {
+	switch {
+	case c.UsadoEm != nil:
+		return ConviteStatusUsado
+	case c.RevogadoEm != nil:
+		return ConviteStatusRevogado
+	case !agora.Before(c.ExpiraEm):
+		return ConviteStatusExpirado
+	default:
+		return ConviteStatusPendente
+	}
+}
+
+// ConvitePreview dados exibidos a quem tem o código, antes de resgatar (registro ou login).
+type ConvitePreview struct {
+	FazendaNome string    `json:"fazenda_nome"`
+	Perfil      string    `json:"perfil"`
+	Papel       string    `json:"papel"`
+	ExpiraEm    time.Time `json:"expira_em"`
+}
+
+// ConviteResgate resultado do resgate: vínculo criado e perfil global após o convite.
+type ConviteResgate struct {
+	FazendaID   int64  `json:"fazenda_id"`
+	FazendaNome string `json:"fazenda_nome"`
+	Papel       string `json:"papel"`
+	Perfil      string `json:"perfil"`
+	// PerfilAlterado indica que o perfil USER (pendente) foi elevado ao perfil do convite.
+	PerfilAlterado bool `json:"perfil_alterado"`
+}
+
+// PodeCriarConvite perfis que emitem convites: ADMIN/DEVELOPER para qualquer fazenda e
+// PROPRIETARIO para fazendas em que é titular.
+func PodeCriarConvite(perfil string) bool {
+	switch perfil {
+	case PerfilAdmin, PerfilDeveloper, PerfilProprietario:
+		return true
+	default:
+		return false
+	}
+}
+
+// PerfisConvidaveis perfis que o emissor pode oferecer. O proprietário só convida a equipe
+// operacional da própria fazenda; perfis globais (GESTAO) e titulares ficam com ADMIN/DEVELOPER.
+func PerfisConvidaveis(emissor string) []string {
+	switch emissor {
+	case PerfilAdmin, PerfilDeveloper:
+		return []string{PerfilFuncionario, PerfilGerente, PerfilGestao, PerfilProprietario}
+	case PerfilProprietario:
+		return []string{PerfilFuncionario, PerfilGerente}
+	default:
+		return nil
+	}
+}
+
+// PodeConvidarComPapel o papel TITULAR só é oferecido por ADMIN/DEVELOPER.
+func PodeConvidarComPapel(emissor, papel string) bool {
+	switch papel {
+	case PapelVinculoOperacional:
+		return PodeCriarConvite(emissor)
+	case PapelVinculoTitular:
+		return emissor == PerfilAdmin || emissor == PerfilDeveloper
+	default:
+		return false
+	}
+}
+
+// PerfilAposConvite o convite só eleva quem ainda é USER (pendente); um perfil operacional já
+// atribuído é global e vale para as outras fazendas, então não é trocado pelo convite.
+func PerfilAposConvite(atual, alvo string) string {
+	if atual == PerfilUser {
+		return alvo
+	}
+	return atual
+}
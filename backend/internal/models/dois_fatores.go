@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// DoisFatoresCodigosRecuperacao códigos de uso único gerados na ativação e em cada regeneração (BR-ACESSO-027).
+const DoisFatoresCodigosRecuperacao = 10
+
+// DoisFatoresDesafioMaxTentativas códigos errados aceitos por desafio do login antes de exigir a senha de novo.
+const DoisFatoresDesafioMaxTentativas = 5
+
+// DoisFatoresEmissor nome que o app autenticador mostra ao lado da conta.
+const DoisFatoresEmissor = "CeialMilk"
+
+// DoisFatores inscrição TOTP do usuário. AtivadoEm nil = inscrição iniciada e ainda não confirmada
+// com um código. O segredo cifrado e o último passo usado nunca saem da API.
+type DoisFatores struct {
+	UsuarioID      int64      `json:"usuario_id"`
+	SegredoCifrado string     `json:"-"`
+	AtivadoEm      *time.Time `json:"ativado_em,omitempty"`
+	UltimoPasso    int64      `json:"-"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// DoisFatoresStatus estado do 2FA da conta em GET /api/v1/me/2fa. Nivel é o AAL da sessão atual.
+type DoisFatoresStatus struct {
+	Ativo                       bool       `json:"ativo"`
+	AtivadoEm                   *time.Time `json:"ativado_em,omitempty"`
+	Obrigatorio                 bool       `json:"obrigatorio"`
+	CodigosRecuperacaoRestantes int        `json:"codigos_recuperacao_restantes"`
+	Nivel                       int        `json:"nivel"`
+}
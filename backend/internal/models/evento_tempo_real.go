@@ -0,0 +1,19 @@
+package models
+
+// Entidades publicadas no stream de eventos em tempo real (GET /api/v1/me/eventos). A ação reutiliza
+// o vocabulário da auditoria (CREATE, UPDATE, DELETE, RESTORE).
+const (
+	EventoEntidadeAlerta         = "alerta"
+	EventoEntidadeProducao       = "producao"
+	EventoEntidadeRestricaoLeite = "restricao_leite"
+)
+
+// EventoTempoReal aviso leve de mudança numa fazenda: o cliente recarrega o que lhe interessa.
+// ID = 0 quando a mudança abrange vários registos (ex.: resolução automática de alertas do animal).
+type EventoTempoReal struct {
+	Entidade  string `json:"entidade"`
+	Acao      string `json:"acao"`
+	FazendaID int64  `json:"fazenda_id"`
+	ID        int64  `json:"id,omitempty"`
+	AnimalID  int64  `json:"animal_id,omitempty"`
+}
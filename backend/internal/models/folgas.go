@@ -2,14 +2,73 @@ package models
 
 import "time"
 
-// FolgasEscalaConfig define âncora e os 3 usuários do rodízio 5x1 (slots 0,1,2).
+// FolgasEscalaConfig rodízio da fazenda: uma ou mais equipes com padrão próprio.
 type FolgasEscalaConfig struct {
-	FazendaID      int64     `json:"fazenda_id" db:"fazenda_id"`
-	DataAnchor     time.Time `json:"data_anchor" db:"data_anchor"`
-	UsuarioSlot0   int64     `json:"usuario_slot_0" db:"usuario_slot_0"`
-	UsuarioSlot1   int64     `json:"usuario_slot_1" db:"usuario_slot_1"`
-	UsuarioSlot2   int64     `json:"usuario_slot_2" db:"usuario_slot_2"`
-	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+	FazendaID int64          `json:"fazenda_id"`
+	Equipes   []FolgasEquipe `json:"equipes"`
+}
+
+// FolgasEquipe padrão de rodízio: ciclo de CicloDias a partir de DataAnchor; cada participante folga
+// FolgasPorCiclo dias consecutivos a partir do seu Deslocamento no ciclo. O 5x1 clássico é ciclo 6,
+// 1 folga, três participantes com deslocamentos 0, 1 e 2.
+type FolgasEquipe struct {
+	ID             int64                      `json:"id" db:"id"`
+	FazendaID      int64                      `json:"fazenda_id" db:"fazenda_id"`
+	Nome           string                    `json:"nome" db:"nome"`
+	DataAnchor     time.Time                 `json:"data_anchor" db:"data_anchor"`
+	CicloDias      int                        `json:"ciclo_dias" db:"ciclo_dias"`
+	FolgasPorCiclo int                        `json:"folgas_por_ciclo" db:"folgas_por_ciclo"`
+	Participantes  []FolgasEquipeParticipante `json:"participantes"`
+	UpdatedAt      time.Time                 `json:"updated_at" db:"updated_at"`
+}
+
+// FolgasEquipeParticipante membro da equipe, na ordem do rodízio.
+type FolgasEquipeParticipante struct {
+	UsuarioID    int64  `json:"usuario_id" db:"usuario_id"`
+	UsuarioNome  string `json:"usuario_nome,omitempty" db:"-"`
+	Posicao      int    `json:"posicao" db:"posicao"`
+	Deslocamento int    `json:"deslocamento" db:"deslocamento"`
+}
+
+// UsuariosDeFolga participantes que o padrão põe de folga no dia d (ordem do rodízio).
+func (e *FolgasEquipe) UsuariosDeFolga(d time.Time) []int64 {
+	if e.CicloDias <= 0 {
+		return nil
+	}
+	a := time.Date(e.DataAnchor.Year(), e.DataAnchor.Month(), e.DataAnchor.Day(), 0, 0, 0, 0, time.UTC)
+	b := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+	dia := int(b.Sub(a).Hours() / 24)
+	var out []int64
+	for _, p := range e.Participantes {
+		rel := ((dia-p.Deslocamento)%e.CicloDias + e.CicloDias) % e.CicloDias
+		if rel < e.FolgasPorCiclo {
+			out = append(out, p.UsuarioID)
+		}
+	}
+	return out
+}
+
+// TemParticipante indica se o usuário pertence à equipe.
+func (e *FolgasEquipe) TemParticipante(usuarioID int64) bool {
+	for _, p := range e.Participantes {
+		if p.UsuarioID == usuarioID {
+			return true
+		}
+	}
+	return false
+}
+
+// EquipeDoUsuario equipe do usuário na configuração (nil se não participa do rodízio).
+func (c *FolgasEscalaConfig) EquipeDoUsuario(usuarioID int64) *FolgasEquipe {
+	if c == nil {
+		return nil
+	}
+	for i := range c.Equipes {
+		if c.Equipes[i].TemParticipante(usuarioID) {
+			return &c.Equipes[i]
+		}
+	}
+	return nil
 }
 
 const (
@@ -32,33 +91,47 @@ type EscalaFolga struct {
 	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
 	UsuarioNome  string     `json:"usuario_nome,omitempty" db:"-"`
-	// Rodízio 5x1 previsto (UsuarioParaDia); preenchido na listagem enriquecida, não vem do banco.
+	// Rodízio previsto para a equipe do usuário na data; preenchido na listagem enriquecida, não vem do banco.
 	RodizioEsperadoTemFolga    bool    `json:"rodizio_esperado_tem_folga" db:"-"`
 	RodizioEsperadoUsuarioID   *int64  `json:"rodizio_esperado_usuario_id,omitempty" db:"-"`
 	RodizioEsperadoUsuarioNome *string `json:"rodizio_esperado_usuario_nome,omitempty" db:"-"`
 }
 
-// FolgasRodizioDia previsto pelo ciclo 5x1 para uma data (independente de haver linha na escala).
+// FolgasRodizioDia previsto pelo rodízio para uma data (independente de haver linha na escala).
+// UsuarioID/UsuarioNome só vêm preenchidos quando há exatamente uma folga prevista no dia.
 type FolgasRodizioDia struct {
 	Data         time.Time `json:"data"`
 	TemFolga     bool      `json:"tem_folga"`
 	UsuarioID    *int64    `json:"usuario_id,omitempty"`
 	UsuarioNome  *string   `json:"usuario_nome,omitempty"`
+	Previstos    []FolgasRodizioPrevisto `json:"previstos"`
+}
+
+// FolgasRodizioPrevisto folga prevista de um participante numa data.
+type FolgasRodizioPrevisto struct {
+	UsuarioID   int64  `json:"usuario_id"`
+	UsuarioNome string `json:"usuario_nome,omitempty"`
+	EquipeID    int64  `json:"equipe_id"`
+	EquipeNome  string `json:"equipe_nome"`
 }
 
 // FolgasEscalaListResponse escala + mapa diário do rodízio (para dias sem registros).
 type FolgasEscalaListResponse struct {
 	Linhas         []EscalaFolga       `json:"linhas"`
 	RodizioPorDia  []FolgasRodizioDia  `json:"rodizio_por_dia"`
+	Ausencias      []FolgaAusencia     `json:"ausencias"`
 }
 
 // FolgaEquidadeResumo compara folgas registradas vs teóricas do rodízio no intervalo.
 type FolgaEquidadeResumo struct {
 	UsuarioID          int64  `json:"usuario_id"`
 	UsuarioNome        string `json:"usuario_nome"`
+	EquipeID           int64  `json:"equipe_id"`
+	EquipeNome         string `json:"equipe_nome"`
 	FolgasRegistradas  int    `json:"folgas_registradas"`
 	FolgasTeoricasAuto int    `json:"folgas_teoricas_auto"`
 	Delta              int    `json:"delta"`
+	DiasAusente        int    `json:"dias_ausente"`
 }
 
 // FolgaJustificativa trilha de justificativa do funcionário.
@@ -92,9 +165,126 @@ type FolgaAlteracao struct {
 	CreatedAt  time.Time      `json:"created_at" db:"created_at"`
 }
 
-// FolgaAlertaDia dia com possível conflito (mais de um de folga sem exceção/justificativa completa).
+// Tipos de FolgaAlertaDia.
+const (
+	FolgaAlertaConflito      = "CONFLITO"
+	FolgaAlertaTrocaPendente = "TROCA_PENDENTE"
+	FolgaAlertaDesfalque     = "DESFALQUE"
+)
+
+// FolgaAlertaDia dia com possível conflito (mais folgas na equipe do que o rodízio prevê, sem
+// exceção/justificativa completa), troca pendente ou equipe desfalcada por ausências.
+// EquipeID nil agrupa quem não participa de nenhuma equipe.
 type FolgaAlertaDia struct {
 	Data            time.Time `json:"data"`
+	Tipo            string    `json:"tipo"`
 	QuantidadeFolga int       `json:"quantidade_folga"`
 	MotivoAlerta    string    `json:"motivo_alerta"`
+	EquipeID        *int64    `json:"equipe_id,omitempty"`
+	EquipeNome      *string   `json:"equipe_nome,omitempty"`
+	// TrocaID preenchido quando o alerta é uma troca de folga pendente (BR-FOLGAS-009).
+	TrocaID *int64 `json:"troca_id,omitempty"`
+	// Ausentes participantes da equipe em férias, atestado ou licença no dia (BR-FOLGAS-010).
+	Ausentes int `json:"ausentes,omitempty"`
+}
+
+// Status da troca de folga (BR-FOLGAS-009).
+const (
+	FolgaTrocaPendenteColega = "PENDENTE_COLEGA"
+	FolgaTrocaPendenteGestao = "PENDENTE_GESTAO"
+	FolgaTrocaAprovada       = "APROVADA"
+	FolgaTrocaRecusada       = "RECUSADA"
+	FolgaTrocaRejeitada      = "REJEITADA"
+	FolgaTrocaCancelada      = "CANCELADA"
+)
+
+// FolgaTroca pedido de troca: o solicitante cede a folga de DataSolicitante e assume a do colega em DataColega.
+type FolgaTroca struct {
+	ID                int64      `json:"id" db:"id"`
+	FazendaID         int64      `json:"fazenda_id" db:"fazenda_id"`
+	SolicitanteID     int64      `json:"solicitante_id" db:"solicitante_id"`
+	SolicitanteNome   string     `json:"solicitante_nome,omitempty" db:"-"`
+	ColegaID          int64      `json:"colega_id" db:"colega_id"`
+	ColegaNome        string     `json:"colega_nome,omitempty" db:"-"`
+	DataSolicitante   time.Time  `json:"data_solicitante" db:"data_solicitante"`
+	DataColega        time.Time  `json:"data_colega" db:"data_colega"`
+	Motivo            string     `json:"motivo" db:"motivo"`
+	Status            string     `json:"status" db:"status"`
+	RespondidoEm      *time.Time `json:"respondido_em,omitempty" db:"respondido_em"`
+	DecididoPor       *int64     `json:"decidido_por,omitempty" db:"decidido_por"`
+	DecididoEm        *time.Time `json:"decidido_em,omitempty" db:"decidido_em"`
+	ObservacaoDecisao *string    `json:"observacao_decisao,omitempty" db:"observacao_decisao"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Pendente indica se a troca ainda aguarda colega ou gestão.
+func (t *FolgaTroca) Pendente() bool {
+	return t.Status == FolgaTrocaPendenteColega || t.Status == FolgaTrocaPendenteGestao
+}
+
+// Tipos de ausência (BR-FOLGAS-010).
+const (
+	FolgaAusenciaFerias               = "FERIAS"
+	FolgaAusenciaAtestado             = "ATESTADO"
+	FolgaAusenciaLicencaNaoRemunerada = "LICENCA_NAO_REMUNERADA"
+	// FolgaAusenciaOculta tipo exibido a colegas sem gestão (não expõe atestado).
+	FolgaAusenciaOculta = "AUSENCIA"
+)
+
+// FolgasFeriasDiasAnoPadrao direito anual de férias quando a gestão não definiu outro valor.
+const FolgasFeriasDiasAnoPadrao = 30
+
+// IsValidFolgaAusenciaTipo indica se o tipo pode ser registrado.
+func IsValidFolgaAusenciaTipo(tipo string) bool {
+	switch tipo {
+	case FolgaAusenciaFerias, FolgaAusenciaAtestado, FolgaAusenciaLicencaNaoRemunerada:
+		return true
+	}
+	return false
+}
+
+// FolgaAusencia férias, atestado ou licença de um funcionário, com datas inclusivas.
+type FolgaAusencia struct {
+	ID          int64     `json:"id" db:"id"`
+	FazendaID   int64     `json:"fazenda_id" db:"fazenda_id"`
+	UsuarioID   int64     `json:"usuario_id" db:"usuario_id"`
+	UsuarioNome string    `json:"usuario_nome,omitempty" db:"-"`
+	Tipo        string    `json:"tipo" db:"tipo"`
+	DataInicio  time.Time `json:"data_inicio" db:"data_inicio"`
+	DataFim     time.Time `json:"data_fim" db:"data_fim"`
+	AnexoRef    *string   `json:"anexo_ref,omitempty" db:"anexo_ref"`
+	Observacao  *string   `json:"observacao,omitempty" db:"observacao"`
+	CreatedBy   *int64    `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Cobre indica se a data está no período da ausência.
+func (a *FolgaAusencia) Cobre(d time.Time) bool {
+	return !d.Before(a.DataInicio) && !d.After(a.DataFim)
+}
+
+// DiasEntre dias da ausência dentro de [inicio, fim].
+func (a *FolgaAusencia) DiasEntre(inicio, fim time.Time) int {
+	if a.DataInicio.After(inicio) {
+		inicio = a.DataInicio
+	}
+	if a.DataFim.Before(fim) {
+		fim = a.DataFim
+	}
+	if fim.Before(inicio) {
+		return 0
+	}
+	return int(fim.Sub(inicio).Hours()/24) + 1
+}
+
+// FolgaFeriasSaldo direito e uso de férias do funcionário no ano.
+type FolgaFeriasSaldo struct {
+	UsuarioID   int64  `json:"usuario_id"`
+	UsuarioNome string `json:"usuario_nome"`
+	Ano         int    `json:"ano"`
+	DiasDireito int    `json:"dias_direito"`
+	DiasUsados  int    `json:"dias_usados"`
+	Saldo       int    `json:"saldo"`
 }
@@ -43,11 +43,49 @@ type IntegracaoCliente struct {
 	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
 	FazendaIDs        []int64    `json:"fazenda_ids,omitempty" db:"-"`
 	Scopes            []string   `json:"scopes,omitempty" db:"-"`
+	// OAuth2 client credentials (opcional; coexistem com a API key).
+	OAuthClientID      *string    `json:"oauth_client_id,omitempty" db:"oauth_client_id"`
+	OAuthSecretHash    string     `json:"-" db:"oauth_secret_hash"`
+	TokensValidosDesde *time.Time `json:"tokens_validos_desde,omitempty" db:"tokens_validos_desde"`
+	// Expiração agendada e período de graça da chave anterior após rotação (BR-INTEG-016).
+	ChaveExpiraEm         *time.Time `json:"chave_expira_em,omitempty" db:"chave_expira_em"`
+	KeyPrefixAnterior     *string    `json:"key_prefix_anterior,omitempty" db:"key_prefix_anterior"`
+	KeyHashAnterior       string     `json:"-" db:"key_hash_anterior"`
+	ChaveAnteriorExpiraEm *time.Time `json:"chave_anterior_expira_em,omitempty" db:"chave_anterior_expira_em"`
+	// IPAllowlist CIDRs de origem permitidos; vazio = qualquer origem.
+	IPAllowlist       []string   `json:"ip_allowlist" db:"ip_allowlist"`
+	AvisoExpiracaoRef *time.Time `json:"-" db:"aviso_expiracao_ref"`
+}
+
+// Motivos de rejeição registados em integracao_chamadas.motivo_rejeicao.
+const (
+	IntegracaoRejeicaoTokenAusente          = "TOKEN_AUSENTE"
+	IntegracaoRejeicaoFormatoInvalido       = "FORMATO_INVALIDO"
+	IntegracaoRejeicaoChaveDesconhecida     = "CHAVE_DESCONHECIDA"
+	IntegracaoRejeicaoChaveInvalida         = "CHAVE_INVALIDA"
+	IntegracaoRejeicaoChaveExpirada         = "CHAVE_EXPIRADA"
+	IntegracaoRejeicaoChaveAnteriorExpirada = "CHAVE_ANTERIOR_EXPIRADA"
+	IntegracaoRejeicaoClienteInativo        = "CLIENTE_INATIVO"
+	IntegracaoRejeicaoTokenInvalido         = "TOKEN_INVALIDO"
+	IntegracaoRejeicaoTokenRevogado         = "TOKEN_REVOGADO"
+	IntegracaoRejeicaoCredenciaisInvalidas  = "CREDENCIAIS_INVALIDAS"
+	IntegracaoRejeicaoIPNaoPermitido        = "IP_NAO_PERMITIDO"
+	IntegracaoRejeicaoScopeInsuficiente     = "SCOPE_INSUFICIENTE"
+	IntegracaoRejeicaoRateLimit             = "RATE_LIMIT"
+	IntegracaoRejeicaoErroInterno           = "ERRO_INTERNO"
+)
+
+// IntegracaoTokenResposta corpo de POST /api/v1/integracoes/oauth/token (RFC 6749 §5.1).
+type IntegracaoTokenResposta struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	Scope       string `json:"scope"`
 }
 
 type IntegracaoChamada struct {
 	ID             int64     `json:"id" db:"id"`
-	ClienteID      int64     `json:"cliente_id" db:"cliente_id"`
+	ClienteID      *int64    `json:"cliente_id" db:"cliente_id"` // nil em rejeições sem cliente identificado
 	Method         string    `json:"method" db:"method"`
 	Path           string    `json:"path" db:"path"`
 	StatusCode     int       `json:"status_code" db:"status_code"`
@@ -55,6 +93,8 @@ type IntegracaoChamada struct {
 	IdempotencyKey *string   `json:"idempotency_key,omitempty" db:"idempotency_key"`
 	DuracaoMs      int       `json:"duracao_ms" db:"duracao_ms"`
 	ErroResumo     *string   `json:"erro_resumo,omitempty" db:"erro_resumo"`
+	MotivoRejeicao *string   `json:"motivo_rejeicao,omitempty" db:"motivo_rejeicao"`
+	IP             *string   `json:"ip,omitempty" db:"ip"`
 	CreatedAt      time.Time `json:"created_at" db:"created_at"`
 }
 
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Origem de uma execução do agendador (BR-JOBS-001).
+const (
+	JobOrigemAgendada   = "AGENDADA"
+	JobOrigemRecuperada = "RECUPERADA" // slot perdido (réplica parada na hora) executado depois
+	JobOrigemManual     = "MANUAL"
+)
+
+// Estado de uma execução.
+const (
+	JobStatusEmExecucao = "EM_EXECUCAO"
+	JobStatusSucesso    = "SUCESSO"
+	JobStatusErro       = "ERRO"
+)
+
+// JobExecucao entrada do histórico do agendador. AgendadoPara nil = disparo manual.
+type JobExecucao struct {
+	ID           int64      `json:"id" db:"id"`
+	Job          string     `json:"job" db:"job"`
+	AgendadoPara *time.Time `json:"agendado_para,omitempty" db:"agendado_para"`
+	Origem       string     `json:"origem" db:"origem"`
+	Status       string     `json:"status" db:"status"`
+	Instancia    string     `json:"instancia" db:"instancia"`
+	DisparadoPor *int64     `json:"disparado_por,omitempty" db:"disparado_por"`
+	IniciadoEm   time.Time  `json:"iniciado_em" db:"iniciado_em"`
+	// UltimoSinalEm sinal de vida renovado pela réplica enquanto a execução corre.
+	UltimoSinalEm *time.Time      `json:"ultimo_sinal_em,omitempty" db:"ultimo_sinal_em"`
+	FinalizadoEm  *time.Time      `json:"finalizado_em,omitempty" db:"finalizado_em"`
+	Resultado     json.RawMessage `json:"resultado,omitempty" db:"resultado"`
+	Erro          *string         `json:"erro,omitempty" db:"erro"`
+}
+
+// JobExecucaoFiltro filtros do histórico (GET /api/v1/admin/jobs/execucoes).
+type JobExecucaoFiltro struct {
+	Job    string
+	Status string
+	Limit  int
+	Offset int
+}
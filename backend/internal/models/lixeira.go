@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// LixeiraItem é um registo excluído logicamente, restaurável até ExpiraEm.
+// Entidade usa os mesmos códigos da auditoria (PARTO, CIO, COBERTURA, PRODUCAO_LEITE).
+type LixeiraItem struct {
+	Entidade        string    `json:"entidade"`
+	ID              int64     `json:"id"`
+	AnimalID        int64     `json:"animal_id"`
+	Identificacao   string    `json:"identificacao"`
+	FazendaID       int64     `json:"fazenda_id"`
+	DataEvento      time.Time `json:"data_evento"`
+	ExcluidoEm      time.Time `json:"excluido_em"`
+	ExcluidoPor     *int64    `json:"excluido_por,omitempty"`
+	ExcluidoPorNome *string   `json:"excluido_por_nome,omitempty"`
+	ExpiraEm        time.Time `json:"expira_em"`
+}
+
+// ValidLixeiraEntidades retorna as entidades com exclusão lógica.
+func ValidLixeiraEntidades() []string {
+	return []string{AuditoriaEntidadeParto, AuditoriaEntidadeCio, AuditoriaEntidadeCobertura, AuditoriaEntidadeProducaoLeite}
+}
+
+func IsValidLixeiraEntidade(entidade string) bool {
+	for _, e := range ValidLixeiraEntidades() {
+		if e == entidade {
+			return true
+		}
+	}
+	return false
+}
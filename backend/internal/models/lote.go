@@ -9,6 +9,9 @@ type Lote struct {
 	Tipo       *string   `json:"tipo,omitempty" db:"tipo"`
 	Descricao  *string   `json:"descricao,omitempty" db:"descricao"`
 	Ativo      bool      `json:"ativo" db:"ativo"`
+	// Capacidade máxima de animais (nil = sem limite); Ocupacao = animais no rebanho hoje no lote (BR-LOTE-008).
+	Capacidade *int      `json:"capacidade,omitempty" db:"capacidade"`
+	Ocupacao   int       `json:"ocupacao" db:"ocupacao"`
 	CreatedAt  time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
 }
@@ -23,6 +26,11 @@ const (
 	LoteTipoEngorda     = "ENGORDA"
 )
 
+// AcimaCapacidade indica lote com capacidade definida e mais animais do que ela.
+func (l *Lote) AcimaCapacidade() bool {
+	return l.Capacidade != nil && l.Ocupacao > *l.Capacidade
+}
+
 func ValidTiposLote() []string {
 	return []string{LoteTipoLactacao, LoteTipoSecas, LoteTipoMaternidade, LoteTipoPreParto, LoteTipoBezerros, LoteTipoRecria, LoteTipoEngorda}
 }
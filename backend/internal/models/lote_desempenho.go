@@ -0,0 +1,70 @@
+package models
+
+// LoteProducaoDia produção do lote num dia; o lote de cada ordenha é o do animal no momento da produção.
+type LoteProducaoDia struct {
+	Data              string  `json:"data"`
+	Litros            float64 `json:"litros"`
+	AnimaisOrdenhados int     `json:"animais_ordenhados"`
+	MediaPorAnimal    float64 `json:"media_por_animal"`
+}
+
+// LoteProducaoResumo totais de produção de um lote num período.
+type LoteProducaoResumo struct {
+	LitrosTotal       float64 `json:"litros_total"`
+	DiasComProducao   int     `json:"dias_com_producao"`
+	MediaDiariaLitros float64 `json:"media_diaria_litros"`
+	// MediaPorAnimalDia litros por vaca ordenhada por dia (soma dos litros / soma das vacas ordenhadas em cada dia).
+	MediaPorAnimalDia float64 `json:"media_por_animal_dia"`
+}
+
+// LoteIndicadores estado atual dos animais no lote (no rebanho).
+type LoteIndicadores struct {
+	Animais    int `json:"animais"`
+	EmLactacao int `json:"em_lactacao"`
+	// DELMedio média de dias em lactação das vacas com lactação em andamento (nil sem lactações).
+	DELMedio             *float64       `json:"del_medio,omitempty"`
+	CasosSaudeAbertos    int            `json:"casos_saude_abertos"`
+	AnimaisComCasoAberto int            `json:"animais_com_caso_aberto"`
+	Prenhes              int            `json:"prenhes"`
+	PorStatusReprodutivo map[string]int `json:"por_status_reprodutivo"`
+}
+
+// LoteDesempenhoComparacao produção antes e depois de uma data de referência (ex.: troca de dieta).
+type LoteDesempenhoComparacao struct {
+	Marco  string             `json:"marco"`
+	Antes  LoteProducaoResumo `json:"antes"`
+	Depois LoteProducaoResumo `json:"depois"`
+	// VariacaoPct variação percentual da média por animal/dia (nil sem produção antes do marco).
+	VariacaoPct *float64 `json:"variacao_pct,omitempty"`
+}
+
+// LoteDesempenho relatório de um lote no período.
+type LoteDesempenho struct {
+	LoteID      int64                     `json:"lote_id"`
+	LoteNome    string                    `json:"lote_nome"`
+	Tipo        *string                   `json:"tipo,omitempty"`
+	De          string                    `json:"de"`
+	Ate         string                    `json:"ate"`
+	Producao    LoteProducaoResumo        `json:"producao"`
+	Serie       []LoteProducaoDia         `json:"serie"`
+	Indicadores LoteIndicadores           `json:"indicadores"`
+	Comparacao  *LoteDesempenhoComparacao `json:"comparacao,omitempty"`
+}
+
+// LoteDesempenhoResumo linha do relatório por fazenda; LoteID nil agrega a produção de animais sem lote.
+type LoteDesempenhoResumo struct {
+	LoteID      *int64             `json:"lote_id,omitempty"`
+	LoteNome    *string            `json:"lote_nome,omitempty"`
+	Tipo        *string            `json:"tipo,omitempty"`
+	Ativo       bool               `json:"ativo"`
+	Producao    LoteProducaoResumo `json:"producao"`
+	Indicadores LoteIndicadores    `json:"indicadores"`
+}
+
+// LotesDesempenho relatório de todos os lotes da fazenda no período.
+type LotesDesempenho struct {
+	FazendaID int64                  `json:"fazenda_id"`
+	De        string                 `json:"de"`
+	Ate       string                 `json:"ate"`
+	Lotes     []LoteDesempenhoResumo `json:"lotes"`
+}
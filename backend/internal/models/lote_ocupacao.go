@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Tipos de evento no histórico de ocupação de um lote.
+const (
+	LoteEventoEntrada = "ENTRADA"
+	LoteEventoSaida   = "SAIDA"
+	LoteEventoBaixa   = "BAIXA" // animal saiu do rebanho estando no lote
+)
+
+// LoteOcupacaoEvento entrada/saída do lote (movimentacoes_lote) ou baixa de um animal do lote.
+type LoteOcupacaoEvento struct {
+	Tipo                string    `json:"tipo"`
+	Data                time.Time `json:"data"`
+	AnimalID            int64     `json:"animal_id"`
+	AnimalIdentificacao string    `json:"animal_identificacao"`
+	MovimentacaoID      *int64    `json:"movimentacao_id,omitempty"`
+	OutroLoteID         *int64    `json:"outro_lote_id,omitempty"`
+	OutroLoteNome       *string   `json:"outro_lote_nome,omitempty"`
+	Motivo              *string   `json:"motivo,omitempty"`
+}
+
+// LoteOcupacaoDia ocupação no fim do dia civil.
+type LoteOcupacaoDia struct {
+	Data     string `json:"data"`
+	Ocupacao int    `json:"ocupacao"`
+	Entradas int    `json:"entradas"`
+	Saidas   int    `json:"saidas"`
+}
+
+// LoteOcupacaoHistorico resposta de GET /lotes/:id/ocupacao (BR-LOTE-010).
+type LoteOcupacaoHistorico struct {
+	LoteID        int64                `json:"lote_id"`
+	Capacidade    *int                 `json:"capacidade,omitempty"`
+	OcupacaoAtual int                  `json:"ocupacao_atual"`
+	De            string               `json:"de"`
+	Ate           string               `json:"ate"`
+	Serie         []LoteOcupacaoDia    `json:"serie"`
+	Eventos       []LoteOcupacaoEvento `json:"eventos"`
+}
@@ -0,0 +1,107 @@
+package models
+
+import "time"
+
+// Canais de notificação de alertas (BR-ALERTA-021).
+const (
+	NotificacaoCanalWebPush  = "WEB_PUSH"
+	NotificacaoCanalEmail    = "EMAIL"
+	NotificacaoCanalSMS      = "SMS"
+	NotificacaoCanalWhatsApp = "WHATSAPP"
+)
+
+// Modo de entrega de um canal: imediato ou acumulado no resumo (digest).
+const (
+	NotificacaoModoImediato = "IMEDIATO"
+	NotificacaoModoDigest   = "DIGEST"
+)
+
+func ValidNotificacaoCanais() []string {
+	return []string{NotificacaoCanalWebPush, NotificacaoCanalEmail, NotificacaoCanalSMS, NotificacaoCanalWhatsApp}
+}
+
+func IsValidNotificacaoCanal(v string) bool {
+	for _, c := range ValidNotificacaoCanais() {
+		if c == v {
+			return true
+		}
+	}
+	return false
+}
+
+func IsValidNotificacaoModo(v string) bool {
+	return v == NotificacaoModoImediato || v == NotificacaoModoDigest
+}
+
+// NotificacaoPreferencia escolha do utilizador para um canal. Tipos nil = todos os tipos de alerta.
+type NotificacaoPreferencia struct {
+	UsuarioID        int64      `json:"-" db:"usuario_id"`
+	Canal            string     `json:"canal" db:"canal"`
+	Ativo            bool       `json:"ativo" db:"ativo"`
+	SeveridadeMinima string     `json:"severidade_minima" db:"severidade_minima"`
+	Tipos            []string   `json:"tipos" db:"tipos"`
+	Modo             string     `json:"modo" db:"modo"`
+	Disponivel       bool       `json:"disponivel" db:"-"` // canal configurado no servidor
+	UpdatedAt        *time.Time `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// NotificacaoConfigUsuario contacto e horário de silêncio do utilizador (horas locais HH:MM no fuso dos alertas).
+// Silêncio com início > fim atravessa a meia-noite (ex.: 22:00–06:00).
+type NotificacaoConfigUsuario struct {
+	UsuarioID              int64      `json:"-" db:"usuario_id"`
+	Telefone               *string    `json:"telefone,omitempty" db:"telefone"`
+	SilencioInicio         *string    `json:"silencio_inicio,omitempty" db:"silencio_inicio"`
+	SilencioFim            *string    `json:"silencio_fim,omitempty" db:"silencio_fim"`
+	SilencioPermiteCritica bool       `json:"silencio_permite_critica" db:"silencio_permite_critica"`
+	UpdatedAt              *time.Time `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// NotificacaoPreferencias configuração completa devolvida em GET /me/notificacoes.
+type NotificacaoPreferencias struct {
+	Config NotificacaoConfigUsuario `json:"config"`
+	Canais []NotificacaoPreferencia `json:"canais"`
+}
+
+// NotificacaoPreferenciaPadrao comportamento sem preferências gravadas: só Web Push, CRITICA e ALTA, imediato.
+func NotificacaoPreferenciaPadrao(canal string) NotificacaoPreferencia {
+	return NotificacaoPreferencia{
+		Canal:            canal,
+		Ativo:            canal == NotificacaoCanalWebPush,
+		SeveridadeMinima: AlertaSeveridadeAlta,
+		Modo:             NotificacaoModoImediato,
+	}
+}
+
+// NotificacaoConfigPadrao utilizador sem contacto nem silêncio; críticos furam o silêncio.
+func NotificacaoConfigPadrao(usuarioID int64) NotificacaoConfigUsuario {
+	return NotificacaoConfigUsuario{UsuarioID: usuarioID, SilencioPermiteCritica: true}
+}
+
+// NotificacaoDigestItem alerta acumulado para o resumo de um canal.
+type NotificacaoDigestItem struct {
+	ID        int64     `json:"id" db:"id"`
+	UsuarioID int64     `json:"usuario_id" db:"usuario_id"`
+	Canal     string    `json:"canal" db:"canal"`
+	AlertaID  *int64    `json:"alerta_id,omitempty" db:"alerta_id"`
+	FazendaID *int64    `json:"fazenda_id,omitempty" db:"fazenda_id"`
+	Titulo    string    `json:"titulo" db:"titulo"`
+	Corpo     string    `json:"corpo" db:"corpo"`
+	URL       string    `json:"url" db:"url"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// AlertaSeveridadeNivel ordena as severidades (0 = desconhecida).
+func AlertaSeveridadeNivel(severidade string) int {
+	switch severidade {
+	case AlertaSeveridadeCritica:
+		return 4
+	case AlertaSeveridadeAlta:
+		return 3
+	case AlertaSeveridadeMedia:
+		return 2
+	case AlertaSeveridadeBaixa:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// RebanhoSnapshotAnimal é o estado reconstruído de um animal numa data passada.
+// StatusReprodutivo é nil para machos e para fêmeas sem marcos reprodutivos até a data.
+type RebanhoSnapshotAnimal struct {
+	AnimalID          int64      `json:"animal_id"`
+	Identificacao     string     `json:"identificacao"`
+	Sexo              *string    `json:"sexo,omitempty"`
+	Categoria         *string    `json:"categoria,omitempty"`
+	StatusReprodutivo *string    `json:"status_reprodutivo,omitempty"`
+	StatusSaude       string     `json:"status_saude"`
+	LoteID            *int64     `json:"lote_id,omitempty"`
+	LoteNome          *string    `json:"lote_nome,omitempty"`
+	EmLactacao        bool       `json:"em_lactacao"`
+	EmRestricaoLeite  bool       `json:"em_restricao_leite"`
+	GestacaoAtiva     bool       `json:"gestacao_ativa"`
+	DataPrevistaParto *time.Time `json:"data_prevista_parto,omitempty"`
+}
+
+// RebanhoSnapshotLote contagem de animais por lote na data (lote_id nil = sem lote).
+type RebanhoSnapshotLote struct {
+	LoteID   *int64  `json:"lote_id,omitempty"`
+	LoteNome *string `json:"lote_nome,omitempty"`
+	Total    int     `json:"total"`
+}
+
+// RebanhoSnapshotResumo agrega o rebanho na data; os totais seguem os mesmos conceitos de ResumoPecuario.
+type RebanhoSnapshotResumo struct {
+	TotalAnimais          int                   `json:"total_animais"`
+	PrenhesTotal          int                   `json:"prenhes_total"`
+	RestricoesAtivasTotal int                   `json:"restricoes_ativas_total"`
+	ProducaoDiaLitros     float64               `json:"producao_dia_litros"`
+	ProducaoSemanaLitros  float64               `json:"producao_semana_litros"`
+	PartosProximos7dTotal int                   `json:"partos_proximos_7d_total"`
+	LactacaoAtivaTotal    int                   `json:"lactacao_ativa_total"`
+	PorCategoria          map[string]int        `json:"por_categoria"`
+	PorStatusReprodutivo  map[string]int        `json:"por_status_reprodutivo"`
+	PorStatusSaude        map[string]int        `json:"por_status_saude"`
+	PorLote               []RebanhoSnapshotLote `json:"por_lote"`
+}
+
+// RebanhoSnapshot é o rebanho da fazenda tal como estava no fim do dia Data.
+type RebanhoSnapshot struct {
+	FazendaID int64                    `json:"fazenda_id"`
+	Data      string                   `json:"data"`
+	Animais   []*RebanhoSnapshotAnimal `json:"animais"`
+	Resumo    RebanhoSnapshotResumo    `json:"resumo"`
+}
@@ -9,4 +9,11 @@ type RefreshToken struct {
 	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	Revoked   bool      `json:"revoked" db:"revoked"`
+	// AAL nível de garantia com que a sessão foi aberta (1 senha, 2 senha + TOTP); mantido na rotação.
+	AAL int `json:"aal" db:"aal"`
+	// FamiliaID identifica a sessão (dispositivo): nasce no login e passa de token em token na rotação.
+	FamiliaID        int64     `json:"familia_id" db:"familia_id"`
+	SessaoIniciadaEm time.Time `json:"sessao_iniciada_em" db:"sessao_iniciada_em"`
+	UserAgent        *string   `json:"user_agent,omitempty" db:"user_agent"`
+	IP               *string   `json:"ip,omitempty" db:"ip"`
 }
@@ -0,0 +1,85 @@
+package models
+
+import "time"
+
+// Frequência do resumo periódico de alertas (BR-ALERTA-022).
+const (
+	ResumoAlertasDiario  = "DIARIO"
+	ResumoAlertasSemanal = "SEMANAL"
+)
+
+// ResumoAlertasMaisUrgentes limita os alertas listados por fazenda no resumo.
+const ResumoAlertasMaisUrgentes = 5
+
+func IsValidResumoAlertasFrequencia(v string) bool {
+	return v == ResumoAlertasDiario || v == ResumoAlertasSemanal
+}
+
+// ResumoAlertasConfig agendamento do resumo do utilizador. Hora local no fuso dos alertas; DiaSemana
+// (0 = domingo) só para SEMANAL.
+type ResumoAlertasConfig struct {
+	UsuarioID     int64      `json:"-" db:"usuario_id"`
+	Ativo         bool       `json:"ativo" db:"ativo"`
+	Frequencia    string     `json:"frequencia" db:"frequencia"`
+	Hora          int        `json:"hora" db:"hora"`
+	DiaSemana     *int       `json:"dia_semana,omitempty" db:"dia_semana"`
+	Canais        []string   `json:"canais" db:"canais"`
+	UltimoEnvioEm *time.Time `json:"ultimo_envio_em,omitempty" db:"ultimo_envio_em"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// ResumoAlertasConfigPadrao resumo desligado (utilizador ainda não configurou).
+func ResumoAlertasConfigPadrao(usuarioID int64) ResumoAlertasConfig {
+	return ResumoAlertasConfig{
+		UsuarioID:  usuarioID,
+		Frequencia: ResumoAlertasDiario,
+		Hora:       7,
+		Canais:     []string{NotificacaoCanalWebPush},
+	}
+}
+
+// ResumoAlertasTipo contagem de alertas abertos de um tipo e severidade.
+type ResumoAlertasTipo struct {
+	Tipo       string `json:"tipo"`
+	Label      string `json:"label"`
+	Severidade string `json:"severidade"`
+	Total      int    `json:"total"`
+}
+
+// VacinaPrevistaResumo vacina prevista e não aplicada até ao fim da janela do resumo.
+type VacinaPrevistaResumo struct {
+	AnimalID      int64     `json:"animal_id"`
+	Identificacao string    `json:"identificacao"`
+	TipoVacina    string    `json:"tipo_vacina"`
+	DataPrevista  time.Time `json:"data_prevista"`
+}
+
+// ResumoAlertasFazenda alertas abertos e agenda de uma fazenda.
+type ResumoAlertasFazenda struct {
+	FazendaID         int64                      `json:"fazenda_id"`
+	FazendaNome       string                     `json:"fazenda_nome"`
+	AlertasAbertos    int                        `json:"alertas_abertos"`
+	PorTipo           []ResumoAlertasTipo        `json:"por_tipo"`
+	MaisUrgentes      []AlertaWithNames          `json:"mais_urgentes"`
+	PartosPrevistos   []PartoPrevistoResumo      `json:"partos_previstos"`
+	HormonioPendentes []HormonioLactacaoPendente `json:"hormonio_pendentes"`
+	VacinasPrevistas  []VacinaPrevistaResumo     `json:"vacinas_previstas"`
+}
+
+// ResumoAlertas conteúdo do resumo de um utilizador; a agenda cobre [Data, AgendaAte].
+type ResumoAlertas struct {
+	Data       string                 `json:"data"`
+	AgendaAte  string                 `json:"agenda_ate"`
+	Frequencia string                 `json:"frequencia"`
+	Fazendas   []ResumoAlertasFazenda `json:"fazendas"`
+}
+
+// Vazio indica que não há alertas abertos nem agenda em nenhuma fazenda (resumo não é enviado).
+func (r *ResumoAlertas) Vazio() bool {
+	for _, f := range r.Fazendas {
+		if f.AlertasAbertos > 0 || len(f.PartosPrevistos) > 0 || len(f.HormonioPendentes) > 0 || len(f.VacinasPrevistas) > 0 {
+			return false
+		}
+	}
+	return true
+}
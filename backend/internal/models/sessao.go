@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Sessao sessão ativa de um dispositivo — a família de refresh tokens viva (BR-ACESSO-028).
+type Sessao struct {
+	ID          int64     `json:"id"`
+	IniciadaEm  time.Time `json:"iniciada_em"`
+	UltimoUsoEm time.Time `json:"ultimo_uso_em"`
+	ExpiraEm    time.Time `json:"expira_em"`
+	UserAgent   *string   `json:"user_agent,omitempty"`
+	IP          *string   `json:"ip,omitempty"`
+	AAL         int       `json:"aal"`
+	// Atual sessão do próprio pedido (cookie de refresh); sempre false na visão do administrador.
+	Atual bool `json:"atual"`
+}
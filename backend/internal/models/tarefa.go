@@ -0,0 +1,136 @@
+package models
+
+import "time"
+
+// Status da tarefa: mesmo fluxo do alerta (BR-TAREFA-002), com CONCLUIDA/CANCELADA no lugar de
+// RESOLVIDO/IGNORADO.
+const (
+	TarefaStatusAberta      = "ABERTA"
+	TarefaStatusEmAndamento = "EM_ANDAMENTO"
+	TarefaStatusConcluida   = "CONCLUIDA"
+	TarefaStatusCancelada   = "CANCELADA"
+)
+
+// Recorrência: ao concluir, a próxima ocorrência nasce 1 ou 7 dias depois (BR-TAREFA-001).
+const (
+	TarefaRecorrenciaNenhuma = "NENHUMA"
+	TarefaRecorrenciaDiaria  = "DIARIA"
+	TarefaRecorrenciaSemanal = "SEMANAL"
+)
+
+const (
+	TarefaTituloMaxLen    = 200
+	TarefaChecklistMax    = 30
+	TarefaChecklistMaxLen = 200
+)
+
+type Tarefa struct {
+	ID            int64      `json:"id"`
+	FazendaID     int64      `json:"fazenda_id"`
+	Titulo        string     `json:"titulo"`
+	Descricao     *string    `json:"descricao,omitempty"`
+	AnimalID      *int64     `json:"animal_id,omitempty"`
+	LoteID        *int64     `json:"lote_id,omitempty"`
+	AreaID        *int64     `json:"area_id,omitempty"`
+	AlertaID      *int64     `json:"alerta_id,omitempty"`
+	ResponsavelID *int64     `json:"responsavel_id,omitempty"`
+	DataPrevista  time.Time  `json:"data_prevista"`
+	Recorrencia   string     `json:"recorrencia"`
+	SerieID       *int64     `json:"serie_id,omitempty"`
+	Status        string     `json:"status"`
+	ConcluidaPor  *int64     `json:"concluida_por,omitempty"`
+	ConcluidaEm   *time.Time `json:"concluida_em,omitempty"`
+	CreatedBy     *int64     `json:"created_by,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+type TarefaChecklistItem struct {
+	ID       int64      `json:"id"`
+	TarefaID int64      `json:"tarefa_id"`
+	Ordem    int        `json:"ordem"`
+	Texto    string     `json:"texto"`
+	Feito    bool       `json:"feito"`
+	FeitoPor *int64     `json:"feito_por,omitempty"`
+	FeitoEm  *time.Time `json:"feito_em,omitempty"`
+}
+
+type TarefaWithNames struct {
+	Tarefa
+	AnimalIdentificacao *string               `json:"animal_identificacao,omitempty"`
+	LoteNome            *string               `json:"lote_nome,omitempty"`
+	AreaNome            *string               `json:"area_nome,omitempty"`
+	ResponsavelNome     *string               `json:"responsavel_nome,omitempty"`
+	Checklist           []TarefaChecklistItem `json:"checklist"`
+	// Atrasada: ABERTA/EM_ANDAMENTO com data_prevista anterior a hoje (calculado na consulta).
+	Atrasada bool `json:"atrasada"`
+	// ResponsavelDeFolga: o responsável tem folga ou ausência registrada na data prevista (BR-TAREFA-004).
+	ResponsavelDeFolga bool `json:"responsavel_de_folga"`
+}
+
+// MinhasTarefasHoje resposta de "minhas tarefas de hoje" (BR-TAREFA-004): de folga ou ausente, a
+// lista vem vazia e Pendentes informa quantas ficaram para a gestão redistribuir.
+type MinhasTarefasHoje struct {
+	Data      time.Time         `json:"data"`
+	DeFolga   bool              `json:"de_folga"`
+	Motivo    string            `json:"motivo,omitempty"`
+	Pendentes int               `json:"pendentes"`
+	Tarefas   []TarefaWithNames `json:"tarefas"`
+}
+
+func IsValidTarefaStatus(v string) bool {
+	switch v {
+	case TarefaStatusAberta, TarefaStatusEmAndamento, TarefaStatusConcluida, TarefaStatusCancelada:
+		return true
+	}
+	return false
+}
+
+func IsValidTarefaRecorrencia(v string) bool {
+	switch v {
+	case TarefaRecorrenciaNenhuma, TarefaRecorrenciaDiaria, TarefaRecorrenciaSemanal:
+		return true
+	}
+	return false
+}
+
+func IsTarefaStatusTerminal(status string) bool {
+	return status == TarefaStatusConcluida || status == TarefaStatusCancelada
+}
+
+func IsTransicaoTarefaStatusValida(from, to string) bool {
+	if from == to || IsTarefaStatusTerminal(from) {
+		return false
+	}
+	switch from {
+	case TarefaStatusAberta:
+		return to == TarefaStatusEmAndamento || to == TarefaStatusConcluida || to == TarefaStatusCancelada
+	case TarefaStatusEmAndamento:
+		return to == TarefaStatusConcluida || to == TarefaStatusCancelada
+	default:
+		return false
+	}
+}
+
+// ProximaDataTarefa data da próxima ocorrência da série; ok=false sem recorrência.
+func ProximaDataTarefa(recorrencia string, d time.Time) (time.Time, bool) {
+	switch recorrencia {
+	case TarefaRecorrenciaDiaria:
+		return d.AddDate(0, 0, 1), true
+	case TarefaRecorrenciaSemanal:
+		return d.AddDate(0, 0, 7), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// PodeGerenciarTarefas criar, editar, cancelar e excluir tarefas; mesma matriz da gestão de folgas.
+func PodeGerenciarTarefas(perfil string) bool {
+	return PodeGerenciarFolgas(perfil)
+}
+
+// PodeExecutarTarefa iniciar, concluir e marcar checklist (a própria tarefa ou uma sem responsável);
+// USER aguardando provisão não executa.
+func PodeExecutarTarefa(perfil string) bool {
+	return PodeMarcarAlertaEmAndamento(perfil)
+}
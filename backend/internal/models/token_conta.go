@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Tipos de token de uso único da conta (BR-ACESSO-026).
+const (
+	TokenContaResetSenha          = "RESET_SENHA"
+	TokenContaVerificacaoEmail    = "VERIFICACAO_EMAIL"
+	TokenContaResetSenhaValidade  = time.Hour
+	TokenContaVerificacaoValidade = 48 * time.Hour
+	// TokenContaMaxPorHora limita envios por conta e tipo, além do limite por IP da rota.
+	TokenContaMaxPorHora = 3
+)
+
+// TokenConta token de redefinição de senha ou verificação de e-mail. O valor em claro só vai no
+// link enviado por e-mail; o banco guarda o hash.
+type TokenConta struct {
+	ID        int64      `json:"id"`
+	UsuarioID int64      `json:"usuario_id"`
+	Tipo      string     `json:"tipo"`
+	Email     string     `json:"email"`
+	ExpiraEm  time.Time  `json:"expira_em"`
+	UsadoEm   *time.Time `json:"usado_em,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
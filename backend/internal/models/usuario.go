@@ -11,6 +11,8 @@ type Usuario struct {
 	Enabled   bool      `json:"enabled" db:"enabled"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	// EmailVerificadoEm nil enquanto o e-mail não foi confirmado (BR-ACESSO-026).
+	EmailVerificadoEm *time.Time `json:"email_verificado_em,omitempty" db:"email_verificado_em"`
 }
 
 // UsuarioPublico dados mínimos para listagens (ex.: vínculo com fazenda).
@@ -0,0 +1,298 @@
+// Package oidc cliente OpenID Connect mínimo para o login SSO (BR-ACESSO-029): descoberta do emissor,
+// authorization code com PKCE (S256), troca do código e validação do ID token (RS256, JWKS do emissor).
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrIDTokenInvalido = errors.New("ID token inválido")
+	ErrTrocaCodigo     = errors.New("o provedor recusou o código de autorização")
+)
+
+// jwksIntervaloMinimo evita rebuscar as chaves a cada token com kid desconhecido.
+const jwksIntervaloMinimo = time.Minute
+
+// Config cliente registado no provedor. ClientSecret vazio = cliente público (só PKCE).
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// Fluxo dados de um login em curso, guardados no navegador entre o redirecionamento e o callback.
+type Fluxo struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	// Redirect caminho do frontend para onde voltar depois do login.
+	Redirect string `json:"redirect,omitempty"`
+}
+
+type descoberta struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider emissor descoberto em /.well-known/openid-configuration.
+type Provider struct {
+	cfg    Config
+	meta   descoberta
+	client *http.Client
+	now    func() time.Time
+
+	mu           sync.Mutex
+	chaves       map[string]*rsa.PublicKey
+	chavesBuscEm time.Time
+}
+
+// NewProvider faz a descoberta do emissor; o issuer anunciado tem de ser o configurado.
+func NewProvider(ctx context.Context, cfg Config, client *http.Client) (*Provider, error) {
+	if cfg.Issuer == "" || cfg.ClientID == "" || cfg.RedirectURL == "" {
+		return nil, errors.New("oidc: issuer, client id e redirect URL são obrigatórios")
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "email", "profile"}
+	}
+	p := &Provider{cfg: cfg, client: client, now: time.Now}
+	wellKnown := strings.TrimSuffix(cfg.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := p.getJSON(ctx, wellKnown, &p.meta); err != nil {
+		return nil, fmt.Errorf("oidc: descoberta: %w", err)
+	}
+	if strings.TrimSuffix(p.meta.Issuer, "/") != strings.TrimSuffix(cfg.Issuer, "/") {
+		return nil, fmt.Errorf("oidc: issuer anunciado %q difere do configurado %q", p.meta.Issuer, cfg.Issuer)
+	}
+	if p.meta.AuthorizationEndpoint == "" || p.meta.TokenEndpoint == "" || p.meta.JWKSURI == "" {
+		return nil, errors.New("oidc: descoberta incompleta (authorization, token ou jwks)")
+	}
+	return p, nil
+}
+
+// Issuer emissor anunciado (chave das identidades vinculadas).
+func (p *Provider) Issuer() string {
+	return p.meta.Issuer
+}
+
+// NovoFluxo state, nonce e verificador PKCE aleatórios.
+func NovoFluxo(redirect string) (Fluxo, error) {
+	var f Fluxo
+	for _, campo := range []*string{&f.State, &f.Nonce, &f.Verifier} {
+		v, err := aleatorio()
+		if err != nil {
+			return Fluxo{}, err
+		}
+		*campo = v
+	}
+	f.Redirect = redirect
+	return f, nil
+}
+
+func aleatorio() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// DesafioPKCE code_challenge S256 do verificador (RFC 7636).
+func DesafioPKCE(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthURL endereço do provedor para onde o navegador é enviado.
+func (p *Provider) AuthURL(f Fluxo) string {
+	q := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.cfg.ClientID},
+		"redirect_uri":          {p.cfg.RedirectURL},
+		"scope":                 {strings.Join(p.cfg.Scopes, " ")},
+		"state":                 {f.State},
+		"nonce":                 {f.Nonce},
+		"code_challenge":        {DesafioPKCE(f.Verifier)},
+		"code_challenge_method": {"S256"},
+	}
+	sep := "?"
+	if strings.Contains(p.meta.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return p.meta.AuthorizationEndpoint + sep + q.Encode()
+}
+
+// Trocar troca o código pelo ID token e devolve as claims já validadas (assinatura, iss, aud, exp, nonce).
+func (p *Provider) Trocar(ctx context.Context, code string, f Fluxo) (jwt.MapClaims, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"code_verifier": {f.Verifier},
+	}
+	if p.cfg.ClientSecret == "" {
+		form.Set("client_id", p.cfg.ClientID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w (HTTP %d)", ErrTrocaCodigo, resp.StatusCode)
+	}
+	var tok struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.Unmarshal(body, &tok); err != nil || tok.IDToken == "" {
+		return nil, fmt.Errorf("%w: resposta sem id_token", ErrTrocaCodigo)
+	}
+	return p.VerificarIDToken(ctx, tok.IDToken, f.Nonce)
+}
+
+// VerificarIDToken valida o ID token do emissor configurado para este cliente e com o nonce do fluxo.
+func (p *Provider) VerificarIDToken(ctx context.Context, raw, nonce string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.chave(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256"}),
+		jwt.WithIssuer(p.meta.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute),
+		jwt.WithTimeFunc(p.now),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrIDTokenInvalido, err)
+	}
+	if n, _ := claims["nonce"].(string); n == "" || n != nonce {
+		return nil, fmt.Errorf("%w: nonce não confere", ErrIDTokenInvalido)
+	}
+	// Com várias audiências, azp tem de ser este cliente (OIDC Core 3.1.3.7).
+	if aud, _ := claims.GetAudience(); len(aud) > 1 {
+		if azp, _ := claims["azp"].(string); azp != p.cfg.ClientID {
+			return nil, fmt.Errorf("%w: azp não confere", ErrIDTokenInvalido)
+		}
+	}
+	if sub, _ := claims["sub"].(string); sub == "" {
+		return nil, fmt.Errorf("%w: sem sub", ErrIDTokenInvalido)
+	}
+	return claims, nil
+}
+
+// chave RSA do kid; kid desconhecido rebusca o JWKS (rotação de chaves no provedor), no máximo uma vez por minuto.
+func (p *Provider) chave(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if k := p.escolher(kid); k != nil {
+		return k, nil
+	}
+	if !p.chavesBuscEm.IsZero() && p.now().Sub(p.chavesBuscEm) < jwksIntervaloMinimo {
+		return nil, errors.New("chave de assinatura desconhecida")
+	}
+	chaves, err := p.buscarJWKS(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.chaves, p.chavesBuscEm = chaves, p.now()
+	if k := p.escolher(kid); k != nil {
+		return k, nil
+	}
+	return nil, errors.New("chave de assinatura desconhecida")
+}
+
+// escolher sem kid só serve se o JWKS tiver uma única chave.
+func (p *Provider) escolher(kid string) *rsa.PublicKey {
+	if kid != "" {
+		return p.chaves[kid]
+	}
+	if len(p.chaves) == 1 {
+		for _, k := range p.chaves {
+			return k
+		}
+	}
+	return nil
+}
+
+func (p *Provider) buscarJWKS(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := p.getJSON(ctx, p.meta.JWKSURI, &set); err != nil {
+		return nil, fmt.Errorf("oidc: jwks: %w", err)
+	}
+	out := map[string]*rsa.PublicKey{}
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil || len(e) == 0 || len(e) > 4 {
+			continue
+		}
+		out[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	return out, nil
+}
+
+func (p *Provider) getJSON(ctx context.Context, endpoint string, dst interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d em %s", resp.StatusCode, endpoint)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(dst)
+}
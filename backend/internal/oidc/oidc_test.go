@@ -0,0 +1,105 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/ceialmilk/api/internal/oidc/oidctest"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func novoProvider(t *testing.T, secret string) (*Provider, *oidctest.Issuer) {
+	t.Helper()
+	iss, srv := oidctest.NewServer("ceialmilk", secret)
+	t.Cleanup(srv.Close)
+	p, err := NewProvider(context.Background(), Config{
+		Issuer:       iss.URL,
+		ClientID:     "ceialmilk",
+		ClientSecret: secret,
+		RedirectURL:  "http://localhost:8080/api/auth/oidc/callback",
+	}, srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p, iss
+}
+
+func TestProvider_FluxoCompleto(t *testing.T) {
+	for _, secret := range []string{"", "s3gr3d0"} {
+		p, iss := novoProvider(t, secret)
+		f, err := NovoFluxo("/animais")
+		if err != nil {
+			t.Fatal(err)
+		}
+		authURL := p.AuthURL(f)
+		u, _ := url.Parse(authURL)
+		if u.Query().Get("code_challenge") != DesafioPKCE(f.Verifier) || u.Query().Get("code_challenge_method") != "S256" {
+			t.Fatalf("PKCE ausente em %s", authURL)
+		}
+		code, state, err := iss.Authorize(authURL)
+		if err != nil || code == "" || state != f.State {
+			t.Fatalf("authorize: code %q state %q err %v", code, state, err)
+		}
+		claims, err := p.Trocar(context.Background(), code, f)
+		if err != nil {
+			t.Fatalf("secret %q: %v", secret, err)
+		}
+		if claims["sub"] != "usuario-1" || claims["email"] != "produtor@example.com" {
+			t.Errorf("claims = %v", claims)
+		}
+		if _, err := p.Trocar(context.Background(), code, f); !errors.Is(err, ErrTrocaCodigo) {
+			t.Errorf("código reutilizado: err = %v", err)
+		}
+	}
+}
+
+func TestProvider_Recusas(t *testing.T) {
+	p, iss := novoProvider(t, "")
+	ctx := context.Background()
+
+	f, _ := NovoFluxo("")
+	code, _, _ := iss.Authorize(p.AuthURL(f))
+	outro := f
+	outro.Verifier = "verificador-de-outro-navegador"
+	if _, err := p.Trocar(ctx, code, outro); !errors.Is(err, ErrTrocaCodigo) {
+		t.Errorf("PKCE errado: err = %v", err)
+	}
+
+	f, _ = NovoFluxo("")
+	code, _, _ = iss.Authorize(p.AuthURL(f))
+	outro = f
+	outro.Nonce = "outro"
+	if _, err := p.Trocar(ctx, code, outro); !errors.Is(err, ErrIDTokenInvalido) {
+		t.Errorf("nonce errado: err = %v", err)
+	}
+
+	casos := map[string]func(jwt.MapClaims){
+		"audiência de outro cliente": func(c jwt.MapClaims) { c["aud"] = "outro-app" },
+		"emissor diferente":          func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" },
+		"expirado":                   func(c jwt.MapClaims) { c["exp"] = 1000 },
+		"sem sub":                    func(c jwt.MapClaims) { delete(c, "sub") },
+		"várias audiências sem azp":  func(c jwt.MapClaims) { c["aud"] = []string{"ceialmilk", "outro-app"} },
+	}
+	for nome, hook := range casos {
+		iss.IDTokenHook = hook
+		f, _ = NovoFluxo("")
+		code, _, _ = iss.Authorize(p.AuthURL(f))
+		if _, err := p.Trocar(ctx, code, f); !errors.Is(err, ErrIDTokenInvalido) {
+			t.Errorf("%s: err = %v", nome, err)
+		}
+	}
+}
+
+func TestNewProvider_IssuerDiferente(t *testing.T) {
+	iss, srv := oidctest.NewServer("ceialmilk", "")
+	defer srv.Close()
+	iss.URL = "https://outro-emissor.example.com"
+	_, err := NewProvider(context.Background(), Config{
+		Issuer: srv.URL, ClientID: "ceialmilk", RedirectURL: "http://localhost/cb",
+	}, srv.Client())
+	if err == nil {
+		t.Fatal("descoberta com issuer diferente deve falhar")
+	}
+}
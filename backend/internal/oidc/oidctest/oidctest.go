@@ -0,0 +1,217 @@
+// Package oidctest emissor OpenID Connect falso para testes e desenvolvimento local (BR-ACESSO-029):
+// descoberta, JWKS, /authorize que aprova sem tela e /token que confere o PKCE e assina o ID token.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const kid = "oidctest"
+
+type autorizacao struct {
+	redirectURI string
+	challenge   string
+	nonce       string
+	claims      map[string]interface{}
+}
+
+// Issuer provedor falso. Claims são as do próximo login (sub, email, name, groups…); iss, aud, nonce,
+// iat e exp são preenchidos pelo emissor.
+type Issuer struct {
+	URL          string
+	ClientID     string
+	ClientSecret string
+
+	mu     sync.Mutex
+	claims map[string]interface{}
+	codes  map[string]autorizacao
+	key    *rsa.PrivateKey
+	// IDTokenHook permite adulterar as claims assinadas (testes de validação).
+	IDTokenHook func(claims jwt.MapClaims)
+}
+
+// New emissor em url (sem barra final) para o cliente clientID; clientSecret vazio = cliente público.
+func New(issuerURL, clientID, clientSecret string) (*Issuer, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	return &Issuer{
+		URL:          issuerURL,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		claims:       map[string]interface{}{"sub": "usuario-1", "email": "produtor@example.com", "email_verified": true, "name": "Produtor Teste"},
+		codes:        map[string]autorizacao{},
+		key:          key,
+	}, nil
+}
+
+// NewServer emissor num httptest.Server; Close encerra.
+func NewServer(clientID, clientSecret string) (*Issuer, *httptest.Server) {
+	srv := httptest.NewUnstartedServer(nil)
+	srv.Start()
+	iss, err := New(srv.URL, clientID, clientSecret)
+	if err != nil {
+		panic(err)
+	}
+	srv.Config.Handler = iss
+	return iss, srv
+}
+
+// SetClaims claims do próximo login.
+func (i *Issuer) SetClaims(claims map[string]interface{}) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.claims = claims
+}
+
+// Authorize simula o navegador no /authorize: devolve o code que o provedor mandaria ao redirect_uri.
+func (i *Issuer) Authorize(authURL string) (code, state string, err error) {
+	req, _ := http.NewRequest(http.MethodGet, authURL, nil)
+	w := httptest.NewRecorder()
+	i.ServeHTTP(w, req)
+	loc, err := url.Parse(w.Header().Get("Location"))
+	if err != nil {
+		return "", "", err
+	}
+	return loc.Query().Get("code"), loc.Query().Get("state"), nil
+}
+
+func (i *Issuer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/.well-known/openid-configuration":
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"issuer":                                i.URL,
+			"authorization_endpoint":                i.URL + "/authorize",
+			"token_endpoint":                        i.URL + "/token",
+			"jwks_uri":                              i.URL + "/jwks",
+			"response_types_supported":              []string{"code"},
+			"subject_types_supported":               []string{"public"},
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+			"code_challenge_methods_supported":      []string{"S256"},
+		})
+	case "/jwks":
+		writeJSON(w, http.StatusOK, map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA", "kid": kid, "use": "sig", "alg": "RS256",
+			"n": base64.RawURLEncoding.EncodeToString(i.key.N.Bytes()),
+			"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(i.key.E)).Bytes()),
+		}}})
+	case "/authorize":
+		i.authorize(w, r)
+	case "/token":
+		i.token(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (i *Issuer) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	redirectURI := q.Get("redirect_uri")
+	if q.Get("client_id") != i.ClientID || q.Get("response_type") != "code" || redirectURI == "" ||
+		q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		http.Error(w, "pedido de autorização inválido", http.StatusBadRequest)
+		return
+	}
+	code := aleatorio()
+	i.mu.Lock()
+	claims := make(map[string]interface{}, len(i.claims))
+	for k, v := range i.claims {
+		claims[k] = v
+	}
+	i.codes[code] = autorizacao{redirectURI: redirectURI, challenge: q.Get("code_challenge"), nonce: q.Get("nonce"), claims: claims}
+	i.mu.Unlock()
+
+	dest, err := url.Parse(redirectURI)
+	if err != nil {
+		http.Error(w, "redirect_uri inválido", http.StatusBadRequest)
+		return
+	}
+	dq := dest.Query()
+	dq.Set("code", code)
+	dq.Set("state", q.Get("state"))
+	dest.RawQuery = dq.Encode()
+	http.Redirect(w, r, dest.String(), http.StatusFound)
+}
+
+func (i *Issuer) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	clientID, secret, basic := r.BasicAuth()
+	if basic {
+		clientID, _ = url.QueryUnescape(clientID)
+		secret, _ = url.QueryUnescape(secret)
+	} else {
+		clientID = r.PostForm.Get("client_id")
+	}
+	if clientID != i.ClientID || (i.ClientSecret != "" && subtle.ConstantTimeCompare([]byte(secret), []byte(i.ClientSecret)) != 1) {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	code := r.PostForm.Get("code")
+	i.mu.Lock()
+	a, ok := i.codes[code]
+	delete(i.codes, code)
+	i.mu.Unlock()
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || a.redirectURI != r.PostForm.Get("redirect_uri") || base64.RawURLEncoding.EncodeToString(sum[:]) != a.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{}
+	for k, v := range a.claims {
+		claims[k] = v
+	}
+	claims["iss"] = i.URL
+	claims["aud"] = i.ClientID
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(5 * time.Minute).Unix()
+	if a.nonce != "" {
+		claims["nonce"] = a.nonce
+	}
+	if i.IDTokenHook != nil {
+		i.IDTokenHook(claims)
+	}
+	tok := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	tok.Header["kid"] = kid
+	signed, err := tok.SignedString(i.key)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": aleatorio(),
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     signed,
+	})
+}
+
+func aleatorio() string {
+	b := make([]byte, 24)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
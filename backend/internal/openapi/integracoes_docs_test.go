@@ -22,6 +22,7 @@ func TestEmbeddedOpenAPISpec(t *testing.T) {
 		t.Fatalf("openapi version: got %q", doc.OpenAPI)
 	}
 	required := []string{
+		"/api/v1/integracoes/oauth/token",
 		"/api/v1/integracoes/me",
 		"/api/v1/integracoes/animais/search",
 		"/api/v1/integracoes/animais/{id}",
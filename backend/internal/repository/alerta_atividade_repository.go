@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AlertaAtividadeRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAlertaAtividadeRepository(db *pgxpool.Pool) *AlertaAtividadeRepository {
+	return &AlertaAtividadeRepository{db: db}
+}
+
+func (r *AlertaAtividadeRepository) Create(ctx context.Context, row *models.AlertaAtividade) error {
+	const q = `
+		INSERT INTO alertas_atividades (alerta_id, fazenda_id, usuario_id, tipo, texto, dados)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+	var dados interface{}
+	if len(row.Dados) > 0 {
+		dados = row.Dados
+	}
+	return r.db.QueryRow(ctx, q, row.AlertaID, row.FazendaID, row.UsuarioID, row.Tipo, row.Texto, dados).
+		Scan(&row.ID, &row.CreatedAt)
+}
+
+// ListByAlerta devolve o histórico do alerta em ordem cronológica.
+func (r *AlertaAtividadeRepository) ListByAlerta(ctx context.Context, fazendaID, alertaID int64) ([]*models.AlertaAtividade, error) {
+	const q = `
+		SELECT at.id, at.alerta_id, at.fazenda_id, at.usuario_id, u.nome, at.tipo, at.texto, at.dados, at.created_at
+		FROM alertas_atividades at
+		LEFT JOIN usuarios u ON u.id = at.usuario_id
+		WHERE at.fazenda_id = $1 AND at.alerta_id = $2
+		ORDER BY at.created_at ASC, at.id ASC
+	`
+	rows, err := r.db.Query(ctx, q, fazendaID, alertaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []*models.AlertaAtividade{}
+	for rows.Next() {
+		var a models.AlertaAtividade
+		var dados []byte
+		if err := rows.Scan(&a.ID, &a.AlertaID, &a.FazendaID, &a.UsuarioID, &a.UsuarioNome, &a.Tipo, &a.Texto, &dados, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		if len(dados) > 0 {
+			a.Dados = dados
+		}
+		out = append(out, &a)
+	}
+	return out, rows.Err()
+}
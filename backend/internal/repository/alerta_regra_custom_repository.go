@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AlertaRegraCustomRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAlertaRegraCustomRepository(db *pgxpool.Pool) *AlertaRegraCustomRepository {
+	return &AlertaRegraCustomRepository{db: db}
+}
+
+const alertaRegraCustomColumns = `id, fazenda_id, nome, descricao, expressao, severidade, ativo, push_perfis,
+	created_by, updated_by, created_at, updated_at`
+
+func scanAlertaRegraCustom(row pgx.Row) (*models.AlertaRegraCustom, error) {
+	var m models.AlertaRegraCustom
+	if err := row.Scan(&m.ID, &m.FazendaID, &m.Nome, &m.Descricao, &m.Expressao, &m.Severidade, &m.Ativo, &m.PushPerfis,
+		&m.CreatedBy, &m.UpdatedBy, &m.CreatedAt, &m.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// ListByFazendaID devolve as regras personalizadas da fazenda; apenasAtivas filtra as desligadas.
+func (r *AlertaRegraCustomRepository) ListByFazendaID(ctx context.Context, fazendaID int64, apenasAtivas bool) ([]*models.AlertaRegraCustom, error) {
+	q := `SELECT ` + alertaRegraCustomColumns + ` FROM alertas_regras_custom
+		WHERE fazenda_id = $1 AND ($2 = false OR ativo)
+		ORDER BY id ASC`
+	rows, err := r.db.Query(ctx, q, fazendaID, apenasAtivas)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	list := []*models.AlertaRegraCustom{}
+	for rows.Next() {
+		m, err := scanAlertaRegraCustom(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, m)
+	}
+	return list, rows.Err()
+}
+
+func (r *AlertaRegraCustomRepository) GetByID(ctx context.Context, fazendaID, id int64) (*models.AlertaRegraCustom, error) {
+	q := `SELECT ` + alertaRegraCustomColumns + ` FROM alertas_regras_custom WHERE id = $1 AND fazenda_id = $2`
+	return scanAlertaRegraCustom(r.db.QueryRow(ctx, q, id, fazendaID))
+}
+
+func (r *AlertaRegraCustomRepository) CountByFazendaID(ctx context.Context, fazendaID int64) (int, error) {
+	var n int
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM alertas_regras_custom WHERE fazenda_id = $1`, fazendaID).Scan(&n)
+	return n, err
+}
+
+func (r *AlertaRegraCustomRepository) Create(ctx context.Context, m *models.AlertaRegraCustom) error {
+	const q = `
+		INSERT INTO alertas_regras_custom (fazenda_id, nome, descricao, expressao, severidade, ativo, push_perfis, created_by, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+		RETURNING id, created_at, updated_at
+	`
+	return r.db.QueryRow(ctx, q, m.FazendaID, m.Nome, m.Descricao, m.Expressao, m.Severidade, m.Ativo, m.PushPerfis, m.CreatedBy).
+		Scan(&m.ID, &m.CreatedAt, &m.UpdatedAt)
+}
+
+func (r *AlertaRegraCustomRepository) Update(ctx context.Context, m *models.AlertaRegraCustom) error {
+	const q = `
+		UPDATE alertas_regras_custom
+		SET nome = $3, descricao = $4, expressao = $5, severidade = $6, ativo = $7, push_perfis = $8,
+		    updated_by = $9, updated_at = NOW()
+		WHERE id = $1 AND fazenda_id = $2
+		RETURNING updated_at
+	`
+	return r.db.QueryRow(ctx, q, m.ID, m.FazendaID, m.Nome, m.Descricao, m.Expressao, m.Severidade, m.Ativo, m.PushPerfis, m.UpdatedBy).
+		Scan(&m.UpdatedAt)
+}
+
+// Delete remove a regra; alertas já gerados ficam com regra_custom_id NULL.
+func (r *AlertaRegraCustomRepository) Delete(ctx context.Context, fazendaID, id int64) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM alertas_regras_custom WHERE id = $1 AND fazenda_id = $2`, id, fazendaID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// AlertaCustomMetricas são os atributos e factos de um animal ativo usados pelas expressões das regras
+// personalizadas. As métricas em dias são derivadas no service a partir das datas.
+type AlertaCustomMetricas struct {
+	AnimalID           int64
+	Identificacao      string
+	Sexo               *string
+	Categoria          *string
+	Raca               *string
+	StatusReprodutivo  *string
+	StatusSaude        *string
+	LoteNome           *string
+	DataNascimento     *time.Time
+	InicioLactacao     *time.Time // lactação em aberto
+	UltimoParto        *time.Time
+	UltimaCobertura    *time.Time
+	UltimoCio          *time.Time
+	NumeroPartos       int
+	Prenhe             bool // gestação CONFIRMADA
+	EmRestricaoLeite   bool
+	Producao7d         float64 // litros de refDate-6 a refDate
+	Producao7dAnterior float64 // litros de refDate-13 a refDate-7
+}
+
+// ListMetricasByFazenda devolve as métricas dos animais ativos (sem baixa) da fazenda em refDate.
+func (r *AlertaRegraCustomRepository) ListMetricasByFazenda(ctx context.Context, fazendaID int64, refDate time.Time) ([]*AlertaCustomMetricas, error) {
+	const q = `
+		SELECT a.id, a.identificacao, a.sexo, a.categoria, a.raca, a.status_reprodutivo, a.status_saude, l.nome,
+			a.data_nascimento,
+			(SELECT MAX(lc.data_inicio) FROM lactacoes lc
+				WHERE lc.animal_id = a.id AND lc.data_fim IS NULL AND lc.data_inicio <= $2::date),
+			(SELECT MAX(p.data) FROM partos p
+				WHERE p.animal_id = a.id AND p.excluido_em IS NULL AND p.data::date <= $2::date),
+			(SELECT MAX(cb.data) FROM coberturas cb
+				WHERE cb.animal_id = a.id AND cb.excluido_em IS NULL AND cb.data::date <= $2::date),
+			(SELECT MAX(c.data_detectado) FROM cios c
+				WHERE c.animal_id = a.id AND c.excluido_em IS NULL AND c.data_detectado::date <= $2::date),
+			(SELECT COUNT(*) FROM partos p WHERE p.animal_id = a.id AND p.excluido_em IS NULL),
+			EXISTS (SELECT 1 FROM gestacoes g WHERE g.animal_id = a.id AND g.status = $3),
+			EXISTS (
+				SELECT 1 FROM restricoes_leite rl
+				WHERE rl.animal_id = a.id AND rl.status = $4 AND rl.liberado_em IS NULL
+			),
+			COALESCE((
+				SELECT SUM(pl.quantidade) FROM producao_leite pl
+				WHERE pl.animal_id = a.id AND pl.excluido_em IS NULL
+				AND pl.data_hora >= $2::date - 6 AND pl.data_hora < $2::date + 1
+			), 0),
+			COALESCE((
+				SELECT SUM(pl.quantidade) FROM producao_leite pl
+				WHERE pl.animal_id = a.id AND pl.excluido_em IS NULL
+				AND pl.data_hora >= $2::date - 13 AND pl.data_hora < $2::date - 6
+			), 0)
+		FROM animais a
+		LEFT JOIN lotes l ON l.id = a.lote_id
+		WHERE a.fazenda_id = $1 AND a.data_saida IS NULL
+		ORDER BY a.identificacao ASC
+	`
+	rows, err := r.db.Query(ctx, q, fazendaID, refDate,
+		models.GestacaoStatusConfirmada, models.RestricaoLeiteStatusAguardandoLab)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	list := []*AlertaCustomMetricas{}
+	for rows.Next() {
+		var m AlertaCustomMetricas
+		if err := rows.Scan(&m.AnimalID, &m.Identificacao, &m.Sexo, &m.Categoria, &m.Raca, &m.StatusReprodutivo,
+			&m.StatusSaude, &m.LoteNome, &m.DataNascimento, &m.InicioLactacao, &m.UltimoParto, &m.UltimaCobertura,
+			&m.UltimoCio, &m.NumeroPartos, &m.Prenhe, &m.EmRestricaoLeite, &m.Producao7d, &m.Producao7dAnterior); err != nil {
+			return nil, err
+		}
+		list = append(list, &m)
+	}
+	return list, rows.Err()
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AlertaRegraRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAlertaRegraRepository(db *pgxpool.Pool) *AlertaRegraRepository {
+	return &AlertaRegraRepository{db: db}
+}
+
+// ListByFazendaID devolve as regras personalizadas da fazenda (tipos sem linha usam o padrão).
+func (r *AlertaRegraRepository) ListByFazendaID(ctx context.Context, fazendaID int64) ([]*models.AlertaRegraConfig, error) {
+	const q = `
+		SELECT fazenda_id, tipo, ativo, dias, severidade, push_perfis, updated_by, updated_at
+		FROM alertas_regras_config
+		WHERE fazenda_id = $1
+		ORDER BY tipo ASC
+	`
+	rows, err := r.db.Query(ctx, q, fazendaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var list []*models.AlertaRegraConfig
+	for rows.Next() {
+		var c models.AlertaRegraConfig
+		if err := rows.Scan(&c.FazendaID, &c.Tipo, &c.Ativo, &c.Dias, &c.Severidade, &c.PushPerfis, &c.UpdatedBy, &c.UpdatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, &c)
+	}
+	return list, rows.Err()
+}
+
+// Upsert grava a personalização da regra (PushPerfis nil = NULL = padrão).
+func (r *AlertaRegraRepository) Upsert(ctx context.Context, c *models.AlertaRegraConfig) error {
+	const q = `
+		INSERT INTO alertas_regras_config (fazenda_id, tipo, ativo, dias, severidade, push_perfis, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		ON CONFLICT (fazenda_id, tipo) DO UPDATE SET
+			ativo = EXCLUDED.ativo,
+			dias = EXCLUDED.dias,
+			severidade = EXCLUDED.severidade,
+			push_perfis = EXCLUDED.push_perfis,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at
+		RETURNING updated_at
+	`
+	return r.db.QueryRow(ctx, q, c.FazendaID, c.Tipo, c.Ativo, c.Dias, c.Severidade, c.PushPerfis, c.UpdatedBy).Scan(&c.UpdatedAt)
+}
+
+// Delete remove a personalização (a regra volta ao padrão).
+func (r *AlertaRegraRepository) Delete(ctx context.Context, fazendaID int64, tipo string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM alertas_regras_config WHERE fazenda_id = $1 AND tipo = $2`, fazendaID, tipo)
+	return err
+}
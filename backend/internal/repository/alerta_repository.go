@@ -18,8 +18,13 @@ type AlertaListFilters struct {
 	Severidade  string
 	PeriodStart *time.Time
 	PeriodEnd   *time.Time
-	Limit       int
-	Offset      int
+	// ResponsavelID filtra pelo responsável; SemResponsavel lista os não atribuídos (BR-ALERTA-023).
+	ResponsavelID  *int64
+	SemResponsavel bool
+	// Atrasados: ABERTO/EM_ANDAMENTO com prazo ultrapassado.
+	Atrasados bool
+	Limit     int
+	Offset    int
 }
 
 type AlertaRepository struct {
@@ -34,14 +39,18 @@ const alertaSelectWithNames = `
 	SELECT
 		a.id, a.fazenda_id, a.animal_id, a.tipo, a.severidade, a.titulo, a.descricao,
 		a.data_prevista, a.status, a.resolvido_por, a.resolvido_em, a.created_by,
+		a.regra_custom_id, a.responsavel_id, a.atribuido_em, a.prazo_em, a.escalonamento_nivel, a.escalonar_em,
 		a.created_at, a.updated_at,
 		an.identificacao AS animal_identificacao,
 		uc.nome AS created_by_nome,
-		ur.nome AS resolvido_por_nome
+		ur.nome AS resolvido_por_nome,
+		ures.nome AS responsavel_nome,
+		(a.status IN ('ABERTO', 'EM_ANDAMENTO') AND a.prazo_em IS NOT NULL AND a.prazo_em < NOW()) AS atrasado
 	FROM alertas a
 	LEFT JOIN animais an ON an.id = a.animal_id
 	LEFT JOIN usuarios uc ON uc.id = a.created_by
 	LEFT JOIN usuarios ur ON ur.id = a.resolvido_por
+	LEFT JOIN usuarios ures ON ures.id = a.responsavel_id
 `
 
 func (r *AlertaRepository) scanAlertaWithNames(row pgx.Row) (*models.AlertaWithNames, error) {
@@ -59,11 +68,19 @@ func (r *AlertaRepository) scanAlertaWithNames(row pgx.Row) (*models.AlertaWithN
 		&m.ResolvidoPor,
 		&m.ResolvidoEm,
 		&m.CreatedBy,
+		&m.RegraCustomID,
+		&m.ResponsavelID,
+		&m.AtribuidoEm,
+		&m.PrazoEm,
+		&m.EscalonamentoNivel,
+		&m.EscalonarEm,
 		&m.CreatedAt,
 		&m.UpdatedAt,
 		&m.AnimalIdentificacao,
 		&m.CreatedByNome,
 		&m.ResolvidoPorNome,
+		&m.ResponsavelNome,
+		&m.Atrasado,
 	)
 	if err != nil {
 		return nil, err
@@ -100,6 +117,17 @@ func buildAlertaListWhere(fazendaID int64, f AlertaListFilters) (string, []inter
 		)`, idx, idx+1, idx, idx+1)
 		conds = append(conds, periodCond)
 		args = append(args, *f.PeriodStart, *f.PeriodEnd)
+		idx += 2
+	}
+	if f.ResponsavelID != nil {
+		conds = append(conds, fmt.Sprintf("a.responsavel_id = $%d", idx))
+		args = append(args, *f.ResponsavelID)
+		idx++
+	} else if f.SemResponsavel {
+		conds = append(conds, "a.responsavel_id IS NULL")
+	}
+	if f.Atrasados {
+		conds = append(conds, "a.status IN ('ABERTO', 'EM_ANDAMENTO') AND a.prazo_em < NOW()")
 	}
 
 	return strings.Join(conds, " AND "), args
@@ -142,29 +170,11 @@ func (r *AlertaRepository) ListByFazenda(ctx context.Context, fazendaID int64, f
 
 	var out []models.AlertaWithNames
 	for rows.Next() {
-		var m models.AlertaWithNames
-		if err := rows.Scan(
-			&m.ID,
-			&m.FazendaID,
-			&m.AnimalID,
-			&m.Tipo,
-			&m.Severidade,
-			&m.Titulo,
-			&m.Descricao,
-			&m.DataPrevista,
-			&m.Status,
-			&m.ResolvidoPor,
-			&m.ResolvidoEm,
-			&m.CreatedBy,
-			&m.CreatedAt,
-			&m.UpdatedAt,
-			&m.AnimalIdentificacao,
-			&m.CreatedByNome,
-			&m.ResolvidoPorNome,
-		); err != nil {
+		m, err := r.scanAlertaWithNames(rows)
+		if err != nil {
 			return nil, 0, err
 		}
-		out = append(out, m)
+		out = append(out, *m)
 	}
 	if out == nil {
 		out = []models.AlertaWithNames{}
@@ -184,13 +194,24 @@ func (r *AlertaRepository) GetByID(ctx context.Context, fazendaID, alertaID int6
 	return m, nil
 }
 
+// Create insere o alerta; sem PrazoEm, o prazo é o SLA da severidade a contar de agora e o primeiro
+// escalonamento coincide com o prazo.
 func (r *AlertaRepository) Create(ctx context.Context, row *models.Alerta) error {
+	if row.PrazoEm == nil {
+		prazo := time.Now().Add(models.AlertaSLA(row.Severidade))
+		row.PrazoEm = &prazo
+	}
+	if row.EscalonarEm == nil && row.Status == models.AlertaStatusAberto {
+		row.EscalonarEm = row.PrazoEm
+	}
 	const q = `
 		INSERT INTO alertas (
 			fazenda_id, animal_id, tipo, severidade, titulo, descricao,
-			data_prevista, status, created_by
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-		RETURNING id, created_at, updated_at
+			data_prevista, status, created_by, regra_custom_id, responsavel_id, prazo_em, escalonar_em,
+			atribuido_em
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13,
+			CASE WHEN $11::BIGINT IS NULL THEN NULL ELSE NOW() END)
+		RETURNING id, atribuido_em, created_at, updated_at
 	`
 	return r.db.QueryRow(ctx, q,
 		row.FazendaID,
@@ -202,7 +223,11 @@ func (r *AlertaRepository) Create(ctx context.Context, row *models.Alerta) error
 		row.DataPrevista,
 		row.Status,
 		row.CreatedBy,
-	).Scan(&row.ID, &row.CreatedAt, &row.UpdatedAt)
+		row.RegraCustomID,
+		row.ResponsavelID,
+		row.PrazoEm,
+		row.EscalonarEm,
+	).Scan(&row.ID, &row.AtribuidoEm, &row.CreatedAt, &row.UpdatedAt)
 }
 
 func (r *AlertaRepository) UpdateStatus(ctx context.Context, fazendaID, alertaID int64, status string, resolvidoPor *int64, resolvidoEm *time.Time) error {
@@ -211,6 +236,7 @@ func (r *AlertaRepository) UpdateStatus(ctx context.Context, fazendaID, alertaID
 		SET status = $3,
 		    resolvido_por = $4,
 		    resolvido_em = $5,
+		    escalonar_em = CASE WHEN $3 = 'ABERTO' THEN escalonar_em ELSE NULL END,
 		    updated_at = NOW()
 		WHERE id = $1 AND fazenda_id = $2
 	`
@@ -274,3 +300,123 @@ func (r *AlertaRepository) CountCriticosAbertosByFazenda(ctx context.Context, fa
 	err := r.db.QueryRow(ctx, q, fazendaID).Scan(&n)
 	return n, err
 }
+
+// CountAbertosPorTipoByFazenda conta alertas ABERTO/EM_ANDAMENTO por tipo e severidade, mais graves primeiro.
+func (r *AlertaRepository) CountAbertosPorTipoByFazenda(ctx context.Context, fazendaID int64) ([]models.ResumoAlertasTipo, error) {
+	const q = `
+		SELECT tipo, severidade, COUNT(*)::int
+		FROM alertas
+		WHERE fazenda_id = $1
+		  AND status IN ('ABERTO', 'EM_ANDAMENTO')
+		GROUP BY tipo, severidade
+		ORDER BY CASE severidade WHEN 'CRITICA' THEN 0 WHEN 'ALTA' THEN 1 WHEN 'MEDIA' THEN 2 ELSE 3 END,
+			COUNT(*) DESC, tipo
+	`
+	rows, err := r.db.Query(ctx, q, fazendaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []models.ResumoAlertasTipo{}
+	for rows.Next() {
+		var t models.ResumoAlertasTipo
+		if err := rows.Scan(&t.Tipo, &t.Severidade, &t.Total); err != nil {
+			return nil, err
+		}
+		t.Label = models.LabelTipoAlerta(t.Tipo)
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+// ListMaisUrgentesAbertosByFazenda devolve os alertas ABERTO/EM_ANDAMENTO mais graves; dentro da mesma
+// severidade, data prevista mais próxima e depois os mais antigos.
+func (r *AlertaRepository) ListMaisUrgentesAbertosByFazenda(ctx context.Context, fazendaID int64, limit int) ([]models.AlertaWithNames, error) {
+	q := alertaSelectWithNames + `
+		WHERE a.fazenda_id = $1
+		  AND a.status IN ('ABERTO', 'EM_ANDAMENTO')
+		ORDER BY
+			CASE a.severidade WHEN 'CRITICA' THEN 0 WHEN 'ALTA' THEN 1 WHEN 'MEDIA' THEN 2 ELSE 3 END,
+			a.data_prevista ASC NULLS LAST,
+			a.created_at ASC
+		LIMIT $2
+	`
+	rows, err := r.db.Query(ctx, q, fazendaID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []models.AlertaWithNames{}
+	for rows.Next() {
+		m, err := r.scanAlertaWithNames(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *m)
+	}
+	return out, rows.Err()
+}
+
+// UpdateResponsavel define (ou remove, com nil) o responsável do alerta.
+func (r *AlertaRepository) UpdateResponsavel(ctx context.Context, fazendaID, alertaID int64, responsavelID *int64) error {
+	const q = `
+		UPDATE alertas
+		SET responsavel_id = $3,
+		    atribuido_em = CASE WHEN $3::BIGINT IS NULL THEN NULL ELSE NOW() END,
+		    updated_at = NOW()
+		WHERE id = $1 AND fazenda_id = $2
+	`
+	tag, err := r.db.Exec(ctx, q, alertaID, fazendaID, responsavelID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// ListParaEscalonar devolve alertas ainda ABERTO cujo próximo escalonamento já venceu (todas as fazendas).
+func (r *AlertaRepository) ListParaEscalonar(ctx context.Context, agora time.Time, limit int) ([]models.AlertaWithNames, error) {
+	q := alertaSelectWithNames + `
+		WHERE a.status = 'ABERTO'
+		  AND a.escalonar_em IS NOT NULL
+		  AND a.escalonar_em <= $1
+		ORDER BY a.escalonar_em ASC
+		LIMIT $2
+	`
+	rows, err := r.db.Query(ctx, q, agora, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []models.AlertaWithNames{}
+	for rows.Next() {
+		m, err := r.scanAlertaWithNames(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *m)
+	}
+	return out, rows.Err()
+}
+
+// MarcarEscalonado sobe o alerta para nivel e agenda o próximo escalonamento (nil = último nível).
+// Só aplica se o alerta continua ABERTO no nível anterior, para que duas instâncias do cron não
+// escalonem o mesmo alerta; false quando outra já o fez.
+func (r *AlertaRepository) MarcarEscalonado(ctx context.Context, fazendaID, alertaID int64, nivel int, proximo *time.Time) (bool, error) {
+	const q = `
+		UPDATE alertas
+		SET escalonamento_nivel = $3,
+		    escalonar_em = $4,
+		    updated_at = NOW()
+		WHERE id = $1 AND fazenda_id = $2
+		  AND status = 'ABERTO'
+		  AND escalonamento_nivel = $3 - 1
+	`
+	tag, err := r.db.Exec(ctx, q, alertaID, fazendaID, nivel, proximo)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -34,3 +35,31 @@ func TestBuildAlertaListWhere_PeriodoOR(t *testing.T) {
 		t.Fatalf("unexpected period end: %v", args[3])
 	}
 }
+
+func TestBuildAlertaListWhere_ResponsavelEAtrasados(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	uid := int64(7)
+
+	where, args := buildAlertaListWhere(10, AlertaListFilters{
+		PeriodStart:   &start,
+		PeriodEnd:     &end,
+		ResponsavelID: &uid,
+		Atrasados:     true,
+	})
+
+	if len(args) != 4 || args[3] != uid {
+		t.Fatalf("unexpected args: %v", args)
+	}
+	if !strings.Contains(where, "a.responsavel_id = $4") {
+		t.Fatalf("responsavel placeholder after period: %s", where)
+	}
+	if !strings.Contains(where, "a.prazo_em < NOW()") {
+		t.Fatalf("missing overdue condition: %s", where)
+	}
+
+	where, args = buildAlertaListWhere(10, AlertaListFilters{SemResponsavel: true})
+	if len(args) != 1 || !strings.Contains(where, "a.responsavel_id IS NULL") {
+		t.Fatalf("sem responsavel: %s %v", where, args)
+	}
+}
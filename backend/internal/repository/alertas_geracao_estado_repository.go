@@ -13,6 +13,7 @@ type AlertasGeracaoEstado struct {
 	FazendaID           int64
 	UltimaExecucao      time.Time
 	ConformidadeChaves  []string
+	CustomChaves        []string // regra:animal das regras personalizadas que casaram na última execução
 }
 
 type AlertasGeracaoEstadoRepository struct {
@@ -25,13 +26,13 @@ func NewAlertasGeracaoEstadoRepository(db *pgxpool.Pool) *AlertasGeracaoEstadoRe
 
 func (r *AlertasGeracaoEstadoRepository) Get(ctx context.Context, fazendaID int64) (*AlertasGeracaoEstado, error) {
 	const q = `
-		SELECT fazenda_id, ultima_execucao, conformidade_chaves
+		SELECT fazenda_id, ultima_execucao, conformidade_chaves, custom_chaves
 		FROM alertas_geracao_estado
 		WHERE fazenda_id = $1
 	`
-	var raw []byte
+	var raw, rawCustom []byte
 	var e AlertasGeracaoEstado
-	err := r.db.QueryRow(ctx, q, fazendaID).Scan(&e.FazendaID, &e.UltimaExecucao, &raw)
+	err := r.db.QueryRow(ctx, q, fazendaID).Scan(&e.FazendaID, &e.UltimaExecucao, &raw, &rawCustom)
 	if err == pgx.ErrNoRows {
 		return nil, nil
 	}
@@ -43,9 +44,17 @@ func (r *AlertasGeracaoEstadoRepository) Get(ctx context.Context, fazendaID int6
 			return nil, err
 		}
 	}
+	if len(rawCustom) > 0 {
+		if err := json.Unmarshal(rawCustom, &e.CustomChaves); err != nil {
+			return nil, err
+		}
+	}
 	if e.ConformidadeChaves == nil {
 		e.ConformidadeChaves = []string{}
 	}
+	if e.CustomChaves == nil {
+		e.CustomChaves = []string{}
+	}
 	return &e, nil
 }
 
@@ -67,3 +76,22 @@ func (r *AlertasGeracaoEstadoRepository) Upsert(ctx context.Context, fazendaID i
 	_, err = r.db.Exec(ctx, q, fazendaID, execucao, raw)
 	return err
 }
+
+// UpsertCustomChaves grava as chaves das regras personalizadas sem tocar nas de conformidade.
+func (r *AlertasGeracaoEstadoRepository) UpsertCustomChaves(ctx context.Context, fazendaID int64, chaves []string) error {
+	if chaves == nil {
+		chaves = []string{}
+	}
+	raw, err := json.Marshal(chaves)
+	if err != nil {
+		return err
+	}
+	const q = `
+		INSERT INTO alertas_geracao_estado (fazenda_id, custom_chaves)
+		VALUES ($1, $2)
+		ON CONFLICT (fazenda_id) DO UPDATE SET
+			custom_chaves = EXCLUDED.custom_chaves
+	`
+	_, err = r.db.Exec(ctx, q, fazendaID, raw)
+	return err
+}
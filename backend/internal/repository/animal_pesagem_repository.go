@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AnimalPesagemRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAnimalPesagemRepository(db *pgxpool.Pool) *AnimalPesagemRepository {
+	return &AnimalPesagemRepository{db: db}
+}
+
+func (r *AnimalPesagemRepository) Create(ctx context.Context, p *models.AnimalPesagem) error {
+	const q = `
+		INSERT INTO animal_pesagens (animal_id, fazenda_id, data, peso_kg, observacao, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+	return r.db.QueryRow(ctx, q, p.AnimalID, p.FazendaID, p.Data, p.PesoKg, p.Observacao, p.CreatedBy).Scan(&p.ID, &p.CreatedAt)
+}
+
+// ListByAnimal pesagens do animal, da mais recente para a mais antiga.
+func (r *AnimalPesagemRepository) ListByAnimal(ctx context.Context, animalID int64) ([]*models.AnimalPesagem, error) {
+	const q = `
+		SELECT id, animal_id, fazenda_id, data, peso_kg, observacao, created_by, created_at
+		FROM animal_pesagens
+		WHERE animal_id = $1
+		ORDER BY data DESC, id DESC
+	`
+	rows, err := r.db.Query(ctx, q, animalID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []*models.AnimalPesagem{}
+	for rows.Next() {
+		var p models.AnimalPesagem
+		if err := rows.Scan(&p.ID, &p.AnimalID, &p.FazendaID, &p.Data, &p.PesoKg, &p.Observacao, &p.CreatedBy, &p.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, &p)
+	}
+	return out, rows.Err()
+}
+
+// Delete remove a pesagem do animal; pgx.ErrNoRows se não existir.
+func (r *AnimalPesagemRepository) Delete(ctx context.Context, animalID, id int64) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM animal_pesagens WHERE id = $1 AND animal_id = $2`, id, animalID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
@@ -141,6 +141,40 @@ func (r *AnimalRepository) CreateTx(ctx context.Context, tx pgx.Tx, animal *mode
 	).Scan(&animal.ID, &animal.CreatedAt, &animal.UpdatedAt)
 }
 
+// RestoreTx reinsere um animal removido mantendo id e carimbos originais (restauração da lixeira).
+func (r *AnimalRepository) RestoreTx(ctx context.Context, tx pgx.Tx, animal *models.Animal) error {
+	query := `
+		INSERT INTO animais (id, identificacao, raca, data_nascimento, sexo, status_saude, fazenda_id, categoria, status_reprodutivo, mae_id, pai_info, lote_id, peso_nascimento, data_entrada, data_saida, motivo_saida, observacao_saida, origem_aquisicao, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+	`
+	_, err := tx.Exec(
+		ctx,
+		query,
+		animal.ID,
+		animal.Identificacao,
+		animal.Raca,
+		animal.DataNascimento,
+		animal.Sexo,
+		animal.StatusSaude,
+		animal.FazendaID,
+		animal.Categoria,
+		animal.StatusReprodutivo,
+		animal.MaeID,
+		animal.PaiInfo,
+		animal.LoteID,
+		animal.PesoNascimento,
+		animal.DataEntrada,
+		animal.DataSaida,
+		animal.MotivoSaida,
+		animal.ObservacaoSaida,
+		animal.OrigemAquisicao,
+		animal.CreatedBy,
+		animal.CreatedAt,
+		animal.UpdatedAt,
+	)
+	return err
+}
+
 func (r *AnimalRepository) GetByID(ctx context.Context, id int64) (*models.Animal, error) {
 	query := fmt.Sprintf(`SELECT %s FROM animais WHERE id = $1`, animalSelectColumns)
 
@@ -256,7 +290,8 @@ func (r *AnimalRepository) ListParaCoberturaByFazendaID(ctx context.Context, faz
 		AND EXISTS (
 			SELECT 1 FROM cios ci
 			WHERE ci.animal_id = a.id AND ci.fazenda_id = a.fazenda_id
-			AND NOT EXISTS (SELECT 1 FROM coberturas cb WHERE cb.cio_id = ci.id)
+			AND ci.excluido_em IS NULL
+			AND NOT EXISTS (SELECT 1 FROM coberturas cb WHERE cb.cio_id = ci.id AND cb.excluido_em IS NULL)
 		)
 		ORDER BY a.identificacao ASC
 	`, animalSelectWithPrefix("a"))
@@ -275,6 +310,7 @@ func (r *AnimalRepository) ListParaToqueByFazendaID(ctx context.Context, fazenda
 		AND EXISTS (
 			SELECT 1 FROM coberturas cb
 			WHERE cb.animal_id = a.id AND cb.fazenda_id = a.fazenda_id
+			AND cb.excluido_em IS NULL
 			AND cb.data <= CURRENT_TIMESTAMP - ($3 * interval '1 day')
 			AND NOT EXISTS (
 				SELECT 1 FROM diagnosticos_gestacao dg WHERE dg.cobertura_id = cb.id
@@ -298,7 +334,7 @@ func (r *AnimalRepository) ListParaPartoByFazendaID(ctx context.Context, fazenda
 			SELECT 1 FROM gestacoes g
 			WHERE g.animal_id = a.id AND g.fazenda_id = a.fazenda_id
 			AND g.status = $3
-			AND NOT EXISTS (SELECT 1 FROM partos p WHERE p.gestacao_id = g.id)
+			AND NOT EXISTS (SELECT 1 FROM partos p WHERE p.gestacao_id = g.id AND p.excluido_em IS NULL)
 		)
 		ORDER BY a.identificacao ASC
 	`, animalSelectWithPrefix("a"))
@@ -442,6 +478,60 @@ func (r *AnimalRepository) UpdateStatusReprodutivoTx(ctx context.Context, tx pgx
 	return err
 }
 
+// MarcoReprodutivo é o evento mais recente que define o status reprodutivo (BR-CICLO-002).
+// Tipo: CIO, COBERTURA, TOQUE, PARTO ou SECAGEM; Resultado só para TOQUE.
+// GestacaoAtiva indica gestação CONFIRMADA em curso no momento da consulta.
+type MarcoReprodutivo struct {
+	Tipo          string
+	Data          time.Time
+	Resultado     string
+	GestacaoAtiva bool
+}
+
+// sqlMarcosReprodutivos devolve a união dos marcos reprodutivos (tipo, data, resultado, ordem) do animal
+// animalRef, ignorando registos na lixeira; resultadoPos/resultadoNeg são os placeholders dos toques aceites.
+// ordem desempata marcos na mesma data (o maior prevalece).
+func sqlMarcosReprodutivos(animalRef, resultadoPos, resultadoNeg string) string {
+	return fmt.Sprintf(`
+			SELECT 'CIO' AS tipo, data_detectado AS data, '' AS resultado, 1 AS ordem
+			FROM cios WHERE animal_id = %[1]s AND excluido_em IS NULL
+			UNION ALL
+			SELECT 'COBERTURA', data, '', 2
+			FROM coberturas WHERE animal_id = %[1]s AND excluido_em IS NULL
+			UNION ALL
+			SELECT 'TOQUE', data, resultado, 3
+			FROM diagnosticos_gestacao WHERE animal_id = %[1]s AND resultado IN (%[2]s, %[3]s)
+			UNION ALL
+			SELECT 'PARTO', data, '', 4
+			FROM partos WHERE animal_id = %[1]s AND excluido_em IS NULL
+			UNION ALL
+			SELECT 'SECAGEM', data_secagem::timestamp, '', 5
+			FROM secagens WHERE animal_id = %[1]s`, animalRef, resultadoPos, resultadoNeg)
+}
+
+// GetUltimoMarcoReprodutivo devolve o marco mais recente do animal, ignorando registos na lixeira
+// e toques inconclusivos. Retorna nil quando o animal não tem marcos.
+func (r *AnimalRepository) GetUltimoMarcoReprodutivo(ctx context.Context, animalID int64) (*MarcoReprodutivo, error) {
+	query := `
+		SELECT tipo, data, resultado,
+			EXISTS (SELECT 1 FROM gestacoes g WHERE g.animal_id = $1 AND g.status = $4)
+		FROM (` + sqlMarcosReprodutivos("$1", "$2", "$3") + `
+		) m
+		ORDER BY data DESC, ordem DESC
+		LIMIT 1
+	`
+	var m MarcoReprodutivo
+	err := r.db.QueryRow(ctx, query, animalID, models.DiagnosticoResultadoPositivo, models.DiagnosticoResultadoNegativo, models.GestacaoStatusConfirmada).
+		Scan(&m.Tipo, &m.Data, &m.Resultado, &m.GestacaoAtiva)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
 func (r *AnimalRepository) UpdateCategoria(ctx context.Context, animalID int64, categoria *string) error {
 	query := `UPDATE animais SET categoria = $1, updated_at = $2 WHERE id = $3`
 	_, err := r.db.Exec(ctx, query, categoria, time.Now(), animalID)
@@ -736,6 +826,25 @@ func (r *AnimalRepository) ExistsByIdentificacaoTx(ctx context.Context, tx pgx.T
 	return exists, err
 }
 
+// GetByIDsTx carrega e bloqueia os animais (movimentação em lote); ids inexistentes ficam de fora.
+func (r *AnimalRepository) GetByIDsTx(ctx context.Context, tx pgx.Tx, ids []int64) ([]*models.Animal, error) {
+	query := fmt.Sprintf(`SELECT %s FROM animais WHERE id = ANY($1) ORDER BY id FOR UPDATE`, animalSelectColumns)
+	rows, err := tx.Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var list []*models.Animal
+	for rows.Next() {
+		var a models.Animal
+		if err := scanAnimal(&a, rows); err != nil {
+			return nil, err
+		}
+		list = append(list, &a)
+	}
+	return list, rows.Err()
+}
+
 func (r *AnimalRepository) queryList(ctx context.Context, query string, args ...interface{}) ([]*models.Animal, error) {
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
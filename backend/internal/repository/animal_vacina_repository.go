@@ -214,3 +214,33 @@ func (r *AnimalVacinaRepository) queryAlertaAnimais(ctx context.Context, q strin
 	}
 	return out, rows.Err()
 }
+
+// ListPrevistasAteByFazendaID lista vacinas previstas e não aplicadas até à data (inclui atrasadas),
+// para animais no rebanho (agenda do resumo de alertas — BR-ALERTA-022).
+func (r *AnimalVacinaRepository) ListPrevistasAteByFazendaID(ctx context.Context, fazendaID int64, ate time.Time) ([]models.VacinaPrevistaResumo, error) {
+	q := `
+		SELECT a.id, a.identificacao, v.tipo_vacina, v.data_prevista
+		FROM animal_vacinas v
+		INNER JOIN animais a ON a.id = v.animal_id
+		WHERE v.fazenda_id = $1
+		  AND v.data_aplicacao IS NULL
+		  AND v.data_prevista <= $2::date
+		  AND ` + SQLNoRebanhoFor("a") + `
+		ORDER BY v.data_prevista ASC, a.identificacao ASC
+		LIMIT 200
+	`
+	rows, err := r.db.Query(ctx, q, fazendaID, ate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []models.VacinaPrevistaResumo{}
+	for rows.Next() {
+		var item models.VacinaPrevistaResumo
+		if err := rows.Scan(&item.AnimalID, &item.Identificacao, &item.TipoVacina, &item.DataPrevista); err != nil {
+			return nil, err
+		}
+		out = append(out, item)
+	}
+	return out, rows.Err()
+}
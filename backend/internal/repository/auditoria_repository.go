@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AuditoriaRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAuditoriaRepository(db *pgxpool.Pool) *AuditoriaRepository {
+	return &AuditoriaRepository{db: db}
+}
+
+// Insert grava o evento; sem fazenda_id explícito usa a fazenda do animal.
+func (r *AuditoriaRepository) Insert(ctx context.Context, e *models.AuditoriaEvento) error {
+	query := `
+		INSERT INTO auditoria_eventos (usuario_id, perfil, fazenda_id, animal_id, entidade, entidade_id, acao, diff, correlation_id)
+		VALUES ($1, $2, COALESCE($3::bigint, (SELECT fazenda_id FROM animais WHERE id = $4::bigint)), $4, $5, $6, $7, $8, $9)
+		RETURNING id, fazenda_id, created_at
+	`
+	return r.db.QueryRow(ctx, query,
+		e.UsuarioID, e.Perfil, e.FazendaID, e.AnimalID, e.Entidade, e.EntidadeID, e.Acao, e.Diff, e.CorrelationID,
+	).Scan(&e.ID, &e.FazendaID, &e.CreatedAt)
+}
+
+// List consulta eventos por animal, utilizador e/ou fazenda (mais recentes primeiro) e devolve o total do filtro.
+func (r *AuditoriaRepository) List(ctx context.Context, f models.AuditoriaFiltro) ([]*models.AuditoriaEvento, int64, error) {
+	var conds []string
+	var args []interface{}
+	add := func(cond string, v interface{}) {
+		args = append(args, v)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	if f.AnimalID > 0 {
+		add("e.animal_id = $%d", f.AnimalID)
+	}
+	if f.UsuarioID > 0 {
+		add("e.usuario_id = $%d", f.UsuarioID)
+	}
+	if f.FazendaID > 0 {
+		add("e.fazenda_id = $%d", f.FazendaID)
+	}
+	if f.Entidade != "" {
+		add("e.entidade = $%d", f.Entidade)
+	}
+	if f.Acao != "" {
+		add("e.acao = $%d", f.Acao)
+	}
+	if f.De != nil {
+		add("e.created_at >= $%d", *f.De)
+	}
+	if f.Ate != nil {
+		add("e.created_at < $%d", *f.Ate)
+	}
+	where := ""
+	if len(conds) > 0 {
+		where = "WHERE " + strings.Join(conds, " AND ")
+	}
+	limit := f.Limit
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	args = append(args, limit, f.Offset)
+	query := fmt.Sprintf(`
+		SELECT e.id, e.usuario_id, u.nome, e.perfil, e.fazenda_id, e.animal_id, e.entidade, e.entidade_id, e.acao,
+			e.diff, e.correlation_id, e.created_at, COUNT(*) OVER()
+		FROM auditoria_eventos e
+		LEFT JOIN usuarios u ON u.id = e.usuario_id
+		%s
+		ORDER BY e.created_at DESC, e.id DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)-1, len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	var out []*models.AuditoriaEvento
+	var total int64
+	for rows.Next() {
+		var e models.AuditoriaEvento
+		if err := rows.Scan(
+			&e.ID, &e.UsuarioID, &e.UsuarioNome, &e.Perfil, &e.FazendaID, &e.AnimalID, &e.Entidade, &e.EntidadeID, &e.Acao,
+			&e.Diff, &e.CorrelationID, &e.CreatedAt, &total,
+		); err != nil {
+			return nil, 0, err
+		}
+		out = append(out, &e)
+	}
+	return out, total, rows.Err()
+}
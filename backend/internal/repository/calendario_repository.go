@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CalendarioRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCalendarioRepository(db *pgxpool.Pool) *CalendarioRepository {
+	return &CalendarioRepository{db: db}
+}
+
+const calendarioFeedSelect = `
+	SELECT c.id, c.usuario_id, c.fazenda_id, COALESCE(f.nome, ''), c.escopo, c.token_prefixo,
+	       c.created_at, c.ultimo_uso_em
+	FROM calendario_feeds c
+	LEFT JOIN fazendas f ON f.id = c.fazenda_id
+`
+
+func scanCalendarioFeed(row pgx.Row) (*models.CalendarioFeed, error) {
+	var f models.CalendarioFeed
+	if err := row.Scan(&f.ID, &f.UsuarioID, &f.FazendaID, &f.FazendaNome, &f.Escopo, &f.TokenPrefixo,
+		&f.CreatedAt, &f.UltimoUsoEm); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// Create grava a assinatura com o hash do token; preenche ID e CreatedAt.
+func (r *CalendarioRepository) Create(ctx context.Context, f *models.CalendarioFeed, tokenHash string) error {
+	return r.db.QueryRow(ctx, `
+		INSERT INTO calendario_feeds (usuario_id, fazenda_id, escopo, token_hash, token_prefixo)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`, f.UsuarioID, f.FazendaID, f.Escopo, tokenHash, f.TokenPrefixo).Scan(&f.ID, &f.CreatedAt)
+}
+
+func (r *CalendarioRepository) ListByUsuario(ctx context.Context, usuarioID int64) ([]models.CalendarioFeed, error) {
+	rows, err := r.db.Query(ctx, calendarioFeedSelect+`WHERE c.usuario_id = $1 ORDER BY c.created_at, c.id`, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []models.CalendarioFeed{}
+	for rows.Next() {
+		f, err := scanCalendarioFeed(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *f)
+	}
+	return out, rows.Err()
+}
+
+func (r *CalendarioRepository) CountByUsuario(ctx context.Context, usuarioID int64) (int, error) {
+	var n int
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM calendario_feeds WHERE usuario_id = $1`, usuarioID).Scan(&n)
+	return n, err
+}
+
+// GetByTokenHash resolve a assinatura pelo hash do token; pgx.ErrNoRows se não existir (ou foi revogada).
+func (r *CalendarioRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.CalendarioFeed, error) {
+	return scanCalendarioFeed(r.db.QueryRow(ctx, calendarioFeedSelect+`WHERE c.token_hash = $1`, tokenHash))
+}
+
+func (r *CalendarioRepository) TouchUltimoUso(ctx context.Context, id int64, em time.Time) error {
+	_, err := r.db.Exec(ctx, `UPDATE calendario_feeds SET ultimo_uso_em = $2 WHERE id = $1`, id, em)
+	return err
+}
+
+// Delete revoga uma assinatura do usuário; pgx.ErrNoRows se não for dele ou não existir.
+func (r *CalendarioRepository) Delete(ctx context.Context, id, usuarioID int64) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM calendario_feeds WHERE id = $1 AND usuario_id = $2`, id, usuarioID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteByUsuario revoga todas as assinaturas do usuário e devolve quantas eram.
+func (r *CalendarioRepository) DeleteByUsuario(ctx context.Context, usuarioID int64) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM calendario_feeds WHERE usuario_id = $1`, usuarioID)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
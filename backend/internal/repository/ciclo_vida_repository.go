@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CicloVidaRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCicloVidaRepository(db *pgxpool.Pool) *CicloVidaRepository {
+	return &CicloVidaRepository{db: db}
+}
+
+// GetConfig devolve a configuração personalizada da fazenda; nil, nil quando usa o padrão.
+func (r *CicloVidaRepository) GetConfig(ctx context.Context, fazendaID int64) (*models.CicloVidaConfig, error) {
+	const q = `
+		SELECT fazenda_id, novilha_idade_meses, novilha_peso_kg, macho_idade_meses, macho_categoria, pre_parto_dias, updated_by, updated_at
+		FROM ciclo_vida_config
+		WHERE fazenda_id = $1
+	`
+	var c models.CicloVidaConfig
+	err := r.db.QueryRow(ctx, q, fazendaID).Scan(
+		&c.FazendaID, &c.NovilhaIdadeMeses, &c.NovilhaPesoKg, &c.MachoIdadeMeses, &c.MachoCategoria, &c.PrePartoDias, &c.UpdatedBy, &c.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.Personalizada = true
+	return &c, nil
+}
+
+func (r *CicloVidaRepository) UpsertConfig(ctx context.Context, c *models.CicloVidaConfig) error {
+	const q = `
+		INSERT INTO ciclo_vida_config (fazenda_id, novilha_idade_meses, novilha_peso_kg, macho_idade_meses, macho_categoria, pre_parto_dias, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		ON CONFLICT (fazenda_id) DO UPDATE SET
+			novilha_idade_meses = EXCLUDED.novilha_idade_meses,
+			novilha_peso_kg = EXCLUDED.novilha_peso_kg,
+			macho_idade_meses = EXCLUDED.macho_idade_meses,
+			macho_categoria = EXCLUDED.macho_categoria,
+			pre_parto_dias = EXCLUDED.pre_parto_dias,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at
+		RETURNING updated_at
+	`
+	return r.db.QueryRow(ctx, q, c.FazendaID, c.NovilhaIdadeMeses, c.NovilhaPesoKg, c.MachoIdadeMeses, c.MachoCategoria, c.PrePartoDias, c.UpdatedBy).
+		Scan(&c.UpdatedAt)
+}
+
+// ListAnimais animais no rebanho da fazenda com o necessário para as transições: tipo do lote atual,
+// última pesagem, parto registado (fora da lixeira) e gestação confirmada em curso.
+func (r *CicloVidaRepository) ListAnimais(ctx context.Context, fazendaID int64) ([]models.AnimalCicloVida, error) {
+	const q = `
+		SELECT a.id, a.fazenda_id, a.identificacao, a.sexo, a.categoria, a.data_nascimento, a.lote_id, l.tipo,
+			pes.peso_kg,
+			EXISTS (SELECT 1 FROM partos p WHERE p.animal_id = a.id AND p.excluido_em IS NULL),
+			g.id, g.data_prevista_parto
+		FROM animais a
+		LEFT JOIN lotes l ON l.id = a.lote_id
+		LEFT JOIN LATERAL (
+			SELECT peso_kg::float8 AS peso_kg FROM animal_pesagens
+			WHERE animal_id = a.id ORDER BY data DESC, id DESC LIMIT 1
+		) pes ON TRUE
+		LEFT JOIN LATERAL (
+			SELECT id, data_prevista_parto FROM gestacoes
+			WHERE animal_id = a.id AND status = $2 AND data_prevista_parto IS NOT NULL
+			ORDER BY data_prevista_parto DESC LIMIT 1
+		) g ON TRUE
+		WHERE a.fazenda_id = $1 AND (a.data_saida IS NULL OR a.data_saida > CURRENT_DATE)
+		ORDER BY a.id
+	`
+	rows, err := r.db.Query(ctx, q, fazendaID, models.GestacaoStatusConfirmada)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []models.AnimalCicloVida
+	for rows.Next() {
+		var a models.AnimalCicloVida
+		if err := rows.Scan(
+			&a.ID, &a.FazendaID, &a.Identificacao, &a.Sexo, &a.Categoria, &a.DataNascimento, &a.LoteID, &a.LoteTipo,
+			&a.PesoKg, &a.TemParto, &a.GestacaoID, &a.DataPrevistaParto,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+const propostaLoteSelect = `
+	SELECT p.id, p.fazenda_id, p.animal_id, a.identificacao, p.lote_origem_id, lo.nome, p.lote_destino_id, ld.nome,
+		p.motivo, p.referencia, p.descricao, p.status, p.movimentacao_id, p.decidido_por, p.decidido_em, p.created_at
+	FROM movimentacoes_lote_propostas p
+	JOIN animais a ON a.id = p.animal_id
+	JOIN lotes ld ON ld.id = p.lote_destino_id
+	LEFT JOIN lotes lo ON lo.id = p.lote_origem_id
+`
+
+// ListPropostas propostas da fazenda (status vazio = todas), mais recentes primeiro.
+func (r *CicloVidaRepository) ListPropostas(ctx context.Context, fazendaID int64, status string, limit int) ([]*models.MovimentacaoLoteProposta, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 200
+	}
+	return r.queryPropostas(ctx, propostaLoteSelect+`
+		WHERE p.fazenda_id = $1 AND ($2 = '' OR p.status = $2)
+		ORDER BY p.created_at DESC, p.id DESC
+		LIMIT $3
+	`, fazendaID, status, limit)
+}
+
+func (r *CicloVidaRepository) GetPropostasByIDs(ctx context.Context, fazendaID int64, ids []int64) ([]*models.MovimentacaoLoteProposta, error) {
+	return r.queryPropostas(ctx, propostaLoteSelect+`
+		WHERE p.fazenda_id = $1 AND p.id = ANY($2)
+		ORDER BY p.id
+	`, fazendaID, ids)
+}
+
+// CreateProposta insere a proposta; false quando o animal já tem proposta pendente ou a mesma situação
+// já foi decidida (índices únicos parciais).
+func (r *CicloVidaRepository) CreateProposta(ctx context.Context, p *models.MovimentacaoLoteProposta) (bool, error) {
+	const q = `
+		INSERT INTO movimentacoes_lote_propostas (fazenda_id, animal_id, lote_origem_id, lote_destino_id, motivo, referencia, descricao)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT DO NOTHING
+		RETURNING id, status, created_at
+	`
+	err := r.db.QueryRow(ctx, q, p.FazendaID, p.AnimalID, p.LoteOrigemID, p.LoteDestinoID, p.Motivo, p.Referencia, p.Descricao).
+		Scan(&p.ID, &p.Status, &p.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// DecidirProposta muda o estado de uma proposta PENDENTE; pgx.ErrNoRows se já não estiver pendente.
+func (r *CicloVidaRepository) DecidirProposta(ctx context.Context, fazendaID, id int64, status string, decididoPor *int64) error {
+	const q = `
+		UPDATE movimentacoes_lote_propostas
+		SET status = $3, decidido_por = $4, decidido_em = NOW()
+		WHERE fazenda_id = $1 AND id = $2 AND status = 'PENDENTE'
+	`
+	tag, err := r.db.Exec(ctx, q, fazendaID, id, status, decididoPor)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// ReabrirProposta devolve a PENDENTE uma aprovação cuja movimentação falhou.
+func (r *CicloVidaRepository) ReabrirProposta(ctx context.Context, id int64) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE movimentacoes_lote_propostas
+		SET status = 'PENDENTE', decidido_por = NULL, decidido_em = NULL
+		WHERE id = $1 AND status = 'APROVADA' AND movimentacao_id IS NULL
+	`, id)
+	return err
+}
+
+func (r *CicloVidaRepository) SetPropostaMovimentacao(ctx context.Context, id, movimentacaoID int64) error {
+	_, err := r.db.Exec(ctx, `UPDATE movimentacoes_lote_propostas SET movimentacao_id = $2 WHERE id = $1`, id, movimentacaoID)
+	return err
+}
+
+func (r *CicloVidaRepository) queryPropostas(ctx context.Context, query string, args ...interface{}) ([]*models.MovimentacaoLoteProposta, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []*models.MovimentacaoLoteProposta{}
+	for rows.Next() {
+		var p models.MovimentacaoLoteProposta
+		if err := rows.Scan(
+			&p.ID, &p.FazendaID, &p.AnimalID, &p.AnimalIdentificacao, &p.LoteOrigemID, &p.LoteOrigemNome, &p.LoteDestinoID, &p.LoteDestinoNome,
+			&p.Motivo, &p.Referencia, &p.Descricao, &p.Status, &p.MovimentacaoID, &p.DecididoPor, &p.DecididoEm, &p.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, &p)
+	}
+	return out, rows.Err()
+}
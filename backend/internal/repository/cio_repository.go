@@ -25,7 +25,7 @@ func (r *CioRepository) Create(ctx context.Context, c *models.Cio) error {
 }
 
 func (r *CioRepository) GetByID(ctx context.Context, id int64) (*models.Cio, error) {
-	query := `SELECT id, animal_id, data_detectado, metodo_deteccao, intensidade, observacoes, usuario_id, fazenda_id, created_at FROM cios WHERE id = $1`
+	query := `SELECT id, animal_id, data_detectado, metodo_deteccao, intensidade, observacoes, usuario_id, fazenda_id, created_at FROM cios WHERE id = $1 AND excluido_em IS NULL`
 	var c models.Cio
 	err := r.db.QueryRow(ctx, query, id).Scan(&c.ID, &c.AnimalID, &c.DataDetectado, &c.MetodoDeteccao, &c.Intensidade, &c.Observacoes, &c.UsuarioID, &c.FazendaID, &c.CreatedAt)
 	if err == pgx.ErrNoRows {
@@ -36,7 +36,7 @@ func (r *CioRepository) GetByID(ctx context.Context, id int64) (*models.Cio, err
 
 func (r *CioRepository) GetByAnimalID(ctx context.Context, animalID int64) ([]*models.Cio, error) {
 	query := `SELECT id, animal_id, data_detectado, metodo_deteccao, intensidade, observacoes, usuario_id, fazenda_id, created_at
-		FROM cios WHERE animal_id = $1 ORDER BY data_detectado DESC`
+		FROM cios WHERE animal_id = $1 AND excluido_em IS NULL ORDER BY data_detectado DESC`
 	rows, err := r.db.Query(ctx, query, animalID)
 	if err != nil {
 		return nil, err
@@ -55,7 +55,7 @@ func (r *CioRepository) GetByAnimalID(ctx context.Context, animalID int64) ([]*m
 
 func (r *CioRepository) GetByFazendaID(ctx context.Context, fazendaID int64) ([]*models.Cio, error) {
 	query := `SELECT id, animal_id, data_detectado, metodo_deteccao, intensidade, observacoes, usuario_id, fazenda_id, created_at
-		FROM cios WHERE fazenda_id = $1 ORDER BY data_detectado DESC`
+		FROM cios WHERE fazenda_id = $1 AND excluido_em IS NULL ORDER BY data_detectado DESC`
 	rows, err := r.db.Query(ctx, query, fazendaID)
 	if err != nil {
 		return nil, err
@@ -78,6 +78,7 @@ func (r *CioRepository) ListDetectadosNaDataByFazendaID(ctx context.Context, faz
 		FROM cios c
 		INNER JOIN animais a ON a.id = c.animal_id
 		WHERE c.fazenda_id = $1
+		  AND c.excluido_em IS NULL
 		  AND c.data_detectado::date = $2::date
 		  AND ` + SQLNoRebanhoFor("a") + `
 		ORDER BY a.identificacao ASC
@@ -108,7 +109,44 @@ func (r *CioRepository) Update(ctx context.Context, c *models.Cio) error {
 	return err
 }
 
-func (r *CioRepository) Delete(ctx context.Context, id int64) error {
-	_, err := r.db.Exec(ctx, `DELETE FROM cios WHERE id = $1`, id)
-	return err
+// Delete envia o cio para a lixeira (exclusão lógica); usuarioID nil = sistema.
+func (r *CioRepository) Delete(ctx context.Context, id int64, usuarioID *int64) error {
+	cmd, err := r.db.Exec(ctx, `UPDATE cios SET excluido_em = CURRENT_TIMESTAMP, excluido_por = $2 WHERE id = $1 AND excluido_em IS NULL`, id, usuarioID)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// GetExcluidoByID devolve o cio apenas se estiver na lixeira.
+func (r *CioRepository) GetExcluidoByID(ctx context.Context, id int64) (*models.Cio, error) {
+	query := `SELECT id, animal_id, data_detectado, metodo_deteccao, intensidade, observacoes, usuario_id, fazenda_id, created_at FROM cios WHERE id = $1 AND excluido_em IS NOT NULL`
+	var c models.Cio
+	err := r.db.QueryRow(ctx, query, id).Scan(&c.ID, &c.AnimalID, &c.DataDetectado, &c.MetodoDeteccao, &c.Intensidade, &c.Observacoes, &c.UsuarioID, &c.FazendaID, &c.CreatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, pgx.ErrNoRows
+	}
+	return &c, err
+}
+
+// Restaurar retira o cio da lixeira.
+func (r *CioRepository) Restaurar(ctx context.Context, id int64) error {
+	cmd, err := r.db.Exec(ctx, `UPDATE cios SET excluido_em = NULL, excluido_por = NULL WHERE id = $1 AND excluido_em IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// ExistsCoberturaVinculada indica cobertura fora da lixeira vinculada ao cio.
+func (r *CioRepository) ExistsCoberturaVinculada(ctx context.Context, cioID int64) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM coberturas WHERE cio_id = $1 AND excluido_em IS NULL)`, cioID).Scan(&exists)
+	return exists, err
 }
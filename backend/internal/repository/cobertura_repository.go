@@ -27,7 +27,7 @@ func (r *CoberturaRepository) Create(ctx context.Context, c *models.Cobertura) e
 }
 
 func (r *CoberturaRepository) GetByID(ctx context.Context, id int64) (*models.Cobertura, error) {
-	query := `SELECT id, animal_id, cio_id, tipo, data, touro_animal_id, touro_info, semen_partida, tecnico, protocolo_id, observacoes, fazenda_id, created_by, created_at, updated_at FROM coberturas WHERE id = $1`
+	query := `SELECT id, animal_id, cio_id, tipo, data, touro_animal_id, touro_info, semen_partida, tecnico, protocolo_id, observacoes, fazenda_id, created_by, created_at, updated_at FROM coberturas WHERE id = $1 AND excluido_em IS NULL`
 	var c models.Cobertura
 	err := r.db.QueryRow(ctx, query, id).Scan(&c.ID, &c.AnimalID, &c.CioID, &c.Tipo, &c.Data, &c.TouroAnimalID, &c.TouroInfo, &c.SemenPartida, &c.Tecnico, &c.ProtocoloID, &c.Observacoes, &c.FazendaID, &c.CreatedBy, &c.CreatedAt, &c.UpdatedAt)
 	if err == pgx.ErrNoRows {
@@ -38,7 +38,7 @@ func (r *CoberturaRepository) GetByID(ctx context.Context, id int64) (*models.Co
 
 func (r *CoberturaRepository) GetByAnimalID(ctx context.Context, animalID int64) ([]*models.Cobertura, error) {
 	query := `SELECT id, animal_id, cio_id, tipo, data, touro_animal_id, touro_info, semen_partida, tecnico, protocolo_id, observacoes, fazenda_id, created_by, created_at, updated_at
-		FROM coberturas WHERE animal_id = $1 ORDER BY data DESC`
+		FROM coberturas WHERE animal_id = $1 AND excluido_em IS NULL ORDER BY data DESC`
 	rows, err := r.db.Query(ctx, query, animalID)
 	if err != nil {
 		return nil, err
@@ -57,7 +57,7 @@ func (r *CoberturaRepository) GetByAnimalID(ctx context.Context, animalID int64)
 
 func (r *CoberturaRepository) GetByFazendaID(ctx context.Context, fazendaID int64) ([]*models.Cobertura, error) {
 	query := `SELECT id, animal_id, cio_id, tipo, data, touro_animal_id, touro_info, semen_partida, tecnico, protocolo_id, observacoes, fazenda_id, created_at, updated_at
-		FROM coberturas WHERE fazenda_id = $1 ORDER BY data DESC`
+		FROM coberturas WHERE fazenda_id = $1 AND excluido_em IS NULL ORDER BY data DESC`
 	rows, err := r.db.Query(ctx, query, fazendaID)
 	if err != nil {
 		return nil, err
@@ -89,9 +89,39 @@ func (r *CoberturaRepository) Update(ctx context.Context, c *models.Cobertura) e
 	return nil
 }
 
-func (r *CoberturaRepository) Delete(ctx context.Context, id int64) error {
-	_, err := r.db.Exec(ctx, `DELETE FROM coberturas WHERE id = $1`, id)
-	return err
+// Delete envia a cobertura para a lixeira (exclusão lógica); usuarioID nil = sistema.
+func (r *CoberturaRepository) Delete(ctx context.Context, id int64, usuarioID *int64) error {
+	cmd, err := r.db.Exec(ctx, `UPDATE coberturas SET excluido_em = CURRENT_TIMESTAMP, excluido_por = $2 WHERE id = $1 AND excluido_em IS NULL`, id, usuarioID)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// GetExcluidoByID devolve a cobertura apenas se estiver na lixeira.
+func (r *CoberturaRepository) GetExcluidoByID(ctx context.Context, id int64) (*models.Cobertura, error) {
+	query := `SELECT id, animal_id, cio_id, tipo, data, touro_animal_id, touro_info, semen_partida, tecnico, protocolo_id, observacoes, fazenda_id, created_by, created_at, updated_at FROM coberturas WHERE id = $1 AND excluido_em IS NOT NULL`
+	var c models.Cobertura
+	err := r.db.QueryRow(ctx, query, id).Scan(&c.ID, &c.AnimalID, &c.CioID, &c.Tipo, &c.Data, &c.TouroAnimalID, &c.TouroInfo, &c.SemenPartida, &c.Tecnico, &c.ProtocoloID, &c.Observacoes, &c.FazendaID, &c.CreatedBy, &c.CreatedAt, &c.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, pgx.ErrNoRows
+	}
+	return &c, err
+}
+
+// Restaurar retira a cobertura da lixeira.
+func (r *CoberturaRepository) Restaurar(ctx context.Context, id int64) error {
+	cmd, err := r.db.Exec(ctx, `UPDATE coberturas SET excluido_em = NULL, excluido_por = NULL WHERE id = $1 AND excluido_em IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
 }
 
 // HasPendenteToqueByAnimalID indica cobertura há diasMinimos+ dias sem diagnóstico de gestação.
@@ -100,6 +130,7 @@ func (r *CoberturaRepository) HasPendenteToqueByAnimalID(ctx context.Context, an
 		SELECT EXISTS (
 			SELECT 1 FROM coberturas cb
 			WHERE cb.animal_id = $1 AND cb.fazenda_id = $2
+			AND cb.excluido_em IS NULL
 			AND cb.data <= CURRENT_TIMESTAMP - ($3 * interval '1 day')
 			AND NOT EXISTS (
 				SELECT 1 FROM diagnosticos_gestacao dg WHERE dg.cobertura_id = cb.id
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrConviteIndisponivel = errors.New("convite já usado, revogado ou expirado")
+	ErrConviteJaVinculado  = errors.New("você já está vinculado a esta fazenda")
+)
+
+type ConviteRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewConviteRepository(db *pgxpool.Pool) *ConviteRepository {
+	return &ConviteRepository{db: db}
+}
+
+const conviteSelect = `
+	SELECT c.id, c.fazenda_id, COALESCE(f.nome, ''), c.perfil, c.papel, c.codigo_prefixo, c.observacao,
+	       c.expira_em, c.criado_por, uc.nome, c.created_at, c.usado_por, uu.nome, c.usado_em,
+	       c.revogado_por, c.revogado_em
+	FROM convites c
+	LEFT JOIN fazendas f ON f.id = c.fazenda_id
+	LEFT JOIN usuarios uc ON uc.id = c.criado_por
+	LEFT JOIN usuarios uu ON uu.id = c.usado_por
+`
+
+func scanConvite(row pgx.Row) (*models.Convite, error) {
+	var c models.Convite
+	if err := row.Scan(&c.ID, &c.FazendaID, &c.FazendaNome, &c.Perfil, &c.Papel, &c.CodigoPrefixo, &c.Observacao,
+		&c.ExpiraEm, &c.CriadoPor, &c.CriadoPorNome, &c.CreatedAt, &c.UsadoPor, &c.UsadoPorNome, &c.UsadoEm,
+		&c.RevogadoPor, &c.RevogadoEm); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Create grava o convite com o hash do código; preenche ID e CreatedAt.
+func (r *ConviteRepository) Create(ctx context.Context, c *models.Convite, codigoHash string) error {
+	return r.db.QueryRow(ctx, `
+		INSERT INTO convites (fazenda_id, perfil, papel, codigo_hash, codigo_prefixo, observacao, expira_em, criado_por)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+	`, c.FazendaID, c.Perfil, c.Papel, codigoHash, c.CodigoPrefixo, c.Observacao, c.ExpiraEm, c.CriadoPor).
+		Scan(&c.ID, &c.CreatedAt)
+}
+
+// ListByFazenda convites da fazenda, mais recentes primeiro.
+func (r *ConviteRepository) ListByFazenda(ctx context.Context, fazendaID int64, limit int) ([]models.Convite, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	rows, err := r.db.Query(ctx, conviteSelect+`WHERE c.fazenda_id = $1 ORDER BY c.created_at DESC, c.id DESC LIMIT $2`, fazendaID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []models.Convite{}
+	for rows.Next() {
+		c, err := scanConvite(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *c)
+	}
+	return out, rows.Err()
+}
+
+// GetByID convite da fazenda; pgx.ErrNoRows se não existir.
+func (r *ConviteRepository) GetByID(ctx context.Context, fazendaID, id int64) (*models.Convite, error) {
+	return scanConvite(r.db.QueryRow(ctx, conviteSelect+`WHERE c.id = $1 AND c.fazenda_id = $2`, id, fazendaID))
+}
+
+// GetByCodigoHash resolve o convite pelo hash do código; pgx.ErrNoRows se não existir.
+func (r *ConviteRepository) GetByCodigoHash(ctx context.Context, codigoHash string) (*models.Convite, error) {
+	return scanConvite(r.db.QueryRow(ctx, conviteSelect+`WHERE c.codigo_hash = $1`, codigoHash))
+}
+
+// Revogar marca o convite pendente como revogado; ErrConviteIndisponivel se já foi usado ou revogado.
+func (r *ConviteRepository) Revogar(ctx context.Context, fazendaID, id, actorID int64, em time.Time) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE convites SET revogado_por = $3, revogado_em = $4
+		WHERE id = $1 AND fazenda_id = $2 AND usado_em IS NULL AND revogado_em IS NULL
+	`, id, fazendaID, actorID, em)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrConviteIndisponivel
+	}
+	return nil
+}
+
+// Resgatar consome o convite e cria o vínculo numa transação: marca o uso (só se ainda pendente em em),
+// insere usuarios_fazendas com o papel do convite e grava perfilNovo no usuário quando diferente do atual.
+// ErrConviteIndisponivel se outro resgate ou revogação chegou antes; ErrConviteJaVinculado se o vínculo já existe.
+func (r *ConviteRepository) Resgatar(ctx context.Context, conviteID, usuarioID int64, perfilNovo string, em time.Time) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var fazendaID int64
+	var papel string
+	err = tx.QueryRow(ctx, `
+		UPDATE convites SET usado_por = $2, usado_em = $3
+		WHERE id = $1 AND usado_em IS NULL AND revogado_em IS NULL AND expira_em > $3
+		RETURNING fazenda_id, papel
+	`, conviteID, usuarioID, em).Scan(&fazendaID, &papel)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrConviteIndisponivel
+	}
+	if err != nil {
+		return err
+	}
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO usuarios_fazendas (usuario_id, fazenda_id, papel) VALUES ($1, $2, $3)
+		ON CONFLICT (usuario_id, fazenda_id) DO NOTHING
+	`, usuarioID, fazendaID, papel)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrConviteJaVinculado
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE usuarios SET perfil = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND perfil <> $2
+	`, usuarioID, perfilNovo); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -93,3 +93,10 @@ func (r *CriaRepository) UpdateTx(ctx context.Context, tx pgx.Tx, c *models.Cria
 	_, err := tx.Exec(ctx, query, c.AnimalID, c.Peso, c.Condicao, c.Observacoes, c.ID)
 	return err
 }
+
+// RelinkAnimalTx revincula a cria ao animal gerado (restauração do parto); só altera crias sem animal.
+func (r *CriaRepository) RelinkAnimalTx(ctx context.Context, tx pgx.Tx, criaID, partoID, animalID int64) error {
+	query := `UPDATE crias SET animal_id = $1 WHERE id = $2 AND parto_id = $3 AND animal_id IS NULL`
+	_, err := tx.Exec(ctx, query, animalID, criaID, partoID)
+	return err
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	// ErrDoisFatoresJaAtivo a inscrição já foi confirmada; nova inscrição só depois de desativar.
+	ErrDoisFatoresJaAtivo = errors.New("autenticação em dois fatores já ativa")
+	// ErrDoisFatoresIndisponivel código já consumido (passo TOTP repetido ou código de recuperação usado).
+	ErrDoisFatoresIndisponivel = errors.New("código já utilizado")
+	// ErrDoisFatoresDesafioIndisponivel desafio do login desconhecido, já usado, expirado ou sem tentativas.
+	ErrDoisFatoresDesafioIndisponivel = errors.New("desafio de login indisponível")
+)
+
+type DoisFatoresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewDoisFatoresRepository(db *pgxpool.Pool) *DoisFatoresRepository {
+	return &DoisFatoresRepository{db: db}
+}
+
+// Get inscrição do usuário (pendente ou ativa); pgx.ErrNoRows se não existir.
+func (r *DoisFatoresRepository) Get(ctx context.Context, usuarioID int64) (*models.DoisFatores, error) {
+	var d models.DoisFatores
+	err := r.db.QueryRow(ctx, `
+		SELECT usuario_id, segredo_cifrado, ativado_em, ultimo_passo, created_at, updated_at
+		FROM usuarios_dois_fatores WHERE usuario_id = $1
+	`, usuarioID).Scan(&d.UsuarioID, &d.SegredoCifrado, &d.AtivadoEm, &d.UltimoPasso, &d.CreatedAt, &d.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// SalvarPendente grava (ou substitui) o segredo de uma inscrição ainda não confirmada.
+// ErrDoisFatoresJaAtivo se o 2FA da conta já está ativo.
+func (r *DoisFatoresRepository) SalvarPendente(ctx context.Context, usuarioID int64, segredoCifrado string) error {
+	tag, err := r.db.Exec(ctx, `
+		INSERT INTO usuarios_dois_fatores (usuario_id, segredo_cifrado)
+		VALUES ($1, $2)
+		ON CONFLICT (usuario_id) DO UPDATE
+		SET segredo_cifrado = EXCLUDED.segredo_cifrado, ultimo_passo = 0, updated_at = CURRENT_TIMESTAMP
+		WHERE usuarios_dois_fatores.ativado_em IS NULL
+	`, usuarioID, segredoCifrado)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrDoisFatoresJaAtivo
+	}
+	return nil
+}
+
+// Ativar confirma a inscrição pendente numa transação: grava o passo do código de confirmação e troca
+// os códigos de recuperação. ErrDoisFatoresJaAtivo se outra ativação chegou antes.
+func (r *DoisFatoresRepository) Ativar(ctx context.Context, usuarioID, passo int64, codigoHashes []string, em time.Time) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE usuarios_dois_fatores SET ativado_em = $3, ultimo_passo = $2, updated_at = $3
+		WHERE usuario_id = $1 AND ativado_em IS NULL
+	`, usuarioID, passo, em)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrDoisFatoresJaAtivo
+	}
+	if err := substituirCodigosRecuperacao(ctx, tx, usuarioID, codigoHashes); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func substituirCodigosRecuperacao(ctx context.Context, tx pgx.Tx, usuarioID int64, codigoHashes []string) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM usuarios_dois_fatores_recuperacao WHERE usuario_id = $1`, usuarioID); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx, `
+		INSERT INTO usuarios_dois_fatores_recuperacao (usuario_id, codigo_hash)
+		SELECT $1, unnest($2::text[])
+	`, usuarioID, codigoHashes)
+	return err
+}
+
+// SubstituirCodigos invalida os códigos de recuperação anteriores e grava os novos.
+func (r *DoisFatoresRepository) SubstituirCodigos(ctx context.Context, usuarioID int64, codigoHashes []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	if err := substituirCodigosRecuperacao(ctx, tx, usuarioID, codigoHashes); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// RegistrarPasso consome o passo TOTP; ErrDoisFatoresIndisponivel se ele (ou um posterior) já foi usado,
+// o que barra o mesmo código em dois pedidos concorrentes.
+func (r *DoisFatoresRepository) RegistrarPasso(ctx context.Context, usuarioID, passo int64) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE usuarios_dois_fatores SET ultimo_passo = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE usuario_id = $1 AND ativado_em IS NOT NULL AND ultimo_passo < $2
+	`, usuarioID, passo)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrDoisFatoresIndisponivel
+	}
+	return nil
+}
+
+// UsarCodigoRecuperacao marca o código como usado; ErrDoisFatoresIndisponivel se não existe ou já foi usado.
+func (r *DoisFatoresRepository) UsarCodigoRecuperacao(ctx context.Context, usuarioID int64, codigoHash string, em time.Time) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE usuarios_dois_fatores_recuperacao SET usado_em = $3
+		WHERE usuario_id = $1 AND codigo_hash = $2 AND usado_em IS NULL
+	`, usuarioID, codigoHash, em)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrDoisFatoresIndisponivel
+	}
+	return nil
+}
+
+// CountCodigosRestantes códigos de recuperação ainda não usados.
+func (r *DoisFatoresRepository) CountCodigosRestantes(ctx context.Context, usuarioID int64) (int, error) {
+	var n int
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM usuarios_dois_fatores_recuperacao WHERE usuario_id = $1 AND usado_em IS NULL
+	`, usuarioID).Scan(&n)
+	return n, err
+}
+
+// Remover apaga a inscrição e os códigos de recuperação; devolve false se não havia inscrição.
+func (r *DoisFatoresRepository) Remover(ctx context.Context, usuarioID int64) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	if _, err := tx.Exec(ctx, `DELETE FROM usuarios_dois_fatores_recuperacao WHERE usuario_id = $1`, usuarioID); err != nil {
+		return false, err
+	}
+	tag, err := tx.Exec(ctx, `DELETE FROM usuarios_dois_fatores WHERE usuario_id = $1`, usuarioID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, tx.Commit(ctx)
+}
+
+// RegistrarDesafio grava o jti do desafio emitido após a senha e descarta os desafios vencidos do usuário.
+func (r *DoisFatoresRepository) RegistrarDesafio(ctx context.Context, jti string, usuarioID int64, expiraEm, agora time.Time) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM usuarios_dois_fatores_desafios WHERE usuario_id = $1 AND expira_em < $2
+	`, usuarioID, agora); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO usuarios_dois_fatores_desafios (jti, usuario_id, expira_em) VALUES ($1, $2, $3)
+	`, jti, usuarioID, expiraEm); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// ReservarTentativaDesafio conta uma tentativa de código no desafio numa única instrução, o que barra
+// pedidos concorrentes além do limite. ErrDoisFatoresDesafioIndisponivel se o desafio não é do usuário,
+// já foi consumido, expirou ou esgotou as maxTentativas.
+func (r *DoisFatoresRepository) ReservarTentativaDesafio(ctx context.Context, jti string, usuarioID int64, maxTentativas int, agora time.Time) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE usuarios_dois_fatores_desafios SET tentativas = tentativas + 1
+		WHERE jti = $1 AND usuario_id = $2 AND consumido_em IS NULL AND expira_em > $4 AND tentativas < $3
+	`, jti, usuarioID, maxTentativas, agora)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrDoisFatoresDesafioIndisponivel
+	}
+	return nil
+}
+
+// ConsumirDesafio marca o desafio como usado; ErrDoisFatoresDesafioIndisponivel se outro pedido chegou antes.
+func (r *DoisFatoresRepository) ConsumirDesafio(ctx context.Context, jti string, em time.Time) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE usuarios_dois_fatores_desafios SET consumido_em = $2
+		WHERE jti = $1 AND consumido_em IS NULL
+	`, jti, em)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrDoisFatoresDesafioIndisponivel
+	}
+	return nil
+}
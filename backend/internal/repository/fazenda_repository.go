@@ -409,6 +409,19 @@ func (r *FazendaRepository) UsuarioVinculadoAFazenda(ctx context.Context, usuari
 	return ok, err
 }
 
+// GetPapelVinculo papel do vínculo usuário–fazenda (TITULAR | OPERACIONAL); "" sem vínculo.
+func (r *FazendaRepository) GetPapelVinculo(ctx context.Context, usuarioID, fazendaID int64) (string, error) {
+	var papel string
+	err := r.db.QueryRow(ctx,
+		`SELECT papel FROM usuarios_fazendas WHERE usuario_id = $1 AND fazenda_id = $2`,
+		usuarioID, fazendaID,
+	).Scan(&papel)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	return papel, err
+}
+
 // UpdateUsuarioFazendaAtiva persiste a fazenda ativa do utilizador (valida vínculo).
 func (r *FazendaRepository) UpdateUsuarioFazendaAtiva(ctx context.Context, usuarioID, fazendaID int64) error {
 	linked, err := r.UsuarioVinculadoAFazenda(ctx, usuarioID, fazendaID)
@@ -432,7 +445,8 @@ func (r *FazendaRepository) UpdateUsuarioFazendaAtiva(ctx context.Context, usuar
 }
 
 // ListUsuarioIDsForAlertaPush devolve IDs de utilizadores elegíveis a receber push para alertas da fazenda.
-func (r *FazendaRepository) ListUsuarioIDsForAlertaPush(ctx context.Context, fazendaID int64) ([]int64, error) {
+// perfis restringe os destinatários (nil = todos os perfis operacionais).
+func (r *FazendaRepository) ListUsuarioIDsForAlertaPush(ctx context.Context, fazendaID int64, perfis []string) ([]int64, error) {
 	const q = `
 		SELECT DISTINCT u.id
 		FROM usuarios u
@@ -441,9 +455,39 @@ func (r *FazendaRepository) ListUsuarioIDsForAlertaPush(ctx context.Context, faz
 		WHERE u.enabled = true
 		  AND u.fazenda_ativa_id = $1
 		  AND u.perfil NOT IN ('USER', 'INTEGRACAO')
+		  AND ($2::text[] IS NULL OR u.perfil = ANY($2::text[]))
+		ORDER BY u.id ASC
+	`
+	rows, err := r.db.Query(ctx, q, fazendaID, perfis)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ListUsuarioIDsForAlertaNotificacao igual a ListUsuarioIDsForAlertaPush sem exigir subscrição Web Push:
+// os canais de cada utilizador são resolvidos pelas preferências de notificação (BR-ALERTA-021).
+func (r *FazendaRepository) ListUsuarioIDsForAlertaNotificacao(ctx context.Context, fazendaID int64, perfis []string) ([]int64, error) {
+	const q = `
+		SELECT u.id
+		FROM usuarios u
+		INNER JOIN usuarios_fazendas uf ON uf.usuario_id = u.id AND uf.fazenda_id = $1
+		WHERE u.enabled = true
+		  AND u.fazenda_ativa_id = $1
+		  AND u.perfil NOT IN ('USER', 'INTEGRACAO')
+		  AND ($2::text[] IS NULL OR u.perfil = ANY($2::text[]))
 		ORDER BY u.id ASC
 	`
-	rows, err := r.db.Query(ctx, q, fazendaID)
+	rows, err := r.db.Query(ctx, q, fazendaID, perfis)
 	if err != nil {
 		return nil, err
 	}
@@ -46,34 +46,114 @@ func (r *FolgasRepository) UsuarioTemFazendaComPerfilPermitido(ctx context.Conte
 	return ok, err
 }
 
+// GetConfig carrega as equipes de rodízio da fazenda com os participantes (nomes inclusos);
+// pgx.ErrNoRows quando a fazenda não tem nenhuma equipe.
 func (r *FolgasRepository) GetConfig(ctx context.Context, fazendaID int64) (*models.FolgasEscalaConfig, error) {
-	q := `
-		SELECT fazenda_id, data_anchor, usuario_slot_0, usuario_slot_1, usuario_slot_2, updated_at
-		FROM folgas_escala_config WHERE fazenda_id = $1
-	`
-	var c models.FolgasEscalaConfig
-	err := r.db.QueryRow(ctx, q, fazendaID).Scan(
-		&c.FazendaID, &c.DataAnchor, &c.UsuarioSlot0, &c.UsuarioSlot1, &c.UsuarioSlot2, &c.UpdatedAt,
-	)
+	rows, err := r.db.Query(ctx, `
+		SELECT id, fazenda_id, nome, data_anchor, ciclo_dias, folgas_por_ciclo, updated_at
+		FROM folgas_equipes WHERE fazenda_id = $1
+		ORDER BY id
+	`, fazendaID)
+	if err != nil {
+		return nil, err
+	}
+	c := &models.FolgasEscalaConfig{FazendaID: fazendaID}
+	idx := make(map[int64]int)
+	for rows.Next() {
+		var e models.FolgasEquipe
+		if err := rows.Scan(&e.ID, &e.FazendaID, &e.Nome, &e.DataAnchor, &e.CicloDias, &e.FolgasPorCiclo, &e.UpdatedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		idx[e.ID] = len(c.Equipes)
+		c.Equipes = append(c.Equipes, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(c.Equipes) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	prows, err := r.db.Query(ctx, `
+		SELECT p.equipe_id, p.usuario_id, COALESCE(u.nome, ''), p.posicao, p.deslocamento
+		FROM folgas_equipe_participantes p
+		LEFT JOIN usuarios u ON u.id = p.usuario_id
+		WHERE p.fazenda_id = $1
+		ORDER BY p.equipe_id, p.posicao
+	`, fazendaID)
 	if err != nil {
 		return nil, err
 	}
-	return &c, nil
+	defer prows.Close()
+	for prows.Next() {
+		var equipeID int64
+		var p models.FolgasEquipeParticipante
+		if err := prows.Scan(&equipeID, &p.UsuarioID, &p.UsuarioNome, &p.Posicao, &p.Deslocamento); err != nil {
+			return nil, err
+		}
+		if i, ok := idx[equipeID]; ok {
+			c.Equipes[i].Participantes = append(c.Equipes[i].Participantes, p)
+		}
+	}
+	return c, prows.Err()
 }
 
-func (r *FolgasRepository) UpsertConfig(ctx context.Context, c *models.FolgasEscalaConfig) error {
-	q := `
-		INSERT INTO folgas_escala_config (fazenda_id, data_anchor, usuario_slot_0, usuario_slot_1, usuario_slot_2, updated_at)
-		VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP)
-		ON CONFLICT (fazenda_id) DO UPDATE SET
-			data_anchor = EXCLUDED.data_anchor,
-			usuario_slot_0 = EXCLUDED.usuario_slot_0,
-			usuario_slot_1 = EXCLUDED.usuario_slot_1,
-			usuario_slot_2 = EXCLUDED.usuario_slot_2,
-			updated_at = CURRENT_TIMESTAMP
-	`
-	_, err := r.db.Exec(ctx, q, c.FazendaID, c.DataAnchor, c.UsuarioSlot0, c.UsuarioSlot1, c.UsuarioSlot2)
-	return err
+// ReplaceEquipes substitui as equipes da fazenda numa transação: equipes com ID existente são
+// atualizadas, as sem ID criadas e as omitidas removidas; participantes são regravados.
+// pgx.ErrNoRows quando um ID informado não pertence à fazenda.
+func (r *FolgasRepository) ReplaceEquipes(ctx context.Context, fazendaID int64, equipes []models.FolgasEquipe) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	manter := make([]int64, 0, len(equipes))
+	for _, e := range equipes {
+		if e.ID > 0 {
+			manter = append(manter, e.ID)
+		}
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM folgas_equipe_participantes WHERE fazenda_id = $1`, fazendaID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx,
+		`DELETE FROM folgas_equipes WHERE fazenda_id = $1 AND NOT (id = ANY($2::bigint[]))`,
+		fazendaID, manter,
+	); err != nil {
+		return err
+	}
+	for i := range equipes {
+		e := &equipes[i]
+		e.FazendaID = fazendaID
+		if e.ID > 0 {
+			err = tx.QueryRow(ctx, `
+				UPDATE folgas_equipes SET nome = $3, data_anchor = $4, ciclo_dias = $5, folgas_por_ciclo = $6,
+				       updated_at = CURRENT_TIMESTAMP
+				WHERE id = $1 AND fazenda_id = $2
+				RETURNING updated_at
+			`, e.ID, fazendaID, e.Nome, e.DataAnchor, e.CicloDias, e.FolgasPorCiclo).Scan(&e.UpdatedAt)
+		} else {
+			err = tx.QueryRow(ctx, `
+				INSERT INTO folgas_equipes (fazenda_id, nome, data_anchor, ciclo_dias, folgas_por_ciclo)
+				VALUES ($1, $2, $3, $4, $5)
+				RETURNING id, updated_at
+			`, fazendaID, e.Nome, e.DataAnchor, e.CicloDias, e.FolgasPorCiclo).Scan(&e.ID, &e.UpdatedAt)
+		}
+		if err != nil {
+			return err
+		}
+		for _, p := range e.Participantes {
+			if _, err := tx.Exec(ctx, `
+				INSERT INTO folgas_equipe_participantes (equipe_id, fazenda_id, usuario_id, posicao, deslocamento)
+				VALUES ($1, $2, $3, $4, $5)
+			`, e.ID, fazendaID, p.UsuarioID, p.Posicao, p.Deslocamento); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit(ctx)
 }
 
 func (r *FolgasRepository) DeleteAutoInRange(ctx context.Context, fazendaID int64, inicio, fim time.Time) error {
@@ -84,15 +164,6 @@ func (r *FolgasRepository) DeleteAutoInRange(ctx context.Context, fazendaID int6
 	return err
 }
 
-func (r *FolgasRepository) HasManualOnDate(ctx context.Context, fazendaID int64, d time.Time) (bool, error) {
-	var ok bool
-	err := r.db.QueryRow(ctx,
-		`SELECT EXISTS(SELECT 1 FROM escala_folgas WHERE fazenda_id = $1 AND data = $2::date AND origem = 'MANUAL')`,
-		fazendaID, d,
-	).Scan(&ok)
-	return ok, err
-}
-
 func (r *FolgasRepository) InsertEscala(ctx context.Context, e *models.EscalaFolga) error {
 	q := `
 		INSERT INTO escala_folgas (fazenda_id, data, usuario_id, origem, justificada, motivo, observacoes, created_by, updated_at)
@@ -122,8 +193,15 @@ func (r *FolgasRepository) UpsertEscala(ctx context.Context, e *models.EscalaFol
 	).Scan(&e.ID, &e.CreatedAt, &e.UpdatedAt)
 }
 
-func (r *FolgasRepository) DeleteAllForDate(ctx context.Context, fazendaID int64, d time.Time) error {
-	_, err := r.db.Exec(ctx, `DELETE FROM escala_folgas WHERE fazenda_id = $1 AND data = $2::date`, fazendaID, d)
+// DeleteForDateUsuarios remove as folgas dos usuários informados na data.
+func (r *FolgasRepository) DeleteForDateUsuarios(ctx context.Context, fazendaID int64, d time.Time, usuarioIDs []int64) error {
+	if len(usuarioIDs) == 0 {
+		return nil
+	}
+	_, err := r.db.Exec(ctx,
+		`DELETE FROM escala_folgas WHERE fazenda_id = $1 AND data = $2::date AND usuario_id = ANY($3::bigint[])`,
+		fazendaID, d, usuarioIDs,
+	)
 	return err
 }
 
@@ -161,15 +239,6 @@ func (r *FolgasRepository) ListEscalaRange(ctx context.Context, fazendaID int64,
 	return out, rows.Err()
 }
 
-func (r *FolgasRepository) CountFolgasOnDate(ctx context.Context, fazendaID int64, d time.Time) (int64, error) {
-	var n int64
-	err := r.db.QueryRow(ctx,
-		`SELECT COUNT(*) FROM escala_folgas WHERE fazenda_id = $1 AND data = $2::date`,
-		fazendaID, d,
-	).Scan(&n)
-	return n, err
-}
-
 func (r *FolgasRepository) ListFolgasUsuarioOnDate(ctx context.Context, fazendaID int64, d time.Time) ([]models.EscalaFolga, error) {
 	q := `
 		SELECT id, fazenda_id, data, usuario_id, origem, justificada, motivo, observacoes, created_by, created_at, updated_at
@@ -331,3 +400,339 @@ func (r *FolgasRepository) CountDistinctFolgasUsuarioRange(ctx context.Context,
 	).Scan(&n)
 	return n, err
 }
+
+// ListGestoresFazenda usuários ativos vinculados à fazenda que podem aprovar alterações de escala.
+func (r *FolgasRepository) ListGestoresFazenda(ctx context.Context, fazendaID int64) ([]int64, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT u.id
+		FROM usuarios_fazendas uf
+		INNER JOIN usuarios u ON u.id = uf.usuario_id
+		WHERE uf.fazenda_id = $1 AND u.enabled = true AND u.perfil IN ('GERENTE', 'GESTAO', 'PROPRIETARIO')
+		ORDER BY u.id
+	`, fazendaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+const folgasTrocaSelect = `
+	SELECT t.id, t.fazenda_id, t.solicitante_id, COALESCE(us.nome, ''), t.colega_id, COALESCE(uc.nome, ''),
+	       t.data_solicitante, t.data_colega, t.motivo, t.status, t.respondido_em, t.decidido_por, t.decidido_em,
+	       t.observacao_decisao, t.created_at, t.updated_at
+	FROM folgas_trocas t
+	LEFT JOIN usuarios us ON us.id = t.solicitante_id
+	LEFT JOIN usuarios uc ON uc.id = t.colega_id
+`
+
+func scanFolgaTroca(row pgx.Row, t *models.FolgaTroca) error {
+	return row.Scan(
+		&t.ID, &t.FazendaID, &t.SolicitanteID, &t.SolicitanteNome, &t.ColegaID, &t.ColegaNome,
+		&t.DataSolicitante, &t.DataColega, &t.Motivo, &t.Status, &t.RespondidoEm, &t.DecididoPor, &t.DecididoEm,
+		&t.ObservacaoDecisao, &t.CreatedAt, &t.UpdatedAt,
+	)
+}
+
+func (r *FolgasRepository) InsertTroca(ctx context.Context, t *models.FolgaTroca) error {
+	return r.db.QueryRow(ctx, `
+		INSERT INTO folgas_trocas (fazenda_id, solicitante_id, colega_id, data_solicitante, data_colega, motivo, status)
+		VALUES ($1, $2, $3, $4::date, $5::date, $6, $7)
+		RETURNING id, created_at, updated_at
+	`, t.FazendaID, t.SolicitanteID, t.ColegaID, t.DataSolicitante, t.DataColega, t.Motivo, t.Status,
+	).Scan(&t.ID, &t.CreatedAt, &t.UpdatedAt)
+}
+
+// GetTroca pgx.ErrNoRows quando a troca não existe na fazenda.
+func (r *FolgasRepository) GetTroca(ctx context.Context, fazendaID, id int64) (*models.FolgaTroca, error) {
+	var t models.FolgaTroca
+	if err := scanFolgaTroca(r.db.QueryRow(ctx, folgasTrocaSelect+` WHERE t.fazenda_id = $1 AND t.id = $2`, fazendaID, id), &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ListTrocas trocas da fazenda, mais recentes primeiro; usuarioID restringe às que envolvem o usuário
+// e status vazio traz todas.
+func (r *FolgasRepository) ListTrocas(ctx context.Context, fazendaID int64, usuarioID *int64, status string, limit int) ([]models.FolgaTroca, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 100
+	}
+	rows, err := r.db.Query(ctx, folgasTrocaSelect+`
+		WHERE t.fazenda_id = $1
+		  AND ($2::bigint IS NULL OR t.solicitante_id = $2 OR t.colega_id = $2)
+		  AND ($3 = '' OR t.status = $3)
+		ORDER BY t.created_at DESC, t.id DESC
+		LIMIT $4
+	`, fazendaID, usuarioID, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []models.FolgaTroca
+	for rows.Next() {
+		var t models.FolgaTroca
+		if err := scanFolgaTroca(rows, &t); err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+// ListTrocasPendentesRange trocas pendentes com alguma das datas no intervalo.
+func (r *FolgasRepository) ListTrocasPendentesRange(ctx context.Context, fazendaID int64, inicio, fim time.Time) ([]models.FolgaTroca, error) {
+	rows, err := r.db.Query(ctx, folgasTrocaSelect+`
+		WHERE t.fazenda_id = $1
+		  AND t.status IN ('PENDENTE_COLEGA', 'PENDENTE_GESTAO')
+		  AND (t.data_solicitante BETWEEN $2::date AND $3::date OR t.data_colega BETWEEN $2::date AND $3::date)
+		ORDER BY LEAST(t.data_solicitante, t.data_colega), t.id
+	`, fazendaID, inicio, fim)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []models.FolgaTroca
+	for rows.Next() {
+		var t models.FolgaTroca
+		if err := scanFolgaTroca(rows, &t); err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+// ExisteTrocaPendente indica se alguma troca pendente já envolve a folga (usuário, data).
+func (r *FolgasRepository) ExisteTrocaPendente(ctx context.Context, fazendaID, usuarioID int64, d time.Time) (bool, error) {
+	var ok bool
+	err := r.db.QueryRow(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM folgas_trocas
+			WHERE fazenda_id = $1 AND status IN ('PENDENTE_COLEGA', 'PENDENTE_GESTAO')
+			  AND ((solicitante_id = $2 AND data_solicitante = $3::date) OR (colega_id = $2 AND data_colega = $3::date))
+		)
+	`, fazendaID, usuarioID, d).Scan(&ok)
+	return ok, err
+}
+
+// UpdateTrocaStatus muda o status a partir de `de` (transição otimista); pgx.ErrNoRows se a troca já mudou.
+func (r *FolgasRepository) UpdateTrocaStatus(ctx context.Context, t *models.FolgaTroca, de string) error {
+	return r.db.QueryRow(ctx, `
+		UPDATE folgas_trocas
+		SET status = $3, respondido_em = $4, decidido_por = $5, decidido_em = $6, observacao_decisao = $7,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = $2
+		RETURNING updated_at
+	`, t.ID, de, t.Status, t.RespondidoEm, t.DecididoPor, t.DecididoEm, t.ObservacaoDecisao).Scan(&t.UpdatedAt)
+}
+
+// ErrFolgasTrocaEscalaMudou quando as folgas da troca já não estão na escala como no pedido.
+var ErrFolgasTrocaEscalaMudou = errors.New("a escala mudou desde o pedido de troca: as folgas envolvidas não estão mais registradas como solicitado")
+
+// AplicarTroca aprova a troca numa transação: move a folga do solicitante para DataColega e a do colega
+// para DataSolicitante (origem MANUAL), grava o status APROVADA e a alteração TROCA.
+func (r *FolgasRepository) AplicarTroca(ctx context.Context, t *models.FolgaTroca, alteracao *models.FolgaAlteracao) error {
+	raw, err := json.Marshal(alteracao.Detalhes)
+	if err != nil {
+		return err
+	}
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var origem, destino int
+	err = tx.QueryRow(ctx, `
+		SELECT
+			COUNT(*) FILTER (WHERE (usuario_id = $2 AND data = $3::date) OR (usuario_id = $4 AND data = $5::date)),
+			COUNT(*) FILTER (WHERE (usuario_id = $2 AND data = $5::date) OR (usuario_id = $4 AND data = $3::date))
+		FROM escala_folgas
+		WHERE fazenda_id = $1 AND usuario_id IN ($2, $4)
+	`, t.FazendaID, t.SolicitanteID, t.DataSolicitante, t.ColegaID, t.DataColega).Scan(&origem, &destino)
+	if err != nil {
+		return err
+	}
+	if origem != 2 || destino != 0 {
+		return ErrFolgasTrocaEscalaMudou
+	}
+	motivo := "Troca de folga aprovada"
+	mover := `
+		UPDATE escala_folgas
+		SET data = $4::date, origem = 'MANUAL', justificada = false, motivo = $5, created_by = $6,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE fazenda_id = $1 AND usuario_id = $2 AND data = $3::date
+	`
+	if _, err := tx.Exec(ctx, mover, t.FazendaID, t.SolicitanteID, t.DataSolicitante, t.DataColega, motivo, t.DecididoPor); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, mover, t.FazendaID, t.ColegaID, t.DataColega, t.DataSolicitante, motivo, t.DecididoPor); err != nil {
+		return err
+	}
+	err = tx.QueryRow(ctx, `
+		UPDATE folgas_trocas
+		SET status = 'APROVADA', decidido_por = $2, decidido_em = $3, observacao_decisao = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'PENDENTE_GESTAO'
+		RETURNING updated_at
+	`, t.ID, t.DecididoPor, t.DecididoEm, t.ObservacaoDecisao).Scan(&t.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	err = tx.QueryRow(ctx,
+		`INSERT INTO folgas_alteracoes (fazenda_id, actor_id, tipo, detalhes) VALUES ($1, $2, $3, $4) RETURNING id, created_at`,
+		alteracao.FazendaID, alteracao.ActorID, alteracao.Tipo, raw,
+	).Scan(&alteracao.ID, &alteracao.CreatedAt)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	t.Status = models.FolgaTrocaAprovada
+	return nil
+}
+
+const folgasAusenciaSelect = `
+	SELECT a.id, a.fazenda_id, a.usuario_id, COALESCE(u.nome, ''), a.tipo, a.data_inicio, a.data_fim,
+	       a.anexo_ref, a.observacao, a.created_by, a.created_at, a.updated_at
+	FROM folgas_ausencias a
+	LEFT JOIN usuarios u ON u.id = a.usuario_id
+`
+
+func scanFolgaAusencia(row pgx.Row, a *models.FolgaAusencia) error {
+	return row.Scan(
+		&a.ID, &a.FazendaID, &a.UsuarioID, &a.UsuarioNome, &a.Tipo, &a.DataInicio, &a.DataFim,
+		&a.AnexoRef, &a.Observacao, &a.CreatedBy, &a.CreatedAt, &a.UpdatedAt,
+	)
+}
+
+// ListAusenciasRange ausências que tocam [inicio, fim]; usuarioID restringe a um funcionário.
+func (r *FolgasRepository) ListAusenciasRange(ctx context.Context, fazendaID int64, inicio, fim time.Time, usuarioID *int64) ([]models.FolgaAusencia, error) {
+	rows, err := r.db.Query(ctx, folgasAusenciaSelect+`
+		WHERE a.fazenda_id = $1 AND a.data_inicio <= $3::date AND a.data_fim >= $2::date
+		  AND ($4::bigint IS NULL OR a.usuario_id = $4)
+		ORDER BY a.data_inicio, a.usuario_id, a.id
+	`, fazendaID, inicio, fim, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []models.FolgaAusencia
+	for rows.Next() {
+		var a models.FolgaAusencia
+		if err := scanFolgaAusencia(rows, &a); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// GetAusencia pgx.ErrNoRows quando a ausência não existe na fazenda.
+func (r *FolgasRepository) GetAusencia(ctx context.Context, fazendaID, id int64) (*models.FolgaAusencia, error) {
+	var a models.FolgaAusencia
+	if err := scanFolgaAusencia(r.db.QueryRow(ctx, folgasAusenciaSelect+` WHERE a.fazenda_id = $1 AND a.id = $2`, fazendaID, id), &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// ErrFolgasAusenciaSobreposta quando o funcionário já tem ausência no período.
+var ErrFolgasAusenciaSobreposta = errors.New("o funcionário já tem ausência registrada que se sobrepõe a este período")
+
+// InsertAusencia grava a ausência e remove as folgas AUTO do funcionário no período, numa transação.
+// Folgas MANUAL ficam (decisão da gestão). Retorna as folgas AUTO removidas.
+func (r *FolgasRepository) InsertAusencia(ctx context.Context, a *models.FolgaAusencia) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Trava o vínculo do funcionário para serializar a checagem de sobreposição.
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM usuarios_fazendas WHERE usuario_id = $1 AND fazenda_id = $2 FOR UPDATE`, a.UsuarioID, a.FazendaID); err != nil {
+		return 0, err
+	}
+	var sobreposta bool
+	err = tx.QueryRow(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM folgas_ausencias
+			WHERE fazenda_id = $1 AND usuario_id = $2 AND data_inicio <= $4::date AND data_fim >= $3::date
+		)
+	`, a.FazendaID, a.UsuarioID, a.DataInicio, a.DataFim).Scan(&sobreposta)
+	if err != nil {
+		return 0, err
+	}
+	if sobreposta {
+		return 0, ErrFolgasAusenciaSobreposta
+	}
+	err = tx.QueryRow(ctx, `
+		INSERT INTO folgas_ausencias (fazenda_id, usuario_id, tipo, data_inicio, data_fim, anexo_ref, observacao, created_by)
+		VALUES ($1, $2, $3, $4::date, $5::date, $6, $7, $8)
+		RETURNING id, created_at, updated_at
+	`, a.FazendaID, a.UsuarioID, a.Tipo, a.DataInicio, a.DataFim, a.AnexoRef, a.Observacao, a.CreatedBy,
+	).Scan(&a.ID, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		return 0, err
+	}
+	tag, err := tx.Exec(ctx, `
+		DELETE FROM escala_folgas
+		WHERE fazenda_id = $1 AND usuario_id = $2 AND origem = 'AUTO' AND data >= $3::date AND data <= $4::date
+	`, a.FazendaID, a.UsuarioID, a.DataInicio, a.DataFim)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// DeleteAusencia pgx.ErrNoRows quando a ausência não existe na fazenda.
+func (r *FolgasRepository) DeleteAusencia(ctx context.Context, fazendaID, id int64) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM folgas_ausencias WHERE fazenda_id = $1 AND id = $2`, fazendaID, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// ListFeriasDireito dias de férias definidos pela gestão no ano, por usuário.
+func (r *FolgasRepository) ListFeriasDireito(ctx context.Context, fazendaID int64, ano int) (map[int64]int, error) {
+	rows, err := r.db.Query(ctx, `SELECT usuario_id, dias FROM folgas_ferias_direito WHERE fazenda_id = $1 AND ano = $2`, fazendaID, ano)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make(map[int64]int)
+	for rows.Next() {
+		var uid int64
+		var dias int
+		if err := rows.Scan(&uid, &dias); err != nil {
+			return nil, err
+		}
+		out[uid] = dias
+	}
+	return out, rows.Err()
+}
+
+func (r *FolgasRepository) UpsertFeriasDireito(ctx context.Context, fazendaID, usuarioID int64, ano, dias int) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO folgas_ferias_direito (fazenda_id, usuario_id, ano, dias, updated_at)
+		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
+		ON CONFLICT (fazenda_id, usuario_id, ano) DO UPDATE SET dias = EXCLUDED.dias, updated_at = CURRENT_TIMESTAMP
+	`, fazendaID, usuarioID, ano, dias)
+	return err
+}
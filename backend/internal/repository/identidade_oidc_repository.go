@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// IdentidadeOIDCRepository vínculo conta ↔ identidade no provedor OIDC (BR-ACESSO-029).
+type IdentidadeOIDCRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewIdentidadeOIDCRepository(db *pgxpool.Pool) *IdentidadeOIDCRepository {
+	return &IdentidadeOIDCRepository{db: db}
+}
+
+// GetUsuarioID conta vinculada a (emissor, sujeito); pgx.ErrNoRows se não houver.
+func (r *IdentidadeOIDCRepository) GetUsuarioID(ctx context.Context, emissor, sujeito string) (int64, error) {
+	var id int64
+	err := r.db.QueryRow(ctx, `
+		SELECT usuario_id FROM usuarios_identidades_oidc WHERE emissor = $1 AND sujeito = $2
+	`, emissor, sujeito).Scan(&id)
+	return id, err
+}
+
+// RegistrarLogin atualiza o e-mail informado pelo provedor e o momento do login.
+func (r *IdentidadeOIDCRepository) RegistrarLogin(ctx context.Context, emissor, sujeito, email string, em time.Time) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE usuarios_identidades_oidc SET email = $3, ultimo_login_em = $4
+		WHERE emissor = $1 AND sujeito = $2
+	`, emissor, sujeito, email, em)
+	return err
+}
+
+// Vincular liga a identidade a uma conta existente (mesmo e-mail, verificado no provedor) e marca o
+// e-mail da conta como verificado, numa transação.
+func (r *IdentidadeOIDCRepository) Vincular(ctx context.Context, usuarioID int64, emissor, sujeito, email string, em time.Time) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO usuarios_identidades_oidc (usuario_id, emissor, sujeito, email, created_at, ultimo_login_em)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		ON CONFLICT (emissor, sujeito) DO NOTHING
+	`, usuarioID, emissor, sujeito, email, em); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE usuarios SET email_verificado_em = COALESCE(email_verificado_em, $2) WHERE id = $1
+	`, usuarioID, em); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// CriarUsuario provisiona a conta e o vínculo numa transação; preenche ID, CreatedAt e UpdatedAt de u.
+func (r *IdentidadeOIDCRepository) CriarUsuario(ctx context.Context, u *models.Usuario, emissor, sujeito string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO usuarios (nome, email, senha, perfil, enabled, email_verificado_em)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`, u.Nome, u.Email, u.Senha, u.Perfil, u.Enabled, u.EmailVerificadoEm).Scan(&u.ID, &u.CreatedAt, &u.UpdatedAt); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO usuarios_identidades_oidc (usuario_id, emissor, sujeito, email, created_at, ultimo_login_em)
+		VALUES ($1, $2, $3, $4, $5, $5)
+	`, u.ID, emissor, sujeito, u.Email, u.CreatedAt); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// ElevarPerfil troca o perfil só se a conta ainda for USER (pendente de provisão); false se não mudou.
+func (r *IdentidadeOIDCRepository) ElevarPerfil(ctx context.Context, usuarioID int64, perfil string) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE usuarios SET perfil = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND perfil = $3
+	`, usuarioID, perfil, models.PerfilUser)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
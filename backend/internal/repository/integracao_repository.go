@@ -18,52 +18,52 @@ func NewIntegracaoRepository(db *pgxpool.Pool) *IntegracaoRepository {
 	return &IntegracaoRepository{db: db}
 }
 
-func (r *IntegracaoRepository) CreateCliente(ctx context.Context, c *models.IntegracaoCliente) error {
-	query := `
-		INSERT INTO integracao_clientes (nome, actor_user_id, key_prefix, key_hash, ativo, criado_por_admin_id)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, created_at, updated_at
-	`
-	return r.db.QueryRow(ctx, query, c.Nome, c.ActorUserID, c.KeyPrefix, c.KeyHash, c.Ativo, c.CriadoPorAdminID).
-		Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
-}
+const integracaoClienteColumns = `id, nome, actor_user_id, key_prefix, key_hash, ativo, revogado_em, criado_por_admin_id, created_at, updated_at,
+	oauth_client_id, COALESCE(oauth_secret_hash, ''), tokens_validos_desde,
+	chave_expira_em, key_prefix_anterior, COALESCE(key_hash_anterior, ''), chave_anterior_expira_em, ip_allowlist, aviso_expiracao_ref`
 
-func (r *IntegracaoRepository) GetByID(ctx context.Context, id int64) (*models.IntegracaoCliente, error) {
-	query := `
-		SELECT id, nome, actor_user_id, key_prefix, key_hash, ativo, revogado_em, criado_por_admin_id, created_at, updated_at
-		FROM integracao_clientes WHERE id = $1
-	`
+func scanIntegracaoCliente(row pgx.Row) (*models.IntegracaoCliente, error) {
 	var c models.IntegracaoCliente
-	err := r.db.QueryRow(ctx, query, id).Scan(
+	err := row.Scan(
 		&c.ID, &c.Nome, &c.ActorUserID, &c.KeyPrefix, &c.KeyHash, &c.Ativo, &c.RevogadoEm,
 		&c.CriadoPorAdminID, &c.CreatedAt, &c.UpdatedAt,
+		&c.OAuthClientID, &c.OAuthSecretHash, &c.TokensValidosDesde,
+		&c.ChaveExpiraEm, &c.KeyPrefixAnterior, &c.KeyHashAnterior, &c.ChaveAnteriorExpiraEm, &c.IPAllowlist, &c.AvisoExpiracaoRef,
 	)
-	if err == pgx.ErrNoRows {
-		return nil, pgx.ErrNoRows
-	}
 	if err != nil {
 		return nil, err
 	}
 	return &c, nil
 }
 
-func (r *IntegracaoRepository) GetByKeyPrefix(ctx context.Context, prefix string) (*models.IntegracaoCliente, error) {
+func (r *IntegracaoRepository) CreateCliente(ctx context.Context, c *models.IntegracaoCliente) error {
 	query := `
-		SELECT id, nome, actor_user_id, key_prefix, key_hash, ativo, revogado_em, criado_por_admin_id, created_at, updated_at
-		FROM integracao_clientes WHERE key_prefix = $1
+		INSERT INTO integracao_clientes (nome, actor_user_id, key_prefix, key_hash, ativo, criado_por_admin_id, chave_expira_em, ip_allowlist)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at, updated_at
 	`
-	var c models.IntegracaoCliente
-	err := r.db.QueryRow(ctx, query, prefix).Scan(
-		&c.ID, &c.Nome, &c.ActorUserID, &c.KeyPrefix, &c.KeyHash, &c.Ativo, &c.RevogadoEm,
-		&c.CriadoPorAdminID, &c.CreatedAt, &c.UpdatedAt,
-	)
-	if err == pgx.ErrNoRows {
-		return nil, pgx.ErrNoRows
+	if c.IPAllowlist == nil {
+		c.IPAllowlist = []string{}
 	}
-	if err != nil {
-		return nil, err
-	}
-	return &c, nil
+	return r.db.QueryRow(ctx, query, c.Nome, c.ActorUserID, c.KeyPrefix, c.KeyHash, c.Ativo, c.CriadoPorAdminID, c.ChaveExpiraEm, c.IPAllowlist).
+		Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
+}
+
+func (r *IntegracaoRepository) GetByID(ctx context.Context, id int64) (*models.IntegracaoCliente, error) {
+	query := `SELECT ` + integracaoClienteColumns + ` FROM integracao_clientes WHERE id = $1`
+	return scanIntegracaoCliente(r.db.QueryRow(ctx, query, id))
+}
+
+// GetByKeyPrefix busca pelo prefixo da chave atual ou da chave anterior (período de graça da rotação).
+func (r *IntegracaoRepository) GetByKeyPrefix(ctx context.Context, prefix string) (*models.IntegracaoCliente, error) {
+	query := `SELECT ` + integracaoClienteColumns + ` FROM integracao_clientes WHERE key_prefix = $1 OR key_prefix_anterior = $1`
+	return scanIntegracaoCliente(r.db.QueryRow(ctx, query, prefix))
+}
+
+// GetByOAuthClientID busca o cliente pelo client_id OAuth2 (público).
+func (r *IntegracaoRepository) GetByOAuthClientID(ctx context.Context, clientID string) (*models.IntegracaoCliente, error) {
+	query := `SELECT ` + integracaoClienteColumns + ` FROM integracao_clientes WHERE oauth_client_id = $1`
+	return scanIntegracaoCliente(r.db.QueryRow(ctx, query, clientID))
 }
 
 func (r *IntegracaoRepository) List(ctx context.Context, limit, offset int) ([]*models.IntegracaoCliente, error) {
@@ -71,7 +71,7 @@ func (r *IntegracaoRepository) List(ctx context.Context, limit, offset int) ([]*
 		limit = 50
 	}
 	query := `
-		SELECT id, nome, actor_user_id, key_prefix, key_hash, ativo, revogado_em, criado_por_admin_id, created_at, updated_at
+		SELECT ` + integracaoClienteColumns + `
 		FROM integracao_clientes
 		ORDER BY nome ASC
 		LIMIT $1 OFFSET $2
@@ -83,14 +83,11 @@ func (r *IntegracaoRepository) List(ctx context.Context, limit, offset int) ([]*
 	defer rows.Close()
 	var out []*models.IntegracaoCliente
 	for rows.Next() {
-		var c models.IntegracaoCliente
-		if err := rows.Scan(
-			&c.ID, &c.Nome, &c.ActorUserID, &c.KeyPrefix, &c.KeyHash, &c.Ativo, &c.RevogadoEm,
-			&c.CriadoPorAdminID, &c.CreatedAt, &c.UpdatedAt,
-		); err != nil {
+		c, err := scanIntegracaoCliente(rows)
+		if err != nil {
 			return nil, err
 		}
-		out = append(out, &c)
+		out = append(out, c)
 	}
 	return out, rows.Err()
 }
@@ -111,26 +108,141 @@ func (r *IntegracaoRepository) UpdateCliente(ctx context.Context, c *models.Inte
 	return r.db.QueryRow(ctx, query, c.ID, c.Nome, c.Ativo).Scan(&c.UpdatedAt)
 }
 
-func (r *IntegracaoRepository) UpdateKey(ctx context.Context, id int64, keyPrefix, keyHash string) error {
-	_, err := r.db.Exec(ctx, `
-		UPDATE integracao_clientes SET key_prefix = $2, key_hash = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $1
-	`, id, keyPrefix, keyHash)
+// UpdateKey substitui a chave. anteriorExpiraEm != nil mantém a chave atual válida como "anterior"
+// até essa data (período de graça); nil descarta-a imediatamente.
+func (r *IntegracaoRepository) UpdateKey(ctx context.Context, id int64, keyPrefix, keyHash string, expiraEm, anteriorExpiraEm *time.Time) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE integracao_clientes
+		SET key_prefix_anterior = CASE WHEN $5::timestamptz IS NULL THEN NULL ELSE key_prefix END,
+			key_hash_anterior = CASE WHEN $5::timestamptz IS NULL THEN NULL ELSE key_hash END,
+			chave_anterior_expira_em = $5,
+			key_prefix = $2, key_hash = $3, chave_expira_em = $4, aviso_expiracao_ref = NULL,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`, id, keyPrefix, keyHash, expiraEm, anteriorExpiraEm)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// SetChaveExpiracao agenda (ou remove, com nil) a expiração da chave atual.
+func (r *IntegracaoRepository) SetChaveExpiracao(ctx context.Context, id int64, expiraEm *time.Time) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE integracao_clientes
+		SET chave_expira_em = $2, aviso_expiracao_ref = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`, id, expiraEm)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// SetIPAllowlist grava os CIDRs de origem permitidos (lista vazia = sem restrição).
+func (r *IntegracaoRepository) SetIPAllowlist(ctx context.Context, id int64, cidrs []string) error {
+	if cidrs == nil {
+		cidrs = []string{}
+	}
+	tag, err := r.db.Exec(ctx, `
+		UPDATE integracao_clientes SET ip_allowlist = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1
+	`, id, cidrs)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// ListChavesExpirandoSemAviso lista clientes ativos cuja chave expira até `ate` e que ainda não
+// foram avisados para essa data de expiração.
+func (r *IntegracaoRepository) ListChavesExpirandoSemAviso(ctx context.Context, ate time.Time) ([]*models.IntegracaoCliente, error) {
+	query := `
+		SELECT ` + integracaoClienteColumns + `
+		FROM integracao_clientes
+		WHERE ativo = true AND revogado_em IS NULL
+		  AND chave_expira_em IS NOT NULL AND chave_expira_em <= $1
+		  AND aviso_expiracao_ref IS DISTINCT FROM chave_expira_em
+		ORDER BY chave_expira_em ASC
+	`
+	rows, err := r.db.Query(ctx, query, ate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*models.IntegracaoCliente
+	for rows.Next() {
+		c, err := scanIntegracaoCliente(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// MarcarAvisoExpiracao regista que o aviso para a expiração `ref` já foi enviado.
+func (r *IntegracaoRepository) MarcarAvisoExpiracao(ctx context.Context, id int64, ref time.Time) error {
+	_, err := r.db.Exec(ctx, `UPDATE integracao_clientes SET aviso_expiracao_ref = $2 WHERE id = $1`, id, ref)
 	return err
 }
 
+// Revogar desativa o cliente e invalida access tokens OAuth já emitidos (tokens_validos_desde).
 func (r *IntegracaoRepository) Revogar(ctx context.Context, id int64) error {
 	_, err := r.db.Exec(ctx, `
-		UPDATE integracao_clientes SET ativo = false, revogado_em = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE id = $1
+		UPDATE integracao_clientes
+		SET ativo = false, revogado_em = CURRENT_TIMESTAMP, tokens_validos_desde = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
 	`, id)
 	return err
 }
 
-func (r *IntegracaoRepository) Reativar(ctx context.Context, id int64, keyPrefix, keyHash string) error {
+// SetOAuthCredentials grava client_id e hash do segredo; tokens emitidos com o segredo anterior deixam de valer.
+func (r *IntegracaoRepository) SetOAuthCredentials(ctx context.Context, id int64, clientID, secretHash string) error {
 	tag, err := r.db.Exec(ctx, `
 		UPDATE integracao_clientes
-		SET ativo = true, revogado_em = NULL, key_prefix = $2, key_hash = $3, updated_at = CURRENT_TIMESTAMP
+		SET oauth_client_id = $2, oauth_secret_hash = $3, tokens_validos_desde = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`, id, clientID, secretHash)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// RevogarTokens invalida os access tokens OAuth emitidos até agora, sem desativar o cliente.
+func (r *IntegracaoRepository) RevogarTokens(ctx context.Context, id int64) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE integracao_clientes SET tokens_validos_desde = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE id = $1
+	`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (r *IntegracaoRepository) Reativar(ctx context.Context, id int64, keyPrefix, keyHash string, expiraEm *time.Time) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE integracao_clientes
+		SET ativo = true, revogado_em = NULL, key_prefix = $2, key_hash = $3, chave_expira_em = $4,
+			key_prefix_anterior = NULL, key_hash_anterior = NULL, chave_anterior_expira_em = NULL, aviso_expiracao_ref = NULL,
+			updated_at = CURRENT_TIMESTAMP
 		WHERE id = $1 AND revogado_em IS NOT NULL
-	`, id, keyPrefix, keyHash)
+	`, id, keyPrefix, keyHash, expiraEm)
 	if err != nil {
 		return err
 	}
@@ -222,14 +334,18 @@ func (r *IntegracaoRepository) LoadClienteRelations(ctx context.Context, c *mode
 	return nil
 }
 
+const integracaoChamadaColumns = `id, cliente_id, method, path, status_code, correlation_id, idempotency_key, duracao_ms, erro_resumo,
+	motivo_rejeicao, ip, created_at`
+
 func (r *IntegracaoRepository) InsertChamada(ctx context.Context, ch *models.IntegracaoChamada) error {
 	query := `
-		INSERT INTO integracao_chamadas (cliente_id, method, path, status_code, correlation_id, idempotency_key, duracao_ms, erro_resumo)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO integracao_chamadas (cliente_id, method, path, status_code, correlation_id, idempotency_key, duracao_ms, erro_resumo, motivo_rejeicao, ip)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		RETURNING id, created_at
 	`
 	return r.db.QueryRow(ctx, query,
 		ch.ClienteID, ch.Method, ch.Path, ch.StatusCode, ch.CorrelationID, ch.IdempotencyKey, ch.DuracaoMs, ch.ErroResumo,
+		ch.MotivoRejeicao, ch.IP,
 	).Scan(&ch.ID, &ch.CreatedAt)
 }
 
@@ -237,10 +353,27 @@ func (r *IntegracaoRepository) ListChamadas(ctx context.Context, clienteID int64
 	if limit <= 0 {
 		limit = 50
 	}
-	rows, err := r.db.Query(ctx, `
-		SELECT id, cliente_id, method, path, status_code, correlation_id, idempotency_key, duracao_ms, erro_resumo, created_at
+	return r.queryChamadas(ctx, `
+		SELECT `+integracaoChamadaColumns+`
 		FROM integracao_chamadas WHERE cliente_id = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3
 	`, clienteID, limit, offset)
+}
+
+// ListRejeicoes lista chamadas rejeitadas (com motivo), inclusive de clientes não identificados.
+func (r *IntegracaoRepository) ListRejeicoes(ctx context.Context, motivo string, limit, offset int) ([]*models.IntegracaoChamada, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	return r.queryChamadas(ctx, `
+		SELECT `+integracaoChamadaColumns+`
+		FROM integracao_chamadas
+		WHERE motivo_rejeicao IS NOT NULL AND ($1 = '' OR motivo_rejeicao = $1)
+		ORDER BY created_at DESC LIMIT $2 OFFSET $3
+	`, motivo, limit, offset)
+}
+
+func (r *IntegracaoRepository) queryChamadas(ctx context.Context, query string, args ...interface{}) ([]*models.IntegracaoChamada, error) {
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -250,7 +383,7 @@ func (r *IntegracaoRepository) ListChamadas(ctx context.Context, clienteID int64
 		var ch models.IntegracaoChamada
 		if err := rows.Scan(
 			&ch.ID, &ch.ClienteID, &ch.Method, &ch.Path, &ch.StatusCode, &ch.CorrelationID,
-			&ch.IdempotencyKey, &ch.DuracaoMs, &ch.ErroResumo, &ch.CreatedAt,
+			&ch.IdempotencyKey, &ch.DuracaoMs, &ch.ErroResumo, &ch.MotivoRejeicao, &ch.IP, &ch.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
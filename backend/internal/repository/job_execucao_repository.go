@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type JobExecucaoRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewJobExecucaoRepository(db *pgxpool.Pool) *JobExecucaoRepository {
+	return &JobExecucaoRepository{db: db}
+}
+
+const jobExecucaoColumns = `id, job, agendado_para, origem, status, instancia, disparado_por, iniciado_em, ultimo_sinal_em, finalizado_em, resultado, erro`
+
+// Iniciar reivindica a execução: devolve false se o slot já foi reivindicado por outra réplica ou se o job
+// já tem uma execução em curso (índices únicos parciais).
+func (r *JobExecucaoRepository) Iniciar(ctx context.Context, row *models.JobExecucao) (bool, error) {
+	const q = `
+		INSERT INTO jobs_execucoes (job, agendado_para, origem, status, instancia, disparado_por)
+		VALUES ($1, $2, $3, 'EM_EXECUCAO', $4, $5)
+		ON CONFLICT DO NOTHING
+		RETURNING id, status, iniciado_em
+	`
+	err := r.db.QueryRow(ctx, q, row.Job, row.AgendadoPara, row.Origem, row.Instancia, row.DisparadoPor).
+		Scan(&row.ID, &row.Status, &row.IniciadoEm)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *JobExecucaoRepository) Finalizar(ctx context.Context, id int64, status string, resultado []byte, erro *string) error {
+	const q = `
+		UPDATE jobs_execucoes
+		SET status = $2, resultado = $3, erro = $4, finalizado_em = NOW()
+		WHERE id = $1 AND status = 'EM_EXECUCAO'
+	`
+	var res interface{}
+	if len(resultado) > 0 {
+		res = resultado
+	}
+	tag, err := r.db.Exec(ctx, q, id, status, res, erro)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// Sinalizar renova o sinal de vida de uma execução em curso.
+func (r *JobExecucaoRepository) Sinalizar(ctx context.Context, id int64) error {
+	_, err := r.db.Exec(ctx, `UPDATE jobs_execucoes SET ultimo_sinal_em = NOW() WHERE id = $1 AND status = 'EM_EXECUCAO'`, id)
+	return err
+}
+
+// MarcarAbandonadas encerra como ERRO as execuções em curso sem sinal de vida desde o limite (réplica
+// que caiu a meio), libertando o job para nova execução.
+func (r *JobExecucaoRepository) MarcarAbandonadas(ctx context.Context, job string, semSinalDesde time.Time) (int64, error) {
+	const q = `
+		UPDATE jobs_execucoes
+		SET status = 'ERRO', erro = 'execução abandonada (instância encerrada sem concluir)', finalizado_em = NOW()
+		WHERE job = $1 AND status = 'EM_EXECUCAO' AND COALESCE(ultimo_sinal_em, iniciado_em) < $2
+	`
+	tag, err := r.db.Exec(ctx, q, job, semSinalDesde)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// SlotReivindicado indica se já existe execução (de qualquer réplica) para o slot.
+func (r *JobExecucaoRepository) SlotReivindicado(ctx context.Context, job string, agendadoPara time.Time) (bool, error) {
+	var existe bool
+	err := r.db.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM jobs_execucoes WHERE job = $1 AND agendado_para = $2)`,
+		job, agendadoPara,
+	).Scan(&existe)
+	return existe, err
+}
+
+// UltimoSlot slot agendado mais recente já reivindicado (qualquer estado); nil se o job nunca correu pela agenda.
+func (r *JobExecucaoRepository) UltimoSlot(ctx context.Context, job string) (*time.Time, error) {
+	var slot *time.Time
+	err := r.db.QueryRow(ctx, `SELECT MAX(agendado_para) FROM jobs_execucoes WHERE job = $1`, job).Scan(&slot)
+	return slot, err
+}
+
+func (r *JobExecucaoRepository) List(ctx context.Context, f models.JobExecucaoFiltro) ([]*models.JobExecucao, error) {
+	if f.Limit <= 0 || f.Limit > 200 {
+		f.Limit = 50
+	}
+	if f.Offset < 0 {
+		f.Offset = 0
+	}
+	return r.query(ctx, `
+		SELECT `+jobExecucaoColumns+`
+		FROM jobs_execucoes
+		WHERE ($1 = '' OR job = $1) AND ($2 = '' OR status = $2)
+		ORDER BY iniciado_em DESC, id DESC
+		LIMIT $3 OFFSET $4
+	`, f.Job, f.Status, f.Limit, f.Offset)
+}
+
+// GetByID devolve nil, nil quando a execução não existe.
+func (r *JobExecucaoRepository) GetByID(ctx context.Context, id int64) (*models.JobExecucao, error) {
+	list, err := r.query(ctx, `SELECT `+jobExecucaoColumns+` FROM jobs_execucoes WHERE id = $1`, id)
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+	return list[0], nil
+}
+
+// UltimasPorJob última execução de cada job, para o painel de administração.
+func (r *JobExecucaoRepository) UltimasPorJob(ctx context.Context) (map[string]*models.JobExecucao, error) {
+	list, err := r.query(ctx, `
+		SELECT DISTINCT ON (job) `+jobExecucaoColumns+`
+		FROM jobs_execucoes
+		ORDER BY job, iniciado_em DESC, id DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]*models.JobExecucao, len(list))
+	for _, e := range list {
+		out[e.Job] = e
+	}
+	return out, nil
+}
+
+// PurgarAntigas remove o histórico finalizado anterior ao limite.
+func (r *JobExecucaoRepository) PurgarAntigas(ctx context.Context, antesDe time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM jobs_execucoes WHERE status <> 'EM_EXECUCAO' AND iniciado_em < $1`, antesDe)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *JobExecucaoRepository) query(ctx context.Context, query string, args ...interface{}) ([]*models.JobExecucao, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []*models.JobExecucao{}
+	for rows.Next() {
+		var e models.JobExecucao
+		var resultado []byte
+		if err := rows.Scan(
+			&e.ID, &e.Job, &e.AgendadoPara, &e.Origem, &e.Status, &e.Instancia, &e.DisparadoPor,
+			&e.IniciadoEm, &e.UltimoSinalEm, &e.FinalizadoEm, &resultado, &e.Erro,
+		); err != nil {
+			return nil, err
+		}
+		if len(resultado) > 0 {
+			e.Resultado = resultado
+		}
+		out = append(out, &e)
+	}
+	return out, rows.Err()
+}
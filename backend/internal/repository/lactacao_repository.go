@@ -175,3 +175,10 @@ func (r *LactacaoRepository) GetEmAndamentoByAnimalIDTx(ctx context.Context, tx
 	}
 	return &l, err
 }
+
+// ExistsByPartoIDTx indica se já existe lactação vinculada ao parto.
+func (r *LactacaoRepository) ExistsByPartoIDTx(ctx context.Context, tx pgx.Tx, partoID int64) (bool, error) {
+	var exists bool
+	err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM lactacoes WHERE parto_id = $1)`, partoID).Scan(&exists)
+	return exists, err
+}
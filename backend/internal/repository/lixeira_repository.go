@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type LixeiraRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewLixeiraRepository(db *pgxpool.Pool) *LixeiraRepository {
+	return &LixeiraRepository{db: db}
+}
+
+// lixeiraUnionSQL reúne os registos excluídos logicamente da fazenda $1 (produção usa a fazenda do animal).
+const lixeiraUnionSQL = `
+SELECT 'PARTO' AS entidade, p.id, p.animal_id, a.identificacao, p.fazenda_id, p.data AS data_evento,
+       p.excluido_em, p.excluido_por, u.nome AS excluido_por_nome
+FROM partos p
+INNER JOIN animais a ON a.id = p.animal_id
+LEFT JOIN usuarios u ON u.id = p.excluido_por
+WHERE p.fazenda_id = $1 AND p.excluido_em IS NOT NULL
+
+UNION ALL
+
+SELECT 'CIO', c.id, c.animal_id, a.identificacao, c.fazenda_id, c.data_detectado,
+       c.excluido_em, c.excluido_por, u.nome
+FROM cios c
+INNER JOIN animais a ON a.id = c.animal_id
+LEFT JOIN usuarios u ON u.id = c.excluido_por
+WHERE c.fazenda_id = $1 AND c.excluido_em IS NOT NULL
+
+UNION ALL
+
+SELECT 'COBERTURA', cb.id, cb.animal_id, a.identificacao, cb.fazenda_id, cb.data,
+       cb.excluido_em, cb.excluido_por, u.nome
+FROM coberturas cb
+INNER JOIN animais a ON a.id = cb.animal_id
+LEFT JOIN usuarios u ON u.id = cb.excluido_por
+WHERE cb.fazenda_id = $1 AND cb.excluido_em IS NOT NULL
+
+UNION ALL
+
+SELECT 'PRODUCAO_LEITE', pl.id, pl.animal_id, a.identificacao, a.fazenda_id, pl.data_hora,
+       pl.excluido_em, pl.excluido_por, u.nome
+FROM producao_leite pl
+INNER JOIN animais a ON a.id = pl.animal_id
+LEFT JOIN usuarios u ON u.id = pl.excluido_por
+WHERE a.fazenda_id = $1 AND pl.excluido_em IS NOT NULL
+`
+
+// ListByFazendaID lista itens da lixeira excluídos a partir de desde (mais recentes primeiro) e devolve o total.
+// entidade vazia = todas.
+func (r *LixeiraRepository) ListByFazendaID(ctx context.Context, fazendaID int64, entidade string, desde time.Time, limit, offset int) ([]*models.LixeiraItem, int64, error) {
+	query := `
+		SELECT l.entidade, l.id, l.animal_id, l.identificacao, l.fazenda_id, l.data_evento,
+		       l.excluido_em, l.excluido_por, l.excluido_por_nome, COUNT(*) OVER()
+		FROM (` + lixeiraUnionSQL + `) l
+		WHERE ($2 = '' OR l.entidade = $2) AND l.excluido_em >= $3
+		ORDER BY l.excluido_em DESC, l.id DESC
+		LIMIT $4 OFFSET $5
+	`
+	rows, err := r.db.Query(ctx, query, fazendaID, entidade, desde, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	list := []*models.LixeiraItem{}
+	var total int64
+	for rows.Next() {
+		var it models.LixeiraItem
+		if err := rows.Scan(&it.Entidade, &it.ID, &it.AnimalID, &it.Identificacao, &it.FazendaID, &it.DataEvento,
+			&it.ExcluidoEm, &it.ExcluidoPor, &it.ExcluidoPorNome, &total); err != nil {
+			return nil, 0, err
+		}
+		list = append(list, &it)
+	}
+	return list, total, rows.Err()
+}
+
+// GetItem devolve o item da lixeira da fazenda; pgx.ErrNoRows se não estiver na lixeira ou for de outra fazenda.
+func (r *LixeiraRepository) GetItem(ctx context.Context, fazendaID int64, entidade string, id int64) (*models.LixeiraItem, error) {
+	query := `
+		SELECT l.entidade, l.id, l.animal_id, l.identificacao, l.fazenda_id, l.data_evento,
+		       l.excluido_em, l.excluido_por, l.excluido_por_nome
+		FROM (` + lixeiraUnionSQL + `) l
+		WHERE l.entidade = $2 AND l.id = $3
+	`
+	var it models.LixeiraItem
+	err := r.db.QueryRow(ctx, query, fazendaID, entidade, id).Scan(&it.Entidade, &it.ID, &it.AnimalID, &it.Identificacao,
+		&it.FazendaID, &it.DataEvento, &it.ExcluidoEm, &it.ExcluidoPor, &it.ExcluidoPorNome)
+	if err == pgx.ErrNoRows {
+		return nil, pgx.ErrNoRows
+	}
+	return &it, err
+}
+
+// PurgarExcluidosAntes apaga definitivamente os registos excluídos antes de limite (fim da retenção).
+// Crias do parto seguem por CASCADE; lactações ficam sem parto_id (SET NULL).
+func (r *LixeiraRepository) PurgarExcluidosAntes(ctx context.Context, limite time.Time) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	var total int64
+	for _, q := range []string{
+		`DELETE FROM producao_leite WHERE excluido_em IS NOT NULL AND excluido_em < $1`,
+		`DELETE FROM partos WHERE excluido_em IS NOT NULL AND excluido_em < $1`,
+		`DELETE FROM coberturas WHERE excluido_em IS NOT NULL AND excluido_em < $1`,
+		`DELETE FROM cios WHERE excluido_em IS NOT NULL AND excluido_em < $1`,
+	} {
+		cmd, err := tx.Exec(ctx, q, limite)
+		if err != nil {
+			return 0, err
+		}
+		total += cmd.RowsAffected()
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type LoteDesempenhoRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewLoteDesempenhoRepository(db *pgxpool.Pool) *LoteDesempenhoRepository {
+	return &LoteDesempenhoRepository{db: db}
+}
+
+// LoteProducaoDiaLinha litros de um lote num dia civil (LoteID nil = animais sem lote).
+type LoteProducaoDiaLinha struct {
+	LoteID  *int64
+	Data    time.Time
+	Litros  float64
+	Animais int
+}
+
+// LoteIndicadoresLinha estado atual dos animais no rebanho de um lote.
+type LoteIndicadoresLinha struct {
+	LoteID               int64
+	Animais              int
+	EmLactacao           int
+	DELMedio             *float64
+	CasosSaudeAbertos    int
+	AnimaisComCasoAberto int
+	Prenhes              int
+	PorStatusReprodutivo map[string]int
+}
+
+// sqlLoteNaProducao lote do animal no instante da ordenha p.data_hora: destino da última movimentação
+// até esse instante; sem movimentação anterior, a origem da primeira movimentação; sem movimentações,
+// o lote atual (mesmo critério do rebanho na data, ao instante).
+const sqlLoteNaProducao = `
+	CASE
+		WHEN EXISTS (SELECT 1 FROM movimentacoes_lote m WHERE m.animal_id = p.animal_id AND m.data <= p.data_hora) THEN (
+			SELECT m.lote_destino_id FROM movimentacoes_lote m
+			WHERE m.animal_id = p.animal_id AND m.data <= p.data_hora
+			ORDER BY m.data DESC, m.id DESC LIMIT 1
+		)
+		WHEN EXISTS (SELECT 1 FROM movimentacoes_lote m WHERE m.animal_id = p.animal_id) THEN (
+			SELECT m.lote_origem_id FROM movimentacoes_lote m
+			WHERE m.animal_id = p.animal_id
+			ORDER BY m.data ASC, m.id ASC LIMIT 1
+		)
+		ELSE a.lote_id
+	END`
+
+// ProducaoDiariaPorLote soma a produção da fazenda por lote (no momento da ordenha) e dia civil em
+// [de, ate]. Com loteID, devolve só esse lote.
+func (r *LoteDesempenhoRepository) ProducaoDiariaPorLote(ctx context.Context, fazendaID int64, loteID *int64, de, ate time.Time) ([]LoteProducaoDiaLinha, error) {
+	query := `
+		WITH prod AS (
+			SELECT p.animal_id, p.data_hora::date AS dia, p.quantidade, ` + sqlLoteNaProducao + ` AS lote_id
+			FROM producao_leite p
+			JOIN animais a ON a.id = p.animal_id
+			WHERE a.fazenda_id = $1
+			AND p.excluido_em IS NULL
+			AND p.data_hora >= $2::date
+			AND p.data_hora < $3::date + 1
+		)
+		SELECT lote_id, dia, SUM(quantidade)::float8, COUNT(DISTINCT animal_id)
+		FROM prod
+		WHERE $4::bigint IS NULL OR lote_id = $4
+		GROUP BY lote_id, dia
+		ORDER BY dia ASC, lote_id ASC NULLS LAST
+	`
+	rows, err := r.db.Query(ctx, query, fazendaID, de, ate, loteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	list := []LoteProducaoDiaLinha{}
+	for rows.Next() {
+		var ln LoteProducaoDiaLinha
+		if err := rows.Scan(&ln.LoteID, &ln.Data, &ln.Litros, &ln.Animais); err != nil {
+			return nil, err
+		}
+		list = append(list, ln)
+	}
+	return list, rows.Err()
+}
+
+// IndicadoresPorLote DEL médio, casos de saúde em aberto e gestação dos animais no rebanho, por lote
+// atual. Com loteID, devolve só esse lote.
+func (r *LoteDesempenhoRepository) IndicadoresPorLote(ctx context.Context, fazendaID int64, loteID *int64) (map[int64]*LoteIndicadoresLinha, error) {
+	query := `
+		SELECT a.lote_id,
+			COUNT(*),
+			COUNT(lc.data_inicio),
+			AVG(CURRENT_DATE - lc.data_inicio)::float8,
+			COALESCE(SUM(sc.n), 0),
+			COUNT(*) FILTER (WHERE sc.n > 0),
+			COUNT(*) FILTER (WHERE EXISTS (
+				SELECT 1 FROM gestacoes g WHERE g.animal_id = a.id AND g.status = $3
+			))
+		FROM animais a
+		LEFT JOIN LATERAL (
+			SELECT l.data_inicio FROM lactacoes l
+			WHERE l.animal_id = a.id
+			AND l.fazenda_id = a.fazenda_id
+			AND l.data_fim IS NULL
+			AND (l.status IS NULL OR l.status = 'EM_ANDAMENTO')
+			ORDER BY l.data_inicio DESC
+			LIMIT 1
+		) lc ON TRUE
+		LEFT JOIN LATERAL (
+			SELECT COUNT(*) AS n FROM animal_saude s WHERE s.animal_id = a.id AND s.status = $4
+		) sc ON TRUE
+		WHERE a.fazenda_id = $1
+		AND a.lote_id IS NOT NULL
+		AND ($2::bigint IS NULL OR a.lote_id = $2)
+		AND ` + SQLNoRebanhoFor("a") + `
+		GROUP BY a.lote_id
+	`
+	rows, err := r.db.Query(ctx, query, fazendaID, loteID, models.GestacaoStatusConfirmada, models.AnimalSaudeStatusAtivo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := map[int64]*LoteIndicadoresLinha{}
+	for rows.Next() {
+		ln := &LoteIndicadoresLinha{PorStatusReprodutivo: map[string]int{}}
+		if err := rows.Scan(&ln.LoteID, &ln.Animais, &ln.EmLactacao, &ln.DELMedio,
+			&ln.CasosSaudeAbertos, &ln.AnimaisComCasoAberto, &ln.Prenhes); err != nil {
+			return nil, err
+		}
+		out[ln.LoteID] = ln
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = r.db.Query(ctx, `
+		SELECT a.lote_id, a.status_reprodutivo, COUNT(*)
+		FROM animais a
+		WHERE a.fazenda_id = $1
+		AND a.lote_id IS NOT NULL
+		AND a.status_reprodutivo IS NOT NULL
+		AND ($2::bigint IS NULL OR a.lote_id = $2)
+		AND `+SQLNoRebanhoFor("a")+`
+		GROUP BY a.lote_id, a.status_reprodutivo
+	`, fazendaID, loteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			id     int64
+			status string
+			n      int
+		)
+		if err := rows.Scan(&id, &status, &n); err != nil {
+			return nil, err
+		}
+		if ln := out[id]; ln != nil {
+			ln.PorStatusReprodutivo[status] = n
+		}
+	}
+	return out, rows.Err()
+}
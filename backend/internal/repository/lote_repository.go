@@ -19,21 +19,41 @@ func NewLoteRepository(db *pgxpool.Pool) *LoteRepository {
 	return &LoteRepository{db: db}
 }
 
+// loteSelectColumns inclui a ocupação atual: animais no rebanho com lote_id = lote (BR-LOTE-008).
+var loteSelectColumns = `l.id, l.nome, l.fazenda_id, l.tipo, l.descricao, l.ativo, l.capacidade,
+	(SELECT COUNT(*) FROM animais a WHERE a.lote_id = l.id AND ` + SQLNoRebanhoFor("a") + `) AS ocupacao,
+	l.created_at, l.updated_at`
+
+func scanLote(l *models.Lote, row pgx.Row) error {
+	return row.Scan(&l.ID, &l.Nome, &l.FazendaID, &l.Tipo, &l.Descricao, &l.Ativo, &l.Capacidade, &l.Ocupacao, &l.CreatedAt, &l.UpdatedAt)
+}
+
 func (r *LoteRepository) Create(ctx context.Context, lote *models.Lote) error {
 	query := `
-		INSERT INTO lotes (nome, fazenda_id, tipo, descricao, ativo)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO lotes (nome, fazenda_id, tipo, descricao, ativo, capacidade)
+		VALUES ($1, $2, $3, $4, $5, $6)
 		RETURNING id, created_at, updated_at
 	`
-	err := r.db.QueryRow(ctx, query, lote.Nome, lote.FazendaID, lote.Tipo, lote.Descricao, lote.Ativo).
+	err := r.db.QueryRow(ctx, query, lote.Nome, lote.FazendaID, lote.Tipo, lote.Descricao, lote.Ativo, lote.Capacidade).
 		Scan(&lote.ID, &lote.CreatedAt, &lote.UpdatedAt)
 	return err
 }
 
 func (r *LoteRepository) GetByID(ctx context.Context, id int64) (*models.Lote, error) {
-	query := `SELECT id, nome, fazenda_id, tipo, descricao, ativo, created_at, updated_at FROM lotes WHERE id = $1`
+	query := `SELECT ` + loteSelectColumns + ` FROM lotes l WHERE l.id = $1`
+	var l models.Lote
+	err := scanLote(&l, r.db.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, pgx.ErrNoRows
+	}
+	return &l, err
+}
+
+// GetByIDTx lê o lote dentro da transação bloqueando a linha (movimentação em lote concorrente).
+func (r *LoteRepository) GetByIDTx(ctx context.Context, tx pgx.Tx, id int64) (*models.Lote, error) {
+	query := `SELECT ` + loteSelectColumns + ` FROM lotes l WHERE l.id = $1 FOR UPDATE OF l`
 	var l models.Lote
-	err := r.db.QueryRow(ctx, query, id).Scan(&l.ID, &l.Nome, &l.FazendaID, &l.Tipo, &l.Descricao, &l.Ativo, &l.CreatedAt, &l.UpdatedAt)
+	err := scanLote(&l, tx.QueryRow(ctx, query, id))
 	if err == pgx.ErrNoRows {
 		return nil, pgx.ErrNoRows
 	}
@@ -41,7 +61,7 @@ func (r *LoteRepository) GetByID(ctx context.Context, id int64) (*models.Lote, e
 }
 
 func (r *LoteRepository) GetByFazendaID(ctx context.Context, fazendaID int64) ([]*models.Lote, error) {
-	query := `SELECT id, nome, fazenda_id, tipo, descricao, ativo, created_at, updated_at FROM lotes WHERE fazenda_id = $1 ORDER BY nome ASC`
+	query := `SELECT ` + loteSelectColumns + ` FROM lotes l WHERE l.fazenda_id = $1 ORDER BY l.nome ASC`
 	rows, err := r.db.Query(ctx, query, fazendaID)
 	if err != nil {
 		return nil, err
@@ -50,7 +70,7 @@ func (r *LoteRepository) GetByFazendaID(ctx context.Context, fazendaID int64) ([
 	var list []*models.Lote
 	for rows.Next() {
 		var l models.Lote
-		if err := rows.Scan(&l.ID, &l.Nome, &l.FazendaID, &l.Tipo, &l.Descricao, &l.Ativo, &l.CreatedAt, &l.UpdatedAt); err != nil {
+		if err := scanLote(&l, rows); err != nil {
 			return nil, err
 		}
 		list = append(list, &l)
@@ -62,8 +82,8 @@ func (r *LoteRepository) Update(ctx context.Context, lote *models.Lote) error {
 	if lote.ID <= 0 {
 		return fmt.Errorf("id do lote invalido: %d", lote.ID)
 	}
-	query := `UPDATE lotes SET nome = $1, tipo = $2, descricao = $3, ativo = $4, updated_at = $5 WHERE id = $6`
-	cmd, err := r.db.Exec(ctx, query, lote.Nome, lote.Tipo, lote.Descricao, lote.Ativo, time.Now(), lote.ID)
+	query := `UPDATE lotes SET nome = $1, tipo = $2, descricao = $3, ativo = $4, capacidade = $5, updated_at = $6 WHERE id = $7`
+	cmd, err := r.db.Exec(ctx, query, lote.Nome, lote.Tipo, lote.Descricao, lote.Ativo, lote.Capacidade, time.Now(), lote.ID)
 	if err != nil {
 		return err
 	}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/ceialmilk/api/internal/models"
 	"github.com/jackc/pgx/v5"
@@ -23,6 +24,56 @@ func (r *MovimentacaoLoteRepository) Create(ctx context.Context, m *models.Movim
 		Scan(&m.ID, &m.CreatedAt)
 }
 
+// CreateTx grava a movimentação e atualiza animais.lote_id na mesma transação (movimentação em lote).
+func (r *MovimentacaoLoteRepository) CreateTx(ctx context.Context, tx pgx.Tx, m *models.MovimentacaoLote) error {
+	query := `INSERT INTO movimentacoes_lote (animal_id, lote_origem_id, lote_destino_id, data, motivo, usuario_id)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`
+	if err := tx.QueryRow(ctx, query, m.AnimalID, m.LoteOrigemID, m.LoteDestinoID, m.Data, m.Motivo, m.UsuarioID).
+		Scan(&m.ID, &m.CreatedAt); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx, `UPDATE animais SET lote_id = $1, updated_at = NOW() WHERE id = $2`, m.LoteDestinoID, m.AnimalID)
+	return err
+}
+
+// ListEventosOcupacao entradas e saídas do lote desde a data (inclusive), mais as baixas de animais que estavam
+// no lote, por ordem cronológica.
+func (r *MovimentacaoLoteRepository) ListEventosOcupacao(ctx context.Context, loteID int64, desde time.Time) ([]models.LoteOcupacaoEvento, error) {
+	query := `
+		SELECT CASE WHEN m.lote_destino_id = $1 THEN 'ENTRADA' ELSE 'SAIDA' END,
+			m.data, m.animal_id, a.identificacao, m.id,
+			CASE WHEN m.lote_destino_id = $1 THEN m.lote_origem_id ELSE m.lote_destino_id END,
+			CASE WHEN m.lote_destino_id = $1 THEN lo.nome ELSE ld.nome END,
+			m.motivo
+		FROM movimentacoes_lote m
+		JOIN animais a ON a.id = m.animal_id
+		LEFT JOIN lotes lo ON lo.id = m.lote_origem_id
+		LEFT JOIN lotes ld ON ld.id = m.lote_destino_id
+		WHERE (m.lote_destino_id = $1 OR m.lote_origem_id = $1)
+		  AND m.lote_origem_id IS DISTINCT FROM m.lote_destino_id
+		  AND m.data >= $2
+		UNION ALL
+		SELECT 'BAIXA', a.data_saida::timestamp, a.id, a.identificacao, NULL, NULL, NULL, a.motivo_saida
+		FROM animais a
+		WHERE a.lote_id = $1 AND a.data_saida IS NOT NULL AND a.data_saida >= $2::date AND a.data_saida <= CURRENT_DATE
+		ORDER BY 2, 5 NULLS LAST
+	`
+	rows, err := r.db.Query(ctx, query, loteID, desde)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []models.LoteOcupacaoEvento{}
+	for rows.Next() {
+		var e models.LoteOcupacaoEvento
+		if err := rows.Scan(&e.Tipo, &e.Data, &e.AnimalID, &e.AnimalIdentificacao, &e.MovimentacaoID, &e.OutroLoteID, &e.OutroLoteNome, &e.Motivo); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
 func (r *MovimentacaoLoteRepository) GetByAnimalID(ctx context.Context, animalID int64) ([]*models.MovimentacaoLote, error) {
 	query := `SELECT id, animal_id, lote_origem_id, lote_destino_id, data, motivo, usuario_id, created_at
 		FROM movimentacoes_lote WHERE animal_id = $1 ORDER BY data DESC`
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type NotificacaoRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewNotificacaoRepository(db *pgxpool.Pool) *NotificacaoRepository {
+	return &NotificacaoRepository{db: db}
+}
+
+// GetConfig devolve contacto e silêncio do utilizador (nil sem linha = padrão).
+func (r *NotificacaoRepository) GetConfig(ctx context.Context, usuarioID int64) (*models.NotificacaoConfigUsuario, error) {
+	const q = `
+		SELECT usuario_id, telefone, silencio_inicio, silencio_fim, silencio_permite_critica, updated_at
+		FROM notificacao_config_usuario
+		WHERE usuario_id = $1
+	`
+	var c models.NotificacaoConfigUsuario
+	var updatedAt time.Time
+	err := r.db.QueryRow(ctx, q, usuarioID).Scan(&c.UsuarioID, &c.Telefone, &c.SilencioInicio, &c.SilencioFim,
+		&c.SilencioPermiteCritica, &updatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.UpdatedAt = &updatedAt
+	return &c, nil
+}
+
+// ListPreferencias devolve as preferências gravadas (canais sem linha usam o padrão).
+func (r *NotificacaoRepository) ListPreferencias(ctx context.Context, usuarioID int64) ([]*models.NotificacaoPreferencia, error) {
+	const q = `
+		SELECT usuario_id, canal, ativo, severidade_minima, tipos, modo, updated_at
+		FROM notificacao_preferencias
+		WHERE usuario_id = $1
+	`
+	rows, err := r.db.Query(ctx, q, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var list []*models.NotificacaoPreferencia
+	for rows.Next() {
+		var p models.NotificacaoPreferencia
+		var updatedAt time.Time
+		if err := rows.Scan(&p.UsuarioID, &p.Canal, &p.Ativo, &p.SeveridadeMinima, &p.Tipos, &p.Modo, &updatedAt); err != nil {
+			return nil, err
+		}
+		p.UpdatedAt = &updatedAt
+		list = append(list, &p)
+	}
+	return list, rows.Err()
+}
+
+// SalvarPreferencias grava config e preferências de canais numa transação.
+func (r *NotificacaoRepository) SalvarPreferencias(ctx context.Context, cfg *models.NotificacaoConfigUsuario, prefs []models.NotificacaoPreferencia) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	const qCfg = `
+		INSERT INTO notificacao_config_usuario (usuario_id, telefone, silencio_inicio, silencio_fim, silencio_permite_critica, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (usuario_id) DO UPDATE SET
+			telefone = EXCLUDED.telefone,
+			silencio_inicio = EXCLUDED.silencio_inicio,
+			silencio_fim = EXCLUDED.silencio_fim,
+			silencio_permite_critica = EXCLUDED.silencio_permite_critica,
+			updated_at = EXCLUDED.updated_at
+	`
+	if _, err := tx.Exec(ctx, qCfg, cfg.UsuarioID, cfg.Telefone, cfg.SilencioInicio, cfg.SilencioFim, cfg.SilencioPermiteCritica); err != nil {
+		return err
+	}
+	const qPref = `
+		INSERT INTO notificacao_preferencias (usuario_id, canal, ativo, severidade_minima, tipos, modo, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		ON CONFLICT (usuario_id, canal) DO UPDATE SET
+			ativo = EXCLUDED.ativo,
+			severidade_minima = EXCLUDED.severidade_minima,
+			tipos = EXCLUDED.tipos,
+			modo = EXCLUDED.modo,
+			updated_at = EXCLUDED.updated_at
+	`
+	for _, p := range prefs {
+		if _, err := tx.Exec(ctx, qPref, cfg.UsuarioID, p.Canal, p.Ativo, p.SeveridadeMinima, p.Tipos, p.Modo); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// EnfileirarDigest acumula um alerta para o próximo resumo do canal.
+func (r *NotificacaoRepository) EnfileirarDigest(ctx context.Context, item *models.NotificacaoDigestItem) error {
+	const q = `
+		INSERT INTO notificacoes_digest_fila (usuario_id, canal, alerta_id, fazenda_id, titulo, corpo, url)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+	return r.db.QueryRow(ctx, q, item.UsuarioID, item.Canal, item.AlertaID, item.FazendaID, item.Titulo, item.Corpo, item.URL).
+		Scan(&item.ID, &item.CreatedAt)
+}
+
+// ListDigestPendentes devolve os itens por enviar criados antes de ate, por utilizador, canal e data.
+func (r *NotificacaoRepository) ListDigestPendentes(ctx context.Context, ate time.Time) ([]*models.NotificacaoDigestItem, error) {
+	const q = `
+		SELECT id, usuario_id, canal, alerta_id, fazenda_id, titulo, corpo, url, created_at
+		FROM notificacoes_digest_fila
+		WHERE enviado_em IS NULL AND created_at < $1
+		ORDER BY usuario_id, canal, created_at, id
+	`
+	rows, err := r.db.Query(ctx, q, ate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	list := []*models.NotificacaoDigestItem{}
+	for rows.Next() {
+		var it models.NotificacaoDigestItem
+		if err := rows.Scan(&it.ID, &it.UsuarioID, &it.Canal, &it.AlertaID, &it.FazendaID, &it.Titulo, &it.Corpo, &it.URL, &it.CreatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, &it)
+	}
+	return list, rows.Err()
+}
+
+// MarcarDigestEnviados marca os itens como enviados.
+func (r *NotificacaoRepository) MarcarDigestEnviados(ctx context.Context, ids []int64, em time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := r.db.Exec(ctx, `UPDATE notificacoes_digest_fila SET enviado_em = $2 WHERE id = ANY($1)`, ids, em)
+	return err
+}
+
+// PurgarDigestEnviados apaga itens enviados antes de antes (histórico curto da fila).
+func (r *NotificacaoRepository) PurgarDigestEnviados(ctx context.Context, antes time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM notificacoes_digest_fila WHERE enviado_em IS NOT NULL AND enviado_em < $1`, antes)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -10,7 +10,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// ErrPartoDeleteNoRows indica DELETE sem linhas afetadas (ex.: concorrência/race).
+// ErrPartoDeleteNoRows indica exclusão sem linhas afetadas (ex.: concorrência/race).
 var ErrPartoDeleteNoRows = errors.New("parto delete: nenhuma linha")
 
 type PartoRepository struct {
@@ -36,7 +36,7 @@ func (r *PartoRepository) CreateTx(ctx context.Context, tx pgx.Tx, p *models.Par
 }
 
 func (r *PartoRepository) GetByIDForUpdateTx(ctx context.Context, tx pgx.Tx, id int64) (*models.Parto, error) {
-	query := `SELECT id, animal_id, gestacao_id, data, tipo, numero_crias, complicacoes, observacoes, fazenda_id, created_at FROM partos WHERE id = $1 FOR UPDATE`
+	query := `SELECT id, animal_id, gestacao_id, data, tipo, numero_crias, complicacoes, observacoes, fazenda_id, created_at FROM partos WHERE id = $1 AND excluido_em IS NULL FOR UPDATE`
 	var p models.Parto
 	err := tx.QueryRow(ctx, query, id).Scan(&p.ID, &p.AnimalID, &p.GestacaoID, &p.Data, &p.Tipo, &p.NumeroCrias, &p.Complicacoes, &p.Observacoes, &p.FazendaID, &p.CreatedAt)
 	if err == pgx.ErrNoRows {
@@ -47,7 +47,7 @@ func (r *PartoRepository) GetByIDForUpdateTx(ctx context.Context, tx pgx.Tx, id
 
 func (r *PartoRepository) GetByAnimalIDTx(ctx context.Context, tx pgx.Tx, animalID int64) ([]*models.Parto, error) {
 	query := `SELECT id, animal_id, gestacao_id, data, tipo, numero_crias, complicacoes, observacoes, fazenda_id, created_at
-		FROM partos WHERE animal_id = $1 ORDER BY data DESC`
+		FROM partos WHERE animal_id = $1 AND excluido_em IS NULL ORDER BY data DESC`
 	rows, err := tx.Query(ctx, query, animalID)
 	if err != nil {
 		return nil, err
@@ -81,7 +81,7 @@ func (r *PartoRepository) UpdateGestacaoID(ctx context.Context, partoID, gestaca
 }
 
 func (r *PartoRepository) GetByID(ctx context.Context, id int64) (*models.Parto, error) {
-	query := `SELECT id, animal_id, gestacao_id, data, tipo, numero_crias, complicacoes, observacoes, fazenda_id, created_at FROM partos WHERE id = $1`
+	query := `SELECT id, animal_id, gestacao_id, data, tipo, numero_crias, complicacoes, observacoes, fazenda_id, created_at FROM partos WHERE id = $1 AND excluido_em IS NULL`
 	var p models.Parto
 	err := r.db.QueryRow(ctx, query, id).Scan(&p.ID, &p.AnimalID, &p.GestacaoID, &p.Data, &p.Tipo, &p.NumeroCrias, &p.Complicacoes, &p.Observacoes, &p.FazendaID, &p.CreatedAt)
 	if err == pgx.ErrNoRows {
@@ -92,7 +92,7 @@ func (r *PartoRepository) GetByID(ctx context.Context, id int64) (*models.Parto,
 
 func (r *PartoRepository) GetByAnimalID(ctx context.Context, animalID int64) ([]*models.Parto, error) {
 	query := `SELECT id, animal_id, gestacao_id, data, tipo, numero_crias, complicacoes, observacoes, fazenda_id, created_by, created_at
-		FROM partos WHERE animal_id = $1 ORDER BY data DESC`
+		FROM partos WHERE animal_id = $1 AND excluido_em IS NULL ORDER BY data DESC`
 	rows, err := r.db.Query(ctx, query, animalID)
 	if err != nil {
 		return nil, err
@@ -111,7 +111,7 @@ func (r *PartoRepository) GetByAnimalID(ctx context.Context, animalID int64) ([]
 
 func (r *PartoRepository) GetByFazendaID(ctx context.Context, fazendaID int64) ([]*models.Parto, error) {
 	query := `SELECT id, animal_id, gestacao_id, data, tipo, numero_crias, complicacoes, observacoes, fazenda_id, created_at
-		FROM partos WHERE fazenda_id = $1 ORDER BY data DESC`
+		FROM partos WHERE fazenda_id = $1 AND excluido_em IS NULL ORDER BY data DESC`
 	rows, err := r.db.Query(ctx, query, fazendaID)
 	if err != nil {
 		return nil, err
@@ -134,7 +134,7 @@ func (r *PartoRepository) Update(ctx context.Context, p *models.Parto) error {
 	}
 	query := `UPDATE partos
 		SET animal_id = $1, gestacao_id = $2, data = $3, tipo = $4, numero_crias = $5, complicacoes = $6, observacoes = $7, fazenda_id = $8
-		WHERE id = $9`
+		WHERE id = $9 AND excluido_em IS NULL`
 	cmd, err := r.db.Exec(ctx, query, p.AnimalID, p.GestacaoID, p.Data, p.Tipo, p.NumeroCrias, p.Complicacoes, p.Observacoes, p.FazendaID, p.ID)
 	if err != nil {
 		return err
@@ -145,11 +145,14 @@ func (r *PartoRepository) Update(ctx context.Context, p *models.Parto) error {
 	return nil
 }
 
-func (r *PartoRepository) Delete(ctx context.Context, id int64) error {
+// DeleteTx envia o parto para a lixeira (exclusão lógica). animaisGerados guarda o snapshot (JSON)
+// dos animais removidos junto com o parto, usado para recriá-los na restauração.
+func (r *PartoRepository) DeleteTx(ctx context.Context, tx pgx.Tx, id int64, usuarioID *int64, animaisGerados []byte) error {
 	if id <= 0 {
 		return fmt.Errorf("id invalido: %d", id)
 	}
-	cmd, err := r.db.Exec(ctx, `DELETE FROM partos WHERE id = $1`, id)
+	cmd, err := tx.Exec(ctx, `UPDATE partos SET excluido_em = CURRENT_TIMESTAMP, excluido_por = $2, excluido_animais_gerados = $3
+		WHERE id = $1 AND excluido_em IS NULL`, id, usuarioID, animaisGerados)
 	if err != nil {
 		return err
 	}
@@ -159,16 +162,49 @@ func (r *PartoRepository) Delete(ctx context.Context, id int64) error {
 	return nil
 }
 
-func (r *PartoRepository) DeleteTx(ctx context.Context, tx pgx.Tx, id int64) error {
-	if id <= 0 {
-		return fmt.Errorf("id invalido: %d", id)
+// GetExcluidoByIDForUpdateTx bloqueia e devolve o parto apenas se estiver na lixeira, com o snapshot dos animais gerados.
+func (r *PartoRepository) GetExcluidoByIDForUpdateTx(ctx context.Context, tx pgx.Tx, id int64) (*models.Parto, []byte, error) {
+	query := `SELECT id, animal_id, gestacao_id, data, tipo, numero_crias, complicacoes, observacoes, fazenda_id, created_by, created_at, excluido_animais_gerados
+		FROM partos WHERE id = $1 AND excluido_em IS NOT NULL FOR UPDATE`
+	var p models.Parto
+	var gerados []byte
+	err := tx.QueryRow(ctx, query, id).Scan(&p.ID, &p.AnimalID, &p.GestacaoID, &p.Data, &p.Tipo, &p.NumeroCrias, &p.Complicacoes, &p.Observacoes, &p.FazendaID, &p.CreatedBy, &p.CreatedAt, &gerados)
+	if err == pgx.ErrNoRows {
+		return nil, nil, pgx.ErrNoRows
 	}
-	cmd, err := tx.Exec(ctx, `DELETE FROM partos WHERE id = $1`, id)
+	return &p, gerados, err
+}
+
+// GetExcluidoByID devolve o parto apenas se estiver na lixeira.
+func (r *PartoRepository) GetExcluidoByID(ctx context.Context, id int64) (*models.Parto, error) {
+	query := `SELECT id, animal_id, gestacao_id, data, tipo, numero_crias, complicacoes, observacoes, fazenda_id, created_by, created_at
+		FROM partos WHERE id = $1 AND excluido_em IS NOT NULL`
+	var p models.Parto
+	err := r.db.QueryRow(ctx, query, id).Scan(&p.ID, &p.AnimalID, &p.GestacaoID, &p.Data, &p.Tipo, &p.NumeroCrias, &p.Complicacoes, &p.Observacoes, &p.FazendaID, &p.CreatedBy, &p.CreatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, pgx.ErrNoRows
+	}
+	return &p, err
+}
+
+// RestaurarTx retira o parto da lixeira e descarta o snapshot dos animais gerados.
+func (r *PartoRepository) RestaurarTx(ctx context.Context, tx pgx.Tx, id int64) error {
+	cmd, err := tx.Exec(ctx, `UPDATE partos SET excluido_em = NULL, excluido_por = NULL, excluido_animais_gerados = NULL
+		WHERE id = $1 AND excluido_em IS NOT NULL`, id)
 	if err != nil {
 		return err
 	}
 	if cmd.RowsAffected() == 0 {
-		return ErrPartoDeleteNoRows
+		return pgx.ErrNoRows
 	}
 	return nil
 }
+
+// IsMaisRecenteTx indica se não há parto (fora da lixeira) do animal posterior ao parto informado.
+func (r *PartoRepository) IsMaisRecenteTx(ctx context.Context, tx pgx.Tx, p *models.Parto) (bool, error) {
+	var posterior bool
+	err := tx.QueryRow(ctx, `SELECT EXISTS (
+		SELECT 1 FROM partos WHERE animal_id = $1 AND id <> $2 AND excluido_em IS NULL AND data > $3
+	)`, p.AnimalID, p.ID, p.Data).Scan(&posterior)
+	return !posterior, err
+}
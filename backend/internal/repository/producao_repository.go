@@ -46,7 +46,7 @@ func (r *ProducaoRepository) GetByID(ctx context.Context, id int64) (*models.Pro
 	query := `
 		SELECT ` + producaoSelectCols + `
 		FROM producao_leite
-		WHERE id = $1
+		WHERE id = $1 AND excluido_em IS NULL
 	`
 
 	var producao models.ProducaoLeite
@@ -71,6 +71,7 @@ func (r *ProducaoRepository) GetAll(ctx context.Context) ([]*models.ProducaoLeit
 	query := `
 		SELECT ` + producaoSelectCols + `
 		FROM producao_leite
+		WHERE excluido_em IS NULL
 		ORDER BY data_hora DESC
 	`
 
@@ -85,7 +86,7 @@ func (r *ProducaoRepository) GetByFazendaIDs(ctx context.Context, fazendaIDs []i
 		SELECT p.id, p.animal_id, p.lactacao_id, p.quantidade, p.data_hora, p.qualidade, p.created_at
 		FROM producao_leite p
 		INNER JOIN animais a ON a.id = p.animal_id
-		WHERE a.fazenda_id = ANY($1::bigint[])
+		WHERE a.fazenda_id = ANY($1::bigint[]) AND p.excluido_em IS NULL
 	`
 	args := []interface{}{fazendaIDs}
 	if lactacaoID != nil {
@@ -100,7 +101,7 @@ func (r *ProducaoRepository) GetByAnimalID(ctx context.Context, animalID int64)
 	query := `
 		SELECT id, animal_id, lactacao_id, quantidade, data_hora, qualidade, created_by, created_at
 		FROM producao_leite
-		WHERE animal_id = $1
+		WHERE animal_id = $1 AND excluido_em IS NULL
 		ORDER BY data_hora DESC
 	`
 	rows, err := r.db.Query(ctx, query, animalID)
@@ -124,7 +125,7 @@ func (r *ProducaoRepository) GetByDateRange(ctx context.Context, startDate, endD
 	query := `
 		SELECT ` + producaoSelectCols + `
 		FROM producao_leite
-		WHERE data_hora BETWEEN $1 AND $2
+		WHERE data_hora BETWEEN $1 AND $2 AND excluido_em IS NULL
 		ORDER BY data_hora DESC
 	`
 
@@ -139,7 +140,7 @@ func (r *ProducaoRepository) GetByFazendaIDsAndDateRange(ctx context.Context, fa
 		SELECT p.id, p.animal_id, p.lactacao_id, p.quantidade, p.data_hora, p.qualidade, p.created_at
 		FROM producao_leite p
 		INNER JOIN animais a ON a.id = p.animal_id
-		WHERE a.fazenda_id = ANY($1::bigint[]) AND p.data_hora BETWEEN $2 AND $3
+		WHERE a.fazenda_id = ANY($1::bigint[]) AND p.data_hora BETWEEN $2 AND $3 AND p.excluido_em IS NULL
 	`
 	args := []interface{}{fazendaIDs, startDate, endDate}
 	if lactacaoID != nil {
@@ -154,7 +155,7 @@ func (r *ProducaoRepository) GetByAnimalAndDateRange(ctx context.Context, animal
 	query := `
 		SELECT ` + producaoSelectCols + `
 		FROM producao_leite
-		WHERE animal_id = $1 AND data_hora BETWEEN $2 AND $3
+		WHERE animal_id = $1 AND data_hora BETWEEN $2 AND $3 AND excluido_em IS NULL
 		ORDER BY data_hora DESC
 	`
 
@@ -168,7 +169,7 @@ func (r *ProducaoRepository) Update(ctx context.Context, producao *models.Produc
 	query := `
 		UPDATE producao_leite
 		SET animal_id = $1, lactacao_id = $2, quantidade = $3, data_hora = $4, qualidade = $5
-		WHERE id = $6
+		WHERE id = $6 AND excluido_em IS NULL
 	`
 
 	cmd, err := r.db.Exec(
@@ -190,15 +191,50 @@ func (r *ProducaoRepository) Update(ctx context.Context, producao *models.Produc
 	return nil
 }
 
-func (r *ProducaoRepository) Delete(ctx context.Context, id int64) error {
-	query := `DELETE FROM producao_leite WHERE id = $1`
-	_, err := r.db.Exec(ctx, query, id)
-	return err
+// Delete envia o registo para a lixeira (exclusão lógica); usuarioID nil = sistema.
+func (r *ProducaoRepository) Delete(ctx context.Context, id int64, usuarioID *int64) error {
+	query := `UPDATE producao_leite SET excluido_em = CURRENT_TIMESTAMP, excluido_por = $2 WHERE id = $1 AND excluido_em IS NULL`
+	cmd, err := r.db.Exec(ctx, query, id, usuarioID)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// GetExcluidoByID devolve o registo apenas se estiver na lixeira.
+func (r *ProducaoRepository) GetExcluidoByID(ctx context.Context, id int64) (*models.ProducaoLeite, error) {
+	query := `
+		SELECT ` + producaoSelectCols + `
+		FROM producao_leite
+		WHERE id = $1 AND excluido_em IS NOT NULL
+	`
+	var p models.ProducaoLeite
+	err := r.db.QueryRow(ctx, query, id).Scan(&p.ID, &p.AnimalID, &p.LactacaoID, &p.Quantidade, &p.DataHora, &p.Qualidade, &p.CreatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, pgx.ErrNoRows
+	}
+	return &p, err
+}
+
+// Restaurar retira o registo da lixeira, regravando a lactação resolvida na restauração.
+func (r *ProducaoRepository) Restaurar(ctx context.Context, id int64, lactacaoID *int64) error {
+	query := `UPDATE producao_leite SET excluido_em = NULL, excluido_por = NULL, lactacao_id = $2 WHERE id = $1 AND excluido_em IS NOT NULL`
+	cmd, err := r.db.Exec(ctx, query, id, lactacaoID)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
 }
 
 func (r *ProducaoRepository) Count(ctx context.Context) (int64, error) {
 	var n int64
-	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM producao_leite`).Scan(&n)
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM producao_leite WHERE excluido_em IS NULL`).Scan(&n)
 	return n, err
 }
 
@@ -211,14 +247,14 @@ func (r *ProducaoRepository) CountByFazendaIDs(ctx context.Context, fazendaIDs [
 		SELECT COUNT(*)
 		FROM producao_leite p
 		INNER JOIN animais a ON a.id = p.animal_id
-		WHERE a.fazenda_id = ANY($1::bigint[])
+		WHERE a.fazenda_id = ANY($1::bigint[]) AND p.excluido_em IS NULL
 	`, fazendaIDs).Scan(&n)
 	return n, err
 }
 
 func (r *ProducaoRepository) CountByAnimal(ctx context.Context, animalID int64) (int64, error) {
 	var n int64
-	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM producao_leite WHERE animal_id = $1`, animalID).Scan(&n)
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM producao_leite WHERE animal_id = $1 AND excluido_em IS NULL`, animalID).Scan(&n)
 	return n, err
 }
 
@@ -227,7 +263,7 @@ func (r *ProducaoRepository) SumLitrosByFazendaBetween(ctx context.Context, faze
 		SELECT COALESCE(SUM(p.quantidade), 0)
 		FROM producao_leite p
 		INNER JOIN animais a ON a.id = p.animal_id
-		WHERE a.fazenda_id = $1 AND p.data_hora >= $2 AND p.data_hora < $3
+		WHERE a.fazenda_id = $1 AND p.data_hora >= $2 AND p.data_hora < $3 AND p.excluido_em IS NULL
 	`
 	var total float64
 	err := r.db.QueryRow(ctx, q, fazendaID, start, end).Scan(&total)
@@ -238,7 +274,7 @@ func (r *ProducaoRepository) GetResumoByAnimal(ctx context.Context, animalID int
 	query := `
 		SELECT COALESCE(SUM(quantidade), 0), COALESCE(AVG(quantidade), 0), COUNT(*)
 		FROM producao_leite
-		WHERE animal_id = $1
+		WHERE animal_id = $1 AND excluido_em IS NULL
 	`
 
 	var resumo models.ProducaoResumo
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RebanhoSnapshotRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewRebanhoSnapshotRepository(db *pgxpool.Pool) *RebanhoSnapshotRepository {
+	return &RebanhoSnapshotRepository{db: db}
+}
+
+// RebanhoSnapshotLinha são os factos de um animal no fim de uma data, lidos dos eventos
+// (movimentações de lote, ciclo, lactações, saúde, restrições de leite). A derivação de
+// categoria, status reprodutivo e status de saúde fica no service.
+type RebanhoSnapshotLinha struct {
+	AnimalID          int64
+	Identificacao     string
+	Sexo              *string
+	DataNascimento    *time.Time
+	CategoriaAtual    *string
+	LoteID            *int64
+	LoteNome          *string
+	Marco             *MarcoReprodutivo // mais recente até a data; GestacaoAtiva refere-se à data
+	GestacaoAtiva     bool
+	DataPrevistaParto *time.Time
+	PrimeiroParto     *time.Time
+	EmLactacao        bool
+	EmRestricaoLeite  bool
+	CasosSaudeTipos   []string // tipos dos casos de saúde em aberto na data
+}
+
+// ListByFazendaNaData reconstrói o rebanho da fazenda no fim de data (data civil):
+//   - presença: entrada (data_entrada, nascimento ou cadastro) até a data e sem baixa efetiva até a data;
+//   - lote: destino da última movimentação até a data; sem movimentação até a data, a origem da primeira
+//     movimentação posterior; sem movimentações, o lote atual;
+//   - gestação ativa: confirmada até a data e não encerrada por parto até a data; PERDA/ABORTO usam
+//     updated_at como data de encerramento (a tabela não guarda outra).
+//
+// Animais transferidos entre fazendas aparecem na fazenda atual (não há histórico de fazenda).
+func (r *RebanhoSnapshotRepository) ListByFazendaNaData(ctx context.Context, fazendaID int64, data time.Time) ([]*RebanhoSnapshotLinha, error) {
+	query := `
+		SELECT a.id, a.identificacao, a.sexo, a.data_nascimento, a.categoria,
+			lt.lote_id, l.nome,
+			mc.tipo, mc.data, mc.resultado,
+			g.data_prevista_parto, g.id IS NOT NULL,
+			(SELECT MIN(p.data) FROM partos p WHERE p.animal_id = a.id AND p.excluido_em IS NULL),
+			EXISTS (
+				SELECT 1 FROM lactacoes lc
+				WHERE lc.animal_id = a.id
+				AND lc.data_inicio <= $2::date
+				AND (lc.data_fim IS NULL OR lc.data_fim >= $2::date)
+			),
+			EXISTS (
+				SELECT 1 FROM restricoes_leite rl
+				WHERE rl.animal_id = a.id
+				AND rl.status <> $9
+				AND rl.inicio_em <= $2::date
+				AND ((rl.liberado_em IS NULL AND rl.status = $10) OR rl.liberado_em > $2::date)
+			),
+			COALESCE((
+				SELECT array_agg(s.tipo_caso ORDER BY s.data_inicio)
+				FROM animal_saude s
+				WHERE s.animal_id = a.id
+				AND s.status <> $11
+				AND s.data_inicio <= $2::date
+				AND ((s.data_fim IS NULL AND s.status = $12) OR s.data_fim >= $2::date)
+			), '{}')
+		FROM animais a
+		LEFT JOIN LATERAL (
+			SELECT CASE
+				WHEN EXISTS (SELECT 1 FROM movimentacoes_lote m WHERE m.animal_id = a.id AND m.data::date <= $2::date) THEN (
+					SELECT m.lote_destino_id FROM movimentacoes_lote m
+					WHERE m.animal_id = a.id AND m.data::date <= $2::date
+					ORDER BY m.data DESC, m.id DESC LIMIT 1
+				)
+				WHEN EXISTS (SELECT 1 FROM movimentacoes_lote m WHERE m.animal_id = a.id) THEN (
+					SELECT m.lote_origem_id FROM movimentacoes_lote m
+					WHERE m.animal_id = a.id
+					ORDER BY m.data ASC, m.id ASC LIMIT 1
+				)
+				ELSE a.lote_id
+			END AS lote_id
+		) lt ON TRUE
+		LEFT JOIN lotes l ON l.id = lt.lote_id
+		LEFT JOIN LATERAL (
+			SELECT m.tipo, m.data, m.resultado
+			FROM (` + sqlMarcosReprodutivos("a.id", "$3", "$4") + `
+			) m
+			WHERE m.data::date <= $2::date
+			ORDER BY m.data DESC, m.ordem DESC
+			LIMIT 1
+		) mc ON TRUE
+		LEFT JOIN LATERAL (
+			SELECT gs.id, gs.data_prevista_parto
+			FROM gestacoes gs
+			WHERE gs.animal_id = a.id
+			AND gs.data_confirmacao <= $2::date
+			AND (
+				gs.status = $5
+				OR (gs.status = $6 AND NOT EXISTS (
+					SELECT 1 FROM partos p
+					WHERE p.animal_id = a.id AND p.excluido_em IS NULL
+					AND p.data::date >= gs.data_confirmacao AND p.data::date <= $2::date
+				))
+				OR (gs.status IN ($7, $8) AND gs.updated_at::date > $2::date)
+			)
+			ORDER BY gs.data_confirmacao DESC, gs.id DESC
+			LIMIT 1
+		) g ON TRUE
+		WHERE a.fazenda_id = $1
+		AND COALESCE(a.data_entrada, a.data_nascimento, a.created_at::date) <= $2::date
+		AND (a.data_saida IS NULL OR a.data_saida > $2::date)
+		ORDER BY a.identificacao ASC
+	`
+	rows, err := r.db.Query(ctx, query, fazendaID, data,
+		models.DiagnosticoResultadoPositivo, models.DiagnosticoResultadoNegativo,
+		models.GestacaoStatusConfirmada, models.GestacaoStatusPartoRealizado,
+		models.GestacaoStatusPerda, models.GestacaoStatusAborto,
+		models.RestricaoLeiteStatusCancelado, models.RestricaoLeiteStatusAguardandoLab,
+		models.AnimalSaudeStatusCancelado, models.AnimalSaudeStatusAtivo,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	list := []*RebanhoSnapshotLinha{}
+	for rows.Next() {
+		var (
+			ln         RebanhoSnapshotLinha
+			marcoTipo  *string
+			marcoData  *time.Time
+			marcoResul *string
+		)
+		if err := rows.Scan(&ln.AnimalID, &ln.Identificacao, &ln.Sexo, &ln.DataNascimento, &ln.CategoriaAtual,
+			&ln.LoteID, &ln.LoteNome,
+			&marcoTipo, &marcoData, &marcoResul,
+			&ln.DataPrevistaParto, &ln.GestacaoAtiva, &ln.PrimeiroParto,
+			&ln.EmLactacao, &ln.EmRestricaoLeite, &ln.CasosSaudeTipos); err != nil {
+			return nil, err
+		}
+		if marcoTipo != nil && marcoData != nil {
+			ln.Marco = &MarcoReprodutivo{Tipo: *marcoTipo, Data: *marcoData, GestacaoAtiva: ln.GestacaoAtiva}
+			if marcoResul != nil {
+				ln.Marco.Resultado = *marcoResul
+			}
+		}
+		list = append(list, &ln)
+	}
+	return list, rows.Err()
+}
@@ -18,21 +18,33 @@ func NewRefreshTokenRepository(db *pgxpool.Pool) *RefreshTokenRepository {
 }
 
 // Create persiste o refresh token. A coluna `token` armazena o SHA-256 (hex) do valor
-// em claro — o hashing é responsabilidade do RefreshTokenService.
+// em claro — o hashing é responsabilidade do RefreshTokenService. FamiliaID zero abre uma sessão nova
+// (familia_id e sessao_iniciada_em do banco); na rotação o chamador repassa os da sessão.
 func (r *RefreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
 	query := `
-		INSERT INTO refresh_tokens (token, user_id, expires_at)
-		VALUES ($1, $2, $3)
-		RETURNING id, created_at
+		INSERT INTO refresh_tokens (token, user_id, expires_at, aal, familia_id, sessao_iniciada_em, user_agent, ip)
+		VALUES ($1, $2, $3, $4, COALESCE($5, nextval('refresh_tokens_familia_seq')), COALESCE($6, CURRENT_TIMESTAMP), $7, $8)
+		RETURNING id, created_at, familia_id, sessao_iniciada_em
 	`
 
+	var familiaID *int64
+	var iniciadaEm *time.Time
+	if token.FamiliaID != 0 {
+		familiaID = &token.FamiliaID
+		iniciadaEm = &token.SessaoIniciadaEm
+	}
 	err := r.db.QueryRow(
 		ctx,
 		query,
 		token.Token,
 		token.UserID,
 		token.ExpiresAt,
-	).Scan(&token.ID, &token.CreatedAt)
+		token.AAL,
+		familiaID,
+		iniciadaEm,
+		token.UserAgent,
+		token.IP,
+	).Scan(&token.ID, &token.CreatedAt, &token.FamiliaID, &token.SessaoIniciadaEm)
 
 	return err
 }
@@ -40,7 +52,8 @@ func (r *RefreshTokenRepository) Create(ctx context.Context, token *models.Refre
 // GetByToken busca pelo hash do token (SHA-256 hex), não pelo valor em claro.
 func (r *RefreshTokenRepository) GetByToken(ctx context.Context, token string) (*models.RefreshToken, error) {
 	query := `
-		SELECT id, token, user_id, expires_at, created_at, revoked
+		SELECT id, token, user_id, expires_at, created_at, revoked, aal,
+		       familia_id, sessao_iniciada_em, user_agent, ip
 		FROM refresh_tokens
 		WHERE token = $1 AND revoked = FALSE
 	`
@@ -53,6 +66,11 @@ func (r *RefreshTokenRepository) GetByToken(ctx context.Context, token string) (
 		&rt.ExpiresAt,
 		&rt.CreatedAt,
 		&rt.Revoked,
+		&rt.AAL,
+		&rt.FamiliaID,
+		&rt.SessaoIniciadaEm,
+		&rt.UserAgent,
+		&rt.IP,
 	)
 
 	if err == pgx.ErrNoRows {
@@ -84,6 +102,60 @@ func (r *RefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID in
 	return err
 }
 
+// ListSessoesAtivas uma linha por família com token vivo em em (o mais recente), do uso mais recente
+// para o mais antigo. ID da sessão = familia_id.
+func (r *RefreshTokenRepository) ListSessoesAtivas(ctx context.Context, userID int64, em time.Time) ([]models.Sessao, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT familia_id, sessao_iniciada_em, created_at, expires_at, user_agent, ip, aal FROM (
+			SELECT DISTINCT ON (familia_id) familia_id, sessao_iniciada_em, created_at, expires_at, user_agent, ip, aal
+			FROM refresh_tokens
+			WHERE user_id = $1 AND revoked = FALSE AND expires_at > $2
+			ORDER BY familia_id, created_at DESC
+		) s
+		ORDER BY created_at DESC, familia_id DESC
+	`, userID, em)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []models.Sessao{}
+	for rows.Next() {
+		var s models.Sessao
+		if err := rows.Scan(&s.ID, &s.IniciadaEm, &s.UltimoUsoEm, &s.ExpiraEm, &s.UserAgent, &s.IP, &s.AAL); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+// RevokeFamilia encerra a sessão familiaID do usuário; false se não havia token vivo nela.
+func (r *RefreshTokenRepository) RevokeFamilia(ctx context.Context, userID, familiaID int64) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE refresh_tokens SET revoked = TRUE
+		WHERE user_id = $1 AND familia_id = $2 AND revoked = FALSE
+	`, userID, familiaID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// RevokeAllForUserExceto encerra as sessões do usuário menos a família excetoFamiliaID (0 = todas);
+// devolve quantas sessões foram encerradas.
+func (r *RefreshTokenRepository) RevokeAllForUserExceto(ctx context.Context, userID, excetoFamiliaID int64) (int64, error) {
+	var n int64
+	err := r.db.QueryRow(ctx, `
+		WITH revogados AS (
+			UPDATE refresh_tokens SET revoked = TRUE
+			WHERE user_id = $1 AND familia_id <> $2 AND revoked = FALSE
+			RETURNING familia_id
+		)
+		SELECT COUNT(DISTINCT familia_id) FROM revogados
+	`, userID, excetoFamiliaID).Scan(&n)
+	return n, err
+}
+
 func (r *RefreshTokenRepository) DeleteExpired(ctx context.Context) error {
 	query := `
 		DELETE FROM refresh_tokens
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ResumoAlertasRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewResumoAlertasRepository(db *pgxpool.Pool) *ResumoAlertasRepository {
+	return &ResumoAlertasRepository{db: db}
+}
+
+const resumoAlertasSelect = `
+	SELECT r.usuario_id, r.ativo, r.frequencia, r.hora, r.dia_semana, r.canais, r.ultimo_envio_em, r.updated_at
+	FROM resumo_alertas_usuario r
+`
+
+func scanResumoAlertasConfig(row pgx.Row) (*models.ResumoAlertasConfig, error) {
+	var c models.ResumoAlertasConfig
+	var hora int16
+	var dia *int16
+	var updatedAt time.Time
+	if err := row.Scan(&c.UsuarioID, &c.Ativo, &c.Frequencia, &hora, &dia, &c.Canais, &c.UltimoEnvioEm, &updatedAt); err != nil {
+		return nil, err
+	}
+	c.Hora = int(hora)
+	if dia != nil {
+		d := int(*dia)
+		c.DiaSemana = &d
+	}
+	c.UpdatedAt = &updatedAt
+	return &c, nil
+}
+
+// GetConfig devolve o agendamento do utilizador (nil sem linha = resumo desligado).
+func (r *ResumoAlertasRepository) GetConfig(ctx context.Context, usuarioID int64) (*models.ResumoAlertasConfig, error) {
+	c, err := scanResumoAlertasConfig(r.db.QueryRow(ctx, resumoAlertasSelect+` WHERE r.usuario_id = $1`, usuarioID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	return c, err
+}
+
+// UpsertConfig grava o agendamento; ultimo_envio_em é preservado.
+func (r *ResumoAlertasRepository) UpsertConfig(ctx context.Context, c *models.ResumoAlertasConfig) error {
+	const q = `
+		INSERT INTO resumo_alertas_usuario (usuario_id, ativo, frequencia, hora, dia_semana, canais, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		ON CONFLICT (usuario_id) DO UPDATE SET
+			ativo = EXCLUDED.ativo,
+			frequencia = EXCLUDED.frequencia,
+			hora = EXCLUDED.hora,
+			dia_semana = EXCLUDED.dia_semana,
+			canais = EXCLUDED.canais,
+			updated_at = EXCLUDED.updated_at
+		RETURNING ultimo_envio_em, updated_at
+	`
+	var updatedAt time.Time
+	err := r.db.QueryRow(ctx, q, c.UsuarioID, c.Ativo, c.Frequencia, c.Hora, c.DiaSemana, c.Canais).
+		Scan(&c.UltimoEnvioEm, &updatedAt)
+	if err != nil {
+		return err
+	}
+	c.UpdatedAt = &updatedAt
+	return nil
+}
+
+// ListDevidos devolve os resumos ativos agendados para a hora e dia da semana indicados, ainda não
+// enviados desde inicioDia (evita reenvio se o processo reiniciar na mesma hora). Só utilizadores
+// ativos com perfil operacional.
+func (r *ResumoAlertasRepository) ListDevidos(ctx context.Context, hora, diaSemana int, inicioDia time.Time) ([]*models.ResumoAlertasConfig, error) {
+	q := resumoAlertasSelect + `
+		INNER JOIN usuarios u ON u.id = r.usuario_id
+		WHERE r.ativo
+		  AND r.hora = $1
+		  AND (r.frequencia = 'DIARIO' OR r.dia_semana = $2)
+		  AND (r.ultimo_envio_em IS NULL OR r.ultimo_envio_em < $3)
+		  AND u.enabled = true
+		  AND u.perfil NOT IN ('USER', 'INTEGRACAO')
+		ORDER BY r.usuario_id
+	`
+	rows, err := r.db.Query(ctx, q, hora, diaSemana, inicioDia)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	list := []*models.ResumoAlertasConfig{}
+	for rows.Next() {
+		c, err := scanResumoAlertasConfig(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, c)
+	}
+	return list, rows.Err()
+}
+
+func (r *ResumoAlertasRepository) MarcarEnviado(ctx context.Context, usuarioID int64, em time.Time) error {
+	_, err := r.db.Exec(ctx, `UPDATE resumo_alertas_usuario SET ultimo_envio_em = $2 WHERE usuario_id = $1`, usuarioID, em)
+	return err
+}
@@ -0,0 +1,413 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TarefaListFilters struct {
+	Status string
+	// Abertas: ABERTA/EM_ANDAMENTO (ignorado quando Status é informado).
+	Abertas        bool
+	ResponsavelID  *int64
+	SemResponsavel bool
+	// Inicio/Fim recortam data_prevista (inclusivos); só Fim lista tudo até a data.
+	Inicio *time.Time
+	Fim    *time.Time
+	Limit  int
+	Offset int
+}
+
+type TarefaRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTarefaRepository(db *pgxpool.Pool) *TarefaRepository {
+	return &TarefaRepository{db: db}
+}
+
+// responsavel_de_folga: folga na escala ou ausência cobrindo a data prevista (BR-TAREFA-004).
+const tarefaSelectWithNames = `
+	SELECT
+		t.id, t.fazenda_id, t.titulo, t.descricao, t.animal_id, t.lote_id, t.area_id, t.alerta_id,
+		t.responsavel_id, t.data_prevista, t.recorrencia, t.serie_id, t.status, t.concluida_por,
+		t.concluida_em, t.created_by, t.created_at, t.updated_at,
+		an.identificacao AS animal_identificacao,
+		l.nome AS lote_nome,
+		ar.nome AS area_nome,
+		ur.nome AS responsavel_nome,
+		(t.status IN ('ABERTA', 'EM_ANDAMENTO') AND t.data_prevista < CURRENT_DATE) AS atrasada,
+		(t.responsavel_id IS NOT NULL AND (
+			EXISTS (SELECT 1 FROM escala_folgas ef
+				WHERE ef.fazenda_id = t.fazenda_id AND ef.usuario_id = t.responsavel_id AND ef.data = t.data_prevista)
+			OR EXISTS (SELECT 1 FROM folgas_ausencias fa
+				WHERE fa.fazenda_id = t.fazenda_id AND fa.usuario_id = t.responsavel_id
+				  AND t.data_prevista BETWEEN fa.data_inicio AND fa.data_fim)
+		)) AS responsavel_de_folga
+	FROM tarefas t
+	LEFT JOIN animais an ON an.id = t.animal_id
+	LEFT JOIN lotes l ON l.id = t.lote_id
+	LEFT JOIN areas ar ON ar.id = t.area_id
+	LEFT JOIN usuarios ur ON ur.id = t.responsavel_id
+`
+
+const tarefaOrderBy = `
+	ORDER BY
+		CASE t.status WHEN 'EM_ANDAMENTO' THEN 0 WHEN 'ABERTA' THEN 1 ELSE 2 END,
+		t.data_prevista, t.id
+`
+
+func scanTarefaWithNames(row pgx.Row) (*models.TarefaWithNames, error) {
+	var m models.TarefaWithNames
+	err := row.Scan(
+		&m.ID, &m.FazendaID, &m.Titulo, &m.Descricao, &m.AnimalID, &m.LoteID, &m.AreaID, &m.AlertaID,
+		&m.ResponsavelID, &m.DataPrevista, &m.Recorrencia, &m.SerieID, &m.Status, &m.ConcluidaPor,
+		&m.ConcluidaEm, &m.CreatedBy, &m.CreatedAt, &m.UpdatedAt,
+		&m.AnimalIdentificacao, &m.LoteNome, &m.AreaNome, &m.ResponsavelNome,
+		&m.Atrasada, &m.ResponsavelDeFolga,
+	)
+	if err != nil {
+		return nil, err
+	}
+	m.Checklist = []models.TarefaChecklistItem{}
+	return &m, nil
+}
+
+func buildTarefaListWhere(fazendaID int64, f TarefaListFilters) (string, []interface{}) {
+	conds := []string{"t.fazenda_id = $1"}
+	args := []interface{}{fazendaID}
+	idx := 2
+
+	if f.Status != "" {
+		conds = append(conds, fmt.Sprintf("t.status = $%d", idx))
+		args = append(args, f.Status)
+		idx++
+	} else if f.Abertas {
+		conds = append(conds, "t.status IN ('ABERTA', 'EM_ANDAMENTO')")
+	}
+	if f.ResponsavelID != nil {
+		conds = append(conds, fmt.Sprintf("t.responsavel_id = $%d", idx))
+		args = append(args, *f.ResponsavelID)
+		idx++
+	} else if f.SemResponsavel {
+		conds = append(conds, "t.responsavel_id IS NULL")
+	}
+	if f.Inicio != nil {
+		conds = append(conds, fmt.Sprintf("t.data_prevista >= $%d::date", idx))
+		args = append(args, *f.Inicio)
+		idx++
+	}
+	if f.Fim != nil {
+		conds = append(conds, fmt.Sprintf("t.data_prevista <= $%d::date", idx))
+		args = append(args, *f.Fim)
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+func (r *TarefaRepository) ListByFazenda(ctx context.Context, fazendaID int64, f TarefaListFilters) ([]models.TarefaWithNames, int64, error) {
+	where, args := buildTarefaListWhere(fazendaID, f)
+
+	var total int64
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM tarefas t WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	limit := f.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	offset := f.Offset
+	if offset < 0 {
+		offset = 0
+	}
+	listArgs := append(append([]interface{}{}, args...), limit, offset)
+	listQ := fmt.Sprintf(`%s WHERE %s %s LIMIT $%d OFFSET $%d`,
+		tarefaSelectWithNames, where, tarefaOrderBy, len(args)+1, len(args)+2)
+
+	rows, err := r.db.Query(ctx, listQ, listArgs...)
+	if err != nil {
+		return nil, 0, err
+	}
+	out := []models.TarefaWithNames{}
+	for rows.Next() {
+		m, err := scanTarefaWithNames(rows)
+		if err != nil {
+			rows.Close()
+			return nil, 0, err
+		}
+		out = append(out, *m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	if err := r.carregarChecklists(ctx, out); err != nil {
+		return nil, 0, err
+	}
+	return out, total, nil
+}
+
+// GetByID pgx.ErrNoRows quando a tarefa não existe na fazenda.
+func (r *TarefaRepository) GetByID(ctx context.Context, fazendaID, id int64) (*models.TarefaWithNames, error) {
+	m, err := scanTarefaWithNames(r.db.QueryRow(ctx, tarefaSelectWithNames+` WHERE t.id = $1 AND t.fazenda_id = $2`, id, fazendaID))
+	if err != nil {
+		return nil, err
+	}
+	list := []models.TarefaWithNames{*m}
+	if err := r.carregarChecklists(ctx, list); err != nil {
+		return nil, err
+	}
+	return &list[0], nil
+}
+
+func (r *TarefaRepository) carregarChecklists(ctx context.Context, tarefas []models.TarefaWithNames) error {
+	if len(tarefas) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(tarefas))
+	pos := make(map[int64]int, len(tarefas))
+	for i, t := range tarefas {
+		ids[i] = t.ID
+		pos[t.ID] = i
+	}
+	rows, err := r.db.Query(ctx, `
+		SELECT id, tarefa_id, ordem, texto, feito, feito_por, feito_em
+		FROM tarefas_checklist_itens
+		WHERE tarefa_id = ANY($1)
+		ORDER BY tarefa_id, ordem, id
+	`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var it models.TarefaChecklistItem
+		if err := rows.Scan(&it.ID, &it.TarefaID, &it.Ordem, &it.Texto, &it.Feito, &it.FeitoPor, &it.FeitoEm); err != nil {
+			return err
+		}
+		i := pos[it.TarefaID]
+		tarefas[i].Checklist = append(tarefas[i].Checklist, it)
+	}
+	return rows.Err()
+}
+
+func insertTarefaTx(ctx context.Context, tx pgx.Tx, t *models.Tarefa, checklist []string) error {
+	const q = `
+		INSERT INTO tarefas (
+			fazenda_id, titulo, descricao, animal_id, lote_id, area_id, alerta_id, responsavel_id,
+			data_prevista, recorrencia, serie_id, status, created_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9::date, $10, $11, $12, $13)
+		RETURNING id, created_at, updated_at
+	`
+	if err := tx.QueryRow(ctx, q,
+		t.FazendaID, t.Titulo, t.Descricao, t.AnimalID, t.LoteID, t.AreaID, t.AlertaID, t.ResponsavelID,
+		t.DataPrevista, t.Recorrencia, t.SerieID, t.Status, t.CreatedBy,
+	).Scan(&t.ID, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return err
+	}
+	return insertChecklistTx(ctx, tx, t.ID, checklist)
+}
+
+func insertChecklistTx(ctx context.Context, tx pgx.Tx, tarefaID int64, checklist []string) error {
+	for i, texto := range checklist {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO tarefas_checklist_itens (tarefa_id, ordem, texto) VALUES ($1, $2, $3)`,
+			tarefaID, i+1, texto,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Create insere a tarefa com os itens de checklist na ordem recebida.
+func (r *TarefaRepository) Create(ctx context.Context, t *models.Tarefa, checklist []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	if err := insertTarefaTx(ctx, tx, t, checklist); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// Update grava os campos editáveis; checklist nil mantém os itens, não-nil substitui todos
+// (itens com o mesmo texto conservam a marcação de feito).
+func (r *TarefaRepository) Update(ctx context.Context, t *models.Tarefa, checklist []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE tarefas
+		SET titulo = $3, descricao = $4, animal_id = $5, lote_id = $6, area_id = $7,
+		    responsavel_id = $8, data_prevista = $9::date, recorrencia = $10, updated_at = NOW()
+		WHERE id = $1 AND fazenda_id = $2
+	`, t.ID, t.FazendaID, t.Titulo, t.Descricao, t.AnimalID, t.LoteID, t.AreaID,
+		t.ResponsavelID, t.DataPrevista, t.Recorrencia)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	if checklist != nil {
+		type marca struct {
+			por *int64
+			em  *time.Time
+		}
+		feitos := map[string]marca{}
+		rows, err := tx.Query(ctx,
+			`SELECT texto, feito_por, feito_em FROM tarefas_checklist_itens WHERE tarefa_id = $1 AND feito`, t.ID)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var texto string
+			var m marca
+			if err := rows.Scan(&texto, &m.por, &m.em); err != nil {
+				rows.Close()
+				return err
+			}
+			feitos[texto] = m
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM tarefas_checklist_itens WHERE tarefa_id = $1`, t.ID); err != nil {
+			return err
+		}
+		for i, texto := range checklist {
+			m, feito := feitos[texto]
+			if _, err := tx.Exec(ctx, `
+				INSERT INTO tarefas_checklist_itens (tarefa_id, ordem, texto, feito, feito_por, feito_em)
+				VALUES ($1, $2, $3, $4, $5, $6)
+			`, t.ID, i+1, texto, feito, m.por, m.em); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// UpdateStatus muda o status; assumir grava o ator como responsável quando a tarefa não tem um.
+func (r *TarefaRepository) UpdateStatus(ctx context.Context, fazendaID, id int64, status string, actorID int64, assumir bool) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE tarefas
+		SET status = $3,
+		    responsavel_id = CASE WHEN $5 AND responsavel_id IS NULL THEN $4 ELSE responsavel_id END,
+		    concluida_por = CASE WHEN $3 IN ('CONCLUIDA', 'CANCELADA') THEN $4 ELSE NULL END,
+		    concluida_em = CASE WHEN $3 IN ('CONCLUIDA', 'CANCELADA') THEN NOW() ELSE NULL END,
+		    updated_at = NOW()
+		WHERE id = $1 AND fazenda_id = $2
+	`, id, fazendaID, status, actorID, assumir)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// Concluir fecha a tarefa e, numa transação, cria a próxima ocorrência da série (se houver) com a
+// mesma checklist desmarcada (BR-TAREFA-001).
+func (r *TarefaRepository) Concluir(ctx context.Context, fazendaID, id, actorID int64, proxima *models.Tarefa) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE tarefas
+		SET status = 'CONCLUIDA',
+		    responsavel_id = COALESCE(responsavel_id, $3),
+		    concluida_por = $3, concluida_em = NOW(), updated_at = NOW()
+		WHERE id = $1 AND fazenda_id = $2 AND status IN ('ABERTA', 'EM_ANDAMENTO')
+	`, id, fazendaID, actorID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	if proxima != nil {
+		var textos []string
+		rows, err := tx.Query(ctx, `SELECT texto FROM tarefas_checklist_itens WHERE tarefa_id = $1 ORDER BY ordem, id`, id)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var texto string
+			if err := rows.Scan(&texto); err != nil {
+				rows.Close()
+				return err
+			}
+			textos = append(textos, texto)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if err := insertTarefaTx(ctx, tx, proxima, textos); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *TarefaRepository) Delete(ctx context.Context, fazendaID, id int64) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM tarefas WHERE id = $1 AND fazenda_id = $2`, id, fazendaID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// MarcarChecklistItem marca ou desmarca um item da tarefa; pgx.ErrNoRows se o item não é dela.
+func (r *TarefaRepository) MarcarChecklistItem(ctx context.Context, tarefaID, itemID int64, feito bool, actorID int64) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE tarefas_checklist_itens
+		SET feito = $3,
+		    feito_por = CASE WHEN $3 THEN $4::bigint ELSE NULL END,
+		    feito_em = CASE WHEN $3 THEN NOW() ELSE NULL END
+		WHERE id = $2 AND tarefa_id = $1
+	`, tarefaID, itemID, feito, actorID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	_, err = r.db.Exec(ctx, `UPDATE tarefas SET updated_at = NOW() WHERE id = $1`, tarefaID)
+	return err
+}
+
+// VinculosDaFazenda confere que animal, lote e área informados (nil = sem vínculo) são da fazenda.
+func (r *TarefaRepository) VinculosDaFazenda(ctx context.Context, fazendaID int64, animalID, loteID, areaID *int64) (bool, error) {
+	var ok bool
+	err := r.db.QueryRow(ctx, `
+		SELECT
+			($2::bigint IS NULL OR EXISTS (SELECT 1 FROM animais WHERE id = $2 AND fazenda_id = $1))
+			AND ($3::bigint IS NULL OR EXISTS (SELECT 1 FROM lotes WHERE id = $3 AND fazenda_id = $1))
+			AND ($4::bigint IS NULL OR EXISTS (SELECT 1 FROM areas WHERE id = $4 AND fazenda_id = $1))
+	`, fazendaID, animalID, loteID, areaID).Scan(&ok)
+	return ok, err
+}
@@ -56,7 +56,7 @@ SELECT 'CIO'::text AS tipo,
        c.id AS ref_id,
        c.usuario_id AS created_by
 FROM cios c
-WHERE c.animal_id = $1
+WHERE c.animal_id = $1 AND c.excluido_em IS NULL
 
 UNION ALL
 
@@ -67,7 +67,7 @@ SELECT 'COBERTURA',
        cb.id,
        cb.created_by
 FROM coberturas cb
-WHERE cb.animal_id = $1
+WHERE cb.animal_id = $1 AND cb.excluido_em IS NULL
 
 UNION ALL
 
@@ -129,7 +129,7 @@ SELECT 'PARTO',
        p.id,
        p.created_by
 FROM partos p
-WHERE p.animal_id = $1
+WHERE p.animal_id = $1 AND p.excluido_em IS NULL
 
 UNION ALL
 
@@ -154,7 +154,7 @@ SELECT 'PRODUCAO',
        pl.id,
        pl.created_by
 FROM producao_leite pl
-WHERE pl.animal_id = $1
+WHERE pl.animal_id = $1 AND pl.excluido_em IS NULL
 
 UNION ALL
 
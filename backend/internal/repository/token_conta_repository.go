@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrTokenContaIndisponivel token inexistente, já usado, expirado ou de outro tipo.
+var ErrTokenContaIndisponivel = errors.New("link inválido ou expirado")
+
+type TokenContaRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTokenContaRepository(db *pgxpool.Pool) *TokenContaRepository {
+	return &TokenContaRepository{db: db}
+}
+
+// Create grava o token com o hash do valor em claro; preenche ID e CreatedAt.
+func (r *TokenContaRepository) Create(ctx context.Context, t *models.TokenConta, tokenHash string) error {
+	return r.db.QueryRow(ctx, `
+		INSERT INTO tokens_conta (usuario_id, tipo, token_hash, email, expira_em)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`, t.UsuarioID, t.Tipo, tokenHash, t.Email, t.ExpiraEm).Scan(&t.ID, &t.CreatedAt)
+}
+
+// CountDesde tokens do tipo emitidos para o usuário a partir de desde (limite de envios por conta).
+func (r *TokenContaRepository) CountDesde(ctx context.Context, usuarioID int64, tipo string, desde time.Time) (int, error) {
+	var n int
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM tokens_conta WHERE usuario_id = $1 AND tipo = $2 AND created_at >= $3
+	`, usuarioID, tipo, desde).Scan(&n)
+	return n, err
+}
+
+// consumirTokenConta marca o token como usado (só se pendente e válido em em) e invalida os outros pendentes
+// do mesmo tipo da conta: o link mais antigo deixa de funcionar quando um deles é usado.
+func consumirTokenConta(ctx context.Context, tx pgx.Tx, tokenHash, tipo string, em time.Time) (int64, string, error) {
+	var usuarioID int64
+	var email string
+	err := tx.QueryRow(ctx, `
+		UPDATE tokens_conta SET usado_em = $3
+		WHERE token_hash = $1 AND tipo = $2 AND usado_em IS NULL AND expira_em > $3
+		RETURNING usuario_id, email
+	`, tokenHash, tipo, em).Scan(&usuarioID, &email)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, "", ErrTokenContaIndisponivel
+	}
+	if err != nil {
+		return 0, "", err
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE tokens_conta SET usado_em = $3 WHERE usuario_id = $1 AND tipo = $2 AND usado_em IS NULL
+	`, usuarioID, tipo, em); err != nil {
+		return 0, "", err
+	}
+	return usuarioID, email, nil
+}
+
+// atualizarSenhaTx grava o novo hash e revoga todos os refresh tokens da conta (todas as sessões).
+func atualizarSenhaTx(ctx context.Context, tx pgx.Tx, usuarioID int64, senhaHash string) error {
+	if _, err := tx.Exec(ctx, `
+		UPDATE usuarios SET senha = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1
+	`, usuarioID, senhaHash); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx, `UPDATE refresh_tokens SET revoked = TRUE WHERE user_id = $1 AND revoked = FALSE`, usuarioID)
+	return err
+}
+
+// RedefinirSenha consome o token de reset e troca a senha numa transação, revogando as sessões.
+// Redefinir pelo link prova a posse do e-mail, então a conta também fica verificada.
+func (r *TokenContaRepository) RedefinirSenha(ctx context.Context, tokenHash, senhaHash string, em time.Time) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	usuarioID, email, err := consumirTokenConta(ctx, tx, tokenHash, models.TokenContaResetSenha, em)
+	if err != nil {
+		return 0, err
+	}
+	if err := atualizarSenhaTx(ctx, tx, usuarioID, senhaHash); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE usuarios SET email_verificado_em = $3
+		WHERE id = $1 AND email = $2 AND email_verificado_em IS NULL
+	`, usuarioID, email, em); err != nil {
+		return 0, err
+	}
+	return usuarioID, tx.Commit(ctx)
+}
+
+// AlterarSenha troca a senha de quem está autenticado, revoga as sessões e invalida links de reset pendentes.
+func (r *TokenContaRepository) AlterarSenha(ctx context.Context, usuarioID int64, senhaHash string, em time.Time) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := atualizarSenhaTx(ctx, tx, usuarioID, senhaHash); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE tokens_conta SET usado_em = $3 WHERE usuario_id = $1 AND tipo = $2 AND usado_em IS NULL
+	`, usuarioID, models.TokenContaResetSenha, em); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// VerificarEmail consome o token de verificação e marca o e-mail como confirmado. O token só vale para o
+// endereço a que foi enviado: se a conta trocou de e-mail depois, ErrTokenContaIndisponivel.
+func (r *TokenContaRepository) VerificarEmail(ctx context.Context, tokenHash string, em time.Time) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	usuarioID, email, err := consumirTokenConta(ctx, tx, tokenHash, models.TokenContaVerificacaoEmail, em)
+	if err != nil {
+		return 0, err
+	}
+	tag, err := tx.Exec(ctx, `
+		UPDATE usuarios SET email_verificado_em = COALESCE(email_verificado_em, $3)
+		WHERE id = $1 AND email = $2
+	`, usuarioID, email, em)
+	if err != nil {
+		return 0, err
+	}
+	if tag.RowsAffected() == 0 {
+		return 0, ErrTokenContaIndisponivel
+	}
+	return usuarioID, tx.Commit(ctx)
+}
@@ -18,7 +18,7 @@ func NewUsuarioRepository(db *pgxpool.Pool) *UsuarioRepository {
 
 func (r *UsuarioRepository) GetByEmail(ctx context.Context, email string) (*models.Usuario, error) {
 	query := `
-		SELECT id, nome, email, senha, perfil, enabled, created_at, updated_at
+		SELECT id, nome, email, senha, perfil, enabled, created_at, updated_at, email_verificado_em
 		FROM usuarios
 		WHERE email = $1
 	`
@@ -33,6 +33,7 @@ func (r *UsuarioRepository) GetByEmail(ctx context.Context, email string) (*mode
 		&u.Enabled,
 		&u.CreatedAt,
 		&u.UpdatedAt,
+		&u.EmailVerificadoEm,
 	)
 	if err == pgx.ErrNoRows {
 		return nil, pgx.ErrNoRows
@@ -67,7 +68,7 @@ func (r *UsuarioRepository) GetNamesByIDs(ctx context.Context, ids []int64) (map
 
 func (r *UsuarioRepository) GetByID(ctx context.Context, id int64) (*models.Usuario, error) {
 	query := `
-		SELECT id, nome, email, senha, perfil, enabled, created_at, updated_at
+		SELECT id, nome, email, senha, perfil, enabled, created_at, updated_at, email_verificado_em
 		FROM usuarios
 		WHERE id = $1
 	`
@@ -82,6 +83,7 @@ func (r *UsuarioRepository) GetByID(ctx context.Context, id int64) (*models.Usua
 		&u.Enabled,
 		&u.CreatedAt,
 		&u.UpdatedAt,
+		&u.EmailVerificadoEm,
 	)
 	if err == pgx.ErrNoRows {
 		return nil, pgx.ErrNoRows
@@ -94,7 +96,7 @@ func (r *UsuarioRepository) GetByID(ctx context.Context, id int64) (*models.Usua
 
 func (r *UsuarioRepository) List(ctx context.Context, limit, offset int) ([]*models.Usuario, error) {
 	query := `
-		SELECT id, nome, email, senha, perfil, enabled, created_at, updated_at
+		SELECT id, nome, email, senha, perfil, enabled, created_at, updated_at, email_verificado_em
 		FROM usuarios
 		ORDER BY nome ASC
 		LIMIT $1 OFFSET $2
@@ -118,6 +120,7 @@ func (r *UsuarioRepository) List(ctx context.Context, limit, offset int) ([]*mod
 			&u.Enabled,
 			&u.CreatedAt,
 			&u.UpdatedAt,
+			&u.EmailVerificadoEm,
 		); err != nil {
 			return nil, err
 		}
@@ -141,6 +144,24 @@ func (r *UsuarioRepository) CountByPerfil(ctx context.Context, perfil string) (i
 	return n, err
 }
 
+// ListIDsAtivosByPerfis retorna IDs de utilizadores habilitados com um dos perfis (ex.: destinatários de avisos admin).
+func (r *UsuarioRepository) ListIDsAtivosByPerfis(ctx context.Context, perfis []string) ([]int64, error) {
+	rows, err := r.db.Query(ctx, `SELECT id FROM usuarios WHERE enabled = true AND perfil = ANY($1) ORDER BY id`, perfis)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
 // ListPendentesProvisao lista utilizadores USER ativos com contagem de fazendas vinculadas (fila de provisão para admin).
 func (r *UsuarioRepository) ListPendentesProvisao(ctx context.Context, limit int) ([]models.UsuarioPendenteProvisao, error) {
 	if limit <= 0 {
@@ -205,26 +226,29 @@ func (r *UsuarioRepository) Create(ctx context.Context, u *models.Usuario) error
 		Scan(&u.ID, &u.CreatedAt, &u.UpdatedAt)
 }
 
+// Update altera os dados cadastrais; trocar o e-mail volta a conta para não verificada (BR-ACESSO-026).
 func (r *UsuarioRepository) Update(ctx context.Context, u *models.Usuario) error {
 	query := `
 		UPDATE usuarios
-		SET nome = $2, email = $3, perfil = $4, enabled = $5, updated_at = CURRENT_TIMESTAMP
+		SET nome = $2, email = $3, perfil = $4, enabled = $5, updated_at = CURRENT_TIMESTAMP,
+		    email_verificado_em = CASE WHEN email = $3 THEN email_verificado_em END
 		WHERE id = $1
-		RETURNING updated_at
+		RETURNING updated_at, email_verificado_em
 	`
 	return r.db.QueryRow(ctx, query, u.ID, u.Nome, u.Email, u.Perfil, u.Enabled).
-		Scan(&u.UpdatedAt)
+		Scan(&u.UpdatedAt, &u.EmailVerificadoEm)
 }
 
 func (r *UsuarioRepository) UpdateWithPassword(ctx context.Context, u *models.Usuario) error {
 	query := `
 		UPDATE usuarios
-		SET nome = $2, email = $3, senha = $4, perfil = $5, enabled = $6, updated_at = CURRENT_TIMESTAMP
+		SET nome = $2, email = $3, senha = $4, perfil = $5, enabled = $6, updated_at = CURRENT_TIMESTAMP,
+		    email_verificado_em = CASE WHEN email = $3 THEN email_verificado_em END
 		WHERE id = $1
-		RETURNING updated_at
+		RETURNING updated_at, email_verificado_em
 	`
 	return r.db.QueryRow(ctx, query, u.ID, u.Nome, u.Email, u.Senha, u.Perfil, u.Enabled).
-		Scan(&u.UpdatedAt)
+		Scan(&u.UpdatedAt, &u.EmailVerificadoEm)
 }
 
 func (r *UsuarioRepository) ToggleEnabled(ctx context.Context, id int64, enabled bool) error {
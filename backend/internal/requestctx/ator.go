@@ -0,0 +1,36 @@
+package requestctx
+
+import "context"
+
+type atorCtxKey struct{}
+
+type correlationIDCtxKey struct{}
+
+// Ator identifica quem executa o pedido; propagado no context.Context do request até aos services
+// (auditoria). UsuarioID 0 = processo do sistema (cron, jobs).
+type Ator struct {
+	UsuarioID int64
+	Perfil    string
+}
+
+// WithAtor anexa o ator autenticado ao contexto.
+func WithAtor(ctx context.Context, ator Ator) context.Context {
+	return context.WithValue(ctx, atorCtxKey{}, ator)
+}
+
+// AtorFromContext devolve o ator do contexto, se houver.
+func AtorFromContext(ctx context.Context) (Ator, bool) {
+	ator, ok := ctx.Value(atorCtxKey{}).(Ator)
+	return ator, ok
+}
+
+// WithCorrelationID anexa o correlation ID ao contexto (fora do gin.Context).
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDCtxKey{}, id)
+}
+
+// CorrelationIDFromContext devolve o correlation ID do contexto ou "".
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDCtxKey{}).(string)
+	return id
+}
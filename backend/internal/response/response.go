@@ -41,6 +41,7 @@ const (
 	CodeBadRequest          = "BAD_REQUEST"
 	CodeQuotaExceeded       = "QUOTA_EXCEEDED"
 	CodeServiceUnavailable  = "SERVICE_UNAVAILABLE"
+	CodeTwoFactorRequired   = "TWO_FACTOR_REQUIRED"
 )
 
 // Success retorna uma resposta de sucesso padronizada
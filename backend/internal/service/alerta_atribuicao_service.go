@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// alertaEscalonamentoLote máximo de alertas escalonados por execução do cron.
+const alertaEscalonamentoLote = 200
+
+type alertaAtribuicaoStore interface {
+	UpdateResponsavel(ctx context.Context, fazendaID, alertaID int64, responsavelID *int64) error
+	ListParaEscalonar(ctx context.Context, agora time.Time, limit int) ([]models.AlertaWithNames, error)
+	MarcarEscalonado(ctx context.Context, fazendaID, alertaID int64, nivel int, proximo *time.Time) (bool, error)
+}
+
+type alertaAtividadeStore interface {
+	Create(ctx context.Context, row *models.AlertaAtividade) error
+	ListByAlerta(ctx context.Context, fazendaID, alertaID int64) ([]*models.AlertaAtividade, error)
+}
+
+type alertaUsuariosFazendaStore interface {
+	ListUsuariosPublicosByFazendaID(ctx context.Context, fazendaID int64) ([]models.UsuarioPublico, error)
+}
+
+func (s *AlertaService) getAlerta(ctx context.Context, fazendaID, alertaID int64) (*models.AlertaWithNames, error) {
+	row, err := s.repo.GetByID(ctx, fazendaID, alertaID)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && row == nil) {
+		return nil, ErrAlertaNotFound
+	}
+	return row, err
+}
+
+type AtribuirAlertaInput struct {
+	// ResponsavelID nil remove a atribuição.
+	ResponsavelID *int64
+	ActorUserID   int64
+	Perfil        string
+}
+
+// Atribuir define o responsável do alerta (BR-ALERTA-023). Gestão atribui a qualquer utilizador da
+// fazenda; os demais perfis operacionais só assumem ou largam o alerta para si.
+func (s *AlertaService) Atribuir(ctx context.Context, fazendaID, alertaID int64, in AtribuirAlertaInput) (*models.AlertaWithNames, error) {
+	if !models.PodeMarcarAlertaEmAndamento(in.Perfil) {
+		return nil, ErrAlertaForbidden
+	}
+	existing, err := s.getAlerta(ctx, fazendaID, alertaID)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Status == models.AlertaStatusResolvido || existing.Status == models.AlertaStatusIgnorado {
+		return nil, ErrAlertaEncerrado
+	}
+	if !models.PodeAtribuirAlerta(in.Perfil) {
+		proprio := in.ResponsavelID != nil && *in.ResponsavelID == in.ActorUserID
+		largar := in.ResponsavelID == nil && existing.ResponsavelID != nil && *existing.ResponsavelID == in.ActorUserID
+		if !proprio && !largar {
+			return nil, ErrAlertaForbidden
+		}
+	}
+	if sameInt64Ptr(existing.ResponsavelID, in.ResponsavelID) {
+		return existing, nil
+	}
+
+	texto := "Atribuição removida"
+	if in.ResponsavelID != nil {
+		usuarios, err := s.usuariosFaz.ListUsuariosPublicosByFazendaID(ctx, fazendaID)
+		if err != nil {
+			return nil, err
+		}
+		nome := ""
+		for _, u := range usuarios {
+			if u.ID == *in.ResponsavelID {
+				nome = u.Nome
+				break
+			}
+		}
+		if nome == "" {
+			return nil, ErrAlertaResponsavelInvalido
+		}
+		texto = "Atribuído a " + nome
+	}
+
+	if err := s.atribuicao.UpdateResponsavel(ctx, fazendaID, alertaID, in.ResponsavelID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAlertaNotFound
+		}
+		return nil, err
+	}
+	s.registrarAtividade(ctx, &models.AlertaAtividade{
+		AlertaID:  alertaID,
+		FazendaID: fazendaID,
+		UsuarioID: &in.ActorUserID,
+		Tipo:      models.AlertaAtividadeAtribuicao,
+		Texto:     texto,
+		Dados:     alertaAtividadeDados(map[string]interface{}{"de": existing.ResponsavelID, "para": in.ResponsavelID}),
+	})
+	s.publicarEventoAlerta(ctx, models.AuditoriaAcaoUpdate, &existing.Alerta)
+	if in.ResponsavelID != nil && *in.ResponsavelID != in.ActorUserID && s.pushSvc != nil {
+		s.pushSvc.NotifyUsuarios([]int64{*in.ResponsavelID}, "Alerta atribuído a você", existing.Titulo, alertaURL(existing))
+	}
+	return s.repo.GetByID(ctx, fazendaID, alertaID)
+}
+
+type ComentarAlertaInput struct {
+	Texto       string
+	ActorUserID int64
+	Perfil      string
+}
+
+// Comentar adiciona um comentário ao histórico do alerta; o responsável (se outro) é notificado.
+func (s *AlertaService) Comentar(ctx context.Context, fazendaID, alertaID int64, in ComentarAlertaInput) (*models.AlertaAtividade, error) {
+	if !models.PodeMarcarAlertaEmAndamento(in.Perfil) {
+		return nil, ErrAlertaForbidden
+	}
+	texto := strings.TrimSpace(in.Texto)
+	if texto == "" || utf8.RuneCountInString(texto) > models.AlertaComentarioMaxLen {
+		return nil, ErrAlertaComentarioInvalido
+	}
+	existing, err := s.getAlerta(ctx, fazendaID, alertaID)
+	if err != nil {
+		return nil, err
+	}
+	row := &models.AlertaAtividade{
+		AlertaID:  alertaID,
+		FazendaID: fazendaID,
+		UsuarioID: &in.ActorUserID,
+		Tipo:      models.AlertaAtividadeComentario,
+		Texto:     texto,
+	}
+	if err := s.atividades.Create(ctx, row); err != nil {
+		return nil, err
+	}
+	s.publicarEventoAlerta(ctx, models.AuditoriaAcaoUpdate, &existing.Alerta)
+	if existing.ResponsavelID != nil && *existing.ResponsavelID != in.ActorUserID && s.pushSvc != nil {
+		s.pushSvc.NotifyUsuarios([]int64{*existing.ResponsavelID}, "Novo comentário: "+existing.Titulo, texto, alertaURL(existing))
+	}
+	return row, nil
+}
+
+// ListAtividades devolve comentários e eventos do alerta em ordem cronológica.
+func (s *AlertaService) ListAtividades(ctx context.Context, fazendaID, alertaID int64) ([]*models.AlertaAtividade, error) {
+	if _, err := s.getAlerta(ctx, fazendaID, alertaID); err != nil {
+		return nil, err
+	}
+	return s.atividades.ListByAlerta(ctx, fazendaID, alertaID)
+}
+
+// EscalonarVencidos sobe um nível os alertas ainda ABERTO com escalonamento vencido e notifica os
+// perfis do novo nível (GERENTE/GESTAO, depois PROPRIETARIO). Cada nível seguinte vence um SLA depois.
+func (s *AlertaService) EscalonarVencidos(ctx context.Context, agora time.Time) (int, error) {
+	list, err := s.atribuicao.ListParaEscalonar(ctx, agora, alertaEscalonamentoLote)
+	if err != nil {
+		return 0, err
+	}
+	escalonados := 0
+	for i := range list {
+		a := &list[i]
+		nivel := a.EscalonamentoNivel + 1
+		if nivel > models.AlertaEscalonamentoMaxNivel {
+			continue
+		}
+		var proximo *time.Time
+		if nivel < models.AlertaEscalonamentoMaxNivel {
+			p := agora.Add(models.AlertaSLA(a.Severidade))
+			proximo = &p
+		}
+		ok, err := s.atribuicao.MarcarEscalonado(ctx, a.FazendaID, a.ID, nivel, proximo)
+		if err != nil {
+			return escalonados, err
+		}
+		if !ok {
+			continue
+		}
+		perfis := models.AlertaEscalonamentoPerfis(nivel)
+		s.registrarAtividade(ctx, &models.AlertaAtividade{
+			AlertaID:  a.ID,
+			FazendaID: a.FazendaID,
+			Tipo:      models.AlertaAtividadeEscalonamento,
+			Texto:     fmt.Sprintf("Prazo ultrapassado: escalonado para %s", strings.Join(perfis, ", ")),
+			Dados:     alertaAtividadeDados(map[string]interface{}{"nivel": nivel, "perfis": perfis}),
+		})
+		a.EscalonamentoNivel = nivel
+		s.publicarEventoAlerta(ctx, models.AuditoriaAcaoUpdate, &a.Alerta)
+		if s.pushSvc != nil {
+			s.pushSvc.NotifyAlertaEscalonado(a, perfis)
+		}
+		escalonados++
+	}
+	return escalonados, nil
+}
+
+// registrarAtividade grava um evento do histórico; falha não desfaz a alteração já aplicada.
+func (s *AlertaService) registrarAtividade(ctx context.Context, row *models.AlertaAtividade) {
+	if s.atividades == nil {
+		return
+	}
+	if err := s.atividades.Create(ctx, row); err != nil {
+		slog.Warn("alertas: registrar atividade", "error", err, "alerta_id", row.AlertaID, "tipo", row.Tipo)
+	}
+}
+
+func alertaAtividadeDados(v map[string]interface{}) json.RawMessage {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+func alertaURL(a *models.AlertaWithNames) string {
+	return fmt.Sprintf("/alertas?id=%d", a.ID)
+}
+
+func sameInt64Ptr(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
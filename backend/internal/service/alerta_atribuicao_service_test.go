@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+func (f *fakeAlertaRepo) UpdateResponsavel(_ context.Context, fazendaID, alertaID int64, responsavelID *int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	a, ok := f.byID[alertaID]
+	if !ok || a.FazendaID != fazendaID {
+		return pgx.ErrNoRows
+	}
+	a.ResponsavelID = responsavelID
+	return nil
+}
+
+func (f *fakeAlertaRepo) ListParaEscalonar(_ context.Context, agora time.Time, _ int) ([]models.AlertaWithNames, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []models.AlertaWithNames
+	for _, a := range f.byID {
+		if a.Status == models.AlertaStatusAberto && a.EscalonarEm != nil && !a.EscalonarEm.After(agora) {
+			out = append(out, *a)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeAlertaRepo) MarcarEscalonado(_ context.Context, _, alertaID int64, nivel int, proximo *time.Time) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	a := f.byID[alertaID]
+	if a.Status != models.AlertaStatusAberto || a.EscalonamentoNivel != nivel-1 {
+		return false, nil
+	}
+	a.EscalonamentoNivel = nivel
+	a.EscalonarEm = proximo
+	return true, nil
+}
+
+type fakeAlertaAtividades struct {
+	rows []*models.AlertaAtividade
+}
+
+func (f *fakeAlertaAtividades) Create(_ context.Context, row *models.AlertaAtividade) error {
+	row.ID = int64(len(f.rows) + 1)
+	f.rows = append(f.rows, row)
+	return nil
+}
+
+func (f *fakeAlertaAtividades) ListByAlerta(_ context.Context, _, alertaID int64) ([]*models.AlertaAtividade, error) {
+	var out []*models.AlertaAtividade
+	for _, r := range f.rows {
+		if r.AlertaID == alertaID {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+type fakeAlertaUsuariosFazenda []models.UsuarioPublico
+
+func (f fakeAlertaUsuariosFazenda) ListUsuariosPublicosByFazendaID(context.Context, int64) ([]models.UsuarioPublico, error) {
+	return f, nil
+}
+
+func newAlertaAtribuicaoServiceForTest() (*AlertaService, *fakeAlertaRepo, *fakeAlertaAtividades) {
+	repo := newFakeAlertaRepo()
+	atividades := &fakeAlertaAtividades{}
+	usuarios := fakeAlertaUsuariosFazenda{
+		{ID: 1, Nome: "Ana", Perfil: models.PerfilGerente},
+		{ID: 2, Nome: "Bruno", Perfil: models.PerfilFuncionario},
+		{ID: 3, Nome: "Carla", Perfil: models.PerfilFuncionario},
+	}
+	return &AlertaService{repo: repo, atribuicao: repo, atividades: atividades, usuariosFaz: usuarios}, repo, atividades
+}
+
+func TestAlertaService_Atribuir_Permissoes(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, atividades := newAlertaAtribuicaoServiceForTest()
+	a := &models.AlertaWithNames{Alerta: models.Alerta{FazendaID: 10, Titulo: "Cio", Status: models.AlertaStatusAberto}}
+	repo.seed(a)
+
+	// FUNCIONARIO não atribui a outra pessoa.
+	if _, err := svc.Atribuir(ctx, 10, a.ID, AtribuirAlertaInput{ResponsavelID: int64Ptr(3), ActorUserID: 2, Perfil: models.PerfilFuncionario}); !errors.Is(err, ErrAlertaForbidden) {
+		t.Fatalf("funcionario atribuindo a outro: %v", err)
+	}
+	// ...mas assume para si.
+	if _, err := svc.Atribuir(ctx, 10, a.ID, AtribuirAlertaInput{ResponsavelID: int64Ptr(2), ActorUserID: 2, Perfil: models.PerfilFuncionario}); err != nil {
+		t.Fatalf("funcionario assumindo: %v", err)
+	}
+	// Outro FUNCIONARIO não larga o alerta alheio.
+	if _, err := svc.Atribuir(ctx, 10, a.ID, AtribuirAlertaInput{ActorUserID: 3, Perfil: models.PerfilFuncionario}); !errors.Is(err, ErrAlertaForbidden) {
+		t.Fatalf("largar alerta alheio: %v", err)
+	}
+	// Gestão reatribui; utilizador fora da fazenda é recusado.
+	if _, err := svc.Atribuir(ctx, 10, a.ID, AtribuirAlertaInput{ResponsavelID: int64Ptr(99), ActorUserID: 1, Perfil: models.PerfilGerente}); !errors.Is(err, ErrAlertaResponsavelInvalido) {
+		t.Fatalf("responsavel fora da fazenda: %v", err)
+	}
+	got, err := svc.Atribuir(ctx, 10, a.ID, AtribuirAlertaInput{ResponsavelID: int64Ptr(3), ActorUserID: 1, Perfil: models.PerfilGerente})
+	if err != nil || got.ResponsavelID == nil || *got.ResponsavelID != 3 {
+		t.Fatalf("gerente reatribuindo: %v %+v", err, got)
+	}
+
+	if len(atividades.rows) != 2 || atividades.rows[1].Texto != "Atribuído a Carla" {
+		t.Fatalf("atividades: %+v", atividades.rows)
+	}
+
+	a.Status = models.AlertaStatusResolvido
+	if _, err := svc.Atribuir(ctx, 10, a.ID, AtribuirAlertaInput{ResponsavelID: int64Ptr(1), ActorUserID: 1, Perfil: models.PerfilGerente}); !errors.Is(err, ErrAlertaEncerrado) {
+		t.Fatalf("alerta encerrado: %v", err)
+	}
+}
+
+func TestAlertaService_UpdateStatus_EmAndamentoAssume(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, atividades := newAlertaAtribuicaoServiceForTest()
+	a := &models.AlertaWithNames{Alerta: models.Alerta{FazendaID: 10, Status: models.AlertaStatusAberto}}
+	repo.seed(a)
+
+	if _, err := svc.UpdateStatus(ctx, 10, a.ID, UpdateAlertaStatusInput{Status: models.AlertaStatusEmAndamento, ActorUserID: 2, Perfil: models.PerfilFuncionario}); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	if a.ResponsavelID == nil || *a.ResponsavelID != 2 {
+		t.Fatalf("quem coloca em andamento deve assumir: %v", a.ResponsavelID)
+	}
+	if len(atividades.rows) != 2 || atividades.rows[0].Tipo != models.AlertaAtividadeStatus || atividades.rows[1].Tipo != models.AlertaAtividadeAtribuicao {
+		t.Fatalf("atividades: %+v", atividades.rows)
+	}
+}
+
+func TestAlertaService_Comentar_Validacao(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _ := newAlertaAtribuicaoServiceForTest()
+	a := &models.AlertaWithNames{Alerta: models.Alerta{FazendaID: 10, Status: models.AlertaStatusAberto}}
+	repo.seed(a)
+
+	in := ComentarAlertaInput{Texto: "   ", ActorUserID: 2, Perfil: models.PerfilFuncionario}
+	if _, err := svc.Comentar(ctx, 10, a.ID, in); !errors.Is(err, ErrAlertaComentarioInvalido) {
+		t.Fatalf("vazio: %v", err)
+	}
+	in.Texto = strings.Repeat("é", models.AlertaComentarioMaxLen+1)
+	if _, err := svc.Comentar(ctx, 10, a.ID, in); !errors.Is(err, ErrAlertaComentarioInvalido) {
+		t.Fatalf("longo: %v", err)
+	}
+	in.Texto = strings.Repeat("é", models.AlertaComentarioMaxLen)
+	if _, err := svc.Comentar(ctx, 10, a.ID, in); err != nil {
+		t.Fatalf("limite em caracteres, não bytes: %v", err)
+	}
+	in.Perfil = models.PerfilUser
+	if _, err := svc.Comentar(ctx, 10, a.ID, in); !errors.Is(err, ErrAlertaForbidden) {
+		t.Fatalf("USER: %v", err)
+	}
+	if _, err := svc.ListAtividades(ctx, 10, 999); !errors.Is(err, ErrAlertaNotFound) {
+		t.Fatalf("alerta inexistente: %v", err)
+	}
+}
+
+func TestAlertaService_EscalonarVencidos(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, atividades := newAlertaAtribuicaoServiceForTest()
+	agora := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	vencido := agora.Add(-time.Minute)
+	futuro := agora.Add(time.Hour)
+	a := &models.AlertaWithNames{Alerta: models.Alerta{FazendaID: 10, Severidade: models.AlertaSeveridadeCritica, Status: models.AlertaStatusAberto, EscalonarEm: &vencido}}
+	b := &models.AlertaWithNames{Alerta: models.Alerta{FazendaID: 10, Severidade: models.AlertaSeveridadeAlta, Status: models.AlertaStatusAberto, EscalonarEm: &futuro}}
+	repo.seed(a)
+	repo.seed(b)
+
+	n, err := svc.EscalonarVencidos(ctx, agora)
+	if err != nil || n != 1 {
+		t.Fatalf("nível 1: n=%d err=%v", n, err)
+	}
+	if a.EscalonamentoNivel != 1 || a.EscalonarEm == nil || !a.EscalonarEm.Equal(agora.Add(4*time.Hour)) {
+		t.Fatalf("próximo escalonamento deve ser um SLA depois: %+v", a.Alerta)
+	}
+	if b.EscalonamentoNivel != 0 {
+		t.Fatal("alerta dentro do prazo não escalona")
+	}
+
+	n, _ = svc.EscalonarVencidos(ctx, agora.Add(5*time.Hour))
+	if n != 2 || a.EscalonamentoNivel != models.AlertaEscalonamentoMaxNivel || a.EscalonarEm != nil {
+		t.Fatalf("nível 2 é o último: n=%d %+v", n, a.Alerta)
+	}
+	b.Status = models.AlertaStatusEmAndamento
+	if n, _ := svc.EscalonarVencidos(ctx, agora.Add(100*time.Hour)); n != 0 {
+		t.Fatalf("sem escalonamento após o último nível nem fora de ABERTO: %d", n)
+	}
+
+	var ultimo *models.AlertaAtividade
+	for _, r := range atividades.rows {
+		if r.AlertaID == a.ID {
+			ultimo = r
+		}
+	}
+	if ultimo == nil || ultimo.UsuarioID != nil || !strings.Contains(ultimo.Texto, models.PerfilProprietario) {
+		t.Fatalf("atividade de escalonamento: %+v", ultimo)
+	}
+}
+
+func TestAlertaSLA(t *testing.T) {
+	if models.AlertaSLA(models.AlertaSeveridadeCritica) >= models.AlertaSLA(models.AlertaSeveridadeAlta) ||
+		models.AlertaSLA(models.AlertaSeveridadeMedia) >= models.AlertaSLA(models.AlertaSeveridadeBaixa) {
+		t.Fatal("SLA deve crescer com a severidade mais baixa")
+	}
+}
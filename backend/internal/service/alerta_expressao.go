@@ -0,0 +1,696 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/repository"
+)
+
+// Linguagem de expressões das regras de alerta personalizadas (BR-ALERTA-020).
+//
+//	expr   := ou
+//	ou     := e { ("or" | "ou" | "||") e }
+//	e      := nao { ("and" | "e" | "&&") nao }
+//	nao    := ("not" | "nao" | "não" | "!") nao | cmp
+//	cmp    := soma [ op soma | ["not"] "in" "[" literal {"," literal} "]" ]   op: = == != <> < <= > >=
+//	soma   := termo { ("+" | "-") termo }
+//	termo  := unario { ("*" | "/") unario }
+//	unario := "-" unario | primario
+//	primario := número | "texto" | 'texto' | true | false | null | variável | "(" expr ")"
+//
+// Só lê variáveis do catálogo (alertaExprVariaveis); não há chamadas de função nem acesso a dados fora dele.
+// Tipos verificados na compilação. null: aritmética com null dá null; = / != comparam com null;
+// <, <=, >, >= e in com null dão false; and/or/not tratam null como false. Texto compara sem
+// distinguir maiúsculas.
+
+var ErrAlertaExpressaoInvalida = errors.New("expressão inválida")
+
+type exprTipo int
+
+const (
+	exprNulo exprTipo = iota
+	exprNumero
+	exprTexto
+	exprBooleano
+)
+
+func (t exprTipo) String() string {
+	switch t {
+	case exprNumero:
+		return "NUMERO"
+	case exprTexto:
+		return "TEXTO"
+	case exprBooleano:
+		return "BOOLEANO"
+	default:
+		return "NULO"
+	}
+}
+
+type exprValor struct {
+	tipo exprTipo
+	num  float64
+	txt  string
+	b    bool
+}
+
+var exprValorNulo = exprValor{}
+
+func exprNum(v float64) exprValor { return exprValor{tipo: exprNumero, num: v} }
+func exprTxt(v string) exprValor  { return exprValor{tipo: exprTexto, txt: v} }
+func exprBool(v bool) exprValor   { return exprValor{tipo: exprBooleano, b: v} }
+
+func exprTxtPtr(v *string) exprValor {
+	if v == nil {
+		return exprValorNulo
+	}
+	return exprTxt(*v)
+}
+
+func (v exprValor) verdadeiro() bool { return v.tipo == exprBooleano && v.b }
+
+func exprIguais(a, b exprValor) bool {
+	if a.tipo == exprNulo || b.tipo == exprNulo {
+		return a.tipo == b.tipo
+	}
+	switch a.tipo {
+	case exprNumero:
+		return a.num == b.num
+	case exprTexto:
+		return strings.EqualFold(a.txt, b.txt)
+	default:
+		return a.b == b.b
+	}
+}
+
+// exprAmbiente resolve o valor de uma variável do catálogo para o animal avaliado.
+type exprAmbiente func(nome string) exprValor
+
+type exprNo interface {
+	tipo() exprTipo
+	avaliar(env exprAmbiente) exprValor
+}
+
+type exprLiteral struct{ v exprValor }
+
+func (n exprLiteral) tipo() exprTipo                 { return n.v.tipo }
+func (n exprLiteral) avaliar(exprAmbiente) exprValor { return n.v }
+
+type exprVariavel struct {
+	nome string
+	t    exprTipo
+}
+
+func (n exprVariavel) tipo() exprTipo                     { return n.t }
+func (n exprVariavel) avaliar(env exprAmbiente) exprValor { return env(n.nome) }
+
+type exprUnario struct {
+	op string
+	x  exprNo
+}
+
+func (n exprUnario) tipo() exprTipo {
+	if n.op == "-" {
+		return exprNumero
+	}
+	return exprBooleano
+}
+
+func (n exprUnario) avaliar(env exprAmbiente) exprValor {
+	v := n.x.avaliar(env)
+	if n.op == "-" {
+		if v.tipo != exprNumero {
+			return exprValorNulo
+		}
+		return exprNum(-v.num)
+	}
+	return exprBool(!v.verdadeiro())
+}
+
+type exprBinario struct {
+	op   string
+	l, r exprNo
+}
+
+func (n exprBinario) tipo() exprTipo {
+	switch n.op {
+	case "+", "-", "*", "/":
+		return exprNumero
+	default:
+		return exprBooleano
+	}
+}
+
+func (n exprBinario) avaliar(env exprAmbiente) exprValor {
+	switch n.op {
+	case "and":
+		return exprBool(n.l.avaliar(env).verdadeiro() && n.r.avaliar(env).verdadeiro())
+	case "or":
+		return exprBool(n.l.avaliar(env).verdadeiro() || n.r.avaliar(env).verdadeiro())
+	}
+	l, r := n.l.avaliar(env), n.r.avaliar(env)
+	switch n.op {
+	case "=":
+		return exprBool(exprIguais(l, r))
+	case "!=":
+		return exprBool(!exprIguais(l, r))
+	}
+	if l.tipo != exprNumero || r.tipo != exprNumero {
+		if n.tipo() == exprNumero {
+			return exprValorNulo
+		}
+		return exprBool(false)
+	}
+	switch n.op {
+	case "+":
+		return exprNum(l.num + r.num)
+	case "-":
+		return exprNum(l.num - r.num)
+	case "*":
+		return exprNum(l.num * r.num)
+	case "/":
+		if r.num == 0 {
+			return exprValorNulo
+		}
+		return exprNum(l.num / r.num)
+	case "<":
+		return exprBool(l.num < r.num)
+	case "<=":
+		return exprBool(l.num <= r.num)
+	case ">":
+		return exprBool(l.num > r.num)
+	default: // ">="
+		return exprBool(l.num >= r.num)
+	}
+}
+
+type exprEm struct {
+	x      exprNo
+	lista  []exprValor
+	negado bool
+}
+
+func (n exprEm) tipo() exprTipo { return exprBooleano }
+
+func (n exprEm) avaliar(env exprAmbiente) exprValor {
+	v := n.x.avaliar(env)
+	if v.tipo == exprNulo {
+		return exprBool(false)
+	}
+	for _, item := range n.lista {
+		if exprIguais(v, item) {
+			return exprBool(!n.negado)
+		}
+	}
+	return exprBool(n.negado)
+}
+
+// AlertaExpressao é uma expressão compilada e verificada; seguro para uso concorrente.
+type AlertaExpressao struct {
+	raiz exprNo
+}
+
+// Avaliar indica se o animal satisfaz a expressão.
+func (e *AlertaExpressao) Avaliar(env exprAmbiente) bool {
+	return e.raiz.avaliar(env).verdadeiro()
+}
+
+// CompilarAlertaExpressao analisa a expressão, valida variáveis e tipos e exige resultado booleano.
+func CompilarAlertaExpressao(src string) (*AlertaExpressao, error) {
+	if strings.TrimSpace(src) == "" {
+		return nil, fmt.Errorf("%w: expressão vazia", ErrAlertaExpressaoInvalida)
+	}
+	if len(src) > models.AlertaRegraCustomExpressaoMaxLen {
+		return nil, fmt.Errorf("%w: máximo de %d caracteres", ErrAlertaExpressaoInvalida, models.AlertaRegraCustomExpressaoMaxLen)
+	}
+	toks, err := exprTokenizar(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{toks: toks}
+	raiz, err := p.ou()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.atual(); t.tipo != exprTokFim {
+		return nil, p.erro(t, "símbolo inesperado %q", t.texto)
+	}
+	if raiz.tipo() != exprBooleano {
+		return nil, fmt.Errorf("%w: a expressão deve resultar em verdadeiro/falso (obtido %s)", ErrAlertaExpressaoInvalida, raiz.tipo())
+	}
+	return &AlertaExpressao{raiz: raiz}, nil
+}
+
+type exprTokTipo int
+
+const (
+	exprTokFim exprTokTipo = iota
+	exprTokNumero
+	exprTokTexto
+	exprTokIdent
+	exprTokOp
+)
+
+type exprToken struct {
+	tipo  exprTokTipo
+	texto string // operador normalizado, identificador em minúsculas ou conteúdo do texto
+	num   float64
+	pos   int // posição (1-based, em caracteres) para mensagens de erro
+}
+
+var exprOperadores = []string{"==", "!=", "<>", "<=", ">=", "&&", "||", "(", ")", "[", "]", ",", "+", "-", "*", "/", "=", "<", ">", "!"}
+
+var exprOpNormalizado = map[string]string{"==": "=", "<>": "!=", "&&": "and", "||": "or", "!": "not"}
+
+func exprTokenizar(src string) ([]exprToken, error) {
+	rs := []rune(src)
+	var toks []exprToken
+	for i := 0; i < len(rs); {
+		c := rs[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsDigit(c) || (c == '.' && i+1 < len(rs) && unicode.IsDigit(rs[i+1])):
+			j := i
+			for j < len(rs) && (unicode.IsDigit(rs[j]) || rs[j] == '.') {
+				j++
+			}
+			v, err := strconv.ParseFloat(string(rs[i:j]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: posição %d: número inválido %q", ErrAlertaExpressaoInvalida, i+1, string(rs[i:j]))
+			}
+			toks = append(toks, exprToken{tipo: exprTokNumero, texto: string(rs[i:j]), num: v, pos: i + 1})
+			i = j
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(rs) && rs[j] != c {
+				j++
+			}
+			if j >= len(rs) {
+				return nil, fmt.Errorf("%w: posição %d: texto sem fecho", ErrAlertaExpressaoInvalida, i+1)
+			}
+			toks = append(toks, exprToken{tipo: exprTokTexto, texto: string(rs[i+1 : j]), pos: i + 1})
+			i = j + 1
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(rs) && (unicode.IsLetter(rs[j]) || unicode.IsDigit(rs[j]) || rs[j] == '_') {
+				j++
+			}
+			toks = append(toks, exprToken{tipo: exprTokIdent, texto: strings.ToLower(string(rs[i:j])), pos: i + 1})
+			i = j
+		default:
+			op := ""
+			for _, cand := range exprOperadores {
+				if strings.HasPrefix(string(rs[i:min(i+2, len(rs))]), cand) {
+					op = cand
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("%w: posição %d: caractere inesperado %q", ErrAlertaExpressaoInvalida, i+1, string(c))
+			}
+			norm := op
+			if n, ok := exprOpNormalizado[op]; ok {
+				norm = n
+			}
+			toks = append(toks, exprToken{tipo: exprTokOp, texto: norm, pos: i + 1})
+			i += len([]rune(op))
+		}
+	}
+	return append(toks, exprToken{tipo: exprTokFim, pos: len(rs) + 1}), nil
+}
+
+// Palavras-chave aceites em inglês e português.
+var exprPalavrasChave = map[string]string{
+	"and": "and", "e": "and",
+	"or": "or", "ou": "or",
+	"not": "not", "nao": "not", "não": "not",
+	"in":   "in",
+	"true": "true", "verdadeiro": "true",
+	"false": "false", "falso": "false",
+	"null": "null", "nulo": "null",
+}
+
+type exprParser struct {
+	toks []exprToken
+	i    int
+}
+
+func (p *exprParser) atual() exprToken { return p.toks[p.i] }
+
+func (p *exprParser) avancar() exprToken {
+	t := p.toks[p.i]
+	if t.tipo != exprTokFim {
+		p.i++
+	}
+	return t
+}
+
+// eh indica se o token atual é o operador ou a palavra-chave (normalizada) indicada.
+func (p *exprParser) eh(op string) bool {
+	t := p.atual()
+	switch t.tipo {
+	case exprTokOp:
+		return t.texto == op
+	case exprTokIdent:
+		return exprPalavrasChave[t.texto] == op
+	default:
+		return false
+	}
+}
+
+func (p *exprParser) erro(t exprToken, format string, args ...any) error {
+	if t.tipo == exprTokFim {
+		return fmt.Errorf("%w: fim inesperado da expressão", ErrAlertaExpressaoInvalida)
+	}
+	return fmt.Errorf("%w: posição %d: %s", ErrAlertaExpressaoInvalida, t.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *exprParser) esperar(op string) error {
+	if !p.eh(op) {
+		return p.erro(p.atual(), "esperado %q", op)
+	}
+	p.avancar()
+	return nil
+}
+
+func (p *exprParser) ou() (exprNo, error) {
+	return p.logico("or", p.e)
+}
+
+func (p *exprParser) e() (exprNo, error) {
+	return p.logico("and", p.nao)
+}
+
+func (p *exprParser) logico(op string, proximo func() (exprNo, error)) (exprNo, error) {
+	l, err := proximo()
+	if err != nil {
+		return nil, err
+	}
+	for p.eh(op) {
+		t := p.avancar()
+		r, err := proximo()
+		if err != nil {
+			return nil, err
+		}
+		if l.tipo() != exprBooleano || r.tipo() != exprBooleano {
+			return nil, p.erro(t, "%q exige operandos verdadeiro/falso", op)
+		}
+		l = exprBinario{op: op, l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *exprParser) nao() (exprNo, error) {
+	if p.eh("not") {
+		t := p.avancar()
+		x, err := p.nao()
+		if err != nil {
+			return nil, err
+		}
+		if x.tipo() != exprBooleano {
+			return nil, p.erro(t, "\"not\" exige operando verdadeiro/falso")
+		}
+		return exprUnario{op: "not", x: x}, nil
+	}
+	return p.cmp()
+}
+
+func (p *exprParser) cmp() (exprNo, error) {
+	l, err := p.soma()
+	if err != nil {
+		return nil, err
+	}
+	negado := false
+	if p.eh("not") && p.i+1 < len(p.toks) && exprPalavrasChave[p.toks[p.i+1].texto] == "in" && p.toks[p.i+1].tipo == exprTokIdent {
+		p.avancar()
+		negado = true
+	}
+	if p.eh("in") {
+		return p.em(l, negado)
+	}
+	t := p.atual()
+	if t.tipo != exprTokOp {
+		return l, nil
+	}
+	switch t.texto {
+	case "=", "!=", "<", "<=", ">", ">=":
+	default:
+		return l, nil
+	}
+	p.avancar()
+	r, err := p.soma()
+	if err != nil {
+		return nil, err
+	}
+	lt, rt := l.tipo(), r.tipo()
+	if t.texto == "=" || t.texto == "!=" {
+		if lt != exprNulo && rt != exprNulo && lt != rt {
+			return nil, p.erro(t, "não é possível comparar %s com %s", lt, rt)
+		}
+	} else if lt != exprNumero || rt != exprNumero {
+		return nil, p.erro(t, "%q exige números", t.texto)
+	}
+	return exprBinario{op: t.texto, l: l, r: r}, nil
+}
+
+func (p *exprParser) em(x exprNo, negado bool) (exprNo, error) {
+	t := p.avancar()
+	if err := p.esperar("["); err != nil {
+		return nil, err
+	}
+	var lista []exprValor
+	for {
+		item, err := p.primario()
+		if err != nil {
+			return nil, err
+		}
+		lit, ok := item.(exprLiteral)
+		if !ok || lit.v.tipo == exprNulo {
+			return nil, p.erro(t, "a lista de \"in\" só aceita números ou textos")
+		}
+		if lit.v.tipo != x.tipo() {
+			return nil, p.erro(t, "não é possível comparar %s com %s", x.tipo(), lit.v.tipo)
+		}
+		lista = append(lista, lit.v)
+		if !p.eh(",") {
+			break
+		}
+		p.avancar()
+	}
+	if err := p.esperar("]"); err != nil {
+		return nil, err
+	}
+	return exprEm{x: x, lista: lista, negado: negado}, nil
+}
+
+func (p *exprParser) soma() (exprNo, error) {
+	return p.aritmetico([]string{"+", "-"}, p.termo)
+}
+
+func (p *exprParser) termo() (exprNo, error) {
+	return p.aritmetico([]string{"*", "/"}, p.unario)
+}
+
+func (p *exprParser) aritmetico(ops []string, proximo func() (exprNo, error)) (exprNo, error) {
+	l, err := proximo()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.atual()
+		if t.tipo != exprTokOp || (t.texto != ops[0] && t.texto != ops[1]) {
+			return l, nil
+		}
+		p.avancar()
+		r, err := proximo()
+		if err != nil {
+			return nil, err
+		}
+		if l.tipo() != exprNumero || r.tipo() != exprNumero {
+			return nil, p.erro(t, "%q exige números", t.texto)
+		}
+		l = exprBinario{op: t.texto, l: l, r: r}
+	}
+}
+
+func (p *exprParser) unario() (exprNo, error) {
+	if p.eh("-") {
+		t := p.avancar()
+		x, err := p.unario()
+		if err != nil {
+			return nil, err
+		}
+		if x.tipo() != exprNumero {
+			return nil, p.erro(t, "\"-\" exige número")
+		}
+		if lit, ok := x.(exprLiteral); ok {
+			return exprLiteral{v: exprNum(-lit.v.num)}, nil
+		}
+		return exprUnario{op: "-", x: x}, nil
+	}
+	return p.primario()
+}
+
+func (p *exprParser) primario() (exprNo, error) {
+	t := p.avancar()
+	switch t.tipo {
+	case exprTokNumero:
+		return exprLiteral{v: exprNum(t.num)}, nil
+	case exprTokTexto:
+		return exprLiteral{v: exprTxt(t.texto)}, nil
+	case exprTokIdent:
+		switch exprPalavrasChave[t.texto] {
+		case "true":
+			return exprLiteral{v: exprBool(true)}, nil
+		case "false":
+			return exprLiteral{v: exprBool(false)}, nil
+		case "null":
+			return exprLiteral{v: exprValorNulo}, nil
+		case "":
+			v, ok := alertaExprVariaveis[t.texto]
+			if !ok {
+				return nil, p.erro(t, "variável desconhecida %q", t.texto)
+			}
+			return exprVariavel{nome: t.texto, t: v.tipo}, nil
+		}
+	case exprTokOp:
+		if t.texto == "(" {
+			x, err := p.ou()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.esperar(")"); err != nil {
+				return nil, err
+			}
+			return x, nil
+		}
+	}
+	return nil, p.erro(t, "símbolo inesperado %q", t.texto)
+}
+
+// Catálogo de variáveis ------------------------------------------------------------------------------
+
+type alertaExprVariavel struct {
+	tipo      exprTipo
+	descricao string
+	valor     func(m *repository.AlertaCustomMetricas, ref time.Time) exprValor
+}
+
+func exprDiasDesde(d *time.Time, ref time.Time) exprValor {
+	if d == nil {
+		return exprValorNulo
+	}
+	return exprNum(float64(diasCivis(*d, ref)))
+}
+
+// diasCivis conta dias de calendário entre as datas (sem considerar horas ou fuso).
+func diasCivis(de, ate time.Time) int {
+	a := time.Date(de.Year(), de.Month(), de.Day(), 0, 0, 0, 0, time.UTC)
+	b := time.Date(ate.Year(), ate.Month(), ate.Day(), 0, 0, 0, 0, time.UTC)
+	return int(math.Round(b.Sub(a).Hours() / 24))
+}
+
+// mesesCompletos conta meses completos entre as datas.
+func mesesCompletos(de, ate time.Time) int {
+	m := (ate.Year()-de.Year())*12 + int(ate.Month()) - int(de.Month())
+	if ate.Day() < de.Day() {
+		m--
+	}
+	return m
+}
+
+var alertaExprVariaveis = map[string]alertaExprVariavel{
+	"sexo": {exprTexto, "Sexo (M ou F)", func(m *repository.AlertaCustomMetricas, _ time.Time) exprValor {
+		return exprTxtPtr(m.Sexo)
+	}},
+	"categoria": {exprTexto, "Categoria atual (BEZERRA, NOVILHA, MATRIZ, BEZERRO, TOURO, BOI)", func(m *repository.AlertaCustomMetricas, _ time.Time) exprValor {
+		return exprTxtPtr(m.Categoria)
+	}},
+	"raca": {exprTexto, "Raça", func(m *repository.AlertaCustomMetricas, _ time.Time) exprValor {
+		return exprTxtPtr(m.Raca)
+	}},
+	"status_reprodutivo": {exprTexto, "Status reprodutivo atual (VAZIA, SERVIDA, PRENHE, PARIDA, SECA)", func(m *repository.AlertaCustomMetricas, _ time.Time) exprValor {
+		return exprTxtPtr(m.StatusReprodutivo)
+	}},
+	"status_saude": {exprTexto, "Status de saúde atual (SAUDAVEL, DOENTE, EM_TRATAMENTO)", func(m *repository.AlertaCustomMetricas, _ time.Time) exprValor {
+		return exprTxtPtr(m.StatusSaude)
+	}},
+	"lote": {exprTexto, "Nome do lote atual", func(m *repository.AlertaCustomMetricas, _ time.Time) exprValor {
+		return exprTxtPtr(m.LoteNome)
+	}},
+	"idade_meses": {exprNumero, "Idade em meses completos (null sem data de nascimento)", func(m *repository.AlertaCustomMetricas, ref time.Time) exprValor {
+		if m.DataNascimento == nil {
+			return exprValorNulo
+		}
+		return exprNum(float64(mesesCompletos(*m.DataNascimento, ref)))
+	}},
+	"idade_dias": {exprNumero, "Idade em dias (null sem data de nascimento)", func(m *repository.AlertaCustomMetricas, ref time.Time) exprValor {
+		return exprDiasDesde(m.DataNascimento, ref)
+	}},
+	"del": {exprNumero, "Dias em lactação (DEL/DIM) da lactação em aberto; null fora de lactação", func(m *repository.AlertaCustomMetricas, ref time.Time) exprValor {
+		return exprDiasDesde(m.InicioLactacao, ref)
+	}},
+	"em_lactacao": {exprBooleano, "Tem lactação em aberto", func(m *repository.AlertaCustomMetricas, _ time.Time) exprValor {
+		return exprBool(m.InicioLactacao != nil)
+	}},
+	"prenhe": {exprBooleano, "Tem gestação confirmada em curso", func(m *repository.AlertaCustomMetricas, _ time.Time) exprValor {
+		return exprBool(m.Prenhe)
+	}},
+	"em_restricao_leite": {exprBooleano, "Tem restrição de leite aguardando laboratório", func(m *repository.AlertaCustomMetricas, _ time.Time) exprValor {
+		return exprBool(m.EmRestricaoLeite)
+	}},
+	"numero_partos": {exprNumero, "Total de partos registados", func(m *repository.AlertaCustomMetricas, _ time.Time) exprValor {
+		return exprNum(float64(m.NumeroPartos))
+	}},
+	"dias_desde_ultimo_parto": {exprNumero, "Dias desde o último parto (null sem partos)", func(m *repository.AlertaCustomMetricas, ref time.Time) exprValor {
+		return exprDiasDesde(m.UltimoParto, ref)
+	}},
+	"dias_desde_ultima_cobertura": {exprNumero, "Dias desde a última cobertura (null sem coberturas)", func(m *repository.AlertaCustomMetricas, ref time.Time) exprValor {
+		return exprDiasDesde(m.UltimaCobertura, ref)
+	}},
+	"dias_desde_ultimo_cio": {exprNumero, "Dias desde o último cio detectado (null sem cios)", func(m *repository.AlertaCustomMetricas, ref time.Time) exprValor {
+		return exprDiasDesde(m.UltimoCio, ref)
+	}},
+	"producao_7d": {exprNumero, "Litros produzidos nos últimos 7 dias (inclui hoje)", func(m *repository.AlertaCustomMetricas, _ time.Time) exprValor {
+		return exprNum(m.Producao7d)
+	}},
+	"producao_7d_anterior": {exprNumero, "Litros produzidos nos 7 dias anteriores a esses", func(m *repository.AlertaCustomMetricas, _ time.Time) exprValor {
+		return exprNum(m.Producao7dAnterior)
+	}},
+	"variacao_producao_7d_pct": {exprNumero, "Variação % da produção semana contra semana (null sem produção na semana anterior)", func(m *repository.AlertaCustomMetricas, _ time.Time) exprValor {
+		if m.Producao7dAnterior <= 0 {
+			return exprValorNulo
+		}
+		return exprNum((m.Producao7d - m.Producao7dAnterior) / m.Producao7dAnterior * 100)
+	}},
+}
+
+// AlertaExpressaoVariaveis devolve o catálogo de variáveis por nome.
+func AlertaExpressaoVariaveis() []models.AlertaExpressaoVariavel {
+	out := make([]models.AlertaExpressaoVariavel, 0, len(alertaExprVariaveis))
+	for nome, v := range alertaExprVariaveis {
+		out = append(out, models.AlertaExpressaoVariavel{Nome: nome, Tipo: v.tipo.String(), Descricao: v.descricao})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Nome < out[j].Nome })
+	return out
+}
+
+// alertaExprAmbiente liga as variáveis do catálogo às métricas de um animal em ref.
+func alertaExprAmbiente(m *repository.AlertaCustomMetricas, ref time.Time) exprAmbiente {
+	return func(nome string) exprValor {
+		v, ok := alertaExprVariaveis[nome]
+		if !ok {
+			return exprValorNulo
+		}
+		return v.valor(m, ref)
+	}
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ceialmilk/api/internal/repository"
+)
+
+func TestCompilarAlertaExpressao_Erros(t *testing.T) {
+	casos := []string{
+		"",
+		"del >",
+		"del > 150 and",
+		"altura > 3",
+		"categoria > 2",
+		"del + \"a\" > 1",
+		"del",
+		"prenhe and del",
+		"categoria in [\"NOVILHA\", 3]",
+		"(del > 1",
+		"del > 1 # x",
+		"categoria = 'NOVILHA",
+	}
+	for _, src := range casos {
+		if _, err := CompilarAlertaExpressao(src); !errors.Is(err, ErrAlertaExpressaoInvalida) {
+			t.Fatalf("%q: esperado ErrAlertaExpressaoInvalida, got %v", src, err)
+		}
+	}
+}
+
+func TestAlertaExpressao_Avaliar(t *testing.T) {
+	ref := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	dia := func(y int, m time.Month, d int) *time.Time { t := time.Date(y, m, d, 0, 0, 0, 0, time.UTC); return &t }
+	novilha, matriz := "NOVILHA", "MATRIZ"
+
+	vacaDel180 := &repository.AlertaCustomMetricas{Categoria: &matriz, InicioLactacao: dia(2025, 1, 1), UltimoParto: dia(2025, 1, 1), NumeroPartos: 2}
+	vacaPrenhe := &repository.AlertaCustomMetricas{Categoria: &matriz, InicioLactacao: dia(2025, 1, 1), Prenhe: true}
+	novilhaSemCobertura := &repository.AlertaCustomMetricas{Categoria: &novilha, DataNascimento: dia(2024, 2, 15)}
+	novilhaCoberta := &repository.AlertaCustomMetricas{Categoria: &novilha, DataNascimento: dia(2024, 2, 15), UltimaCobertura: dia(2025, 6, 1)}
+	quedaProducao := &repository.AlertaCustomMetricas{Categoria: &matriz, Producao7d: 70, Producao7dAnterior: 100}
+	semHistorico := &repository.AlertaCustomMetricas{}
+
+	casos := []struct {
+		expr string
+		m    *repository.AlertaCustomMetricas
+		want bool
+	}{
+		{"del > 150 and not prenhe", vacaDel180, true},
+		{"del > 150 and not prenhe", vacaPrenhe, false},
+		{"del > 150 and not prenhe", semHistorico, false},
+		{"categoria = \"novilha\" e idade_meses > 15 e dias_desde_ultima_cobertura = null", novilhaSemCobertura, true},
+		{"categoria = \"novilha\" e idade_meses > 15 e dias_desde_ultima_cobertura = null", novilhaCoberta, false},
+		{"variacao_producao_7d_pct <= -20", quedaProducao, true},
+		{"variacao_producao_7d_pct <= -20", semHistorico, false},
+		{"producao_7d < producao_7d_anterior * 0.8", quedaProducao, true},
+		{"categoria in ['MATRIZ', 'NOVILHA'] && numero_partos >= 2", vacaDel180, true},
+		{"categoria not in ['MATRIZ']", semHistorico, false},
+		{"!(del > 150) || prenhe", semHistorico, true},
+		{"idade_dias / 0 = null", novilhaSemCobertura, true},
+		{"em_lactacao = verdadeiro and dias_desde_ultimo_parto >= 180", vacaDel180, true},
+	}
+	for _, tc := range casos {
+		expr, err := CompilarAlertaExpressao(tc.expr)
+		if err != nil {
+			t.Fatalf("%q: %v", tc.expr, err)
+		}
+		if got := expr.Avaliar(alertaExprAmbiente(tc.m, ref)); got != tc.want {
+			t.Fatalf("%q sobre %+v: esperado %v, got %v", tc.expr, tc.m, tc.want, got)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/ceialmilk/api/internal/models"
@@ -13,15 +14,9 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
-const (
-	SistemaAlertasEmail          = "sistema@interno.ceialmilk"
-	diasTratamentoVencido        = 14
-	diasPartoPrevistoJanela      = 14
-	diasRestricaoLeiteAlerta      = 7
-	diasGestacaoSemSecagemAlerta = 250
-	diasVacinaVencidaAlerta      = 7
-	diasVacinaReforcoAlerta      = 7
-)
+// SistemaAlertasEmail é o utilizador técnico gravado em created_by dos alertas automáticos.
+// Janelas e severidades de cada regra: models.AlertaRegrasPadrao + configuração da fazenda (BR-ALERTA-019).
+const SistemaAlertasEmail = "sistema@interno.ceialmilk"
 
 type GerarAlertasResultado struct {
 	FazendasProcessadas int `json:"fazendas_processadas"`
@@ -37,11 +32,26 @@ type alertaGeracaoStore interface {
 	ResolveOpenByFazendaTipoAnimal(ctx context.Context, fazendaID int64, tipo string, animalID int64) error
 }
 
+type alertaRegrasProvider interface {
+	RegrasEfetivas(ctx context.Context, fazendaID int64) (map[string]models.AlertaRegra, error)
+}
+
+type alertaRegrasCustomProvider interface {
+	AvaliarAtivas(ctx context.Context, fazendaID int64, refDate time.Time) ([]AlertaRegraCustomResultado, error)
+}
+
+type alertasGeracaoEstadoStore interface {
+	Get(ctx context.Context, fazendaID int64) (*repository.AlertasGeracaoEstado, error)
+	Upsert(ctx context.Context, fazendaID int64, chaves []string, execucao time.Time) error
+	UpsertCustomChaves(ctx context.Context, fazendaID int64, chaves []string) error
+}
+
 type hormonioPendentesForAlertaStore interface {
 	ListPendentesByFazendaID(ctx context.Context, fazendaID int64, refDate time.Time) ([]*models.HormonioLactacaoPendente, error)
 }
 
 type AlertaGeracaoService struct {
+	publicaEventos
 	alertaRepo         alertaGeracaoStore
 	fazendaRepo        *repository.FazendaRepository
 	animalSaudeRepo    *repository.AnimalSaudeRepository
@@ -51,9 +61,11 @@ type AlertaGeracaoService struct {
 	restricaoRepo    *repository.RestricaoLeiteRepository
 	cioRepo          *repository.CioRepository
 	conformidadeSvc  *ConformidadeService
-	estadoRepo       *repository.AlertasGeracaoEstadoRepository
+	estadoRepo       alertasGeracaoEstadoStore
 	usuarioRepo      *repository.UsuarioRepository
 	pushSvc          *PushNotificationService
+	regrasSvc        alertaRegrasProvider
+	customSvc        alertaRegrasCustomProvider
 	sistemaUserID    int64
 	tz               *time.Location
 }
@@ -109,6 +121,17 @@ func (s *AlertaGeracaoService) SetPushNotificationService(pushSvc *PushNotificat
 	s.pushSvc = pushSvc
 }
 
+// SetAlertaRegraService faz a geração ler a configuração de regras por fazenda (BR-ALERTA-019);
+// sem ela todas as regras correm com os valores padrão.
+func (s *AlertaGeracaoService) SetAlertaRegraService(regrasSvc alertaRegrasProvider) {
+	s.regrasSvc = regrasSvc
+}
+
+// SetAlertaRegraCustomService habilita as regras personalizadas da fazenda (BR-ALERTA-020).
+func (s *AlertaGeracaoService) SetAlertaRegraCustomService(customSvc alertaRegrasCustomProvider) {
+	s.customSvc = customSvc
+}
+
 // SetAnimalVacinaRepo habilita as regras 7 e 8 (BR-ALERTA-016/017).
 func (s *AlertaGeracaoService) SetAnimalVacinaRepo(repo *repository.AnimalVacinaRepository) {
 	s.animalVacinaRepo = repo
@@ -142,46 +165,78 @@ func (s *AlertaGeracaoService) GerarAlertasDiarios(ctx context.Context, refDate
 }
 
 func (s *AlertaGeracaoService) gerarPorFazenda(ctx context.Context, fazendaID int64, refDate time.Time) (criados, ignorados, erros int) {
-	type regraFn func(context.Context, int64, time.Time) (int, int, error)
-	regras := []regraFn{
-		s.regraTratamentoVencido,
-		s.regraPartoPrevisto,
-		s.regraRestricaoLeiteAtiva,
-		s.regraNaoConformidade,
-		s.regraGestacaoSemSecagem,
-		s.regraCioDetectado,
-		s.regraVacinaVencida,
-		s.regraVacinaReforcoVencido,
-		s.regraHormonioLactacaoPendente,
-	}
-	for _, fn := range regras {
-		c, ig, err := fn(ctx, fazendaID, refDate)
+	type regraFn func(context.Context, int64, time.Time, models.AlertaRegra) (int, int, error)
+	regras := map[string]regraFn{
+		models.AlertaTipoTratamentoVencido:        s.regraTratamentoVencido,
+		models.AlertaTipoPartoPrevisto:            s.regraPartoPrevisto,
+		models.AlertaTipoRestricaoLeiteAtiva:      s.regraRestricaoLeiteAtiva,
+		models.AlertaTipoNaoConformidade:          s.regraNaoConformidade,
+		models.AlertaTipoGestacaoSemSecagem:       s.regraGestacaoSemSecagem,
+		models.AlertaTipoCioDetectado:             s.regraCioDetectado,
+		models.AlertaTipoVacinaVencida:            s.regraVacinaVencida,
+		models.AlertaTipoVacinaReforcoVencido:     s.regraVacinaReforcoVencido,
+		models.AlertaTipoHormonioLactacaoPendente: s.regraHormonioLactacaoPendente,
+	}
+	config, err := s.regrasDaFazenda(ctx, fazendaID)
+	if err != nil {
+		// Sem a configuração não se sabe que regras estão desligadas: não gera nada para a fazenda.
+		slog.Warn("alerta geracao: configuração de regras indisponível",
+			"fazenda_id", fazendaID,
+			"error", err,
+		)
+		return 0, 0, 1
+	}
+	for _, padrao := range models.AlertaRegrasPadrao() {
+		fn, ok := regras[padrao.Tipo]
+		regra := config[padrao.Tipo]
+		if !ok || !regra.Ativo {
+			continue
+		}
+		c, ig, err := fn(ctx, fazendaID, refDate, regra)
 		criados += c
 		ignorados += ig
 		if err != nil {
 			erros++
 			slog.Warn("alerta geracao: regra falhou",
 				"fazenda_id", fazendaID,
+				"tipo", padrao.Tipo,
 				"error", err,
 			)
 		}
 	}
+	if s.customSvc != nil {
+		c, ig, e := s.gerarCustom(ctx, fazendaID, refDate)
+		criados += c
+		ignorados += ig
+		erros += e
+	}
 	return criados, ignorados, erros
 }
 
-func (s *AlertaGeracaoService) regraTratamentoVencido(ctx context.Context, fazendaID int64, refDate time.Time) (int, int, error) {
-	limite := refDate.AddDate(0, 0, -diasTratamentoVencido)
+func (s *AlertaGeracaoService) regrasDaFazenda(ctx context.Context, fazendaID int64) (map[string]models.AlertaRegra, error) {
+	if s.regrasSvc != nil {
+		return s.regrasSvc.RegrasEfetivas(ctx, fazendaID)
+	}
+	out := make(map[string]models.AlertaRegra)
+	for _, p := range models.AlertaRegrasPadrao() {
+		out[p.Tipo] = models.ResolverAlertaRegra(p, nil)
+	}
+	return out, nil
+}
+
+func (s *AlertaGeracaoService) regraTratamentoVencido(ctx context.Context, fazendaID int64, refDate time.Time, regra models.AlertaRegra) (int, int, error) {
+	limite := refDate.AddDate(0, 0, -regra.DiasEfetivos())
 	itens, err := s.animalSaudeRepo.ListTratamentosSemFimVencidosByFazendaID(ctx, fazendaID, limite)
 	if err != nil {
 		return 0, 0, err
 	}
-	return s.criarAlertasAnimais(ctx, fazendaID, models.AlertaTipoTratamentoVencido, itens, func(ident string) string {
+	return s.criarAlertasAnimais(ctx, fazendaID, regra, itens, func(ident string) string {
 		return fmt.Sprintf("Tratamento vencido — Animal %s", ident)
 	}, nil)
 }
 
-func (s *AlertaGeracaoService) regraPartoPrevisto(ctx context.Context, fazendaID int64, refDate time.Time) (int, int, error) {
-	ate := refDate.AddDate(0, 0, diasPartoPrevistoJanela)
+func (s *AlertaGeracaoService) regraPartoPrevisto(ctx context.Context, fazendaID int64, refDate time.Time, regra models.AlertaRegra) (int, int, error) {
+	ate := refDate.AddDate(0, 0, regra.DiasEfetivos())
 	itens, err := s.gestacaoRepo.ListPartosPrevistosNaJanelaByFazendaID(ctx, fazendaID, refDate, ate)
 	if err != nil {
 		return 0, 0, err
@@ -194,7 +249,7 @@ func (s *AlertaGeracaoService) regraPartoPrevisto(ctx context.Context, fazendaID
 			t := truncateToDateUTC(*item.DataPrevistaParto)
 			dp = &t
 		}
-		c, ig, err := s.tryCreateAlerta(ctx, fazendaID, models.AlertaTipoPartoPrevisto, &item.AnimalID, titulo, nil, dp)
+		c, ig, err := s.tryCreateAlerta(ctx, fazendaID, regra, &item.AnimalID, titulo, nil, dp)
 		if err != nil {
 			return criados, ignorados, err
 		}
@@ -204,70 +259,70 @@ func (s *AlertaGeracaoService) regraPartoPrevisto(ctx context.Context, fazendaID
 	return criados, ignorados, nil
 }
 
-func (s *AlertaGeracaoService) regraRestricaoLeiteAtiva(ctx context.Context, fazendaID int64, refDate time.Time) (int, int, error) {
-	limite := refDate.AddDate(0, 0, -diasRestricaoLeiteAlerta)
+func (s *AlertaGeracaoService) regraRestricaoLeiteAtiva(ctx context.Context, fazendaID int64, refDate time.Time, regra models.AlertaRegra) (int, int, error) {
+	limite := refDate.AddDate(0, 0, -regra.DiasEfetivos())
 	itens, err := s.restricaoRepo.ListAtivasAguardandoAntigasByFazendaID(ctx, fazendaID, limite)
 	if err != nil {
 		return 0, 0, err
 	}
-	return s.criarAlertasAnimais(ctx, fazendaID, models.AlertaTipoRestricaoLeiteAtiva, itens, func(ident string) string {
-		return fmt.Sprintf("Restrição de leite há 7+ dias — Animal %s", ident)
+	return s.criarAlertasAnimais(ctx, fazendaID, regra, itens, func(ident string) string {
+		return fmt.Sprintf("Restrição de leite há %d+ dias — Animal %s", regra.DiasEfetivos(), ident)
 	}, nil)
 }
 
-func (s *AlertaGeracaoService) regraGestacaoSemSecagem(ctx context.Context, fazendaID int64, refDate time.Time) (int, int, error) {
-	limite := refDate.AddDate(0, 0, -diasGestacaoSemSecagemAlerta)
+func (s *AlertaGeracaoService) regraGestacaoSemSecagem(ctx context.Context, fazendaID int64, refDate time.Time, regra models.AlertaRegra) (int, int, error) {
+	limite := refDate.AddDate(0, 0, -regra.DiasEfetivos())
 	itens, err := s.gestacaoRepo.ListConfirmadasSemSecagemByFazendaID(ctx, fazendaID, limite)
 	if err != nil {
 		return 0, 0, err
 	}
-	return s.criarAlertasAnimais(ctx, fazendaID, models.AlertaTipoGestacaoSemSecagem, itens, func(ident string) string {
+	return s.criarAlertasAnimais(ctx, fazendaID, regra, itens, func(ident string) string {
 		return fmt.Sprintf("Gestação sem secagem — Animal %s", ident)
 	}, nil)
 }
 
-func (s *AlertaGeracaoService) regraCioDetectado(ctx context.Context, fazendaID int64, refDate time.Time) (int, int, error) {
+func (s *AlertaGeracaoService) regraCioDetectado(ctx context.Context, fazendaID int64, refDate time.Time, regra models.AlertaRegra) (int, int, error) {
 	itens, err := s.cioRepo.ListDetectadosNaDataByFazendaID(ctx, fazendaID, refDate)
 	if err != nil {
 		return 0, 0, err
 	}
-	return s.criarAlertasAnimais(ctx, fazendaID, models.AlertaTipoCioDetectado, itens, func(ident string) string {
+	return s.criarAlertasAnimais(ctx, fazendaID, regra, itens, func(ident string) string {
 		return fmt.Sprintf("Cio detectado — Animal %s", ident)
 	}, nil)
 }
 
-// regraVacinaVencida (regra 7 — BR-ALERTA-016): vacina prevista com data_prevista há mais de 7 dias.
-func (s *AlertaGeracaoService) regraVacinaVencida(ctx context.Context, fazendaID int64, refDate time.Time) (int, int, error) {
+// regraVacinaVencida (regra 7 — BR-ALERTA-016): vacina prevista com data_prevista há mais de N dias (padrão 7).
+func (s *AlertaGeracaoService) regraVacinaVencida(ctx context.Context, fazendaID int64, refDate time.Time, regra models.AlertaRegra) (int, int, error) {
 	if s.animalVacinaRepo == nil {
 		return 0, 0, nil
 	}
-	limite := refDate.AddDate(0, 0, -diasVacinaVencidaAlerta)
+	limite := refDate.AddDate(0, 0, -regra.DiasEfetivos())
 	itens, err := s.animalVacinaRepo.ListPrevistasVencidasByFazendaID(ctx, fazendaID, limite)
 	if err != nil {
 		return 0, 0, err
 	}
-	return s.criarAlertasAnimais(ctx, fazendaID, models.AlertaTipoVacinaVencida, itens, func(ident string) string {
+	return s.criarAlertasAnimais(ctx, fazendaID, regra, itens, func(ident string) string {
 		return fmt.Sprintf("Vacina atrasada — Animal %s", ident)
 	}, nil)
 }
 
-// regraVacinaReforcoVencido (regra 8 — BR-ALERTA-017): reforço vencido há mais de 7 dias sem nova dose.
-func (s *AlertaGeracaoService) regraVacinaReforcoVencido(ctx context.Context, fazendaID int64, refDate time.Time) (int, int, error) {
+// regraVacinaReforcoVencido (regra 8 — BR-ALERTA-017): reforço vencido há mais de N dias (padrão 7) sem nova dose.
+func (s *AlertaGeracaoService) regraVacinaReforcoVencido(ctx context.Context, fazendaID int64, refDate time.Time, regra models.AlertaRegra) (int, int, error) {
 	if s.animalVacinaRepo == nil {
 		return 0, 0, nil
 	}
-	limite := refDate.AddDate(0, 0, -diasVacinaReforcoAlerta)
+	limite := refDate.AddDate(0, 0, -regra.DiasEfetivos())
 	itens, err := s.animalVacinaRepo.ListReforcosVencidosByFazendaID(ctx, fazendaID, limite)
 	if err != nil {
 		return 0, 0, err
 	}
-	return s.criarAlertasAnimais(ctx, fazendaID, models.AlertaTipoVacinaReforcoVencido, itens, func(ident string) string {
+	return s.criarAlertasAnimais(ctx, fazendaID, regra, itens, func(ident string) string {
 		return fmt.Sprintf("Reforço de vacina vencido — Animal %s", ident)
 	}, nil)
 }
 
 // regraHormonioLactacaoPendente (regra 9 — BR-ALERTA-018): 1ª dose ou manutenção com data_proxima ≤ hoje.
-func (s *AlertaGeracaoService) regraHormonioLactacaoPendente(ctx context.Context, fazendaID int64, refDate time.Time) (int, int, error) {
+func (s *AlertaGeracaoService) regraHormonioLactacaoPendente(ctx context.Context, fazendaID int64, refDate time.Time, regra models.AlertaRegra) (int, int, error) {
 	if s.animalHormonioRepo == nil {
 		return 0, 0, nil
 	}
@@ -285,12 +340,12 @@ func (s *AlertaGeracaoService) regraHormonioLactacaoPendente(ctx context.Context
 			Identificacao: p.AnimalIdentificacao,
 		})
 	}
-	return s.criarAlertasAnimais(ctx, fazendaID, models.AlertaTipoHormonioLactacaoPendente, itens, func(ident string) string {
+	return s.criarAlertasAnimais(ctx, fazendaID, regra, itens, func(ident string) string {
 		return fmt.Sprintf("Hormônio de lactação pendente — Animal %s", ident)
 	}, nil)
 }
 
-func (s *AlertaGeracaoService) regraNaoConformidade(ctx context.Context, fazendaID int64, refDate time.Time) (int, int, error) {
+func (s *AlertaGeracaoService) regraNaoConformidade(ctx context.Context, fazendaID int64, refDate time.Time, regra models.AlertaRegra) (int, int, error) {
 	anomalias, err := s.conformidadeSvc.ListByFazenda(ctx, fazendaID)
 	if err != nil {
 		return 0, 0, err
@@ -321,7 +376,7 @@ func (s *AlertaGeracaoService) regraNaoConformidade(ctx context.Context, fazenda
 		}
 		titulo := fmt.Sprintf("Não conformidade detectada — %s", a.Descricao)
 		desc := fmt.Sprintf("%s (%s)", a.Descricao, a.Codigo)
-		c, ig, err := s.tryCreateAlerta(ctx, fazendaID, models.AlertaTipoNaoConformidade, &a.AnimalID, titulo, &desc, nil)
+		c, ig, err := s.tryCreateAlerta(ctx, fazendaID, regra, &a.AnimalID, titulo, &desc, nil)
 		if err != nil {
 			return criados, ignorados, err
 		}
@@ -339,10 +394,111 @@ func conformidadeChave(codigo string, animalID int64) string {
 	return fmt.Sprintf("%s:%d", codigo, animalID)
 }
 
+// gerarCustom avalia as regras personalizadas (BR-ALERTA-020). Como em NAO_CONFORMIDADE, o alerta só nasce
+// quando o animal passa a satisfazer a regra: as chaves regra:animal da execução anterior ficam em
+// alertas_geracao_estado.custom_chaves. Cada regra que falha conta um erro e mantém as chaves anteriores.
+func (s *AlertaGeracaoService) gerarCustom(ctx context.Context, fazendaID int64, refDate time.Time) (criados, ignorados, erros int) {
+	resultados, err := s.customSvc.AvaliarAtivas(ctx, fazendaID, refDate)
+	if err != nil {
+		slog.Warn("alerta geracao: regras personalizadas falharam", "fazenda_id", fazendaID, "error", err)
+		return 0, 0, 1
+	}
+	estado, err := s.estadoRepo.Get(ctx, fazendaID)
+	if err != nil {
+		slog.Warn("alerta geracao: estado das regras personalizadas indisponível", "fazenda_id", fazendaID, "error", err)
+		return 0, 0, 1
+	}
+	var anteriores []string
+	if estado != nil {
+		anteriores = estado.CustomChaves
+	}
+	if len(resultados) == 0 && len(anteriores) == 0 {
+		return 0, 0, 0
+	}
+	anterior := make(map[string]struct{}, len(anteriores))
+	for _, k := range anteriores {
+		anterior[k] = struct{}{}
+	}
+
+	atuais := make([]string, 0, len(anteriores))
+	for _, res := range resultados {
+		if res.Err != nil {
+			erros++
+			slog.Warn("alerta geracao: regra personalizada inválida",
+				"fazenda_id", fazendaID,
+				"regra_custom_id", res.Regra.ID,
+				"error", res.Err,
+			)
+			prefixo := fmt.Sprintf("%d:", res.Regra.ID)
+			for _, k := range anteriores {
+				if strings.HasPrefix(k, prefixo) {
+					atuais = append(atuais, k)
+				}
+			}
+			continue
+		}
+		regra := res.Regra.AlertaRegraEfetiva()
+		for _, a := range res.Animais {
+			k := customChave(res.Regra.ID, a.AnimalID)
+			if _, ok := anterior[k]; ok {
+				atuais = append(atuais, k)
+				continue
+			}
+			c, ig, err := s.criarAlertaCustom(ctx, fazendaID, res.Regra, regra, a)
+			if err != nil {
+				// Sem a chave, o animal volta a ser tentado na próxima execução.
+				erros++
+				slog.Warn("alerta geracao: falha ao criar alerta personalizado",
+					"fazenda_id", fazendaID,
+					"regra_custom_id", res.Regra.ID,
+					"animal_id", a.AnimalID,
+					"error", err,
+				)
+				continue
+			}
+			atuais = append(atuais, k)
+			criados += c
+			ignorados += ig
+		}
+	}
+	if err := s.estadoRepo.UpsertCustomChaves(ctx, fazendaID, atuais); err != nil {
+		erros++
+		slog.Warn("alerta geracao: falha ao gravar estado das regras personalizadas", "fazenda_id", fazendaID, "error", err)
+	}
+	return criados, ignorados, erros
+}
+
+func customChave(regraID, animalID int64) string {
+	return fmt.Sprintf("%d:%d", regraID, animalID)
+}
+
+func (s *AlertaGeracaoService) criarAlertaCustom(
+	ctx context.Context,
+	fazendaID int64,
+	r *models.AlertaRegraCustom,
+	regra models.AlertaRegra,
+	animal models.AlertaRegraCustomAnimal,
+) (int, int, error) {
+	desc := "Expressão: " + r.Expressao
+	if r.Descricao != nil {
+		desc = *r.Descricao
+	}
+	animalID, regraID := animal.AnimalID, r.ID
+	row := &models.Alerta{
+		FazendaID:     fazendaID,
+		AnimalID:      &animalID,
+		Tipo:          models.AlertaTipoCustom,
+		Titulo:        fmt.Sprintf("%s — Animal %s", r.Nome, animal.Identificacao),
+		Descricao:     &desc,
+		RegraCustomID: &regraID,
+	}
+	return s.inserirAlertaAutomatico(ctx, fazendaID, regra, row)
+}
+
 func (s *AlertaGeracaoService) criarAlertasAnimais(
 	ctx context.Context,
 	fazendaID int64,
-	tipo string,
+	regra models.AlertaRegra,
 	itens []repository.AlertaAnimalIdentificacao,
 	tituloFn func(ident string) string,
 	dataPrevista *time.Time,
@@ -350,7 +506,7 @@ func (s *AlertaGeracaoService) criarAlertasAnimais(
 	var criados, ignorados int
 	for _, item := range itens {
 		animalID := item.AnimalID
-		c, ig, err := s.tryCreateAlerta(ctx, fazendaID, tipo, &animalID, tituloFn(item.Identificacao), nil, dataPrevista)
+		c, ig, err := s.tryCreateAlerta(ctx, fazendaID, regra, &animalID, tituloFn(item.Identificacao), nil, dataPrevista)
 		if err != nil {
 			return criados, ignorados, err
 		}
@@ -363,7 +519,7 @@ func (s *AlertaGeracaoService) criarAlertasAnimais(
 func (s *AlertaGeracaoService) tryCreateAlerta(
 	ctx context.Context,
 	fazendaID int64,
-	tipo string,
+	regra models.AlertaRegra,
 	animalID *int64,
 	titulo string,
 	descricao *string,
@@ -372,6 +528,7 @@ func (s *AlertaGeracaoService) tryCreateAlerta(
 	if animalID == nil {
 		return 0, 0, errors.New("animal_id obrigatório para alerta automático")
 	}
+	tipo := regra.Tipo
 	open, err := s.alertaRepo.ExistsOpenByFazendaTipoAnimal(ctx, fazendaID, tipo, *animalID)
 	if err != nil {
 		return 0, 0, err
@@ -379,21 +536,26 @@ func (s *AlertaGeracaoService) tryCreateAlerta(
 	if open {
 		return 0, 1, nil
 	}
-	severidade, ok := models.SeveridadePadraoPorTipo(tipo)
-	if !ok {
-		return 0, 0, ErrAlertaTipoInvalido
-	}
 	row := &models.Alerta{
 		FazendaID:    fazendaID,
 		AnimalID:     animalID,
 		Tipo:         tipo,
-		Severidade:   severidade,
 		Titulo:       titulo,
 		Descricao:    descricao,
 		DataPrevista: dataPrevista,
-		Status:       models.AlertaStatusAberto,
-		CreatedBy:    s.sistemaUserID,
 	}
+	return s.inserirAlertaAutomatico(ctx, fazendaID, regra, row)
+}
+
+// inserirAlertaAutomatico grava o alerta com a severidade da regra e envia o push aos perfis da regra;
+// violação do índice único de alerta aberto conta como duplicata.
+func (s *AlertaGeracaoService) inserirAlertaAutomatico(ctx context.Context, fazendaID int64, regra models.AlertaRegra, row *models.Alerta) (criados, ignorados int, err error) {
+	if !models.IsValidAlertaSeveridade(regra.Severidade) {
+		return 0, 0, ErrAlertaSeveridadeInvalida
+	}
+	row.Severidade = regra.Severidade
+	row.Status = models.AlertaStatusAberto
+	row.CreatedBy = s.sistemaUserID
 	if err := s.alertaRepo.Create(ctx, row); err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
@@ -403,15 +565,20 @@ func (s *AlertaGeracaoService) tryCreateAlerta(
 	}
 	if s.pushSvc != nil {
 		if created, err := s.alertaRepo.GetByID(ctx, fazendaID, row.ID); err == nil && created != nil {
-			s.pushSvc.NotifyAlertaCreated(created)
+			s.pushSvc.NotifyAlertaCreatedParaPerfis(created, regra.PushPerfis)
 		}
 	}
+	s.publicarEventoAlerta(ctx, models.AuditoriaAcaoCreate, row)
 	return 1, 0, nil
 }
 
 // ResolveOpenByAnimal resolve alertas abertos do tipo informado para o animal (resolução automática).
 func (s *AlertaGeracaoService) ResolveOpenByAnimal(ctx context.Context, fazendaID, animalID int64, tipo string) error {
-	return s.alertaRepo.ResolveOpenByFazendaTipoAnimal(ctx, fazendaID, tipo, animalID)
+	if err := s.alertaRepo.ResolveOpenByFazendaTipoAnimal(ctx, fazendaID, tipo, animalID); err != nil {
+		return err
+	}
+	s.publicarEvento(ctx, models.EventoEntidadeAlerta, models.AuditoriaAcaoUpdate, fazendaID, 0, animalID)
+	return nil
 }
 
 func truncateToDateInTZ(t time.Time, loc *time.Location) time.Time {
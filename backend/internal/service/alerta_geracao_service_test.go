@@ -8,13 +8,15 @@ import (
 	"time"
 
 	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/repository"
 )
 
 type fakeAlertaRepoGeracao struct {
-	mu        sync.Mutex
-	open      map[string]struct{}
-	created   int
-	createErr error
+	mu          sync.Mutex
+	open        map[string]struct{}
+	created     int
+	createErr   error
+	severidades []string
 }
 
 func openKey(fazendaID int64, tipo string, animalID int64) string {
@@ -43,6 +45,7 @@ func (f *fakeAlertaRepoGeracao) Create(_ context.Context, row *models.Alerta) er
 		return f.createErr
 	}
 	f.created++
+	f.severidades = append(f.severidades, row.Severidade)
 	row.ID = int64(f.created)
 	f.open[openKey(row.FazendaID, row.Tipo, *row.AnimalID)] = struct{}{}
 	return nil
@@ -55,6 +58,11 @@ func (f *fakeAlertaRepoGeracao) ResolveOpenByFazendaTipoAnimal(_ context.Context
 	return nil
 }
 
+func regraPadraoTeste(tipo string) models.AlertaRegra {
+	p, _ := models.AlertaRegraPadraoPorTipo(tipo)
+	return models.ResolverAlertaRegra(p, nil)
+}
+
 type fakeGestacaoRepoPartos struct {
 	itens []models.PartoPrevistoResumo
 }
@@ -78,7 +86,7 @@ func TestTryCreateAlerta_NaoDuplica(t *testing.T) {
 	}
 	animalID := int64(100)
 
-	c1, ig1, err := svc.tryCreateAlerta(ctx, 1, models.AlertaTipoTratamentoVencido, &animalID, "Tratamento vencido", nil, nil)
+	c1, ig1, err := svc.tryCreateAlerta(ctx, 1, regraPadraoTeste(models.AlertaTipoTratamentoVencido), &animalID, "Tratamento vencido", nil, nil)
 	if err != nil {
 		t.Fatalf("primeira chamada: %v", err)
 	}
@@ -86,7 +94,7 @@ func TestTryCreateAlerta_NaoDuplica(t *testing.T) {
 		t.Fatalf("primeira: criados=%d ignorados=%d", c1, ig1)
 	}
 
-	c2, ig2, err := svc.tryCreateAlerta(ctx, 1, models.AlertaTipoTratamentoVencido, &animalID, "Tratamento vencido 2", nil, nil)
+	c2, ig2, err := svc.tryCreateAlerta(ctx, 1, regraPadraoTeste(models.AlertaTipoTratamentoVencido), &animalID, "Tratamento vencido 2", nil, nil)
 	if err != nil {
 		t.Fatalf("segunda chamada: %v", err)
 	}
@@ -223,7 +231,7 @@ func TestRegraHormonioLactacaoPendente_CriaEDeduplica(t *testing.T) {
 		tz:                 time.UTC,
 	}
 
-	c1, ig1, err := svc.regraHormonioLactacaoPendente(ctx, 1, ref)
+	c1, ig1, err := svc.regraHormonioLactacaoPendente(ctx, 1, ref, regraPadraoTeste(models.AlertaTipoHormonioLactacaoPendente))
 	if err != nil {
 		t.Fatalf("primeira execução: %v", err)
 	}
@@ -231,7 +239,7 @@ func TestRegraHormonioLactacaoPendente_CriaEDeduplica(t *testing.T) {
 		t.Fatalf("primeira: criados=%d ignorados=%d", c1, ig1)
 	}
 
-	c2, ig2, err := svc.regraHormonioLactacaoPendente(ctx, 1, ref)
+	c2, ig2, err := svc.regraHormonioLactacaoPendente(ctx, 1, ref, regraPadraoTeste(models.AlertaTipoHormonioLactacaoPendente))
 	if err != nil {
 		t.Fatalf("segunda execução: %v", err)
 	}
@@ -247,3 +255,131 @@ func TestRegraHormonioLactacaoPendente_CriaEDeduplica(t *testing.T) {
 		t.Fatalf("severidade=%s ok=%v, want ALTA", severidade, ok)
 	}
 }
+
+type fakeAlertaRegrasProvider struct {
+	cfgs map[string]*models.AlertaRegraConfig
+}
+
+func (f *fakeAlertaRegrasProvider) RegrasEfetivas(_ context.Context, _ int64) (map[string]models.AlertaRegra, error) {
+	out := map[string]models.AlertaRegra{}
+	for _, p := range models.AlertaRegrasPadrao() {
+		out[p.Tipo] = models.ResolverAlertaRegra(p, f.cfgs[p.Tipo])
+	}
+	return out, nil
+}
+
+// regrasSoHormonio desliga todas as regras exceto HORMONIO_LACTACAO_PENDENTE (as demais usam repositórios reais).
+func regrasSoHormonio(hormonio *models.AlertaRegraConfig) *fakeAlertaRegrasProvider {
+	cfgs := map[string]*models.AlertaRegraConfig{}
+	for _, p := range models.AlertaRegrasPadrao() {
+		cfgs[p.Tipo] = &models.AlertaRegraConfig{Tipo: p.Tipo, Ativo: false}
+	}
+	cfgs[models.AlertaTipoHormonioLactacaoPendente] = hormonio
+	return &fakeAlertaRegrasProvider{cfgs: cfgs}
+}
+
+func TestGerarPorFazenda_AplicaConfiguracaoDaRegra(t *testing.T) {
+	ctx := context.Background()
+	ref := time.Date(2026, 6, 14, 0, 0, 0, 0, time.UTC)
+	hormonioFake := &fakeHormonioRepoGeracao{
+		pendentes: []*models.HormonioLactacaoPendente{{AnimalID: 10, AnimalIdentificacao: "V-10"}},
+	}
+	media := models.AlertaSeveridadeMedia
+
+	fakeAlerta := newFakeAlertaRepoGeracao()
+	svc := &AlertaGeracaoService{
+		alertaRepo:         fakeAlerta,
+		animalHormonioRepo: hormonioFake,
+		regrasSvc: regrasSoHormonio(&models.AlertaRegraConfig{
+			Tipo: models.AlertaTipoHormonioLactacaoPendente, Ativo: true, Severidade: &media,
+		}),
+		sistemaUserID: 1,
+		tz:            time.UTC,
+	}
+	criados, _, erros := svc.gerarPorFazenda(ctx, 1, ref)
+	if criados != 1 || erros != 0 {
+		t.Fatalf("criados=%d erros=%d", criados, erros)
+	}
+	if len(fakeAlerta.severidades) != 1 || fakeAlerta.severidades[0] != models.AlertaSeveridadeMedia {
+		t.Fatalf("severidade personalizada não aplicada: %v", fakeAlerta.severidades)
+	}
+
+	fakeAlerta = newFakeAlertaRepoGeracao()
+	svc.alertaRepo = fakeAlerta
+	svc.regrasSvc = regrasSoHormonio(&models.AlertaRegraConfig{Tipo: models.AlertaTipoHormonioLactacaoPendente, Ativo: false})
+	criados, _, erros = svc.gerarPorFazenda(ctx, 1, ref)
+	if criados != 0 || erros != 0 || fakeAlerta.created != 0 {
+		t.Fatalf("regra desligada não deveria gerar: criados=%d erros=%d", criados, erros)
+	}
+}
+
+type fakeAlertaRegrasCustom struct {
+	resultados []AlertaRegraCustomResultado
+}
+
+func (f *fakeAlertaRegrasCustom) AvaliarAtivas(_ context.Context, _ int64, _ time.Time) ([]AlertaRegraCustomResultado, error) {
+	return f.resultados, nil
+}
+
+type fakeAlertasGeracaoEstado struct {
+	custom []string
+}
+
+func (f *fakeAlertasGeracaoEstado) Get(_ context.Context, fazendaID int64) (*repository.AlertasGeracaoEstado, error) {
+	return &repository.AlertasGeracaoEstado{FazendaID: fazendaID, CustomChaves: f.custom}, nil
+}
+
+func (f *fakeAlertasGeracaoEstado) Upsert(_ context.Context, _ int64, _ []string, _ time.Time) error {
+	return nil
+}
+
+func (f *fakeAlertasGeracaoEstado) UpsertCustomChaves(_ context.Context, _ int64, chaves []string) error {
+	f.custom = chaves
+	return nil
+}
+
+func TestGerarCustom_SoAnimaisQuePassamASatisfazerARegra(t *testing.T) {
+	ctx := context.Background()
+	ref := time.Date(2026, 6, 14, 0, 0, 0, 0, time.UTC)
+	regra := &models.AlertaRegraCustom{ID: 5, Nome: "DEL alto sem prenhez", Expressao: "del > 150 and not prenhe",
+		Severidade: models.AlertaSeveridadeAlta, Ativo: true}
+	v1 := models.AlertaRegraCustomAnimal{AnimalID: 1, Identificacao: "V-1"}
+	v2 := models.AlertaRegraCustomAnimal{AnimalID: 2, Identificacao: "V-2"}
+
+	custom := &fakeAlertaRegrasCustom{resultados: []AlertaRegraCustomResultado{{Regra: regra, Animais: []models.AlertaRegraCustomAnimal{v1, v2}}}}
+	estado := &fakeAlertasGeracaoEstado{}
+	fakeAlerta := newFakeAlertaRepoGeracao()
+	svc := &AlertaGeracaoService{
+		alertaRepo:    fakeAlerta,
+		estadoRepo:    estado,
+		customSvc:     custom,
+		sistemaUserID: 1,
+	}
+
+	if c, _, e := svc.gerarCustom(ctx, 1, ref); c != 2 || e != 0 {
+		t.Fatalf("primeira execução: criados=%d erros=%d", c, e)
+	}
+	if len(estado.custom) != 2 || estado.custom[0] != "5:1" {
+		t.Fatalf("chaves: %v", estado.custom)
+	}
+	// Continuam a satisfazer: nada de novo, mesmo que os alertas tenham sido resolvidos.
+	fakeAlerta.open = map[string]struct{}{}
+	if c, _, _ := svc.gerarCustom(ctx, 1, ref); c != 0 {
+		t.Fatalf("segunda execução não deve criar: %d", c)
+	}
+	// V-2 deixa de satisfazer e volta: novo alerta.
+	custom.resultados[0].Animais = []models.AlertaRegraCustomAnimal{v1}
+	svc.gerarCustom(ctx, 1, ref)
+	custom.resultados[0].Animais = []models.AlertaRegraCustomAnimal{v1, v2}
+	if c, _, _ := svc.gerarCustom(ctx, 1, ref); c != 1 {
+		t.Fatalf("reentrada deve criar 1, got %d", c)
+	}
+	// Expressão que deixou de compilar: conta erro e preserva as chaves da regra.
+	custom.resultados[0] = AlertaRegraCustomResultado{Regra: regra, Err: ErrAlertaExpressaoInvalida}
+	if c, _, e := svc.gerarCustom(ctx, 1, ref); c != 0 || e != 1 || len(estado.custom) != 2 {
+		t.Fatalf("regra inválida: criados=%d erros=%d chaves=%v", c, e, estado.custom)
+	}
+	if fakeAlerta.severidades[0] != models.AlertaSeveridadeAlta {
+		t.Fatalf("severidade da regra não aplicada: %v", fakeAlerta.severidades)
+	}
+}
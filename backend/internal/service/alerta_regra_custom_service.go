@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrAlertaRegraCustomNotFound   = errors.New("regra de alerta personalizada não encontrada")
+	ErrAlertaRegraCustomNome       = errors.New("nome da regra é obrigatório (máx. 120 caracteres)")
+	ErrAlertaRegraCustomLimite     = errors.New("limite de regras personalizadas por fazenda atingido")
+	ErrAlertaRegraCustomDataFutura = errors.New("data de referência não pode ser futura")
+)
+
+type alertaRegraCustomStore interface {
+	ListByFazendaID(ctx context.Context, fazendaID int64, apenasAtivas bool) ([]*models.AlertaRegraCustom, error)
+	GetByID(ctx context.Context, fazendaID, id int64) (*models.AlertaRegraCustom, error)
+	CountByFazendaID(ctx context.Context, fazendaID int64) (int, error)
+	Create(ctx context.Context, m *models.AlertaRegraCustom) error
+	Update(ctx context.Context, m *models.AlertaRegraCustom) error
+	Delete(ctx context.Context, fazendaID, id int64) error
+	ListMetricasByFazenda(ctx context.Context, fazendaID int64, refDate time.Time) ([]*repository.AlertaCustomMetricas, error)
+}
+
+// AlertaRegraCustomService gere as regras de alerta definidas pela fazenda e avalia as expressões
+// sobre os animais ativos (BR-ALERTA-020).
+type AlertaRegraCustomService struct {
+	repo alertaRegraCustomStore
+}
+
+func NewAlertaRegraCustomService(repo *repository.AlertaRegraCustomRepository) *AlertaRegraCustomService {
+	return &AlertaRegraCustomService{repo: repo}
+}
+
+// AlertaRegraCustomInput corpo de criação/edição da regra.
+type AlertaRegraCustomInput struct {
+	Nome       string
+	Descricao  *string
+	Expressao  string
+	Severidade string // vazio = MEDIA
+	Ativo      bool
+	PushPerfis []string // nil = perfis operacionais; vazio = sem push
+}
+
+// AlertaRegraCustomResultado animais que satisfazem uma regra numa data; Err preenchido quando a
+// expressão gravada deixou de compilar (a regra é ignorada nessa execução).
+type AlertaRegraCustomResultado struct {
+	Regra   *models.AlertaRegraCustom
+	Animais []models.AlertaRegraCustomAnimal
+	Err     error
+}
+
+// AlertaRegraCustomPreview resultado da pré-visualização de uma expressão.
+type AlertaRegraCustomPreview struct {
+	Data    string                           `json:"data"`
+	Total   int                              `json:"total"`
+	Animais []models.AlertaRegraCustomAnimal `json:"animais"`
+}
+
+func (s *AlertaRegraCustomService) List(ctx context.Context, fazendaID int64) ([]*models.AlertaRegraCustom, error) {
+	return s.repo.ListByFazendaID(ctx, fazendaID, false)
+}
+
+func (s *AlertaRegraCustomService) Get(ctx context.Context, fazendaID, id int64) (*models.AlertaRegraCustom, error) {
+	m, err := s.repo.GetByID(ctx, fazendaID, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAlertaRegraCustomNotFound
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+func (s *AlertaRegraCustomService) Create(ctx context.Context, fazendaID int64, in AlertaRegraCustomInput, actorID int64, perfil string) (*models.AlertaRegraCustom, error) {
+	if !models.PodeConfigurarRegrasAlerta(perfil) {
+		return nil, ErrAlertaRegraForbidden
+	}
+	if err := normalizeAlertaRegraCustomInput(&in); err != nil {
+		return nil, err
+	}
+	n, err := s.repo.CountByFazendaID(ctx, fazendaID)
+	if err != nil {
+		return nil, err
+	}
+	if n >= models.AlertaRegraCustomMaxPorFazenda {
+		return nil, fmt.Errorf("%w (%d)", ErrAlertaRegraCustomLimite, models.AlertaRegraCustomMaxPorFazenda)
+	}
+	m := &models.AlertaRegraCustom{FazendaID: fazendaID}
+	aplicarAlertaRegraCustomInput(m, in)
+	if actorID > 0 {
+		m.CreatedBy = &actorID
+		m.UpdatedBy = &actorID
+	}
+	if err := s.repo.Create(ctx, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (s *AlertaRegraCustomService) Update(ctx context.Context, fazendaID, id int64, in AlertaRegraCustomInput, actorID int64, perfil string) (*models.AlertaRegraCustom, error) {
+	if !models.PodeConfigurarRegrasAlerta(perfil) {
+		return nil, ErrAlertaRegraForbidden
+	}
+	if err := normalizeAlertaRegraCustomInput(&in); err != nil {
+		return nil, err
+	}
+	m, err := s.Get(ctx, fazendaID, id)
+	if err != nil {
+		return nil, err
+	}
+	aplicarAlertaRegraCustomInput(m, in)
+	if actorID > 0 {
+		m.UpdatedBy = &actorID
+	}
+	if err := s.repo.Update(ctx, m); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAlertaRegraCustomNotFound
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+func (s *AlertaRegraCustomService) Delete(ctx context.Context, fazendaID, id int64, perfil string) error {
+	if !models.PodeConfigurarRegrasAlerta(perfil) {
+		return ErrAlertaRegraForbidden
+	}
+	if err := s.repo.Delete(ctx, fazendaID, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrAlertaRegraCustomNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Preview avalia a expressão sobre o rebanho ativo em refDate (nada é gravado).
+func (s *AlertaRegraCustomService) Preview(ctx context.Context, fazendaID int64, expressao string, refDate time.Time) (*AlertaRegraCustomPreview, error) {
+	expr, err := CompilarAlertaExpressao(expressao)
+	if err != nil {
+		return nil, err
+	}
+	ref := TruncateToCivilDate(refDate)
+	if civilAfter(ref, time.Now()) {
+		return nil, ErrAlertaRegraCustomDataFutura
+	}
+	metricas, err := s.repo.ListMetricasByFazenda(ctx, fazendaID, ref)
+	if err != nil {
+		return nil, err
+	}
+	animais := avaliarAlertaExpressao(expr, metricas, ref)
+	return &AlertaRegraCustomPreview{Data: ref.Format("2006-01-02"), Total: len(animais), Animais: animais}, nil
+}
+
+// AvaliarAtivas avalia as regras ativas da fazenda em refDate (geração diária). As métricas só são
+// lidas quando há regras ativas.
+func (s *AlertaRegraCustomService) AvaliarAtivas(ctx context.Context, fazendaID int64, refDate time.Time) ([]AlertaRegraCustomResultado, error) {
+	regras, err := s.repo.ListByFazendaID(ctx, fazendaID, true)
+	if err != nil || len(regras) == 0 {
+		return nil, err
+	}
+	metricas, err := s.repo.ListMetricasByFazenda(ctx, fazendaID, refDate)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]AlertaRegraCustomResultado, 0, len(regras))
+	for _, r := range regras {
+		res := AlertaRegraCustomResultado{Regra: r}
+		expr, err := CompilarAlertaExpressao(r.Expressao)
+		if err != nil {
+			res.Err = err
+		} else {
+			res.Animais = avaliarAlertaExpressao(expr, metricas, refDate)
+		}
+		out = append(out, res)
+	}
+	return out, nil
+}
+
+func avaliarAlertaExpressao(expr *AlertaExpressao, metricas []*repository.AlertaCustomMetricas, ref time.Time) []models.AlertaRegraCustomAnimal {
+	out := []models.AlertaRegraCustomAnimal{}
+	for _, m := range metricas {
+		if m == nil {
+			continue
+		}
+		if expr.Avaliar(alertaExprAmbiente(m, ref)) {
+			out = append(out, models.AlertaRegraCustomAnimal{AnimalID: m.AnimalID, Identificacao: m.Identificacao})
+		}
+	}
+	return out
+}
+
+func normalizeAlertaRegraCustomInput(in *AlertaRegraCustomInput) error {
+	in.Nome = strings.TrimSpace(in.Nome)
+	if in.Nome == "" || len([]rune(in.Nome)) > models.AlertaRegraCustomNomeMaxLen {
+		return ErrAlertaRegraCustomNome
+	}
+	if in.Descricao != nil {
+		d := strings.TrimSpace(*in.Descricao)
+		if d == "" {
+			in.Descricao = nil
+		} else {
+			in.Descricao = &d
+		}
+	}
+	in.Expressao = strings.TrimSpace(in.Expressao)
+	if _, err := CompilarAlertaExpressao(in.Expressao); err != nil {
+		return err
+	}
+	if in.Severidade == "" {
+		in.Severidade = models.AlertaSeveridadeMedia
+	}
+	if !models.IsValidAlertaSeveridade(in.Severidade) {
+		return ErrAlertaSeveridadeInvalida
+	}
+	for _, p := range in.PushPerfis {
+		if !models.IsValidAlertaPushPerfil(p) {
+			return fmt.Errorf("%w: %s", ErrAlertaRegraPushPerfil, p)
+		}
+	}
+	in.PushPerfis = dedupPerfis(in.PushPerfis)
+	return nil
+}
+
+func aplicarAlertaRegraCustomInput(m *models.AlertaRegraCustom, in AlertaRegraCustomInput) {
+	m.Nome = in.Nome
+	m.Descricao = in.Descricao
+	m.Expressao = in.Expressao
+	m.Severidade = in.Severidade
+	m.Ativo = in.Ativo
+	m.PushPerfis = in.PushPerfis
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/repository"
+)
+
+type fakeAlertaRegraCustomStore struct {
+	regras   []*models.AlertaRegraCustom
+	metricas []*repository.AlertaCustomMetricas
+}
+
+func (f *fakeAlertaRegraCustomStore) ListByFazendaID(_ context.Context, _ int64, apenasAtivas bool) ([]*models.AlertaRegraCustom, error) {
+	var out []*models.AlertaRegraCustom
+	for _, r := range f.regras {
+		if !apenasAtivas || r.Ativo {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeAlertaRegraCustomStore) GetByID(_ context.Context, _ int64, id int64) (*models.AlertaRegraCustom, error) {
+	for _, r := range f.regras {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return nil, errors.New("no rows")
+}
+
+func (f *fakeAlertaRegraCustomStore) CountByFazendaID(_ context.Context, _ int64) (int, error) {
+	return len(f.regras), nil
+}
+
+func (f *fakeAlertaRegraCustomStore) Create(_ context.Context, m *models.AlertaRegraCustom) error {
+	m.ID = int64(len(f.regras) + 1)
+	f.regras = append(f.regras, m)
+	return nil
+}
+
+func (f *fakeAlertaRegraCustomStore) Update(_ context.Context, _ *models.AlertaRegraCustom) error {
+	return nil
+}
+
+func (f *fakeAlertaRegraCustomStore) Delete(_ context.Context, _, _ int64) error { return nil }
+
+func (f *fakeAlertaRegraCustomStore) ListMetricasByFazenda(_ context.Context, _ int64, _ time.Time) ([]*repository.AlertaCustomMetricas, error) {
+	return f.metricas, nil
+}
+
+func TestAlertaRegraCustomService_CreateEAvaliar(t *testing.T) {
+	ctx := context.Background()
+	inicio := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := &fakeAlertaRegraCustomStore{metricas: []*repository.AlertaCustomMetricas{
+		{AnimalID: 1, Identificacao: "V-1", InicioLactacao: &inicio},
+		{AnimalID: 2, Identificacao: "V-2", InicioLactacao: &inicio, Prenhe: true},
+	}}
+	svc := &AlertaRegraCustomService{repo: store}
+
+	if _, err := svc.Create(ctx, 1, AlertaRegraCustomInput{Nome: "x", Expressao: "del > 1"}, 9, models.PerfilFuncionario); !errors.Is(err, ErrAlertaRegraForbidden) {
+		t.Fatalf("FUNCIONARIO não cria regras: %v", err)
+	}
+	if _, err := svc.Create(ctx, 1, AlertaRegraCustomInput{Nome: "  ", Expressao: "del > 1"}, 9, models.PerfilGestao); !errors.Is(err, ErrAlertaRegraCustomNome) {
+		t.Fatalf("nome vazio: %v", err)
+	}
+	if _, err := svc.Create(ctx, 1, AlertaRegraCustomInput{Nome: "x", Expressao: "del >> 1"}, 9, models.PerfilGestao); !errors.Is(err, ErrAlertaExpressaoInvalida) {
+		t.Fatalf("expressão inválida: %v", err)
+	}
+	r, err := svc.Create(ctx, 1, AlertaRegraCustomInput{Nome: "DEL alto", Expressao: " del > 150 and not prenhe ", Ativo: true}, 9, models.PerfilGestao)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if r.Severidade != models.AlertaSeveridadeMedia || r.Expressao != "del > 150 and not prenhe" || r.PushPerfis != nil {
+		t.Fatalf("regra: %+v", r)
+	}
+	// Regra gravada que deixou de compilar não impede as restantes.
+	store.regras = append(store.regras, &models.AlertaRegraCustom{ID: 99, Nome: "antiga", Expressao: "variavel_removida > 1", Ativo: true})
+
+	res, err := svc.AvaliarAtivas(ctx, 1, time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("AvaliarAtivas: %v", err)
+	}
+	if len(res) != 2 || len(res[0].Animais) != 1 || res[0].Animais[0].AnimalID != 1 || res[1].Err == nil {
+		t.Fatalf("resultados: %+v", res)
+	}
+}
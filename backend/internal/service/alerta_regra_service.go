@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/repository"
+)
+
+var (
+	ErrAlertaRegraTipoInvalido  = errors.New("tipo não corresponde a uma regra automática de alerta")
+	ErrAlertaRegraDiasInvalidos = errors.New("dias fora do intervalo permitido para a regra")
+	ErrAlertaRegraSemDias       = errors.New("a regra não aceita parâmetro de dias")
+	ErrAlertaRegraPushPerfil    = errors.New("perfil inválido para push de alerta")
+	ErrAlertaRegraForbidden     = errors.New("apenas gestão ou administrador pode configurar regras de alerta")
+)
+
+type alertaRegraStore interface {
+	ListByFazendaID(ctx context.Context, fazendaID int64) ([]*models.AlertaRegraConfig, error)
+	Upsert(ctx context.Context, c *models.AlertaRegraConfig) error
+	Delete(ctx context.Context, fazendaID int64, tipo string) error
+}
+
+// AlertaRegraService gere a configuração por fazenda das regras de geração automática (BR-ALERTA-019)
+// e resolve as regras efetivas lidas pelo cron e por POST /admin/alertas/gerar.
+type AlertaRegraService struct {
+	repo alertaRegraStore
+}
+
+func NewAlertaRegraService(repo *repository.AlertaRegraRepository) *AlertaRegraService {
+	return &AlertaRegraService{repo: repo}
+}
+
+// AlertaRegraInput personalização enviada no PUT; campos nil = padrão da regra.
+type AlertaRegraInput struct {
+	Ativo      bool
+	Dias       *int
+	Severidade *string
+	PushPerfis []string
+}
+
+// List devolve todas as regras automáticas com os valores efetivos na fazenda.
+func (s *AlertaRegraService) List(ctx context.Context, fazendaID int64) ([]models.AlertaRegra, error) {
+	efetivas, err := s.RegrasEfetivas(ctx, fazendaID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]models.AlertaRegra, 0, len(efetivas))
+	for _, p := range models.AlertaRegrasPadrao() {
+		out = append(out, efetivas[p.Tipo])
+	}
+	return out, nil
+}
+
+// RegrasEfetivas devolve as regras da fazenda por tipo (padrão + personalização).
+func (s *AlertaRegraService) RegrasEfetivas(ctx context.Context, fazendaID int64) (map[string]models.AlertaRegra, error) {
+	cfgs, err := s.repo.ListByFazendaID(ctx, fazendaID)
+	if err != nil {
+		return nil, err
+	}
+	porTipo := make(map[string]*models.AlertaRegraConfig, len(cfgs))
+	for _, c := range cfgs {
+		porTipo[c.Tipo] = c
+	}
+	out := make(map[string]models.AlertaRegra)
+	for _, p := range models.AlertaRegrasPadrao() {
+		out[p.Tipo] = models.ResolverAlertaRegra(p, porTipo[p.Tipo])
+	}
+	return out, nil
+}
+
+// Put valida e grava a personalização da regra.
+func (s *AlertaRegraService) Put(ctx context.Context, fazendaID int64, tipo string, in AlertaRegraInput, actorID int64, perfil string) (*models.AlertaRegra, error) {
+	if !models.PodeConfigurarRegrasAlerta(perfil) {
+		return nil, ErrAlertaRegraForbidden
+	}
+	padrao, err := validateAlertaRegraInput(tipo, in)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &models.AlertaRegraConfig{
+		FazendaID:  fazendaID,
+		Tipo:       tipo,
+		Ativo:      in.Ativo,
+		Dias:       in.Dias,
+		Severidade: in.Severidade,
+		PushPerfis: dedupPerfis(in.PushPerfis),
+	}
+	if actorID > 0 {
+		cfg.UpdatedBy = &actorID
+	}
+	if err := s.repo.Upsert(ctx, cfg); err != nil {
+		return nil, err
+	}
+	r := models.ResolverAlertaRegra(padrao, cfg)
+	return &r, nil
+}
+
+// Reset remove a personalização; a regra volta ao padrão.
+func (s *AlertaRegraService) Reset(ctx context.Context, fazendaID int64, tipo string, perfil string) (*models.AlertaRegra, error) {
+	if !models.PodeConfigurarRegrasAlerta(perfil) {
+		return nil, ErrAlertaRegraForbidden
+	}
+	padrao, ok := models.AlertaRegraPadraoPorTipo(tipo)
+	if !ok {
+		return nil, ErrAlertaRegraTipoInvalido
+	}
+	if err := s.repo.Delete(ctx, fazendaID, tipo); err != nil {
+		return nil, err
+	}
+	r := models.ResolverAlertaRegra(padrao, nil)
+	return &r, nil
+}
+
+func validateAlertaRegraInput(tipo string, in AlertaRegraInput) (models.AlertaRegraPadrao, error) {
+	padrao, ok := models.AlertaRegraPadraoPorTipo(tipo)
+	if !ok {
+		return padrao, ErrAlertaRegraTipoInvalido
+	}
+	if in.Dias != nil {
+		if padrao.DiasPadrao == nil {
+			return padrao, ErrAlertaRegraSemDias
+		}
+		if *in.Dias < padrao.DiasMin || *in.Dias > padrao.DiasMax {
+			return padrao, fmt.Errorf("%w (%d a %d)", ErrAlertaRegraDiasInvalidos, padrao.DiasMin, padrao.DiasMax)
+		}
+	}
+	if in.Severidade != nil && !models.IsValidAlertaSeveridade(*in.Severidade) {
+		return padrao, ErrAlertaSeveridadeInvalida
+	}
+	for _, p := range in.PushPerfis {
+		if !models.IsValidAlertaPushPerfil(p) {
+			return padrao, fmt.Errorf("%w: %s", ErrAlertaRegraPushPerfil, p)
+		}
+	}
+	return padrao, nil
+}
+
+// dedupPerfis preserva nil (padrão) e remove repetidos mantendo a ordem.
+func dedupPerfis(perfis []string) []string {
+	if perfis == nil {
+		return nil
+	}
+	out := make([]string, 0, len(perfis))
+	vistos := map[string]struct{}{}
+	for _, p := range perfis {
+		if _, ok := vistos[p]; ok {
+			continue
+		}
+		vistos[p] = struct{}{}
+		out = append(out, p)
+	}
+	return out
+}
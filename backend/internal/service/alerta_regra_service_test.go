@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ceialmilk/api/internal/models"
+)
+
+type fakeAlertaRegraStore struct {
+	cfgs map[string]*models.AlertaRegraConfig
+}
+
+func (f *fakeAlertaRegraStore) ListByFazendaID(_ context.Context, _ int64) ([]*models.AlertaRegraConfig, error) {
+	var out []*models.AlertaRegraConfig
+	for _, c := range f.cfgs {
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+func (f *fakeAlertaRegraStore) Upsert(_ context.Context, c *models.AlertaRegraConfig) error {
+	f.cfgs[c.Tipo] = c
+	return nil
+}
+
+func (f *fakeAlertaRegraStore) Delete(_ context.Context, _ int64, tipo string) error {
+	delete(f.cfgs, tipo)
+	return nil
+}
+
+func TestAlertaRegraService_PutValidaEResolve(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeAlertaRegraStore{cfgs: map[string]*models.AlertaRegraConfig{}}
+	svc := &AlertaRegraService{repo: store}
+	dias := func(d int) *int { return &d }
+
+	erros := []struct {
+		nome string
+		tipo string
+		in   AlertaRegraInput
+		want error
+	}{
+		{"manual não é regra automática", models.AlertaTipoManual, AlertaRegraInput{Ativo: true}, ErrAlertaRegraTipoInvalido},
+		{"dias fora do intervalo", models.AlertaTipoPartoPrevisto, AlertaRegraInput{Ativo: true, Dias: dias(120)}, ErrAlertaRegraDiasInvalidos},
+		{"regra sem dias", models.AlertaTipoCioDetectado, AlertaRegraInput{Ativo: true, Dias: dias(3)}, ErrAlertaRegraSemDias},
+		{"perfil USER não recebe push", models.AlertaTipoPartoPrevisto, AlertaRegraInput{Ativo: true, PushPerfis: []string{models.PerfilUser}}, ErrAlertaRegraPushPerfil},
+	}
+	for _, tc := range erros {
+		if _, err := svc.Put(ctx, 1, tc.tipo, tc.in, 9, models.PerfilGestao); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: esperado %v, got %v", tc.nome, tc.want, err)
+		}
+	}
+	if _, err := svc.Put(ctx, 1, models.AlertaTipoPartoPrevisto, AlertaRegraInput{Ativo: true}, 9, models.PerfilFuncionario); !errors.Is(err, ErrAlertaRegraForbidden) {
+		t.Fatalf("FUNCIONARIO não configura regras: %v", err)
+	}
+
+	baixa := models.AlertaSeveridadeBaixa
+	r, err := svc.Put(ctx, 1, models.AlertaTipoPartoPrevisto, AlertaRegraInput{
+		Ativo: true, Dias: dias(7), Severidade: &baixa,
+		PushPerfis: []string{models.PerfilGestao, models.PerfilGestao},
+	}, 9, models.PerfilGestao)
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if r.DiasEfetivos() != 7 || r.Severidade != baixa || len(r.PushPerfis) != 1 || !r.Personalizada {
+		t.Fatalf("regra efetiva: %+v", r)
+	}
+
+	efetivas, err := svc.RegrasEfetivas(ctx, 1)
+	if err != nil {
+		t.Fatalf("RegrasEfetivas: %v", err)
+	}
+	if efetivas[models.AlertaTipoPartoPrevisto].DiasEfetivos() != 7 {
+		t.Fatalf("config não lida: %+v", efetivas[models.AlertaTipoPartoPrevisto])
+	}
+	if gs := efetivas[models.AlertaTipoGestacaoSemSecagem]; gs.DiasEfetivos() != 250 || gs.Personalizada || !gs.Ativo {
+		t.Fatalf("regra sem config deve usar o padrão: %+v", gs)
+	}
+
+	if _, err := svc.Reset(ctx, 1, models.AlertaTipoPartoPrevisto, models.PerfilGestao); err != nil {
+		t.Fatalf("Reset: %v", err)
+	}
+	efetivas, _ = svc.RegrasEfetivas(ctx, 1)
+	if efetivas[models.AlertaTipoPartoPrevisto].DiasEfetivos() != 14 {
+		t.Fatalf("reset deve voltar ao padrão: %+v", efetivas[models.AlertaTipoPartoPrevisto])
+	}
+}
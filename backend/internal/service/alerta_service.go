@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -23,6 +24,9 @@ var (
 	ErrAlertaTituloObrigatorio  = errors.New("título é obrigatório")
 	ErrAlertaSomenteManualCreate = errors.New("apenas alertas manuais podem ser criados via API")
 	ErrAlertaPeriodoInvalido       = errors.New("período inválido: informe start e end no formato YYYY-MM-DD, com start <= end")
+	ErrAlertaResponsavelInvalido   = errors.New("responsável deve ser um utilizador ativo vinculado à fazenda")
+	ErrAlertaEncerrado             = errors.New("alerta resolvido ou ignorado não pode ser atribuído")
+	ErrAlertaComentarioInvalido    = errors.New("comentário deve ter entre 1 e 2000 caracteres")
 )
 
 type alertaStore interface {
@@ -38,13 +42,19 @@ type alertaAnimalStore interface {
 }
 
 type AlertaService struct {
+	publicaEventos
 	repo       alertaStore
 	animalRepo alertaAnimalStore
 	pushSvc    *PushNotificationService
+
+	// Atribuição, histórico e escalonamento (BR-ALERTA-023).
+	atribuicao  alertaAtribuicaoStore
+	atividades  alertaAtividadeStore
+	usuariosFaz alertaUsuariosFazendaStore
 }
 
-func NewAlertaService(repo *repository.AlertaRepository, animalRepo *repository.AnimalRepository) *AlertaService {
-	return &AlertaService{repo: repo, animalRepo: animalRepo}
+func NewAlertaService(repo *repository.AlertaRepository, animalRepo *repository.AnimalRepository, atividadeRepo *repository.AlertaAtividadeRepository, fazendaRepo *repository.FazendaRepository) *AlertaService {
+	return &AlertaService{repo: repo, animalRepo: animalRepo, atribuicao: repo, atividades: atividadeRepo, usuariosFaz: fazendaRepo}
 }
 
 func (s *AlertaService) SetPushNotificationService(pushSvc *PushNotificationService) {
@@ -57,8 +67,12 @@ type AlertaListQuery struct {
 	Severidade  string
 	PeriodStart *time.Time
 	PeriodEnd   *time.Time
-	Limit       int
-	Offset      int
+	// ResponsavelID "meus alertas"; SemResponsavel ignorado quando ResponsavelID é informado.
+	ResponsavelID  *int64
+	SemResponsavel bool
+	Atrasados      bool
+	Limit          int
+	Offset         int
 }
 
 func (s *AlertaService) ListByFazenda(ctx context.Context, fazendaID int64, q AlertaListQuery) ([]models.AlertaWithNames, int64, error) {
@@ -91,13 +105,16 @@ func (s *AlertaService) ListByFazenda(ctx context.Context, fazendaID int64, q Al
 	}
 
 	return s.repo.ListByFazenda(ctx, fazendaID, repository.AlertaListFilters{
-		Status:      q.Status,
-		Tipo:        q.Tipo,
-		Severidade:  q.Severidade,
-		PeriodStart: q.PeriodStart,
-		PeriodEnd:   q.PeriodEnd,
-		Limit:       limit,
-		Offset:      offset,
+		Status:         q.Status,
+		Tipo:           q.Tipo,
+		Severidade:     q.Severidade,
+		PeriodStart:    q.PeriodStart,
+		PeriodEnd:      q.PeriodEnd,
+		ResponsavelID:  q.ResponsavelID,
+		SemResponsavel: q.SemResponsavel,
+		Atrasados:      q.Atrasados,
+		Limit:          limit,
+		Offset:         offset,
 	})
 }
 
@@ -176,6 +193,7 @@ func (s *AlertaService) Create(ctx context.Context, in CreateAlertaInput, perfil
 	if s.pushSvc != nil {
 		s.pushSvc.NotifyAlertaCreated(created)
 	}
+	s.publicarEventoAlerta(ctx, models.AuditoriaAcaoCreate, &created.Alerta)
 	return created, nil
 }
 
@@ -220,6 +238,29 @@ func (s *AlertaService) UpdateStatus(ctx context.Context, fazendaID, alertaID in
 		}
 		return nil, err
 	}
+	s.registrarAtividade(ctx, &models.AlertaAtividade{
+		AlertaID:  alertaID,
+		FazendaID: fazendaID,
+		UsuarioID: &in.ActorUserID,
+		Tipo:      models.AlertaAtividadeStatus,
+		Texto:     fmt.Sprintf("Status alterado de %s para %s", existing.Status, in.Status),
+		Dados:     alertaAtividadeDados(map[string]interface{}{"de": existing.Status, "para": in.Status}),
+	})
+	// Quem coloca em andamento um alerta sem responsável passa a ser o responsável.
+	if in.Status == models.AlertaStatusEmAndamento && existing.ResponsavelID == nil && s.atribuicao != nil {
+		if err := s.atribuicao.UpdateResponsavel(ctx, fazendaID, alertaID, &in.ActorUserID); err != nil {
+			return nil, err
+		}
+		s.registrarAtividade(ctx, &models.AlertaAtividade{
+			AlertaID:  alertaID,
+			FazendaID: fazendaID,
+			UsuarioID: &in.ActorUserID,
+			Tipo:      models.AlertaAtividadeAtribuicao,
+			Texto:     "Assumiu o alerta",
+			Dados:     alertaAtividadeDados(map[string]interface{}{"de": nil, "para": in.ActorUserID}),
+		})
+	}
+	s.publicarEventoAlerta(ctx, models.AuditoriaAcaoUpdate, &existing.Alerta)
 	return s.repo.GetByID(ctx, fazendaID, alertaID)
 }
 
@@ -264,5 +305,6 @@ func (s *AlertaService) Delete(ctx context.Context, fazendaID, alertaID int64, p
 		}
 		return err
 	}
+	s.publicarEventoAlerta(ctx, models.AuditoriaAcaoDelete, &existing.Alerta)
 	return nil
 }
@@ -19,6 +19,7 @@ var (
 )
 
 type AnimalBaixaService struct {
+	auditavel
 	pool            *pgxpool.Pool
 	animalRepo      *repository.AnimalRepository
 	lactacaoRepo    *repository.LactacaoRepository
@@ -105,7 +106,12 @@ func (s *AnimalBaixaService) RegistrarBaixa(
 	}
 	committed = true
 
-	return s.animalRepo.GetByID(ctx, animalID)
+	depois, err := s.animalRepo.GetByID(ctx, animalID)
+	if err != nil {
+		return nil, err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeAnimal, animalID, animal.FazendaID, animalID, animal, depois)
+	return depois, nil
 }
 
 func (s *AnimalBaixaService) ReverterBaixa(ctx context.Context, animalID int64, actorUserID int64) (*models.Animal, error) {
@@ -140,7 +146,12 @@ func (s *AnimalBaixaService) ReverterBaixa(ctx context.Context, animalID int64,
 	}
 	committed = true
 
-	return s.animalRepo.GetByID(ctx, animalID)
+	depois, err := s.animalRepo.GetByID(ctx, animalID)
+	if err != nil {
+		return nil, err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeAnimal, animalID, animal.FazendaID, animalID, animal, depois)
+	return depois, nil
 }
 
 // MotivoBaixaLabel retorna rótulo PT para API/UI.
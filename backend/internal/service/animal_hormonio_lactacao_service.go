@@ -78,6 +78,7 @@ type hormonioSaudeStore interface {
 }
 
 type AnimalHormonioLactacaoService struct {
+	auditavel
 	repo           hormonioLactacaoStore
 	animalRepo     hormonioAnimalStore
 	lactacaoRepo   hormonioLactacaoLookup
@@ -241,6 +242,7 @@ func (s *AnimalHormonioLactacaoService) Create(ctx context.Context, animalID int
 	if err := s.repo.CreateAplicacao(ctx, aplicacao); err != nil {
 		return nil, err
 	}
+	s.auditar(ctx, models.AuditoriaAcaoCreate, models.AuditoriaEntidadeHormonioLactacao, aplicacao.ID, animal.FazendaID, animalID, nil, aplicacao)
 	s.afterCreate(ctx, animal, aplicacao)
 	return aplicacao, nil
 }
@@ -307,6 +309,7 @@ func (s *AnimalHormonioLactacaoService) Update(ctx context.Context, animalID, ap
 		}
 	}
 
+	antes := *existing
 	existing.Produto = in.Produto
 	existing.DataAplicacao = TruncateToCivilDate(in.DataAplicacao)
 	existing.DataProximaAplicacao = calcDataProximaAplicacao(in.DataAplicacao, ctxEleg.gestacao.DataPrevistaParto)
@@ -319,11 +322,20 @@ func (s *AnimalHormonioLactacaoService) Update(ctx context.Context, animalID, ap
 		}
 		return nil, err
 	}
+	s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeHormonioLactacao, existing.ID, animal.FazendaID, animalID, &antes, existing)
 	return existing, nil
 }
 
 func (s *AnimalHormonioLactacaoService) Delete(ctx context.Context, animalID, aplicacaoID int64) error {
-	if _, err := s.ensureAnimalAtivo(ctx, animalID); err != nil {
+	animal, err := s.ensureAnimalAtivo(ctx, animalID)
+	if err != nil {
+		return err
+	}
+	existing, err := s.repo.GetAplicacaoByID(ctx, animalID, aplicacaoID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrHormonioNotFound
+		}
 		return err
 	}
 	if err := s.repo.DeleteAplicacao(ctx, animalID, aplicacaoID); err != nil {
@@ -332,6 +344,7 @@ func (s *AnimalHormonioLactacaoService) Delete(ctx context.Context, animalID, ap
 		}
 		return err
 	}
+	s.auditar(ctx, models.AuditoriaAcaoDelete, models.AuditoriaEntidadeHormonioLactacao, aplicacaoID, animal.FazendaID, animalID, existing, nil)
 	return nil
 }
 
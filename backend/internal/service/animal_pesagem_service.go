@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrAnimalPesagemNotFound     = errors.New("pesagem nao encontrada")
+	ErrAnimalPesagemPesoInvalido = errors.New("peso_kg deve ser maior que zero e menor que 5000")
+	ErrAnimalPesagemDataFutura   = errors.New("data da pesagem nao pode ser futura")
+)
+
+type CreateAnimalPesagemInput struct {
+	Data       time.Time
+	PesoKg     float64
+	Observacao *string
+	CreatedBy  *int64
+}
+
+// AnimalPesagemService pesagens do animal; o peso mais recente alimenta a transição BEZERRA → NOVILHA (BR-LOTE-005).
+type AnimalPesagemService struct {
+	auditavel
+	repo       *repository.AnimalPesagemRepository
+	animalRepo *repository.AnimalRepository
+}
+
+func NewAnimalPesagemService(repo *repository.AnimalPesagemRepository, animalRepo *repository.AnimalRepository) *AnimalPesagemService {
+	return &AnimalPesagemService{repo: repo, animalRepo: animalRepo}
+}
+
+func (s *AnimalPesagemService) ListByAnimalID(ctx context.Context, animalID int64) ([]*models.AnimalPesagem, error) {
+	return s.repo.ListByAnimal(ctx, animalID)
+}
+
+func (s *AnimalPesagemService) Create(ctx context.Context, animalID int64, in CreateAnimalPesagemInput) (*models.AnimalPesagem, error) {
+	animal, err := s.ensureAnimalAtivo(ctx, animalID)
+	if err != nil {
+		return nil, err
+	}
+	if in.PesoKg <= 0 || in.PesoKg >= 5000 {
+		return nil, ErrAnimalPesagemPesoInvalido
+	}
+	data := TruncateToCivilDate(in.Data)
+	if data.After(TruncateToCivilDate(time.Now())) {
+		return nil, ErrAnimalPesagemDataFutura
+	}
+	row := &models.AnimalPesagem{
+		AnimalID:   animalID,
+		FazendaID:  animal.FazendaID,
+		Data:       data,
+		PesoKg:     in.PesoKg,
+		Observacao: in.Observacao,
+		CreatedBy:  in.CreatedBy,
+	}
+	if err := s.repo.Create(ctx, row); err != nil {
+		return nil, err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoCreate, models.AuditoriaEntidadeAnimalPesagem, row.ID, animal.FazendaID, animalID, nil, row)
+	return row, nil
+}
+
+func (s *AnimalPesagemService) Delete(ctx context.Context, animalID, pesagemID int64) error {
+	animal, err := s.ensureAnimalAtivo(ctx, animalID)
+	if err != nil {
+		return err
+	}
+	list, err := s.repo.ListByAnimal(ctx, animalID)
+	if err != nil {
+		return err
+	}
+	var existing *models.AnimalPesagem
+	for _, p := range list {
+		if p.ID == pesagemID {
+			existing = p
+			break
+		}
+	}
+	if existing == nil {
+		return ErrAnimalPesagemNotFound
+	}
+	if err := s.repo.Delete(ctx, animalID, pesagemID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrAnimalPesagemNotFound
+		}
+		return err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoDelete, models.AuditoriaEntidadeAnimalPesagem, pesagemID, animal.FazendaID, animalID, existing, nil)
+	return nil
+}
+
+func (s *AnimalPesagemService) ensureAnimalAtivo(ctx context.Context, animalID int64) (*models.Animal, error) {
+	animal, err := s.animalRepo.GetByID(ctx, animalID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAnimalNotFound
+		}
+		return nil, err
+	}
+	if err := EnsureAnimalNoRebanho(animal); err != nil {
+		return nil, err
+	}
+	return animal, nil
+}
@@ -32,6 +32,7 @@ type animalSaudeAnimalStore interface {
 }
 
 type AnimalSaudeService struct {
+	auditavel
 	repo           animalSaudeStore
 	animalRepo     animalSaudeAnimalStore
 	alertaResolver AlertaAutoResolver
@@ -102,6 +103,7 @@ func (s *AnimalSaudeService) Create(ctx context.Context, animalID int64, in Save
 	if err := s.repo.Create(ctx, row); err != nil {
 		return nil, err
 	}
+	s.auditar(ctx, models.AuditoriaAcaoCreate, models.AuditoriaEntidadeAnimalSaude, row.ID, animal.FazendaID, animalID, nil, row)
 	if err := s.syncAnimalStatusSaude(ctx, animalID); err != nil {
 		return nil, err
 	}
@@ -132,6 +134,7 @@ func (s *AnimalSaudeService) Update(ctx context.Context, animalID, saudeID int64
 		}
 	}
 
+	antes := *existing
 	existing.TipoCaso = in.TipoCaso
 	existing.DataInicio = normalizeAnimalSaudeDate(in.DataInicio)
 	existing.DataFim = normalizeOptionalAnimalSaudeDate(in.DataFim)
@@ -143,6 +146,7 @@ func (s *AnimalSaudeService) Update(ctx context.Context, animalID, saudeID int64
 		}
 		return nil, err
 	}
+	s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeAnimalSaude, existing.ID, animal.FazendaID, animalID, &antes, existing)
 	if err := s.syncAnimalStatusSaude(ctx, animalID); err != nil {
 		return nil, err
 	}
@@ -151,7 +155,15 @@ func (s *AnimalSaudeService) Update(ctx context.Context, animalID, saudeID int64
 }
 
 func (s *AnimalSaudeService) Delete(ctx context.Context, animalID, saudeID int64) error {
-	if _, err := s.ensureAnimalAtivo(ctx, animalID); err != nil {
+	animal, err := s.ensureAnimalAtivo(ctx, animalID)
+	if err != nil {
+		return err
+	}
+	existing, err := s.repo.GetByID(ctx, animalID, saudeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrAnimalSaudeNotFound
+		}
 		return err
 	}
 	if err := s.repo.Delete(ctx, animalID, saudeID); err != nil {
@@ -160,6 +172,7 @@ func (s *AnimalSaudeService) Delete(ctx context.Context, animalID, saudeID int64
 		}
 		return err
 	}
+	s.auditar(ctx, models.AuditoriaAcaoDelete, models.AuditoriaEntidadeAnimalSaude, saudeID, animal.FazendaID, animalID, existing, nil)
 	return s.syncAnimalStatusSaude(ctx, animalID)
 }
 
@@ -35,6 +35,7 @@ type animalSaudeAtivosLister interface {
 }
 
 type AnimalService struct {
+	auditavel
 	repo              *repository.AnimalRepository
 	fazendaRepo       *repository.FazendaRepository
 	gestacaoRepo      *repository.GestacaoRepository
@@ -134,7 +135,11 @@ func (s *AnimalService) Create(ctx context.Context, animal *models.Animal) error
 		return err
 	}
 
-	return s.repo.Create(ctx, animal)
+	if err := s.repo.Create(ctx, animal); err != nil {
+		return err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoCreate, models.AuditoriaEntidadeAnimal, animal.ID, animal.FazendaID, animal.ID, nil, animal)
+	return nil
 }
 
 func (s *AnimalService) GetByID(ctx context.Context, id int64) (*models.Animal, error) {
@@ -291,7 +296,11 @@ func (s *AnimalService) Update(ctx context.Context, animal *models.Animal) error
 		return err
 	}
 
-	return s.repo.Update(ctx, animal)
+	if err := s.repo.Update(ctx, animal); err != nil {
+		return err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeAnimal, animal.ID, animal.FazendaID, animal.ID, existing, animal)
+	return nil
 }
 
 func (s *AnimalService) Delete(ctx context.Context, id int64) error {
@@ -307,7 +316,11 @@ func (s *AnimalService) Delete(ctx context.Context, id int64) error {
 		return err
 	}
 
-	return s.repo.Delete(ctx, id)
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoDelete, models.AuditoriaEntidadeAnimal, id, existing.FazendaID, id, existing, nil)
+	return nil
 }
 
 // equivalenteIdentificacao retorna a forma alternativa (número ↔ por extenso) para busca; "" se não houver.
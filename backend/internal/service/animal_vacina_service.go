@@ -40,6 +40,7 @@ type vacinaSaudeStore interface {
 }
 
 type AnimalVacinaService struct {
+	auditavel
 	repo           animalVacinaStore
 	animalRepo     vacinaAnimalStore
 	saudeRepo      vacinaSaudeStore
@@ -173,6 +174,7 @@ func (s *AnimalVacinaService) Create(ctx context.Context, animalID int64, in Sav
 	if err := s.repo.Create(ctx, row); err != nil {
 		return nil, err
 	}
+	s.auditar(ctx, models.AuditoriaAcaoCreate, models.AuditoriaEntidadeAnimalVacina, row.ID, animal.FazendaID, animalID, nil, row)
 	if row.DataAplicacao != nil {
 		s.afterAplicacao(ctx, animal, row)
 	}
@@ -204,6 +206,7 @@ func (s *AnimalVacinaService) Update(ctx context.Context, animalID, vacinaID int
 		return nil, ErrVacinaDataPrevistaObrigatoria
 	}
 
+	antes := *existing
 	existing.TipoVacina = in.TipoVacina
 	existing.Dose = in.Dose
 	existing.Lote = in.Lote
@@ -240,6 +243,7 @@ func (s *AnimalVacinaService) Update(ctx context.Context, animalID, vacinaID int
 		}
 		return nil, err
 	}
+	s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeAnimalVacina, existing.ID, animal.FazendaID, animalID, &antes, existing)
 	if !wasAplicada && existing.DataAplicacao != nil {
 		s.afterAplicacao(ctx, animal, existing)
 	}
@@ -269,6 +273,7 @@ func (s *AnimalVacinaService) Aplicar(ctx context.Context, animalID, vacinaID in
 	if err := validateVacinaDataAplicacao(animal, in.DataAplicacao); err != nil {
 		return nil, err
 	}
+	antes := *existing
 	aplicacao := TruncateToCivilDate(in.DataAplicacao)
 	existing.DataAplicacao = &aplicacao
 	if in.ValidadeDias != nil {
@@ -282,13 +287,22 @@ func (s *AnimalVacinaService) Aplicar(ctx context.Context, animalID, vacinaID in
 		}
 		return nil, err
 	}
+	s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeAnimalVacina, existing.ID, animal.FazendaID, animalID, &antes, existing)
 	s.afterAplicacao(ctx, animal, existing)
 	existing.Status = models.DeriveVacinaStatus(existing, CivilToday())
 	return existing, nil
 }
 
 func (s *AnimalVacinaService) Delete(ctx context.Context, animalID, vacinaID int64) error {
-	if _, err := s.ensureAnimalAtivo(ctx, animalID); err != nil {
+	animal, err := s.ensureAnimalAtivo(ctx, animalID)
+	if err != nil {
+		return err
+	}
+	existing, err := s.repo.GetByID(ctx, animalID, vacinaID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrVacinaNotFound
+		}
 		return err
 	}
 	if err := s.repo.Delete(ctx, animalID, vacinaID); err != nil {
@@ -297,6 +311,7 @@ func (s *AnimalVacinaService) Delete(ctx context.Context, animalID, vacinaID int
 		}
 		return err
 	}
+	s.auditar(ctx, models.AuditoriaAcaoDelete, models.AuditoriaEntidadeAnimalVacina, vacinaID, animal.FazendaID, animalID, existing, nil)
 	return nil
 }
 
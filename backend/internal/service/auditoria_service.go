@@ -0,0 +1,199 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/requestctx"
+)
+
+var (
+	ErrAuditoriaFiltroObrigatorio = errors.New("informe animal, usuario ou fazenda para consultar a auditoria")
+	ErrAuditoriaAcaoInvalida      = errors.New("acao invalida (CREATE, UPDATE, DELETE ou RESTORE)")
+)
+
+// camposIgnoradosAuditoria não entram no diff (carimbos técnicos e segredos).
+var camposIgnoradosAuditoria = map[string]struct{}{
+	"created_at": {},
+	"updated_at": {},
+	"senha":      {},
+	"password":   {},
+}
+
+type auditoriaStore interface {
+	Insert(ctx context.Context, e *models.AuditoriaEvento) error
+	List(ctx context.Context, f models.AuditoriaFiltro) ([]*models.AuditoriaEvento, int64, error)
+}
+
+// AuditoriaService mantém a trilha genérica de mutações (auditoria_eventos). O ator e o correlation ID
+// vêm do context.Context do pedido (requestctx); sem ator, o evento fica como PerfilSistema.
+type AuditoriaService struct {
+	repo auditoriaStore
+}
+
+func NewAuditoriaService(repo auditoriaStore) *AuditoriaService {
+	return &AuditoriaService{repo: repo}
+}
+
+// Registrar grava o evento. A auditoria não interrompe a mutação já concluída: falhas são apenas logadas.
+// Em UPDATE sem campos alterados nada é gravado. Seguro com receptor nil (auditoria desabilitada).
+func (s *AuditoriaService) Registrar(ctx context.Context, r models.AuditoriaRegistro) {
+	if s == nil || s.repo == nil {
+		return
+	}
+	diff, mudou, err := DiffAuditoria(r.Antes, r.Depois)
+	if err != nil {
+		slog.Warn("auditoria: diff falhou", "entidade", r.Entidade, "entidade_id", r.EntidadeID, "error", err)
+		return
+	}
+	if r.Acao == models.AuditoriaAcaoUpdate && !mudou {
+		return
+	}
+	diffJSON, err := json.Marshal(diff)
+	if err != nil {
+		slog.Warn("auditoria: serializar diff", "entidade", r.Entidade, "error", err)
+		return
+	}
+	e := &models.AuditoriaEvento{
+		Perfil:     models.PerfilSistema,
+		Entidade:   r.Entidade,
+		EntidadeID: r.EntidadeID,
+		Acao:       r.Acao,
+		Diff:       diffJSON,
+	}
+	if ator, ok := requestctx.AtorFromContext(ctx); ok && ator.UsuarioID > 0 {
+		uid := ator.UsuarioID
+		e.UsuarioID = &uid
+		if ator.Perfil != "" {
+			e.Perfil = ator.Perfil
+		}
+	}
+	if r.FazendaID > 0 {
+		fid := r.FazendaID
+		e.FazendaID = &fid
+	}
+	if r.AnimalID > 0 {
+		aid := r.AnimalID
+		e.AnimalID = &aid
+	}
+	if corr := requestctx.CorrelationIDFromContext(ctx); corr != "" {
+		e.CorrelationID = &corr
+	}
+	// Contexto próprio: o pedido pode ser cancelado logo após a resposta.
+	if err := s.repo.Insert(context.WithoutCancel(ctx), e); err != nil {
+		slog.Warn("auditoria: gravar evento falhou",
+			"entidade", r.Entidade, "entidade_id", r.EntidadeID, "acao", r.Acao, "error", err)
+	}
+}
+
+// List consulta a trilha; exige ao menos um escopo (animal, utilizador ou fazenda).
+func (s *AuditoriaService) List(ctx context.Context, f models.AuditoriaFiltro) ([]*models.AuditoriaEvento, int64, error) {
+	if f.AnimalID <= 0 && f.UsuarioID <= 0 && f.FazendaID <= 0 {
+		return nil, 0, ErrAuditoriaFiltroObrigatorio
+	}
+	if f.Acao != "" && !models.IsValidAuditoriaAcao(f.Acao) {
+		return nil, 0, ErrAuditoriaAcaoInvalida
+	}
+	list, total, err := s.repo.List(ctx, f)
+	if err != nil {
+		return nil, 0, err
+	}
+	if list == nil {
+		list = []*models.AuditoriaEvento{}
+	}
+	return list, total, nil
+}
+
+// DiffAuditoria compara as representações JSON de antes e depois campo a campo.
+// CREATE (antes nil) devolve todos os campos em Depois; DELETE (depois nil) todos em Antes;
+// UPDATE apenas os campos alterados. mudou=false quando não há diferença.
+func DiffAuditoria(antes, depois interface{}) (models.AuditoriaDiff, bool, error) {
+	var diff models.AuditoriaDiff
+	a, err := camposJSON(antes)
+	if err != nil {
+		return diff, false, err
+	}
+	d, err := camposJSON(depois)
+	if err != nil {
+		return diff, false, err
+	}
+	for k, va := range a {
+		vd, ok := d[k]
+		if ok && bytes.Equal(va, vd) {
+			continue
+		}
+		if diff.Antes == nil {
+			diff.Antes = map[string]json.RawMessage{}
+		}
+		diff.Antes[k] = va
+		if ok {
+			if diff.Depois == nil {
+				diff.Depois = map[string]json.RawMessage{}
+			}
+			diff.Depois[k] = vd
+		}
+	}
+	for k, vd := range d {
+		if _, ok := a[k]; ok {
+			continue
+		}
+		if diff.Depois == nil {
+			diff.Depois = map[string]json.RawMessage{}
+		}
+		diff.Depois[k] = vd
+	}
+	return diff, diff.Antes != nil || diff.Depois != nil, nil
+}
+
+func camposJSON(v interface{}) (map[string]json.RawMessage, error) {
+	out := map[string]json.RawMessage{}
+	if v == nil {
+		return out, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(raw, []byte("null")) {
+		return out, nil
+	}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, err
+	}
+	for k := range camposIgnoradosAuditoria {
+		delete(out, k)
+	}
+	for k, val := range out {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, val); err == nil {
+			out[k] = buf.Bytes()
+		}
+	}
+	return out, nil
+}
+
+// auditavel é embutido nos services com mutações auditadas (BR-AUDIT-012).
+type auditavel struct {
+	auditoria *AuditoriaService
+}
+
+// SetAuditoria habilita o registo na trilha de auditoria.
+func (a *auditavel) SetAuditoria(svc *AuditoriaService) {
+	a.auditoria = svc
+}
+
+func (a *auditavel) auditar(ctx context.Context, acao, entidade string, entidadeID, fazendaID, animalID int64, antes, depois interface{}) {
+	a.auditoria.Registrar(ctx, models.AuditoriaRegistro{
+		Acao:       acao,
+		Entidade:   entidade,
+		EntidadeID: entidadeID,
+		FazendaID:  fazendaID,
+		AnimalID:   animalID,
+		Antes:      antes,
+		Depois:     depois,
+	})
+}
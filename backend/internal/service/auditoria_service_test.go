@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/requestctx"
+)
+
+type fakeAuditoriaStore struct {
+	eventos []*models.AuditoriaEvento
+	filtro  models.AuditoriaFiltro
+}
+
+func (f *fakeAuditoriaStore) Insert(_ context.Context, e *models.AuditoriaEvento) error {
+	f.eventos = append(f.eventos, e)
+	return nil
+}
+
+func (f *fakeAuditoriaStore) List(_ context.Context, filtro models.AuditoriaFiltro) ([]*models.AuditoriaEvento, int64, error) {
+	f.filtro = filtro
+	return nil, 0, nil
+}
+
+type auditoriaTestRow struct {
+	ID        int64   `json:"id"`
+	Nome      string  `json:"nome"`
+	Obs       *string `json:"obs"`
+	UpdatedAt string  `json:"updated_at"`
+}
+
+func TestDiffAuditoria_CreateUpdateDelete(t *testing.T) {
+	obs := "x"
+	antes := &auditoriaTestRow{ID: 1, Nome: "Mimosa", Obs: &obs, UpdatedAt: "ontem"}
+	depois := &auditoriaTestRow{ID: 1, Nome: "Mimosa II", Obs: &obs, UpdatedAt: "hoje"}
+
+	diff, mudou, err := DiffAuditoria(nil, depois)
+	if err != nil || !mudou {
+		t.Fatalf("create: mudou=%v err=%v", mudou, err)
+	}
+	if diff.Antes != nil || len(diff.Depois) != 3 {
+		t.Fatalf("create: esperado 3 campos em depois, got antes=%v depois=%v", diff.Antes, diff.Depois)
+	}
+	if _, ok := diff.Depois["updated_at"]; ok {
+		t.Fatal("updated_at não deve entrar no diff")
+	}
+
+	diff, mudou, err = DiffAuditoria(antes, depois)
+	if err != nil || !mudou {
+		t.Fatalf("update: mudou=%v err=%v", mudou, err)
+	}
+	if len(diff.Antes) != 1 || len(diff.Depois) != 1 {
+		t.Fatalf("update: esperado só nome, got antes=%v depois=%v", diff.Antes, diff.Depois)
+	}
+	if string(diff.Antes["nome"]) != `"Mimosa"` || string(diff.Depois["nome"]) != `"Mimosa II"` {
+		t.Fatalf("update: nome antes=%s depois=%s", diff.Antes["nome"], diff.Depois["nome"])
+	}
+
+	diff, mudou, err = DiffAuditoria(antes, nil)
+	if err != nil || !mudou {
+		t.Fatalf("delete: mudou=%v err=%v", mudou, err)
+	}
+	if diff.Depois != nil || len(diff.Antes) != 3 {
+		t.Fatalf("delete: esperado 3 campos em antes, got antes=%v depois=%v", diff.Antes, diff.Depois)
+	}
+
+	igual := *antes
+	igual.UpdatedAt = "agora"
+	if _, mudou, _ := DiffAuditoria(antes, &igual); mudou {
+		t.Fatal("update sem alteração (exceto carimbos) não deve contar como mudança")
+	}
+}
+
+func TestAuditoriaRegistrar_AtorECorrelation(t *testing.T) {
+	store := &fakeAuditoriaStore{}
+	svc := NewAuditoriaService(store)
+
+	ctx := requestctx.WithAtor(context.Background(), requestctx.Ator{UsuarioID: 7, Perfil: models.PerfilGerente})
+	ctx = requestctx.WithCorrelationID(ctx, "corr-1")
+	svc.Registrar(ctx, models.AuditoriaRegistro{
+		Acao:       models.AuditoriaAcaoCreate,
+		Entidade:   models.AuditoriaEntidadeCio,
+		EntidadeID: 10,
+		FazendaID:  3,
+		AnimalID:   5,
+		Depois:     &auditoriaTestRow{ID: 10, Nome: "cio"},
+	})
+
+	if len(store.eventos) != 1 {
+		t.Fatalf("esperado 1 evento, got %d", len(store.eventos))
+	}
+	e := store.eventos[0]
+	if e.UsuarioID == nil || *e.UsuarioID != 7 || e.Perfil != models.PerfilGerente {
+		t.Fatalf("ator inesperado: usuario=%v perfil=%s", e.UsuarioID, e.Perfil)
+	}
+	if e.CorrelationID == nil || *e.CorrelationID != "corr-1" {
+		t.Fatalf("correlation inesperado: %v", e.CorrelationID)
+	}
+	if e.FazendaID == nil || *e.FazendaID != 3 || e.AnimalID == nil || *e.AnimalID != 5 {
+		t.Fatalf("escopo inesperado: fazenda=%v animal=%v", e.FazendaID, e.AnimalID)
+	}
+	var diff models.AuditoriaDiff
+	if err := json.Unmarshal(e.Diff, &diff); err != nil {
+		t.Fatalf("diff inválido: %v", err)
+	}
+	if string(diff.Depois["nome"]) != `"cio"` {
+		t.Fatalf("diff depois inesperado: %s", e.Diff)
+	}
+}
+
+func TestAuditoriaRegistrar_SistemaEUpdateSemMudanca(t *testing.T) {
+	store := &fakeAuditoriaStore{}
+	svc := NewAuditoriaService(store)
+	row := &auditoriaTestRow{ID: 1, Nome: "a"}
+
+	svc.Registrar(context.Background(), models.AuditoriaRegistro{
+		Acao: models.AuditoriaAcaoUpdate, Entidade: models.AuditoriaEntidadeLote, EntidadeID: 1, Antes: row, Depois: row,
+	})
+	if len(store.eventos) != 0 {
+		t.Fatalf("update sem alteração não deve gravar, got %d", len(store.eventos))
+	}
+
+	svc.Registrar(context.Background(), models.AuditoriaRegistro{
+		Acao: models.AuditoriaAcaoDelete, Entidade: models.AuditoriaEntidadeLote, EntidadeID: 1, Antes: row,
+	})
+	if len(store.eventos) != 1 {
+		t.Fatalf("esperado 1 evento, got %d", len(store.eventos))
+	}
+	e := store.eventos[0]
+	if e.UsuarioID != nil || e.Perfil != models.PerfilSistema || e.CorrelationID != nil {
+		t.Fatalf("sem ator esperado SISTEMA, got usuario=%v perfil=%s", e.UsuarioID, e.Perfil)
+	}
+
+	var nilSvc *AuditoriaService
+	nilSvc.Registrar(context.Background(), models.AuditoriaRegistro{Acao: models.AuditoriaAcaoCreate})
+}
+
+func TestAuditoriaList_ExigeEscopoEAcaoValida(t *testing.T) {
+	svc := NewAuditoriaService(&fakeAuditoriaStore{})
+	ctx := context.Background()
+
+	if _, _, err := svc.List(ctx, models.AuditoriaFiltro{Entidade: models.AuditoriaEntidadeParto}); !errors.Is(err, ErrAuditoriaFiltroObrigatorio) {
+		t.Fatalf("esperado ErrAuditoriaFiltroObrigatorio, got %v", err)
+	}
+	if _, _, err := svc.List(ctx, models.AuditoriaFiltro{FazendaID: 1, Acao: "PATCH"}); !errors.Is(err, ErrAuditoriaAcaoInvalida) {
+		t.Fatalf("esperado ErrAuditoriaAcaoInvalida, got %v", err)
+	}
+	if _, _, err := svc.List(ctx, models.AuditoriaFiltro{FazendaID: 1, Acao: models.AuditoriaAcaoRestore}); err != nil {
+		t.Fatalf("RESTORE deve ser aceite, got %v", err)
+	}
+	list, _, err := svc.List(ctx, models.AuditoriaFiltro{AnimalID: 2})
+	if err != nil || list == nil {
+		t.Fatalf("esperado lista vazia não nil, got %v err=%v", list, err)
+	}
+}
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	calendarioFeedsMax = 10
+	// Janela publicada no .ics: um mês para trás e seis à frente (a escala é gerada por mês).
+	calendarioDiasPassado = 31
+	calendarioDiasFuturo  = 186
+)
+
+var (
+	ErrCalendarioEscopo       = errors.New("escopo deve ser PESSOAL ou FAZENDA")
+	ErrCalendarioSemAcesso    = errors.New("você não tem acesso a esta fazenda")
+	ErrCalendarioSoGestao     = errors.New("a assinatura da escala completa da fazenda é exclusiva da gestão")
+	ErrCalendarioLimite       = fmt.Errorf("limite de %d assinaturas de calendário por usuário atingido: revogue uma antes de criar outra", calendarioFeedsMax)
+	ErrCalendarioFeedNotFound = errors.New("assinatura de calendário não encontrada")
+)
+
+// CalendarioService assinaturas iCal da escala de folgas (BR-FOLGAS-011): o token na URL substitui o
+// login, porque o app de calendário do celular não envia JWT.
+type CalendarioService struct {
+	repo        *repository.CalendarioRepository
+	folgasRepo  *repository.FolgasRepository
+	usuarioRepo *repository.UsuarioRepository
+	fazendaSvc  *FazendaService
+	// tarefaRepo opcional: tarefas abertas atribuídas entram no feed PESSOAL (BR-TAREFA-004).
+	tarefaRepo *repository.TarefaRepository
+	now        func() time.Time
+}
+
+func NewCalendarioService(repo *repository.CalendarioRepository, folgasRepo *repository.FolgasRepository, usuarioRepo *repository.UsuarioRepository, fazendaSvc *FazendaService) *CalendarioService {
+	return &CalendarioService{repo: repo, folgasRepo: folgasRepo, usuarioRepo: usuarioRepo, fazendaSvc: fazendaSvc, now: time.Now}
+}
+
+func (s *CalendarioService) SetTarefaRepository(repo *repository.TarefaRepository) {
+	s.tarefaRepo = repo
+}
+
+// calendarioEvento dia inteiro (ou intervalo de dias, Fim inclusivo) publicado no .ics.
+type calendarioEvento struct {
+	UID       string
+	Inicio    time.Time
+	Fim       time.Time
+	Resumo    string
+	Descricao string
+}
+
+var calendarioAusenciaLabel = map[string]string{
+	models.FolgaAusenciaFerias:               "Férias",
+	models.FolgaAusenciaAtestado:             "Atestado",
+	models.FolgaAusenciaLicencaNaoRemunerada: "Licença não remunerada",
+}
+
+// eventosEscala converte a escala em eventos: no escopo PESSOAL só as folgas e ausências do usuário,
+// no FAZENDA as de todos, com o nome de cada um no título.
+func eventosEscala(escopo string, usuarioID int64, fazendaNome string, linhas []models.EscalaFolga, ausencias []models.FolgaAusencia) []calendarioEvento {
+	pessoal := escopo == models.CalendarioEscopoPessoal
+	titulo := func(base, nome string, id int64) string {
+		if pessoal {
+			return base
+		}
+		if strings.TrimSpace(nome) == "" {
+			nome = fmt.Sprintf("#%d", id)
+		}
+		return base + ": " + nome
+	}
+	var out []calendarioEvento
+	for _, l := range linhas {
+		if pessoal && l.UsuarioID != usuarioID {
+			continue
+		}
+		desc := fazendaNome
+		if l.Origem == models.FolgaOrigemManual && l.Motivo != nil && strings.TrimSpace(*l.Motivo) != "" {
+			desc += "\n" + strings.TrimSpace(*l.Motivo)
+		}
+		out = append(out, calendarioEvento{
+			UID:       fmt.Sprintf("folga-%d@ceialmilk", l.ID),
+			Inicio:    l.Data,
+			Fim:       l.Data,
+			Resumo:    titulo("Folga", l.UsuarioNome, l.UsuarioID),
+			Descricao: desc,
+		})
+	}
+	for _, a := range ausencias {
+		if pessoal && a.UsuarioID != usuarioID {
+			continue
+		}
+		label, ok := calendarioAusenciaLabel[a.Tipo]
+		if !ok {
+			label = "Ausente"
+		}
+		out = append(out, calendarioEvento{
+			UID:       fmt.Sprintf("ausencia-%d@ceialmilk", a.ID),
+			Inicio:    a.DataInicio,
+			Fim:       a.DataFim,
+			Resumo:    titulo(label, a.UsuarioNome, a.UsuarioID),
+			Descricao: fazendaNome,
+		})
+	}
+	return out
+}
+
+// eventosTarefas tarefas atribuídas ao usuário como eventos de dia inteiro na data prevista.
+func eventosTarefas(fazendaNome string, tarefas []models.TarefaWithNames) []calendarioEvento {
+	out := make([]calendarioEvento, 0, len(tarefas))
+	for _, t := range tarefas {
+		desc := fazendaNome
+		if t.Descricao != nil && strings.TrimSpace(*t.Descricao) != "" {
+			desc += "\n" + strings.TrimSpace(*t.Descricao)
+		}
+		out = append(out, calendarioEvento{
+			UID:       fmt.Sprintf("tarefa-%d@ceialmilk", t.ID),
+			Inicio:    t.DataPrevista,
+			Fim:       t.DataPrevista,
+			Resumo:    "Tarefa: " + t.Titulo,
+			Descricao: desc,
+		})
+	}
+	return out
+}
+
+// escaparTextoICS aplica o escape de TEXT da RFC 5545 (barra, ponto e vírgula, vírgula e quebra de linha).
+func escaparTextoICS(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", "").Replace(s)
+}
+
+// dobrarLinhaICS quebra linhas acima de 75 octetos (RFC 5545 §3.1) sem partir caracteres UTF-8;
+// cada continuação começa com espaço.
+func dobrarLinhaICS(linha string) string {
+	const max = 75
+	if len(linha) <= max {
+		return linha + "\r\n"
+	}
+	var b strings.Builder
+	limite := max
+	n := 0
+	for _, r := range linha {
+		rl := len(string(r))
+		if n+rl > limite {
+			b.WriteString("\r\n ")
+			n = 0
+			limite = max - 1
+		}
+		b.WriteRune(r)
+		n += rl
+	}
+	b.WriteString("\r\n")
+	return b.String()
+}
+
+// montarICS gera o VCALENDAR com eventos de dia inteiro (DTEND exclusivo, dia seguinte ao fim).
+func montarICS(nome string, eventos []calendarioEvento, agora time.Time) string {
+	var b strings.Builder
+	linha := func(s string) { b.WriteString(dobrarLinhaICS(s)) }
+	stamp := agora.UTC().Format("20060102T150405Z")
+	linha("BEGIN:VCALENDAR")
+	linha("VERSION:2.0")
+	linha("PRODID:-//CeialMilk//Escala de folgas//PT-BR")
+	linha("CALSCALE:GREGORIAN")
+	linha("METHOD:PUBLISH")
+	linha("X-WR-CALNAME:" + escaparTextoICS(nome))
+	linha("X-PUBLISHED-TTL:PT6H")
+	linha("REFRESH-INTERVAL;VALUE=DURATION:PT6H")
+	for _, e := range eventos {
+		linha("BEGIN:VEVENT")
+		linha("UID:" + e.UID)
+		linha("DTSTAMP:" + stamp)
+		linha("DTSTART;VALUE=DATE:" + e.Inicio.Format("20060102"))
+		linha("DTEND;VALUE=DATE:" + e.Fim.AddDate(0, 0, 1).Format("20060102"))
+		linha("SUMMARY:" + escaparTextoICS(e.Resumo))
+		if e.Descricao != "" {
+			linha("DESCRIPTION:" + escaparTextoICS(e.Descricao))
+		}
+		linha("TRANSP:TRANSPARENT")
+		linha("END:VEVENT")
+	}
+	linha("END:VCALENDAR")
+	return b.String()
+}
+
+// gerarTokenCalendario devolve o token em claro (vai na URL) e o prefixo exibido na listagem.
+func gerarTokenCalendario() (token, prefixo string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, token[:8], nil
+}
+
+// temAcessoFazenda repete a regra de validarAcessoFazenda das folgas, a partir do usuário do token.
+func (s *CalendarioService) temAcessoFazenda(ctx context.Context, usuarioID int64, perfil string, fazendaID int64) (bool, error) {
+	if models.PodeAcessarFazendaSemVinculoGestao(perfil) {
+		if _, err := s.fazendaSvc.GetByID(ctx, fazendaID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	}
+	return s.folgasRepo.UsuarioTemFazenda(ctx, usuarioID, fazendaID)
+}
+
+// CriarFeed cria uma assinatura e devolve o token em claro, que não fica guardado.
+func (s *CalendarioService) CriarFeed(ctx context.Context, usuarioID int64, perfil string, fazendaID int64, escopo string) (*models.CalendarioFeed, string, error) {
+	if !models.IsValidCalendarioEscopo(escopo) {
+		return nil, "", ErrCalendarioEscopo
+	}
+	if escopo == models.CalendarioEscopoFazenda && !models.PodeGerenciarFolgas(perfil) {
+		return nil, "", ErrCalendarioSoGestao
+	}
+	ok, err := s.temAcessoFazenda(ctx, usuarioID, perfil, fazendaID)
+	if err != nil {
+		return nil, "", err
+	}
+	if !ok {
+		return nil, "", ErrCalendarioSemAcesso
+	}
+	n, err := s.repo.CountByUsuario(ctx, usuarioID)
+	if err != nil {
+		return nil, "", err
+	}
+	if n >= calendarioFeedsMax {
+		return nil, "", ErrCalendarioLimite
+	}
+	token, prefixo, err := gerarTokenCalendario()
+	if err != nil {
+		return nil, "", err
+	}
+	f := &models.CalendarioFeed{UsuarioID: usuarioID, FazendaID: fazendaID, Escopo: escopo, TokenPrefixo: prefixo}
+	if err := s.repo.Create(ctx, f, hashRefreshToken(token)); err != nil {
+		return nil, "", err
+	}
+	if fz, err := s.fazendaSvc.GetByID(ctx, fazendaID); err == nil {
+		f.FazendaNome = fz.Nome
+	}
+	return f, token, nil
+}
+
+func (s *CalendarioService) ListFeeds(ctx context.Context, usuarioID int64) ([]models.CalendarioFeed, error) {
+	return s.repo.ListByUsuario(ctx, usuarioID)
+}
+
+func (s *CalendarioService) RevogarFeed(ctx context.Context, usuarioID, id int64) error {
+	if err := s.repo.Delete(ctx, id, usuarioID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrCalendarioFeedNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// RevogarTodos apaga todas as assinaturas do usuário (ex.: celular perdido).
+func (s *CalendarioService) RevogarTodos(ctx context.Context, usuarioID int64) (int64, error) {
+	return s.repo.DeleteByUsuario(ctx, usuarioID)
+}
+
+// GerarICS resolve o token e monta o calendário. Usuário desativado, vínculo removido ou perda do
+// perfil de gestão (escopo FAZENDA) tornam o token inválido sem precisar revogá-lo.
+func (s *CalendarioService) GerarICS(ctx context.Context, token string) (string, error) {
+	if token == "" {
+		return "", ErrCalendarioFeedNotFound
+	}
+	f, err := s.repo.GetByTokenHash(ctx, hashRefreshToken(token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrCalendarioFeedNotFound
+		}
+		return "", err
+	}
+	u, err := s.usuarioRepo.GetByID(ctx, f.UsuarioID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrCalendarioFeedNotFound
+		}
+		return "", err
+	}
+	if !u.Enabled || (f.Escopo == models.CalendarioEscopoFazenda && !models.PodeGerenciarFolgas(u.Perfil)) {
+		return "", ErrCalendarioFeedNotFound
+	}
+	ok, err := s.temAcessoFazenda(ctx, u.ID, u.Perfil, f.FazendaID)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", ErrCalendarioFeedNotFound
+	}
+
+	agora := s.now()
+	hoje := truncateDateUTC(agora)
+	inicio := hoje.AddDate(0, 0, -calendarioDiasPassado)
+	fim := hoje.AddDate(0, 0, calendarioDiasFuturo)
+	linhas, err := s.folgasRepo.ListEscalaRange(ctx, f.FazendaID, inicio, fim)
+	if err != nil {
+		return "", err
+	}
+	var filtro *int64
+	if f.Escopo == models.CalendarioEscopoPessoal {
+		filtro = &u.ID
+	}
+	ausencias, err := s.folgasRepo.ListAusenciasRange(ctx, f.FazendaID, inicio, fim, filtro)
+	if err != nil {
+		return "", err
+	}
+	eventos := eventosEscala(f.Escopo, u.ID, f.FazendaNome, linhas, ausencias)
+	if f.Escopo == models.CalendarioEscopoPessoal && s.tarefaRepo != nil {
+		tarefas, _, err := s.tarefaRepo.ListByFazenda(ctx, f.FazendaID, repository.TarefaListFilters{
+			Abertas: true, ResponsavelID: &u.ID, Inicio: &inicio, Fim: &fim, Limit: 500,
+		})
+		if err != nil {
+			return "", err
+		}
+		eventos = append(eventos, eventosTarefas(f.FazendaNome, tarefas)...)
+	}
+	_ = s.repo.TouchUltimoUso(ctx, f.ID, agora)
+
+	nome := "Minhas folgas — " + f.FazendaNome
+	if f.Escopo == models.CalendarioEscopoFazenda {
+		nome = "Escala de folgas — " + f.FazendaNome
+	}
+	return montarICS(nome, eventos, agora), nil
+}
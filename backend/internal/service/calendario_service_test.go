@@ -0,0 +1,115 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ceialmilk/api/internal/models"
+)
+
+func TestEscaparTextoICS(t *testing.T) {
+	got := escaparTextoICS("Troca; motivo, ver\\nota\nlinha 2")
+	want := `Troca\; motivo\, ver\\nota\nlinha 2`
+	if got != want {
+		t.Fatalf("got %q want %q", got, want)
+	}
+}
+
+func TestDobrarLinhaICS(t *testing.T) {
+	if got := dobrarLinhaICS("SUMMARY:Folga"); got != "SUMMARY:Folga\r\n" {
+		t.Fatalf("linha curta alterada: %q", got)
+	}
+	longa := "DESCRIPTION:" + strings.Repeat("ação ", 40)
+	dobrada := dobrarLinhaICS(longa)
+	partes := strings.Split(strings.TrimSuffix(dobrada, "\r\n"), "\r\n")
+	if len(partes) < 2 {
+		t.Fatalf("linha longa não foi dobrada: %q", dobrada)
+	}
+	var junta strings.Builder
+	for i, p := range partes {
+		if len(p) > 75 {
+			t.Errorf("parte %d com %d octetos", i, len(p))
+		}
+		if !utf8.ValidString(p) {
+			t.Errorf("parte %d partiu caractere UTF-8: %q", i, p)
+		}
+		if i > 0 {
+			if !strings.HasPrefix(p, " ") {
+				t.Errorf("continuação %d sem espaço inicial", i)
+			}
+			p = p[1:]
+		}
+		junta.WriteString(p)
+	}
+	if junta.String() != longa {
+		t.Fatal("desdobrar não recupera a linha original")
+	}
+}
+
+func TestEventosEscala(t *testing.T) {
+	d := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	motivo := "Consulta médica"
+	linhas := []models.EscalaFolga{
+		{ID: 7, Data: d, UsuarioID: 1, UsuarioNome: "Ana", Origem: models.FolgaOrigemManual, Motivo: &motivo},
+		{ID: 8, Data: d, UsuarioID: 2, UsuarioNome: "Bruno", Origem: models.FolgaOrigemAuto},
+	}
+	ausencias := []models.FolgaAusencia{
+		{ID: 3, UsuarioID: 2, UsuarioNome: "Bruno", Tipo: models.FolgaAusenciaFerias, DataInicio: d.AddDate(0, 0, 1), DataFim: d.AddDate(0, 0, 10)},
+	}
+
+	pessoal := eventosEscala(models.CalendarioEscopoPessoal, 1, "Sítio Boa Vista", linhas, ausencias)
+	if len(pessoal) != 1 || pessoal[0].UID != "folga-7@ceialmilk" || pessoal[0].Resumo != "Folga" {
+		t.Fatalf("pessoal = %+v", pessoal)
+	}
+	if pessoal[0].Descricao != "Sítio Boa Vista\nConsulta médica" {
+		t.Errorf("descrição = %q", pessoal[0].Descricao)
+	}
+
+	fazenda := eventosEscala(models.CalendarioEscopoFazenda, 1, "Sítio Boa Vista", linhas, ausencias)
+	if len(fazenda) != 3 {
+		t.Fatalf("fazenda = %+v", fazenda)
+	}
+	if fazenda[1].Resumo != "Folga: Bruno" || fazenda[2].Resumo != "Férias: Bruno" {
+		t.Errorf("títulos = %q, %q", fazenda[1].Resumo, fazenda[2].Resumo)
+	}
+}
+
+func TestEventosTarefas(t *testing.T) {
+	d := time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC)
+	desc := "Trocar filtro"
+	ev := eventosTarefas("Sítio", []models.TarefaWithNames{
+		{Tarefa: models.Tarefa{ID: 4, Titulo: "Limpar tanque", Descricao: &desc, DataPrevista: d}},
+	})
+	if len(ev) != 1 || ev[0].UID != "tarefa-4@ceialmilk" || ev[0].Resumo != "Tarefa: Limpar tanque" {
+		t.Fatalf("eventos = %+v", ev)
+	}
+	if !ev[0].Inicio.Equal(d) || !ev[0].Fim.Equal(d) || ev[0].Descricao != "Sítio\nTrocar filtro" {
+		t.Errorf("evento = %+v", ev[0])
+	}
+}
+
+func TestMontarICS(t *testing.T) {
+	d := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	agora := time.Date(2026, 10, 18, 9, 30, 0, 0, time.FixedZone("BRT", -3*3600))
+	ics := montarICS("Minhas folgas — Sítio", []calendarioEvento{
+		{UID: "ausencia-3@ceialmilk", Inicio: d, Fim: d.AddDate(0, 0, 2), Resumo: "Férias"},
+	}, agora)
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"X-WR-CALNAME:Minhas folgas — Sítio\r\n",
+		"DTSTAMP:20261018T123000Z\r\n",
+		"DTSTART;VALUE=DATE:20261020\r\n",
+		"DTEND;VALUE=DATE:20261023\r\n", // fim exclusivo: último dia 22 + 1
+		"SUMMARY:Férias\r\n",
+		"END:VEVENT\r\nEND:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("ics sem %q:\n%s", want, ics)
+		}
+	}
+	if strings.Contains(ics, "DESCRIPTION:") {
+		t.Error("DESCRIPTION vazia não deve ser emitida")
+	}
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/repository"
+)
+
+// derivarStatusReprodutivo aplica BR-CICLO-002 ao marco reprodutivo mais recente.
+// Retorna nil quando não há marco (status atual é mantido).
+func derivarStatusReprodutivo(m *repository.MarcoReprodutivo) *string {
+	if m == nil {
+		return nil
+	}
+	var status string
+	switch {
+	case m.Tipo == "SECAGEM":
+		status = models.StatusReprodutivoSeca
+	case m.GestacaoAtiva:
+		status = models.StatusReprodutivoPrenhe
+	case m.Tipo == "PARTO":
+		status = models.StatusReprodutivoParida
+	case m.Tipo == "COBERTURA":
+		status = models.StatusReprodutivoServida
+	default:
+		// CIO, toque negativo ou toque positivo cuja gestação já não está ativa.
+		status = models.StatusReprodutivoVazia
+	}
+	return &status
+}
+
+// RecalcularStatusReprodutivo recalcula o status reprodutivo a partir dos eventos fora da lixeira
+// (usado ao restaurar cio, cobertura ou parto).
+func RecalcularStatusReprodutivo(ctx context.Context, animalRepo *repository.AnimalRepository, animalID int64) error {
+	m, err := animalRepo.GetUltimoMarcoReprodutivo(ctx, animalID)
+	if err != nil {
+		return err
+	}
+	status := derivarStatusReprodutivo(m)
+	if status == nil {
+		return nil
+	}
+	return animalRepo.UpdateStatusReprodutivo(ctx, animalID, status)
+}
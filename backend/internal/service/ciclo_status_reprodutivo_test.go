@@ -0,0 +1,34 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/repository"
+)
+
+func TestDerivarStatusReprodutivo(t *testing.T) {
+	cases := []struct {
+		nome  string
+		marco *repository.MarcoReprodutivo
+		want  string
+	}{
+		{"cio", &repository.MarcoReprodutivo{Tipo: "CIO"}, models.StatusReprodutivoVazia},
+		{"cobertura", &repository.MarcoReprodutivo{Tipo: "COBERTURA"}, models.StatusReprodutivoServida},
+		{"toque negativo", &repository.MarcoReprodutivo{Tipo: "TOQUE", Resultado: models.DiagnosticoResultadoNegativo}, models.StatusReprodutivoVazia},
+		{"toque positivo com gestação", &repository.MarcoReprodutivo{Tipo: "TOQUE", Resultado: models.DiagnosticoResultadoPositivo, GestacaoAtiva: true}, models.StatusReprodutivoPrenhe},
+		{"toque positivo sem gestação ativa", &repository.MarcoReprodutivo{Tipo: "TOQUE", Resultado: models.DiagnosticoResultadoPositivo}, models.StatusReprodutivoVazia},
+		{"cio com gestação ativa mantém prenhe", &repository.MarcoReprodutivo{Tipo: "CIO", GestacaoAtiva: true}, models.StatusReprodutivoPrenhe},
+		{"parto", &repository.MarcoReprodutivo{Tipo: "PARTO"}, models.StatusReprodutivoParida},
+		{"secagem prevalece sobre gestação", &repository.MarcoReprodutivo{Tipo: "SECAGEM", GestacaoAtiva: true}, models.StatusReprodutivoSeca},
+	}
+	for _, tc := range cases {
+		got := derivarStatusReprodutivo(tc.marco)
+		if got == nil || *got != tc.want {
+			t.Fatalf("%s: esperado %s, got %v", tc.nome, tc.want, got)
+		}
+	}
+	if got := derivarStatusReprodutivo(nil); got != nil {
+		t.Fatalf("sem marco deve manter o status, got %s", *got)
+	}
+}
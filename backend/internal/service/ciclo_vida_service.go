@@ -0,0 +1,481 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrCicloVidaForbidden       = errors.New("perfil sem permissão para gerir o ciclo de vida e as propostas de lote")
+	ErrCicloVidaConfigInvalida  = errors.New("configuração do ciclo de vida inválida")
+	ErrPropostaLoteIDsInvalidos = errors.New("informe entre 1 e 200 propostas")
+)
+
+// loteTipoPorMotivo tipo do lote de destino de cada motivo de proposta.
+var loteTipoPorMotivo = map[string]string{
+	models.PropostaLoteMotivoPreParto: models.LoteTipoPreParto,
+	models.PropostaLoteMotivoRecria:   models.LoteTipoRecria,
+	models.PropostaLoteMotivoEngorda:  models.LoteTipoEngorda,
+}
+
+// propostaLoteMaxDecisao máximo de propostas por pedido de aprovação/rejeição em lote.
+const propostaLoteMaxDecisao = 200
+
+type cicloVidaStore interface {
+	GetConfig(ctx context.Context, fazendaID int64) (*models.CicloVidaConfig, error)
+	UpsertConfig(ctx context.Context, c *models.CicloVidaConfig) error
+	ListAnimais(ctx context.Context, fazendaID int64) ([]models.AnimalCicloVida, error)
+	ListPropostas(ctx context.Context, fazendaID int64, status string, limit int) ([]*models.MovimentacaoLoteProposta, error)
+	GetPropostasByIDs(ctx context.Context, fazendaID int64, ids []int64) ([]*models.MovimentacaoLoteProposta, error)
+	CreateProposta(ctx context.Context, p *models.MovimentacaoLoteProposta) (bool, error)
+	DecidirProposta(ctx context.Context, fazendaID, id int64, status string, decididoPor *int64) error
+	ReabrirProposta(ctx context.Context, id int64) error
+	SetPropostaMovimentacao(ctx context.Context, id, movimentacaoID int64) error
+}
+
+type cicloVidaLotes interface {
+	GetByFazendaID(ctx context.Context, fazendaID int64) ([]*models.Lote, error)
+}
+
+type cicloVidaAnimais interface {
+	GetByID(ctx context.Context, id int64) (*models.Animal, error)
+	UpdateCategoria(ctx context.Context, animalID int64, categoria *string) error
+}
+
+type cicloVidaMovimentacoes interface {
+	Create(ctx context.Context, m *models.MovimentacaoLote) error
+	AvisosCapacidade(ctx context.Context, loteID int64) []string
+}
+
+// CicloVidaService motor diário de transições de categoria e sugestões de lote (BR-LOTE-005/006).
+// Transições de categoria são aplicadas de imediato (auditadas); mudanças de lote ficam como propostas
+// até a gestão aprovar.
+type CicloVidaService struct {
+	auditavel
+	repo          cicloVidaStore
+	lotes         cicloVidaLotes
+	animais       cicloVidaAnimais
+	movimentacoes cicloVidaMovimentacoes
+	fazendaRepo   *repository.FazendaRepository
+}
+
+func NewCicloVidaService(repo *repository.CicloVidaRepository, loteRepo *repository.LoteRepository, animalRepo *repository.AnimalRepository, movimentacaoSvc *MovimentacaoLoteService, fazendaRepo *repository.FazendaRepository) *CicloVidaService {
+	return &CicloVidaService{repo: repo, lotes: loteRepo, animais: animalRepo, movimentacoes: movimentacaoSvc, fazendaRepo: fazendaRepo}
+}
+
+// GetConfig configuração efetiva da fazenda (padrão quando não personalizada).
+func (s *CicloVidaService) GetConfig(ctx context.Context, fazendaID int64) (*models.CicloVidaConfig, error) {
+	cfg, err := s.repo.GetConfig(ctx, fazendaID)
+	if err != nil || cfg != nil {
+		return cfg, err
+	}
+	return models.CicloVidaConfigPadrao(fazendaID), nil
+}
+
+type UpdateCicloVidaConfigInput struct {
+	NovilhaIdadeMeses int
+	NovilhaPesoKg     *float64
+	MachoIdadeMeses   int
+	MachoCategoria    string
+	PrePartoDias      int
+	ActorUserID       int64
+	Perfil            string
+}
+
+func (s *CicloVidaService) UpdateConfig(ctx context.Context, fazendaID int64, in UpdateCicloVidaConfigInput) (*models.CicloVidaConfig, error) {
+	if !models.PodeGerirCicloVida(in.Perfil) {
+		return nil, ErrCicloVidaForbidden
+	}
+	if in.NovilhaIdadeMeses < 1 || in.NovilhaIdadeMeses > 60 || in.MachoIdadeMeses < 1 || in.MachoIdadeMeses > 60 ||
+		in.PrePartoDias < 1 || in.PrePartoDias > 120 ||
+		(in.NovilhaPesoKg != nil && *in.NovilhaPesoKg <= 0) ||
+		(in.MachoCategoria != models.CategoriaBoi && in.MachoCategoria != models.CategoriaTouro) {
+		return nil, ErrCicloVidaConfigInvalida
+	}
+	cfg := &models.CicloVidaConfig{
+		FazendaID:         fazendaID,
+		NovilhaIdadeMeses: in.NovilhaIdadeMeses,
+		NovilhaPesoKg:     in.NovilhaPesoKg,
+		MachoIdadeMeses:   in.MachoIdadeMeses,
+		MachoCategoria:    in.MachoCategoria,
+		PrePartoDias:      in.PrePartoDias,
+		Personalizada:     true,
+		UpdatedBy:         &in.ActorUserID,
+	}
+	if err := s.repo.UpsertConfig(ctx, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// ResultadoCicloVida contagens de uma execução do motor.
+type ResultadoCicloVida struct {
+	FazendasProcessadas int `json:"fazendas_processadas"`
+	Reclassificados     int `json:"reclassificados"`
+	PropostasCriadas    int `json:"propostas_criadas"`
+	PropostasObsoletas  int `json:"propostas_obsoletas"`
+}
+
+func (r *ResultadoCicloVida) somar(o ResultadoCicloVida) {
+	r.FazendasProcessadas += o.FazendasProcessadas
+	r.Reclassificados += o.Reclassificados
+	r.PropostasCriadas += o.PropostasCriadas
+	r.PropostasObsoletas += o.PropostasObsoletas
+}
+
+// ExecutarTodas corre o motor em todas as fazendas (job diário). Falha numa fazenda não trava as demais.
+func (s *CicloVidaService) ExecutarTodas(ctx context.Context, ref time.Time) (ResultadoCicloVida, error) {
+	var total ResultadoCicloVida
+	fazendas, err := s.fazendaRepo.GetAll(ctx)
+	if err != nil {
+		return total, err
+	}
+	for _, f := range fazendas {
+		if f == nil {
+			continue
+		}
+		res, err := s.ExecutarFazenda(ctx, f.ID, ref)
+		if err != nil {
+			if ctx.Err() != nil {
+				return total, err
+			}
+			slog.Warn("ciclo de vida: fazenda falhou", "fazenda_id", f.ID, "error", err)
+			continue
+		}
+		total.somar(res)
+	}
+	return total, nil
+}
+
+// ExecutarFazenda aplica as transições de categoria e sincroniza as propostas de lote da fazenda.
+func (s *CicloVidaService) ExecutarFazenda(ctx context.Context, fazendaID int64, ref time.Time) (ResultadoCicloVida, error) {
+	res := ResultadoCicloVida{FazendasProcessadas: 1}
+	cfg, err := s.GetConfig(ctx, fazendaID)
+	if err != nil {
+		return res, err
+	}
+	animais, err := s.repo.ListAnimais(ctx, fazendaID)
+	if err != nil {
+		return res, err
+	}
+	lotes, err := s.lotes.GetByFazendaID(ctx, fazendaID)
+	if err != nil {
+		return res, err
+	}
+
+	transicoes, propostas := planejarCicloVida(cfg, animais, lotes, ref)
+	for _, t := range transicoes {
+		para := t.Para
+		if err := s.animais.UpdateCategoria(ctx, t.AnimalID, &para); err != nil {
+			slog.Warn("ciclo de vida: reclassificar", "animal_id", t.AnimalID, "para", para, "error", err)
+			continue
+		}
+		s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeAnimal, t.AnimalID, fazendaID, t.AnimalID,
+			map[string]interface{}{"categoria": t.De},
+			map[string]interface{}{"categoria": t.Para, "motivo_reclassificacao": t.Motivo})
+		res.Reclassificados++
+	}
+
+	criadas, obsoletas, err := s.sincronizarPropostas(ctx, fazendaID, propostas)
+	res.PropostasCriadas, res.PropostasObsoletas = criadas, obsoletas
+	return res, err
+}
+
+// sincronizarPropostas torna obsoletas as pendentes que deixaram de se aplicar e cria as novas.
+func (s *CicloVidaService) sincronizarPropostas(ctx context.Context, fazendaID int64, desejadas []*models.MovimentacaoLoteProposta) (criadas, obsoletas int, err error) {
+	pendentes, err := s.repo.ListPropostas(ctx, fazendaID, models.PropostaLoteStatusPendente, 500)
+	if err != nil {
+		return 0, 0, err
+	}
+	porAnimal := make(map[int64]*models.MovimentacaoLoteProposta, len(desejadas))
+	for _, p := range desejadas {
+		porAnimal[p.AnimalID] = p
+	}
+	for _, p := range pendentes {
+		d, ok := porAnimal[p.AnimalID]
+		if ok && d.Motivo == p.Motivo && d.Referencia == p.Referencia && d.LoteDestinoID == p.LoteDestinoID {
+			delete(porAnimal, p.AnimalID)
+			continue
+		}
+		if err := s.repo.DecidirProposta(ctx, fazendaID, p.ID, models.PropostaLoteStatusObsoleta, nil); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return criadas, obsoletas, err
+		}
+		obsoletas++
+	}
+	for _, p := range desejadas {
+		if porAnimal[p.AnimalID] != p {
+			continue
+		}
+		ok, err := s.repo.CreateProposta(ctx, p)
+		if err != nil {
+			return criadas, obsoletas, err
+		}
+		if ok {
+			criadas++
+		}
+	}
+	return criadas, obsoletas, nil
+}
+
+// transicaoCategoria mudança de categoria decidida pelo motor.
+type transicaoCategoria struct {
+	AnimalID int64
+	De       string
+	Para     string
+	Motivo   string
+}
+
+// planejarCicloVida decide, sem efeitos, as transições de categoria e as propostas de lote do dia.
+// Regras: BEZERRA → NOVILHA por idade ou peso; BEZERRA/NOVILHA com parto → MATRIZ; BEZERRO → BOI/TOURO
+// por idade. Lotes: gestação a ≤ N dias do parto → PRE_PARTO; novilha ou boi ainda no lote de
+// bezerros → RECRIA / ENGORDA. Só há proposta quando a fazenda tem lote ativo do tipo de destino.
+func planejarCicloVida(cfg *models.CicloVidaConfig, animais []models.AnimalCicloVida, lotes []*models.Lote, ref time.Time) ([]transicaoCategoria, []*models.MovimentacaoLoteProposta) {
+	destinos := map[string]*models.Lote{}
+	for _, l := range lotes {
+		if l == nil || !l.Ativo || l.Tipo == nil {
+			continue
+		}
+		if atual, ok := destinos[*l.Tipo]; !ok || l.ID < atual.ID {
+			destinos[*l.Tipo] = l
+		}
+	}
+
+	var transicoes []transicaoCategoria
+	var propostas []*models.MovimentacaoLoteProposta
+	for i := range animais {
+		a := &animais[i]
+		categoria := ""
+		if a.Categoria != nil {
+			categoria = *a.Categoria
+		}
+		if para, motivo := proximaCategoria(cfg, a, categoria, ref); para != "" {
+			transicoes = append(transicoes, transicaoCategoria{AnimalID: a.ID, De: categoria, Para: para, Motivo: motivo})
+			categoria = para
+		}
+
+		loteTipo := ""
+		if a.LoteTipo != nil {
+			loteTipo = *a.LoteTipo
+		}
+		var motivo, referencia, descricao string
+		switch {
+		case a.GestacaoID != nil && a.DataPrevistaParto != nil &&
+			diasCivis(ref, *a.DataPrevistaParto) <= cfg.PrePartoDias &&
+			loteTipo != models.LoteTipoPreParto && loteTipo != models.LoteTipoMaternidade:
+			motivo = models.PropostaLoteMotivoPreParto
+			referencia = fmt.Sprintf("gestacao:%d", *a.GestacaoID)
+			dias := diasCivis(ref, *a.DataPrevistaParto)
+			descricao = fmt.Sprintf("Parto previsto para %s (%s)", a.DataPrevistaParto.Format("02/01/2006"), descreverDiasParto(dias))
+		case categoria == models.CategoriaNovilha && loteTipo == models.LoteTipoBezerros:
+			motivo = models.PropostaLoteMotivoRecria
+			referencia = "categoria:" + categoria
+			descricao = "Novilha ainda no lote de bezerros"
+		case categoria == models.CategoriaBoi && loteTipo == models.LoteTipoBezerros:
+			motivo = models.PropostaLoteMotivoEngorda
+			referencia = "categoria:" + categoria
+			descricao = "Boi ainda no lote de bezerros"
+		default:
+			continue
+		}
+		destino, ok := destinos[loteTipoPorMotivo[motivo]]
+		if !ok || (a.LoteID != nil && *a.LoteID == destino.ID) {
+			continue
+		}
+		propostas = append(propostas, &models.MovimentacaoLoteProposta{
+			FazendaID:           a.FazendaID,
+			AnimalID:            a.ID,
+			AnimalIdentificacao: a.Identificacao,
+			LoteOrigemID:        a.LoteID,
+			LoteDestinoID:       destino.ID,
+			LoteDestinoNome:     destino.Nome,
+			Motivo:              motivo,
+			Referencia:          referencia,
+			Descricao:           descricao,
+			Status:              models.PropostaLoteStatusPendente,
+		})
+	}
+	return transicoes, propostas
+}
+
+// proximaCategoria categoria seguinte do animal ("" = mantém) e o motivo legível.
+func proximaCategoria(cfg *models.CicloVidaConfig, a *models.AnimalCicloVida, categoria string, ref time.Time) (string, string) {
+	idade := -1
+	if a.DataNascimento != nil {
+		idade = mesesCompletos(*a.DataNascimento, ref)
+	}
+	switch categoria {
+	case models.CategoriaBezerra, models.CategoriaNovilha:
+		if a.TemParto {
+			return models.CategoriaMatriz, "primeiro parto registado"
+		}
+		if categoria == models.CategoriaNovilha {
+			return "", ""
+		}
+		if idade >= cfg.NovilhaIdadeMeses {
+			return models.CategoriaNovilha, fmt.Sprintf("idade %d meses", idade)
+		}
+		if cfg.NovilhaPesoKg != nil && a.PesoKg != nil && *a.PesoKg >= *cfg.NovilhaPesoKg {
+			return models.CategoriaNovilha, fmt.Sprintf("peso %.0f kg", *a.PesoKg)
+		}
+	case models.CategoriaBezerro:
+		if idade >= cfg.MachoIdadeMeses {
+			return cfg.MachoCategoria, fmt.Sprintf("idade %d meses", idade)
+		}
+	}
+	return "", ""
+}
+
+func descreverDiasParto(dias int) string {
+	switch {
+	case dias < 0:
+		return fmt.Sprintf("atrasado %d dias", -dias)
+	case dias == 0:
+		return "hoje"
+	case dias == 1:
+		return "amanhã"
+	default:
+		return fmt.Sprintf("em %d dias", dias)
+	}
+}
+
+// ListPropostas propostas da fazenda filtradas por estado (vazio = todas).
+func (s *CicloVidaService) ListPropostas(ctx context.Context, fazendaID int64, status string) ([]*models.MovimentacaoLoteProposta, error) {
+	return s.repo.ListPropostas(ctx, fazendaID, status, 0)
+}
+
+// PropostaDecisaoFalha proposta que não pôde ser decidida no pedido em lote.
+type PropostaDecisaoFalha struct {
+	ID   int64  `json:"id"`
+	Erro string `json:"erro"`
+}
+
+// ResultadoDecisaoPropostas resposta da aprovação/rejeição em lote.
+type ResultadoDecisaoPropostas struct {
+	Processadas int                    `json:"processadas"`
+	Obsoletas   int                    `json:"obsoletas"`
+	Falhas      []PropostaDecisaoFalha `json:"falhas"`
+	Avisos      []string               `json:"avisos,omitempty"`
+}
+
+type DecidirPropostasInput struct {
+	IDs         []int64
+	ActorUserID int64
+	Perfil      string
+}
+
+func (s *CicloVidaService) carregarPropostasDecisao(ctx context.Context, fazendaID int64, in DecidirPropostasInput) ([]*models.MovimentacaoLoteProposta, *ResultadoDecisaoPropostas, error) {
+	if !models.PodeGerirCicloVida(in.Perfil) {
+		return nil, nil, ErrCicloVidaForbidden
+	}
+	if len(in.IDs) == 0 || len(in.IDs) > propostaLoteMaxDecisao {
+		return nil, nil, ErrPropostaLoteIDsInvalidos
+	}
+	list, err := s.repo.GetPropostasByIDs(ctx, fazendaID, in.IDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	res := &ResultadoDecisaoPropostas{Falhas: []PropostaDecisaoFalha{}}
+	encontradas := make(map[int64]bool, len(list))
+	var pendentes []*models.MovimentacaoLoteProposta
+	for _, p := range list {
+		encontradas[p.ID] = true
+		if p.Status != models.PropostaLoteStatusPendente {
+			res.Falhas = append(res.Falhas, PropostaDecisaoFalha{ID: p.ID, Erro: "proposta já " + p.Status})
+			continue
+		}
+		pendentes = append(pendentes, p)
+	}
+	for _, id := range in.IDs {
+		if !encontradas[id] {
+			res.Falhas = append(res.Falhas, PropostaDecisaoFalha{ID: id, Erro: "proposta não encontrada"})
+			encontradas[id] = true
+		}
+	}
+	return pendentes, res, nil
+}
+
+// AprovarPropostas executa as movimentações propostas (BR-LOTE-006). Cada proposta é independente:
+// as que falham voltam a PENDENTE e são devolvidas em Falhas; animal fora do rebanho ou já no lote
+// de destino torna a proposta OBSOLETA.
+func (s *CicloVidaService) AprovarPropostas(ctx context.Context, fazendaID int64, in DecidirPropostasInput) (*ResultadoDecisaoPropostas, error) {
+	pendentes, res, err := s.carregarPropostasDecisao(ctx, fazendaID, in)
+	if err != nil {
+		return nil, err
+	}
+	var destinos []int64
+	for _, p := range pendentes {
+		animal, err := s.animais.GetByID(ctx, p.AnimalID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return res, err
+		}
+		if animal == nil || animal.IsForaDoRebanho() || (animal.LoteID != nil && *animal.LoteID == p.LoteDestinoID) {
+			if err := s.repo.DecidirProposta(ctx, fazendaID, p.ID, models.PropostaLoteStatusObsoleta, &in.ActorUserID); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+				return res, err
+			}
+			res.Obsoletas++
+			continue
+		}
+		// Reivindica a proposta antes de movimentar: duas aprovações simultâneas não duplicam a movimentação.
+		if err := s.repo.DecidirProposta(ctx, fazendaID, p.ID, models.PropostaLoteStatusAprovada, &in.ActorUserID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				res.Falhas = append(res.Falhas, PropostaDecisaoFalha{ID: p.ID, Erro: "proposta já decidida"})
+				continue
+			}
+			return res, err
+		}
+		motivo := "Proposta automática: " + p.Descricao
+		m := &models.MovimentacaoLote{
+			AnimalID:      p.AnimalID,
+			LoteOrigemID:  animal.LoteID,
+			LoteDestinoID: p.LoteDestinoID,
+			Motivo:        &motivo,
+			UsuarioID:     in.ActorUserID,
+			Data:          time.Now(),
+		}
+		if err := s.movimentacoes.Create(ctx, m); err != nil {
+			if rErr := s.repo.ReabrirProposta(ctx, p.ID); rErr != nil {
+				slog.Warn("ciclo de vida: reabrir proposta", "proposta_id", p.ID, "error", rErr)
+			}
+			res.Falhas = append(res.Falhas, PropostaDecisaoFalha{ID: p.ID, Erro: err.Error()})
+			continue
+		}
+		if err := s.repo.SetPropostaMovimentacao(ctx, p.ID, m.ID); err != nil {
+			slog.Warn("ciclo de vida: ligar movimentação à proposta", "proposta_id", p.ID, "error", err)
+		}
+		res.Processadas++
+		if !slices.Contains(destinos, p.LoteDestinoID) {
+			destinos = append(destinos, p.LoteDestinoID)
+		}
+	}
+	for _, loteID := range destinos {
+		res.Avisos = append(res.Avisos, s.movimentacoes.AvisosCapacidade(ctx, loteID)...)
+	}
+	return res, nil
+}
+
+// RejeitarPropostas descarta as propostas; a mesma situação (ex.: a mesma gestação) não volta a ser proposta.
+func (s *CicloVidaService) RejeitarPropostas(ctx context.Context, fazendaID int64, in DecidirPropostasInput) (*ResultadoDecisaoPropostas, error) {
+	pendentes, res, err := s.carregarPropostasDecisao(ctx, fazendaID, in)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range pendentes {
+		if err := s.repo.DecidirProposta(ctx, fazendaID, p.ID, models.PropostaLoteStatusRejeitada, &in.ActorUserID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				res.Falhas = append(res.Falhas, PropostaDecisaoFalha{ID: p.ID, Erro: "proposta já decidida"})
+				continue
+			}
+			return res, err
+		}
+		res.Processadas++
+	}
+	return res, nil
+}
@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestPlanejarCicloVida_Transicoes(t *testing.T) {
+	ref := time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC)
+	cfg := models.CicloVidaConfigPadrao(1)
+	peso := 300.0
+	cfg.NovilhaPesoKg = &peso
+	nasc := func(meses int) *time.Time {
+		d := ref.AddDate(0, -meses, 0)
+		return &d
+	}
+	pesada := 320.0
+	animais := []models.AnimalCicloVida{
+		{ID: 1, Categoria: strPtr(models.CategoriaBezerra), DataNascimento: nasc(12)},
+		{ID: 2, Categoria: strPtr(models.CategoriaBezerra), DataNascimento: nasc(9), PesoKg: &pesada},
+		{ID: 3, Categoria: strPtr(models.CategoriaBezerra), DataNascimento: nasc(11)},
+		{ID: 4, Categoria: strPtr(models.CategoriaNovilha), DataNascimento: nasc(26), TemParto: true},
+		{ID: 5, Categoria: strPtr(models.CategoriaBezerro), DataNascimento: nasc(13)},
+		{ID: 6, Categoria: strPtr(models.CategoriaBezerro)},
+		{ID: 7, Categoria: strPtr(models.CategoriaMatriz), TemParto: true},
+	}
+	transicoes, _ := planejarCicloVida(cfg, animais, nil, ref)
+
+	esperado := map[int64]string{1: models.CategoriaNovilha, 2: models.CategoriaNovilha, 4: models.CategoriaMatriz, 5: models.CategoriaBoi}
+	if len(transicoes) != len(esperado) {
+		t.Fatalf("transições: %+v", transicoes)
+	}
+	for _, tr := range transicoes {
+		if esperado[tr.AnimalID] != tr.Para {
+			t.Fatalf("animal %d: %s → %s", tr.AnimalID, tr.De, tr.Para)
+		}
+	}
+	if transicoes[1].Motivo != "peso 320 kg" {
+		t.Fatalf("motivo por peso: %q", transicoes[1].Motivo)
+	}
+}
+
+func TestPlanejarCicloVida_Propostas(t *testing.T) {
+	ref := time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC)
+	cfg := models.CicloVidaConfigPadrao(1)
+	lotes := []*models.Lote{
+		{ID: 10, Nome: "Bezerros", Tipo: strPtr(models.LoteTipoBezerros), Ativo: true},
+		{ID: 21, Nome: "Pré-parto B", Tipo: strPtr(models.LoteTipoPreParto), Ativo: true},
+		{ID: 20, Nome: "Pré-parto A", Tipo: strPtr(models.LoteTipoPreParto), Ativo: true},
+		{ID: 30, Nome: "Recria antiga", Tipo: strPtr(models.LoteTipoRecria), Ativo: false},
+		{ID: 40, Nome: "Engorda", Tipo: strPtr(models.LoteTipoEngorda), Ativo: true},
+		{ID: 50, Nome: "Maternidade", Tipo: strPtr(models.LoteTipoMaternidade), Ativo: true},
+	}
+	lote := func(id int64, tipo string) (*int64, *string) { return &id, &tipo }
+	previsto := func(dias int) *time.Time {
+		d := ref.AddDate(0, 0, dias)
+		return &d
+	}
+	gest := func(id int64) *int64 { return &id }
+
+	a1 := models.AnimalCicloVida{ID: 1, Categoria: strPtr(models.CategoriaMatriz), GestacaoID: gest(100), DataPrevistaParto: previsto(21)}
+	a2 := models.AnimalCicloVida{ID: 2, Categoria: strPtr(models.CategoriaMatriz), GestacaoID: gest(101), DataPrevistaParto: previsto(22)}
+	a3 := models.AnimalCicloVida{ID: 3, Categoria: strPtr(models.CategoriaMatriz), GestacaoID: gest(102), DataPrevistaParto: previsto(3)}
+	a3.LoteID, a3.LoteTipo = lote(50, models.LoteTipoMaternidade)
+	a4 := models.AnimalCicloVida{ID: 4, Categoria: strPtr(models.CategoriaNovilha)}
+	a4.LoteID, a4.LoteTipo = lote(10, models.LoteTipoBezerros)
+	// Novilha prenhe no lote de bezerros: o pré-parto tem prioridade sobre a recria.
+	a5 := models.AnimalCicloVida{ID: 5, Categoria: strPtr(models.CategoriaNovilha), GestacaoID: gest(103), DataPrevistaParto: previsto(-2)}
+	a5.LoteID, a5.LoteTipo = lote(10, models.LoteTipoBezerros)
+	// Bezerro que passa a boi hoje sai do lote de bezerros para a engorda na mesma execução.
+	a6 := models.AnimalCicloVida{ID: 6, Categoria: strPtr(models.CategoriaBezerro), DataNascimento: previsto(-400)}
+	a6.LoteID, a6.LoteTipo = lote(10, models.LoteTipoBezerros)
+
+	_, propostas := planejarCicloVida(cfg, []models.AnimalCicloVida{a1, a2, a3, a4, a5, a6}, lotes, ref)
+
+	if len(propostas) != 3 {
+		t.Fatalf("propostas: %+v", propostas)
+	}
+	p1, p5, p6 := propostas[0], propostas[1], propostas[2]
+	if p1.AnimalID != 1 || p1.Motivo != models.PropostaLoteMotivoPreParto || p1.LoteDestinoID != 20 || p1.Referencia != "gestacao:100" {
+		t.Fatalf("pré-parto deve ir para o lote ativo de menor id: %+v", p1)
+	}
+	if p5.AnimalID != 5 || p5.Motivo != models.PropostaLoteMotivoPreParto || p5.Descricao != "Parto previsto para 13/06/2026 (atrasado 2 dias)" {
+		t.Fatalf("prioridade do pré-parto: %+v", p5)
+	}
+	if p6.AnimalID != 6 || p6.Motivo != models.PropostaLoteMotivoEngorda || p6.LoteDestinoID != 40 || *p6.LoteOrigemID != 10 {
+		t.Fatalf("engorda: %+v", p6)
+	}
+}
+
+// fakeCicloVidaStore guarda as propostas em memória com os índices únicos da tabela.
+type fakeCicloVidaStore struct {
+	propostas []*models.MovimentacaoLoteProposta
+}
+
+func (f *fakeCicloVidaStore) GetConfig(context.Context, int64) (*models.CicloVidaConfig, error) {
+	return nil, nil
+}
+
+func (f *fakeCicloVidaStore) UpsertConfig(context.Context, *models.CicloVidaConfig) error { return nil }
+
+func (f *fakeCicloVidaStore) ListAnimais(context.Context, int64) ([]models.AnimalCicloVida, error) {
+	return nil, nil
+}
+
+func (f *fakeCicloVidaStore) ListPropostas(_ context.Context, _ int64, status string, _ int) ([]*models.MovimentacaoLoteProposta, error) {
+	var out []*models.MovimentacaoLoteProposta
+	for _, p := range f.propostas {
+		if status == "" || p.Status == status {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeCicloVidaStore) GetPropostasByIDs(_ context.Context, _ int64, ids []int64) ([]*models.MovimentacaoLoteProposta, error) {
+	var out []*models.MovimentacaoLoteProposta
+	for _, id := range ids {
+		for _, p := range f.propostas {
+			if p.ID == id {
+				out = append(out, p)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeCicloVidaStore) CreateProposta(_ context.Context, p *models.MovimentacaoLoteProposta) (bool, error) {
+	for _, e := range f.propostas {
+		if e.AnimalID != p.AnimalID {
+			continue
+		}
+		if e.Status == models.PropostaLoteStatusPendente ||
+			(e.Status != models.PropostaLoteStatusObsoleta && e.Motivo == p.Motivo && e.Referencia == p.Referencia) {
+			return false, nil
+		}
+	}
+	p.ID = int64(len(f.propostas) + 1)
+	p.Status = models.PropostaLoteStatusPendente
+	f.propostas = append(f.propostas, p)
+	return true, nil
+}
+
+func (f *fakeCicloVidaStore) DecidirProposta(_ context.Context, _ int64, id int64, status string, decididoPor *int64) error {
+	for _, p := range f.propostas {
+		if p.ID == id && p.Status == models.PropostaLoteStatusPendente {
+			p.Status, p.DecididoPor = status, decididoPor
+			return nil
+		}
+	}
+	return pgx.ErrNoRows
+}
+
+func (f *fakeCicloVidaStore) ReabrirProposta(_ context.Context, id int64) error {
+	for _, p := range f.propostas {
+		if p.ID == id {
+			p.Status, p.DecididoPor = models.PropostaLoteStatusPendente, nil
+		}
+	}
+	return nil
+}
+
+func (f *fakeCicloVidaStore) SetPropostaMovimentacao(_ context.Context, id, movimentacaoID int64) error {
+	for _, p := range f.propostas {
+		if p.ID == id {
+			p.MovimentacaoID = &movimentacaoID
+		}
+	}
+	return nil
+}
+
+type fakeCicloVidaAnimais map[int64]*models.Animal
+
+func (f fakeCicloVidaAnimais) GetByID(_ context.Context, id int64) (*models.Animal, error) {
+	if a, ok := f[id]; ok {
+		return a, nil
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (f fakeCicloVidaAnimais) UpdateCategoria(context.Context, int64, *string) error { return nil }
+
+type fakeCicloVidaMovimentacoes struct {
+	criadas []*models.MovimentacaoLote
+	falhar  map[int64]bool
+}
+
+func (f *fakeCicloVidaMovimentacoes) Create(_ context.Context, m *models.MovimentacaoLote) error {
+	if f.falhar[m.AnimalID] {
+		return ErrLoteNotFound
+	}
+	m.ID = int64(len(f.criadas) + 1)
+	f.criadas = append(f.criadas, m)
+	return nil
+}
+
+func (f *fakeCicloVidaMovimentacoes) AvisosCapacidade(context.Context, int64) []string { return nil }
+
+func propostaTeste(animalID int64, motivo, referencia string, destino int64) *models.MovimentacaoLoteProposta {
+	return &models.MovimentacaoLoteProposta{FazendaID: 1, AnimalID: animalID, Motivo: motivo, Referencia: referencia, LoteDestinoID: destino}
+}
+
+func TestCicloVidaService_SincronizarPropostas(t *testing.T) {
+	store := &fakeCicloVidaStore{}
+	s := &CicloVidaService{repo: store}
+	ctx := context.Background()
+
+	criadas, obsoletas, err := s.sincronizarPropostas(ctx, 1, []*models.MovimentacaoLoteProposta{
+		propostaTeste(1, models.PropostaLoteMotivoPreParto, "gestacao:9", 20),
+		propostaTeste(2, models.PropostaLoteMotivoRecria, "categoria:NOVILHA", 30),
+	})
+	if err != nil || criadas != 2 || obsoletas != 0 {
+		t.Fatalf("primeira execução: %d %d %v", criadas, obsoletas, err)
+	}
+	if err := store.DecidirProposta(ctx, 1, 2, models.PropostaLoteStatusRejeitada, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Dia seguinte: animal 1 perdeu a gestação, a recria rejeitada continua a aplicar-se, animal 3 é novo.
+	criadas, obsoletas, err = s.sincronizarPropostas(ctx, 1, []*models.MovimentacaoLoteProposta{
+		propostaTeste(2, models.PropostaLoteMotivoRecria, "categoria:NOVILHA", 30),
+		propostaTeste(3, models.PropostaLoteMotivoEngorda, "categoria:BOI", 40),
+	})
+	if err != nil || criadas != 1 || obsoletas != 1 {
+		t.Fatalf("segunda execução: %d %d %v", criadas, obsoletas, err)
+	}
+	if store.propostas[0].Status != models.PropostaLoteStatusObsoleta {
+		t.Fatalf("proposta sem gestação deve ficar obsoleta: %+v", store.propostas[0])
+	}
+	if len(store.propostas) != 3 {
+		t.Fatalf("proposta rejeitada não volta a ser criada: %d", len(store.propostas))
+	}
+
+	// Sem mudanças: a pendente mantém-se.
+	criadas, obsoletas, _ = s.sincronizarPropostas(ctx, 1, []*models.MovimentacaoLoteProposta{
+		propostaTeste(3, models.PropostaLoteMotivoEngorda, "categoria:BOI", 40),
+	})
+	if criadas != 0 || obsoletas != 0 || store.propostas[2].Status != models.PropostaLoteStatusPendente {
+		t.Fatalf("execução idempotente: %d %d", criadas, obsoletas)
+	}
+}
+
+func TestCicloVidaService_AprovarPropostas(t *testing.T) {
+	ctx := context.Background()
+	lote := func(id int64) *int64 { return &id }
+	saida := time.Now().AddDate(0, 0, -1)
+	store := &fakeCicloVidaStore{}
+	for _, p := range []*models.MovimentacaoLoteProposta{
+		propostaTeste(1, models.PropostaLoteMotivoPreParto, "gestacao:1", 20),
+		propostaTeste(2, models.PropostaLoteMotivoPreParto, "gestacao:2", 20),
+		propostaTeste(3, models.PropostaLoteMotivoRecria, "categoria:NOVILHA", 30),
+		propostaTeste(4, models.PropostaLoteMotivoEngorda, "categoria:BOI", 40),
+	} {
+		if _, err := store.CreateProposta(ctx, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = store.DecidirProposta(ctx, 1, 4, models.PropostaLoteStatusRejeitada, nil)
+	movs := &fakeCicloVidaMovimentacoes{falhar: map[int64]bool{3: true}}
+	s := &CicloVidaService{
+		repo: store,
+		animais: fakeCicloVidaAnimais{
+			1: {ID: 1, FazendaID: 1, LoteID: lote(10)},
+			2: {ID: 2, FazendaID: 1, DataSaida: &saida},
+			3: {ID: 3, FazendaID: 1, LoteID: lote(10)},
+		},
+		movimentacoes: movs,
+	}
+
+	if _, err := s.AprovarPropostas(ctx, 1, DecidirPropostasInput{IDs: []int64{1}, Perfil: models.PerfilFuncionario}); !errors.Is(err, ErrCicloVidaForbidden) {
+		t.Fatalf("funcionário não aprova: %v", err)
+	}
+	res, err := s.AprovarPropostas(ctx, 1, DecidirPropostasInput{IDs: []int64{1, 2, 3, 4, 99}, ActorUserID: 7, Perfil: models.PerfilGerente})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Processadas != 1 || res.Obsoletas != 1 || len(res.Falhas) != 3 {
+		t.Fatalf("resultado: %+v", res)
+	}
+	if len(movs.criadas) != 1 || *movs.criadas[0].LoteOrigemID != 10 || movs.criadas[0].UsuarioID != 7 {
+		t.Fatalf("movimentação: %+v", movs.criadas)
+	}
+	p1, p2, p3 := store.propostas[0], store.propostas[1], store.propostas[2]
+	if p1.Status != models.PropostaLoteStatusAprovada || p1.MovimentacaoID == nil || *p1.DecididoPor != 7 {
+		t.Fatalf("aprovada: %+v", p1)
+	}
+	if p2.Status != models.PropostaLoteStatusObsoleta {
+		t.Fatalf("animal fora do rebanho: %+v", p2)
+	}
+	if p3.Status != models.PropostaLoteStatusPendente {
+		t.Fatalf("falha na movimentação devolve a proposta a pendente: %+v", p3)
+	}
+}
@@ -11,7 +11,11 @@ import (
 
 var ErrCioNotFound = errors.New("cio nao encontrado")
 
+// ErrCioTemVinculos impede exclusão quando há cobertura ligada ao cio.
+var ErrCioTemVinculos = errors.New("cio possui cobertura vinculada")
+
 type CioService struct {
+	auditavel
 	repo        *repository.CioRepository
 	animalRepo  *repository.AnimalRepository
 	fazendaRepo *repository.FazendaRepository
@@ -21,31 +25,32 @@ func NewCioService(repo *repository.CioRepository, animalRepo *repository.Animal
 	return &CioService{repo: repo, animalRepo: animalRepo, fazendaRepo: fazendaRepo}
 }
 
-func (s *CioService) Create(ctx context.Context, c *models.Cio) error {
+// validateCioParaRegistro aplica as regras de registo de cio (criação e restauração da lixeira).
+func (s *CioService) validateCioParaRegistro(ctx context.Context, c *models.Cio) (*models.Animal, error) {
 	if c.AnimalID <= 0 || c.FazendaID <= 0 {
-		return errors.New("animal_id e fazenda_id sao obrigatorios")
+		return nil, errors.New("animal_id e fazenda_id sao obrigatorios")
 	}
 	animal, err := s.animalRepo.GetByID(ctx, c.AnimalID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return ErrAnimalNotFound
+			return nil, ErrAnimalNotFound
 		}
-		return err
+		return nil, err
 	}
 	if animal.FazendaID != c.FazendaID {
-		return errors.New("animal deve ser da mesma fazenda")
+		return nil, errors.New("animal deve ser da mesma fazenda")
 	}
 	if err := EnsureAnimalNoRebanho(animal); err != nil {
-		return err
+		return nil, err
 	}
 	if err := ValidateEventoCioTemporal(animal, c.DataDetectado); err != nil {
-		return err
+		return nil, err
 	}
 	if animal.Sexo != nil && *animal.Sexo != "F" {
-		return errors.New("apenas femeas podem ter registro de cio")
+		return nil, errors.New("apenas femeas podem ter registro de cio")
 	}
 	if err := ValidateElegibilidadeReprodutiva(animal, c.DataDetectado); err != nil {
-		return err
+		return nil, err
 	}
 	if c.MetodoDeteccao != nil && *c.MetodoDeteccao != "" {
 		valid := false
@@ -56,7 +61,7 @@ func (s *CioService) Create(ctx context.Context, c *models.Cio) error {
 			}
 		}
 		if !valid {
-			return errors.New("metodo de deteccao invalido")
+			return nil, errors.New("metodo de deteccao invalido")
 		}
 	}
 	if c.Intensidade != nil && *c.Intensidade != "" {
@@ -68,12 +73,21 @@ func (s *CioService) Create(ctx context.Context, c *models.Cio) error {
 			}
 		}
 		if !valid {
-			return errors.New("intensidade invalida")
+			return nil, errors.New("intensidade invalida")
 		}
 	}
+	return animal, nil
+}
+
+func (s *CioService) Create(ctx context.Context, c *models.Cio) error {
+	animal, err := s.validateCioParaRegistro(ctx, c)
+	if err != nil {
+		return err
+	}
 	if err := s.repo.Create(ctx, c); err != nil {
 		return err
 	}
+	s.auditar(ctx, models.AuditoriaAcaoCreate, models.AuditoriaEntidadeCio, c.ID, c.FazendaID, c.AnimalID, nil, c)
 	return s.applyStatusAfterCio(ctx, animal)
 }
 
@@ -171,7 +185,11 @@ func (s *CioService) Update(ctx context.Context, c *models.Cio) error {
 	if err := ValidateElegibilidadeReprodutiva(animal, c.DataDetectado); err != nil {
 		return err
 	}
-	return s.repo.Update(ctx, c)
+	if err := s.repo.Update(ctx, c); err != nil {
+		return err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeCio, c.ID, c.FazendaID, c.AnimalID, existing, c)
+	return nil
 }
 
 func (s *CioService) Delete(ctx context.Context, id int64) error {
@@ -185,5 +203,44 @@ func (s *CioService) Delete(ctx context.Context, id int64) error {
 	if err := EnsureAnimalIDNoRebanho(ctx, s.animalRepo, existing.AnimalID); err != nil {
 		return err
 	}
-	return s.repo.Delete(ctx, id)
+	vinculado, err := s.repo.ExistsCoberturaVinculada(ctx, id)
+	if err != nil {
+		return err
+	}
+	if vinculado {
+		return ErrCioTemVinculos
+	}
+	if err := s.repo.Delete(ctx, id, usuarioExclusao(ctx)); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrCioNotFound
+		}
+		return err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoDelete, models.AuditoriaEntidadeCio, id, existing.FazendaID, existing.AnimalID, existing, nil)
+	return nil
+}
+
+// Restaurar retira o cio da lixeira após revalidar as regras de registo e recalcula o status reprodutivo.
+func (s *CioService) Restaurar(ctx context.Context, id int64) (*models.Cio, error) {
+	c, err := s.repo.GetExcluidoByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCioNotFound
+		}
+		return nil, err
+	}
+	if _, err := s.validateCioParaRegistro(ctx, c); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Restaurar(ctx, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCioNotFound
+		}
+		return nil, err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoRestore, models.AuditoriaEntidadeCio, c.ID, c.FazendaID, c.AnimalID, nil, c)
+	if err := RecalcularStatusReprodutivo(ctx, s.animalRepo, c.AnimalID); err != nil {
+		return nil, err
+	}
+	return c, nil
 }
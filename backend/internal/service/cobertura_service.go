@@ -25,6 +25,7 @@ var (
 )
 
 type CoberturaService struct {
+	auditavel
 	repo                    *repository.CoberturaRepository
 	animalRepo              *repository.AnimalRepository
 	fazendaRepo             *repository.FazendaRepository
@@ -114,6 +115,7 @@ func (s *CoberturaService) Create(ctx context.Context, c *models.Cobertura) erro
 	if err := s.repo.Create(ctx, c); err != nil {
 		return err
 	}
+	s.auditar(ctx, models.AuditoriaAcaoCreate, models.AuditoriaEntidadeCobertura, c.ID, c.FazendaID, c.AnimalID, nil, c)
 	status := models.StatusReprodutivoServida
 	return s.animalRepo.UpdateStatusReprodutivo(ctx, c.AnimalID, &status)
 }
@@ -144,14 +146,18 @@ func (s *CoberturaService) Update(ctx context.Context, c *models.Cobertura) erro
 	if err := s.validateCoberturaRegras(ctx, c); err != nil {
 		return err
 	}
-	_, err := s.repo.GetByID(ctx, c.ID)
+	existing, err := s.repo.GetByID(ctx, c.ID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return ErrCoberturaNotFound
 		}
 		return err
 	}
-	return s.repo.Update(ctx, c)
+	if err := s.repo.Update(ctx, c); err != nil {
+		return err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeCobertura, c.ID, c.FazendaID, c.AnimalID, existing, c)
+	return nil
 }
 
 func (s *CoberturaService) Delete(ctx context.Context, id int64) error {
@@ -179,5 +185,37 @@ func (s *CoberturaService) Delete(ctx context.Context, id int64) error {
 	if hasDiag {
 		return ErrCoberturaTemVinculos
 	}
-	return s.repo.Delete(ctx, id)
+	if err := s.repo.Delete(ctx, id, usuarioExclusao(ctx)); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrCoberturaNotFound
+		}
+		return err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoDelete, models.AuditoriaEntidadeCobertura, id, existing.FazendaID, existing.AnimalID, existing, nil)
+	return nil
+}
+
+// Restaurar retira a cobertura da lixeira após revalidar as regras de registo e recalcula o status reprodutivo.
+func (s *CoberturaService) Restaurar(ctx context.Context, id int64) (*models.Cobertura, error) {
+	c, err := s.repo.GetExcluidoByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCoberturaNotFound
+		}
+		return nil, err
+	}
+	if err := s.validateCoberturaRegras(ctx, c); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Restaurar(ctx, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCoberturaNotFound
+		}
+		return nil, err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoRestore, models.AuditoriaEntidadeCobertura, c.ID, c.FazendaID, c.AnimalID, nil, c)
+	if err := RecalcularStatusReprodutivo(ctx, s.animalRepo, c.AnimalID); err != nil {
+		return nil, err
+	}
+	return c, nil
 }
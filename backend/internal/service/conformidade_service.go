@@ -86,7 +86,7 @@ func (s *ConformidadeService) checkProducaoSemLactacaoAtiva(ctx context.Context,
 		SELECT DISTINCT a.id, a.identificacao
 		FROM producao_leite p
 		INNER JOIN animais a ON a.id = p.animal_id
-		WHERE a.fazenda_id = $1` + noRebanhoA + sqlProducaoSemLactacaoNaData
+		WHERE a.fazenda_id = $1 AND p.excluido_em IS NULL` + noRebanhoA + sqlProducaoSemLactacaoNaData
 	return s.scanAnimalRows(ctx, q, fazendaID, "INT-002", "ALTA",
 		"Registo de produção sem lactação que cubra a data (BR-CICLO-007).")
 }
@@ -204,12 +204,12 @@ func (s *ConformidadeService) checkMarcoReprodutivoAnimalImaturo(ctx context.Con
 		SELECT DISTINCT a.id, a.identificacao
 		FROM cios ci
 		INNER JOIN animais a ON a.id = ci.animal_id
-		WHERE ci.fazenda_id = $1`+noRebanhoA+` AND `+sqlAnimalImaturoParaMarco, "ci.data_detectado"),
+		WHERE ci.fazenda_id = $1 AND ci.excluido_em IS NULL`+noRebanhoA+` AND `+sqlAnimalImaturoParaMarco, "ci.data_detectado"),
 		fmt.Sprintf(`
 		SELECT DISTINCT a.id, a.identificacao
 		FROM coberturas cb
 		INNER JOIN animais a ON a.id = cb.animal_id
-		WHERE cb.fazenda_id = $1`+noRebanhoA+` AND `+sqlAnimalImaturoParaMarco, "cb.data"),
+		WHERE cb.fazenda_id = $1 AND cb.excluido_em IS NULL`+noRebanhoA+` AND `+sqlAnimalImaturoParaMarco, "cb.data"),
 		fmt.Sprintf(`
 		SELECT DISTINCT a.id, a.identificacao
 		FROM diagnosticos_gestacao dg
@@ -219,7 +219,7 @@ func (s *ConformidadeService) checkMarcoReprodutivoAnimalImaturo(ctx context.Con
 		SELECT DISTINCT a.id, a.identificacao
 		FROM partos p
 		INNER JOIN animais a ON a.id = p.animal_id
-		WHERE p.fazenda_id = $1`+noRebanhoA+` AND `+sqlAnimalImaturoParaMarco, "p.data"),
+		WHERE p.fazenda_id = $1 AND p.excluido_em IS NULL`+noRebanhoA+` AND `+sqlAnimalImaturoParaMarco, "p.data"),
 		fmt.Sprintf(`
 		SELECT DISTINCT a.id, a.identificacao
 		FROM secagens s
@@ -229,7 +229,7 @@ func (s *ConformidadeService) checkMarcoReprodutivoAnimalImaturo(ctx context.Con
 		SELECT DISTINCT a.id, a.identificacao
 		FROM producao_leite pl
 		INNER JOIN animais a ON a.id = pl.animal_id
-		WHERE a.fazenda_id = $1`+noRebanhoA+` AND `+sqlAnimalImaturoParaMarco, "pl.data_hora"),
+		WHERE a.fazenda_id = $1 AND pl.excluido_em IS NULL`+noRebanhoA+` AND `+sqlAnimalImaturoParaMarco, "pl.data_hora"),
 	}
 	seen := make(map[int64]ConformidadeAnomalia)
 	for _, q := range queries {
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/repository"
+	"github.com/ceialmilk/api/internal/requestctx"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrContaTokenInvalido       = errors.New("link inválido ou expirado")
+	ErrContaSenhaCurta          = errors.New("a senha deve ter pelo menos 8 caracteres")
+	ErrContaSenhaAtualIncorreta = errors.New("senha atual incorreta")
+	ErrContaSenhaIgual          = errors.New("a nova senha deve ser diferente da atual")
+	ErrContaEmailJaVerificado   = errors.New("e-mail já verificado")
+	ErrContaLimiteEnvios        = errors.New("muitos pedidos de e-mail para esta conta; tente novamente mais tarde")
+	ErrContaMailIndisponivel    = errors.New("envio de e-mail não configurado no servidor")
+)
+
+// contaSenhaMinLen política mínima de senha (BR-ACESSO-024).
+const contaSenhaMinLen = 8
+
+type contaTokenStore interface {
+	Create(ctx context.Context, t *models.TokenConta, tokenHash string) error
+	CountDesde(ctx context.Context, usuarioID int64, tipo string, desde time.Time) (int, error)
+	RedefinirSenha(ctx context.Context, tokenHash, senhaHash string, em time.Time) (int64, error)
+	AlterarSenha(ctx context.Context, usuarioID int64, senhaHash string, em time.Time) error
+	VerificarEmail(ctx context.Context, tokenHash string, em time.Time) (int64, error)
+}
+
+type contaUsuarioStore interface {
+	GetByEmail(ctx context.Context, email string) (*models.Usuario, error)
+	GetByID(ctx context.Context, id int64) (*models.Usuario, error)
+}
+
+// ContaService redefinição de senha por e-mail, troca de senha autenticada e verificação de e-mail
+// (BR-ACESSO-026). Tokens de uso único guardados como hash, como os refresh tokens.
+type ContaService struct {
+	auditavel
+	tokens   contaTokenStore
+	usuarios contaUsuarioStore
+	mailer   MailSender
+	baseURL  string
+	now      func() time.Time
+}
+
+func NewContaService(tokens *repository.TokenContaRepository, usuarios *repository.UsuarioRepository, mailer MailSender, baseURL string) *ContaService {
+	return &ContaService{
+		tokens:   tokens,
+		usuarios: usuarios,
+		mailer:   mailer,
+		baseURL:  baseURL,
+		now:      time.Now,
+	}
+}
+
+// gerarTokenConta 32 bytes aleatórios em base64 URL-safe (vai na query string do link).
+func gerarTokenConta() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func (s *ContaService) mailDisponivel() bool {
+	return s.mailer != nil && s.mailer.Enabled()
+}
+
+// linkConta caminho do frontend com o token; absoluto quando APP_BASE_URL está configurada.
+func (s *ContaService) linkConta(caminho, token string) string {
+	rel := caminho + "?token=" + token
+	if link := linkNotificacao(s.baseURL, rel); link != "" {
+		return link
+	}
+	return rel
+}
+
+// emitir aplica o limite por conta, grava o hash e envia o e-mail com o link.
+func (s *ContaService) emitir(ctx context.Context, u *models.Usuario, tipo string) error {
+	agora := s.now()
+	n, err := s.tokens.CountDesde(ctx, u.ID, tipo, agora.Add(-time.Hour))
+	if err != nil {
+		return err
+	}
+	if n >= models.TokenContaMaxPorHora {
+		return ErrContaLimiteEnvios
+	}
+	token, err := gerarTokenConta()
+	if err != nil {
+		return err
+	}
+	validade := models.TokenContaResetSenhaValidade
+	if tipo == models.TokenContaVerificacaoEmail {
+		validade = models.TokenContaVerificacaoValidade
+	}
+	t := &models.TokenConta{UsuarioID: u.ID, Tipo: tipo, Email: u.Email, ExpiraEm: agora.Add(validade)}
+	if err := s.tokens.Create(ctx, t, hashRefreshToken(token)); err != nil {
+		return err
+	}
+	return s.mailer.Enviar(ctx, NotificacaoDestinatario{UsuarioID: u.ID, Nome: u.Nome, Email: u.Email}, s.mensagemConta(u, tipo, token))
+}
+
+func (s *ContaService) mensagemConta(u *models.Usuario, tipo, token string) NotificacaoMensagem {
+	nome := strings.TrimSpace(u.Nome)
+	if nome == "" {
+		nome = "olá"
+	}
+	if tipo == models.TokenContaResetSenha {
+		return NotificacaoMensagem{
+			Titulo: "CeialMilk — redefinir senha",
+			Corpo: fmt.Sprintf("%s,\n\nRecebemos um pedido para redefinir a senha da sua conta. Abra o link abaixo em até 1 hora "+
+				"para escolher uma nova senha (ele só funciona uma vez):\n\n%s\n\n"+
+				"Se não foi você, ignore este e-mail: a senha atual continua válida.",
+				nome, s.linkConta("/redefinir-senha", token)),
+		}
+	}
+	return NotificacaoMensagem{
+		Titulo: "CeialMilk — confirme seu e-mail",
+		Corpo: fmt.Sprintf("%s,\n\nConfirme o e-mail da sua conta abrindo o link abaixo (válido por 48 horas):\n\n%s",
+			nome, s.linkConta("/verificar-email", token)),
+	}
+}
+
+// SolicitarResetSenha envia o link de redefinição. A resposta ao cliente não pode revelar se o e-mail
+// existe: conta inexistente, desativada ou acima do limite terminam sem erro (só log).
+func (s *ContaService) SolicitarResetSenha(ctx context.Context, email string) error {
+	if !s.mailDisponivel() {
+		return ErrContaMailIndisponivel
+	}
+	u, err := s.usuarios.GetByEmail(ctx, strings.TrimSpace(email))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !u.Enabled {
+		return nil
+	}
+	if err := s.emitir(ctx, u, models.TokenContaResetSenha); err != nil {
+		slog.Warn("conta: reset de senha não enviado", "usuario_id", u.ID, "error", err)
+	}
+	return nil
+}
+
+func validarNovaSenha(senha string) error {
+	if utf8.RuneCountInString(senha) < contaSenhaMinLen {
+		return ErrContaSenhaCurta
+	}
+	return nil
+}
+
+// RedefinirSenha troca a senha pelo token do e-mail e encerra todas as sessões da conta.
+func (s *ContaService) RedefinirSenha(ctx context.Context, token, novaSenha string) error {
+	if err := validarNovaSenha(novaSenha); err != nil {
+		return err
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return ErrContaTokenInvalido
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(novaSenha), bcryptCost)
+	if err != nil {
+		return err
+	}
+	usuarioID, err := s.tokens.RedefinirSenha(ctx, hashRefreshToken(token), string(hash), s.now())
+	if errors.Is(err, repository.ErrTokenContaIndisponivel) {
+		return ErrContaTokenInvalido
+	}
+	if err != nil {
+		return err
+	}
+	s.auditarSenha(ctx, usuarioID, "REDEFINIDA_POR_EMAIL")
+	return nil
+}
+
+// AlterarSenha troca a senha de quem está autenticado (confirma a atual) e revoga todos os refresh
+// tokens; o handler emite uma sessão nova só para o dispositivo que fez a troca.
+func (s *ContaService) AlterarSenha(ctx context.Context, usuarioID int64, senhaAtual, novaSenha string) error {
+	if err := validarNovaSenha(novaSenha); err != nil {
+		return err
+	}
+	u, err := s.usuarios.GetByID(ctx, usuarioID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUsuarioNotFound
+		}
+		return err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(u.Senha), []byte(senhaAtual)) != nil {
+		return ErrContaSenhaAtualIncorreta
+	}
+	if senhaAtual == novaSenha {
+		return ErrContaSenhaIgual
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(novaSenha), bcryptCost)
+	if err != nil {
+		return err
+	}
+	if err := s.tokens.AlterarSenha(ctx, usuarioID, string(hash), s.now()); err != nil {
+		return err
+	}
+	s.auditarSenha(ctx, usuarioID, "ALTERADA")
+	return nil
+}
+
+// auditarSenha registra a troca sem o hash (segredos não entram no diff, BR-AUDIT-012). A chave não
+// pode ser "senha": ela está em camposIgnoradosAuditoria e o diff sairia vazio.
+func (s *ContaService) auditarSenha(ctx context.Context, usuarioID int64, como string) {
+	if _, ok := requestctx.AtorFromContext(ctx); !ok {
+		ctx = requestctx.WithAtor(ctx, requestctx.Ator{UsuarioID: usuarioID})
+	}
+	s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeUsuario, usuarioID, 0, 0,
+		map[string]string{}, map[string]string{"senha_alterada": como})
+}
+
+// EnviarVerificacao (re)envia o link de confirmação do e-mail da conta.
+func (s *ContaService) EnviarVerificacao(ctx context.Context, usuarioID int64) error {
+	u, err := s.usuarios.GetByID(ctx, usuarioID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUsuarioNotFound
+		}
+		return err
+	}
+	if u.EmailVerificadoEm != nil {
+		return ErrContaEmailJaVerificado
+	}
+	if !s.mailDisponivel() {
+		return ErrContaMailIndisponivel
+	}
+	return s.emitir(ctx, u, models.TokenContaVerificacaoEmail)
+}
+
+// VerificarEmail confirma o e-mail pelo token do link.
+func (s *ContaService) VerificarEmail(ctx context.Context, token string) error {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return ErrContaTokenInvalido
+	}
+	usuarioID, err := s.tokens.VerificarEmail(ctx, hashRefreshToken(token), s.now())
+	if errors.Is(err, repository.ErrTokenContaIndisponivel) {
+		return ErrContaTokenInvalido
+	}
+	if err != nil {
+		return err
+	}
+	if _, ok := requestctx.AtorFromContext(ctx); !ok {
+		ctx = requestctx.WithAtor(ctx, requestctx.Ator{UsuarioID: usuarioID})
+	}
+	s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeUsuario, usuarioID, 0, 0,
+		map[string]bool{"email_verificado": false}, map[string]bool{"email_verificado": true})
+	return nil
+}
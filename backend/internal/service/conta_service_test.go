@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/repository"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type fakeContaTokens struct {
+	criados     []models.TokenConta
+	hashes      []string
+	recentes    int
+	senhaHash   string
+	alterouPara int64
+	resetHash   string
+	verifHash   string
+}
+
+func (f *fakeContaTokens) Create(_ context.Context, t *models.TokenConta, h string) error {
+	f.criados = append(f.criados, *t)
+	f.hashes = append(f.hashes, h)
+	return nil
+}
+
+func (f *fakeContaTokens) CountDesde(context.Context, int64, string, time.Time) (int, error) {
+	return f.recentes, nil
+}
+
+func (f *fakeContaTokens) RedefinirSenha(_ context.Context, h, senhaHash string, _ time.Time) (int64, error) {
+	if h != f.resetHash {
+		return 0, repository.ErrTokenContaIndisponivel
+	}
+	f.resetHash = ""
+	f.senhaHash = senhaHash
+	return 9, nil
+}
+
+func (f *fakeContaTokens) AlterarSenha(_ context.Context, usuarioID int64, senhaHash string, _ time.Time) error {
+	f.alterouPara = usuarioID
+	f.senhaHash = senhaHash
+	return nil
+}
+
+func (f *fakeContaTokens) VerificarEmail(_ context.Context, h string, _ time.Time) (int64, error) {
+	if h != f.verifHash {
+		return 0, repository.ErrTokenContaIndisponivel
+	}
+	return 9, nil
+}
+
+type fakeContaUsuarios struct {
+	u *models.Usuario
+}
+
+func (f fakeContaUsuarios) GetByEmail(_ context.Context, email string) (*models.Usuario, error) {
+	if f.u == nil || f.u.Email != email {
+		return nil, pgx.ErrNoRows
+	}
+	return f.u, nil
+}
+
+func (f fakeContaUsuarios) GetByID(context.Context, int64) (*models.Usuario, error) {
+	if f.u == nil {
+		return nil, pgx.ErrNoRows
+	}
+	return f.u, nil
+}
+
+type fakeMailer struct {
+	enabled  bool
+	enviados []NotificacaoMensagem
+}
+
+func (f *fakeMailer) Enabled() bool { return f.enabled }
+
+func (f *fakeMailer) Enviar(_ context.Context, _ NotificacaoDestinatario, m NotificacaoMensagem) error {
+	f.enviados = append(f.enviados, m)
+	return nil
+}
+
+// tokenDoCorpo extrai o token do link enviado no e-mail.
+func tokenDoCorpo(t *testing.T, corpo string) string {
+	t.Helper()
+	i := strings.Index(corpo, "?token=")
+	if i < 0 {
+		t.Fatalf("corpo sem link: %q", corpo)
+	}
+	return strings.Fields(corpo[i+len("?token="):])[0]
+}
+
+func novoContaServiceTeste(u *models.Usuario) (*ContaService, *fakeContaTokens, *fakeMailer) {
+	tokens := &fakeContaTokens{}
+	mailer := &fakeMailer{enabled: true}
+	agora := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	return &ContaService{
+		tokens:   tokens,
+		usuarios: fakeContaUsuarios{u: u},
+		mailer:   mailer,
+		baseURL:  "https://app.exemplo.pt",
+		now:      func() time.Time { return agora },
+	}, tokens, mailer
+}
+
+func TestSolicitarResetSenha(t *testing.T) {
+	u := &models.Usuario{ID: 9, Nome: "Ana", Email: "ana@exemplo.pt", Enabled: true}
+	s, tokens, mailer := novoContaServiceTeste(u)
+
+	if err := s.SolicitarResetSenha(context.Background(), " ana@exemplo.pt "); err != nil {
+		t.Fatal(err)
+	}
+	if len(tokens.criados) != 1 || len(mailer.enviados) != 1 {
+		t.Fatalf("criados %d enviados %d", len(tokens.criados), len(mailer.enviados))
+	}
+	criado := tokens.criados[0]
+	if criado.Tipo != models.TokenContaResetSenha || !criado.ExpiraEm.Equal(s.now().Add(time.Hour)) {
+		t.Errorf("token = %+v", criado)
+	}
+	corpo := mailer.enviados[0].Corpo
+	if !strings.Contains(corpo, "https://app.exemplo.pt/redefinir-senha?token=") {
+		t.Errorf("link absoluto ausente: %q", corpo)
+	}
+	token := tokenDoCorpo(t, corpo)
+	if tokens.hashes[0] != hashRefreshToken(token) || strings.Contains(tokens.hashes[0], token) {
+		t.Error("só o hash do token deve ser gravado")
+	}
+
+	// Conta inexistente e limite atingido: sem erro para não revelar nada ao cliente.
+	if err := s.SolicitarResetSenha(context.Background(), "outro@exemplo.pt"); err != nil {
+		t.Errorf("inexistente: err = %v", err)
+	}
+	tokens.recentes = models.TokenContaMaxPorHora
+	if err := s.SolicitarResetSenha(context.Background(), "ana@exemplo.pt"); err != nil {
+		t.Errorf("limite: err = %v", err)
+	}
+	if len(mailer.enviados) != 1 {
+		t.Errorf("enviados = %d, want 1", len(mailer.enviados))
+	}
+
+	mailer.enabled = false
+	if err := s.SolicitarResetSenha(context.Background(), "ana@exemplo.pt"); !errors.Is(err, ErrContaMailIndisponivel) {
+		t.Errorf("sem mailer: err = %v", err)
+	}
+}
+
+func TestRedefinirSenhaConta(t *testing.T) {
+	s, tokens, _ := novoContaServiceTeste(&models.Usuario{ID: 9})
+	tokens.resetHash = hashRefreshToken("tok")
+
+	if err := s.RedefinirSenha(context.Background(), "tok", "curta"); !errors.Is(err, ErrContaSenhaCurta) {
+		t.Errorf("senha curta: err = %v", err)
+	}
+	if err := s.RedefinirSenha(context.Background(), "tok", "nova-senha-1"); err != nil {
+		t.Fatal(err)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(tokens.senhaHash), []byte("nova-senha-1")) != nil {
+		t.Error("hash bcrypt da nova senha não gravado")
+	}
+	if err := s.RedefinirSenha(context.Background(), "tok", "nova-senha-2"); !errors.Is(err, ErrContaTokenInvalido) {
+		t.Errorf("token reutilizado: err = %v", err)
+	}
+}
+
+func TestAlterarSenhaConta(t *testing.T) {
+	atual, _ := bcrypt.GenerateFromPassword([]byte("senha-atual"), bcrypt.MinCost)
+	s, tokens, _ := novoContaServiceTeste(&models.Usuario{ID: 9, Senha: string(atual)})
+
+	if err := s.AlterarSenha(context.Background(), 9, "errada!!", "nova-senha-1"); !errors.Is(err, ErrContaSenhaAtualIncorreta) {
+		t.Errorf("senha atual errada: err = %v", err)
+	}
+	if err := s.AlterarSenha(context.Background(), 9, "senha-atual", "senha-atual"); !errors.Is(err, ErrContaSenhaIgual) {
+		t.Errorf("mesma senha: err = %v", err)
+	}
+	if err := s.AlterarSenha(context.Background(), 9, "senha-atual", "nova-senha-1"); err != nil {
+		t.Fatal(err)
+	}
+	if tokens.alterouPara != 9 {
+		t.Errorf("alterouPara = %d", tokens.alterouPara)
+	}
+}
+
+func TestAuditoriaTrocaSenhaConta(t *testing.T) {
+	atual, _ := bcrypt.GenerateFromPassword([]byte("senha-atual"), bcrypt.MinCost)
+	s, tokens, _ := novoContaServiceTeste(&models.Usuario{ID: 9, Senha: string(atual)})
+	store := &fakeAuditoriaStore{}
+	s.SetAuditoria(NewAuditoriaService(store))
+	tokens.resetHash = hashRefreshToken("tok")
+
+	if err := s.RedefinirSenha(context.Background(), "tok", "nova-senha-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AlterarSenha(context.Background(), 9, "senha-atual", "nova-senha-2"); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.eventos) != 2 {
+		t.Fatalf("eventos = %d, want 2", len(store.eventos))
+	}
+	for i, como := range []string{"REDEFINIDA_POR_EMAIL", "ALTERADA"} {
+		e := store.eventos[i]
+		if e.Acao != models.AuditoriaAcaoUpdate || e.Entidade != models.AuditoriaEntidadeUsuario || e.EntidadeID != 9 {
+			t.Errorf("evento %d = %+v", i, e)
+		}
+		if e.UsuarioID == nil || *e.UsuarioID != 9 {
+			t.Errorf("evento %d: ator = %v", i, e.UsuarioID)
+		}
+		var diff models.AuditoriaDiff
+		if err := json.Unmarshal(e.Diff, &diff); err != nil {
+			t.Fatalf("diff inválido: %v", err)
+		}
+		if len(diff.Depois) == 0 {
+			t.Fatalf("evento %d: diff vazio: %s", i, e.Diff)
+		}
+		if string(diff.Depois["senha_alterada"]) != `"`+como+`"` {
+			t.Errorf("evento %d: diff = %s", i, e.Diff)
+		}
+		if strings.Contains(string(e.Diff), "$2a$") {
+			t.Errorf("evento %d: hash da senha no diff", i)
+		}
+	}
+}
+
+func TestVerificacaoEmail(t *testing.T) {
+	u := &models.Usuario{ID: 9, Nome: "Ana", Email: "ana@exemplo.pt", Enabled: true}
+	s, tokens, mailer := novoContaServiceTeste(u)
+
+	if err := s.EnviarVerificacao(context.Background(), 9); err != nil {
+		t.Fatal(err)
+	}
+	if tokens.criados[0].Tipo != models.TokenContaVerificacaoEmail ||
+		!tokens.criados[0].ExpiraEm.Equal(s.now().Add(models.TokenContaVerificacaoValidade)) {
+		t.Errorf("token = %+v", tokens.criados[0])
+	}
+	token := tokenDoCorpo(t, mailer.enviados[0].Corpo)
+	tokens.verifHash = hashRefreshToken(token)
+	if err := s.VerificarEmail(context.Background(), token); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.VerificarEmail(context.Background(), "outro"); !errors.Is(err, ErrContaTokenInvalido) {
+		t.Errorf("token desconhecido: err = %v", err)
+	}
+
+	tokens.recentes = models.TokenContaMaxPorHora
+	if err := s.EnviarVerificacao(context.Background(), 9); !errors.Is(err, ErrContaLimiteEnvios) {
+		t.Errorf("limite: err = %v", err)
+	}
+	agora := s.now()
+	u.EmailVerificadoEm = &agora
+	if err := s.EnviarVerificacao(context.Background(), 9); !errors.Is(err, ErrContaEmailJaVerificado) {
+		t.Errorf("já verificado: err = %v", err)
+	}
+}
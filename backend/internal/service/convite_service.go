@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/repository"
+	"github.com/ceialmilk/api/internal/requestctx"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrConviteNotFound            = errors.New("convite não encontrado")
+	ErrConviteInvalido            = errors.New("código de convite inválido, já usado, revogado ou expirado")
+	ErrConviteForbidden           = errors.New("sem permissão para gerenciar convites desta fazenda")
+	ErrConvitePerfilInvalido      = errors.New("perfil não pode ser oferecido por este convite")
+	ErrConvitePapelInvalido       = errors.New("papel deve ser TITULAR ou OPERACIONAL (TITULAR só por ADMIN/DEVELOPER)")
+	ErrConviteValidadeInvalida    = errors.New("validade do convite deve ser de 1 a 30 dias")
+	ErrConviteObservacaoLonga     = errors.New("observação do convite deve ter até 200 caracteres")
+	ErrConviteNaoPendente         = errors.New("só convites pendentes podem ser revogados")
+	ErrConviteJaVinculado         = errors.New("você já está vinculado a esta fazenda")
+	ErrConviteUsuarioIndisponivel = errors.New("usuário desativado não pode resgatar convite")
+)
+
+// Alfabeto do código sem caracteres confundíveis (0/O, 1/I): 32 símbolos, 60 bits em 12 posições.
+const (
+	conviteAlfabeto    = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	conviteCodigoLen   = 12
+	conviteGrupoLen    = 4
+	conviteListaLimite = 100
+)
+
+type conviteStore interface {
+	Create(ctx context.Context, c *models.Convite, codigoHash string) error
+	ListByFazenda(ctx context.Context, fazendaID int64, limit int) ([]models.Convite, error)
+	GetByID(ctx context.Context, fazendaID, id int64) (*models.Convite, error)
+	GetByCodigoHash(ctx context.Context, codigoHash string) (*models.Convite, error)
+	Revogar(ctx context.Context, fazendaID, id, actorID int64, em time.Time) error
+	Resgatar(ctx context.Context, conviteID, usuarioID int64, perfilNovo string, em time.Time) error
+}
+
+type conviteFazendaStore interface {
+	GetByID(ctx context.Context, id int64) (*models.Fazenda, error)
+	GetPapelVinculo(ctx context.Context, usuarioID, fazendaID int64) (string, error)
+}
+
+type conviteUsuarioStore interface {
+	GetByID(ctx context.Context, id int64) (*models.Usuario, error)
+}
+
+// ConviteService convites de acesso a uma fazenda (BR-ACESSO-010): emissão por ADMIN/DEVELOPER ou pelo
+// titular, resgate no registro, no login ou já autenticado, revogação e trilha na auditoria.
+type ConviteService struct {
+	auditavel
+	repo     conviteStore
+	fazendas conviteFazendaStore
+	usuarios conviteUsuarioStore
+	now      func() time.Time
+}
+
+func NewConviteService(repo *repository.ConviteRepository, fazendaRepo *repository.FazendaRepository, usuarioRepo *repository.UsuarioRepository) *ConviteService {
+	return &ConviteService{repo: repo, fazendas: fazendaRepo, usuarios: usuarioRepo, now: time.Now}
+}
+
+// gerarCodigoConvite devolve o código formatado (XXXX-XXXX-XXXX) e o prefixo exibido na listagem.
+func gerarCodigoConvite() (codigo, prefixo string, err error) {
+	b := make([]byte, conviteCodigoLen)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	var sb strings.Builder
+	for i, v := range b {
+		if i > 0 && i%conviteGrupoLen == 0 {
+			sb.WriteByte('-')
+		}
+		// 256 é múltiplo de 32: o módulo não favorece nenhum símbolo.
+		sb.WriteByte(conviteAlfabeto[int(v)%len(conviteAlfabeto)])
+	}
+	codigo = sb.String()
+	return codigo, codigo[:conviteGrupoLen], nil
+}
+
+// normalizarCodigoConvite aceita o código digitado com minúsculas, espaços ou sem hífens.
+func normalizarCodigoConvite(codigo string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToUpper(codigo) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+func hashCodigoConvite(codigo string) string {
+	return hashRefreshToken(normalizarCodigoConvite(codigo))
+}
+
+// autorizarEmissor ADMIN/DEVELOPER em qualquer fazenda existente; PROPRIETARIO só onde é TITULAR.
+func (s *ConviteService) autorizarEmissor(ctx context.Context, fazendaID, actorID int64, perfil string) (*models.Fazenda, error) {
+	if !models.PodeCriarConvite(perfil) {
+		return nil, ErrConviteForbidden
+	}
+	fz, err := s.fazendas.GetByID(ctx, fazendaID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrFazendaNotFound
+		}
+		return nil, err
+	}
+	if perfil == models.PerfilProprietario {
+		papel, err := s.fazendas.GetPapelVinculo(ctx, actorID, fazendaID)
+		if err != nil {
+			return nil, err
+		}
+		if papel != models.PapelVinculoTitular {
+			return nil, ErrConviteForbidden
+		}
+	}
+	return fz, nil
+}
+
+type CriarConviteInput struct {
+	Perfil       string
+	Papel        string
+	ValidadeDias int
+	Observacao   *string
+	ActorUserID  int64
+	ActorPerfil  string
+}
+
+func validarConviteInput(in *CriarConviteInput) error {
+	if !slices.Contains(models.PerfisConvidaveis(in.ActorPerfil), in.Perfil) {
+		return ErrConvitePerfilInvalido
+	}
+	if in.Papel == "" {
+		in.Papel = models.PapelVinculoOperacional
+	}
+	if !models.PodeConvidarComPapel(in.ActorPerfil, in.Papel) {
+		return ErrConvitePapelInvalido
+	}
+	if in.ValidadeDias == 0 {
+		in.ValidadeDias = models.ConviteValidadeDiasPadrao
+	}
+	if in.ValidadeDias < 1 || in.ValidadeDias > models.ConviteValidadeDiasMax {
+		return ErrConviteValidadeInvalida
+	}
+	if in.Observacao != nil {
+		obs := strings.TrimSpace(*in.Observacao)
+		if obs == "" {
+			in.Observacao = nil
+		} else if len([]rune(obs)) > models.ConviteObservacaoMaxLen {
+			return ErrConviteObservacaoLonga
+		} else {
+			in.Observacao = &obs
+		}
+	}
+	return nil
+}
+
+// Criar emite o convite e devolve o código em claro, que não fica guardado.
+func (s *ConviteService) Criar(ctx context.Context, fazendaID int64, in CriarConviteInput) (*models.Convite, string, error) {
+	fz, err := s.autorizarEmissor(ctx, fazendaID, in.ActorUserID, in.ActorPerfil)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := validarConviteInput(&in); err != nil {
+		return nil, "", err
+	}
+	codigo, prefixo, err := gerarCodigoConvite()
+	if err != nil {
+		return nil, "", err
+	}
+	actor := in.ActorUserID
+	c := &models.Convite{
+		FazendaID:     fazendaID,
+		FazendaNome:   fz.Nome,
+		Perfil:        in.Perfil,
+		Papel:         in.Papel,
+		CodigoPrefixo: prefixo,
+		Observacao:    in.Observacao,
+		ExpiraEm:      s.now().Add(time.Duration(in.ValidadeDias) * 24 * time.Hour),
+		CriadoPor:     &actor,
+	}
+	if err := s.repo.Create(ctx, c, hashCodigoConvite(codigo)); err != nil {
+		return nil, "", err
+	}
+	c.Status = c.StatusEm(s.now())
+	s.auditar(ctx, models.AuditoriaAcaoCreate, models.AuditoriaEntidadeConvite, c.ID, fazendaID, 0, nil, c)
+	return c, codigo, nil
+}
+
+func (s *ConviteService) List(ctx context.Context, fazendaID, actorID int64, perfil string) ([]models.Convite, error) {
+	if _, err := s.autorizarEmissor(ctx, fazendaID, actorID, perfil); err != nil {
+		return nil, err
+	}
+	list, err := s.repo.ListByFazenda(ctx, fazendaID, conviteListaLimite)
+	if err != nil {
+		return nil, err
+	}
+	agora := s.now()
+	for i := range list {
+		list[i].Status = list[i].StatusEm(agora)
+	}
+	return list, nil
+}
+
+// Revogar invalida um convite pendente; o registro fica para o histórico.
+func (s *ConviteService) Revogar(ctx context.Context, fazendaID, id, actorID int64, perfil string) (*models.Convite, error) {
+	if _, err := s.autorizarEmissor(ctx, fazendaID, actorID, perfil); err != nil {
+		return nil, err
+	}
+	antes, err := s.repo.GetByID(ctx, fazendaID, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrConviteNotFound
+		}
+		return nil, err
+	}
+	agora := s.now()
+	if err := s.repo.Revogar(ctx, fazendaID, id, actorID, agora); err != nil {
+		if errors.Is(err, repository.ErrConviteIndisponivel) {
+			return nil, ErrConviteNaoPendente
+		}
+		return nil, err
+	}
+	depois := *antes
+	depois.RevogadoPor = &actorID
+	depois.RevogadoEm = &agora
+	depois.Status = models.ConviteStatusRevogado
+	antes.Status = antes.StatusEm(agora)
+	s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeConvite, id, fazendaID, 0, antes, &depois)
+	return &depois, nil
+}
+
+// pendentePorCodigo resolve o código; qualquer situação que não PENDENTE vira ErrConviteInvalido
+// (quem só tem o código não precisa saber se existiu).
+func (s *ConviteService) pendentePorCodigo(ctx context.Context, codigo string) (*models.Convite, error) {
+	if len(normalizarCodigoConvite(codigo)) != conviteCodigoLen {
+		return nil, ErrConviteInvalido
+	}
+	c, err := s.repo.GetByCodigoHash(ctx, hashCodigoConvite(codigo))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrConviteInvalido
+		}
+		return nil, err
+	}
+	if c.StatusEm(s.now()) != models.ConviteStatusPendente {
+		return nil, ErrConviteInvalido
+	}
+	return c, nil
+}
+
+// Preview mostra fazenda, perfil e papel do convite para o registro ou login confirmar.
+func (s *ConviteService) Preview(ctx context.Context, codigo string) (*models.ConvitePreview, error) {
+	c, err := s.pendentePorCodigo(ctx, codigo)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ConvitePreview{FazendaNome: c.FazendaNome, Perfil: c.Perfil, Papel: c.Papel, ExpiraEm: c.ExpiraEm}, nil
+}
+
+// Resgatar vincula o usuário à fazenda do convite com o papel indicado e, se ele ainda é USER
+// (pendente de provisão), atribui o perfil do convite. No registro e no login não há ator no
+// contexto: o próprio usuário passa a ser o ator da auditoria.
+func (s *ConviteService) Resgatar(ctx context.Context, codigo string, usuarioID int64) (*models.ConviteResgate, error) {
+	c, err := s.pendentePorCodigo(ctx, codigo)
+	if err != nil {
+		return nil, err
+	}
+	u, err := s.usuarios.GetByID(ctx, usuarioID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUsuarioNotFound
+		}
+		return nil, err
+	}
+	if !u.Enabled {
+		return nil, ErrConviteUsuarioIndisponivel
+	}
+	perfilNovo := models.PerfilAposConvite(u.Perfil, c.Perfil)
+	agora := s.now()
+	if err := s.repo.Resgatar(ctx, c.ID, u.ID, perfilNovo, agora); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrConviteIndisponivel):
+			return nil, ErrConviteInvalido
+		case errors.Is(err, repository.ErrConviteJaVinculado):
+			return nil, ErrConviteJaVinculado
+		}
+		return nil, err
+	}
+
+	if _, ok := requestctx.AtorFromContext(ctx); !ok {
+		ctx = requestctx.WithAtor(ctx, requestctx.Ator{UsuarioID: u.ID, Perfil: u.Perfil})
+	}
+	antes := *c
+	antes.Status = models.ConviteStatusPendente
+	depois := *c
+	depois.UsadoPor = &u.ID
+	depois.UsadoPorNome = &u.Nome
+	depois.UsadoEm = &agora
+	depois.Status = models.ConviteStatusUsado
+	s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeConvite, c.ID, c.FazendaID, 0, &antes, &depois)
+	if perfilNovo != u.Perfil {
+		s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeUsuario, u.ID, 0, 0,
+			map[string]string{"perfil": u.Perfil}, map[string]string{"perfil": perfilNovo})
+	}
+
+	return &models.ConviteResgate{
+		FazendaID:      c.FazendaID,
+		FazendaNome:    c.FazendaNome,
+		Papel:          c.Papel,
+		Perfil:         perfilNovo,
+		PerfilAlterado: perfilNovo != u.Perfil,
+	}, nil
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestGerarCodigoConvite(t *testing.T) {
+	formato := regexp.MustCompile(`^[A-HJ-NP-Z2-9]{4}-[A-HJ-NP-Z2-9]{4}-[A-HJ-NP-Z2-9]{4}$`)
+	vistos := map[string]bool{}
+	for i := 0; i < 50; i++ {
+		codigo, prefixo, err := gerarCodigoConvite()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !formato.MatchString(codigo) || prefixo != codigo[:4] {
+			t.Fatalf("código %q prefixo %q", codigo, prefixo)
+		}
+		if vistos[codigo] {
+			t.Fatalf("código repetido %q", codigo)
+		}
+		vistos[codigo] = true
+	}
+	if len(conviteAlfabeto) != 32 {
+		t.Errorf("alfabeto com %d símbolos; o módulo de um byte exige 32", len(conviteAlfabeto))
+	}
+}
+
+func TestNormalizarCodigoConvite(t *testing.T) {
+	if got := normalizarCodigoConvite(" abcd-efgh 2345 "); got != "ABCDEFGH2345" {
+		t.Errorf("normalizado = %q", got)
+	}
+	if hashCodigoConvite("abcd-efgh-2345") != hashCodigoConvite("ABCDEFGH2345") {
+		t.Error("hash deve ignorar formatação do código digitado")
+	}
+}
+
+func TestValidarConviteInput(t *testing.T) {
+	in := CriarConviteInput{Perfil: models.PerfilFuncionario, ActorPerfil: models.PerfilProprietario}
+	if err := validarConviteInput(&in); err != nil {
+		t.Fatal(err)
+	}
+	if in.Papel != models.PapelVinculoOperacional || in.ValidadeDias != models.ConviteValidadeDiasPadrao {
+		t.Errorf("padrões = %+v", in)
+	}
+
+	longa := string(make([]rune, 201))
+	casos := []struct {
+		nome string
+		in   CriarConviteInput
+		want error
+	}{
+		{"proprietário não convida GESTAO", CriarConviteInput{Perfil: models.PerfilGestao, ActorPerfil: models.PerfilProprietario}, ErrConvitePerfilInvalido},
+		{"ninguém convida ADMIN", CriarConviteInput{Perfil: models.PerfilAdmin, ActorPerfil: models.PerfilAdmin}, ErrConvitePerfilInvalido},
+		{"proprietário não convida titular", CriarConviteInput{Perfil: models.PerfilGerente, Papel: models.PapelVinculoTitular, ActorPerfil: models.PerfilProprietario}, ErrConvitePapelInvalido},
+		{"papel desconhecido", CriarConviteInput{Perfil: models.PerfilGerente, Papel: "SOCIO", ActorPerfil: models.PerfilAdmin}, ErrConvitePapelInvalido},
+		{"validade longa", CriarConviteInput{Perfil: models.PerfilGerente, ValidadeDias: 31, ActorPerfil: models.PerfilAdmin}, ErrConviteValidadeInvalida},
+		{"observação longa", CriarConviteInput{Perfil: models.PerfilGerente, Observacao: &longa, ActorPerfil: models.PerfilAdmin}, ErrConviteObservacaoLonga},
+	}
+	for _, c := range casos {
+		if err := validarConviteInput(&c.in); !errors.Is(err, c.want) {
+			t.Errorf("%s: err = %v, want %v", c.nome, err, c.want)
+		}
+	}
+
+	titular := CriarConviteInput{Perfil: models.PerfilProprietario, Papel: models.PapelVinculoTitular, ActorPerfil: models.PerfilDeveloper}
+	if err := validarConviteInput(&titular); err != nil {
+		t.Errorf("DEVELOPER convida titular: %v", err)
+	}
+}
+
+type fakeConviteStore struct {
+	conviteStore
+	convite      *models.Convite
+	resgatou     bool
+	perfilNovo   string
+	errResgatar  error
+	codigoHashes []string
+}
+
+func (f *fakeConviteStore) GetByCodigoHash(_ context.Context, h string) (*models.Convite, error) {
+	f.codigoHashes = append(f.codigoHashes, h)
+	if f.convite == nil {
+		return nil, pgx.ErrNoRows
+	}
+	c := *f.convite
+	return &c, nil
+}
+
+func (f *fakeConviteStore) Resgatar(_ context.Context, _, _ int64, perfilNovo string, _ time.Time) error {
+	if f.errResgatar != nil {
+		return f.errResgatar
+	}
+	f.resgatou = true
+	f.perfilNovo = perfilNovo
+	return nil
+}
+
+type fakeConviteFazendas struct {
+	papel string
+}
+
+func (f fakeConviteFazendas) GetByID(_ context.Context, id int64) (*models.Fazenda, error) {
+	return &models.Fazenda{ID: id, Nome: "Sítio"}, nil
+}
+
+func (f fakeConviteFazendas) GetPapelVinculo(context.Context, int64, int64) (string, error) {
+	return f.papel, nil
+}
+
+type fakeConviteUsuarios struct {
+	u *models.Usuario
+}
+
+func (f fakeConviteUsuarios) GetByID(context.Context, int64) (*models.Usuario, error) {
+	return f.u, nil
+}
+
+func TestResgatarConvite(t *testing.T) {
+	agora := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	convite := &models.Convite{ID: 7, FazendaID: 3, FazendaNome: "Sítio", Perfil: models.PerfilFuncionario,
+		Papel: models.PapelVinculoOperacional, ExpiraEm: agora.Add(time.Hour)}
+	store := &fakeConviteStore{convite: convite}
+	s := &ConviteService{
+		repo:     store,
+		fazendas: fakeConviteFazendas{},
+		usuarios: fakeConviteUsuarios{u: &models.Usuario{ID: 9, Nome: "Ana", Perfil: models.PerfilUser, Enabled: true}},
+		now:      func() time.Time { return agora },
+	}
+
+	res, err := s.Resgatar(context.Background(), "abcd-efgh-2345", 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !store.resgatou || store.perfilNovo != models.PerfilFuncionario || !res.PerfilAlterado || res.FazendaID != 3 {
+		t.Errorf("resgate USER = %+v (perfilNovo %q)", res, store.perfilNovo)
+	}
+
+	s.usuarios = fakeConviteUsuarios{u: &models.Usuario{ID: 9, Perfil: models.PerfilGerente, Enabled: true}}
+	res, _ = s.Resgatar(context.Background(), "ABCDEFGH2345", 9)
+	if res.Perfil != models.PerfilGerente || res.PerfilAlterado {
+		t.Errorf("perfil operacional não muda: %+v", res)
+	}
+
+	store.errResgatar = repository.ErrConviteJaVinculado
+	if _, err := s.Resgatar(context.Background(), "ABCDEFGH2345", 9); !errors.Is(err, ErrConviteJaVinculado) {
+		t.Errorf("já vinculado: err = %v", err)
+	}
+	store.errResgatar = nil
+
+	expirado := *convite
+	expirado.ExpiraEm = agora
+	store.convite = &expirado
+	if _, err := s.Resgatar(context.Background(), "ABCDEFGH2345", 9); !errors.Is(err, ErrConviteInvalido) {
+		t.Errorf("expirado: err = %v", err)
+	}
+
+	n := len(store.codigoHashes)
+	if _, err := s.Resgatar(context.Background(), "curto", 9); !errors.Is(err, ErrConviteInvalido) || len(store.codigoHashes) != n {
+		t.Errorf("código malformado não deve consultar o banco: err = %v", err)
+	}
+}
+
+func TestAutorizarEmissorConvite(t *testing.T) {
+	s := &ConviteService{fazendas: fakeConviteFazendas{papel: models.PapelVinculoOperacional}}
+	if _, err := s.autorizarEmissor(context.Background(), 1, 2, models.PerfilProprietario); !errors.Is(err, ErrConviteForbidden) {
+		t.Errorf("proprietário sem titularidade: err = %v", err)
+	}
+	s.fazendas = fakeConviteFazendas{papel: models.PapelVinculoTitular}
+	if _, err := s.autorizarEmissor(context.Background(), 1, 2, models.PerfilProprietario); err != nil {
+		t.Errorf("titular: err = %v", err)
+	}
+	if _, err := s.autorizarEmissor(context.Background(), 1, 2, models.PerfilGerente); !errors.Is(err, ErrConviteForbidden) {
+		t.Errorf("gerente: err = %v", err)
+	}
+	s.fazendas = fakeConviteFazendas{}
+	if _, err := s.autorizarEmissor(context.Background(), 1, 2, models.PerfilAdmin); err != nil {
+		t.Errorf("admin sem vínculo: err = %v", err)
+	}
+}
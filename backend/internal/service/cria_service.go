@@ -14,7 +14,17 @@ import (
 
 var ErrCriaNotFound = errors.New("cria nao encontrada")
 
+// ErrCriaIdentificacaoEmUso impede recriar o animal de uma cria cuja identificação já foi reutilizada.
+var ErrCriaIdentificacaoEmUso = errors.New("identificacao do animal gerado pela cria ja esta em uso")
+
+// criaAnimalRemovido é o snapshot de um animal removido junto com o parto (partos.excluido_animais_gerados).
+type criaAnimalRemovido struct {
+	CriaID int64          `json:"cria_id"`
+	Animal *models.Animal `json:"animal"`
+}
+
 type CriaService struct {
+	auditavel
 	pool       *pgxpool.Pool
 	repo       *repository.CriaRepository
 	partoRepo  *repository.PartoRepository
@@ -71,7 +81,11 @@ func (s *CriaService) Create(ctx context.Context, c *models.Cria) error {
 	clearCriaTransientAnimalFields(c)
 
 	if c.Condicao != models.CriaCondicaoVivo || c.AnimalID != nil {
-		return s.repo.Create(ctx, c)
+		if err := s.repo.Create(ctx, c); err != nil {
+			return err
+		}
+		s.auditar(ctx, models.AuditoriaAcaoCreate, models.AuditoriaEntidadeCria, c.ID, parto.FazendaID, parto.AnimalID, nil, c)
+		return nil
 	}
 
 	tx, err := s.pool.Begin(ctx)
@@ -92,21 +106,32 @@ func (s *CriaService) Create(ctx context.Context, c *models.Cria) error {
 		return err
 	}
 
-	if err := s.insertCriaVivaComAnimalGeradoTx(ctx, tx, parto, c, identUser, racaPtr); err != nil {
+	gerado, err := s.insertCriaVivaComAnimalGeradoTx(ctx, tx, parto, c, identUser, racaPtr)
+	if err != nil {
 		return err
 	}
 	if err := tx.Commit(ctx); err != nil {
 		return err
 	}
 	committed = true
+	s.auditarCriaCriada(ctx, parto, c, gerado)
 	return nil
 }
 
+// auditarCriaCriada regista a cria e o animal gerado (se houver) após o commit.
+func (s *CriaService) auditarCriaCriada(ctx context.Context, parto *models.Parto, c *models.Cria, gerado *models.Animal) {
+	s.auditar(ctx, models.AuditoriaAcaoCreate, models.AuditoriaEntidadeCria, c.ID, parto.FazendaID, parto.AnimalID, nil, c)
+	if gerado != nil {
+		s.auditar(ctx, models.AuditoriaAcaoCreate, models.AuditoriaEntidadeAnimal, gerado.ID, gerado.FazendaID, gerado.ID, nil, gerado)
+	}
+}
+
 // createCriaAsPartOfPartoTx insere uma cria dentro de uma transação já aberta (ex.: parto + N crias).
 // O registo do parto em `parto` deve estar persistido na mesma transação; `c.PartoID` deve coincidir com `parto.ID`.
-func (s *CriaService) createCriaAsPartOfPartoTx(ctx context.Context, tx pgx.Tx, parto *models.Parto, c *models.Cria) error {
+// Devolve o animal gerado para cria viva (nil caso contrário), para auditoria após o commit.
+func (s *CriaService) createCriaAsPartOfPartoTx(ctx context.Context, tx pgx.Tx, parto *models.Parto, c *models.Cria) (*models.Animal, error) {
 	if err := s.validateCriaCampos(c); err != nil {
-		return err
+		return nil, err
 	}
 	if c.PartoID != parto.ID {
 		c.PartoID = parto.ID
@@ -119,21 +144,21 @@ func (s *CriaService) createCriaAsPartOfPartoTx(ctx context.Context, tx pgx.Tx,
 	clearCriaTransientAnimalFields(c)
 
 	if c.Condicao != models.CriaCondicaoVivo || c.AnimalID != nil {
-		return s.repo.CreateTx(ctx, tx, c)
+		return nil, s.repo.CreateTx(ctx, tx, c)
 	}
 	return s.insertCriaVivaComAnimalGeradoTx(ctx, tx, parto, c, identUser, racaPtr)
 }
 
-func (s *CriaService) insertCriaVivaComAnimalGeradoTx(ctx context.Context, tx pgx.Tx, parto *models.Parto, c *models.Cria, identUser string, racaPtr *string) error {
+func (s *CriaService) insertCriaVivaComAnimalGeradoTx(ctx context.Context, tx pgx.Tx, parto *models.Parto, c *models.Cria, identUser string, racaPtr *string) (*models.Animal, error) {
 	cnt, err := s.repo.CountByPartoIDTx(ctx, tx, parto.ID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	n := int(cnt) + 1
 
 	ident, err := s.resolveIdentificacaoCriaVivaTx(ctx, tx, parto, c, n, identUser)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	categoria := models.CategoriaBezerra
@@ -157,16 +182,16 @@ func (s *CriaService) insertCriaVivaComAnimalGeradoTx(ctx context.Context, tx pg
 		CreatedBy:       parto.CreatedBy,
 	}
 	if err := s.repo.CreateTx(ctx, tx, c); err != nil {
-		return err
+		return nil, err
 	}
 	if err := s.animalRepo.CreateTx(ctx, tx, animal); err != nil {
-		return err
+		return nil, err
 	}
 	c.AnimalID = &animal.ID
 	if err := s.repo.UpdateTx(ctx, tx, c); err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	return animal, nil
 }
 
 func (s *CriaService) resolveIdentificacaoCriaVivaTx(ctx context.Context, tx pgx.Tx, parto *models.Parto, c *models.Cria, n int, identUser string) (string, error) {
@@ -330,11 +355,12 @@ func animalGeradoParaDesfazerComCria(p *models.Parto, a *models.Animal, c *model
 
 // DeleteAnimaisGeradosPorCriasDoPartoTx remove, na mesma transação, animais NASCIDO vinculados às crias do parto
 // (desfazer o nascimento). Não remove a matriz nem animais de outra origem ou sem vínculo de mãe com o parto.
-func (s *CriaService) DeleteAnimaisGeradosPorCriasDoPartoTx(ctx context.Context, tx pgx.Tx, p *models.Parto) error {
+func (s *CriaService) DeleteAnimaisGeradosPorCriasDoPartoTx(ctx context.Context, tx pgx.Tx, p *models.Parto) ([]criaAnimalRemovido, error) {
 	crias, err := s.repo.GetByPartoIDTx(ctx, tx, p.ID)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	var removidos []criaAnimalRemovido
 	seen := make(map[int64]struct{})
 	for _, c := range crias {
 		if c.AnimalID == nil {
@@ -353,28 +379,63 @@ func (s *CriaService) DeleteAnimaisGeradosPorCriasDoPartoTx(ctx context.Context,
 			if errors.Is(err, pgx.ErrNoRows) {
 				continue
 			}
-			return err
+			return nil, err
 		}
 		if !animalGeradoParaDesfazerComCria(p, animal, c) {
 			continue
 		}
 		if err := s.animalRepo.DeleteTx(ctx, tx, aid); err != nil {
-			return fmt.Errorf("excluir animal %d gerado pelo parto: %w", aid, err)
+			return nil, fmt.Errorf("excluir animal %d gerado pelo parto: %w", aid, err)
 		}
+		removidos = append(removidos, criaAnimalRemovido{CriaID: c.ID, Animal: animal})
 	}
-	return nil
+	return removidos, nil
+}
+
+// RestaurarAnimaisGeradosTx recria, com o id original, os animais removidos na exclusão do parto e revincula as crias.
+// Registos associados ao animal após o nascimento (pesagens, vacinas, etc.) não são recuperados.
+func (s *CriaService) RestaurarAnimaisGeradosTx(ctx context.Context, tx pgx.Tx, p *models.Parto, removidos []criaAnimalRemovido) ([]*models.Animal, error) {
+	restaurados := make([]*models.Animal, 0, len(removidos))
+	for _, r := range removidos {
+		if r.Animal == nil {
+			continue
+		}
+		taken, err := s.animalRepo.ExistsByIdentificacaoTx(ctx, tx, r.Animal.Identificacao)
+		if err != nil {
+			return nil, err
+		}
+		if taken {
+			return nil, fmt.Errorf("%w: %s", ErrCriaIdentificacaoEmUso, r.Animal.Identificacao)
+		}
+		if err := s.animalRepo.RestoreTx(ctx, tx, r.Animal); err != nil {
+			return nil, fmt.Errorf("recriar animal %d gerado pelo parto: %w", r.Animal.ID, err)
+		}
+		if err := s.repo.RelinkAnimalTx(ctx, tx, r.CriaID, p.ID, r.Animal.ID); err != nil {
+			return nil, err
+		}
+		restaurados = append(restaurados, r.Animal)
+	}
+	return restaurados, nil
 }
 
 func (s *CriaService) Update(ctx context.Context, c *models.Cria) error {
 	if c.ID <= 0 {
 		return errors.New("id invalido")
 	}
-	_, err := s.repo.GetByID(ctx, c.ID)
+	existing, err := s.repo.GetByID(ctx, c.ID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return ErrCriaNotFound
 		}
 		return err
 	}
-	return s.repo.Update(ctx, c)
+	if err := s.repo.Update(ctx, c); err != nil {
+		return err
+	}
+	var fazendaID, maeID int64
+	if parto, err := s.partoRepo.GetByID(ctx, c.PartoID); err == nil {
+		fazendaID, maeID = parto.FazendaID, parto.AnimalID
+	}
+	s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeCria, c.ID, fazendaID, maeID, existing, c)
+	return nil
 }
@@ -19,6 +19,7 @@ var (
 )
 
 type DiagnosticoGestacaoService struct {
+	auditavel
 	repo           *repository.DiagnosticoGestacaoRepository
 	animalRepo     *repository.AnimalRepository
 	gestacaoRepo   *repository.GestacaoRepository
@@ -80,6 +81,7 @@ func (s *DiagnosticoGestacaoService) Create(ctx context.Context, d *models.Diagn
 	if err := s.repo.Create(ctx, d); err != nil {
 		return err
 	}
+	s.auditar(ctx, models.AuditoriaAcaoCreate, models.AuditoriaEntidadeToque, d.ID, d.FazendaID, d.AnimalID, nil, d)
 	if d.Resultado == models.DiagnosticoResultadoNegativo {
 		status := models.StatusReprodutivoVazia
 		return s.animalRepo.UpdateStatusReprodutivo(ctx, d.AnimalID, &status)
@@ -105,6 +107,7 @@ func (s *DiagnosticoGestacaoService) Create(ctx context.Context, d *models.Diagn
 	if err := s.gestacaoRepo.Create(ctx, gestacao); err != nil {
 		return err
 	}
+	s.auditar(ctx, models.AuditoriaAcaoCreate, models.AuditoriaEntidadeGestacao, gestacao.ID, gestacao.FazendaID, gestacao.AnimalID, nil, gestacao)
 	status := models.StatusReprodutivoPrenhe
 	return s.animalRepo.UpdateStatusReprodutivo(ctx, d.AnimalID, &status)
 }
@@ -133,14 +136,18 @@ func (s *DiagnosticoGestacaoService) GetByFazendaIDFiltered(ctx context.Context,
 }
 
 func (s *DiagnosticoGestacaoService) Delete(ctx context.Context, id int64) error {
-	_, err := s.repo.GetByID(ctx, id)
+	existing, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return ErrDiagnosticoNotFound
 		}
 		return err
 	}
-	return s.repo.Delete(ctx, id)
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoDelete, models.AuditoriaEntidadeToque, id, existing.FazendaID, existing.AnimalID, existing, nil)
+	return nil
 }
 
 // resolveCoberturaIDForPositivo usa cobertura_id informado ou a cobertura mais recente do animal sem gestação vinculada.
@@ -0,0 +1,408 @@
+package service
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/repository"
+	"github.com/ceialmilk/api/internal/requestctx"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrDoisFatoresSenhaIncorreta = errors.New("senha incorreta")
+	ErrDoisFatoresCodigoInvalido = errors.New("código inválido ou já utilizado")
+	ErrDoisFatoresJaAtivo        = errors.New("a autenticação em dois fatores já está ativa")
+	ErrDoisFatoresInativo        = errors.New("a autenticação em dois fatores não está ativa")
+	ErrDoisFatoresSemInscricao   = errors.New("inicie a configuração da autenticação em dois fatores primeiro")
+	ErrDoisFatoresObrigatorio    = errors.New("o seu perfil exige autenticação em dois fatores; ela não pode ser desativada")
+	// ErrDoisFatoresDesafioInvalido desafio do login já usado, expirado ou sem tentativas: volta à senha.
+	ErrDoisFatoresDesafioInvalido = errors.New("sessão de login expirada; entre novamente")
+)
+
+// Códigos de recuperação: 10 símbolos do alfabeto dos convites (sem 0/O/1/I), exibidos como XXXXX-XXXXX.
+const (
+	recuperacaoCodigoLen = 10
+	recuperacaoGrupoLen  = 5
+)
+
+type doisFatoresStore interface {
+	Get(ctx context.Context, usuarioID int64) (*models.DoisFatores, error)
+	SalvarPendente(ctx context.Context, usuarioID int64, segredoCifrado string) error
+	Ativar(ctx context.Context, usuarioID, passo int64, codigoHashes []string, em time.Time) error
+	SubstituirCodigos(ctx context.Context, usuarioID int64, codigoHashes []string) error
+	RegistrarPasso(ctx context.Context, usuarioID, passo int64) error
+	UsarCodigoRecuperacao(ctx context.Context, usuarioID int64, codigoHash string, em time.Time) error
+	CountCodigosRestantes(ctx context.Context, usuarioID int64) (int, error)
+	Remover(ctx context.Context, usuarioID int64) (bool, error)
+	RegistrarDesafio(ctx context.Context, jti string, usuarioID int64, expiraEm, agora time.Time) error
+	ReservarTentativaDesafio(ctx context.Context, jti string, usuarioID int64, maxTentativas int, agora time.Time) error
+	ConsumirDesafio(ctx context.Context, jti string, em time.Time) error
+}
+
+type doisFatoresUsuarioStore interface {
+	GetByID(ctx context.Context, id int64) (*models.Usuario, error)
+}
+
+// InscricaoDoisFatores dados para cadastrar a conta no app autenticador (QR code do URI ou segredo digitado).
+type InscricaoDoisFatores struct {
+	Segredo string `json:"segredo"`
+	URI     string `json:"otpauth_uri"`
+}
+
+// DoisFatoresService inscrição, verificação e remoção do 2FA por TOTP (BR-ACESSO-027). O segredo é
+// cifrado com AES-GCM (precisa ser lido para validar códigos); os códigos de recuperação só como hash.
+type DoisFatoresService struct {
+	auditavel
+	repo     doisFatoresStore
+	usuarios doisFatoresUsuarioStore
+	aead     cipher.AEAD
+	// exige perfis obrigados a 2FA (mesma regra do AuthMiddleware); nil = nenhum.
+	exige   func(perfil string) bool
+	sessoes sessaoRevoker
+	now     func() time.Time
+}
+
+// NewDoisFatoresService chave deriva a chave AES-256 (SHA-256) que cifra os segredos TOTP.
+func NewDoisFatoresService(repo *repository.DoisFatoresRepository, usuarios *repository.UsuarioRepository, chave string) (*DoisFatoresService, error) {
+	aead, err := novaCifraDoisFatores(chave)
+	if err != nil {
+		return nil, err
+	}
+	return &DoisFatoresService{repo: repo, usuarios: usuarios, aead: aead, now: time.Now}, nil
+}
+
+func novaCifraDoisFatores(chave string) (cipher.AEAD, error) {
+	if strings.TrimSpace(chave) == "" {
+		return nil, errors.New("chave de cifra do 2FA vazia")
+	}
+	sum := sha256.Sum256([]byte(chave))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// SetExigeDoisFatores regra de perfis obrigados a 2FA (impede desativar).
+func (s *DoisFatoresService) SetExigeDoisFatores(fn func(perfil string) bool) {
+	s.exige = fn
+}
+
+// SetSessaoRevoker encerra as sessões na ativação e na redefinição pelo administrador.
+func (s *DoisFatoresService) SetSessaoRevoker(r sessaoRevoker) {
+	s.sessoes = r
+}
+
+// Obrigatorio indica se o perfil não pode ficar sem 2FA.
+func (s *DoisFatoresService) Obrigatorio(perfil string) bool {
+	return s.exige != nil && s.exige(perfil)
+}
+
+func (s *DoisFatoresService) cifrar(segredo string) (string, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(s.aead.Seal(nonce, nonce, []byte(segredo), nil)), nil
+}
+
+func (s *DoisFatoresService) decifrar(cifrado string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(cifrado)
+	if err != nil {
+		return "", err
+	}
+	n := s.aead.NonceSize()
+	if len(raw) < n {
+		return "", errors.New("segredo 2FA corrompido")
+	}
+	plain, err := s.aead.Open(nil, raw[:n], raw[n:], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// gerarCodigosRecuperacao códigos em claro (mostrados uma vez) e os hashes a gravar.
+func gerarCodigosRecuperacao() (codigos, hashes []string, err error) {
+	for i := 0; i < models.DoisFatoresCodigosRecuperacao; i++ {
+		b := make([]byte, recuperacaoCodigoLen)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		var sb strings.Builder
+		for j, v := range b {
+			if j == recuperacaoGrupoLen {
+				sb.WriteByte('-')
+			}
+			sb.WriteByte(conviteAlfabeto[int(v)%len(conviteAlfabeto)])
+		}
+		codigos = append(codigos, sb.String())
+		hashes = append(hashes, hashCodigoConvite(sb.String()))
+	}
+	return codigos, hashes, nil
+}
+
+func (s *DoisFatoresService) conferirSenha(ctx context.Context, usuarioID int64, senha string) (*models.Usuario, error) {
+	u, err := s.usuarios.GetByID(ctx, usuarioID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUsuarioNotFound
+		}
+		return nil, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(u.Senha), []byte(senha)) != nil {
+		return nil, ErrDoisFatoresSenhaIncorreta
+	}
+	return u, nil
+}
+
+// ativo inscrição confirmada; ErrDoisFatoresInativo se não houver.
+func (s *DoisFatoresService) ativo(ctx context.Context, usuarioID int64) (*models.DoisFatores, error) {
+	d, err := s.repo.Get(ctx, usuarioID)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && d.AtivadoEm == nil) {
+		return nil, ErrDoisFatoresInativo
+	}
+	return d, err
+}
+
+// Ativo indica se o login do usuário pede o segundo fator.
+func (s *DoisFatoresService) Ativo(ctx context.Context, usuarioID int64) (bool, error) {
+	_, err := s.ativo(ctx, usuarioID)
+	if errors.Is(err, ErrDoisFatoresInativo) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Status estado do 2FA da conta (Nivel fica a cargo do handler, que conhece a sessão).
+func (s *DoisFatoresService) Status(ctx context.Context, usuarioID int64, perfil string) (*models.DoisFatoresStatus, error) {
+	st := &models.DoisFatoresStatus{Obrigatorio: s.Obrigatorio(perfil)}
+	d, err := s.ativo(ctx, usuarioID)
+	if errors.Is(err, ErrDoisFatoresInativo) {
+		return st, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	st.Ativo, st.AtivadoEm = true, d.AtivadoEm
+	if st.CodigosRecuperacaoRestantes, err = s.repo.CountCodigosRestantes(ctx, usuarioID); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// Iniciar gera um segredo novo (substitui inscrição pendente) após confirmar a senha.
+func (s *DoisFatoresService) Iniciar(ctx context.Context, usuarioID int64, senha string) (*InscricaoDoisFatores, error) {
+	u, err := s.conferirSenha(ctx, usuarioID, senha)
+	if err != nil {
+		return nil, err
+	}
+	segredo, err := gerarSegredoTOTP()
+	if err != nil {
+		return nil, err
+	}
+	cifrado, err := s.cifrar(segredo)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SalvarPendente(ctx, usuarioID, cifrado); err != nil {
+		if errors.Is(err, repository.ErrDoisFatoresJaAtivo) {
+			return nil, ErrDoisFatoresJaAtivo
+		}
+		return nil, err
+	}
+	return &InscricaoDoisFatores{Segredo: segredo, URI: totpURI(models.DoisFatoresEmissor, u.Email, segredo)}, nil
+}
+
+// Ativar confirma a inscrição com o primeiro código do autenticador, devolve os códigos de recuperação
+// (exibidos uma única vez) e encerra as sessões existentes, abertas só com senha.
+func (s *DoisFatoresService) Ativar(ctx context.Context, usuarioID int64, codigo string) ([]string, error) {
+	d, err := s.repo.Get(ctx, usuarioID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrDoisFatoresSemInscricao
+	}
+	if err != nil {
+		return nil, err
+	}
+	if d.AtivadoEm != nil {
+		return nil, ErrDoisFatoresJaAtivo
+	}
+	segredo, err := s.decifrar(d.SegredoCifrado)
+	if err != nil {
+		return nil, err
+	}
+	passo, ok := verificarTOTP(segredo, codigo, s.now(), d.UltimoPasso)
+	if !ok {
+		return nil, ErrDoisFatoresCodigoInvalido
+	}
+	codigos, hashes, err := gerarCodigosRecuperacao()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Ativar(ctx, usuarioID, passo, hashes, s.now()); err != nil {
+		if errors.Is(err, repository.ErrDoisFatoresJaAtivo) {
+			return nil, ErrDoisFatoresJaAtivo
+		}
+		return nil, err
+	}
+	if s.sessoes != nil {
+		if err := s.sessoes.RevokeAllForUser(ctx, usuarioID); err != nil {
+			return nil, err
+		}
+	}
+	s.auditarDoisFatores(ctx, usuarioID, false, true)
+	return codigos, nil
+}
+
+// Verificar segundo passo do login: aceita o código TOTP atual ou um código de recuperação (consumido).
+func (s *DoisFatoresService) Verificar(ctx context.Context, usuarioID int64, codigo string) error {
+	d, err := s.ativo(ctx, usuarioID)
+	if err != nil {
+		return err
+	}
+	normalizado := normalizarCodigoConvite(codigo)
+	if len(normalizado) == recuperacaoCodigoLen {
+		err := s.repo.UsarCodigoRecuperacao(ctx, usuarioID, hashRefreshToken(normalizado), s.now())
+		if errors.Is(err, repository.ErrDoisFatoresIndisponivel) {
+			return ErrDoisFatoresCodigoInvalido
+		}
+		if err != nil {
+			return err
+		}
+		if _, ok := requestctx.AtorFromContext(ctx); !ok {
+			ctx = requestctx.WithAtor(ctx, requestctx.Ator{UsuarioID: usuarioID})
+		}
+		s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeUsuario, usuarioID, 0, 0,
+			map[string]string{}, map[string]string{"dois_fatores": "CODIGO_RECUPERACAO_USADO"})
+		return nil
+	}
+	segredo, err := s.decifrar(d.SegredoCifrado)
+	if err != nil {
+		return err
+	}
+	passo, ok := verificarTOTP(segredo, normalizado, s.now(), d.UltimoPasso)
+	if !ok {
+		return ErrDoisFatoresCodigoInvalido
+	}
+	if err := s.repo.RegistrarPasso(ctx, usuarioID, passo); err != nil {
+		if errors.Is(err, repository.ErrDoisFatoresIndisponivel) {
+			return ErrDoisFatoresCodigoInvalido
+		}
+		return err
+	}
+	return nil
+}
+
+// RegistrarDesafio guarda o jti do desafio do login, que passa a valer uma única vez.
+func (s *DoisFatoresService) RegistrarDesafio(ctx context.Context, jti string, usuarioID int64, expiraEm time.Time) error {
+	return s.repo.RegistrarDesafio(ctx, jti, usuarioID, expiraEm, s.now())
+}
+
+// VerificarDesafio segundo passo do login: cada código conta uma tentativa do desafio (até
+// models.DoisFatoresDesafioMaxTentativas) e o desafio é consumido no primeiro código aceito.
+// ErrDoisFatoresDesafioInvalido se o desafio já não serve.
+func (s *DoisFatoresService) VerificarDesafio(ctx context.Context, jti string, usuarioID int64, codigo string) error {
+	err := s.repo.ReservarTentativaDesafio(ctx, jti, usuarioID, models.DoisFatoresDesafioMaxTentativas, s.now())
+	if errors.Is(err, repository.ErrDoisFatoresDesafioIndisponivel) {
+		return ErrDoisFatoresDesafioInvalido
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.Verificar(ctx, usuarioID, codigo); err != nil {
+		return err
+	}
+	if err := s.repo.ConsumirDesafio(ctx, jti, s.now()); err != nil {
+		if errors.Is(err, repository.ErrDoisFatoresDesafioIndisponivel) {
+			return ErrDoisFatoresDesafioInvalido
+		}
+		return err
+	}
+	return nil
+}
+
+// RegenerarCodigos invalida os códigos de recuperação anteriores; exige um código TOTP válido.
+func (s *DoisFatoresService) RegenerarCodigos(ctx context.Context, usuarioID int64, codigo string) ([]string, error) {
+	if len(normalizarCodigoConvite(codigo)) == recuperacaoCodigoLen {
+		return nil, ErrDoisFatoresCodigoInvalido
+	}
+	if err := s.Verificar(ctx, usuarioID, codigo); err != nil {
+		return nil, err
+	}
+	codigos, hashes, err := gerarCodigosRecuperacao()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SubstituirCodigos(ctx, usuarioID, hashes); err != nil {
+		return nil, err
+	}
+	if _, ok := requestctx.AtorFromContext(ctx); !ok {
+		ctx = requestctx.WithAtor(ctx, requestctx.Ator{UsuarioID: usuarioID})
+	}
+	s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeUsuario, usuarioID, 0, 0,
+		map[string]string{}, map[string]string{"dois_fatores": "CODIGOS_RECUPERACAO_REGENERADOS"})
+	return codigos, nil
+}
+
+// Desativar remove o 2FA da própria conta (senha + código); bloqueado para perfis obrigados.
+func (s *DoisFatoresService) Desativar(ctx context.Context, usuarioID int64, senha, codigo string) error {
+	u, err := s.conferirSenha(ctx, usuarioID, senha)
+	if err != nil {
+		return err
+	}
+	if s.Obrigatorio(u.Perfil) {
+		return ErrDoisFatoresObrigatorio
+	}
+	if err := s.Verificar(ctx, usuarioID, codigo); err != nil {
+		return err
+	}
+	if _, err := s.repo.Remover(ctx, usuarioID); err != nil {
+		return err
+	}
+	s.auditarDoisFatores(ctx, usuarioID, true, false)
+	return nil
+}
+
+// Redefinir administrador remove o 2FA de quem perdeu o autenticador e os códigos; encerra as sessões.
+// O usuário volta a entrar só com senha (e, se o perfil exigir, é levado a se inscrever de novo).
+func (s *DoisFatoresService) Redefinir(ctx context.Context, usuarioID int64) error {
+	if _, err := s.usuarios.GetByID(ctx, usuarioID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUsuarioNotFound
+		}
+		return err
+	}
+	removido, err := s.repo.Remover(ctx, usuarioID)
+	if err != nil {
+		return err
+	}
+	if !removido {
+		return ErrDoisFatoresInativo
+	}
+	if s.sessoes != nil {
+		if err := s.sessoes.RevokeAllForUser(ctx, usuarioID); err != nil {
+			return err
+		}
+	}
+	s.auditarDoisFatores(ctx, usuarioID, true, false)
+	return nil
+}
+
+// auditarDoisFatores registra a mudança de estado sem segredo nem códigos (BR-AUDIT-012).
+func (s *DoisFatoresService) auditarDoisFatores(ctx context.Context, usuarioID int64, antes, depois bool) {
+	if _, ok := requestctx.AtorFromContext(ctx); !ok {
+		ctx = requestctx.WithAtor(ctx, requestctx.Ator{UsuarioID: usuarioID})
+	}
+	s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeUsuario, usuarioID, 0, 0,
+		map[string]bool{"dois_fatores": antes}, map[string]bool{"dois_fatores": depois})
+}
@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/repository"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type fakeDoisFatoresStore struct {
+	d        *models.DoisFatores
+	codigos  map[string]bool // hash → usado
+	removido bool
+	desafios map[string]*fakeDesafio
+}
+
+type fakeDesafio struct {
+	usuarioID  int64
+	tentativas int
+	consumido  bool
+	expiraEm   time.Time
+}
+
+func (f *fakeDoisFatoresStore) Get(context.Context, int64) (*models.DoisFatores, error) {
+	if f.d == nil {
+		return nil, pgx.ErrNoRows
+	}
+	d := *f.d
+	return &d, nil
+}
+
+func (f *fakeDoisFatoresStore) SalvarPendente(_ context.Context, id int64, cifrado string) error {
+	if f.d != nil && f.d.AtivadoEm != nil {
+		return repository.ErrDoisFatoresJaAtivo
+	}
+	f.d = &models.DoisFatores{UsuarioID: id, SegredoCifrado: cifrado}
+	return nil
+}
+
+func (f *fakeDoisFatoresStore) Ativar(_ context.Context, _, passo int64, hashes []string, em time.Time) error {
+	f.d.AtivadoEm, f.d.UltimoPasso = &em, passo
+	return f.SubstituirCodigos(context.Background(), 0, hashes)
+}
+
+func (f *fakeDoisFatoresStore) SubstituirCodigos(_ context.Context, _ int64, hashes []string) error {
+	f.codigos = map[string]bool{}
+	for _, h := range hashes {
+		f.codigos[h] = false
+	}
+	return nil
+}
+
+func (f *fakeDoisFatoresStore) RegistrarPasso(_ context.Context, _, passo int64) error {
+	if passo <= f.d.UltimoPasso {
+		return repository.ErrDoisFatoresIndisponivel
+	}
+	f.d.UltimoPasso = passo
+	return nil
+}
+
+func (f *fakeDoisFatoresStore) UsarCodigoRecuperacao(_ context.Context, _ int64, h string, _ time.Time) error {
+	if usado, ok := f.codigos[h]; !ok || usado {
+		return repository.ErrDoisFatoresIndisponivel
+	}
+	f.codigos[h] = true
+	return nil
+}
+
+func (f *fakeDoisFatoresStore) CountCodigosRestantes(context.Context, int64) (int, error) {
+	n := 0
+	for _, usado := range f.codigos {
+		if !usado {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (f *fakeDoisFatoresStore) Remover(context.Context, int64) (bool, error) {
+	existia := f.d != nil
+	f.d, f.codigos, f.removido = nil, nil, true
+	return existia, nil
+}
+
+func (f *fakeDoisFatoresStore) RegistrarDesafio(_ context.Context, jti string, usuarioID int64, expiraEm, _ time.Time) error {
+	if f.desafios == nil {
+		f.desafios = map[string]*fakeDesafio{}
+	}
+	f.desafios[jti] = &fakeDesafio{usuarioID: usuarioID, expiraEm: expiraEm}
+	return nil
+}
+
+func (f *fakeDoisFatoresStore) ReservarTentativaDesafio(_ context.Context, jti string, usuarioID int64, max int, agora time.Time) error {
+	d, ok := f.desafios[jti]
+	if !ok || d.usuarioID != usuarioID || d.consumido || !d.expiraEm.After(agora) || d.tentativas >= max {
+		return repository.ErrDoisFatoresDesafioIndisponivel
+	}
+	d.tentativas++
+	return nil
+}
+
+func (f *fakeDoisFatoresStore) ConsumirDesafio(_ context.Context, jti string, _ time.Time) error {
+	d, ok := f.desafios[jti]
+	if !ok || d.consumido {
+		return repository.ErrDoisFatoresDesafioIndisponivel
+	}
+	d.consumido = true
+	return nil
+}
+
+type fakeSessaoRevoker struct{ revogados []int64 }
+
+func (f *fakeSessaoRevoker) RevokeAllForUser(_ context.Context, id int64) error {
+	f.revogados = append(f.revogados, id)
+	return nil
+}
+
+func novoDoisFatoresTeste(t *testing.T, perfil string, agora *time.Time) (*DoisFatoresService, *fakeDoisFatoresStore, *fakeSessaoRevoker) {
+	t.Helper()
+	hash, _ := bcrypt.GenerateFromPassword([]byte("senha-certa"), bcrypt.MinCost)
+	aead, err := novaCifraDoisFatores("chave-de-teste")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &fakeDoisFatoresStore{}
+	sessoes := &fakeSessaoRevoker{}
+	s := &DoisFatoresService{
+		repo:     store,
+		usuarios: fakeConviteUsuarios{u: &models.Usuario{ID: 5, Email: "ana@exemplo.pt", Senha: string(hash), Perfil: perfil}},
+		aead:     aead,
+		exige:    func(p string) bool { return p == models.PerfilAdmin },
+		sessoes:  sessoes,
+		now:      func() time.Time { return *agora },
+	}
+	return s, store, sessoes
+}
+
+func TestDoisFatores_InscricaoELogin(t *testing.T) {
+	agora := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	s, store, sessoes := novoDoisFatoresTeste(t, models.PerfilGerente, &agora)
+	ctx := context.Background()
+
+	if _, err := s.Iniciar(ctx, 5, "errada"); !errors.Is(err, ErrDoisFatoresSenhaIncorreta) {
+		t.Fatalf("senha errada: err = %v", err)
+	}
+	insc, err := s.Iniciar(ctx, 5, "senha-certa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u, _ := url.Parse(insc.URI); u.Query().Get("secret") != insc.Segredo {
+		t.Errorf("uri %s sem o segredo", insc.URI)
+	}
+	if store.d.SegredoCifrado == insc.Segredo {
+		t.Error("segredo não pode ser gravado em claro")
+	}
+
+	if _, err := s.Ativar(ctx, 5, "000000"); !errors.Is(err, ErrDoisFatoresCodigoInvalido) {
+		t.Errorf("código errado: err = %v", err)
+	}
+	codigo, _ := codigoTOTP(insc.Segredo, totpPasso(agora))
+	recuperacao, err := s.Ativar(ctx, 5, codigo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recuperacao) != models.DoisFatoresCodigosRecuperacao || len(sessoes.revogados) != 1 {
+		t.Errorf("ativação: %d códigos, sessões revogadas %v", len(recuperacao), sessoes.revogados)
+	}
+	if ativo, _ := s.Ativo(ctx, 5); !ativo {
+		t.Error("2FA deveria estar ativo")
+	}
+
+	if err := s.Verificar(ctx, 5, codigo); !errors.Is(err, ErrDoisFatoresCodigoInvalido) {
+		t.Errorf("mesmo código não pode ser reutilizado: err = %v", err)
+	}
+	agora = agora.Add(30 * time.Second)
+	codigo, _ = codigoTOTP(insc.Segredo, totpPasso(agora))
+	if err := s.Verificar(ctx, 5, codigo); err != nil {
+		t.Errorf("código do passo seguinte: %v", err)
+	}
+
+	if err := s.Verificar(ctx, 5, recuperacao[0]); err != nil {
+		t.Errorf("código de recuperação: %v", err)
+	}
+	if err := s.Verificar(ctx, 5, recuperacao[0]); !errors.Is(err, ErrDoisFatoresCodigoInvalido) {
+		t.Errorf("código de recuperação é de uso único: err = %v", err)
+	}
+	st, _ := s.Status(ctx, 5, models.PerfilGerente)
+	if !st.Ativo || st.Obrigatorio || st.CodigosRecuperacaoRestantes != models.DoisFatoresCodigosRecuperacao-1 {
+		t.Errorf("status = %+v", st)
+	}
+
+	if _, err := s.Iniciar(ctx, 5, "senha-certa"); !errors.Is(err, ErrDoisFatoresJaAtivo) {
+		t.Errorf("reinscrição com 2FA ativo: err = %v", err)
+	}
+	if err := s.Desativar(ctx, 5, "senha-certa", recuperacao[1]); err != nil {
+		t.Fatalf("desativar: %v", err)
+	}
+	if ativo, _ := s.Ativo(ctx, 5); ativo {
+		t.Error("2FA deveria estar inativo")
+	}
+}
+
+func TestDoisFatores_Obrigatorio(t *testing.T) {
+	agora := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	s, store, sessoes := novoDoisFatoresTeste(t, models.PerfilAdmin, &agora)
+	ctx := context.Background()
+	insc, _ := s.Iniciar(ctx, 5, "senha-certa")
+	codigo, _ := codigoTOTP(insc.Segredo, totpPasso(agora))
+	recuperacao, _ := s.Ativar(ctx, 5, codigo)
+
+	if err := s.Desativar(ctx, 5, "senha-certa", recuperacao[0]); !errors.Is(err, ErrDoisFatoresObrigatorio) {
+		t.Errorf("perfil obrigado não desativa: err = %v", err)
+	}
+	if _, err := s.RegenerarCodigos(ctx, 5, recuperacao[0]); !errors.Is(err, ErrDoisFatoresCodigoInvalido) {
+		t.Errorf("regenerar exige TOTP: err = %v", err)
+	}
+
+	if err := s.Redefinir(ctx, 5); err != nil {
+		t.Fatal(err)
+	}
+	if !store.removido || len(sessoes.revogados) != 2 {
+		t.Errorf("redefinir: removido %v, revogações %v", store.removido, sessoes.revogados)
+	}
+	if err := s.Redefinir(ctx, 5); !errors.Is(err, ErrDoisFatoresInativo) {
+		t.Errorf("redefinir sem 2FA: err = %v", err)
+	}
+}
+
+func TestCifraDoisFatores(t *testing.T) {
+	s := &DoisFatoresService{}
+	s.aead, _ = novaCifraDoisFatores("k1")
+	c1, _ := s.cifrar("SEGREDO")
+	c2, _ := s.cifrar("SEGREDO")
+	if c1 == c2 {
+		t.Error("nonce deve variar a cada cifra")
+	}
+	if got, err := s.decifrar(c1); err != nil || got != "SEGREDO" {
+		t.Errorf("decifrar = %q, %v", got, err)
+	}
+	outra := &DoisFatoresService{}
+	outra.aead, _ = novaCifraDoisFatores("k2")
+	if _, err := outra.decifrar(c1); err == nil {
+		t.Error("chave diferente não pode decifrar")
+	}
+	if _, err := novaCifraDoisFatores(" "); err == nil {
+		t.Error("chave vazia deve falhar")
+	}
+}
+
+func TestDoisFatores_DesafioUsoUnico(t *testing.T) {
+	agora := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	s, _, _ := novoDoisFatoresTeste(t, models.PerfilGerente, &agora)
+	ctx := context.Background()
+	insc, _ := s.Iniciar(ctx, 5, "senha-certa")
+	codigo, _ := codigoTOTP(insc.Segredo, totpPasso(agora))
+	if _, err := s.Ativar(ctx, 5, codigo); err != nil {
+		t.Fatal(err)
+	}
+	expira := agora.Add(5 * time.Minute)
+
+	agora = agora.Add(30 * time.Second)
+	codigo, _ = codigoTOTP(insc.Segredo, totpPasso(agora))
+	_ = s.RegistrarDesafio(ctx, "d1", 5, expira)
+	if err := s.VerificarDesafio(ctx, "d1", 6, codigo); !errors.Is(err, ErrDoisFatoresDesafioInvalido) {
+		t.Errorf("desafio de outro usuário: err = %v", err)
+	}
+	if err := s.VerificarDesafio(ctx, "d1", 5, codigo); err != nil {
+		t.Fatalf("primeiro uso: %v", err)
+	}
+	agora = agora.Add(30 * time.Second)
+	codigo, _ = codigoTOTP(insc.Segredo, totpPasso(agora))
+	if err := s.VerificarDesafio(ctx, "d1", 5, codigo); !errors.Is(err, ErrDoisFatoresDesafioInvalido) {
+		t.Errorf("desafio reutilizado após sucesso: err = %v", err)
+	}
+
+	_ = s.RegistrarDesafio(ctx, "d2", 5, expira)
+	for i := 0; i < models.DoisFatoresDesafioMaxTentativas; i++ {
+		if err := s.VerificarDesafio(ctx, "d2", 5, "000000"); !errors.Is(err, ErrDoisFatoresCodigoInvalido) {
+			t.Fatalf("tentativa %d: err = %v", i+1, err)
+		}
+	}
+	if err := s.VerificarDesafio(ctx, "d2", 5, codigo); !errors.Is(err, ErrDoisFatoresDesafioInvalido) {
+		t.Errorf("desafio após %d códigos errados: err = %v", models.DoisFatoresDesafioMaxTentativas, err)
+	}
+
+	_ = s.RegistrarDesafio(ctx, "d3", 5, expira)
+	agora = expira
+	if err := s.VerificarDesafio(ctx, "d3", 5, codigo); !errors.Is(err, ErrDoisFatoresDesafioInvalido) {
+		t.Errorf("desafio expirado: err = %v", err)
+	}
+}
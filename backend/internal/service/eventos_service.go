@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// eventosCanalPG canal Postgres LISTEN/NOTIFY partilhado por todas as réplicas da API.
+const eventosCanalPG = "ceialmilk_eventos"
+
+// eventosBufferAssinatura eventos pendentes por ligação; um cliente lento perde avisos em vez de
+// atrasar os demais.
+const eventosBufferAssinatura = 32
+
+// EventosAssinatura ligação de um cliente ao stream de uma fazenda.
+type EventosAssinatura struct {
+	FazendaID int64
+	C         <-chan models.EventoTempoReal
+	c         chan models.EventoTempoReal
+}
+
+// EventosService distribui avisos de mudança (alertas, produção, restrições) às ligações SSE abertas.
+// Publicar faz NOTIFY no Postgres; cada réplica escuta o canal (Run) e entrega às suas assinaturas,
+// pelo que o evento chega a todos os clientes independentemente da réplica que tratou a escrita.
+type EventosService struct {
+	pool *pgxpool.Pool
+	// notificar envia o payload ao canal (pg_notify); substituível nos testes.
+	notificar func(ctx context.Context, payload string) error
+
+	mu   sync.RWMutex
+	subs map[int64]map[*EventosAssinatura]struct{}
+}
+
+func NewEventosService(pool *pgxpool.Pool) *EventosService {
+	s := &EventosService{pool: pool, subs: map[int64]map[*EventosAssinatura]struct{}{}}
+	s.notificar = func(ctx context.Context, payload string) error {
+		_, err := pool.Exec(ctx, `SELECT pg_notify($1, $2)`, eventosCanalPG, payload)
+		return err
+	}
+	return s
+}
+
+// Publicar avisa as ligações da fazenda. Falhas são apenas logadas: o evento é uma dica de
+// atualização e nunca interrompe a escrita que o originou. Seguro com receptor nil.
+func (s *EventosService) Publicar(ctx context.Context, ev models.EventoTempoReal) {
+	if s == nil || ev.FazendaID <= 0 {
+		return
+	}
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		slog.Warn("eventos: serializar", "error", err)
+		return
+	}
+	nctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	if err := s.notificar(nctx, string(payload)); err != nil {
+		slog.Warn("eventos: notify falhou", "error", err, "entidade", ev.Entidade, "fazenda_id", ev.FazendaID)
+	}
+}
+
+// Assinar abre uma ligação para os eventos da fazenda; chamar Cancelar ao terminar. C é fechado
+// quando o serviço encerra.
+func (s *EventosService) Assinar(fazendaID int64) *EventosAssinatura {
+	ch := make(chan models.EventoTempoReal, eventosBufferAssinatura)
+	a := &EventosAssinatura{FazendaID: fazendaID, C: ch, c: ch}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.subs[fazendaID] == nil {
+		s.subs[fazendaID] = map[*EventosAssinatura]struct{}{}
+	}
+	s.subs[fazendaID][a] = struct{}{}
+	return a
+}
+
+func (s *EventosService) Cancelar(a *EventosAssinatura) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if set, ok := s.subs[a.FazendaID]; ok {
+		delete(set, a)
+		if len(set) == 0 {
+			delete(s.subs, a.FazendaID)
+		}
+	}
+}
+
+// distribuir entrega o payload recebido do canal às assinaturas locais da fazenda.
+func (s *EventosService) distribuir(payload string) {
+	var ev models.EventoTempoReal
+	if err := json.Unmarshal([]byte(payload), &ev); err != nil {
+		slog.Warn("eventos: payload inválido", "error", err)
+		return
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for a := range s.subs[ev.FazendaID] {
+		select {
+		case a.c <- ev:
+		default:
+			slog.Debug("eventos: assinatura lenta, evento descartado", "fazenda_id", ev.FazendaID)
+		}
+	}
+}
+
+// Run escuta o canal numa ligação dedicada até ctx terminar, reconectando com espera crescente
+// (máx. 30 s) se a ligação cair. Ao terminar, fecha as assinaturas para que os streams abertos
+// acabem e não atrasem o shutdown do servidor.
+func (s *EventosService) Run(ctx context.Context) {
+	go func() {
+		defer s.fecharAssinaturas()
+		defer func() {
+			if r := recover(); r != nil {
+				slog.Error("eventos: panic recuperado", "panic", r)
+			}
+		}()
+		espera := time.Second
+		for {
+			inicio := time.Now()
+			err := s.escutar(ctx)
+			if ctx.Err() != nil {
+				slog.Info("eventos: encerrado")
+				return
+			}
+			if time.Since(inicio) > time.Minute {
+				espera = time.Second
+			}
+			slog.Warn("eventos: escuta interrompida, reconectando", "error", err, "em", espera.String())
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(espera):
+			}
+			if espera < 30*time.Second {
+				espera *= 2
+			}
+		}
+	}()
+}
+
+func (s *EventosService) fecharAssinaturas() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, set := range s.subs {
+		for a := range set {
+			close(a.c)
+		}
+	}
+	s.subs = map[int64]map[*EventosAssinatura]struct{}{}
+}
+
+func (s *EventosService) escutar(ctx context.Context) error {
+	pc, err := s.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// A ligação fica em LISTEN: nunca volta ao pool.
+	conn := pc.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+eventosCanalPG); err != nil {
+		return err
+	}
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		s.distribuir(n.Payload)
+	}
+}
+
+// publicaEventos embutido nos services que avisam o stream em tempo real (padrão de auditavel).
+type publicaEventos struct {
+	eventos *EventosService
+}
+
+// SetEventos habilita os avisos em tempo real.
+func (p *publicaEventos) SetEventos(svc *EventosService) {
+	p.eventos = svc
+}
+
+func (p *publicaEventos) publicarEvento(ctx context.Context, entidade, acao string, fazendaID, id, animalID int64) {
+	p.eventos.Publicar(ctx, models.EventoTempoReal{
+		Entidade:  entidade,
+		Acao:      acao,
+		FazendaID: fazendaID,
+		ID:        id,
+		AnimalID:  animalID,
+	})
+}
+
+func (p *publicaEventos) publicarEventoAlerta(ctx context.Context, acao string, a *models.Alerta) {
+	var animalID int64
+	if a.AnimalID != nil {
+		animalID = *a.AnimalID
+	}
+	p.publicarEvento(ctx, models.EventoEntidadeAlerta, acao, a.FazendaID, a.ID, animalID)
+}
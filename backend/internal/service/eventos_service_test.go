@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ceialmilk/api/internal/models"
+)
+
+// newEventosServiceLoopback simula o Postgres: o NOTIFY volta imediatamente pelo LISTEN desta réplica.
+func newEventosServiceLoopback() *EventosService {
+	s := &EventosService{subs: map[int64]map[*EventosAssinatura]struct{}{}}
+	s.notificar = func(_ context.Context, payload string) error {
+		s.distribuir(payload)
+		return nil
+	}
+	return s
+}
+
+func TestEventosService_EntregaPorFazenda(t *testing.T) {
+	s := newEventosServiceLoopback()
+	a1 := s.Assinar(1)
+	a2 := s.Assinar(2)
+	defer s.Cancelar(a2)
+
+	s.Publicar(context.Background(), models.EventoTempoReal{Entidade: models.EventoEntidadeAlerta, Acao: models.AuditoriaAcaoCreate, FazendaID: 1, ID: 7})
+
+	select {
+	case ev := <-a1.C:
+		if ev.ID != 7 || ev.Entidade != models.EventoEntidadeAlerta {
+			t.Fatalf("evento: %+v", ev)
+		}
+	default:
+		t.Fatal("assinatura da fazenda 1 deve receber o evento")
+	}
+	select {
+	case ev := <-a2.C:
+		t.Fatalf("outra fazenda não recebe: %+v", ev)
+	default:
+	}
+
+	s.Cancelar(a1)
+	s.Publicar(context.Background(), models.EventoTempoReal{Entidade: models.EventoEntidadeProducao, FazendaID: 1})
+	select {
+	case ev := <-a1.C:
+		t.Fatalf("assinatura cancelada não recebe: %+v", ev)
+	default:
+	}
+	if _, ok := s.subs[1]; ok {
+		t.Fatal("fazenda sem assinaturas deve sair do mapa")
+	}
+}
+
+func TestEventosService_AssinaturaLentaNaoBloqueia(t *testing.T) {
+	s := newEventosServiceLoopback()
+	lenta := s.Assinar(1)
+	for i := 0; i < eventosBufferAssinatura+5; i++ {
+		s.Publicar(context.Background(), models.EventoTempoReal{Entidade: models.EventoEntidadeAlerta, FazendaID: 1, ID: int64(i + 1)})
+	}
+	if len(lenta.C) != eventosBufferAssinatura {
+		t.Fatalf("buffer: %d", len(lenta.C))
+	}
+
+	s.fecharAssinaturas()
+	n := 0
+	for range lenta.C {
+		n++
+	}
+	if n != eventosBufferAssinatura {
+		t.Fatalf("eventos pendentes entregues antes do fecho: %d", n)
+	}
+}
+
+func TestEventosService_FalhaNotifyNaoPropaga(t *testing.T) {
+	s := &EventosService{subs: map[int64]map[*EventosAssinatura]struct{}{}}
+	chamadas := 0
+	s.notificar = func(context.Context, string) error {
+		chamadas++
+		return errors.New("conexão recusada")
+	}
+	s.Publicar(context.Background(), models.EventoTempoReal{Entidade: models.EventoEntidadeAlerta, FazendaID: 1})
+	s.Publicar(context.Background(), models.EventoTempoReal{Entidade: models.EventoEntidadeAlerta})
+	if chamadas != 1 {
+		t.Fatalf("evento sem fazenda não é publicado: %d", chamadas)
+	}
+
+	var nilSvc *EventosService
+	nilSvc.Publicar(context.Background(), models.EventoTempoReal{FazendaID: 1})
+	var p publicaEventos
+	p.publicarEventoAlerta(context.Background(), models.AuditoriaAcaoUpdate, &models.Alerta{FazendaID: 1})
+}
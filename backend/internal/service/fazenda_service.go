@@ -15,6 +15,7 @@ var ErrFazendaDuplicada = errors.New("já existe uma fazenda com esse nome e loc
 var ErrCriarMinhaFazendaPerfil = errors.New("perfil não autorizado a criar fazenda neste fluxo")
 
 type FazendaService struct {
+	auditavel
 	repo *repository.FazendaRepository
 }
 
@@ -33,7 +34,11 @@ func (s *FazendaService) Create(ctx context.Context, fazenda *models.Fazenda) er
 	if exists {
 		return ErrFazendaDuplicada
 	}
-	return s.repo.Create(ctx, fazenda)
+	if err := s.repo.Create(ctx, fazenda); err != nil {
+		return err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoCreate, models.AuditoriaEntidadeFazenda, fazenda.ID, fazenda.ID, 0, nil, fazenda)
+	return nil
 }
 
 // CreateMinhaFazenda cria nova fazenda e vincula ao utilizador apenas quando o perfil já é
@@ -53,7 +58,11 @@ func (s *FazendaService) CreateMinhaFazenda(ctx context.Context, usuarioID int64
 	if perfil != models.PerfilProprietario {
 		return ErrCriarMinhaFazendaPerfil
 	}
-	return s.repo.CreateFazendaAndLinkUsuario(ctx, fazenda, usuarioID, models.PapelVinculoTitular)
+	if err := s.repo.CreateFazendaAndLinkUsuario(ctx, fazenda, usuarioID, models.PapelVinculoTitular); err != nil {
+		return err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoCreate, models.AuditoriaEntidadeFazenda, fazenda.ID, fazenda.ID, 0, nil, fazenda)
+	return nil
 }
 
 func (s *FazendaService) GetByID(ctx context.Context, id int64) (*models.Fazenda, error) {
@@ -70,7 +79,7 @@ func (s *FazendaService) Update(ctx context.Context, fazenda *models.Fazenda) er
 	}
 
 	// Verificar se existe
-	_, err := s.repo.GetByID(ctx, fazenda.ID)
+	existing, err := s.repo.GetByID(ctx, fazenda.ID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return ErrFazendaNotFound
@@ -87,12 +96,16 @@ func (s *FazendaService) Update(ctx context.Context, fazenda *models.Fazenda) er
 		return ErrFazendaDuplicada
 	}
 
-	return s.repo.Update(ctx, fazenda)
+	if err := s.repo.Update(ctx, fazenda); err != nil {
+		return err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeFazenda, fazenda.ID, fazenda.ID, 0, existing, fazenda)
+	return nil
 }
 
 func (s *FazendaService) Delete(ctx context.Context, id int64) error {
 	// Verificar se existe
-	_, err := s.repo.GetByID(ctx, id)
+	existing, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return ErrFazendaNotFound
@@ -100,7 +113,11 @@ func (s *FazendaService) Delete(ctx context.Context, id int64) error {
 		return err
 	}
 
-	return s.repo.Delete(ctx, id)
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoDelete, models.AuditoriaEntidadeFazenda, id, id, 0, existing, nil)
+	return nil
 }
 
 func (s *FazendaService) SearchByNome(ctx context.Context, nome string) ([]*models.Fazenda, error) {
@@ -0,0 +1,324 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrFolgasAusenciaTipo       = errors.New("tipo de ausência inválido (FERIAS, ATESTADO ou LICENCA_NAO_REMUNERADA)")
+	ErrFolgasAusenciaPeriodo    = errors.New("período inválido: data fim anterior à início ou mais de 366 dias")
+	ErrFolgasAusenciaNotFound   = errors.New("ausência não encontrada")
+	ErrFolgasAusenciaSobreposta = repository.ErrFolgasAusenciaSobreposta
+	ErrFolgasFeriasDireito      = errors.New("dias de férias devem estar entre 0 e 60 e o ano entre 2000 e 2100")
+)
+
+// FolgaAusenciaInput registro de férias, atestado ou licença (datas inclusivas).
+type FolgaAusenciaInput struct {
+	UsuarioID  int64
+	Tipo       string
+	DataInicio time.Time
+	DataFim    time.Time
+	AnexoRef   string
+	Observacao string
+}
+
+// ausentesNoDia usuários com ausência cobrindo a data.
+func ausentesNoDia(ausencias []models.FolgaAusencia, d time.Time) map[int64]bool {
+	out := make(map[int64]bool)
+	for i := range ausencias {
+		if ausencias[i].Cobre(d) {
+			out[ausencias[i].UsuarioID] = true
+		}
+	}
+	return out
+}
+
+// ocultarAusencias colegas sem gestão veem só que o outro está ausente; o próprio vê tudo.
+func ocultarAusencias(ausencias []models.FolgaAusencia, userID int64) []models.FolgaAusencia {
+	out := make([]models.FolgaAusencia, len(ausencias))
+	for i, a := range ausencias {
+		if a.UsuarioID != userID {
+			a.Tipo = models.FolgaAusenciaOculta
+			a.AnexoRef, a.Observacao, a.CreatedBy = nil, nil, nil
+		}
+		out[i] = a
+	}
+	return out
+}
+
+// alertasDesfalque dias em que uma equipe tem ausentes e, somando as folgas registradas, fica com
+// menos gente disponível do que o rodízio prevê.
+func alertasDesfalque(cfg *models.FolgasEscalaConfig, linhas []models.EscalaFolga, ausencias []models.FolgaAusencia, inicio, fim time.Time) []models.FolgaAlertaDia {
+	if cfg == nil || len(ausencias) == 0 {
+		return nil
+	}
+	folgas := make(map[string]map[int64]bool)
+	for _, l := range linhas {
+		k := l.Data.Format("2006-01-02")
+		if folgas[k] == nil {
+			folgas[k] = make(map[int64]bool)
+		}
+		folgas[k][l.UsuarioID] = true
+	}
+	var out []models.FolgaAlertaDia
+	for d := inicio; !d.After(fim); d = d.AddDate(0, 0, 1) {
+		ausentes := ausentesNoDia(ausencias, d)
+		if len(ausentes) == 0 {
+			continue
+		}
+		deFolga := folgas[d.Format("2006-01-02")]
+		for i := range cfg.Equipes {
+			e := &cfg.Equipes[i]
+			nAusentes, nFolga := 0, 0
+			for _, p := range e.Participantes {
+				switch {
+				case ausentes[p.UsuarioID]:
+					nAusentes++
+				case deFolga[p.UsuarioID]:
+					nFolga++
+				}
+			}
+			previstas := len(e.UsuariosDeFolga(d))
+			if nAusentes == 0 || nAusentes+nFolga <= previstas {
+				continue
+			}
+			id, nome := e.ID, e.Nome
+			out = append(out, models.FolgaAlertaDia{
+				Data:            d,
+				Tipo:            models.FolgaAlertaDesfalque,
+				QuantidadeFolga: nFolga,
+				Ausentes:        nAusentes,
+				MotivoAlerta: fmt.Sprintf("Equipe desfalcada: %d ausente(s) por férias, atestado ou licença e %d de folga; o rodízio prevê %d fora",
+					nAusentes, nFolga, previstas),
+				EquipeID:   &id,
+				EquipeNome: &nome,
+			})
+		}
+	}
+	return out
+}
+
+// saldoFerias direito (padrão 30 dias) menos os dias de férias dentro do ano, por usuário.
+func saldoFerias(usuarios map[int64]string, direito map[int64]int, ausencias []models.FolgaAusencia, ano int) []models.FolgaFeriasSaldo {
+	inicio := time.Date(ano, 1, 1, 0, 0, 0, 0, time.UTC)
+	fim := time.Date(ano, 12, 31, 0, 0, 0, 0, time.UTC)
+	usados := make(map[int64]int)
+	for i := range ausencias {
+		a := &ausencias[i]
+		if a.Tipo != models.FolgaAusenciaFerias {
+			continue
+		}
+		usados[a.UsuarioID] += a.DiasEntre(inicio, fim)
+		if _, ok := usuarios[a.UsuarioID]; !ok {
+			usuarios[a.UsuarioID] = a.UsuarioNome
+		}
+	}
+	out := make([]models.FolgaFeriasSaldo, 0, len(usuarios))
+	for uid, nome := range usuarios {
+		dir, ok := direito[uid]
+		if !ok {
+			dir = models.FolgasFeriasDiasAnoPadrao
+		}
+		out = append(out, models.FolgaFeriasSaldo{
+			UsuarioID: uid, UsuarioNome: nome, Ano: ano,
+			DiasDireito: dir, DiasUsados: usados[uid], Saldo: dir - usados[uid],
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].UsuarioNome != out[j].UsuarioNome {
+			return out[i].UsuarioNome < out[j].UsuarioNome
+		}
+		return out[i].UsuarioID < out[j].UsuarioID
+	})
+	return out
+}
+
+// RegistrarAusencia grava férias/atestado/licença (gestão) e retira as folgas AUTO do período.
+func (s *FolgasService) RegistrarAusencia(ctx context.Context, fazendaID int64, in FolgaAusenciaInput, actorID int64, perfil string) (*models.FolgaAusencia, error) {
+	if !models.PodeGerenciarFolgas(perfil) {
+		return nil, ErrFolgasSemPermissao
+	}
+	if err := s.validarAcessoFazenda(ctx, fazendaID, perfil, actorID); err != nil {
+		return nil, err
+	}
+	if !models.IsValidFolgaAusenciaTipo(in.Tipo) {
+		return nil, ErrFolgasAusenciaTipo
+	}
+	inicio, fim := truncateDateUTC(in.DataInicio), truncateDateUTC(in.DataFim)
+	if fim.Before(inicio) || fim.Sub(inicio) > 365*24*time.Hour {
+		return nil, ErrFolgasAusenciaPeriodo
+	}
+	ok, err := s.repo.UsuarioTemFazendaComPerfilPermitido(ctx, in.UsuarioID, fazendaID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrFolgasPerfilNaoPermitido
+	}
+	a := &models.FolgaAusencia{
+		FazendaID:  fazendaID,
+		UsuarioID:  in.UsuarioID,
+		Tipo:       in.Tipo,
+		DataInicio: inicio,
+		DataFim:    fim,
+		CreatedBy:  &actorID,
+	}
+	if v := strings.TrimSpace(in.AnexoRef); v != "" {
+		a.AnexoRef = &v
+	}
+	if v := strings.TrimSpace(in.Observacao); v != "" {
+		a.Observacao = &v
+	}
+	removidas, err := s.repo.InsertAusencia(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+	_ = s.repo.InsertAlteracao(ctx, &models.FolgaAlteracao{
+		FazendaID: fazendaID,
+		ActorID:   &actorID,
+		Tipo:      "AUSENCIA",
+		Detalhes: map[string]any{
+			"ausencia_id":           a.ID,
+			"usuario_id":            a.UsuarioID,
+			"tipo":                  a.Tipo,
+			"inicio":                inicio.Format("2006-01-02"),
+			"fim":                   fim.Format("2006-01-02"),
+			"folgas_auto_removidas": removidas,
+		},
+	})
+	return s.repo.GetAusencia(ctx, fazendaID, a.ID)
+}
+
+// ExcluirAusencia remove o registro (gestão); a escala volta ao rodízio na próxima geração.
+func (s *FolgasService) ExcluirAusencia(ctx context.Context, fazendaID, id int64, actorID int64, perfil string) error {
+	if !models.PodeGerenciarFolgas(perfil) {
+		return ErrFolgasSemPermissao
+	}
+	if err := s.validarAcessoFazenda(ctx, fazendaID, perfil, actorID); err != nil {
+		return err
+	}
+	a, err := s.repo.GetAusencia(ctx, fazendaID, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrFolgasAusenciaNotFound
+		}
+		return err
+	}
+	if err := s.repo.DeleteAusencia(ctx, fazendaID, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrFolgasAusenciaNotFound
+		}
+		return err
+	}
+	_ = s.repo.InsertAlteracao(ctx, &models.FolgaAlteracao{
+		FazendaID: fazendaID,
+		ActorID:   &actorID,
+		Tipo:      "AUSENCIA_REMOVIDA",
+		Detalhes: map[string]any{
+			"ausencia_id": a.ID,
+			"usuario_id":  a.UsuarioID,
+			"tipo":        a.Tipo,
+			"inicio":      a.DataInicio.Format("2006-01-02"),
+			"fim":         a.DataFim.Format("2006-01-02"),
+		},
+	})
+	return nil
+}
+
+// ListAusencias ausências que tocam o intervalo; sem gestão, as dos colegas vêm sem tipo nem anexo.
+func (s *FolgasService) ListAusencias(ctx context.Context, fazendaID int64, inicio, fim time.Time, perfil string, userID int64) ([]models.FolgaAusencia, error) {
+	if err := s.validarAcessoFazenda(ctx, fazendaID, perfil, userID); err != nil {
+		return nil, err
+	}
+	inicio, fim = truncateDateUTC(inicio), truncateDateUTC(fim)
+	if fim.Before(inicio) {
+		return nil, fmt.Errorf("data fim anterior à início")
+	}
+	list, err := s.repo.ListAusenciasRange(ctx, fazendaID, inicio, fim, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !models.PodeGerenciarFolgas(perfil) {
+		list = ocultarAusencias(list, userID)
+	}
+	return list, nil
+}
+
+// SaldoFerias direito e uso de férias no ano: gestão vê participantes das equipes e quem teve férias;
+// os demais, só o próprio saldo.
+func (s *FolgasService) SaldoFerias(ctx context.Context, fazendaID int64, ano int, perfil string, userID int64) ([]models.FolgaFeriasSaldo, error) {
+	if err := s.validarAcessoFazenda(ctx, fazendaID, perfil, userID); err != nil {
+		return nil, err
+	}
+	if ano < 2000 || ano > 2100 {
+		return nil, ErrFolgasFeriasDireito
+	}
+	gestao := models.PodeGerenciarFolgas(perfil)
+	var filtro *int64
+	if !gestao {
+		filtro = &userID
+	}
+	inicio := time.Date(ano, 1, 1, 0, 0, 0, 0, time.UTC)
+	ausencias, err := s.repo.ListAusenciasRange(ctx, fazendaID, inicio, inicio.AddDate(1, 0, -1), filtro)
+	if err != nil {
+		return nil, err
+	}
+	direito, err := s.repo.ListFeriasDireito(ctx, fazendaID, ano)
+	if err != nil {
+		return nil, err
+	}
+	usuarios := make(map[int64]string)
+	cfg, err := s.repo.GetConfig(ctx, fazendaID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	if cfg != nil {
+		for _, e := range cfg.Equipes {
+			for _, p := range e.Participantes {
+				if gestao || p.UsuarioID == userID {
+					usuarios[p.UsuarioID] = p.UsuarioNome
+				}
+			}
+		}
+	}
+	return saldoFerias(usuarios, direito, ausencias, ano), nil
+}
+
+// DefinirFeriasDireito ajusta os dias de férias do funcionário no ano (gestão).
+func (s *FolgasService) DefinirFeriasDireito(ctx context.Context, fazendaID, usuarioID int64, ano, dias int, actorID int64, perfil string) error {
+	if !models.PodeGerenciarFolgas(perfil) {
+		return ErrFolgasSemPermissao
+	}
+	if err := s.validarAcessoFazenda(ctx, fazendaID, perfil, actorID); err != nil {
+		return err
+	}
+	if ano < 2000 || ano > 2100 || dias < 0 || dias > 60 {
+		return ErrFolgasFeriasDireito
+	}
+	ok, err := s.repo.UsuarioTemFazendaComPerfilPermitido(ctx, usuarioID, fazendaID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrFolgasPerfilNaoPermitido
+	}
+	if err := s.repo.UpsertFeriasDireito(ctx, fazendaID, usuarioID, ano, dias); err != nil {
+		return err
+	}
+	_ = s.repo.InsertAlteracao(ctx, &models.FolgaAlteracao{
+		FazendaID: fazendaID,
+		ActorID:   &actorID,
+		Tipo:      "FERIAS_DIREITO",
+		Detalhes:  map[string]any{"usuario_id": usuarioID, "ano": ano, "dias": dias},
+	})
+	return nil
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+)
+
+func ausenciaTeste(uid int64, tipo string, inicio time.Time, dias int) models.FolgaAusencia {
+	return models.FolgaAusencia{UsuarioID: uid, Tipo: tipo, DataInicio: inicio, DataFim: inicio.AddDate(0, 0, dias-1)}
+}
+
+func TestPlanejarFolgasAuto_PulaAusentes(t *testing.T) {
+	anchor := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	e := equipeTeste(t, FolgasEquipeInput{DataAnchor: anchor, CicloDias: 3, FolgasPorCiclo: 1, Participantes: participantes(1, 2, 3)})
+	cfg := &models.FolgasEscalaConfig{Equipes: []models.FolgasEquipe{*e}}
+	// Usuário 2 de férias nos dias 0–2: a folga do dia 1 não é gerada; os demais seguem.
+	ausencias := []models.FolgaAusencia{ausenciaTeste(2, models.FolgaAusenciaFerias, anchor, 3)}
+	plano := planejarFolgasAuto(cfg, anchor, anchor.AddDate(0, 0, 2), nil, ausencias)
+	if len(plano) != 2 || plano[0].UsuarioID != 1 || plano[1].UsuarioID != 3 {
+		t.Fatalf("plano = %+v", plano)
+	}
+}
+
+func TestAlertasDesfalque(t *testing.T) {
+	anchor := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	e := equipeTeste(t, FolgasEquipeInput{DataAnchor: anchor, CicloDias: 3, FolgasPorCiclo: 1, Participantes: participantes(1, 2, 3)})
+	cfg := &models.FolgasEscalaConfig{Equipes: []models.FolgasEquipe{*e}}
+	d0, d1 := anchor, anchor.AddDate(0, 0, 1)
+	// Usuário 3 de atestado em d0 e d1; d0 tem a folga prevista do 1 (2 fora, previsto 1) -> alerta.
+	// d1: a folga prevista era do 2, mas não foi registrada -> só o ausente, sem alerta.
+	linhas := []models.EscalaFolga{{Data: d0, UsuarioID: 1}}
+	ausencias := []models.FolgaAusencia{ausenciaTeste(3, models.FolgaAusenciaAtestado, d0, 2)}
+	alertas := alertasDesfalque(cfg, linhas, ausencias, d0, d1)
+	if len(alertas) != 1 {
+		t.Fatalf("esperado 1 alerta, got %+v", alertas)
+	}
+	a := alertas[0]
+	if !a.Data.Equal(d0) || a.Tipo != models.FolgaAlertaDesfalque || a.Ausentes != 1 || a.QuantidadeFolga != 1 {
+		t.Fatalf("alerta = %+v", a)
+	}
+}
+
+func TestFolgasTeoricas_DescontaAusencias(t *testing.T) {
+	anchor := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	e := equipeTeste(t, FolgasEquipeInput{DataAnchor: anchor, CicloDias: 2, FolgasPorCiclo: 1, Participantes: participantes(1, 2)})
+	ausencias := []models.FolgaAusencia{ausenciaTeste(1, models.FolgaAusenciaLicencaNaoRemunerada, anchor, 4)}
+	teo := folgasTeoricas(e, anchor, anchor.AddDate(0, 0, 5), ausencias)
+	if teo[1] != 1 || teo[2] != 3 {
+		t.Fatalf("teóricas = %v", teo)
+	}
+}
+
+func TestSaldoFerias(t *testing.T) {
+	dez := time.Date(2025, 12, 29, 0, 0, 0, 0, time.UTC)
+	ausencias := []models.FolgaAusencia{
+		// Atravessa o ano: só 10 dias contam em 2026.
+		ausenciaTeste(1, models.FolgaAusenciaFerias, dez, 13),
+		ausenciaTeste(1, models.FolgaAusenciaAtestado, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), 5),
+		{UsuarioID: 2, UsuarioNome: "Bia", Tipo: models.FolgaAusenciaFerias,
+			DataInicio: time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), DataFim: time.Date(2026, 7, 20, 0, 0, 0, 0, time.UTC)},
+	}
+	got := saldoFerias(map[int64]string{1: "Ana"}, map[int64]int{2: 15}, ausencias, 2026)
+	if len(got) != 2 {
+		t.Fatalf("saldos = %+v", got)
+	}
+	if a := got[0]; a.UsuarioID != 1 || a.DiasDireito != 30 || a.DiasUsados != 10 || a.Saldo != 20 {
+		t.Fatalf("Ana = %+v", a)
+	}
+	if b := got[1]; b.UsuarioID != 2 || b.DiasDireito != 15 || b.DiasUsados != 20 || b.Saldo != -5 {
+		t.Fatalf("Bia = %+v", b)
+	}
+}
+
+func TestOcultarAusencias(t *testing.T) {
+	ref := "atestado-123.pdf"
+	list := []models.FolgaAusencia{
+		{UsuarioID: 1, Tipo: models.FolgaAusenciaAtestado, AnexoRef: &ref},
+		{UsuarioID: 2, Tipo: models.FolgaAusenciaAtestado, AnexoRef: &ref},
+	}
+	got := ocultarAusencias(list, 1)
+	if got[0].Tipo != models.FolgaAusenciaAtestado || got[0].AnexoRef == nil {
+		t.Fatalf("própria ausência não deve ser ocultada: %+v", got[0])
+	}
+	if got[1].Tipo != models.FolgaAusenciaOculta || got[1].AnexoRef != nil {
+		t.Fatalf("ausência do colega deve ser ocultada: %+v", got[1])
+	}
+	if list[1].Tipo != models.FolgaAusenciaAtestado {
+		t.Fatal("a lista original não deve mudar")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -14,22 +15,40 @@ import (
 )
 
 var (
-	ErrFolgasConfigNotFound     = repository.ErrFolgasConfigNotFound
-	ErrFolgasSemPermissao       = errors.New("sem permissão para esta operação")
-	ErrFolgasSlotsInvalidos     = errors.New("os três usuários do rodízio devem ser distintos e vinculados à fazenda")
-	ErrFolgasPerfilNaoPermitido = errors.New("apenas usuários com perfil FUNCIONARIO, GERENTE, PROPRIETARIO (ou GESTAO) podem ser usados na escala de folgas")
-	ErrFolgasConflitoFolgaDupla = errors.New("mais de um funcionário de folga neste dia: registre exceção do dia (motivo) ou justifique")
-	ErrFolgasNaoEFolga          = errors.New("você não está de folga nesta data")
-	ErrFolgasUsuarioJaFolgaDia  = errors.New("já existe folga registrada para este usuário nesta data")
+	ErrFolgasConfigNotFound      = repository.ErrFolgasConfigNotFound
+	ErrFolgasSemPermissao        = errors.New("sem permissão para esta operação")
+	ErrFolgasPerfilNaoPermitido  = errors.New("apenas usuários com perfil FUNCIONARIO, GERENTE, PROPRIETARIO (ou GESTAO) podem ser usados na escala de folgas")
+	ErrFolgasConflitoFolgaDupla  = errors.New("mais funcionários da equipe de folga neste dia do que o rodízio prevê: registre exceção do dia (motivo) ou justifique")
+	ErrFolgasSemEquipes          = errors.New("informe de 1 a 20 equipes de rodízio")
+	ErrFolgasEquipeNome          = errors.New("nome da equipe é obrigatório (até 100 caracteres) e deve ser único na fazenda")
+	ErrFolgasEquipeCiclo         = errors.New("ciclo_dias deve estar entre 2 e 60 e folgas_por_ciclo entre 1 e ciclo_dias-1")
+	ErrFolgasEquipeParticipantes = errors.New("cada equipe precisa de 1 a 50 participantes distintos e um usuário só pode estar em uma equipe")
+	ErrFolgasEquipeDeslocamento  = errors.New("deslocamento deve estar entre 0 e ciclo_dias-1")
+	ErrFolgasEquipeNotFound      = errors.New("equipe de rodízio não encontrada nesta fazenda")
+	ErrFolgasNaoEFolga           = errors.New("você não está de folga nesta data")
+	ErrFolgasUsuarioJaFolgaDia   = errors.New("já existe folga registrada para este usuário nesta data")
 )
 
 type FolgasService struct {
-	repo       *repository.FolgasRepository
-	fazendaSvc *FazendaService
+	repo        *repository.FolgasRepository
+	fazendaSvc  *FazendaService
+	notificador folgasNotificador
+	now         func() time.Time
+}
+
+// folgasNotificador entrega avisos de troca de folga (implementado por NotificacaoService).
+type folgasNotificador interface {
+	NotificarUsuarios(ctx context.Context, fazendaID int64, usuarioIDs []int64, msg NotificacaoMensagem)
 }
 
 func NewFolgasService(repo *repository.FolgasRepository, fazendaSvc *FazendaService) *FolgasService {
-	return &FolgasService{repo: repo, fazendaSvc: fazendaSvc}
+	return &FolgasService{repo: repo, fazendaSvc: fazendaSvc, now: time.Now}
+}
+
+// SetNotificador liga os avisos de troca de folga aos canais de notificação; sem ele as trocas
+// funcionam sem aviso.
+func (s *FolgasService) SetNotificador(n folgasNotificador) {
+	s.notificador = n
 }
 
 // FazendaService retorna o serviço de fazendas (validação de acesso nos handlers).
@@ -72,22 +91,215 @@ func isEscalaFolgasUniqueViolation(err error) bool {
 		strings.Contains(constraint, "usuario_id")
 }
 
-// UsuarioParaDia calcula quem folga no dia (rodízio 5x1 com 3 pessoas).
-func UsuarioParaDia(cfg *models.FolgasEscalaConfig, d time.Time) (usuarioID int64, temFolga bool) {
-	a := truncateDateUTC(cfg.DataAnchor)
-	b := truncateDateUTC(d)
-	days := int(b.Sub(a).Hours() / 24)
-	idx := ((days % 6) + 6) % 6
-	switch idx {
-	case 0:
-		return cfg.UsuarioSlot0, true
-	case 1:
-		return cfg.UsuarioSlot1, true
-	case 2:
-		return cfg.UsuarioSlot2, true
-	default:
-		return 0, false
+const (
+	folgasMaxEquipes       = 20
+	folgasMaxParticipantes = 50
+)
+
+// FolgasEquipeInput equipe enviada no PUT da configuração; ID zero cria uma nova.
+type FolgasEquipeInput struct {
+	ID             int64
+	Nome           string
+	DataAnchor     time.Time
+	CicloDias      int
+	FolgasPorCiclo int
+	Participantes  []FolgasParticipanteInput
+}
+
+// FolgasParticipanteInput participante na ordem do rodízio; sem Deslocamento, usa
+// posição × folgas_por_ciclo (folgas em sequência, como no 5x1).
+type FolgasParticipanteInput struct {
+	UsuarioID    int64
+	Deslocamento *int
+}
+
+// montarEquipes valida o padrão de cada equipe e monta os modelos (posições e deslocamentos).
+func montarEquipes(in []FolgasEquipeInput) ([]models.FolgasEquipe, error) {
+	if len(in) == 0 || len(in) > folgasMaxEquipes {
+		return nil, ErrFolgasSemEquipes
+	}
+	nomes := make(map[string]bool)
+	usuarios := make(map[int64]bool)
+	out := make([]models.FolgasEquipe, 0, len(in))
+	for _, e := range in {
+		nome := strings.TrimSpace(e.Nome)
+		chave := strings.ToLower(nome)
+		if nome == "" || len([]rune(nome)) > 100 || nomes[chave] {
+			return nil, ErrFolgasEquipeNome
+		}
+		nomes[chave] = true
+		if e.CicloDias < 2 || e.CicloDias > 60 || e.FolgasPorCiclo < 1 || e.FolgasPorCiclo >= e.CicloDias {
+			return nil, ErrFolgasEquipeCiclo
+		}
+		if len(e.Participantes) == 0 || len(e.Participantes) > folgasMaxParticipantes {
+			return nil, ErrFolgasEquipeParticipantes
+		}
+		eq := models.FolgasEquipe{
+			ID:             e.ID,
+			Nome:           nome,
+			DataAnchor:     truncateDateUTC(e.DataAnchor),
+			CicloDias:      e.CicloDias,
+			FolgasPorCiclo: e.FolgasPorCiclo,
+		}
+		for i, p := range e.Participantes {
+			if p.UsuarioID <= 0 || usuarios[p.UsuarioID] {
+				return nil, ErrFolgasEquipeParticipantes
+			}
+			usuarios[p.UsuarioID] = true
+			desl := (i * e.FolgasPorCiclo) % e.CicloDias
+			if p.Deslocamento != nil {
+				desl = *p.Deslocamento
+				if desl < 0 || desl >= e.CicloDias {
+					return nil, ErrFolgasEquipeDeslocamento
+				}
+			}
+			eq.Participantes = append(eq.Participantes, models.FolgasEquipeParticipante{
+				UsuarioID:    p.UsuarioID,
+				Posicao:      i,
+				Deslocamento: desl,
+			})
+		}
+		out = append(out, eq)
+	}
+	return out, nil
+}
+
+// previstosNoDia folgas que o rodízio prevê na data, por equipe e na ordem do rodízio.
+func previstosNoDia(cfg *models.FolgasEscalaConfig, d time.Time) []models.FolgasRodizioPrevisto {
+	out := make([]models.FolgasRodizioPrevisto, 0)
+	if cfg == nil {
+		return out
+	}
+	for i := range cfg.Equipes {
+		e := &cfg.Equipes[i]
+		for _, uid := range e.UsuariosDeFolga(d) {
+			out = append(out, models.FolgasRodizioPrevisto{
+				UsuarioID:   uid,
+				UsuarioNome: nomeParticipante(e, uid),
+				EquipeID:    e.ID,
+				EquipeNome:  e.Nome,
+			})
+		}
+	}
+	return out
+}
+
+func nomeParticipante(e *models.FolgasEquipe, usuarioID int64) string {
+	for _, p := range e.Participantes {
+		if p.UsuarioID == usuarioID {
+			return p.UsuarioNome
+		}
+	}
+	return ""
+}
+
+// limiteFolgasDia quantas folgas a equipe comporta na data sem exceção: as previstas pelo padrão,
+// no mínimo uma. Quem não participa de equipe (e == nil) segue a regra antiga de uma por dia.
+func limiteFolgasDia(e *models.FolgasEquipe, d time.Time) int {
+	if e == nil {
+		return 1
+	}
+	return max(len(e.UsuariosDeFolga(d)), 1)
+}
+
+// planejarFolgasAuto folgas AUTO do intervalo; equipes com algum participante já registrado
+// no dia (registros MANUAL remanescentes) ficam de fora naquele dia, e quem está ausente
+// (férias, atestado, licença) não recebe folga.
+func planejarFolgasAuto(cfg *models.FolgasEscalaConfig, inicio, fim time.Time, existentes []models.EscalaFolga, ausencias []models.FolgaAusencia) []models.EscalaFolga {
+	ocupados := make(map[string]map[int64]bool)
+	for _, r := range existentes {
+		k := r.Data.Format("2006-01-02")
+		if ocupados[k] == nil {
+			ocupados[k] = make(map[int64]bool)
+		}
+		ocupados[k][r.UsuarioID] = true
+	}
+	var out []models.EscalaFolga
+	for d := inicio; !d.After(fim); d = d.AddDate(0, 0, 1) {
+		dia := ocupados[d.Format("2006-01-02")]
+		ausentes := ausentesNoDia(ausencias, d)
+		for i := range cfg.Equipes {
+			e := &cfg.Equipes[i]
+			manual := false
+			for _, p := range e.Participantes {
+				if dia[p.UsuarioID] {
+					manual = true
+					break
+				}
+			}
+			if manual {
+				continue
+			}
+			for _, uid := range e.UsuariosDeFolga(d) {
+				if ausentes[uid] {
+					continue
+				}
+				out = append(out, models.EscalaFolga{
+					FazendaID: cfg.FazendaID,
+					Data:      d,
+					UsuarioID: uid,
+					Origem:    models.FolgaOrigemAuto,
+				})
+			}
+		}
+	}
+	return out
+}
+
+// alertasFolgas dias em que uma equipe (ou o grupo fora das equipes) tem mais folgas do que o
+// rodízio comporta, sem exceção do dia e sem todas as justificativas. Linhas ordenadas por data.
+func alertasFolgas(cfg *models.FolgasEscalaConfig, linhas []models.EscalaFolga) []models.FolgaAlertaDia {
+	var alertas []models.FolgaAlertaDia
+	for i := 0; i < len(linhas); {
+		j := i
+		for j < len(linhas) && linhas[j].Data.Equal(linhas[i].Data) {
+			j++
+		}
+		dia := linhas[i:j]
+		i = j
+		if dia[0].ExcecaoMotivoDia != nil {
+			continue
+		}
+		grupos := make([]*models.FolgasEquipe, 0, len(dia))
+		porGrupo := make(map[*models.FolgasEquipe][]models.EscalaFolga)
+		for _, r := range dia {
+			e := cfg.EquipeDoUsuario(r.UsuarioID)
+			if _, ok := porGrupo[e]; !ok {
+				grupos = append(grupos, e)
+			}
+			porGrupo[e] = append(porGrupo[e], r)
+		}
+		for _, e := range grupos {
+			rows := porGrupo[e]
+			if len(rows) <= limiteFolgasDia(e, dia[0].Data) {
+				continue
+			}
+			allJust := true
+			for _, r := range rows {
+				if !r.Justificada {
+					allJust = false
+					break
+				}
+			}
+			if allJust {
+				continue
+			}
+			a := models.FolgaAlertaDia{
+				Data:            dia[0].Data,
+				Tipo:            models.FolgaAlertaConflito,
+				QuantidadeFolga: len(rows),
+				MotivoAlerta:    "Mais funcionários de folga do que o rodízio prevê, sem exceção do dia registrada ou sem todas as justificativas",
+			}
+			if e != nil {
+				id, nome := e.ID, e.Nome
+				a.EquipeID, a.EquipeNome = &id, &nome
+			} else {
+				a.MotivoAlerta = "Mais de um funcionário fora das equipes de rodízio de folga, sem exceção do dia registrada ou sem todas as justificativas"
+			}
+			alertas = append(alertas, a)
+		}
 	}
+	return alertas
 }
 
 func (s *FolgasService) validarAcessoFazenda(ctx context.Context, fazendaID int64, perfil string, userID int64) error {
@@ -111,51 +323,66 @@ func (s *FolgasService) validarAcessoFazenda(ctx context.Context, fazendaID int6
 	return nil
 }
 
-func (s *FolgasService) validarSlotsFazenda(ctx context.Context, fazendaID, s0, s1, s2 int64) error {
-	if s0 == s1 || s0 == s2 || s1 == s2 {
-		return ErrFolgasSlotsInvalidos
-	}
-	for _, uid := range []int64{s0, s1, s2} {
-		ok, err := s.repo.UsuarioTemFazendaComPerfilPermitido(ctx, uid, fazendaID)
-		if err != nil {
-			return err
-		}
-		if !ok {
-			return ErrFolgasPerfilNaoPermitido
+func (s *FolgasService) validarParticipantesFazenda(ctx context.Context, fazendaID int64, equipes []models.FolgasEquipe) error {
+	for _, e := range equipes {
+		for _, p := range e.Participantes {
+			ok, err := s.repo.UsuarioTemFazendaComPerfilPermitido(ctx, p.UsuarioID, fazendaID)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return ErrFolgasPerfilNaoPermitido
+			}
 		}
 	}
 	return nil
 }
 
-// PutConfig cria/atualiza configuração da escala (gestão/admin/dev).
-func (s *FolgasService) PutConfig(ctx context.Context, fazendaID int64, dataAnchor time.Time, s0, s1, s2 int64, actorID int64, perfil string) error {
+// PutConfig substitui as equipes de rodízio da fazenda (gestão/admin/dev). Equipes omitidas são
+// removidas; a escala já gerada não muda até o próximo Gerar.
+func (s *FolgasService) PutConfig(ctx context.Context, fazendaID int64, in []FolgasEquipeInput, actorID int64, perfil string) error {
 	if !models.PodeGerenciarFolgas(perfil) {
 		return ErrFolgasSemPermissao
 	}
 	if err := s.validarAcessoFazenda(ctx, fazendaID, perfil, actorID); err != nil {
 		return err
 	}
-	if err := s.validarSlotsFazenda(ctx, fazendaID, s0, s1, s2); err != nil {
+	equipes, err := montarEquipes(in)
+	if err != nil {
 		return err
 	}
-	cfg := &models.FolgasEscalaConfig{
-		FazendaID:    fazendaID,
-		DataAnchor:   truncateDateUTC(dataAnchor),
-		UsuarioSlot0: s0,
-		UsuarioSlot1: s1,
-		UsuarioSlot2: s2,
+	if err := s.validarParticipantesFazenda(ctx, fazendaID, equipes); err != nil {
+		return err
 	}
-	if err := s.repo.UpsertConfig(ctx, cfg); err != nil {
+	if err := s.repo.ReplaceEquipes(ctx, fazendaID, equipes); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrFolgasEquipeNotFound
+		}
 		return err
 	}
+	resumo := make([]map[string]any, 0, len(equipes))
+	for _, e := range equipes {
+		ids := make([]int64, 0, len(e.Participantes))
+		desl := make([]int, 0, len(e.Participantes))
+		for _, p := range e.Participantes {
+			ids = append(ids, p.UsuarioID)
+			desl = append(desl, p.Deslocamento)
+		}
+		resumo = append(resumo, map[string]any{
+			"equipe_id":        e.ID,
+			"nome":             e.Nome,
+			"data_anchor":      e.DataAnchor.Format("2006-01-02"),
+			"ciclo_dias":       e.CicloDias,
+			"folgas_por_ciclo": e.FolgasPorCiclo,
+			"participantes":    ids,
+			"deslocamentos":    desl,
+		})
+	}
 	_ = s.repo.InsertAlteracao(ctx, &models.FolgaAlteracao{
 		FazendaID: fazendaID,
 		ActorID:   &actorID,
 		Tipo:      "CONFIG",
-		Detalhes: map[string]any{
-			"data_anchor": cfg.DataAnchor.Format("2006-01-02"),
-			"slot0":       s0, "slot1": s1, "slot2": s2,
-		},
+		Detalhes:  map[string]any{"equipes": resumo},
 	})
 	return nil
 }
@@ -174,7 +401,7 @@ func (s *FolgasService) GetConfig(ctx context.Context, fazendaID int64, perfil s
 	return c, nil
 }
 
-// Gerar preenche dias com AUTO no intervalo (preserva dias com MANUAL).
+// Gerar preenche o intervalo com AUTO conforme as equipes (preserva, por equipe, dias com MANUAL).
 func (s *FolgasService) Gerar(ctx context.Context, fazendaID int64, inicio, fim time.Time, actorID int64, perfil string) error {
 	if !models.PodeGerenciarFolgas(perfil) {
 		return ErrFolgasSemPermissao
@@ -194,25 +421,16 @@ func (s *FolgasService) Gerar(ctx context.Context, fazendaID int64, inicio, fim
 	if err := s.repo.DeleteAutoInRange(ctx, fazendaID, inicio, fim); err != nil {
 		return err
 	}
-	for d := inicio; !d.After(fim); d = d.AddDate(0, 0, 1) {
-		manual, err := s.repo.HasManualOnDate(ctx, fazendaID, d)
-		if err != nil {
-			return err
-		}
-		if manual {
-			continue
-		}
-		uid, ok := UsuarioParaDia(cfg, d)
-		if !ok {
-			continue
-		}
-		e := &models.EscalaFolga{
-			FazendaID: fazendaID,
-			Data:      d,
-			UsuarioID: uid,
-			Origem:    models.FolgaOrigemAuto,
-		}
-		if err := s.repo.InsertEscala(ctx, e); err != nil {
+	manuais, err := s.repo.ListEscalaRange(ctx, fazendaID, inicio, fim)
+	if err != nil {
+		return err
+	}
+	ausencias, err := s.repo.ListAusenciasRange(ctx, fazendaID, inicio, fim, nil)
+	if err != nil {
+		return err
+	}
+	for _, e := range planejarFolgasAuto(cfg, inicio, fim, manuais, ausencias) {
+		if err := s.repo.InsertEscala(ctx, &e); err != nil {
 			return err
 		}
 	}
@@ -235,7 +453,8 @@ const (
 	AlterarDiaAdicionar  AlterarDiaModo = "adicionar"
 )
 
-// AlterarDia ajusta folga(s) em uma data (gestão).
+// AlterarDia ajusta folga(s) em uma data (gestão). Substituir troca apenas as folgas da equipe do
+// usuário naquele dia; adicionar exige exceção do dia quando a equipe passa do previsto.
 func (s *FolgasService) AlterarDia(ctx context.Context, fazendaID int64, d time.Time, usuarioID int64, motivo string, modo AlterarDiaModo, excecaoDiaMotivo string, actorID int64, perfil string) error {
 	if !models.PodeGerenciarFolgas(perfil) {
 		return ErrFolgasSemPermissao
@@ -246,7 +465,8 @@ func (s *FolgasService) AlterarDia(ctx context.Context, fazendaID int64, d time.
 	if motivo == "" {
 		return fmt.Errorf("motivo é obrigatório")
 	}
-	if _, err := s.repo.GetConfigOrErr(ctx, fazendaID); err != nil {
+	cfg, err := s.repo.GetConfigOrErr(ctx, fazendaID)
+	if err != nil {
 		return err
 	}
 	okSlot, err := s.repo.UsuarioTemFazendaComPerfilPermitido(ctx, usuarioID, fazendaID)
@@ -257,10 +477,21 @@ func (s *FolgasService) AlterarDia(ctx context.Context, fazendaID int64, d time.
 		return ErrFolgasPerfilNaoPermitido
 	}
 	d = truncateDateUTC(d)
+	equipe := cfg.EquipeDoUsuario(usuarioID)
 
 	switch modo {
 	case AlterarDiaSubstituir, "":
-		if err := s.repo.DeleteAllForDate(ctx, fazendaID, d); err != nil {
+		rows, err := s.repo.ListFolgasUsuarioOnDate(ctx, fazendaID, d)
+		if err != nil {
+			return err
+		}
+		var remover []int64
+		for _, r := range rows {
+			if cfg.EquipeDoUsuario(r.UsuarioID) == equipe {
+				remover = append(remover, r.UsuarioID)
+			}
+		}
+		if err := s.repo.DeleteForDateUsuarios(ctx, fazendaID, d, remover); err != nil {
 			return err
 		}
 		e := &models.EscalaFolga{
@@ -292,11 +523,17 @@ func (s *FolgasService) AlterarDia(ctx context.Context, fazendaID int64, d time.
 			}
 			return err
 		}
-		n, err := s.repo.CountFolgasOnDate(ctx, fazendaID, d)
+		rows, err := s.repo.ListFolgasUsuarioOnDate(ctx, fazendaID, d)
 		if err != nil {
 			return err
 		}
-		if n > 1 {
+		n := 0
+		for _, r := range rows {
+			if cfg.EquipeDoUsuario(r.UsuarioID) == equipe {
+				n++
+			}
+		}
+		if n > limiteFolgasDia(equipe, d) {
 			_, exErr := s.repo.GetExcecaoDia(ctx, fazendaID, d)
 			if exErr != nil && errors.Is(exErr, pgx.ErrNoRows) {
 				if excecaoDiaMotivo == "" {
@@ -385,7 +622,14 @@ func (s *FolgasService) ListEscala(ctx context.Context, fazendaID int64, inicio,
 	if err != nil {
 		return nil, err
 	}
-	out := &models.FolgasEscalaListResponse{Linhas: list, RodizioPorDia: nil}
+	ausencias, err := s.repo.ListAusenciasRange(ctx, fazendaID, inicio, fim, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !models.PodeGerenciarFolgas(perfil) {
+		ausencias = ocultarAusencias(ausencias, userID)
+	}
+	out := &models.FolgasEscalaListResponse{Linhas: list, RodizioPorDia: nil, Ausencias: ausencias}
 	cfg, err := s.repo.GetConfig(ctx, fazendaID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -393,39 +637,41 @@ func (s *FolgasService) ListEscala(ctx context.Context, fazendaID int64, inicio,
 		}
 		return nil, err
 	}
-	nomes, err := s.repo.LookupNomesUsuarios(ctx, []int64{cfg.UsuarioSlot0, cfg.UsuarioSlot1, cfg.UsuarioSlot2})
-	if err != nil {
-		return nil, err
-	}
 	for d := inicio; !d.After(fim); d = d.AddDate(0, 0, 1) {
-		uid, tem := UsuarioParaDia(cfg, d)
-		rd := models.FolgasRodizioDia{Data: d, TemFolga: tem}
-		if tem {
-			u := uid
+		prev := previstosNoDia(cfg, d)
+		rd := models.FolgasRodizioDia{Data: d, TemFolga: len(prev) > 0, Previstos: prev}
+		if len(prev) == 1 {
+			u, n := prev[0].UsuarioID, prev[0].UsuarioNome
 			rd.UsuarioID = &u
-			if nm, ok := nomes[uid]; ok {
-				n := nm
-				rd.UsuarioNome = &n
-			}
+			rd.UsuarioNome = &n
 		}
 		out.RodizioPorDia = append(out.RodizioPorDia, rd)
 	}
+	// Esperado de cada linha vem da equipe do próprio usuário: ele mesmo quando o padrão prevê a
+	// folga dele, senão o primeiro previsto da equipe (a folga que a linha desloca).
 	for i := range out.Linhas {
-		uid, tem := UsuarioParaDia(cfg, out.Linhas[i].Data)
-		out.Linhas[i].RodizioEsperadoTemFolga = tem
-		if tem {
-			u := uid
-			out.Linhas[i].RodizioEsperadoUsuarioID = &u
-			if nm, ok := nomes[uid]; ok {
-				n := nm
-				out.Linhas[i].RodizioEsperadoUsuarioNome = &n
-			}
+		l := &out.Linhas[i]
+		e := cfg.EquipeDoUsuario(l.UsuarioID)
+		if e == nil {
+			continue
 		}
+		ids := e.UsuariosDeFolga(l.Data)
+		if len(ids) == 0 {
+			continue
+		}
+		l.RodizioEsperadoTemFolga = true
+		esperado := ids[0]
+		if slices.Contains(ids, l.UsuarioID) {
+			esperado = l.UsuarioID
+		}
+		nome := nomeParticipante(e, esperado)
+		l.RodizioEsperadoUsuarioID = &esperado
+		l.RodizioEsperadoUsuarioNome = &nome
 	}
 	return out, nil
 }
 
-// ResumoEquidade compara folgas registradas vs dias em que o rodízio prevê folga para cada um dos três slots (gestão).
+// ResumoEquidade compara folgas registradas vs dias em que o rodízio prevê folga para cada participante das equipes (gestão).
 func (s *FolgasService) ResumoEquidade(ctx context.Context, fazendaID int64, inicio, fim time.Time, perfil string, userID int64) ([]models.FolgaEquidadeResumo, error) {
 	if !models.PodeGerenciarFolgas(perfil) {
 		return nil, ErrFolgasSemPermissao
@@ -442,36 +688,54 @@ func (s *FolgasService) ResumoEquidade(ctx context.Context, fazendaID int64, ini
 	if fim.Before(inicio) {
 		return nil, fmt.Errorf("data fim anterior à início")
 	}
-	nomes, err := s.repo.LookupNomesUsuarios(ctx, []int64{cfg.UsuarioSlot0, cfg.UsuarioSlot1, cfg.UsuarioSlot2})
+	ausencias, err := s.repo.ListAusenciasRange(ctx, fazendaID, inicio, fim, nil)
 	if err != nil {
 		return nil, err
 	}
-	slots := []int64{cfg.UsuarioSlot0, cfg.UsuarioSlot1, cfg.UsuarioSlot2}
-	out := make([]models.FolgaEquidadeResumo, 0, len(slots))
-	for _, uid := range slots {
-		reg, err := s.repo.CountDistinctFolgasUsuarioRange(ctx, fazendaID, uid, inicio, fim)
-		if err != nil {
-			return nil, err
-		}
-		teo := 0
-		for d := inicio; !d.After(fim); d = d.AddDate(0, 0, 1) {
-			u, tem := UsuarioParaDia(cfg, d)
-			if tem && u == uid {
-				teo++
+	diasAusente := make(map[int64]int)
+	for i := range ausencias {
+		diasAusente[ausencias[i].UsuarioID] += ausencias[i].DiasEntre(inicio, fim)
+	}
+	var out []models.FolgaEquidadeResumo
+	for i := range cfg.Equipes {
+		e := &cfg.Equipes[i]
+		teoricas := folgasTeoricas(e, inicio, fim, ausencias)
+		for _, p := range e.Participantes {
+			reg, err := s.repo.CountDistinctFolgasUsuarioRange(ctx, fazendaID, p.UsuarioID, inicio, fim)
+			if err != nil {
+				return nil, err
 			}
+			r, teo := int(reg), teoricas[p.UsuarioID]
+			out = append(out, models.FolgaEquidadeResumo{
+				UsuarioID:          p.UsuarioID,
+				UsuarioNome:        p.UsuarioNome,
+				EquipeID:           e.ID,
+				EquipeNome:         e.Nome,
+				FolgasRegistradas:  r,
+				FolgasTeoricasAuto: teo,
+				Delta:              r - teo,
+				DiasAusente:        diasAusente[p.UsuarioID],
+			})
 		}
-		r := int(reg)
-		out = append(out, models.FolgaEquidadeResumo{
-			UsuarioID:          uid,
-			UsuarioNome:        nomes[uid],
-			FolgasRegistradas:  r,
-			FolgasTeoricasAuto: teo,
-			Delta:              r - teo,
-		})
 	}
 	return out, nil
 }
 
+// folgasTeoricas dias de folga previstos pelo padrão da equipe no intervalo, por participante.
+// Dias de ausência não contam: a folga prevista que cai neles não é devida (BR-FOLGAS-010).
+func folgasTeoricas(e *models.FolgasEquipe, inicio, fim time.Time, ausencias []models.FolgaAusencia) map[int64]int {
+	out := make(map[int64]int, len(e.Participantes))
+	for d := inicio; !d.After(fim); d = d.AddDate(0, 0, 1) {
+		ausentes := ausentesNoDia(ausencias, d)
+		for _, uid := range e.UsuariosDeFolga(d) {
+			if !ausentes[uid] {
+				out[uid]++
+			}
+		}
+	}
+	return out
+}
+
 func (s *FolgasService) ListAlteracoes(ctx context.Context, fazendaID int64, limit int, perfil string, userID int64) ([]models.FolgaAlteracao, error) {
 	if err := s.validarAcessoFazenda(ctx, fazendaID, perfil, userID); err != nil {
 		return nil, err
@@ -485,37 +749,24 @@ func (s *FolgasService) ListAlertas(ctx context.Context, fazendaID int64, inicio
 	}
 	inicio = truncateDateUTC(inicio)
 	fim = truncateDateUTC(fim)
-	var alertas []models.FolgaAlertaDia
-	for d := inicio; !d.After(fim); d = d.AddDate(0, 0, 1) {
-		n, err := s.repo.CountFolgasOnDate(ctx, fazendaID, d)
-		if err != nil {
-			return nil, err
-		}
-		if n <= 1 {
-			continue
-		}
-		if _, err := s.repo.GetExcecaoDia(ctx, fazendaID, d); err == nil {
-			continue
-		}
-		rows, err := s.repo.ListFolgasUsuarioOnDate(ctx, fazendaID, d)
-		if err != nil {
-			return nil, err
-		}
-		allJust := true
-		for _, r := range rows {
-			if !r.Justificada {
-				allJust = false
-				break
-			}
-		}
-		if allJust {
-			continue
-		}
-		alertas = append(alertas, models.FolgaAlertaDia{
-			Data:            d,
-			QuantidadeFolga: int(n),
-			MotivoAlerta:    "Mais de um funcionário de folga sem exceção do dia registrada ou sem todas as justificativas",
-		})
+	cfg, err := s.repo.GetConfig(ctx, fazendaID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	linhas, err := s.repo.ListEscalaRange(ctx, fazendaID, inicio, fim)
+	if err != nil {
+		return nil, err
+	}
+	trocas, err := s.repo.ListTrocasPendentesRange(ctx, fazendaID, inicio, fim)
+	if err != nil {
+		return nil, err
+	}
+	ausencias, err := s.repo.ListAusenciasRange(ctx, fazendaID, inicio, fim, nil)
+	if err != nil {
+		return nil, err
 	}
+	alertas := append(alertasFolgas(cfg, linhas), alertasTrocasPendentes(trocas)...)
+	alertas = append(alertas, alertasDesfalque(cfg, linhas, ausencias, inicio, fim)...)
+	slices.SortStableFunc(alertas, func(a, b models.FolgaAlertaDia) int { return a.Data.Compare(b.Data) })
 	return alertas, nil
 }
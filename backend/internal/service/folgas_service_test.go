@@ -1,6 +1,7 @@
 package service
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -8,57 +9,84 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
-func TestUsuarioParaDia_Rodizio5x1(t *testing.T) {
-	anchor := time.Date(2026, 3, 25, 0, 0, 0, 0, time.UTC) // quarta
-	cfg := &models.FolgasEscalaConfig{
-		DataAnchor:   anchor,
-		UsuarioSlot0: 10,
-		UsuarioSlot1: 20,
-		UsuarioSlot2: 30,
+func equipeTeste(t *testing.T, in FolgasEquipeInput) *models.FolgasEquipe {
+	t.Helper()
+	if in.Nome == "" {
+		in.Nome = "Ordenha"
+	}
+	eqs, err := montarEquipes([]FolgasEquipeInput{in})
+	if err != nil {
+		t.Fatalf("montarEquipes: %v", err)
+	}
+	return &eqs[0]
+}
+
+func participantes(ids ...int64) []FolgasParticipanteInput {
+	out := make([]FolgasParticipanteInput, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, FolgasParticipanteInput{UsuarioID: id})
+	}
+	return out
+}
+
+func folgaUnica(t *testing.T, e *models.FolgasEquipe, d time.Time) (int64, bool) {
+	t.Helper()
+	ids := e.UsuariosDeFolga(d)
+	if len(ids) > 1 {
+		t.Fatalf("esperada no máximo uma folga em %s, got %v", d.Format("2006-01-02"), ids)
+	}
+	if len(ids) == 0 {
+		return 0, false
 	}
+	return ids[0], true
+}
+
+func TestUsuariosDeFolga_Rodizio5x1(t *testing.T) {
+	anchor := time.Date(2026, 3, 25, 0, 0, 0, 0, time.UTC) // quarta
+	// Equipe migrada do 5x1: ciclo 6, 1 folga, deslocamentos padrão 0, 1, 2.
+	e := equipeTeste(t, FolgasEquipeInput{DataAnchor: anchor, CicloDias: 6, FolgasPorCiclo: 1, Participantes: participantes(10, 20, 30)})
 	// Quarta: slot 0
-	uid, ok := UsuarioParaDia(cfg, anchor)
+	uid, ok := folgaUnica(t, e, anchor)
 	if !ok || uid != 10 {
 		t.Fatalf("esperado slot0 na quarta, got %d ok=%v", uid, ok)
 	}
 	// Quinta: slot 1
-	uid, ok = UsuarioParaDia(cfg, anchor.AddDate(0, 0, 1))
+	uid, ok = folgaUnica(t, e, anchor.AddDate(0, 0, 1))
 	if !ok || uid != 20 {
 		t.Fatalf("esperado slot1 na quinta")
 	}
-	// Sábado: slot 2
-	uid, ok = UsuarioParaDia(cfg, anchor.AddDate(0, 0, 2))
+	// Sexta: slot 2
+	uid, ok = folgaUnica(t, e, anchor.AddDate(0, 0, 2))
 	if !ok || uid != 30 {
-		t.Fatalf("esperado slot2 no sábado")
+		t.Fatalf("esperado slot2 na sexta")
 	}
-	// +3, +4, +5: sem folga (sáb, dom, seg no exemplo)
+	// +3, +4, +5: sem folga
 	for _, add := range []int{3, 4, 5} {
-		uid, ok = UsuarioParaDia(cfg, anchor.AddDate(0, 0, add))
+		uid, ok = folgaUnica(t, e, anchor.AddDate(0, 0, add))
 		if ok {
 			t.Fatalf("dia +%d não deveria ter folga automática, uid=%d", add, uid)
 		}
 	}
-	// Próxima quarta (+7): slot 0 de novo (ciclo 6 dias a partir da âncora: +6 = terça com slot0? âncora+6 = terça)
-	// âncora quarta +6 = terça — slot 0
-	uid, ok = UsuarioParaDia(cfg, anchor.AddDate(0, 0, 6))
+	// âncora quarta +6 = terça — slot 0 de novo
+	uid, ok = folgaUnica(t, e, anchor.AddDate(0, 0, 6))
 	if !ok || uid != 10 {
 		t.Fatalf("esperado slot0 em +6 dias (terça), got %d ok=%v", uid, ok)
 	}
+	// Antes da âncora o ciclo continua: -1 = +5 (sem folga), -6 = slot 0.
+	if _, ok := folgaUnica(t, e, anchor.AddDate(0, 0, -1)); ok {
+		t.Fatalf("-1 dia não deveria ter folga")
+	}
+	if uid, ok := folgaUnica(t, e, anchor.AddDate(0, 0, -6)); !ok || uid != 10 {
+		t.Fatalf("esperado slot0 em -6 dias, got %d ok=%v", uid, ok)
+	}
 }
 
-func TestUsuarioParaDia_TresDiasComFolgaEmSequenciaNoInicioDoCiclo(t *testing.T) {
+func TestUsuariosDeFolga_TresDiasComFolgaEmSequenciaNoInicioDoCiclo(t *testing.T) {
 	anchor := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
-	cfg := &models.FolgasEscalaConfig{
-		DataAnchor:   anchor,
-		UsuarioSlot0: 1,
-		UsuarioSlot1: 2,
-		UsuarioSlot2: 3,
-	}
-	inicio := anchor
-	fim := anchor.AddDate(0, 0, 5)
+	e := equipeTeste(t, FolgasEquipeInput{DataAnchor: anchor, CicloDias: 6, FolgasPorCiclo: 1, Participantes: participantes(1, 2, 3)})
 	comFolga := 0
-	for d := inicio; !d.After(fim); d = d.AddDate(0, 0, 1) {
-		if _, tem := UsuarioParaDia(cfg, d); tem {
+	for d := anchor; !d.After(anchor.AddDate(0, 0, 5)); d = d.AddDate(0, 0, 1) {
+		if len(e.UsuariosDeFolga(d)) > 0 {
 			comFolga++
 		}
 	}
@@ -67,15 +95,196 @@ func TestUsuarioParaDia_TresDiasComFolgaEmSequenciaNoInicioDoCiclo(t *testing.T)
 	}
 }
 
+func TestUsuariosDeFolga_6x1SeteOrdenhadores(t *testing.T) {
+	anchor := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	e := equipeTeste(t, FolgasEquipeInput{DataAnchor: anchor, CicloDias: 7, FolgasPorCiclo: 1, Participantes: participantes(1, 2, 3, 4, 5, 6, 7)})
+	porUsuario := map[int64]int{}
+	for d := anchor; d.Before(anchor.AddDate(0, 0, 28)); d = d.AddDate(0, 0, 1) {
+		uid, ok := folgaUnica(t, e, d)
+		if !ok {
+			t.Fatalf("6x1 com 7 pessoas deveria ter uma folga todo dia (%s)", d.Format("2006-01-02"))
+		}
+		porUsuario[uid]++
+	}
+	for uid := int64(1); uid <= 7; uid++ {
+		if porUsuario[uid] != 4 {
+			t.Fatalf("usuário %d: esperadas 4 folgas em 4 semanas, got %d", uid, porUsuario[uid])
+		}
+	}
+	teo := folgasTeoricas(e, anchor, anchor.AddDate(0, 0, 13), nil)
+	if teo[3] != 2 {
+		t.Fatalf("folgasTeoricas: esperadas 2 em 14 dias, got %d", teo[3])
+	}
+}
+
+func TestUsuariosDeFolga_FimDeSemanaAlternado(t *testing.T) {
+	sabado := time.Date(2026, 10, 3, 0, 0, 0, 0, time.UTC)
+	zero, sete := 0, 7
+	e := equipeTeste(t, FolgasEquipeInput{
+		DataAnchor:     sabado,
+		CicloDias:      14,
+		FolgasPorCiclo: 2,
+		Participantes:  []FolgasParticipanteInput{{UsuarioID: 1, Deslocamento: &zero}, {UsuarioID: 2, Deslocamento: &sete}},
+	})
+	for semana := 0; semana < 4; semana++ {
+		esperado := int64(1)
+		if semana%2 == 1 {
+			esperado = 2
+		}
+		for d := 0; d < 7; d++ {
+			dia := sabado.AddDate(0, 0, semana*7+d)
+			ids := e.UsuariosDeFolga(dia)
+			fimDeSemana := dia.Weekday() == time.Saturday || dia.Weekday() == time.Sunday
+			if fimDeSemana && (len(ids) != 1 || ids[0] != esperado) {
+				t.Fatalf("%s: esperado usuário %d de folga, got %v", dia.Format("2006-01-02"), esperado, ids)
+			}
+			if !fimDeSemana && len(ids) != 0 {
+				t.Fatalf("%s: dia útil sem folga, got %v", dia.Format("2006-01-02"), ids)
+			}
+		}
+	}
+}
+
+func TestMontarEquipes_Validacao(t *testing.T) {
+	anchor := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	base := func() FolgasEquipeInput {
+		return FolgasEquipeInput{Nome: "A", DataAnchor: anchor, CicloDias: 6, FolgasPorCiclo: 1, Participantes: participantes(1, 2)}
+	}
+	fora := 6
+	casos := []struct {
+		nome string
+		in   func() []FolgasEquipeInput
+		err  error
+	}{
+		{"sem equipes", func() []FolgasEquipeInput { return nil }, ErrFolgasSemEquipes},
+		{"nome vazio", func() []FolgasEquipeInput { e := base(); e.Nome = "  "; return []FolgasEquipeInput{e} }, ErrFolgasEquipeNome},
+		{"nome repetido", func() []FolgasEquipeInput {
+			a, b := base(), base()
+			b.Nome = "a"
+			b.Participantes = participantes(3)
+			return []FolgasEquipeInput{a, b}
+		}, ErrFolgasEquipeNome},
+		{"folgas >= ciclo", func() []FolgasEquipeInput { e := base(); e.FolgasPorCiclo = 6; return []FolgasEquipeInput{e} }, ErrFolgasEquipeCiclo},
+		{"ciclo curto", func() []FolgasEquipeInput { e := base(); e.CicloDias = 1; return []FolgasEquipeInput{e} }, ErrFolgasEquipeCiclo},
+		{"sem participantes", func() []FolgasEquipeInput { e := base(); e.Participantes = nil; return []FolgasEquipeInput{e} }, ErrFolgasEquipeParticipantes},
+		{"usuário em duas equipes", func() []FolgasEquipeInput {
+			a, b := base(), base()
+			b.Nome = "B"
+			b.Participantes = participantes(2)
+			return []FolgasEquipeInput{a, b}
+		}, ErrFolgasEquipeParticipantes},
+		{"deslocamento fora do ciclo", func() []FolgasEquipeInput {
+			e := base()
+			e.Participantes[1].Deslocamento = &fora
+			return []FolgasEquipeInput{e}
+		}, ErrFolgasEquipeDeslocamento},
+	}
+	for _, c := range casos {
+		if _, err := montarEquipes(c.in()); !errors.Is(err, c.err) {
+			t.Errorf("%s: esperado %v, got %v", c.nome, c.err, err)
+		}
+	}
+	eqs, err := montarEquipes([]FolgasEquipeInput{{Nome: " 6x2 ", DataAnchor: anchor, CicloDias: 8, FolgasPorCiclo: 2, Participantes: participantes(1, 2, 3, 4, 5)}})
+	if err != nil {
+		t.Fatalf("montarEquipes: %v", err)
+	}
+	if eqs[0].Nome != "6x2" {
+		t.Fatalf("nome deveria vir sem espaços, got %q", eqs[0].Nome)
+	}
+	// Deslocamento padrão = posição × folgas, módulo ciclo.
+	for i, want := range []int{0, 2, 4, 6, 0} {
+		if p := eqs[0].Participantes[i]; p.Posicao != i || p.Deslocamento != want {
+			t.Fatalf("participante %d: posicao=%d deslocamento=%d, esperado %d", i, p.Posicao, p.Deslocamento, want)
+		}
+	}
+}
+
+func TestPlanejarFolgasAuto_PreservaManualPorEquipe(t *testing.T) {
+	anchor := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	eqs, err := montarEquipes([]FolgasEquipeInput{
+		{Nome: "A", DataAnchor: anchor, CicloDias: 6, FolgasPorCiclo: 1, Participantes: participantes(1, 2, 3)},
+		{Nome: "B", DataAnchor: anchor, CicloDias: 2, FolgasPorCiclo: 1, Participantes: participantes(4, 5)},
+	})
+	if err != nil {
+		t.Fatalf("montarEquipes: %v", err)
+	}
+	cfg := &models.FolgasEscalaConfig{FazendaID: 9, Equipes: eqs}
+	// Manual do usuário 2 (equipe A) no dia da âncora: equipe A fica de fora, B segue o padrão.
+	manuais := []models.EscalaFolga{{Data: anchor, UsuarioID: 2, Origem: models.FolgaOrigemManual}}
+	plano := planejarFolgasAuto(cfg, anchor, anchor.AddDate(0, 0, 1), manuais, nil)
+	got := map[string][]int64{}
+	for _, e := range plano {
+		if e.FazendaID != 9 || e.Origem != models.FolgaOrigemAuto {
+			t.Fatalf("folga planejada inválida: %+v", e)
+		}
+		k := e.Data.Format("2006-01-02")
+		got[k] = append(got[k], e.UsuarioID)
+	}
+	if d0 := got["2026-01-01"]; len(d0) != 1 || d0[0] != 4 {
+		t.Fatalf("dia da âncora: esperado só usuário 4 (equipe B), got %v", d0)
+	}
+	if d1 := got["2026-01-02"]; len(d1) != 2 || d1[0] != 2 || d1[1] != 5 {
+		t.Fatalf("dia seguinte: esperados 2 e 5, got %v", d1)
+	}
+}
+
+func TestAlertasFolgas_PorEquipe(t *testing.T) {
+	anchor := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	zero := 0
+	// Dupla folga junta: os dois com deslocamento 0 (dias 0 e 1 do ciclo de 4).
+	dupla := []FolgasParticipanteInput{{UsuarioID: 1, Deslocamento: &zero}, {UsuarioID: 2, Deslocamento: &zero}}
+	eqs, err := montarEquipes([]FolgasEquipeInput{
+		{Nome: "Dupla", DataAnchor: anchor, CicloDias: 4, FolgasPorCiclo: 2, Participantes: dupla},
+		{Nome: "Trio", DataAnchor: anchor, CicloDias: 6, FolgasPorCiclo: 1, Participantes: participantes(3, 4, 5)},
+	})
+	if err != nil {
+		t.Fatalf("montarEquipes: %v", err)
+	}
+	cfg := &models.FolgasEscalaConfig{Equipes: eqs}
+	d0, d1, d2 := anchor, anchor.AddDate(0, 0, 1), anchor.AddDate(0, 0, 2)
+	motivo := "evento"
+	linhas := []models.EscalaFolga{
+		// d0: 1 folga da Dupla (prevista) e 3 e 4 do Trio (previsto 1) -> alerta só do Trio.
+		{Data: d0, UsuarioID: 1},
+		{Data: d0, UsuarioID: 3},
+		{Data: d0, UsuarioID: 4},
+		// d1: dois fora das equipes -> alerta do grupo sem equipe.
+		{Data: d1, UsuarioID: 8},
+		{Data: d1, UsuarioID: 9},
+		// d2: Trio com duas, mas dia com exceção -> sem alerta.
+		{Data: d2, UsuarioID: 3, ExcecaoMotivoDia: &motivo},
+		{Data: d2, UsuarioID: 5, ExcecaoMotivoDia: &motivo},
+	}
+	alertas := alertasFolgas(cfg, linhas)
+	if len(alertas) != 2 {
+		t.Fatalf("esperados 2 alertas, got %+v", alertas)
+	}
+	if a := alertas[0]; !a.Data.Equal(d0) || a.QuantidadeFolga != 2 || a.EquipeNome == nil || *a.EquipeNome != "Trio" {
+		t.Fatalf("alerta d0 inesperado: %+v", a)
+	}
+	if a := alertas[1]; !a.Data.Equal(d1) || a.QuantidadeFolga != 2 || a.EquipeID != nil {
+		t.Fatalf("alerta d1 inesperado: %+v", a)
+	}
+	// Dupla prevê duas folgas em d0: registrar ambas não gera alerta; todas justificadas também não.
+	linhas = []models.EscalaFolga{{Data: d0, UsuarioID: 1}, {Data: d0, UsuarioID: 2}, {Data: d1, UsuarioID: 3, Justificada: true}, {Data: d1, UsuarioID: 4, Justificada: true}}
+	if alertas := alertasFolgas(cfg, linhas); len(alertas) != 0 {
+		t.Fatalf("esperado nenhum alerta, got %+v", alertas)
+	}
+	// Sem configuração: regra de uma folga por dia.
+	if alertas := alertasFolgas(nil, []models.EscalaFolga{{Data: d0, UsuarioID: 1}, {Data: d0, UsuarioID: 2}}); len(alertas) != 1 {
+		t.Fatalf("sem configuração esperado 1 alerta, got %+v", alertas)
+	}
+}
+
 func TestIsEscalaFolgasUniqueViolation_VariaConstraintName(t *testing.T) {
 	t.Parallel()
 
 	// Nome padrão da constraint gerada pela migration.
 	err1 := &pgconn.PgError{
-		Code:          "23505",
-		TableName:     "escala_folgas",
+		Code:           "23505",
+		TableName:      "escala_folgas",
 		ConstraintName: "escala_folgas_fazenda_id_data_usuario_id_key",
-		Message:       `duplicate key value violates unique constraint "escala_folgas_fazenda_id_data_usuario_id_key"`,
+		Message:        `duplicate key value violates unique constraint "escala_folgas_fazenda_id_data_usuario_id_key"`,
 	}
 	if !isEscalaFolgasUniqueViolation(err1) {
 		t.Fatalf("esperado true para constraint padrão")
@@ -83,10 +292,10 @@ func TestIsEscalaFolgasUniqueViolation_VariaConstraintName(t *testing.T) {
 
 	// Variação comum: sufixo numérico quando a constraint/index foi recriada.
 	err2 := &pgconn.PgError{
-		Code:          "23505",
-		TableName:     "escala_folgas",
+		Code:           "23505",
+		TableName:      "escala_folgas",
 		ConstraintName: "escala_folgas_fazenda_id_data_usuario_id_key1",
-		Message:       `duplicate key value violates unique constraint "escala_folgas_fazenda_id_data_usuario_id_key1"`,
+		Message:        `duplicate key value violates unique constraint "escala_folgas_fazenda_id_data_usuario_id_key1"`,
 	}
 	if !isEscalaFolgasUniqueViolation(err2) {
 		t.Fatalf("esperado true para constraint com sufixo")
@@ -94,10 +303,10 @@ func TestIsEscalaFolgasUniqueViolation_VariaConstraintName(t *testing.T) {
 
 	// Não deve cair como escala_folgas.
 	err3 := &pgconn.PgError{
-		Code:          "23505",
-		TableName:     "outra_tabela",
+		Code:           "23505",
+		TableName:      "outra_tabela",
 		ConstraintName: "outra_tabela_x_y_key",
-		Message:       `duplicate key value violates unique constraint "outra_tabela_x_y_key"`,
+		Message:        `duplicate key value violates unique constraint "outra_tabela_x_y_key"`,
 	}
 	if isEscalaFolgasUniqueViolation(err3) {
 		t.Fatalf("esperado false para outra tabela")
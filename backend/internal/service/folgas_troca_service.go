@@ -0,0 +1,403 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrFolgasTrocaNotFound         = errors.New("troca de folga não encontrada")
+	ErrFolgasTrocaMotivo           = errors.New("motivo é obrigatório (até 500 caracteres)")
+	ErrFolgasTrocaMesmoUsuario     = errors.New("escolha outro colega para a troca")
+	ErrFolgasTrocaDatas            = errors.New("as datas da troca devem ser diferentes e a partir de hoje")
+	ErrFolgasTrocaSemFolga         = errors.New("você precisa ter folga na data cedida e o colega na data pedida")
+	ErrFolgasTrocaDestinoOcupado   = errors.New("você ou o colega já têm folga ou ausência na data que receberiam")
+	ErrFolgasTrocaPendenteExiste   = errors.New("já existe troca pendente envolvendo uma dessas folgas")
+	ErrFolgasTrocaStatusInvalido   = errors.New("a troca não está mais aguardando esta ação")
+	ErrFolgasTrocaVencida          = errors.New("a troca envolve data que já passou; peça uma nova troca")
+	ErrFolgasTrocaEscalaMudou      = repository.ErrFolgasTrocaEscalaMudou
+	ErrFolgasTrocaAcaoNaoPermitida = errors.New("sem permissão para esta ação na troca")
+)
+
+// Ações sobre uma troca de folga (BR-FOLGAS-009).
+const (
+	folgaTrocaAceitar  = "aceitar"
+	folgaTrocaRecusar  = "recusar"
+	folgaTrocaAprovar  = "aprovar"
+	folgaTrocaRejeitar = "rejeitar"
+	folgaTrocaCancelar = "cancelar"
+)
+
+// FolgaTrocaInput pedido de troca: o solicitante cede DataSolicitante e assume a folga do colega em DataColega.
+type FolgaTrocaInput struct {
+	ColegaID        int64
+	DataSolicitante time.Time
+	DataColega      time.Time
+	Motivo          string
+}
+
+// transicaoTroca valida quem pode executar a ação no status atual e devolve o novo status:
+// o colega aceita/recusa, a gestão aprova/rejeita depois do aceite e o solicitante cancela enquanto pendente.
+func transicaoTroca(t *models.FolgaTroca, acao string, userID int64, perfil string) (string, error) {
+	var de, para string
+	switch acao {
+	case folgaTrocaAceitar, folgaTrocaRecusar:
+		if userID != t.ColegaID {
+			return "", ErrFolgasTrocaAcaoNaoPermitida
+		}
+		de, para = models.FolgaTrocaPendenteColega, models.FolgaTrocaPendenteGestao
+		if acao == folgaTrocaRecusar {
+			para = models.FolgaTrocaRecusada
+		}
+	case folgaTrocaAprovar, folgaTrocaRejeitar:
+		if !models.PodeGerenciarFolgas(perfil) {
+			return "", ErrFolgasTrocaAcaoNaoPermitida
+		}
+		de, para = models.FolgaTrocaPendenteGestao, models.FolgaTrocaAprovada
+		if acao == folgaTrocaRejeitar {
+			para = models.FolgaTrocaRejeitada
+		}
+	case folgaTrocaCancelar:
+		if userID != t.SolicitanteID {
+			return "", ErrFolgasTrocaAcaoNaoPermitida
+		}
+		if !t.Pendente() {
+			return "", ErrFolgasTrocaStatusInvalido
+		}
+		return models.FolgaTrocaCancelada, nil
+	default:
+		return "", fmt.Errorf("ação inválida")
+	}
+	if t.Status != de {
+		return "", ErrFolgasTrocaStatusInvalido
+	}
+	return para, nil
+}
+
+// SolicitarTroca registra o pedido (PENDENTE_COLEGA) e avisa o colega.
+func (s *FolgasService) SolicitarTroca(ctx context.Context, fazendaID int64, in FolgaTrocaInput, userID int64, perfil string) (*models.FolgaTroca, error) {
+	if err := s.validarAcessoFazenda(ctx, fazendaID, perfil, userID); err != nil {
+		return nil, err
+	}
+	motivo := strings.TrimSpace(in.Motivo)
+	if motivo == "" || len([]rune(motivo)) > 500 {
+		return nil, ErrFolgasTrocaMotivo
+	}
+	if in.ColegaID == userID {
+		return nil, ErrFolgasTrocaMesmoUsuario
+	}
+	dSol, dCol := truncateDateUTC(in.DataSolicitante), truncateDateUTC(in.DataColega)
+	hoje := truncateDateUTC(s.now())
+	if dSol.Equal(dCol) || dSol.Before(hoje) || dCol.Before(hoje) {
+		return nil, ErrFolgasTrocaDatas
+	}
+	for _, uid := range []int64{userID, in.ColegaID} {
+		ok, err := s.repo.UsuarioTemFazendaComPerfilPermitido(ctx, uid, fazendaID)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, ErrFolgasPerfilNaoPermitido
+		}
+	}
+	folgasSol, err := s.usuariosDeFolgaEm(ctx, fazendaID, dSol)
+	if err != nil {
+		return nil, err
+	}
+	folgasCol, err := s.usuariosDeFolgaEm(ctx, fazendaID, dCol)
+	if err != nil {
+		return nil, err
+	}
+	if !folgasSol[userID] || !folgasCol[in.ColegaID] {
+		return nil, ErrFolgasTrocaSemFolga
+	}
+	if folgasCol[userID] || folgasSol[in.ColegaID] {
+		return nil, ErrFolgasTrocaDestinoOcupado
+	}
+	ausencias, err := s.repo.ListAusenciasRange(ctx, fazendaID, minData(dSol, dCol), maxData(dSol, dCol), nil)
+	if err != nil {
+		return nil, err
+	}
+	if trocaColideComAusencia(ausencias, userID, in.ColegaID, dSol, dCol) {
+		return nil, ErrFolgasTrocaDestinoOcupado
+	}
+	for _, f := range []struct {
+		uid int64
+		d   time.Time
+	}{{userID, dSol}, {in.ColegaID, dCol}} {
+		pendente, err := s.repo.ExisteTrocaPendente(ctx, fazendaID, f.uid, f.d)
+		if err != nil {
+			return nil, err
+		}
+		if pendente {
+			return nil, ErrFolgasTrocaPendenteExiste
+		}
+	}
+	t := &models.FolgaTroca{
+		FazendaID:       fazendaID,
+		SolicitanteID:   userID,
+		ColegaID:        in.ColegaID,
+		DataSolicitante: dSol,
+		DataColega:      dCol,
+		Motivo:          motivo,
+		Status:          models.FolgaTrocaPendenteColega,
+	}
+	if err := s.repo.InsertTroca(ctx, t); err != nil {
+		return nil, err
+	}
+	criada, err := s.repo.GetTroca(ctx, fazendaID, t.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.notificarTroca(criada, []int64{criada.ColegaID}, "Pedido de troca de folga",
+		fmt.Sprintf("%s propõe trocar a folga de %s pela sua de %s.", criada.SolicitanteNome, formatarDataTroca(dSol), formatarDataTroca(dCol)))
+	return criada, nil
+}
+
+func (s *FolgasService) usuariosDeFolgaEm(ctx context.Context, fazendaID int64, d time.Time) (map[int64]bool, error) {
+	rows, err := s.repo.ListFolgasUsuarioOnDate(ctx, fazendaID, d)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[int64]bool, len(rows))
+	for _, r := range rows {
+		out[r.UsuarioID] = true
+	}
+	return out, nil
+}
+
+// ResponderTroca o colega aceita (vai para a gestão) ou recusa o pedido.
+func (s *FolgasService) ResponderTroca(ctx context.Context, fazendaID, trocaID int64, aceitar bool, userID int64, perfil string) (*models.FolgaTroca, error) {
+	acao := folgaTrocaRecusar
+	if aceitar {
+		acao = folgaTrocaAceitar
+	}
+	t, err := s.carregarTrocaParaAcao(ctx, fazendaID, trocaID, acao, userID, perfil)
+	if err != nil {
+		return nil, err
+	}
+	de := t.Status
+	agora := s.now()
+	t.Status, _ = transicaoTroca(t, acao, userID, perfil)
+	t.RespondidoEm = &agora
+	if err := s.atualizarStatusTroca(ctx, t, de); err != nil {
+		return nil, err
+	}
+	if aceitar {
+		gestores, err := s.repo.ListGestoresFazenda(ctx, fazendaID)
+		if err != nil {
+			slog.Warn("folgas: listar gestores para aviso de troca", "error", err, "fazenda_id", fazendaID)
+		}
+		s.notificarTroca(t, append([]int64{t.SolicitanteID}, gestores...), "Troca de folga aguardando aprovação",
+			fmt.Sprintf("%s aceitou trocar a folga de %s com %s (%s). Falta a aprovação da gestão.",
+				t.ColegaNome, formatarDataTroca(t.DataColega), t.SolicitanteNome, formatarDataTroca(t.DataSolicitante)))
+	} else {
+		s.notificarTroca(t, []int64{t.SolicitanteID}, "Troca de folga recusada",
+			fmt.Sprintf("%s recusou a troca da folga de %s.", t.ColegaNome, formatarDataTroca(t.DataSolicitante)))
+	}
+	return t, nil
+}
+
+// DecidirTroca a gestão aprova (aplica a troca na escala) ou rejeita uma troca já aceita pelo colega.
+func (s *FolgasService) DecidirTroca(ctx context.Context, fazendaID, trocaID int64, aprovar bool, observacao string, userID int64, perfil string) (*models.FolgaTroca, error) {
+	acao := folgaTrocaRejeitar
+	if aprovar {
+		acao = folgaTrocaAprovar
+	}
+	t, err := s.carregarTrocaParaAcao(ctx, fazendaID, trocaID, acao, userID, perfil)
+	if err != nil {
+		return nil, err
+	}
+	de := t.Status
+	agora := s.now()
+	t.DecididoPor = &userID
+	t.DecididoEm = &agora
+	if obs := strings.TrimSpace(observacao); obs != "" {
+		t.ObservacaoDecisao = &obs
+	}
+	if aprovar {
+		if err := s.validarAprovacaoTroca(ctx, t); err != nil {
+			return nil, err
+		}
+		err = s.repo.AplicarTroca(ctx, t, &models.FolgaAlteracao{
+			FazendaID: fazendaID,
+			ActorID:   &userID,
+			Tipo:      "TROCA",
+			Detalhes: map[string]any{
+				"troca_id":         t.ID,
+				"solicitante_id":   t.SolicitanteID,
+				"colega_id":        t.ColegaID,
+				"data_solicitante": t.DataSolicitante.Format("2006-01-02"),
+				"data_colega":      t.DataColega.Format("2006-01-02"),
+				"motivo":           t.Motivo,
+			},
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrFolgasTrocaStatusInvalido
+		}
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		t.Status = models.FolgaTrocaRejeitada
+		if err := s.atualizarStatusTroca(ctx, t, de); err != nil {
+			return nil, err
+		}
+	}
+	titulo, corpo := "Troca de folga aprovada", fmt.Sprintf("%s folga em %s e %s em %s.",
+		t.SolicitanteNome, formatarDataTroca(t.DataColega), t.ColegaNome, formatarDataTroca(t.DataSolicitante))
+	if !aprovar {
+		titulo, corpo = "Troca de folga rejeitada", fmt.Sprintf("A gestão rejeitou a troca entre %s (%s) e %s (%s).",
+			t.SolicitanteNome, formatarDataTroca(t.DataSolicitante), t.ColegaNome, formatarDataTroca(t.DataColega))
+	}
+	if t.ObservacaoDecisao != nil {
+		corpo += " " + *t.ObservacaoDecisao
+	}
+	s.notificarTroca(t, []int64{t.SolicitanteID, t.ColegaID}, titulo, corpo)
+	return t, nil
+}
+
+// validarAprovacaoTroca repete na aprovação as checagens de data e ausência do pedido: entre o pedido e a
+// decisão as datas podem ter passado ou a gestão pode ter registrado ausência para um dos envolvidos.
+func (s *FolgasService) validarAprovacaoTroca(ctx context.Context, t *models.FolgaTroca) error {
+	dSol, dCol := truncateDateUTC(t.DataSolicitante), truncateDateUTC(t.DataColega)
+	hoje := truncateDateUTC(s.now())
+	if dSol.Before(hoje) || dCol.Before(hoje) {
+		return ErrFolgasTrocaVencida
+	}
+	ausencias, err := s.repo.ListAusenciasRange(ctx, t.FazendaID, minData(dSol, dCol), maxData(dSol, dCol), nil)
+	if err != nil {
+		return err
+	}
+	if trocaColideComAusencia(ausencias, t.SolicitanteID, t.ColegaID, dSol, dCol) {
+		return ErrFolgasTrocaDestinoOcupado
+	}
+	return nil
+}
+
+// trocaColideComAusencia indica se alguém assumiria folga num dia em que está ausente.
+func trocaColideComAusencia(ausencias []models.FolgaAusencia, solicitanteID, colegaID int64, dSol, dCol time.Time) bool {
+	return ausentesNoDia(ausencias, dCol)[solicitanteID] || ausentesNoDia(ausencias, dSol)[colegaID]
+}
+
+// CancelarTroca o solicitante desiste enquanto a troca está pendente.
+func (s *FolgasService) CancelarTroca(ctx context.Context, fazendaID, trocaID int64, userID int64, perfil string) (*models.FolgaTroca, error) {
+	t, err := s.carregarTrocaParaAcao(ctx, fazendaID, trocaID, folgaTrocaCancelar, userID, perfil)
+	if err != nil {
+		return nil, err
+	}
+	de := t.Status
+	t.Status = models.FolgaTrocaCancelada
+	if err := s.atualizarStatusTroca(ctx, t, de); err != nil {
+		return nil, err
+	}
+	s.notificarTroca(t, []int64{t.ColegaID}, "Troca de folga cancelada",
+		fmt.Sprintf("%s cancelou o pedido de troca da folga de %s.", t.SolicitanteNome, formatarDataTroca(t.DataSolicitante)))
+	return t, nil
+}
+
+// ListTrocas gestão vê todas as trocas da fazenda; os demais, só as em que são solicitante ou colega.
+func (s *FolgasService) ListTrocas(ctx context.Context, fazendaID int64, status string, perfil string, userID int64) ([]models.FolgaTroca, error) {
+	if err := s.validarAcessoFazenda(ctx, fazendaID, perfil, userID); err != nil {
+		return nil, err
+	}
+	var filtro *int64
+	if !models.PodeGerenciarFolgas(perfil) {
+		filtro = &userID
+	}
+	return s.repo.ListTrocas(ctx, fazendaID, filtro, status, 100)
+}
+
+func (s *FolgasService) carregarTrocaParaAcao(ctx context.Context, fazendaID, trocaID int64, acao string, userID int64, perfil string) (*models.FolgaTroca, error) {
+	if err := s.validarAcessoFazenda(ctx, fazendaID, perfil, userID); err != nil {
+		return nil, err
+	}
+	t, err := s.repo.GetTroca(ctx, fazendaID, trocaID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrFolgasTrocaNotFound
+		}
+		return nil, err
+	}
+	if _, err := transicaoTroca(t, acao, userID, perfil); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (s *FolgasService) atualizarStatusTroca(ctx context.Context, t *models.FolgaTroca, de string) error {
+	if err := s.repo.UpdateTrocaStatus(ctx, t, de); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrFolgasTrocaStatusInvalido
+		}
+		return err
+	}
+	return nil
+}
+
+// notificarTroca avisa em segundo plano; falhas de entrega não afetam a troca.
+func (s *FolgasService) notificarTroca(t *models.FolgaTroca, usuarioIDs []int64, titulo, corpo string) {
+	if s.notificador == nil || len(usuarioIDs) == 0 {
+		return
+	}
+	usuarioIDs = slices.Compact(slices.Sorted(slices.Values(usuarioIDs)))
+	msg := NotificacaoMensagem{Titulo: titulo, Corpo: corpo, URL: "/folgas"}
+	fazendaID := t.FazendaID
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		s.notificador.NotificarUsuarios(ctx, fazendaID, usuarioIDs, msg)
+	}()
+}
+
+func minData(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxData(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func formatarDataTroca(d time.Time) string {
+	return d.Format("02/01/2006")
+}
+
+// alertasTrocasPendentes um alerta por troca pendente, na data mais próxima da troca.
+func alertasTrocasPendentes(trocas []models.FolgaTroca) []models.FolgaAlertaDia {
+	out := make([]models.FolgaAlertaDia, 0, len(trocas))
+	for _, t := range trocas {
+		d := t.DataSolicitante
+		if t.DataColega.Before(d) {
+			d = t.DataColega
+		}
+		aguardando := "colega"
+		if t.Status == models.FolgaTrocaPendenteGestao {
+			aguardando = "aprovação da gestão"
+		}
+		id := t.ID
+		out = append(out, models.FolgaAlertaDia{
+			Data:            d,
+			Tipo:            models.FolgaAlertaTrocaPendente,
+			QuantidadeFolga: 2,
+			MotivoAlerta: fmt.Sprintf("Troca pendente (%s): %s %s ↔ %s %s",
+				aguardando, t.SolicitanteNome, formatarDataTroca(t.DataSolicitante), t.ColegaNome, formatarDataTroca(t.DataColega)),
+			TrocaID: &id,
+		})
+	}
+	return out
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+)
+
+func TestTransicaoTroca(t *testing.T) {
+	const solicitante, colega, gerente = int64(1), int64(2), int64(9)
+	troca := func(status string) *models.FolgaTroca {
+		return &models.FolgaTroca{ID: 5, SolicitanteID: solicitante, ColegaID: colega, Status: status}
+	}
+	cases := []struct {
+		nome    string
+		status  string
+		acao    string
+		userID  int64
+		perfil  string
+		want    string
+		wantErr error
+	}{
+		{"colega aceita", models.FolgaTrocaPendenteColega, folgaTrocaAceitar, colega, models.PerfilFuncionario, models.FolgaTrocaPendenteGestao, nil},
+		{"colega recusa", models.FolgaTrocaPendenteColega, folgaTrocaRecusar, colega, models.PerfilFuncionario, models.FolgaTrocaRecusada, nil},
+		{"solicitante não aceita pelo colega", models.FolgaTrocaPendenteColega, folgaTrocaAceitar, solicitante, models.PerfilFuncionario, "", ErrFolgasTrocaAcaoNaoPermitida},
+		{"gestão não aprova antes do colega", models.FolgaTrocaPendenteColega, folgaTrocaAprovar, gerente, models.PerfilGerente, "", ErrFolgasTrocaStatusInvalido},
+		{"gestão aprova", models.FolgaTrocaPendenteGestao, folgaTrocaAprovar, gerente, models.PerfilGerente, models.FolgaTrocaAprovada, nil},
+		{"gestão rejeita", models.FolgaTrocaPendenteGestao, folgaTrocaRejeitar, gerente, models.PerfilGerente, models.FolgaTrocaRejeitada, nil},
+		{"funcionário não aprova", models.FolgaTrocaPendenteGestao, folgaTrocaAprovar, colega, models.PerfilFuncionario, "", ErrFolgasTrocaAcaoNaoPermitida},
+		{"colega não responde de novo", models.FolgaTrocaPendenteGestao, folgaTrocaRecusar, colega, models.PerfilFuncionario, "", ErrFolgasTrocaStatusInvalido},
+		{"solicitante cancela aguardando gestão", models.FolgaTrocaPendenteGestao, folgaTrocaCancelar, solicitante, models.PerfilFuncionario, models.FolgaTrocaCancelada, nil},
+		{"colega não cancela", models.FolgaTrocaPendenteColega, folgaTrocaCancelar, colega, models.PerfilFuncionario, "", ErrFolgasTrocaAcaoNaoPermitida},
+		{"aprovada não cancela", models.FolgaTrocaAprovada, folgaTrocaCancelar, solicitante, models.PerfilFuncionario, "", ErrFolgasTrocaStatusInvalido},
+	}
+	for _, tc := range cases {
+		got, err := transicaoTroca(troca(tc.status), tc.acao, tc.userID, tc.perfil)
+		if tc.wantErr != nil {
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("%s: err = %v, want %v", tc.nome, err, tc.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("%s: got %q, %v; want %q", tc.nome, got, err, tc.want)
+		}
+	}
+}
+
+func TestAlertasTrocasPendentes(t *testing.T) {
+	d1 := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	d2 := d1.AddDate(0, 0, 3)
+	alertas := alertasTrocasPendentes([]models.FolgaTroca{{
+		ID: 7, SolicitanteNome: "Ana", ColegaNome: "Bruno",
+		DataSolicitante: d2, DataColega: d1, Status: models.FolgaTrocaPendenteGestao,
+	}})
+	if len(alertas) != 1 {
+		t.Fatalf("len = %d", len(alertas))
+	}
+	a := alertas[0]
+	if !a.Data.Equal(d1) || a.TrocaID == nil || *a.TrocaID != 7 {
+		t.Fatalf("alerta = %+v", a)
+	}
+	if !strings.Contains(a.MotivoAlerta, "aprovação da gestão") || !strings.Contains(a.MotivoAlerta, "Ana") {
+		t.Fatalf("motivo = %q", a.MotivoAlerta)
+	}
+}
+
+func TestValidarAprovacaoTroca_DataPassada(t *testing.T) {
+	hoje := time.Date(2026, 10, 20, 15, 0, 0, 0, time.UTC)
+	s := &FolgasService{now: func() time.Time { return hoje }}
+	troca := &models.FolgaTroca{
+		FazendaID: 1, SolicitanteID: 1, ColegaID: 2,
+		DataSolicitante: hoje.AddDate(0, 0, 2), DataColega: hoje.AddDate(0, 0, -1),
+	}
+	if err := s.validarAprovacaoTroca(context.Background(), troca); !errors.Is(err, ErrFolgasTrocaVencida) {
+		t.Fatalf("err = %v, want %v", err, ErrFolgasTrocaVencida)
+	}
+}
+
+func TestTrocaColideComAusencia(t *testing.T) {
+	const solicitante, colega = int64(1), int64(2)
+	dSol := time.Date(2026, 10, 22, 0, 0, 0, 0, time.UTC)
+	dCol := dSol.AddDate(0, 0, 3)
+	ausencia := func(uid int64, inicio, fim time.Time) []models.FolgaAusencia {
+		return []models.FolgaAusencia{{UsuarioID: uid, DataInicio: inicio, DataFim: fim}}
+	}
+	cases := []struct {
+		nome      string
+		ausencias []models.FolgaAusencia
+		want      bool
+	}{
+		{"sem ausências", nil, false},
+		{"solicitante ausente no dia que receberia", ausencia(solicitante, dCol.AddDate(0, 0, -1), dCol), true},
+		{"colega ausente no dia que receberia", ausencia(colega, dSol, dSol), true},
+		{"solicitante ausente no dia que cede", ausencia(solicitante, dSol, dSol), false},
+		{"ausência fora das datas", ausencia(colega, dSol.AddDate(0, 0, 1), dCol.AddDate(0, 0, -1)), false},
+	}
+	for _, tc := range cases {
+		if got := trocaColideComAusencia(tc.ausencias, solicitante, colega, dSol, dCol); got != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.nome, got, tc.want)
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 var ErrGestacaoNotFound = errors.New("gestacao nao encontrada")
 
 type GestacaoService struct {
+	auditavel
 	repo        *repository.GestacaoRepository
 	animalRepo  *repository.AnimalRepository
 	fazendaRepo *repository.FazendaRepository
@@ -46,7 +47,11 @@ func (s *GestacaoService) Create(ctx context.Context, g *models.Gestacao) error
 	if animal.FazendaID != g.FazendaID {
 		return errors.New("animal deve ser da mesma fazenda")
 	}
-	return s.repo.Create(ctx, g)
+	if err := s.repo.Create(ctx, g); err != nil {
+		return err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoCreate, models.AuditoriaEntidadeGestacao, g.ID, g.FazendaID, g.AnimalID, nil, g)
+	return nil
 }
 
 func (s *GestacaoService) GetByID(ctx context.Context, id int64) (*models.Gestacao, error) {
@@ -123,12 +128,16 @@ func (s *GestacaoService) Update(ctx context.Context, g *models.Gestacao) error
 	if g.ID <= 0 {
 		return errors.New("id invalido")
 	}
-	_, err := s.repo.GetByID(ctx, g.ID)
+	existing, err := s.repo.GetByID(ctx, g.ID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return ErrGestacaoNotFound
 		}
 		return err
 	}
-	return s.repo.Update(ctx, g)
+	if err := s.repo.Update(ctx, g); err != nil {
+		return err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeGestacao, g.ID, existing.FazendaID, existing.AnimalID, existing, g)
+	return nil
 }
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+)
+
+var (
+	ErrIntegracaoChaveExpirada        = errors.New("chave de integracao expirada")
+	ErrIntegracaoIPNaoPermitido       = errors.New("origem nao permitida para o cliente de integracao")
+	ErrIntegracaoIPAllowlistInvalida  = errors.New("ip_allowlist invalida: use IPs ou CIDRs (ex.: 203.0.113.0/24)")
+	ErrIntegracaoExpiracaoNoPassado   = errors.New("chave_expira_em deve ser uma data futura")
+	ErrIntegracaoPeriodoGracaInvalido = errors.New("periodo_graca_horas deve estar entre 0 e 720")
+)
+
+// maxPeriodoGracaHoras limita a sobreposição entre chave antiga e nova (30 dias).
+const maxPeriodoGracaHoras = 720
+
+// IntegracaoRejeicaoError descreve uma rejeição de autenticação M2M com o motivo registado na auditoria.
+// ClienteID é 0 quando o cliente não pôde ser identificado.
+type IntegracaoRejeicaoError struct {
+	ClienteID int64
+	Motivo    string
+	Err       error
+}
+
+func (e *IntegracaoRejeicaoError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Motivo, e.Err)
+}
+
+func (e *IntegracaoRejeicaoError) Unwrap() error {
+	return e.Err
+}
+
+func rejeicao(clienteID int64, motivo string, err error) error {
+	return &IntegracaoRejeicaoError{ClienteID: clienteID, Motivo: motivo, Err: err}
+}
+
+// AvaliarChaveAPI valida a chave contra a atual e a anterior (período de graça) do cliente.
+// Devolve "" quando a chave é aceite, ou o motivo de rejeição (models.IntegracaoRejeicao*).
+// O hash é verificado antes de qualquer outro estado para não expor informação a quem não tem a chave.
+func AvaliarChaveAPI(c *models.IntegracaoCliente, fullKey string, now time.Time) string {
+	prefix, err := ExtractKeyPrefix(fullKey)
+	if err != nil {
+		return models.IntegracaoRejeicaoFormatoInvalido
+	}
+	switch {
+	case prefix == c.KeyPrefix:
+		if !CompareAPIKey(fullKey, c.KeyHash) {
+			return models.IntegracaoRejeicaoChaveInvalida
+		}
+		if !c.Ativo || c.RevogadoEm != nil {
+			return models.IntegracaoRejeicaoClienteInativo
+		}
+		if c.ChaveExpiraEm != nil && !now.Before(*c.ChaveExpiraEm) {
+			return models.IntegracaoRejeicaoChaveExpirada
+		}
+	case c.KeyPrefixAnterior != nil && prefix == *c.KeyPrefixAnterior:
+		if c.KeyHashAnterior == "" || !CompareAPIKey(fullKey, c.KeyHashAnterior) {
+			return models.IntegracaoRejeicaoChaveInvalida
+		}
+		if !c.Ativo || c.RevogadoEm != nil {
+			return models.IntegracaoRejeicaoClienteInativo
+		}
+		if c.ChaveAnteriorExpiraEm == nil || !now.Before(*c.ChaveAnteriorExpiraEm) {
+			return models.IntegracaoRejeicaoChaveAnteriorExpirada
+		}
+	default:
+		return models.IntegracaoRejeicaoChaveDesconhecida
+	}
+	return ""
+}
+
+// NormalizarIPAllowlist valida IPs/CIDRs e devolve-os em notação CIDR canónica (IP simples → /32 ou /128).
+func NormalizarIPAllowlist(entradas []string) ([]string, error) {
+	out := make([]string, 0, len(entradas))
+	vistos := make(map[string]struct{}, len(entradas))
+	for _, e := range entradas {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+		var prefix netip.Prefix
+		if strings.Contains(e, "/") {
+			p, err := netip.ParsePrefix(e)
+			if err != nil {
+				return nil, ErrIntegracaoIPAllowlistInvalida
+			}
+			prefix = p.Masked()
+		} else {
+			addr, err := netip.ParseAddr(e)
+			if err != nil {
+				return nil, ErrIntegracaoIPAllowlistInvalida
+			}
+			addr = addr.Unmap()
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		s := prefix.String()
+		if _, ok := vistos[s]; ok {
+			continue
+		}
+		vistos[s] = struct{}{}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// IPPermitido indica se o IP de origem está na allowlist; allowlist vazia permite qualquer origem.
+func IPPermitido(allowlist []string, ip string) bool {
+	if len(allowlist) == 0 {
+		return true
+	}
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, cidr := range allowlist {
+		p, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			continue
+		}
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+)
+
+func novoClienteComChave(t *testing.T) (*models.IntegracaoCliente, string) {
+	t.Helper()
+	fullKey, prefix, hash, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &models.IntegracaoCliente{ID: 7, KeyPrefix: prefix, KeyHash: hash, Ativo: true}, fullKey
+}
+
+func TestAvaliarChaveAPI_ChaveAtual(t *testing.T) {
+	c, key := novoClienteComChave(t)
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	if m := AvaliarChaveAPI(c, key, now); m != "" {
+		t.Fatalf("expected chave aceite, got %s", m)
+	}
+	adulterada := key[:len(key)-1] + "0"
+	if strings.HasSuffix(key, "0") {
+		adulterada = key[:len(key)-1] + "1"
+	}
+	if m := AvaliarChaveAPI(c, adulterada, now); m != models.IntegracaoRejeicaoChaveInvalida {
+		t.Fatalf("expected CHAVE_INVALIDA, got %s", m)
+	}
+	expira := now
+	c.ChaveExpiraEm = &expira
+	if m := AvaliarChaveAPI(c, key, now); m != models.IntegracaoRejeicaoChaveExpirada {
+		t.Fatalf("expected CHAVE_EXPIRADA no instante da expiração, got %s", m)
+	}
+	futuro := now.Add(time.Hour)
+	c.ChaveExpiraEm = &futuro
+	c.Ativo = false
+	if m := AvaliarChaveAPI(c, key, now); m != models.IntegracaoRejeicaoClienteInativo {
+		t.Fatalf("expected CLIENTE_INATIVO, got %s", m)
+	}
+}
+
+func TestAvaliarChaveAPI_PeriodoGraca(t *testing.T) {
+	c, antiga := novoClienteComChave(t)
+	nova, prefix, hash, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	fimGraca := now.Add(24 * time.Hour)
+	anteriorPrefix := c.KeyPrefix
+	c.KeyPrefixAnterior, c.KeyHashAnterior, c.ChaveAnteriorExpiraEm = &anteriorPrefix, c.KeyHash, &fimGraca
+	c.KeyPrefix, c.KeyHash = prefix, hash
+
+	if m := AvaliarChaveAPI(c, nova, now); m != "" {
+		t.Fatalf("expected nova chave aceite, got %s", m)
+	}
+	if m := AvaliarChaveAPI(c, antiga, now.Add(23*time.Hour)); m != "" {
+		t.Fatalf("expected chave anterior aceite durante a graça, got %s", m)
+	}
+	if m := AvaliarChaveAPI(c, antiga, fimGraca); m != models.IntegracaoRejeicaoChaveAnteriorExpirada {
+		t.Fatalf("expected CHAVE_ANTERIOR_EXPIRADA, got %s", m)
+	}
+	c.ChaveAnteriorExpiraEm = nil
+	if m := AvaliarChaveAPI(c, antiga, now); m != models.IntegracaoRejeicaoChaveAnteriorExpirada {
+		t.Fatalf("expected CHAVE_ANTERIOR_EXPIRADA sem graça, got %s", m)
+	}
+	outra, _, _, _ := GenerateAPIKey()
+	if m := AvaliarChaveAPI(c, outra, now); m != models.IntegracaoRejeicaoChaveDesconhecida {
+		t.Fatalf("expected CHAVE_DESCONHECIDA, got %s", m)
+	}
+}
+
+func TestNormalizarIPAllowlist(t *testing.T) {
+	got, err := NormalizarIPAllowlist([]string{" 203.0.113.7 ", "198.51.100.0/24", "198.51.100.9/24", "", "2001:db8::1", "::ffff:10.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"203.0.113.7/32", "198.51.100.0/24", "2001:db8::1/128", "10.0.0.1/32"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for _, invalido := range []string{"10.0.0.0/33", "not-an-ip", "10.0.0"} {
+		if _, err := NormalizarIPAllowlist([]string{invalido}); !errors.Is(err, ErrIntegracaoIPAllowlistInvalida) {
+			t.Fatalf("%q: expected ErrIntegracaoIPAllowlistInvalida, got %v", invalido, err)
+		}
+	}
+}
+
+func TestIPPermitido(t *testing.T) {
+	if !IPPermitido(nil, "192.0.2.1") {
+		t.Fatal("allowlist vazia deve permitir qualquer origem")
+	}
+	allow := []string{"198.51.100.0/24", "2001:db8::/32"}
+	cases := map[string]bool{
+		"198.51.100.42":       true,
+		"198.51.101.1":        false,
+		"2001:db8:1::5":       true,
+		"::ffff:198.51.100.1": true,
+		"":                    false,
+		"lixo":                false,
+	}
+	for ip, want := range cases {
+		if got := IPPermitido(allow, ip); got != want {
+			t.Fatalf("IPPermitido(%q) = %v, want %v", ip, got, want)
+		}
+	}
+}
+
+func TestVerificarOrigem_Rejeicao(t *testing.T) {
+	s := &IntegracaoService{}
+	c := &models.IntegracaoCliente{ID: 9, IPAllowlist: []string{"203.0.113.0/24"}}
+	err := s.VerificarOrigem(c, "192.0.2.1")
+	var rej *IntegracaoRejeicaoError
+	if !errors.As(err, &rej) || rej.ClienteID != 9 || rej.Motivo != models.IntegracaoRejeicaoIPNaoPermitido {
+		t.Fatalf("expected rejeição IP_NAO_PERMITIDO do cliente 9, got %v", err)
+	}
+	if !errors.Is(err, ErrIntegracaoIPNaoPermitido) {
+		t.Fatal("expected errors.Is ErrIntegracaoIPNaoPermitido")
+	}
+	if err := s.VerificarOrigem(c, "203.0.113.10"); err != nil {
+		t.Fatalf("expected origem permitida, got %v", err)
+	}
+}
+
+func TestMensagemAvisoExpiracaoChave(t *testing.T) {
+	now := time.Date(2026, 10, 18, 6, 0, 0, 0, time.UTC)
+	expira := now.Add(36 * time.Hour)
+	c := &models.IntegracaoCliente{Nome: "Laboratório X", ChaveExpiraEm: &expira}
+	titulo, corpo := MensagemAvisoExpiracaoChave(c, now)
+	if titulo != "Chave de integração expira em 2 dias" {
+		t.Fatalf("titulo = %q", titulo)
+	}
+	if !strings.HasPrefix(corpo, "Laboratório X — ") {
+		t.Fatalf("corpo = %q", corpo)
+	}
+	expira = now.Add(-time.Minute)
+	if titulo, _ := MensagemAvisoExpiracaoChave(c, now); titulo != "Chave de integração expirada" {
+		t.Fatalf("titulo expirada = %q", titulo)
+	}
+}
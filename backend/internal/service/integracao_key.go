@@ -49,3 +49,87 @@ func ExtractKeyPrefix(fullKey string) (string, error) {
 	}
 	return apiKeyPrefix + secret[:8], nil
 }
+
+const (
+	oauthClientIDPrefix = "cmk_client_"
+	oauthSecretPrefix   = "cmk_secret_"
+)
+
+// GenerateOAuthClientCredentials gera client_id público, client_secret e hash bcrypt do segredo.
+func GenerateOAuthClientCredentials() (clientID, clientSecret, secretHash string, err error) {
+	idBytes := make([]byte, 12)
+	if _, err = rand.Read(idBytes); err != nil {
+		return "", "", "", err
+	}
+	secretBytes := make([]byte, 24) // prefixo + 48 hex < 72 bytes (limite do bcrypt)
+	if _, err = rand.Read(secretBytes); err != nil {
+		return "", "", "", err
+	}
+	clientID = oauthClientIDPrefix + hex.EncodeToString(idBytes)
+	clientSecret = oauthSecretPrefix + hex.EncodeToString(secretBytes)
+	hash, err := bcrypt.GenerateFromPassword([]byte(clientSecret), bcrypt.DefaultCost)
+	if err != nil {
+		return "", "", "", err
+	}
+	return clientID, clientSecret, string(hash), nil
+}
+
+// ParseOAuthScope converte o parâmetro `scope` (separado por espaços, RFC 6749 §3.3) em lista.
+func ParseOAuthScope(scope string) []string {
+	return strings.Fields(scope)
+}
+
+// RestringirScopesOAuth devolve os scopes concedidos ao token: todos os do cliente quando
+// nada é pedido, ou o subconjunto pedido. Scope fora da lista do cliente → ErrIntegracaoScopeInvalido.
+func RestringirScopesOAuth(clienteScopes, pedidos []string) ([]string, error) {
+	if len(pedidos) == 0 {
+		return clienteScopes, nil
+	}
+	permitidos := make(map[string]bool, len(clienteScopes))
+	for _, s := range clienteScopes {
+		permitidos[s] = true
+	}
+	out := make([]string, 0, len(pedidos))
+	vistos := make(map[string]bool, len(pedidos))
+	for _, s := range pedidos {
+		if !permitidos[s] {
+			return nil, ErrIntegracaoScopeInvalido
+		}
+		if !vistos[s] {
+			vistos[s] = true
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+// IntersectScopes mantém apenas os scopes do token ainda atribuídos ao cliente
+// (alterações admin têm efeito imediato sobre tokens já emitidos).
+func IntersectScopes(token, atuais []string) []string {
+	atuaisSet := make(map[string]bool, len(atuais))
+	for _, s := range atuais {
+		atuaisSet[s] = true
+	}
+	out := make([]string, 0, len(token))
+	for _, s := range token {
+		if atuaisSet[s] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// IntersectFazendaIDs mantém apenas as fazendas do token ainda vinculadas ao cliente.
+func IntersectFazendaIDs(token, atuais []int64) []int64 {
+	atuaisSet := make(map[int64]bool, len(atuais))
+	for _, id := range atuais {
+		atuaisSet[id] = true
+	}
+	out := make([]int64, 0, len(token))
+	for _, id := range token {
+		if atuaisSet[id] {
+			out = append(out, id)
+		}
+	}
+	return out
+}
@@ -1,6 +1,12 @@
 package service
 
-import "testing"
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
 
 func TestGenerateAPIKeyAndValidate(t *testing.T) {
 	full, prefix, hash, err := GenerateAPIKey()
@@ -30,3 +36,64 @@ func TestValidateAPIKeyFormatRejectsJWT(t *testing.T) {
 		t.Fatal("jwt should not match integracao key format")
 	}
 }
+
+func TestGenerateOAuthClientCredentials(t *testing.T) {
+	id, secret, hash, err := GenerateOAuthClientCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(id, oauthClientIDPrefix) || !strings.HasPrefix(secret, oauthSecretPrefix) {
+		t.Fatalf("unexpected prefixes: %s %s", id, secret)
+	}
+	if ValidateAPIKeyFormat(secret) {
+		t.Fatal("client_secret must not be accepted as API key")
+	}
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(secret)) != nil {
+		t.Fatal("bcrypt compare failed")
+	}
+}
+
+func TestRestringirScopesOAuth(t *testing.T) {
+	cliente := []string{"animais:read", "toques:write"}
+	got, err := RestringirScopesOAuth(cliente, nil)
+	if err != nil || len(got) != 2 {
+		t.Fatalf("empty request should grant all: %v %v", got, err)
+	}
+	got, err = RestringirScopesOAuth(cliente, ParseOAuthScope("toques:write toques:write"))
+	if err != nil || len(got) != 1 || got[0] != "toques:write" {
+		t.Fatalf("unexpected subset: %v %v", got, err)
+	}
+	if _, err := RestringirScopesOAuth(cliente, []string{"saude:write"}); err != ErrIntegracaoScopeInvalido {
+		t.Fatalf("expected ErrIntegracaoScopeInvalido, got %v", err)
+	}
+}
+
+func TestTokenEmitidoAntesDaRevogacao(t *testing.T) {
+	revogado := time.Date(2026, 5, 1, 12, 0, 0, 500_000_000, time.UTC)
+	if TokenEmitidoAntesDaRevogacao(revogado.Add(-time.Hour), nil) {
+		t.Fatal("sem revogação nenhum token é rejeitado")
+	}
+	if !TokenEmitidoAntesDaRevogacao(revogado.Add(-2*time.Second), &revogado) {
+		t.Fatal("token anterior à revogação deve ser rejeitado")
+	}
+	// Mesmo segundo: o que foi emitido antes da revogação cai, o emitido depois (nova credencial) vale.
+	if !TokenEmitidoAntesDaRevogacao(revogado.Add(-100*time.Millisecond), &revogado) {
+		t.Fatal("token emitido no mesmo segundo, antes da revogação, deve ser rejeitado")
+	}
+	if TokenEmitidoAntesDaRevogacao(revogado.Add(100*time.Millisecond), &revogado) {
+		t.Fatal("token emitido no mesmo segundo, depois da revogação, deve ser aceito")
+	}
+	// Token sem iat_us (só segundos) emitido no segundo da revogação: rejeitado por segurança.
+	if !TokenEmitidoAntesDaRevogacao(revogado.Truncate(time.Second), &revogado) {
+		t.Fatal("token só com iat do mesmo segundo deve ser rejeitado")
+	}
+}
+
+func TestIntersectScopesEFazendas(t *testing.T) {
+	if got := IntersectScopes([]string{"a", "b"}, []string{"b", "c"}); len(got) != 1 || got[0] != "b" {
+		t.Fatalf("unexpected scopes: %v", got)
+	}
+	if got := IntersectFazendaIDs([]int64{1, 2}, []int64{2}); len(got) != 1 || got[0] != 2 {
+		t.Fatalf("unexpected fazendas: %v", got)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/ceialmilk/api/internal/models"
@@ -19,17 +20,63 @@ var (
 	ErrIntegracaoClienteNotFound = errors.New("cliente de integracao nao encontrado")
 	ErrIntegracaoScopeInvalido   = errors.New("scope de integracao invalido")
 	ErrIntegracaoIdempotencyConflict = errors.New("idempotency key ja usada com payload diferente")
+	ErrIntegracaoCredenciaisInvalidas = errors.New("client_id ou client_secret invalidos")
+	ErrIntegracaoClienteInativo       = errors.New("cliente de integracao inativo ou revogado")
+	ErrIntegracaoTokenRevogado        = errors.New("access token de integracao revogado")
 )
 
 const idempotencyTTL = 72 * time.Hour
 
+const (
+	periodoGracaPadraoHoras = 24
+	avisoExpiracaoPadraoDias = 14
+)
+
 type IntegracaoService struct {
 	repo        *repository.IntegracaoRepository
 	usuarioRepo *repository.UsuarioRepository
+	pushSvc     *PushNotificationService
+	// Política de chaves (BR-INTEG-016): validade 0 = chaves sem expiração automática.
+	validadeChaveDias  int
+	periodoGracaHoras  int
+	avisoExpiracaoDias int
 }
 
 func NewIntegracaoService(repo *repository.IntegracaoRepository, usuarioRepo *repository.UsuarioRepository) *IntegracaoService {
-	return &IntegracaoService{repo: repo, usuarioRepo: usuarioRepo}
+	return &IntegracaoService{
+		repo:               repo,
+		usuarioRepo:        usuarioRepo,
+		periodoGracaHoras:  periodoGracaPadraoHoras,
+		avisoExpiracaoDias: avisoExpiracaoPadraoDias,
+	}
+}
+
+// SetPoliticaChaves configura validade padrão de novas chaves, período de graça padrão da rotação
+// e antecedência do aviso de expiração aos admins.
+func (s *IntegracaoService) SetPoliticaChaves(validadeDias, periodoGracaHoras, avisoDias int) {
+	if validadeDias >= 0 {
+		s.validadeChaveDias = validadeDias
+	}
+	if periodoGracaHoras >= 0 && periodoGracaHoras <= maxPeriodoGracaHoras {
+		s.periodoGracaHoras = periodoGracaHoras
+	}
+	if avisoDias > 0 {
+		s.avisoExpiracaoDias = avisoDias
+	}
+}
+
+// SetPushNotificationService habilita o aviso por Web Push aos admins antes da expiração das chaves.
+func (s *IntegracaoService) SetPushNotificationService(pushSvc *PushNotificationService) {
+	s.pushSvc = pushSvc
+}
+
+// expiracaoPadrao aplica INTEGRATION_KEY_VALIDADE_DIAS a uma chave emitida agora.
+func (s *IntegracaoService) expiracaoPadrao(now time.Time) *time.Time {
+	if s.validadeChaveDias <= 0 {
+		return nil
+	}
+	t := now.UTC().AddDate(0, 0, s.validadeChaveDias)
+	return &t
 }
 
 func (s *IntegracaoService) validateScopes(scopes []string) error {
@@ -44,10 +91,21 @@ func (s *IntegracaoService) validateScopes(scopes []string) error {
 	return nil
 }
 
-func (s *IntegracaoService) CreateCliente(ctx context.Context, nome string, fazendaIDs []int64, scopesStr []string, adminID int64) (*models.IntegracaoCliente, string, error) {
+func (s *IntegracaoService) CreateCliente(ctx context.Context, nome string, fazendaIDs []int64, scopesStr []string, adminID int64, chaveExpiraEm *time.Time, ipAllowlist []string) (*models.IntegracaoCliente, string, error) {
 	if err := s.validateScopes(scopesStr); err != nil {
 		return nil, "", err
 	}
+	allowlist, err := NormalizarIPAllowlist(ipAllowlist)
+	if err != nil {
+		return nil, "", err
+	}
+	now := time.Now()
+	if chaveExpiraEm != nil && !chaveExpiraEm.After(now) {
+		return nil, "", ErrIntegracaoExpiracaoNoPassado
+	}
+	if chaveExpiraEm == nil {
+		chaveExpiraEm = s.expiracaoPadrao(now)
+	}
 	fullKey, keyPrefix, keyHash, err := GenerateAPIKey()
 	if err != nil {
 		return nil, "", err
@@ -71,6 +129,8 @@ func (s *IntegracaoService) CreateCliente(ctx context.Context, nome string, faze
 		KeyHash:          keyHash,
 		Ativo:            true,
 		CriadoPorAdminID: &adminID,
+		ChaveExpiraEm:    chaveExpiraEm,
+		IPAllowlist:      allowlist,
 	}
 	if err := s.repo.CreateCliente(ctx, c); err != nil {
 		return nil, "", err
@@ -146,18 +206,69 @@ func (s *IntegracaoService) UpdateCliente(ctx context.Context, id int64, nome st
 	return nil
 }
 
-func (s *IntegracaoService) RotacionarChave(ctx context.Context, id int64) (string, error) {
+// RotacionarChave emite nova chave. Com período de graça > 0 a chave atual continua válida (como anterior)
+// durante esse número de horas; periodoGracaHoras nil usa o padrão configurado.
+func (s *IntegracaoService) RotacionarChave(ctx context.Context, id int64, periodoGracaHoras *int) (string, *models.IntegracaoCliente, error) {
 	if _, err := s.GetByID(ctx, id); err != nil {
-		return "", err
+		return "", nil, err
+	}
+	horas := s.periodoGracaHoras
+	if periodoGracaHoras != nil {
+		horas = *periodoGracaHoras
+	}
+	if horas < 0 || horas > maxPeriodoGracaHoras {
+		return "", nil, ErrIntegracaoPeriodoGracaInvalido
 	}
 	fullKey, keyPrefix, keyHash, err := GenerateAPIKey()
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
-	if err := s.repo.UpdateKey(ctx, id, keyPrefix, keyHash); err != nil {
-		return "", err
+	now := time.Now().UTC()
+	var anteriorExpiraEm *time.Time
+	if horas > 0 {
+		t := now.Add(time.Duration(horas) * time.Hour)
+		anteriorExpiraEm = &t
 	}
-	return fullKey, nil
+	if err := s.repo.UpdateKey(ctx, id, keyPrefix, keyHash, s.expiracaoPadrao(now), anteriorExpiraEm); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil, ErrIntegracaoClienteNotFound
+		}
+		return "", nil, err
+	}
+	c, err := s.GetByID(ctx, id)
+	if err != nil {
+		return "", nil, err
+	}
+	return fullKey, c, nil
+}
+
+// SetChaveExpiracao agenda a expiração da chave atual; nil remove a expiração.
+func (s *IntegracaoService) SetChaveExpiracao(ctx context.Context, id int64, expiraEm *time.Time) error {
+	if expiraEm != nil && !expiraEm.After(time.Now()) {
+		return ErrIntegracaoExpiracaoNoPassado
+	}
+	if err := s.repo.SetChaveExpiracao(ctx, id, expiraEm); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrIntegracaoClienteNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// SetIPAllowlist substitui os CIDRs de origem permitidos (lista vazia remove a restrição).
+func (s *IntegracaoService) SetIPAllowlist(ctx context.Context, id int64, entradas []string) error {
+	allowlist, err := NormalizarIPAllowlist(entradas)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.SetIPAllowlist(ctx, id, allowlist); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrIntegracaoClienteNotFound
+		}
+		return err
+	}
+	return nil
 }
 
 func (s *IntegracaoService) Revogar(ctx context.Context, id int64) error {
@@ -172,7 +283,7 @@ func (s *IntegracaoService) Reativar(ctx context.Context, id int64) (string, err
 	if err != nil {
 		return "", err
 	}
-	if err := s.repo.Reativar(ctx, id, keyPrefix, keyHash); err != nil {
+	if err := s.repo.Reativar(ctx, id, keyPrefix, keyHash, s.expiracaoPadrao(time.Now())); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return "", ErrIntegracaoClienteNotFound
 		}
@@ -181,23 +292,95 @@ func (s *IntegracaoService) Reativar(ctx context.Context, id int64) (string, err
 	return fullKey, nil
 }
 
+// ResolveClienteByAPIKey autentica a API key (atual ou anterior em período de graça).
+// Rejeições devolvem *IntegracaoRejeicaoError com o motivo para a auditoria.
 func (s *IntegracaoService) ResolveClienteByAPIKey(ctx context.Context, fullKey string) (*models.IntegracaoCliente, error) {
 	prefix, err := ExtractKeyPrefix(fullKey)
 	if err != nil {
-		return nil, err
+		return nil, rejeicao(0, models.IntegracaoRejeicaoFormatoInvalido, err)
 	}
 	c, err := s.repo.GetByKeyPrefix(ctx, prefix)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, ErrIntegracaoClienteNotFound
+			return nil, rejeicao(0, models.IntegracaoRejeicaoChaveDesconhecida, ErrIntegracaoClienteNotFound)
+		}
+		return nil, err
+	}
+	switch motivo := AvaliarChaveAPI(c, fullKey, time.Now()); motivo {
+	case "":
+	case models.IntegracaoRejeicaoChaveInvalida:
+		return nil, rejeicao(c.ID, motivo, ErrIntegracaoClienteNotFound)
+	case models.IntegracaoRejeicaoClienteInativo:
+		return nil, rejeicao(c.ID, motivo, ErrIntegracaoClienteInativo)
+	default:
+		return nil, rejeicao(c.ID, motivo, ErrIntegracaoChaveExpirada)
+	}
+	if err := s.repo.LoadClienteRelations(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// VerificarOrigem aplica a allowlist de CIDRs do cliente ao IP de origem do pedido.
+func (s *IntegracaoService) VerificarOrigem(c *models.IntegracaoCliente, ip string) error {
+	if IPPermitido(c.IPAllowlist, ip) {
+		return nil
+	}
+	return rejeicao(c.ID, models.IntegracaoRejeicaoIPNaoPermitido, ErrIntegracaoIPNaoPermitido)
+}
+
+// GerarCredenciaisOAuth emite (ou substitui) client_id/client_secret OAuth2 do cliente.
+// O segredo em claro só é devolvido aqui; tokens emitidos com credenciais anteriores são invalidados.
+func (s *IntegracaoService) GerarCredenciaisOAuth(ctx context.Context, id int64) (clientID, clientSecret string, err error) {
+	c, err := s.GetByID(ctx, id)
+	if err != nil {
+		return "", "", err
+	}
+	newID, secret, secretHash, err := GenerateOAuthClientCredentials()
+	if err != nil {
+		return "", "", err
+	}
+	// client_id é estável entre rotações do segredo (configuração do parceiro não muda).
+	if c.OAuthClientID != nil && *c.OAuthClientID != "" {
+		newID = *c.OAuthClientID
+	}
+	if err := s.repo.SetOAuthCredentials(ctx, id, newID, secretHash); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", "", ErrIntegracaoClienteNotFound
+		}
+		return "", "", err
+	}
+	return newID, secret, nil
+}
+
+// RevogarTokensOAuth invalida todos os access tokens já emitidos para o cliente (o cliente continua ativo).
+func (s *IntegracaoService) RevogarTokensOAuth(ctx context.Context, id int64) error {
+	if err := s.repo.RevogarTokens(ctx, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrIntegracaoClienteNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// AutenticarClientCredentials valida client_id/client_secret (grant client_credentials).
+func (s *IntegracaoService) AutenticarClientCredentials(ctx context.Context, clientID, clientSecret string) (*models.IntegracaoCliente, error) {
+	if clientID == "" || clientSecret == "" {
+		return nil, ErrIntegracaoCredenciaisInvalidas
+	}
+	c, err := s.repo.GetByOAuthClientID(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, rejeicao(0, models.IntegracaoRejeicaoCredenciaisInvalidas, ErrIntegracaoCredenciaisInvalidas)
 		}
 		return nil, err
 	}
-	if !CompareAPIKey(fullKey, c.KeyHash) {
-		return nil, ErrIntegracaoClienteNotFound
+	if c.OAuthSecretHash == "" || bcrypt.CompareHashAndPassword([]byte(c.OAuthSecretHash), []byte(clientSecret)) != nil {
+		return nil, rejeicao(c.ID, models.IntegracaoRejeicaoCredenciaisInvalidas, ErrIntegracaoCredenciaisInvalidas)
 	}
 	if !c.Ativo || c.RevogadoEm != nil {
-		return nil, errors.New("cliente de integracao inativo ou revogado")
+		return nil, rejeicao(c.ID, models.IntegracaoRejeicaoClienteInativo, ErrIntegracaoClienteInativo)
 	}
 	if err := s.repo.LoadClienteRelations(ctx, c); err != nil {
 		return nil, err
@@ -205,10 +388,104 @@ func (s *IntegracaoService) ResolveClienteByAPIKey(ctx context.Context, fullKey
 	return c, nil
 }
 
+// ResolveClienteByAccessToken carrega o cliente de um access token OAuth já validado (assinatura/expiração)
+// e aplica a revogação: cliente inativo ou token emitido antes de tokens_validos_desde → erro.
+func (s *IntegracaoService) ResolveClienteByAccessToken(ctx context.Context, clienteID int64, emitidoEm time.Time) (*models.IntegracaoCliente, error) {
+	c, err := s.repo.GetByID(ctx, clienteID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, rejeicao(0, models.IntegracaoRejeicaoTokenInvalido, ErrIntegracaoClienteNotFound)
+		}
+		return nil, err
+	}
+	if !c.Ativo || c.RevogadoEm != nil {
+		return nil, rejeicao(c.ID, models.IntegracaoRejeicaoClienteInativo, ErrIntegracaoClienteInativo)
+	}
+	if TokenEmitidoAntesDaRevogacao(emitidoEm, c.TokensValidosDesde) {
+		return nil, rejeicao(c.ID, models.IntegracaoRejeicaoTokenRevogado, ErrIntegracaoTokenRevogado)
+	}
+	if err := s.repo.LoadClienteRelations(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// TokenEmitidoAntesDaRevogacao compara em microssegundos (precisão de tokens_validos_desde e do claim
+// iat_us). Empate conta como revogado; tokens antigos, só com iat em segundos, emitidos no segundo da
+// revogação ficam antes dela e também são rejeitados.
+func TokenEmitidoAntesDaRevogacao(emitidoEm time.Time, validosDesde *time.Time) bool {
+	if validosDesde == nil {
+		return false
+	}
+	return !emitidoEm.Truncate(time.Microsecond).After(validosDesde.Truncate(time.Microsecond))
+}
+
 func (s *IntegracaoService) ListChamadas(ctx context.Context, clienteID int64, limit, offset int) ([]*models.IntegracaoChamada, error) {
 	return s.repo.ListChamadas(ctx, clienteID, limit, offset)
 }
 
+// ListRejeicoes lista chamadas rejeitadas; motivo vazio = todos os motivos.
+func (s *IntegracaoService) ListRejeicoes(ctx context.Context, motivo string, limit, offset int) ([]*models.IntegracaoChamada, error) {
+	return s.repo.ListRejeicoes(ctx, motivo, limit, offset)
+}
+
+// AvisarChavesExpirando avisa os admins (log + Web Push) das chaves que expiram dentro da antecedência
+// configurada. Cada data de expiração é avisada uma única vez; devolve o número de clientes avisados.
+func (s *IntegracaoService) AvisarChavesExpirando(ctx context.Context, now time.Time) (int, error) {
+	ate := now.AddDate(0, 0, s.avisoExpiracaoDias)
+	clientes, err := s.repo.ListChavesExpirandoSemAviso(ctx, ate)
+	if err != nil {
+		return 0, err
+	}
+	if len(clientes) == 0 {
+		return 0, nil
+	}
+	var adminIDs []int64
+	if s.pushSvc != nil {
+		adminIDs, err = s.usuarioRepo.ListIDsAtivosByPerfis(ctx, []string{models.PerfilAdmin, models.PerfilDeveloper})
+		if err != nil {
+			slog.Warn("integracoes: listar admins para aviso de expiracao", "error", err)
+		}
+	}
+	avisados := 0
+	for _, c := range clientes {
+		titulo, corpo := MensagemAvisoExpiracaoChave(c, now)
+		slog.Warn("integracoes: chave proxima da expiracao",
+			"cliente_id", c.ID,
+			"cliente", c.Nome,
+			"chave_expira_em", c.ChaveExpiraEm.Format(time.RFC3339),
+		)
+		if s.pushSvc != nil {
+			s.pushSvc.NotifyUsuarios(adminIDs, titulo, corpo, fmt.Sprintf("/admin/integracoes/%d", c.ID))
+		}
+		if err := s.repo.MarcarAvisoExpiracao(ctx, c.ID, *c.ChaveExpiraEm); err != nil {
+			return avisados, err
+		}
+		avisados++
+	}
+	return avisados, nil
+}
+
+// MensagemAvisoExpiracaoChave monta título e corpo do aviso de expiração da chave.
+func MensagemAvisoExpiracaoChave(c *models.IntegracaoCliente, now time.Time) (titulo, corpo string) {
+	if c.ChaveExpiraEm == nil {
+		return "", ""
+	}
+	restante := c.ChaveExpiraEm.Sub(now)
+	if restante <= 0 {
+		titulo = "Chave de integração expirada"
+	} else {
+		dias := int((restante + 24*time.Hour - 1) / (24 * time.Hour))
+		if dias == 1 {
+			titulo = "Chave de integração expira em 1 dia"
+		} else {
+			titulo = fmt.Sprintf("Chave de integração expira em %d dias", dias)
+		}
+	}
+	corpo = fmt.Sprintf("%s — rotacione a chave antes de %s", c.Nome, c.ChaveExpiraEm.UTC().Format("02/01/2006 15:04 UTC"))
+	return titulo, corpo
+}
+
 func (s *IntegracaoService) LogChamada(ctx context.Context, ch *models.IntegracaoChamada) error {
 	return s.repo.InsertChamada(ctx, ch)
 }
@@ -0,0 +1,436 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/repository"
+)
+
+var (
+	ErrJobNaoEncontrado = errors.New("job não encontrado")
+	ErrJobEmExecucao    = errors.New("job já em execução")
+)
+
+// jobTimeoutPadrao tempo máximo de uma execução quando o job não define Timeout.
+const jobTimeoutPadrao = 10 * time.Minute
+
+// jobIntervaloSinal intervalo entre sinais de vida (ultimo_sinal_em) de uma execução em curso.
+const jobIntervaloSinal = 30 * time.Second
+
+// jobPrazoSinal sem sinal de vida há mais que isto, a execução conta como abandonada (réplica que caiu).
+// Vale o sinal e não o tempo limite: um job que ignora o cancelamento continua vivo e a segurar o job.
+const jobPrazoSinal = 3 * time.Minute
+
+// jobMaxSlotsRecuperados teto de slots perdidos recuperados por job após uma paragem longa; os mais
+// antigos que isso são ignorados.
+const jobMaxSlotsRecuperados = 7
+
+// jobToleranciaAtraso até este atraso sobre o slot a execução conta como AGENDADA; depois, RECUPERADA.
+const jobToleranciaAtraso = 2 * time.Minute
+
+// JobAgenda define os instantes (slots) em que um job deve correr.
+type JobAgenda interface {
+	// Anterior devolve o slot mais recente <= t.
+	Anterior(t time.Time) time.Time
+	// Proximo devolve o primeiro slot > t.
+	Proximo(t time.Time) time.Time
+	Descricao() string
+}
+
+// AgendaDiaria um slot por dia, à hora cheia indicada no fuso Loc.
+type AgendaDiaria struct {
+	Hora int
+	Loc  *time.Location
+}
+
+func (a AgendaDiaria) Anterior(t time.Time) time.Time {
+	l := t.In(a.Loc)
+	slot := time.Date(l.Year(), l.Month(), l.Day(), a.Hora, 0, 0, 0, a.Loc)
+	if slot.After(t) {
+		slot = time.Date(l.Year(), l.Month(), l.Day()-1, a.Hora, 0, 0, 0, a.Loc)
+	}
+	return slot
+}
+
+func (a AgendaDiaria) Proximo(t time.Time) time.Time {
+	l := t.In(a.Loc)
+	slot := time.Date(l.Year(), l.Month(), l.Day(), a.Hora, 0, 0, 0, a.Loc)
+	if !slot.After(t) {
+		slot = time.Date(l.Year(), l.Month(), l.Day()+1, a.Hora, 0, 0, 0, a.Loc)
+	}
+	return slot
+}
+
+func (a AgendaDiaria) Descricao() string {
+	return fmt.Sprintf("diariamente às %02d:00 (%s)", a.Hora, a.Loc)
+}
+
+// AgendaIntervalo slots a cada Intervalo, alinhados à hora cheia (ex.: 15 min → :00, :15, :30, :45).
+type AgendaIntervalo struct {
+	Intervalo time.Duration
+}
+
+func (a AgendaIntervalo) Anterior(t time.Time) time.Time {
+	return t.Truncate(a.Intervalo)
+}
+
+func (a AgendaIntervalo) Proximo(t time.Time) time.Time {
+	return t.Truncate(a.Intervalo).Add(a.Intervalo)
+}
+
+func (a AgendaIntervalo) Descricao() string {
+	return "a cada " + a.Intervalo.String()
+}
+
+// Job tarefa registada no agendador. Executar recebe a referência da execução (slot agendado ou
+// instante do disparo manual) e devolve contagens que ficam no histórico.
+type Job struct {
+	Nome      string
+	Descricao string
+	// Agenda nil = apenas disparo manual (ex.: cron desligado por configuração).
+	Agenda   JobAgenda
+	Timeout  time.Duration
+	Executar func(ctx context.Context, ref time.Time) (interface{}, error)
+}
+
+// JobInfo estado de um job para o painel de administração.
+type JobInfo struct {
+	Nome            string              `json:"nome"`
+	Descricao       string              `json:"descricao"`
+	Agenda          string              `json:"agenda,omitempty"`
+	Agendado        bool                `json:"agendado"`
+	ProximaExecucao *time.Time          `json:"proxima_execucao,omitempty"`
+	UltimaExecucao  *models.JobExecucao `json:"ultima_execucao,omitempty"`
+}
+
+type jobExecucaoStore interface {
+	Iniciar(ctx context.Context, row *models.JobExecucao) (bool, error)
+	Finalizar(ctx context.Context, id int64, status string, resultado []byte, erro *string) error
+	Sinalizar(ctx context.Context, id int64) error
+	MarcarAbandonadas(ctx context.Context, job string, semSinalDesde time.Time) (int64, error)
+	SlotReivindicado(ctx context.Context, job string, agendadoPara time.Time) (bool, error)
+	UltimoSlot(ctx context.Context, job string) (*time.Time, error)
+	List(ctx context.Context, f models.JobExecucaoFiltro) ([]*models.JobExecucao, error)
+	GetByID(ctx context.Context, id int64) (*models.JobExecucao, error)
+	UltimasPorJob(ctx context.Context) (map[string]*models.JobExecucao, error)
+	PurgarAntigas(ctx context.Context, antesDe time.Time) (int64, error)
+}
+
+// JobScheduler corre as tarefas periódicas (BR-JOBS-001). Todas as réplicas da API verificam a agenda,
+// mas cada slot é reivindicado numa linha de jobs_execucoes (índice único por job e slot): só a réplica
+// que a insere executa. Slots perdidos (réplicas paradas na hora) são executados em sequência, do mais
+// antigo para o mais recente, nas verificações seguintes.
+type JobScheduler struct {
+	store     jobExecucaoStore
+	instancia string
+	agora     func() time.Time
+
+	mu sync.Mutex
+	// ctx de Run: encerra as execuções em curso no shutdown.
+	ctx  context.Context
+	jobs map[string]*Job
+	// slots já reivindicados (por esta ou outra réplica), para não voltar a consultar a base a cada minuto.
+	slots map[string]time.Time
+	wg    sync.WaitGroup
+}
+
+func NewJobScheduler(store *repository.JobExecucaoRepository) *JobScheduler {
+	host, _ := os.Hostname()
+	if host == "" {
+		host = "api"
+	}
+	return &JobScheduler{
+		store:     store,
+		instancia: fmt.Sprintf("%s:%d", host, os.Getpid()),
+		agora:     time.Now,
+		jobs:      map[string]*Job{},
+		slots:     map[string]time.Time{},
+	}
+}
+
+// Registrar adiciona o job ao agendador; chamar antes de Run.
+func (s *JobScheduler) Registrar(job Job) {
+	if job.Timeout <= 0 {
+		job.Timeout = jobTimeoutPadrao
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[job.Nome] = &job
+}
+
+// Run verifica a agenda ao arrancar (recuperando slots perdidos) e depois a cada minuto cheio, até ctx terminar.
+func (s *JobScheduler) Run(ctx context.Context) {
+	s.mu.Lock()
+	s.ctx = ctx
+	s.mu.Unlock()
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				slog.Error("jobs: panic recuperado", "panic", r)
+			}
+		}()
+		slog.Info("jobs: agendador iniciado", "instancia", s.instancia, "jobs", len(s.jobs))
+		for {
+			s.verificar(ctx)
+			now := s.agora()
+			select {
+			case <-ctx.Done():
+				slog.Info("jobs: agendador encerrado")
+				return
+			case <-time.After(now.Truncate(time.Minute).Add(time.Minute).Sub(now)):
+			}
+		}
+	}()
+}
+
+func (s *JobScheduler) verificar(ctx context.Context) {
+	now := s.agora()
+	for _, job := range s.listar() {
+		if job.Agenda == nil || ctx.Err() != nil {
+			continue
+		}
+		atual := job.Agenda.Anterior(now)
+		s.mu.Lock()
+		visto := s.slots[job.Nome].Equal(atual)
+		s.mu.Unlock()
+		if visto {
+			continue
+		}
+		slot, err := s.slotPendente(ctx, job, atual)
+		if err != nil {
+			slog.Error("jobs: consultar último slot", "job", job.Nome, "error", err)
+			continue
+		}
+		if slot.IsZero() {
+			// Todos os slots até o atual já foram reivindicados.
+			s.memorizarSlot(job.Nome, atual)
+			continue
+		}
+		origem := models.JobOrigemAgendada
+		if now.Sub(slot) > jobToleranciaAtraso {
+			origem = models.JobOrigemRecuperada
+		}
+		row := &models.JobExecucao{Job: job.Nome, AgendadoPara: &slot, Origem: origem, Instancia: s.instancia}
+		ok, err := s.reivindicar(ctx, job, row, now)
+		if err != nil {
+			slog.Error("jobs: reivindicar slot", "job", job.Nome, "slot", slot.Format(time.RFC3339), "error", err)
+			continue
+		}
+		if !ok {
+			// Slot de outra réplica, ou job ainda ocupado por outra execução: nesse caso tenta de novo no minuto seguinte.
+			reivindicado, err := s.store.SlotReivindicado(ctx, job.Nome, slot)
+			if err != nil || !reivindicado {
+				continue
+			}
+		}
+		// Slot antigo: os seguintes ficam para as próximas verificações (uma execução em curso por job).
+		if slot.Equal(atual) {
+			s.memorizarSlot(job.Nome, atual)
+		}
+		if ok {
+			s.executar(job, row, slot)
+		}
+	}
+}
+
+// slotPendente devolve o slot mais antigo ainda por reivindicar entre o último registado em jobs_execucoes
+// e atual (zero se não houver). Só os jobMaxSlotsRecuperados mais recentes são considerados; job sem
+// histórico começa no slot atual.
+func (s *JobScheduler) slotPendente(ctx context.Context, job *Job, atual time.Time) (time.Time, error) {
+	ultimo, err := s.store.UltimoSlot(ctx, job.Nome)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if ultimo == nil {
+		return atual, nil
+	}
+	if !ultimo.Before(atual) {
+		return time.Time{}, nil
+	}
+	slot := atual
+	for i := 1; i < jobMaxSlotsRecuperados; i++ {
+		anterior := job.Agenda.Anterior(slot.Add(-time.Nanosecond))
+		if !anterior.After(*ultimo) {
+			return slot, nil
+		}
+		slot = anterior
+	}
+	if job.Agenda.Anterior(slot.Add(-time.Nanosecond)).After(*ultimo) {
+		slog.Warn("jobs: slots perdidos além do limite de recuperação ignorados", "job", job.Nome,
+			"ultimo_registado", ultimo.Format(time.RFC3339), "recupera_desde", slot.Format(time.RFC3339))
+	}
+	return slot, nil
+}
+
+func (s *JobScheduler) memorizarSlot(job string, slot time.Time) {
+	s.mu.Lock()
+	s.slots[job] = slot
+	s.mu.Unlock()
+}
+
+// reivindicar liberta execuções abandonadas do job e tenta inserir a nova execução.
+func (s *JobScheduler) reivindicar(ctx context.Context, job *Job, row *models.JobExecucao, now time.Time) (bool, error) {
+	if n, err := s.store.MarcarAbandonadas(ctx, job.Nome, now.Add(-jobPrazoSinal)); err != nil {
+		return false, err
+	} else if n > 0 {
+		slog.Warn("jobs: execuções abandonadas encerradas", "job", job.Nome, "total", n)
+	}
+	return s.store.Iniciar(ctx, row)
+}
+
+// Disparar executa o job fora da agenda (admin). Devolve a execução já iniciada; o resultado fica no histórico.
+func (s *JobScheduler) Disparar(ctx context.Context, nome string, actorUserID int64) (*models.JobExecucao, error) {
+	s.mu.Lock()
+	job, ok := s.jobs[nome]
+	s.mu.Unlock()
+	if !ok {
+		return nil, ErrJobNaoEncontrado
+	}
+	now := s.agora()
+	row := &models.JobExecucao{Job: nome, Origem: models.JobOrigemManual, Instancia: s.instancia, DisparadoPor: &actorUserID}
+	iniciada, err := s.reivindicar(ctx, job, row, now)
+	if err != nil {
+		return nil, err
+	}
+	if !iniciada {
+		return nil, ErrJobEmExecucao
+	}
+	s.executar(job, row, now)
+	return row, nil
+}
+
+// executar corre o job em segundo plano e grava o desfecho; panics e timeouts ficam como ERRO.
+func (s *JobScheduler) executar(job *Job, row *models.JobExecucao, ref time.Time) {
+	s.mu.Lock()
+	base := s.ctx
+	s.mu.Unlock()
+	if base == nil {
+		base = context.Background()
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		inicio := time.Now()
+		parar := s.sinalizarEnquanto(job, row.ID)
+		res, err := s.executarProtegido(base, job, ref)
+		parar()
+
+		status := models.JobStatusSucesso
+		var erro *string
+		if err != nil {
+			status = models.JobStatusErro
+			msg := err.Error()
+			erro = &msg
+		}
+		var resultado []byte
+		if res != nil {
+			if b, mErr := json.Marshal(res); mErr == nil {
+				resultado = b
+			}
+		}
+		fctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if fErr := s.store.Finalizar(fctx, row.ID, status, resultado, erro); fErr != nil {
+			slog.Error("jobs: gravar desfecho", "job", job.Nome, "execucao_id", row.ID, "error", fErr)
+		}
+		if err != nil {
+			slog.Error("jobs: execução falhou", "job", job.Nome, "origem", row.Origem, "error", err, "duracao", time.Since(inicio).String())
+			return
+		}
+		slog.Info("jobs: execução concluída", "job", job.Nome, "origem", row.Origem, "resultado", string(resultado), "duracao", time.Since(inicio).String())
+	}()
+}
+
+// sinalizarEnquanto renova ultimo_sinal_em da execução até a função devolvida ser chamada; enquanto o
+// processo estiver vivo a execução não é dada como abandonada por outra réplica.
+func (s *JobScheduler) sinalizarEnquanto(job *Job, id int64) (parar func()) {
+	fim := make(chan struct{})
+	feito := make(chan struct{})
+	go func() {
+		defer close(feito)
+		ticker := time.NewTicker(jobIntervaloSinal)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-fim:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				if err := s.store.Sinalizar(ctx, id); err != nil {
+					slog.Warn("jobs: sinal de vida", "job", job.Nome, "execucao_id", id, "error", err)
+				}
+				cancel()
+			}
+		}
+	}()
+	return func() {
+		close(fim)
+		<-feito
+	}
+}
+
+func (s *JobScheduler) executarProtegido(base context.Context, job *Job, ref time.Time) (res interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	ctx, cancel := context.WithTimeout(base, job.Timeout)
+	defer cancel()
+	return job.Executar(ctx, ref)
+}
+
+func (s *JobScheduler) listar() []*Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]*Job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		out = append(out, j)
+	}
+	sort.Slice(out, func(i, k int) bool { return out[i].Nome < out[k].Nome })
+	return out
+}
+
+// ListJobs jobs registados com agenda, próxima execução e última execução conhecida (qualquer réplica).
+func (s *JobScheduler) ListJobs(ctx context.Context) ([]JobInfo, error) {
+	ultimas, err := s.store.UltimasPorJob(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := s.agora()
+	jobs := s.listar()
+	out := make([]JobInfo, 0, len(jobs))
+	for _, j := range jobs {
+		info := JobInfo{Nome: j.Nome, Descricao: j.Descricao, Agendado: j.Agenda != nil, UltimaExecucao: ultimas[j.Nome]}
+		if j.Agenda != nil {
+			proxima := j.Agenda.Proximo(now)
+			info.Agenda = j.Agenda.Descricao()
+			info.ProximaExecucao = &proxima
+		}
+		out = append(out, info)
+	}
+	return out, nil
+}
+
+func (s *JobScheduler) ListExecucoes(ctx context.Context, f models.JobExecucaoFiltro) ([]*models.JobExecucao, error) {
+	return s.store.List(ctx, f)
+}
+
+func (s *JobScheduler) purgarHistorico(ctx context.Context, antesDe time.Time) (int64, error) {
+	return s.store.PurgarAntigas(ctx, antesDe)
+}
+
+func (s *JobScheduler) GetExecucao(ctx context.Context, id int64) (*models.JobExecucao, error) {
+	return s.store.GetByID(ctx, id)
+}
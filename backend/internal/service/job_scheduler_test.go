@@ -0,0 +1,422 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+)
+
+// fakeJobStore reproduz os índices únicos de jobs_execucoes: um slot por job e uma execução em curso por job.
+type fakeJobStore struct {
+	mu   sync.Mutex
+	rows []*models.JobExecucao
+}
+
+func (f *fakeJobStore) Iniciar(_ context.Context, row *models.JobExecucao) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, r := range f.rows {
+		if r.Job != row.Job {
+			continue
+		}
+		if r.Status == models.JobStatusEmExecucao {
+			return false, nil
+		}
+		if row.AgendadoPara != nil && r.AgendadoPara != nil && r.AgendadoPara.Equal(*row.AgendadoPara) {
+			return false, nil
+		}
+	}
+	row.ID = int64(len(f.rows) + 1)
+	row.Status = models.JobStatusEmExecucao
+	row.IniciadoEm = time.Now()
+	cp := *row
+	f.rows = append(f.rows, &cp)
+	return true, nil
+}
+
+func (f *fakeJobStore) Finalizar(_ context.Context, id int64, status string, resultado []byte, erro *string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, r := range f.rows {
+		if r.ID == id {
+			r.Status, r.Resultado, r.Erro = status, resultado, erro
+			return nil
+		}
+	}
+	return errors.New("não encontrada")
+}
+
+func (f *fakeJobStore) Sinalizar(_ context.Context, id int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, r := range f.rows {
+		if r.ID == id {
+			agora := time.Now()
+			r.UltimoSinalEm = &agora
+		}
+	}
+	return nil
+}
+
+func (f *fakeJobStore) MarcarAbandonadas(_ context.Context, job string, antes time.Time) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var n int64
+	for _, r := range f.rows {
+		sinal := r.IniciadoEm
+		if r.UltimoSinalEm != nil {
+			sinal = *r.UltimoSinalEm
+		}
+		if r.Job == job && r.Status == models.JobStatusEmExecucao && sinal.Before(antes) {
+			r.Status = models.JobStatusErro
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (f *fakeJobStore) SlotReivindicado(_ context.Context, job string, slot time.Time) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, r := range f.rows {
+		if r.Job == job && r.AgendadoPara != nil && r.AgendadoPara.Equal(slot) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeJobStore) UltimoSlot(_ context.Context, job string) (*time.Time, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var ultimo *time.Time
+	for _, r := range f.rows {
+		if r.Job == job && r.AgendadoPara != nil && (ultimo == nil || r.AgendadoPara.After(*ultimo)) {
+			ultimo = r.AgendadoPara
+		}
+	}
+	return ultimo, nil
+}
+
+func (f *fakeJobStore) List(context.Context, models.JobExecucaoFiltro) ([]*models.JobExecucao, error) {
+	return f.rows, nil
+}
+
+func (f *fakeJobStore) GetByID(_ context.Context, id int64) (*models.JobExecucao, error) {
+	for _, r := range f.rows {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeJobStore) UltimasPorJob(context.Context) (map[string]*models.JobExecucao, error) {
+	out := map[string]*models.JobExecucao{}
+	for _, r := range f.rows {
+		out[r.Job] = r
+	}
+	return out, nil
+}
+
+func (f *fakeJobStore) PurgarAntigas(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func newJobSchedulerTeste(store *fakeJobStore, instancia string, agora time.Time) *JobScheduler {
+	return &JobScheduler{
+		store:     store,
+		instancia: instancia,
+		agora:     func() time.Time { return agora },
+		jobs:      map[string]*Job{},
+		slots:     map[string]time.Time{},
+	}
+}
+
+func TestAgendaDiaria_SlotsNoFuso(t *testing.T) {
+	loc := time.FixedZone("BRT", -3*3600)
+	a := AgendaDiaria{Hora: 6, Loc: loc}
+
+	antes := time.Date(2026, 3, 10, 5, 59, 0, 0, loc)
+	if got := a.Anterior(antes); !got.Equal(time.Date(2026, 3, 9, 6, 0, 0, 0, loc)) {
+		t.Fatalf("anterior às 05:59: %v", got)
+	}
+	if got := a.Proximo(antes); !got.Equal(time.Date(2026, 3, 10, 6, 0, 0, 0, loc)) {
+		t.Fatalf("próximo às 05:59: %v", got)
+	}
+	exato := time.Date(2026, 3, 10, 6, 0, 0, 0, loc)
+	if got := a.Anterior(exato); !got.Equal(exato) {
+		t.Fatalf("anterior no slot: %v", got)
+	}
+	if got := a.Proximo(exato); !got.Equal(time.Date(2026, 3, 11, 6, 0, 0, 0, loc)) {
+		t.Fatalf("próximo no slot: %v", got)
+	}
+
+	i := AgendaIntervalo{Intervalo: 15 * time.Minute}
+	ref := time.Date(2026, 3, 10, 9, 44, 59, 0, time.UTC)
+	if got := i.Anterior(ref); !got.Equal(time.Date(2026, 3, 10, 9, 30, 0, 0, time.UTC)) {
+		t.Fatalf("intervalo anterior: %v", got)
+	}
+	if got := i.Proximo(ref); !got.Equal(time.Date(2026, 3, 10, 9, 45, 0, 0, time.UTC)) {
+		t.Fatalf("intervalo próximo: %v", got)
+	}
+}
+
+func TestJobScheduler_SlotExecutaUmaVezEntreReplicas(t *testing.T) {
+	loc := time.UTC
+	agora := time.Date(2026, 3, 10, 6, 0, 30, 0, loc)
+	store := &fakeJobStore{}
+	var mu sync.Mutex
+	execucoes := 0
+	job := Job{
+		Nome:   "teste.diario",
+		Agenda: AgendaDiaria{Hora: 6, Loc: loc},
+		Executar: func(context.Context, time.Time) (interface{}, error) {
+			mu.Lock()
+			execucoes++
+			mu.Unlock()
+			return map[string]int{"criados": 3}, nil
+		},
+	}
+	a := newJobSchedulerTeste(store, "a", agora)
+	b := newJobSchedulerTeste(store, "b", agora)
+	a.Registrar(job)
+	b.Registrar(job)
+
+	a.verificar(context.Background())
+	b.verificar(context.Background())
+	a.wg.Wait()
+	b.verificar(context.Background())
+	a.verificar(context.Background())
+	a.wg.Wait()
+	b.wg.Wait()
+
+	if execucoes != 1 || len(store.rows) != 1 {
+		t.Fatalf("execuções: %d, linhas: %d", execucoes, len(store.rows))
+	}
+	r := store.rows[0]
+	if r.Instancia != "a" || r.Origem != models.JobOrigemAgendada || r.Status != models.JobStatusSucesso {
+		t.Fatalf("execução: %+v", r)
+	}
+	if string(r.Resultado) != `{"criados":3}` {
+		t.Fatalf("resultado: %s", r.Resultado)
+	}
+	if !b.slots[job.Nome].Equal(*r.AgendadoPara) {
+		t.Fatal("réplica b deve memorizar o slot reivindicado pela outra")
+	}
+}
+
+func TestJobScheduler_RecuperaSlotPerdido(t *testing.T) {
+	loc := time.UTC
+	// A réplica arrancou às 10:00: o slot das 06:00 de hoje não correu.
+	agora := time.Date(2026, 3, 10, 10, 0, 0, 0, loc)
+	store := &fakeJobStore{}
+	var ref time.Time
+	s := newJobSchedulerTeste(store, "a", agora)
+	s.Registrar(Job{
+		Nome:   "teste.diario",
+		Agenda: AgendaDiaria{Hora: 6, Loc: loc},
+		Executar: func(_ context.Context, r time.Time) (interface{}, error) {
+			ref = r
+			return nil, nil
+		},
+	})
+	s.Registrar(Job{Nome: "teste.manual", Executar: func(context.Context, time.Time) (interface{}, error) {
+		t.Fatal("job sem agenda não corre sozinho")
+		return nil, nil
+	}})
+
+	s.verificar(context.Background())
+	s.wg.Wait()
+
+	if len(store.rows) != 1 || store.rows[0].Origem != models.JobOrigemRecuperada {
+		t.Fatalf("execuções: %+v", store.rows)
+	}
+	if !ref.Equal(time.Date(2026, 3, 10, 6, 0, 0, 0, loc)) {
+		t.Fatalf("referência deve ser o slot perdido: %v", ref)
+	}
+}
+
+func TestJobScheduler_SlotOcupadoTentaDeNovo(t *testing.T) {
+	loc := time.UTC
+	agora := time.Date(2026, 3, 10, 6, 0, 0, 0, loc)
+	store := &fakeJobStore{}
+	s := newJobSchedulerTeste(store, "a", agora)
+	s.Registrar(Job{
+		Nome:     "teste.diario",
+		Agenda:   AgendaDiaria{Hora: 6, Loc: loc},
+		Executar: func(context.Context, time.Time) (interface{}, error) { return nil, nil },
+	})
+	// Disparo manual ainda em curso quando o slot chega.
+	store.rows = append(store.rows, &models.JobExecucao{ID: 1, Job: "teste.diario", Origem: models.JobOrigemManual, Status: models.JobStatusEmExecucao, IniciadoEm: agora})
+
+	s.verificar(context.Background())
+	if len(store.rows) != 1 || !s.slots["teste.diario"].IsZero() {
+		t.Fatal("slot não reivindicado não deve ser memorizado")
+	}
+
+	store.rows[0].Status = models.JobStatusSucesso
+	s.verificar(context.Background())
+	s.wg.Wait()
+	if len(store.rows) != 2 || store.rows[1].AgendadoPara == nil {
+		t.Fatalf("slot deve correr quando o job fica livre: %+v", store.rows)
+	}
+}
+
+func TestJobScheduler_Disparar(t *testing.T) {
+	agora := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	store := &fakeJobStore{}
+	s := newJobSchedulerTeste(store, "a", agora)
+	libera := make(chan struct{})
+	s.Registrar(Job{Nome: "teste.lento", Executar: func(context.Context, time.Time) (interface{}, error) {
+		<-libera
+		return nil, errors.New("falha na regra")
+	}})
+	s.Registrar(Job{Nome: "teste.panic", Executar: func(context.Context, time.Time) (interface{}, error) {
+		panic("boom")
+	}})
+
+	if _, err := s.Disparar(context.Background(), "inexistente", 1); !errors.Is(err, ErrJobNaoEncontrado) {
+		t.Fatalf("job inexistente: %v", err)
+	}
+	row, err := s.Disparar(context.Background(), "teste.lento", 7)
+	if err != nil || row.Origem != models.JobOrigemManual || row.DisparadoPor == nil || *row.DisparadoPor != 7 {
+		t.Fatalf("disparo: %+v %v", row, err)
+	}
+	if _, err := s.Disparar(context.Background(), "teste.lento", 7); !errors.Is(err, ErrJobEmExecucao) {
+		t.Fatalf("segundo disparo concorrente: %v", err)
+	}
+	close(libera)
+	if _, err := s.Disparar(context.Background(), "teste.panic", 7); err != nil {
+		t.Fatal(err)
+	}
+	s.wg.Wait()
+
+	for _, r := range store.rows {
+		if r.Status != models.JobStatusErro || r.Erro == nil {
+			t.Fatalf("erro deve ficar no histórico: %+v", r)
+		}
+	}
+	if *store.rows[1].Erro != "panic: boom" {
+		t.Fatalf("panic: %s", *store.rows[1].Erro)
+	}
+}
+
+func TestJobScheduler_ExecucaoAbandonadaLibertaJob(t *testing.T) {
+	agora := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	store := &fakeJobStore{}
+	s := newJobSchedulerTeste(store, "a", agora)
+	s.Registrar(Job{Nome: "teste", Timeout: time.Minute, Executar: func(context.Context, time.Time) (interface{}, error) { return nil, nil }})
+	store.rows = append(store.rows, &models.JobExecucao{ID: 1, Job: "teste", Status: models.JobStatusEmExecucao, IniciadoEm: agora.Add(-time.Hour)})
+
+	if _, err := s.Disparar(context.Background(), "teste", 1); err != nil {
+		t.Fatalf("execução abandonada não deve bloquear: %v", err)
+	}
+	s.wg.Wait()
+	if store.rows[0].Status != models.JobStatusErro || store.rows[1].Status != models.JobStatusSucesso {
+		t.Fatalf("estados: %s, %s", store.rows[0].Status, store.rows[1].Status)
+	}
+}
+
+func TestJobScheduler_RecuperaTodosOsSlotsPerdidosEmSequencia(t *testing.T) {
+	loc := time.UTC
+	// Última execução a 07/03 às 06:00; servidor parado até 10/03 às 10:00: faltam 08, 09 e 10.
+	agora := time.Date(2026, 3, 10, 10, 0, 0, 0, loc)
+	ultimo := time.Date(2026, 3, 7, 6, 0, 0, 0, loc)
+	store := &fakeJobStore{rows: []*models.JobExecucao{
+		{ID: 1, Job: "teste.diario", AgendadoPara: &ultimo, Origem: models.JobOrigemAgendada, Status: models.JobStatusSucesso},
+	}}
+	var refs []time.Time
+	s := newJobSchedulerTeste(store, "a", agora)
+	s.Registrar(Job{
+		Nome:   "teste.diario",
+		Agenda: AgendaDiaria{Hora: 6, Loc: loc},
+		Executar: func(_ context.Context, r time.Time) (interface{}, error) {
+			refs = append(refs, r)
+			return nil, nil
+		},
+	})
+
+	for i := 0; i < 5; i++ {
+		s.verificar(context.Background())
+		s.wg.Wait()
+	}
+
+	want := []time.Time{
+		time.Date(2026, 3, 8, 6, 0, 0, 0, loc),
+		time.Date(2026, 3, 9, 6, 0, 0, 0, loc),
+		time.Date(2026, 3, 10, 6, 0, 0, 0, loc),
+	}
+	if len(refs) != len(want) {
+		t.Fatalf("referências: %v", refs)
+	}
+	for i := range want {
+		if !refs[i].Equal(want[i]) {
+			t.Fatalf("referência %d: %v, want %v", i, refs[i], want[i])
+		}
+	}
+	for _, r := range store.rows[1:] {
+		if r.Origem != models.JobOrigemRecuperada {
+			t.Fatalf("origem: %+v", r)
+		}
+	}
+	if !s.slots["teste.diario"].Equal(want[2]) {
+		t.Fatal("slot atual deve ficar memorizado depois da recuperação")
+	}
+}
+
+func TestJobScheduler_RecuperacaoLimitada(t *testing.T) {
+	loc := time.UTC
+	agora := time.Date(2026, 3, 30, 10, 0, 0, 0, loc)
+	ultimo := time.Date(2026, 3, 1, 6, 0, 0, 0, loc)
+	store := &fakeJobStore{rows: []*models.JobExecucao{
+		{ID: 1, Job: "teste.diario", AgendadoPara: &ultimo, Status: models.JobStatusSucesso},
+	}}
+	execucoes := 0
+	s := newJobSchedulerTeste(store, "a", agora)
+	s.Registrar(Job{
+		Nome:     "teste.diario",
+		Agenda:   AgendaDiaria{Hora: 6, Loc: loc},
+		Executar: func(context.Context, time.Time) (interface{}, error) { execucoes++; return nil, nil },
+	})
+
+	for i := 0; i < 2*jobMaxSlotsRecuperados; i++ {
+		s.verificar(context.Background())
+		s.wg.Wait()
+	}
+
+	if execucoes != jobMaxSlotsRecuperados {
+		t.Fatalf("execuções: %d, want %d", execucoes, jobMaxSlotsRecuperados)
+	}
+	primeiro := store.rows[1].AgendadoPara
+	if want := time.Date(2026, 3, 24, 6, 0, 0, 0, loc); !primeiro.Equal(want) {
+		t.Fatalf("primeiro slot recuperado: %v, want %v", primeiro, want)
+	}
+}
+
+func TestJobScheduler_ExecucaoComSinalRecenteNaoEAbandonada(t *testing.T) {
+	agora := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	store := &fakeJobStore{}
+	s := newJobSchedulerTeste(store, "a", agora)
+	s.Registrar(Job{Nome: "teste", Timeout: time.Minute, Executar: func(context.Context, time.Time) (interface{}, error) { return nil, nil }})
+	// Passou muito do tempo limite, mas a réplica continua a sinalizar (job que ignora o cancelamento).
+	sinal := agora.Add(-time.Minute)
+	store.rows = append(store.rows, &models.JobExecucao{ID: 1, Job: "teste", Status: models.JobStatusEmExecucao, IniciadoEm: agora.Add(-time.Hour), UltimoSinalEm: &sinal})
+
+	if _, err := s.Disparar(context.Background(), "teste", 1); !errors.Is(err, ErrJobEmExecucao) {
+		t.Fatalf("execução viva não deve ser abandonada: %v", err)
+	}
+	if store.rows[0].Status != models.JobStatusEmExecucao {
+		t.Fatalf("estado: %s", store.rows[0].Status)
+	}
+
+	sinal = agora.Add(-jobPrazoSinal - time.Second)
+	if _, err := s.Disparar(context.Background(), "teste", 1); err != nil {
+		t.Fatalf("sem sinal além do prazo a execução deve ser libertada: %v", err)
+	}
+	s.wg.Wait()
+}
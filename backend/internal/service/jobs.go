@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/ceialmilk/api/internal/config"
+)
+
+// Jobs periódicos executados pelo JobScheduler (BR-JOBS-001).
+const (
+	JobAlertasGerar       = "alertas.gerar"
+	JobAlertasResumo      = "alertas.resumo"
+	JobAlertasEscalonar   = "alertas.escalonar"
+	JobAnimaisCicloVida   = "animais.ciclo_vida"
+	JobLixeiraPurgar      = "lixeira.purgar"
+	JobIntegracoesChaves  = "integracoes.avisar_chaves"
+	JobNotificacoesDigest = "notificacoes.digest"
+	JobHistoricoPurgar    = "jobs.purgar_historico"
+)
+
+// alertasEscalonamentoIntervalo intervalo entre verificações de alertas com prazo ultrapassado.
+const alertasEscalonamentoIntervalo = 15 * time.Minute
+
+// jobsHistoricoRetencao tempo de vida das execuções finalizadas em jobs_execucoes.
+const jobsHistoricoRetencao = 90 * 24 * time.Hour
+
+// jobsLocation fuso das agendas diárias (ALERTAS_TZ, default America/Sao_Paulo).
+func jobsLocation(cfg *config.Config) *time.Location {
+	tzName := cfg.AlertasTZ
+	if tzName == "" {
+		tzName = "America/Sao_Paulo"
+	}
+	loc, err := time.LoadLocation(tzName)
+	if err != nil {
+		slog.Warn("jobs: timezone inválida, usando UTC", "tz", tzName, "error", err)
+		return time.UTC
+	}
+	return loc
+}
+
+// agendaDiaria agenda à hora configurada (fallback se fora de 0–23); nil quando ativo = false.
+func agendaDiaria(cfg *config.Config, hora, fallback int, ativo bool) JobAgenda {
+	if !ativo {
+		return nil
+	}
+	if hora < 0 || hora > 23 {
+		hora = fallback
+	}
+	return AgendaDiaria{Hora: hora, Loc: jobsLocation(cfg)}
+}
+
+func agendaIntervalo(intervalo time.Duration, ativo bool) JobAgenda {
+	if !ativo {
+		return nil
+	}
+	return AgendaIntervalo{Intervalo: intervalo}
+}
+
+// NewJobGerarAlertas geração diária de alertas em ALERTAS_CRON_HOUR (ALERTAS_CRON_ENABLED).
+func NewJobGerarAlertas(cfg *config.Config, svc *AlertaGeracaoService) Job {
+	return Job{
+		Nome:      JobAlertasGerar,
+		Descricao: "Geração diária de alertas automáticos",
+		Agenda:    agendaDiaria(cfg, cfg.AlertasCronHour, 6, cfg.AlertasCronEnabled),
+		Timeout:   30 * time.Minute,
+		Executar: func(ctx context.Context, ref time.Time) (interface{}, error) {
+			return svc.GerarAlertasDiarios(ctx, ref)
+		},
+	}
+}
+
+// NewJobResumoAlertas envia de hora a hora os resumos agendados para essa hora (BR-ALERTA-022).
+func NewJobResumoAlertas(cfg *config.Config, svc *ResumoAlertasService) Job {
+	return Job{
+		Nome:      JobAlertasResumo,
+		Descricao: "Resumos periódicos de alertas devidos na hora",
+		Agenda:    agendaIntervalo(time.Hour, cfg.AlertasCronEnabled),
+		Timeout:   30 * time.Minute,
+		Executar: func(ctx context.Context, ref time.Time) (interface{}, error) {
+			enviados, err := svc.EnviarDevidos(ctx, ref)
+			return map[string]int{"enviados": enviados}, err
+		},
+	}
+}
+
+// NewJobEscalonarAlertas escalona os alertas ABERTO com prazo ultrapassado (BR-ALERTA-023).
+func NewJobEscalonarAlertas(cfg *config.Config, svc *AlertaService) Job {
+	return Job{
+		Nome:      JobAlertasEscalonar,
+		Descricao: "Escalonamento de alertas com prazo ultrapassado",
+		Agenda:    agendaIntervalo(alertasEscalonamentoIntervalo, cfg.AlertasCronEnabled),
+		Timeout:   5 * time.Minute,
+		Executar: func(ctx context.Context, _ time.Time) (interface{}, error) {
+			total, err := svc.EscalonarVencidos(ctx, time.Now())
+			return map[string]int{"escalonados": total}, err
+		},
+	}
+}
+
+// NewJobCicloVida transições de categoria e propostas de movimentação de lote (BR-LOTE-005/006).
+func NewJobCicloVida(cfg *config.Config, svc *CicloVidaService) Job {
+	return Job{
+		Nome:      JobAnimaisCicloVida,
+		Descricao: "Ciclo de vida: reclassificação de categoria e propostas de lote",
+		Agenda:    agendaDiaria(cfg, cfg.AlertasCronHour, 6, cfg.AlertasCronEnabled),
+		Timeout:   30 * time.Minute,
+		Executar: func(ctx context.Context, ref time.Time) (interface{}, error) {
+			return svc.ExecutarTodas(ctx, ref)
+		},
+	}
+}
+
+// NewJobPurgarLixeira apaga os registos da lixeira fora da retenção (BR-CICLO-020). Corre mesmo com os
+// alertas desligados, pois garante a retenção.
+func NewJobPurgarLixeira(cfg *config.Config, svc *LixeiraService) Job {
+	return Job{
+		Nome:      JobLixeiraPurgar,
+		Descricao: "Exclusão definitiva de registos expirados da lixeira",
+		Agenda:    agendaDiaria(cfg, cfg.AlertasCronHour, 6, true),
+		Timeout:   5 * time.Minute,
+		Executar: func(ctx context.Context, _ time.Time) (interface{}, error) {
+			apagados, err := svc.PurgarExpirados(ctx)
+			return map[string]int64{"apagados": apagados}, err
+		},
+	}
+}
+
+// NewJobAvisarChavesIntegracao avisa os admins das API keys M2M próximas da expiração (BR-INTEG-016).
+func NewJobAvisarChavesIntegracao(cfg *config.Config, svc *IntegracaoService) Job {
+	return Job{
+		Nome:      JobIntegracoesChaves,
+		Descricao: "Aviso de API keys de integração próximas da expiração",
+		Agenda:    agendaDiaria(cfg, cfg.AlertasCronHour, 6, cfg.AlertasCronEnabled),
+		Timeout:   5 * time.Minute,
+		Executar: func(ctx context.Context, _ time.Time) (interface{}, error) {
+			avisados, err := svc.AvisarChavesExpirando(ctx, time.Now())
+			return map[string]int{"clientes_avisados": avisados}, err
+		},
+	}
+}
+
+// NewJobDigestNotificacoes envia em NOTIFICACOES_DIGEST_HORA os resumos acumulados (BR-ALERTA-021).
+func NewJobDigestNotificacoes(cfg *config.Config, svc *NotificacaoService) Job {
+	return Job{
+		Nome:      JobNotificacoesDigest,
+		Descricao: "Resumo diário das notificações em modo digest ou retidas pelo silêncio",
+		Agenda:    agendaDiaria(cfg, cfg.NotificacoesDigestHora, 7, true),
+		Executar: func(ctx context.Context, _ time.Time) (interface{}, error) {
+			enviados, err := svc.EnviarDigests(ctx)
+			return map[string]int{"enviados": enviados}, err
+		},
+	}
+}
+
+// NewJobPurgarHistoricoJobs mantém jobs_execucoes dentro da retenção.
+func NewJobPurgarHistoricoJobs(cfg *config.Config, scheduler *JobScheduler) Job {
+	return Job{
+		Nome:      JobHistoricoPurgar,
+		Descricao: "Limpeza do histórico de execuções de jobs",
+		Agenda:    agendaDiaria(cfg, cfg.AlertasCronHour, 6, true),
+		Timeout:   5 * time.Minute,
+		Executar: func(ctx context.Context, _ time.Time) (interface{}, error) {
+			apagados, err := scheduler.purgarHistorico(ctx, time.Now().Add(-jobsHistoricoRetencao))
+			return map[string]int64{"apagados": apagados}, err
+		},
+	}
+}
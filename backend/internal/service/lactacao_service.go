@@ -15,6 +15,7 @@ var ErrLactacaoNotFound = errors.New("lactacao nao encontrada")
 var ErrLactacaoAtivaJaExiste = errors.New("animal ja possui lactacao ativa nesta fazenda")
 
 type LactacaoService struct {
+	auditavel
 	repo        *repository.LactacaoRepository
 	animalRepo  *repository.AnimalRepository
 	fazendaRepo *repository.FazendaRepository
@@ -66,7 +67,11 @@ func (s *LactacaoService) Create(ctx context.Context, l *models.Lactacao) error
 			return ErrLactacaoAtivaJaExiste
 		}
 	}
-	return s.repo.Create(ctx, l)
+	if err := s.repo.Create(ctx, l); err != nil {
+		return err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoCreate, models.AuditoriaEntidadeLactacao, l.ID, l.FazendaID, l.AnimalID, nil, l)
+	return nil
 }
 
 func (s *LactacaoService) GetByID(ctx context.Context, id int64) (*models.Lactacao, error) {
@@ -116,7 +121,11 @@ func (s *LactacaoService) Update(ctx context.Context, l *models.Lactacao) error
 	if err := ValidateEventoDataCivilTemporal(animal, dataInicio); err != nil {
 		return err
 	}
-	return s.repo.Update(ctx, l)
+	if err := s.repo.Update(ctx, l); err != nil {
+		return err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeLactacao, l.ID, animal.FazendaID, l.AnimalID, existing, l)
+	return nil
 }
 
 func (s *LactacaoService) GetEmAndamentoByAnimalID(ctx context.Context, animalID int64) (*models.Lactacao, error) {
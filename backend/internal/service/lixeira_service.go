@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/repository"
+	"github.com/ceialmilk/api/internal/requestctx"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrLixeiraEntidadeInvalida = errors.New("entidade invalida (PARTO, CIO, COBERTURA ou PRODUCAO_LEITE)")
+	ErrLixeiraItemNotFound     = errors.New("item nao encontrado na lixeira")
+	ErrLixeiraRetencaoExpirada = errors.New("prazo de restauracao expirado")
+)
+
+// LixeiraRetencaoDiasPadrao é a retenção usada quando a configuração não informa outra.
+const LixeiraRetencaoDiasPadrao = 30
+
+type lixeiraStore interface {
+	ListByFazendaID(ctx context.Context, fazendaID int64, entidade string, desde time.Time, limit, offset int) ([]*models.LixeiraItem, int64, error)
+	GetItem(ctx context.Context, fazendaID int64, entidade string, id int64) (*models.LixeiraItem, error)
+	PurgarExcluidosAntes(ctx context.Context, limite time.Time) (int64, error)
+}
+
+// lixeiraRestaurador retira da lixeira um registo de uma entidade, revalidando as regras de escrita.
+type lixeiraRestaurador func(ctx context.Context, id int64) (interface{}, error)
+
+// LixeiraService expõe, por fazenda, os registos do ciclo excluídos logicamente (partos, cios,
+// coberturas e produção) e a restauração dentro da janela de retenção.
+type LixeiraService struct {
+	repo          lixeiraStore
+	restauradores map[string]lixeiraRestaurador
+	retencao      time.Duration
+	now           func() time.Time
+}
+
+func NewLixeiraService(
+	repo *repository.LixeiraRepository,
+	partoSvc *PartoService,
+	cioSvc *CioService,
+	coberturaSvc *CoberturaService,
+	producaoSvc *ProducaoService,
+	retencaoDias int,
+) *LixeiraService {
+	if retencaoDias <= 0 {
+		retencaoDias = LixeiraRetencaoDiasPadrao
+	}
+	return &LixeiraService{
+		repo: repo,
+		restauradores: map[string]lixeiraRestaurador{
+			models.AuditoriaEntidadeParto: func(ctx context.Context, id int64) (interface{}, error) {
+				return partoSvc.Restaurar(ctx, id)
+			},
+			models.AuditoriaEntidadeCio: func(ctx context.Context, id int64) (interface{}, error) {
+				return cioSvc.Restaurar(ctx, id)
+			},
+			models.AuditoriaEntidadeCobertura: func(ctx context.Context, id int64) (interface{}, error) {
+				return coberturaSvc.Restaurar(ctx, id)
+			},
+			models.AuditoriaEntidadeProducaoLeite: func(ctx context.Context, id int64) (interface{}, error) {
+				return producaoSvc.Restaurar(ctx, id)
+			},
+		},
+		retencao: time.Duration(retencaoDias) * 24 * time.Hour,
+		now:      time.Now,
+	}
+}
+
+// List devolve os itens ainda restauráveis da fazenda; entidade vazia = todas.
+func (s *LixeiraService) List(ctx context.Context, fazendaID int64, entidade string, limit, offset int) ([]*models.LixeiraItem, int64, error) {
+	if entidade != "" && !models.IsValidLixeiraEntidade(entidade) {
+		return nil, 0, ErrLixeiraEntidadeInvalida
+	}
+	list, total, err := s.repo.ListByFazendaID(ctx, fazendaID, entidade, s.now().Add(-s.retencao), limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, it := range list {
+		it.ExpiraEm = it.ExcluidoEm.Add(s.retencao)
+	}
+	return list, total, nil
+}
+
+// Restaurar retira o item da lixeira pelo service da entidade, que revalida integridade do ciclo,
+// regras temporais e recalcula status reprodutivo/lactação.
+func (s *LixeiraService) Restaurar(ctx context.Context, fazendaID int64, entidade string, id int64) (interface{}, error) {
+	restaurar, ok := s.restauradores[entidade]
+	if !ok {
+		return nil, ErrLixeiraEntidadeInvalida
+	}
+	it, err := s.repo.GetItem(ctx, fazendaID, entidade, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrLixeiraItemNotFound
+		}
+		return nil, err
+	}
+	if !s.now().Before(it.ExcluidoEm.Add(s.retencao)) {
+		return nil, ErrLixeiraRetencaoExpirada
+	}
+	return restaurar(ctx, id)
+}
+
+// PurgarExpirados apaga definitivamente o que passou da retenção (cron diário).
+func (s *LixeiraService) PurgarExpirados(ctx context.Context) (int64, error) {
+	return s.repo.PurgarExcluidosAntes(ctx, s.now().Add(-s.retencao))
+}
+
+// usuarioExclusao devolve o utilizador do pedido para excluido_por (nil = processo do sistema).
+func usuarioExclusao(ctx context.Context) *int64 {
+	ator, ok := requestctx.AtorFromContext(ctx)
+	if !ok || ator.UsuarioID <= 0 {
+		return nil
+	}
+	id := ator.UsuarioID
+	return &id
+}
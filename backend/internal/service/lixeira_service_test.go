@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/requestctx"
+	"github.com/jackc/pgx/v5"
+)
+
+type fakeLixeiraStore struct {
+	itens       []*models.LixeiraItem
+	desde       time.Time
+	purgaLimite time.Time
+}
+
+func (f *fakeLixeiraStore) ListByFazendaID(_ context.Context, _ int64, _ string, desde time.Time, _, _ int) ([]*models.LixeiraItem, int64, error) {
+	f.desde = desde
+	return f.itens, int64(len(f.itens)), nil
+}
+
+func (f *fakeLixeiraStore) GetItem(_ context.Context, fazendaID int64, entidade string, id int64) (*models.LixeiraItem, error) {
+	for _, it := range f.itens {
+		if it.FazendaID == fazendaID && it.Entidade == entidade && it.ID == id {
+			return it, nil
+		}
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (f *fakeLixeiraStore) PurgarExcluidosAntes(_ context.Context, limite time.Time) (int64, error) {
+	f.purgaLimite = limite
+	return 0, nil
+}
+
+func newLixeiraServiceTeste(store *fakeLixeiraStore, agora time.Time, restaurados *[]int64) *LixeiraService {
+	return &LixeiraService{
+		repo: store,
+		restauradores: map[string]lixeiraRestaurador{
+			models.AuditoriaEntidadeCio: func(_ context.Context, id int64) (interface{}, error) {
+				*restaurados = append(*restaurados, id)
+				return id, nil
+			},
+		},
+		retencao: 30 * 24 * time.Hour,
+		now:      func() time.Time { return agora },
+	}
+}
+
+func TestLixeiraRestaurar_RetencaoEFazenda(t *testing.T) {
+	agora := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	store := &fakeLixeiraStore{itens: []*models.LixeiraItem{
+		{Entidade: models.AuditoriaEntidadeCio, ID: 1, FazendaID: 7, ExcluidoEm: agora.AddDate(0, 0, -29)},
+		{Entidade: models.AuditoriaEntidadeCio, ID: 2, FazendaID: 7, ExcluidoEm: agora.AddDate(0, 0, -30)},
+	}}
+	var restaurados []int64
+	svc := newLixeiraServiceTeste(store, agora, &restaurados)
+	ctx := context.Background()
+
+	if _, err := svc.Restaurar(ctx, 7, models.AuditoriaEntidadeCio, 1); err != nil {
+		t.Fatalf("restaurar dentro da retenção: %v", err)
+	}
+	if _, err := svc.Restaurar(ctx, 7, models.AuditoriaEntidadeCio, 2); !errors.Is(err, ErrLixeiraRetencaoExpirada) {
+		t.Fatalf("esperado ErrLixeiraRetencaoExpirada, got %v", err)
+	}
+	if _, err := svc.Restaurar(ctx, 8, models.AuditoriaEntidadeCio, 1); !errors.Is(err, ErrLixeiraItemNotFound) {
+		t.Fatalf("item de outra fazenda: esperado ErrLixeiraItemNotFound, got %v", err)
+	}
+	if _, err := svc.Restaurar(ctx, 7, models.AuditoriaEntidadeLote, 1); !errors.Is(err, ErrLixeiraEntidadeInvalida) {
+		t.Fatalf("esperado ErrLixeiraEntidadeInvalida, got %v", err)
+	}
+	if len(restaurados) != 1 || restaurados[0] != 1 {
+		t.Fatalf("esperado restaurar apenas o item 1, got %v", restaurados)
+	}
+}
+
+func TestLixeiraList_ExpiraEmEJanela(t *testing.T) {
+	agora := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	excluido := agora.AddDate(0, 0, -3)
+	store := &fakeLixeiraStore{itens: []*models.LixeiraItem{
+		{Entidade: models.AuditoriaEntidadeParto, ID: 1, FazendaID: 7, ExcluidoEm: excluido},
+	}}
+	var restaurados []int64
+	svc := newLixeiraServiceTeste(store, agora, &restaurados)
+
+	if _, _, err := svc.List(context.Background(), 7, "ANIMAL", 50, 0); !errors.Is(err, ErrLixeiraEntidadeInvalida) {
+		t.Fatalf("esperado ErrLixeiraEntidadeInvalida, got %v", err)
+	}
+	list, total, err := svc.List(context.Background(), 7, "", 50, 0)
+	if err != nil || total != 1 {
+		t.Fatalf("list: total=%d err=%v", total, err)
+	}
+	if !list[0].ExpiraEm.Equal(excluido.AddDate(0, 0, 30)) {
+		t.Fatalf("expira_em inesperado: %v", list[0].ExpiraEm)
+	}
+	if !store.desde.Equal(agora.AddDate(0, 0, -30)) {
+		t.Fatalf("janela da listagem inesperada: %v", store.desde)
+	}
+	if _, err := svc.PurgarExpirados(context.Background()); err != nil || !store.purgaLimite.Equal(store.desde) {
+		t.Fatalf("purga: limite=%v err=%v", store.purgaLimite, err)
+	}
+}
+
+func TestUsuarioExclusao(t *testing.T) {
+	if got := usuarioExclusao(context.Background()); got != nil {
+		t.Fatalf("sem ator esperado nil, got %d", *got)
+	}
+	ctx := requestctx.WithAtor(context.Background(), requestctx.Ator{UsuarioID: 9, Perfil: models.PerfilGerente})
+	if got := usuarioExclusao(ctx); got == nil || *got != 9 {
+		t.Fatalf("esperado 9, got %v", got)
+	}
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+// loteDesempenhoMaxDias janela máxima dos relatórios de desempenho por lote.
+const loteDesempenhoMaxDias = 366
+
+var (
+	ErrLoteDesempenhoPeriodoInvalido = fmt.Errorf("periodo invalido: de <= ate, ate no maximo hoje e no maximo %d dias", loteDesempenhoMaxDias)
+	ErrLoteDesempenhoMarcoInvalido   = errors.New("marco deve estar dentro do periodo e depois de de")
+)
+
+// LoteDesempenhoService relatórios de produção e indicadores por lote (BR-LOTE-011/012).
+type LoteDesempenhoService struct {
+	repo        *repository.LoteDesempenhoRepository
+	loteRepo    *repository.LoteRepository
+	fazendaRepo *repository.FazendaRepository
+}
+
+func NewLoteDesempenhoService(repo *repository.LoteDesempenhoRepository, loteRepo *repository.LoteRepository, fazendaRepo *repository.FazendaRepository) *LoteDesempenhoService {
+	return &LoteDesempenhoService{repo: repo, loteRepo: loteRepo, fazendaRepo: fazendaRepo}
+}
+
+func validarPeriodoDesempenho(de, ate time.Time) (time.Time, time.Time, error) {
+	de, ate = TruncateToCivilDate(de), TruncateToCivilDate(ate)
+	if ate.Before(de) || ate.After(TruncateToCivilDate(time.Now())) || diasCivis(de, ate) >= loteDesempenhoMaxDias {
+		return de, ate, ErrLoteDesempenhoPeriodoInvalido
+	}
+	return de, ate, nil
+}
+
+// DesempenhoLote série diária, totais e indicadores atuais de um lote; com marco, compara a produção
+// antes e depois dessa data.
+func (s *LoteDesempenhoService) DesempenhoLote(ctx context.Context, loteID int64, de, ate time.Time, marco *time.Time) (*models.LoteDesempenho, error) {
+	de, ate, err := validarPeriodoDesempenho(de, ate)
+	if err != nil {
+		return nil, err
+	}
+	if marco != nil {
+		m := TruncateToCivilDate(*marco)
+		if !m.After(de) || m.After(ate) {
+			return nil, ErrLoteDesempenhoMarcoInvalido
+		}
+		marco = &m
+	}
+	lote, err := s.loteRepo.GetByID(ctx, loteID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrLoteNotFound
+		}
+		return nil, err
+	}
+	linhas, err := s.repo.ProducaoDiariaPorLote(ctx, lote.FazendaID, &lote.ID, de, ate)
+	if err != nil {
+		return nil, err
+	}
+	indicadores, err := s.repo.IndicadoresPorLote(ctx, lote.FazendaID, &lote.ID)
+	if err != nil {
+		return nil, err
+	}
+	out := &models.LoteDesempenho{
+		LoteID:      lote.ID,
+		LoteNome:    lote.Nome,
+		Tipo:        lote.Tipo,
+		De:          de.Format("2006-01-02"),
+		Ate:         ate.Format("2006-01-02"),
+		Producao:    resumirProducaoLote(linhas),
+		Serie:       serieProducaoLote(linhas, de, ate),
+		Indicadores: indicadoresLote(indicadores[lote.ID]),
+	}
+	if marco != nil {
+		out.Comparacao = compararProducaoLote(linhas, *marco)
+	}
+	return out, nil
+}
+
+// DesempenhoFazenda resumo por lote da fazenda: lotes ativos, lotes inativos com produção no período e a
+// produção de animais sem lote.
+func (s *LoteDesempenhoService) DesempenhoFazenda(ctx context.Context, fazendaID int64, de, ate time.Time) (*models.LotesDesempenho, error) {
+	de, ate, err := validarPeriodoDesempenho(de, ate)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.fazendaRepo.GetByID(ctx, fazendaID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrFazendaNotFound
+		}
+		return nil, err
+	}
+	lotes, err := s.loteRepo.GetByFazendaID(ctx, fazendaID)
+	if err != nil {
+		return nil, err
+	}
+	linhas, err := s.repo.ProducaoDiariaPorLote(ctx, fazendaID, nil, de, ate)
+	if err != nil {
+		return nil, err
+	}
+	indicadores, err := s.repo.IndicadoresPorLote(ctx, fazendaID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	porLote := map[int64][]repository.LoteProducaoDiaLinha{}
+	var semLote []repository.LoteProducaoDiaLinha
+	for _, ln := range linhas {
+		if ln.LoteID == nil {
+			semLote = append(semLote, ln)
+			continue
+		}
+		porLote[*ln.LoteID] = append(porLote[*ln.LoteID], ln)
+	}
+
+	out := &models.LotesDesempenho{
+		FazendaID: fazendaID,
+		De:        de.Format("2006-01-02"),
+		Ate:       ate.Format("2006-01-02"),
+		Lotes:     []models.LoteDesempenhoResumo{},
+	}
+	for _, l := range lotes {
+		prod := porLote[l.ID]
+		if !l.Ativo && len(prod) == 0 {
+			continue
+		}
+		id, nome := l.ID, l.Nome
+		out.Lotes = append(out.Lotes, models.LoteDesempenhoResumo{
+			LoteID:      &id,
+			LoteNome:    &nome,
+			Tipo:        l.Tipo,
+			Ativo:       l.Ativo,
+			Producao:    resumirProducaoLote(prod),
+			Indicadores: indicadoresLote(indicadores[l.ID]),
+		})
+	}
+	sort.SliceStable(out.Lotes, func(i, j int) bool { return *out.Lotes[i].LoteNome < *out.Lotes[j].LoteNome })
+	if len(semLote) > 0 {
+		out.Lotes = append(out.Lotes, models.LoteDesempenhoResumo{
+			Producao:    resumirProducaoLote(semLote),
+			Indicadores: indicadoresLote(nil),
+		})
+	}
+	return out, nil
+}
+
+// resumirProducaoLote totais das linhas diárias (uma por dia do lote).
+func resumirProducaoLote(linhas []repository.LoteProducaoDiaLinha) models.LoteProducaoResumo {
+	var (
+		res         models.LoteProducaoResumo
+		animaisDias int
+	)
+	for _, ln := range linhas {
+		res.LitrosTotal += ln.Litros
+		res.DiasComProducao++
+		animaisDias += ln.Animais
+	}
+	if res.DiasComProducao > 0 {
+		res.MediaDiariaLitros = arredondar2(res.LitrosTotal / float64(res.DiasComProducao))
+	}
+	if animaisDias > 0 {
+		res.MediaPorAnimalDia = arredondar2(res.LitrosTotal / float64(animaisDias))
+	}
+	res.LitrosTotal = arredondar2(res.LitrosTotal)
+	return res
+}
+
+// serieProducaoLote um ponto por dia de [de, ate]; dias sem ordenha ficam a zero.
+func serieProducaoLote(linhas []repository.LoteProducaoDiaLinha, de, ate time.Time) []models.LoteProducaoDia {
+	porDia := make(map[string]repository.LoteProducaoDiaLinha, len(linhas))
+	for _, ln := range linhas {
+		porDia[ln.Data.Format("2006-01-02")] = ln
+	}
+	serie := []models.LoteProducaoDia{}
+	for d := de; !d.After(ate); d = d.AddDate(0, 0, 1) {
+		chave := d.Format("2006-01-02")
+		ponto := models.LoteProducaoDia{Data: chave}
+		if ln, ok := porDia[chave]; ok {
+			ponto.Litros = arredondar2(ln.Litros)
+			ponto.AnimaisOrdenhados = ln.Animais
+			if ln.Animais > 0 {
+				ponto.MediaPorAnimal = arredondar2(ln.Litros / float64(ln.Animais))
+			}
+		}
+		serie = append(serie, ponto)
+	}
+	return serie
+}
+
+// compararProducaoLote separa as linhas em antes do marco e a partir dele.
+func compararProducaoLote(linhas []repository.LoteProducaoDiaLinha, marco time.Time) *models.LoteDesempenhoComparacao {
+	var antes, depois []repository.LoteProducaoDiaLinha
+	for _, ln := range linhas {
+		if TruncateToCivilDate(ln.Data).Before(marco) {
+			antes = append(antes, ln)
+		} else {
+			depois = append(depois, ln)
+		}
+	}
+	cmp := &models.LoteDesempenhoComparacao{
+		Marco:  marco.Format("2006-01-02"),
+		Antes:  resumirProducaoLote(antes),
+		Depois: resumirProducaoLote(depois),
+	}
+	if cmp.Antes.MediaPorAnimalDia > 0 {
+		v := arredondar2((cmp.Depois.MediaPorAnimalDia - cmp.Antes.MediaPorAnimalDia) / cmp.Antes.MediaPorAnimalDia * 100)
+		cmp.VariacaoPct = &v
+	}
+	return cmp
+}
+
+func indicadoresLote(ln *repository.LoteIndicadoresLinha) models.LoteIndicadores {
+	if ln == nil {
+		return models.LoteIndicadores{PorStatusReprodutivo: map[string]int{}}
+	}
+	out := models.LoteIndicadores{
+		Animais:              ln.Animais,
+		EmLactacao:           ln.EmLactacao,
+		CasosSaudeAbertos:    ln.CasosSaudeAbertos,
+		AnimaisComCasoAberto: ln.AnimaisComCasoAberto,
+		Prenhes:              ln.Prenhes,
+		PorStatusReprodutivo: ln.PorStatusReprodutivo,
+	}
+	if ln.DELMedio != nil {
+		v := math.Round(*ln.DELMedio*10) / 10
+		out.DELMedio = &v
+	}
+	return out
+}
+
+func arredondar2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ceialmilk/api/internal/repository"
+)
+
+func TestResumirProducaoLote(t *testing.T) {
+	dia := func(d int) time.Time { return time.Date(2026, 6, d, 0, 0, 0, 0, time.UTC) }
+	linhas := []repository.LoteProducaoDiaLinha{
+		{Data: dia(1), Litros: 100, Animais: 4},
+		{Data: dia(2), Litros: 50, Animais: 2},
+	}
+	res := resumirProducaoLote(linhas)
+	if res.LitrosTotal != 150 || res.DiasComProducao != 2 || res.MediaDiariaLitros != 75 || res.MediaPorAnimalDia != 25 {
+		t.Fatalf("resumo: %+v", res)
+	}
+	if vazio := resumirProducaoLote(nil); vazio.MediaPorAnimalDia != 0 || vazio.DiasComProducao != 0 {
+		t.Fatalf("resumo vazio: %+v", vazio)
+	}
+
+	serie := serieProducaoLote(linhas, dia(1), dia(3))
+	if len(serie) != 3 || serie[0].MediaPorAnimal != 25 || serie[2].Litros != 0 || serie[2].Data != "2026-06-03" {
+		t.Fatalf("serie: %+v", serie)
+	}
+}
+
+func TestCompararProducaoLote(t *testing.T) {
+	dia := func(d int) time.Time { return time.Date(2026, 6, d, 0, 0, 0, 0, time.UTC) }
+	linhas := []repository.LoteProducaoDiaLinha{
+		{Data: dia(1), Litros: 80, Animais: 4},
+		{Data: dia(2), Litros: 80, Animais: 4},
+		{Data: dia(3), Litros: 88, Animais: 4},
+		{Data: dia(4), Litros: 88, Animais: 4},
+	}
+	cmp := compararProducaoLote(linhas, dia(3))
+	if cmp.Antes.MediaPorAnimalDia != 20 || cmp.Depois.MediaPorAnimalDia != 22 {
+		t.Fatalf("comparação: %+v", cmp)
+	}
+	if cmp.VariacaoPct == nil || *cmp.VariacaoPct != 10 {
+		t.Fatalf("variação: %v", cmp.VariacaoPct)
+	}
+	if sem := compararProducaoLote(linhas[2:], dia(3)); sem.VariacaoPct != nil {
+		t.Fatalf("sem produção antes do marco não há variação: %v", *sem.VariacaoPct)
+	}
+}
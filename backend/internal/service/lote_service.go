@@ -9,9 +9,13 @@ import (
 	"github.com/jackc/pgx/v5"
 )
 
-var ErrLoteNotFound = errors.New("lote nao encontrado")
+var (
+	ErrLoteNotFound           = errors.New("lote nao encontrado")
+	ErrLoteCapacidadeInvalida = errors.New("capacidade do lote deve ser maior que zero")
+)
 
 type LoteService struct {
+	auditavel
 	repo        *repository.LoteRepository
 	fazendaRepo *repository.FazendaRepository
 }
@@ -30,6 +34,9 @@ func (s *LoteService) Create(ctx context.Context, lote *models.Lote) error {
 	if lote.Tipo != nil && *lote.Tipo != "" && !models.IsValidTipoLote(*lote.Tipo) {
 		return errors.New("tipo de lote invalido")
 	}
+	if lote.Capacidade != nil && *lote.Capacidade <= 0 {
+		return ErrLoteCapacidadeInvalida
+	}
 	_, err := s.fazendaRepo.GetByID(ctx, lote.FazendaID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -37,7 +44,11 @@ func (s *LoteService) Create(ctx context.Context, lote *models.Lote) error {
 		}
 		return err
 	}
-	return s.repo.Create(ctx, lote)
+	if err := s.repo.Create(ctx, lote); err != nil {
+		return err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoCreate, models.AuditoriaEntidadeLote, lote.ID, lote.FazendaID, 0, nil, lote)
+	return nil
 }
 
 func (s *LoteService) GetByID(ctx context.Context, id int64) (*models.Lote, error) {
@@ -72,23 +83,34 @@ func (s *LoteService) Update(ctx context.Context, lote *models.Lote) error {
 	if lote.Tipo != nil && *lote.Tipo != "" && !models.IsValidTipoLote(*lote.Tipo) {
 		return errors.New("tipo de lote invalido")
 	}
-	_, err := s.repo.GetByID(ctx, lote.ID)
+	if lote.Capacidade != nil && *lote.Capacidade <= 0 {
+		return ErrLoteCapacidadeInvalida
+	}
+	existing, err := s.repo.GetByID(ctx, lote.ID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return ErrLoteNotFound
 		}
 		return err
 	}
-	return s.repo.Update(ctx, lote)
+	if err := s.repo.Update(ctx, lote); err != nil {
+		return err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeLote, lote.ID, existing.FazendaID, 0, existing, lote)
+	return nil
 }
 
 func (s *LoteService) Delete(ctx context.Context, id int64) error {
-	_, err := s.repo.GetByID(ctx, id)
+	existing, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return ErrLoteNotFound
 		}
 		return err
 	}
-	return s.repo.Delete(ctx, id)
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoDelete, models.AuditoriaEntidadeLote, id, existing.FazendaID, 0, existing, nil)
+	return nil
 }
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+)
+
+// MailSender entrega os e-mails transacionais da conta (redefinição de senha, verificação de e-mail).
+// SMTPSender atende a interface; sem SMTP em desenvolvimento usa-se LogMailSender.
+type MailSender interface {
+	Enabled() bool
+	Enviar(ctx context.Context, dest NotificacaoDestinatario, msg NotificacaoMensagem) error
+}
+
+// LogMailSender substituto local do SMTP: escreve destinatário, assunto e corpo (com o link) no log em vez
+// de enviar. Só para desenvolvimento — o corpo leva tokens de uso único.
+type LogMailSender struct{}
+
+func (LogMailSender) Enabled() bool { return true }
+
+func (LogMailSender) Enviar(_ context.Context, dest NotificacaoDestinatario, msg NotificacaoMensagem) error {
+	if strings.TrimSpace(dest.Email) == "" {
+		return ErrNotificacaoSemContato
+	}
+	slog.Info("mail (local)", "para", dest.Email, "assunto", msg.Titulo, "corpo", msg.Corpo)
+	return nil
+}
@@ -3,21 +3,40 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/ceialmilk/api/internal/models"
 	"github.com/ceialmilk/api/internal/repository"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// movimentacaoEmLoteMax máximo de animais por movimentação em lote (BR-LOTE-009).
+const movimentacaoEmLoteMax = 500
+
+// loteOcupacaoMaxDias janela máxima do histórico de ocupação.
+const loteOcupacaoMaxDias = 366
+
+var (
+	ErrMovimentacaoLoteSemAnimais  = errors.New("informe animal_ids ou um filtro que selecione animais")
+	ErrMovimentacaoLoteLimite      = fmt.Errorf("no maximo %d animais por movimentacao em lote", movimentacaoEmLoteMax)
+	ErrMovimentacaoLoteDataFutura  = errors.New("data da movimentacao nao pode ser futura")
+	ErrMovimentacaoLoteInvalida    = errors.New("movimentacao em lote com animais invalidos; nada foi movimentado")
+	ErrLoteInativo                 = errors.New("lote de destino inativo")
+	ErrLoteOcupacaoPeriodoInvalido = fmt.Errorf("periodo invalido: de <= ate, ate no maximo hoje e no maximo %d dias", loteOcupacaoMaxDias)
 )
 
 type MovimentacaoLoteService struct {
+	auditavel
+	pool       *pgxpool.Pool
 	repo       *repository.MovimentacaoLoteRepository
 	animalRepo *repository.AnimalRepository
 	loteRepo   *repository.LoteRepository
 }
 
-func NewMovimentacaoLoteService(repo *repository.MovimentacaoLoteRepository, animalRepo *repository.AnimalRepository, loteRepo *repository.LoteRepository) *MovimentacaoLoteService {
-	return &MovimentacaoLoteService{repo: repo, animalRepo: animalRepo, loteRepo: loteRepo}
+func NewMovimentacaoLoteService(pool *pgxpool.Pool, repo *repository.MovimentacaoLoteRepository, animalRepo *repository.AnimalRepository, loteRepo *repository.LoteRepository) *MovimentacaoLoteService {
+	return &MovimentacaoLoteService{pool: pool, repo: repo, animalRepo: animalRepo, loteRepo: loteRepo}
 }
 
 func (s *MovimentacaoLoteService) Create(ctx context.Context, m *models.MovimentacaoLote) error {
@@ -47,9 +66,289 @@ func (s *MovimentacaoLoteService) Create(ctx context.Context, m *models.Moviment
 	if err := s.repo.Create(ctx, m); err != nil {
 		return err
 	}
+	s.auditar(ctx, models.AuditoriaAcaoCreate, models.AuditoriaEntidadeMovimentacaoLote, m.ID, animal.FazendaID, m.AnimalID, nil, m)
 	return s.animalRepo.UpdateLoteID(ctx, m.AnimalID, &m.LoteDestinoID)
 }
 
 func (s *MovimentacaoLoteService) GetByAnimalID(ctx context.Context, animalID int64) ([]*models.MovimentacaoLote, error) {
 	return s.repo.GetByAnimalID(ctx, animalID)
 }
+
+// FazendaDoLote fazenda dona do lote, para validação de acesso nos handlers.
+func (s *MovimentacaoLoteService) FazendaDoLote(ctx context.Context, loteID int64) (int64, error) {
+	l, err := s.loteRepo.GetByID(ctx, loteID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrLoteNotFound
+		}
+		return 0, err
+	}
+	return l.FazendaID, nil
+}
+
+// avisoCapacidade texto do aviso quando o lote passou da capacidade ("" se dentro do limite).
+func avisoCapacidade(l *models.Lote) string {
+	if l == nil || !l.AcimaCapacidade() {
+		return ""
+	}
+	return fmt.Sprintf("Lote %s acima da capacidade: %d animais para %d", l.Nome, l.Ocupacao, *l.Capacidade)
+}
+
+// AvisosCapacidade avisos do lote após uma movimentação (BR-LOTE-008); falha de leitura não é propagada.
+func (s *MovimentacaoLoteService) AvisosCapacidade(ctx context.Context, loteID int64) []string {
+	l, err := s.loteRepo.GetByID(ctx, loteID)
+	if err != nil {
+		return nil
+	}
+	if aviso := avisoCapacidade(l); aviso != "" {
+		return []string{aviso}
+	}
+	return nil
+}
+
+// MovimentacaoLoteFiltro seleciona os animais no rebanho da fazenda do lote de destino.
+type MovimentacaoLoteFiltro struct {
+	LoteOrigemID      *int64
+	Categoria         *string
+	Sexo              *string
+	StatusReprodutivo *string
+	StatusSaude       *string
+}
+
+type MovimentarEmLoteInput struct {
+	LoteDestinoID int64
+	AnimalIDs     []int64
+	Filtro        *MovimentacaoLoteFiltro
+	Data          time.Time
+	Motivo        *string
+	UsuarioID     int64
+}
+
+// MovimentacaoLoteFalha animal que impediu a movimentação em lote.
+type MovimentacaoLoteFalha struct {
+	AnimalID      int64  `json:"animal_id"`
+	Identificacao string `json:"identificacao,omitempty"`
+	Erro          string `json:"erro"`
+}
+
+type ResultadoMovimentacaoEmLote struct {
+	Movimentados  int                        `json:"movimentados"`
+	Ignorados     []int64                    `json:"ignorados"`
+	Movimentacoes []*models.MovimentacaoLote `json:"movimentacoes"`
+	Falhas        []MovimentacaoLoteFalha    `json:"falhas,omitempty"`
+	Lote          *models.Lote               `json:"lote,omitempty"`
+	Avisos        []string                   `json:"avisos,omitempty"`
+}
+
+// MovimentarEmLote move vários animais para o lote numa só transação (BR-LOTE-009). Tudo ou nada: se algum
+// animal falhar a validação, nada é gravado e as falhas vêm no resultado com ErrMovimentacaoLoteInvalida.
+// Animais já no lote de destino são ignorados.
+func (s *MovimentacaoLoteService) MovimentarEmLote(ctx context.Context, in MovimentarEmLoteInput) (*ResultadoMovimentacaoEmLote, error) {
+	if in.LoteDestinoID <= 0 || in.UsuarioID <= 0 {
+		return nil, errors.New("lote_destino_id e usuario_id sao obrigatorios")
+	}
+	if in.Data.IsZero() {
+		in.Data = time.Now()
+	}
+	if TruncateToCivilDate(in.Data).After(TruncateToCivilDate(time.Now())) {
+		return nil, ErrMovimentacaoLoteDataFutura
+	}
+	lote, err := s.loteRepo.GetByID(ctx, in.LoteDestinoID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrLoteNotFound
+		}
+		return nil, err
+	}
+	if !lote.Ativo {
+		return nil, ErrLoteInativo
+	}
+	ids, err := s.resolverAnimaisEmLote(ctx, lote.FazendaID, in)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	// Bloqueia o lote e os animais: duas movimentações simultâneas dos mesmos animais não se cruzam.
+	if _, err := s.loteRepo.GetByIDTx(ctx, tx, lote.ID); err != nil {
+		return nil, err
+	}
+	animais, err := s.animalRepo.GetByIDsTx(ctx, tx, ids)
+	if err != nil {
+		return nil, err
+	}
+	mover, ignorados, falhas := validarMovimentacaoEmLote(lote, ids, animais, in.Data)
+	res := &ResultadoMovimentacaoEmLote{Ignorados: ignorados, Movimentacoes: []*models.MovimentacaoLote{}, Falhas: falhas}
+	if len(falhas) > 0 {
+		return res, ErrMovimentacaoLoteInvalida
+	}
+	for _, a := range mover {
+		m := &models.MovimentacaoLote{
+			AnimalID:      a.ID,
+			LoteOrigemID:  a.LoteID,
+			LoteDestinoID: lote.ID,
+			Data:          in.Data,
+			Motivo:        in.Motivo,
+			UsuarioID:     in.UsuarioID,
+		}
+		if err := s.repo.CreateTx(ctx, tx, m); err != nil {
+			return nil, err
+		}
+		res.Movimentacoes = append(res.Movimentacoes, m)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	committed = true
+
+	for _, m := range res.Movimentacoes {
+		s.auditar(ctx, models.AuditoriaAcaoCreate, models.AuditoriaEntidadeMovimentacaoLote, m.ID, lote.FazendaID, m.AnimalID, nil, m)
+	}
+	res.Movimentados = len(res.Movimentacoes)
+	if depois, err := s.loteRepo.GetByID(ctx, lote.ID); err == nil {
+		res.Lote = depois
+		if aviso := avisoCapacidade(depois); aviso != "" {
+			res.Avisos = []string{aviso}
+		}
+	}
+	return res, nil
+}
+
+// resolverAnimaisEmLote junta os ids explícitos e os do filtro, sem repetições, respeitando o limite.
+func (s *MovimentacaoLoteService) resolverAnimaisEmLote(ctx context.Context, fazendaID int64, in MovimentarEmLoteInput) ([]int64, error) {
+	vistos := map[int64]bool{}
+	var ids []int64
+	add := func(id int64) {
+		if id > 0 && !vistos[id] {
+			vistos[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, id := range in.AnimalIDs {
+		add(id)
+	}
+	if f := in.Filtro; f != nil {
+		list, total, err := s.animalRepo.ListAnimaisFilteredPaginated(ctx, repository.AnimalListFilters{
+			FazendaIDs:        []int64{fazendaID},
+			LoteID:            f.LoteOrigemID,
+			Categoria:         f.Categoria,
+			Sexo:              f.Sexo,
+			StatusReprodutivo: f.StatusReprodutivo,
+			StatusSaude:       f.StatusSaude,
+			SomenteNoRebanho:  true,
+		}, movimentacaoEmLoteMax, 0)
+		if err != nil {
+			return nil, err
+		}
+		if total > movimentacaoEmLoteMax {
+			return nil, ErrMovimentacaoLoteLimite
+		}
+		for _, a := range list {
+			add(a.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, ErrMovimentacaoLoteSemAnimais
+	}
+	if len(ids) > movimentacaoEmLoteMax {
+		return nil, ErrMovimentacaoLoteLimite
+	}
+	return ids, nil
+}
+
+// validarMovimentacaoEmLote separa, pela ordem dos ids, os animais a mover, os já no destino e as falhas.
+func validarMovimentacaoEmLote(lote *models.Lote, ids []int64, animais []*models.Animal, data time.Time) (mover []*models.Animal, ignorados []int64, falhas []MovimentacaoLoteFalha) {
+	porID := make(map[int64]*models.Animal, len(animais))
+	for _, a := range animais {
+		porID[a.ID] = a
+	}
+	ignorados = []int64{}
+	dia := TruncateToCivilDate(data)
+	for _, id := range ids {
+		a, ok := porID[id]
+		switch {
+		case !ok || a.FazendaID != lote.FazendaID:
+			falhas = append(falhas, MovimentacaoLoteFalha{AnimalID: id, Erro: "animal nao encontrado na fazenda do lote"})
+		case a.IsForaDoRebanho():
+			falhas = append(falhas, MovimentacaoLoteFalha{AnimalID: id, Identificacao: a.Identificacao, Erro: "animal fora do rebanho"})
+		case a.DataEntrada != nil && dia.Before(TruncateToCivilDate(*a.DataEntrada)):
+			falhas = append(falhas, MovimentacaoLoteFalha{AnimalID: id, Identificacao: a.Identificacao, Erro: "data anterior a entrada do animal"})
+		case a.LoteID != nil && *a.LoteID == lote.ID:
+			ignorados = append(ignorados, id)
+		default:
+			mover = append(mover, a)
+		}
+	}
+	return mover, ignorados, falhas
+}
+
+// HistoricoOcupacao série diária e eventos de entrada/saída do lote no período (BR-LOTE-010).
+func (s *MovimentacaoLoteService) HistoricoOcupacao(ctx context.Context, loteID int64, de, ate time.Time) (*models.LoteOcupacaoHistorico, error) {
+	de, ate = TruncateToCivilDate(de), TruncateToCivilDate(ate)
+	if ate.Before(de) || ate.After(TruncateToCivilDate(time.Now())) || diasCivis(de, ate) >= loteOcupacaoMaxDias {
+		return nil, ErrLoteOcupacaoPeriodoInvalido
+	}
+	lote, err := s.loteRepo.GetByID(ctx, loteID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrLoteNotFound
+		}
+		return nil, err
+	}
+	eventos, err := s.repo.ListEventosOcupacao(ctx, loteID, de)
+	if err != nil {
+		return nil, err
+	}
+	out := &models.LoteOcupacaoHistorico{
+		LoteID:        lote.ID,
+		Capacidade:    lote.Capacidade,
+		OcupacaoAtual: lote.Ocupacao,
+		De:            de.Format("2006-01-02"),
+		Ate:           ate.Format("2006-01-02"),
+		Serie:         serieOcupacao(lote.Ocupacao, eventos, de, ate),
+		Eventos:       []models.LoteOcupacaoEvento{},
+	}
+	limite := ate.AddDate(0, 0, 1)
+	for _, e := range eventos {
+		if e.Data.Before(limite) {
+			out.Eventos = append(out.Eventos, e)
+		}
+	}
+	return out, nil
+}
+
+// serieOcupacao reconstrói a ocupação no fim de cada dia a partir da ocupação atual, desfazendo os
+// eventos desde `de` (inclusive os posteriores a `ate`).
+func serieOcupacao(atual int, eventos []models.LoteOcupacaoEvento, de, ate time.Time) []models.LoteOcupacaoDia {
+	entradas := map[string]int{}
+	saidas := map[string]int{}
+	inicio := atual
+	for _, e := range eventos {
+		dia := TruncateToCivilDate(e.Data).Format("2006-01-02")
+		if e.Tipo == models.LoteEventoEntrada {
+			entradas[dia]++
+			inicio--
+		} else {
+			saidas[dia]++
+			inicio++
+		}
+	}
+	serie := []models.LoteOcupacaoDia{}
+	ocupacao := inicio
+	for d := de; !d.After(ate); d = d.AddDate(0, 0, 1) {
+		chave := d.Format("2006-01-02")
+		ocupacao += entradas[chave] - saidas[chave]
+		serie = append(serie, models.LoteOcupacaoDia{Data: chave, Ocupacao: ocupacao, Entradas: entradas[chave], Saidas: saidas[chave]})
+	}
+	return serie
+}
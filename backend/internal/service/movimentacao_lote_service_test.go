@@ -0,0 +1,81 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+)
+
+func TestValidarMovimentacaoEmLote(t *testing.T) {
+	data := time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC)
+	lote := &models.Lote{ID: 10, FazendaID: 1}
+	outroLote := int64(20)
+	mesmoLote := int64(10)
+	saida := data.AddDate(0, -1, 0)
+	entradaFutura := data.AddDate(0, 0, 3)
+	animais := []*models.Animal{
+		{ID: 1, FazendaID: 1, Identificacao: "A1", LoteID: &outroLote},
+		{ID: 2, FazendaID: 1, Identificacao: "A2", LoteID: &mesmoLote},
+		{ID: 3, FazendaID: 2, Identificacao: "A3"},
+		{ID: 4, FazendaID: 1, Identificacao: "A4", DataSaida: &saida},
+		{ID: 5, FazendaID: 1, Identificacao: "A5", DataEntrada: &entradaFutura},
+		{ID: 6, FazendaID: 1, Identificacao: "A6"},
+	}
+
+	mover, ignorados, falhas := validarMovimentacaoEmLote(lote, []int64{1, 2, 3, 4, 5, 6, 99}, animais, data)
+	if len(mover) != 2 || mover[0].ID != 1 || mover[1].ID != 6 {
+		t.Fatalf("mover: %+v", mover)
+	}
+	if len(ignorados) != 1 || ignorados[0] != 2 {
+		t.Fatalf("ignorados: %v", ignorados)
+	}
+	esperado := []int64{3, 4, 5, 99}
+	if len(falhas) != len(esperado) {
+		t.Fatalf("falhas: %+v", falhas)
+	}
+	for i, f := range falhas {
+		if f.AnimalID != esperado[i] {
+			t.Fatalf("falha %d: %+v", i, f)
+		}
+	}
+}
+
+func TestSerieOcupacao(t *testing.T) {
+	dia := func(d int) time.Time { return time.Date(2026, 6, d, 0, 0, 0, 0, time.UTC) }
+	eventos := []models.LoteOcupacaoEvento{
+		{Tipo: models.LoteEventoEntrada, Data: dia(2)},
+		{Tipo: models.LoteEventoEntrada, Data: dia(2)},
+		{Tipo: models.LoteEventoSaida, Data: dia(3)},
+		{Tipo: models.LoteEventoBaixa, Data: dia(4)},
+		// Depois de `ate`: desfeito na reconstrução, fora da série.
+		{Tipo: models.LoteEventoEntrada, Data: dia(10)},
+	}
+	// Hoje: 5 animais. Antes de tudo: 5 - 3 entradas + 2 saídas = 4.
+	serie := serieOcupacao(5, eventos, dia(1), dia(5))
+	esperado := []int{4, 6, 5, 4, 4}
+	if len(serie) != len(esperado) {
+		t.Fatalf("serie: %+v", serie)
+	}
+	for i, d := range serie {
+		if d.Ocupacao != esperado[i] {
+			t.Fatalf("dia %s: ocupacao %d, esperado %d", d.Data, d.Ocupacao, esperado[i])
+		}
+	}
+	if serie[1].Entradas != 2 || serie[3].Saidas != 1 {
+		t.Fatalf("contagens: %+v", serie)
+	}
+}
+
+func TestAvisoCapacidade(t *testing.T) {
+	capacidade := 3
+	if avisoCapacidade(&models.Lote{Nome: "L", Ocupacao: 3, Capacidade: &capacidade}) != "" {
+		t.Fatal("lote na capacidade não deve avisar")
+	}
+	if avisoCapacidade(&models.Lote{Nome: "L", Ocupacao: 4, Capacidade: &capacidade}) == "" {
+		t.Fatal("lote acima da capacidade deve avisar")
+	}
+	if avisoCapacidade(&models.Lote{Nome: "L", Ocupacao: 40}) != "" {
+		t.Fatal("lote sem capacidade não deve avisar")
+	}
+}
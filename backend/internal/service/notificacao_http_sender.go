@@ -0,0 +1,84 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HTTPMensagemSender entrega SMS ou WhatsApp via API HTTP de um fornecedor (gateway próprio ou agregador).
+// Contrato: POST JSON {"canal","para","mensagem"} com Authorization: Bearer <token>; qualquer 2xx é sucesso.
+type HTTPMensagemSender struct {
+	canal   string
+	url     string
+	token   string
+	baseURL string
+	client  *http.Client
+}
+
+func NewHTTPMensagemSender(canal, url, token, baseURL string) *HTTPMensagemSender {
+	return &HTTPMensagemSender{
+		canal:   canal,
+		url:     url,
+		token:   token,
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (s *HTTPMensagemSender) Canal() string {
+	return s.canal
+}
+
+func (s *HTTPMensagemSender) Enabled() bool {
+	return s.url != ""
+}
+
+type httpMensagemRequest struct {
+	Canal    string `json:"canal"`
+	Para     string `json:"para"`
+	Mensagem string `json:"mensagem"`
+}
+
+func (s *HTTPMensagemSender) Enviar(ctx context.Context, dest NotificacaoDestinatario, msg NotificacaoMensagem) error {
+	if !s.Enabled() {
+		return ErrNotificacaoCanalIndisponivel
+	}
+	if dest.Telefone == nil || *dest.Telefone == "" {
+		return ErrNotificacaoSemContato
+	}
+	texto := msg.Titulo
+	if msg.Corpo != "" {
+		texto += "\n" + msg.Corpo
+	}
+	if link := linkNotificacao(s.baseURL, msg.URL); link != "" {
+		texto += "\n" + link
+	}
+	body, err := json.Marshal(httpMensagemRequest{Canal: s.canal, Para: *dest.Telefone, Mensagem: texto})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", strings.ToLower(s.canal), err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detalhe, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: fornecedor respondeu %d: %s", strings.ToLower(s.canal), resp.StatusCode, strings.TrimSpace(string(detalhe)))
+	}
+	return nil
+}
@@ -0,0 +1,521 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ceialmilk/api/internal/config"
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/repository"
+)
+
+var (
+	ErrNotificacaoCanalInvalido     = errors.New("canal de notificação inválido")
+	ErrNotificacaoModoInvalido      = errors.New("modo de notificação inválido (IMEDIATO ou DIGEST)")
+	ErrNotificacaoTipoInvalido      = errors.New("tipo de alerta inválido nas preferências")
+	ErrNotificacaoHorarioInvalido   = errors.New("horário de silêncio inválido (início e fim em HH:MM, diferentes)")
+	ErrNotificacaoTelefoneInvalido  = errors.New("telefone inválido (formato internacional, ex.: +5511999998888)")
+	ErrNotificacaoCanalIndisponivel = errors.New("canal de notificação não configurado no servidor")
+	ErrNotificacaoSemContato        = errors.New("utilizador sem contacto para o canal")
+)
+
+var (
+	notificacaoTelefoneRe = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+	notificacaoHorarioRe  = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+)
+
+// notificacaoDigestMaxLinhas limita as linhas de alertas no corpo do resumo; o resto vira "+N outros".
+const notificacaoDigestMaxLinhas = 20
+
+// NotificacaoMensagem conteúdo neutro de canal; URL relativa ao frontend.
+type NotificacaoMensagem struct {
+	Titulo     string
+	Corpo      string
+	URL        string
+	BadgeCount int64
+}
+
+// NotificacaoDestinatario contactos do utilizador; cada canal usa o que precisa.
+type NotificacaoDestinatario struct {
+	UsuarioID int64
+	Nome      string
+	Email     string
+	Telefone  *string
+}
+
+// NotificacaoSender é um canal de entrega (BR-ALERTA-021). Implementações: PushNotificationService (Web Push),
+// SMTPSender (e-mail) e HTTPMensagemSender (SMS/WhatsApp via fornecedor HTTP).
+type NotificacaoSender interface {
+	Canal() string
+	Enabled() bool
+	Enviar(ctx context.Context, dest NotificacaoDestinatario, msg NotificacaoMensagem) error
+}
+
+type notificacaoStore interface {
+	GetConfig(ctx context.Context, usuarioID int64) (*models.NotificacaoConfigUsuario, error)
+	ListPreferencias(ctx context.Context, usuarioID int64) ([]*models.NotificacaoPreferencia, error)
+	SalvarPreferencias(ctx context.Context, cfg *models.NotificacaoConfigUsuario, prefs []models.NotificacaoPreferencia) error
+	EnfileirarDigest(ctx context.Context, item *models.NotificacaoDigestItem) error
+	ListDigestPendentes(ctx context.Context, ate time.Time) ([]*models.NotificacaoDigestItem, error)
+	MarcarDigestEnviados(ctx context.Context, ids []int64, em time.Time) error
+	PurgarDigestEnviados(ctx context.Context, antes time.Time) (int64, error)
+}
+
+type notificacaoUsuarioStore interface {
+	GetByID(ctx context.Context, id int64) (*models.Usuario, error)
+}
+
+// NotificacaoService encaminha alertas para os canais escolhidos por cada utilizador, respeitando severidade
+// mínima, tipos, horário de silêncio e modo resumo (BR-ALERTA-021).
+type NotificacaoService struct {
+	repo        notificacaoStore
+	usuarioRepo notificacaoUsuarioStore
+	senders     map[string]NotificacaoSender
+	loc         *time.Location
+	now         func() time.Time
+}
+
+func NewNotificacaoService(cfg *config.Config, repo *repository.NotificacaoRepository, usuarioRepo *repository.UsuarioRepository) *NotificacaoService {
+	tzName := "America/Sao_Paulo"
+	if cfg != nil && cfg.AlertasTZ != "" {
+		tzName = cfg.AlertasTZ
+	}
+	loc, err := time.LoadLocation(tzName)
+	if err != nil {
+		loc = time.UTC
+	}
+	return &NotificacaoService{
+		repo:        repo,
+		usuarioRepo: usuarioRepo,
+		senders:     map[string]NotificacaoSender{},
+		loc:         loc,
+		now:         time.Now,
+	}
+}
+
+// RegistrarCanal liga um canal de entrega (substitui o anterior do mesmo canal).
+func (s *NotificacaoService) RegistrarCanal(sender NotificacaoSender) {
+	if sender == nil || !models.IsValidNotificacaoCanal(sender.Canal()) {
+		return
+	}
+	s.senders[sender.Canal()] = sender
+}
+
+func (s *NotificacaoService) canalDisponivel(canal string) (NotificacaoSender, bool) {
+	sender, ok := s.senders[canal]
+	if !ok || !sender.Enabled() {
+		return nil, false
+	}
+	return sender, true
+}
+
+// GetPreferencias devolve contacto, silêncio e a preferência efetiva de cada canal.
+func (s *NotificacaoService) GetPreferencias(ctx context.Context, usuarioID int64) (*models.NotificacaoPreferencias, error) {
+	cfg, err := s.repo.GetConfig(ctx, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	out := &models.NotificacaoPreferencias{Config: models.NotificacaoConfigPadrao(usuarioID)}
+	if cfg != nil {
+		out.Config = *cfg
+	}
+	gravadas, err := s.repo.ListPreferencias(ctx, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	porCanal := make(map[string]*models.NotificacaoPreferencia, len(gravadas))
+	for _, p := range gravadas {
+		porCanal[p.Canal] = p
+	}
+	for _, canal := range models.ValidNotificacaoCanais() {
+		p := models.NotificacaoPreferenciaPadrao(canal)
+		if g := porCanal[canal]; g != nil {
+			p = *g
+		}
+		p.UsuarioID = usuarioID
+		_, p.Disponivel = s.canalDisponivel(canal)
+		out.Canais = append(out.Canais, p)
+	}
+	return out, nil
+}
+
+// NotificacaoPreferenciasInput corpo de PUT /me/notificacoes; canais omitidos ficam como estão.
+type NotificacaoPreferenciasInput struct {
+	Telefone               *string
+	SilencioInicio         *string
+	SilencioFim            *string
+	SilencioPermiteCritica *bool
+	Canais                 []NotificacaoCanalInput
+}
+
+type NotificacaoCanalInput struct {
+	Canal            string
+	Ativo            bool
+	SeveridadeMinima string   // vazio = ALTA
+	Tipos            []string // nil = todos
+	Modo             string   // vazio = IMEDIATO
+}
+
+// PutPreferencias valida e grava as preferências do utilizador.
+func (s *NotificacaoService) PutPreferencias(ctx context.Context, usuarioID int64, in NotificacaoPreferenciasInput) (*models.NotificacaoPreferencias, error) {
+	cfg := models.NotificacaoConfigPadrao(usuarioID)
+	if in.Telefone != nil {
+		tel := strings.Join(strings.Fields(*in.Telefone), "")
+		if tel != "" {
+			if !notificacaoTelefoneRe.MatchString(tel) {
+				return nil, ErrNotificacaoTelefoneInvalido
+			}
+			cfg.Telefone = &tel
+		}
+	}
+	if (in.SilencioInicio == nil) != (in.SilencioFim == nil) {
+		return nil, ErrNotificacaoHorarioInvalido
+	}
+	if in.SilencioInicio != nil {
+		ini, fim := strings.TrimSpace(*in.SilencioInicio), strings.TrimSpace(*in.SilencioFim)
+		if !notificacaoHorarioRe.MatchString(ini) || !notificacaoHorarioRe.MatchString(fim) || ini == fim {
+			return nil, ErrNotificacaoHorarioInvalido
+		}
+		cfg.SilencioInicio, cfg.SilencioFim = &ini, &fim
+	}
+	if in.SilencioPermiteCritica != nil {
+		cfg.SilencioPermiteCritica = *in.SilencioPermiteCritica
+	}
+
+	prefs := make([]models.NotificacaoPreferencia, 0, len(in.Canais))
+	vistos := map[string]struct{}{}
+	for _, c := range in.Canais {
+		p, err := validarNotificacaoCanalInput(c)
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := vistos[p.Canal]; dup {
+			return nil, fmt.Errorf("%w: %s repetido", ErrNotificacaoCanalInvalido, p.Canal)
+		}
+		vistos[p.Canal] = struct{}{}
+		if p.Ativo && cfg.Telefone == nil && (p.Canal == models.NotificacaoCanalSMS || p.Canal == models.NotificacaoCanalWhatsApp) {
+			return nil, fmt.Errorf("%w: informe o telefone para %s", ErrNotificacaoSemContato, p.Canal)
+		}
+		prefs = append(prefs, p)
+	}
+	if err := s.repo.SalvarPreferencias(ctx, &cfg, prefs); err != nil {
+		return nil, err
+	}
+	return s.GetPreferencias(ctx, usuarioID)
+}
+
+func validarNotificacaoCanalInput(c NotificacaoCanalInput) (models.NotificacaoPreferencia, error) {
+	p := models.NotificacaoPreferencia{Canal: c.Canal, Ativo: c.Ativo, SeveridadeMinima: c.SeveridadeMinima, Modo: c.Modo}
+	if !models.IsValidNotificacaoCanal(p.Canal) {
+		return p, fmt.Errorf("%w: %s", ErrNotificacaoCanalInvalido, p.Canal)
+	}
+	if p.SeveridadeMinima == "" {
+		p.SeveridadeMinima = models.AlertaSeveridadeAlta
+	}
+	if !models.IsValidAlertaSeveridade(p.SeveridadeMinima) {
+		return p, ErrAlertaSeveridadeInvalida
+	}
+	if p.Modo == "" {
+		p.Modo = models.NotificacaoModoImediato
+	}
+	if !models.IsValidNotificacaoModo(p.Modo) {
+		return p, ErrNotificacaoModoInvalido
+	}
+	if c.Tipos != nil {
+		p.Tipos = []string{}
+		for _, t := range dedupPerfis(c.Tipos) {
+			if !models.IsValidAlertaTipo(t) {
+				return p, fmt.Errorf("%w: %s", ErrNotificacaoTipoInvalido, t)
+			}
+			p.Tipos = append(p.Tipos, t)
+		}
+	}
+	return p, nil
+}
+
+type notificacaoEntrega int
+
+const (
+	notificacaoNaoEnviar notificacaoEntrega = iota
+	notificacaoImediata
+	notificacaoNoDigest
+)
+
+// decidirEntregaNotificacao aplica a preferência do canal ao alerta. No horário de silêncio o envio imediato
+// passa para o resumo, exceto CRITICA quando o utilizador permite.
+func decidirEntregaNotificacao(p models.NotificacaoPreferencia, cfg models.NotificacaoConfigUsuario, tipo, severidade string, agora time.Time) notificacaoEntrega {
+	if !p.Ativo || models.AlertaSeveridadeNivel(severidade) < models.AlertaSeveridadeNivel(p.SeveridadeMinima) {
+		return notificacaoNaoEnviar
+	}
+	if p.Tipos != nil {
+		incluido := false
+		for _, t := range p.Tipos {
+			if t == tipo {
+				incluido = true
+				break
+			}
+		}
+		if !incluido {
+			return notificacaoNaoEnviar
+		}
+	}
+	if p.Modo == models.NotificacaoModoDigest {
+		return notificacaoNoDigest
+	}
+	if emSilencioNotificacao(cfg, agora) && !(cfg.SilencioPermiteCritica && severidade == models.AlertaSeveridadeCritica) {
+		return notificacaoNoDigest
+	}
+	return notificacaoImediata
+}
+
+// emSilencioNotificacao indica se agora (hora local) está no intervalo [início, fim) do silêncio.
+func emSilencioNotificacao(cfg models.NotificacaoConfigUsuario, agora time.Time) bool {
+	if cfg.SilencioInicio == nil || cfg.SilencioFim == nil {
+		return false
+	}
+	ini, ok1 := minutosDoDia(*cfg.SilencioInicio)
+	fim, ok2 := minutosDoDia(*cfg.SilencioFim)
+	if !ok1 || !ok2 || ini == fim {
+		return false
+	}
+	m := agora.Hour()*60 + agora.Minute()
+	if ini < fim {
+		return m >= ini && m < fim
+	}
+	return m >= ini || m < fim
+}
+
+func minutosDoDia(hhmm string) (int, bool) {
+	t, err := time.Parse("15:04", hhmm)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+func (s *NotificacaoService) destinatario(ctx context.Context, usuarioID int64, cfg models.NotificacaoConfigUsuario) (NotificacaoDestinatario, error) {
+	u, err := s.usuarioRepo.GetByID(ctx, usuarioID)
+	if err != nil {
+		return NotificacaoDestinatario{}, err
+	}
+	return NotificacaoDestinatario{UsuarioID: usuarioID, Nome: u.Nome, Email: u.Email, Telefone: cfg.Telefone}, nil
+}
+
+// DespacharAlerta entrega o alerta aos utilizadores pelos canais das suas preferências. Falhas de um canal
+// ou utilizador são registadas e não interrompem os restantes.
+func (s *NotificacaoService) DespacharAlerta(ctx context.Context, alerta *models.AlertaWithNames, usuarioIDs []int64, msg NotificacaoMensagem) {
+	if alerta == nil {
+		return
+	}
+	agora := s.now().In(s.loc)
+	for _, uid := range usuarioIDs {
+		prefs, err := s.GetPreferencias(ctx, uid)
+		if err != nil {
+			slog.Warn("notificacao: carregar preferências", "error", err, "usuario_id", uid)
+			continue
+		}
+		var dest *NotificacaoDestinatario
+		for _, p := range prefs.Canais {
+			sender, ok := s.canalDisponivel(p.Canal)
+			if !ok {
+				continue
+			}
+			switch decidirEntregaNotificacao(p, prefs.Config, alerta.Tipo, alerta.Severidade, agora) {
+			case notificacaoImediata:
+				if dest == nil {
+					d, err := s.destinatario(ctx, uid, prefs.Config)
+					if err != nil {
+						slog.Warn("notificacao: carregar destinatário", "error", err, "usuario_id", uid)
+						break
+					}
+					dest = &d
+				}
+				if err := sender.Enviar(ctx, *dest, msg); err != nil {
+					slog.Warn("notificacao: envio falhou", "canal", p.Canal, "usuario_id", uid, "alerta_id", alerta.ID, "error", err)
+				}
+			case notificacaoNoDigest:
+				alertaID, fazendaID := alerta.ID, alerta.FazendaID
+				item := &models.NotificacaoDigestItem{
+					UsuarioID: uid, Canal: p.Canal, AlertaID: &alertaID, FazendaID: &fazendaID,
+					Titulo: msg.Titulo, Corpo: msg.Corpo, URL: msg.URL,
+				}
+				if err := s.repo.EnfileirarDigest(ctx, item); err != nil {
+					slog.Warn("notificacao: enfileirar resumo", "canal", p.Canal, "usuario_id", uid, "error", err)
+				}
+			}
+		}
+	}
+}
+
+// decidirEntregaAviso aplica a preferência do canal a avisos que não são alertas (ex.: troca de folga):
+// tipos e severidade mínima não se aplicam; modo resumo e horário de silêncio sim.
+func decidirEntregaAviso(p models.NotificacaoPreferencia, cfg models.NotificacaoConfigUsuario, agora time.Time) notificacaoEntrega {
+	if !p.Ativo {
+		return notificacaoNaoEnviar
+	}
+	if p.Modo == models.NotificacaoModoDigest || emSilencioNotificacao(cfg, agora) {
+		return notificacaoNoDigest
+	}
+	return notificacaoImediata
+}
+
+// NotificarUsuarios envia um aviso da fazenda pelos canais ativos de cada utilizador (sem alerta associado).
+// Falhas são registadas e não interrompem o chamador.
+func (s *NotificacaoService) NotificarUsuarios(ctx context.Context, fazendaID int64, usuarioIDs []int64, msg NotificacaoMensagem) {
+	agora := s.now().In(s.loc)
+	for _, uid := range usuarioIDs {
+		prefs, err := s.GetPreferencias(ctx, uid)
+		if err != nil {
+			slog.Warn("notificacao: carregar preferências", "error", err, "usuario_id", uid)
+			continue
+		}
+		var dest *NotificacaoDestinatario
+		for _, p := range prefs.Canais {
+			sender, ok := s.canalDisponivel(p.Canal)
+			if !ok {
+				continue
+			}
+			switch decidirEntregaAviso(p, prefs.Config, agora) {
+			case notificacaoImediata:
+				if dest == nil {
+					d, err := s.destinatario(ctx, uid, prefs.Config)
+					if err != nil {
+						slog.Warn("notificacao: carregar destinatário", "error", err, "usuario_id", uid)
+						break
+					}
+					dest = &d
+				}
+				if err := sender.Enviar(ctx, *dest, msg); err != nil {
+					slog.Warn("notificacao: envio de aviso falhou", "canal", p.Canal, "usuario_id", uid, "error", err)
+				}
+			case notificacaoNoDigest:
+				fid := fazendaID
+				item := &models.NotificacaoDigestItem{
+					UsuarioID: uid, Canal: p.Canal, FazendaID: &fid,
+					Titulo: msg.Titulo, Corpo: msg.Corpo, URL: msg.URL,
+				}
+				if err := s.repo.EnfileirarDigest(ctx, item); err != nil {
+					slog.Warn("notificacao: enfileirar resumo", "canal", p.Canal, "usuario_id", uid, "error", err)
+				}
+			}
+		}
+	}
+}
+
+// EnviarDigests envia um resumo por utilizador e canal com os alertas acumulados. Itens de canais que
+// deixaram de estar configurados são descartados; falhas de envio ficam para a próxima execução.
+func (s *NotificacaoService) EnviarDigests(ctx context.Context) (enviados int, err error) {
+	agora := s.now()
+	itens, err := s.repo.ListDigestPendentes(ctx, agora)
+	if err != nil {
+		return 0, err
+	}
+	for inicio := 0; inicio < len(itens); {
+		fim := inicio
+		for fim < len(itens) && itens[fim].UsuarioID == itens[inicio].UsuarioID && itens[fim].Canal == itens[inicio].Canal {
+			fim++
+		}
+		grupo := itens[inicio:fim]
+		inicio = fim
+		if s.enviarDigestGrupo(ctx, grupo, agora) {
+			enviados++
+		}
+	}
+	if _, err := s.repo.PurgarDigestEnviados(ctx, agora.AddDate(0, 0, -30)); err != nil {
+		slog.Warn("notificacao: purgar resumos enviados", "error", err)
+	}
+	return enviados, nil
+}
+
+func (s *NotificacaoService) enviarDigestGrupo(ctx context.Context, grupo []*models.NotificacaoDigestItem, agora time.Time) bool {
+	uid, canal := grupo[0].UsuarioID, grupo[0].Canal
+	ids := make([]int64, len(grupo))
+	for i, it := range grupo {
+		ids[i] = it.ID
+	}
+	sender, ok := s.canalDisponivel(canal)
+	if !ok {
+		slog.Warn("notificacao: resumo descartado, canal indisponível", "canal", canal, "usuario_id", uid, "itens", len(grupo))
+		if err := s.repo.MarcarDigestEnviados(ctx, ids, agora); err != nil {
+			slog.Warn("notificacao: marcar resumo", "error", err)
+		}
+		return false
+	}
+	cfg, err := s.repo.GetConfig(ctx, uid)
+	if err != nil {
+		slog.Warn("notificacao: carregar contacto do resumo", "error", err, "usuario_id", uid)
+		return false
+	}
+	if cfg == nil {
+		padrao := models.NotificacaoConfigPadrao(uid)
+		cfg = &padrao
+	}
+	dest, err := s.destinatario(ctx, uid, *cfg)
+	if err != nil {
+		slog.Warn("notificacao: carregar destinatário do resumo", "error", err, "usuario_id", uid)
+		return false
+	}
+	if err := sender.Enviar(ctx, dest, montarDigestMensagem(grupo)); err != nil {
+		slog.Warn("notificacao: envio do resumo falhou", "canal", canal, "usuario_id", uid, "error", err)
+		return false
+	}
+	if err := s.repo.MarcarDigestEnviados(ctx, ids, agora); err != nil {
+		slog.Warn("notificacao: marcar resumo", "error", err)
+	}
+	return true
+}
+
+func montarDigestMensagem(grupo []*models.NotificacaoDigestItem) NotificacaoMensagem {
+	titulo := "Resumo de alertas — 1 alerta"
+	if len(grupo) != 1 {
+		titulo = fmt.Sprintf("Resumo de alertas — %d alertas", len(grupo))
+	}
+	var b strings.Builder
+	for i, it := range grupo {
+		if i == notificacaoDigestMaxLinhas {
+			fmt.Fprintf(&b, "+%d outros\n", len(grupo)-i)
+			break
+		}
+		fmt.Fprintf(&b, "• %s — %s\n", it.Titulo, it.Corpo)
+	}
+	return NotificacaoMensagem{Titulo: titulo, Corpo: strings.TrimRight(b.String(), "\n"), URL: "/alertas"}
+}
+
+// EnviarTeste envia uma mensagem de teste pelo canal, ignorando preferências e silêncio.
+func (s *NotificacaoService) EnviarTeste(ctx context.Context, usuarioID int64, canal string) error {
+	return s.EnviarParaUsuario(ctx, usuarioID, canal, NotificacaoMensagem{
+		Titulo: "Notificação de teste",
+		Corpo:  "Este canal está configurado para receber alertas do CeialMilk.",
+		URL:    "/alertas",
+	})
+}
+
+// EnviarParaUsuario entrega uma mensagem por um canal, sem aplicar preferências nem silêncio (mensagens
+// pedidas pelo próprio utilizador: teste, resumo agendado).
+func (s *NotificacaoService) EnviarParaUsuario(ctx context.Context, usuarioID int64, canal string, msg NotificacaoMensagem) error {
+	if !models.IsValidNotificacaoCanal(canal) {
+		return ErrNotificacaoCanalInvalido
+	}
+	sender, ok := s.canalDisponivel(canal)
+	if !ok {
+		return ErrNotificacaoCanalIndisponivel
+	}
+	cfg, err := s.repo.GetConfig(ctx, usuarioID)
+	if err != nil {
+		return err
+	}
+	if cfg == nil {
+		padrao := models.NotificacaoConfigPadrao(usuarioID)
+		cfg = &padrao
+	}
+	dest, err := s.destinatario(ctx, usuarioID, *cfg)
+	if err != nil {
+		return err
+	}
+	return sender.Enviar(ctx, dest, msg)
+}
@@ -0,0 +1,407 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+)
+
+type fakeNotificacaoStore struct {
+	cfg      map[int64]*models.NotificacaoConfigUsuario
+	prefs    map[int64][]*models.NotificacaoPreferencia
+	fila     []*models.NotificacaoDigestItem
+	enviados []int64
+	nextID   int64
+}
+
+func newFakeNotificacaoStore() *fakeNotificacaoStore {
+	return &fakeNotificacaoStore{
+		cfg:   map[int64]*models.NotificacaoConfigUsuario{},
+		prefs: map[int64][]*models.NotificacaoPreferencia{},
+	}
+}
+
+func (f *fakeNotificacaoStore) GetConfig(_ context.Context, uid int64) (*models.NotificacaoConfigUsuario, error) {
+	return f.cfg[uid], nil
+}
+
+func (f *fakeNotificacaoStore) ListPreferencias(_ context.Context, uid int64) ([]*models.NotificacaoPreferencia, error) {
+	return f.prefs[uid], nil
+}
+
+func (f *fakeNotificacaoStore) SalvarPreferencias(_ context.Context, cfg *models.NotificacaoConfigUsuario, prefs []models.NotificacaoPreferencia) error {
+	c := *cfg
+	f.cfg[cfg.UsuarioID] = &c
+	for i := range prefs {
+		p := prefs[i]
+		p.UsuarioID = cfg.UsuarioID
+		substituido := false
+		for j, g := range f.prefs[cfg.UsuarioID] {
+			if g.Canal == p.Canal {
+				f.prefs[cfg.UsuarioID][j] = &p
+				substituido = true
+			}
+		}
+		if !substituido {
+			f.prefs[cfg.UsuarioID] = append(f.prefs[cfg.UsuarioID], &p)
+		}
+	}
+	return nil
+}
+
+func (f *fakeNotificacaoStore) EnfileirarDigest(_ context.Context, item *models.NotificacaoDigestItem) error {
+	f.nextID++
+	item.ID = f.nextID
+	f.fila = append(f.fila, item)
+	return nil
+}
+
+func (f *fakeNotificacaoStore) ListDigestPendentes(_ context.Context, _ time.Time) ([]*models.NotificacaoDigestItem, error) {
+	var out []*models.NotificacaoDigestItem
+	for _, it := range f.fila {
+		pendente := true
+		for _, id := range f.enviados {
+			if id == it.ID {
+				pendente = false
+			}
+		}
+		if pendente {
+			out = append(out, it)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeNotificacaoStore) MarcarDigestEnviados(_ context.Context, ids []int64, _ time.Time) error {
+	f.enviados = append(f.enviados, ids...)
+	return nil
+}
+
+func (f *fakeNotificacaoStore) PurgarDigestEnviados(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+type fakeNotificacaoUsuarios struct{}
+
+func (fakeNotificacaoUsuarios) GetByID(_ context.Context, id int64) (*models.Usuario, error) {
+	return &models.Usuario{ID: id, Nome: "Ana", Email: "ana@example.com"}, nil
+}
+
+type fakeNotificacaoSender struct {
+	canal    string
+	enviadas []NotificacaoMensagem
+	falhar   bool
+}
+
+func (f *fakeNotificacaoSender) Canal() string { return f.canal }
+func (f *fakeNotificacaoSender) Enabled() bool { return true }
+func (f *fakeNotificacaoSender) Enviar(_ context.Context, _ NotificacaoDestinatario, msg NotificacaoMensagem) error {
+	if f.falhar {
+		return errors.New("falha simulada")
+	}
+	f.enviadas = append(f.enviadas, msg)
+	return nil
+}
+
+func newTestNotificacaoService(store *fakeNotificacaoStore, agora time.Time) *NotificacaoService {
+	return &NotificacaoService{
+		repo:        store,
+		usuarioRepo: fakeNotificacaoUsuarios{},
+		senders:     map[string]NotificacaoSender{},
+		loc:         time.UTC,
+		now:         func() time.Time { return agora },
+	}
+}
+
+func TestDecidirEntregaNotificacao(t *testing.T) {
+	agora := time.Date(2026, 10, 18, 23, 30, 0, 0, time.UTC)
+	cfg := models.NotificacaoConfigPadrao(1)
+	pref := models.NotificacaoPreferencia{Canal: models.NotificacaoCanalEmail, Ativo: true, SeveridadeMinima: models.AlertaSeveridadeAlta, Modo: models.NotificacaoModoImediato}
+
+	if got := decidirEntregaNotificacao(pref, cfg, models.AlertaTipoPartoPrevisto, models.AlertaSeveridadeMedia, agora); got != notificacaoNaoEnviar {
+		t.Fatalf("abaixo da severidade mínima: got %v", got)
+	}
+	if got := decidirEntregaNotificacao(pref, cfg, models.AlertaTipoPartoPrevisto, models.AlertaSeveridadeAlta, agora); got != notificacaoImediata {
+		t.Fatalf("alta sem silêncio: got %v", got)
+	}
+
+	comTipos := pref
+	comTipos.Tipos = []string{models.AlertaTipoCioDetectado}
+	if got := decidirEntregaNotificacao(comTipos, cfg, models.AlertaTipoPartoPrevisto, models.AlertaSeveridadeCritica, agora); got != notificacaoNaoEnviar {
+		t.Fatalf("tipo fora da lista: got %v", got)
+	}
+
+	digest := pref
+	digest.Modo = models.NotificacaoModoDigest
+	if got := decidirEntregaNotificacao(digest, cfg, models.AlertaTipoPartoPrevisto, models.AlertaSeveridadeCritica, agora); got != notificacaoNoDigest {
+		t.Fatalf("modo digest: got %v", got)
+	}
+
+	silencio := cfg
+	silencio.SilencioInicio, silencio.SilencioFim = strPtr("22:00"), strPtr("06:00")
+	if got := decidirEntregaNotificacao(pref, silencio, models.AlertaTipoPartoPrevisto, models.AlertaSeveridadeAlta, agora); got != notificacaoNoDigest {
+		t.Fatalf("alta no silêncio deve ir para o resumo: got %v", got)
+	}
+	if got := decidirEntregaNotificacao(pref, silencio, models.AlertaTipoPartoPrevisto, models.AlertaSeveridadeCritica, agora); got != notificacaoImediata {
+		t.Fatalf("crítica no silêncio (permitida) deve ser imediata: got %v", got)
+	}
+	silencio.SilencioPermiteCritica = false
+	if got := decidirEntregaNotificacao(pref, silencio, models.AlertaTipoPartoPrevisto, models.AlertaSeveridadeCritica, agora); got != notificacaoNoDigest {
+		t.Fatalf("crítica no silêncio (não permitida): got %v", got)
+	}
+}
+
+func TestDecidirEntregaAviso(t *testing.T) {
+	agora := time.Date(2026, 10, 18, 23, 30, 0, 0, time.UTC)
+	cfg := models.NotificacaoConfigPadrao(1)
+	pref := models.NotificacaoPreferencia{Canal: models.NotificacaoCanalEmail, Ativo: true, SeveridadeMinima: models.AlertaSeveridadeCritica, Tipos: []string{models.AlertaTipoCioDetectado}, Modo: models.NotificacaoModoImediato}
+
+	if got := decidirEntregaAviso(pref, cfg, agora); got != notificacaoImediata {
+		t.Fatalf("aviso ignora tipos e severidade: got %v", got)
+	}
+	silencio := cfg
+	silencio.SilencioInicio, silencio.SilencioFim = strPtr("22:00"), strPtr("06:00")
+	if got := decidirEntregaAviso(pref, silencio, agora); got != notificacaoNoDigest {
+		t.Fatalf("aviso no silêncio vai para o resumo: got %v", got)
+	}
+	pref.Ativo = false
+	if got := decidirEntregaAviso(pref, cfg, agora); got != notificacaoNaoEnviar {
+		t.Fatalf("canal inativo: got %v", got)
+	}
+}
+
+func TestEmSilencioNotificacao(t *testing.T) {
+	cfg := models.NotificacaoConfigUsuario{SilencioInicio: strPtr("22:00"), SilencioFim: strPtr("06:00")}
+	casos := []struct {
+		hora, min int
+		want      bool
+	}{{21, 59, false}, {22, 0, true}, {3, 0, true}, {5, 59, true}, {6, 0, false}, {12, 0, false}}
+	for _, c := range casos {
+		agora := time.Date(2026, 10, 18, c.hora, c.min, 0, 0, time.UTC)
+		if got := emSilencioNotificacao(cfg, agora); got != c.want {
+			t.Fatalf("%02d:%02d: got %v want %v", c.hora, c.min, got, c.want)
+		}
+	}
+	diurno := models.NotificacaoConfigUsuario{SilencioInicio: strPtr("12:00"), SilencioFim: strPtr("14:00")}
+	if !emSilencioNotificacao(diurno, time.Date(2026, 10, 18, 13, 0, 0, 0, time.UTC)) {
+		t.Fatal("13:00 deve estar no silêncio 12:00–14:00")
+	}
+	if emSilencioNotificacao(models.NotificacaoConfigUsuario{}, time.Now()) {
+		t.Fatal("sem silêncio configurado")
+	}
+}
+
+func TestPutPreferencias_Validacao(t *testing.T) {
+	store := newFakeNotificacaoStore()
+	svc := newTestNotificacaoService(store, time.Now())
+	ctx := context.Background()
+
+	_, err := svc.PutPreferencias(ctx, 1, NotificacaoPreferenciasInput{Canais: []NotificacaoCanalInput{{Canal: "FAX", Ativo: true}}})
+	if !errors.Is(err, ErrNotificacaoCanalInvalido) {
+		t.Fatalf("canal inválido: %v", err)
+	}
+	_, err = svc.PutPreferencias(ctx, 1, NotificacaoPreferenciasInput{Canais: []NotificacaoCanalInput{{Canal: models.NotificacaoCanalSMS, Ativo: true}}})
+	if !errors.Is(err, ErrNotificacaoSemContato) {
+		t.Fatalf("SMS sem telefone: %v", err)
+	}
+	_, err = svc.PutPreferencias(ctx, 1, NotificacaoPreferenciasInput{Telefone: strPtr("11999")})
+	if !errors.Is(err, ErrNotificacaoTelefoneInvalido) {
+		t.Fatalf("telefone inválido: %v", err)
+	}
+	_, err = svc.PutPreferencias(ctx, 1, NotificacaoPreferenciasInput{SilencioInicio: strPtr("22:00")})
+	if !errors.Is(err, ErrNotificacaoHorarioInvalido) {
+		t.Fatalf("silêncio sem fim: %v", err)
+	}
+
+	out, err := svc.PutPreferencias(ctx, 1, NotificacaoPreferenciasInput{
+		Telefone:       strPtr("+55 11 99999 8888"),
+		SilencioInicio: strPtr("22:00"),
+		SilencioFim:    strPtr("06:00"),
+		Canais: []NotificacaoCanalInput{
+			{Canal: models.NotificacaoCanalWhatsApp, Ativo: true, Modo: models.NotificacaoModoDigest, Tipos: []string{models.AlertaTipoCioDetectado}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("PutPreferencias: %v", err)
+	}
+	if out.Config.Telefone == nil || *out.Config.Telefone != "+5511999998888" {
+		t.Fatalf("telefone normalizado: %v", out.Config.Telefone)
+	}
+	if len(out.Canais) != len(models.ValidNotificacaoCanais()) {
+		t.Fatalf("esperava todos os canais na resposta, got %d", len(out.Canais))
+	}
+	for _, c := range out.Canais {
+		switch c.Canal {
+		case models.NotificacaoCanalWhatsApp:
+			if !c.Ativo || c.Modo != models.NotificacaoModoDigest || c.SeveridadeMinima != models.AlertaSeveridadeAlta {
+				t.Fatalf("whatsapp gravado: %+v", c)
+			}
+		case models.NotificacaoCanalWebPush:
+			if !c.Ativo {
+				t.Fatal("web push continua ativo por padrão")
+			}
+		}
+	}
+}
+
+func TestDespacharAlerta_ImediatoEDigest(t *testing.T) {
+	store := newFakeNotificacaoStore()
+	store.prefs[1] = []*models.NotificacaoPreferencia{
+		{UsuarioID: 1, Canal: models.NotificacaoCanalEmail, Ativo: true, SeveridadeMinima: models.AlertaSeveridadeMedia, Modo: models.NotificacaoModoDigest},
+	}
+	store.cfg[2] = &models.NotificacaoConfigUsuario{UsuarioID: 2, SilencioInicio: strPtr("22:00"), SilencioFim: strPtr("06:00"), SilencioPermiteCritica: true}
+
+	svc := newTestNotificacaoService(store, time.Date(2026, 10, 18, 23, 0, 0, 0, time.UTC))
+	push := &fakeNotificacaoSender{canal: models.NotificacaoCanalWebPush}
+	email := &fakeNotificacaoSender{canal: models.NotificacaoCanalEmail}
+	svc.RegistrarCanal(push)
+	svc.RegistrarCanal(email)
+
+	alerta := &models.AlertaWithNames{Alerta: models.Alerta{ID: 10, FazendaID: 5, Tipo: models.AlertaTipoPartoPrevisto, Severidade: models.AlertaSeveridadeAlta}}
+	svc.DespacharAlerta(context.Background(), alerta, []int64{1, 2}, NotificacaoMensagem{Titulo: "Parto previsto", Corpo: "Vaca 12"})
+
+	// Utilizador 1: push padrão imediato + e-mail em resumo. Utilizador 2: push retido pelo silêncio.
+	if len(push.enviadas) != 1 {
+		t.Fatalf("push imediatos: got %d want 1", len(push.enviadas))
+	}
+	if len(email.enviadas) != 0 {
+		t.Fatalf("e-mail em modo digest não deve sair já: %d", len(email.enviadas))
+	}
+	if len(store.fila) != 2 {
+		t.Fatalf("itens no resumo: got %d want 2", len(store.fila))
+	}
+
+	enviados, err := svc.EnviarDigests(context.Background())
+	if err != nil {
+		t.Fatalf("EnviarDigests: %v", err)
+	}
+	if enviados != 2 || len(email.enviadas) != 1 || len(push.enviadas) != 2 {
+		t.Fatalf("resumos: enviados=%d email=%d push=%d", enviados, len(email.enviadas), len(push.enviadas))
+	}
+	if !strings.Contains(email.enviadas[0].Corpo, "Parto previsto") {
+		t.Fatalf("corpo do resumo: %q", email.enviadas[0].Corpo)
+	}
+	if again, _ := svc.EnviarDigests(context.Background()); again != 0 {
+		t.Fatalf("itens enviados não devem repetir: %d", again)
+	}
+}
+
+func TestEnviarDigests_FalhaMantemPendente(t *testing.T) {
+	store := newFakeNotificacaoStore()
+	svc := newTestNotificacaoService(store, time.Now())
+	email := &fakeNotificacaoSender{canal: models.NotificacaoCanalEmail, falhar: true}
+	svc.RegistrarCanal(email)
+	_ = store.EnfileirarDigest(context.Background(), &models.NotificacaoDigestItem{UsuarioID: 1, Canal: models.NotificacaoCanalEmail, Titulo: "x"})
+
+	if n, _ := svc.EnviarDigests(context.Background()); n != 0 {
+		t.Fatalf("envio com falha não conta: %d", n)
+	}
+	if len(store.enviados) != 0 {
+		t.Fatal("item com falha deve continuar pendente")
+	}
+	email.falhar = false
+	if n, _ := svc.EnviarDigests(context.Background()); n != 1 {
+		t.Fatalf("reenvio: %d", n)
+	}
+}
+
+func TestMontarDigestMensagem_Limite(t *testing.T) {
+	grupo := make([]*models.NotificacaoDigestItem, notificacaoDigestMaxLinhas+5)
+	for i := range grupo {
+		grupo[i] = &models.NotificacaoDigestItem{Titulo: "Alerta " + strconv.Itoa(i), Corpo: "c"}
+	}
+	msg := montarDigestMensagem(grupo)
+	if !strings.Contains(msg.Titulo, strconv.Itoa(len(grupo))) || !strings.HasSuffix(msg.Corpo, "+5 outros") {
+		t.Fatalf("resumo: %q / %q", msg.Titulo, msg.Corpo)
+	}
+}
+
+// fakeSMTPServer aceita uma mensagem sem TLS nem autenticação e devolve o conteúdo DATA.
+func fakeSMTPServer(t *testing.T) (addr string, recebido <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	ch := make(chan string, 1)
+	go func() {
+		defer ln.Close()
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		w := bufio.NewWriter(conn)
+		reply := func(s string) { _, _ = w.WriteString(s + "\r\n"); _ = w.Flush() }
+		reply("220 fake ESMTP")
+		var data strings.Builder
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 fake")
+			case strings.HasPrefix(cmd, "MAIL"), strings.HasPrefix(cmd, "RCPT"):
+				reply("250 OK")
+			case cmd == "DATA":
+				reply("354 go")
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil || l == ".\r\n" {
+						break
+					}
+					data.WriteString(l)
+				}
+				reply("250 queued")
+			case cmd == "QUIT":
+				reply("221 bye")
+				ch <- data.String()
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+	return ln.Addr().String(), ch
+}
+
+func TestSMTPSender_Enviar(t *testing.T) {
+	addr, recebido := fakeSMTPServer(t)
+	host, portStr, _ := net.SplitHostPort(addr)
+	port, _ := strconv.Atoi(portStr)
+	s := &SMTPSender{host: host, port: port, from: "alertas@example.com", baseURL: "https://app.example.com", timeout: 5 * time.Second}
+
+	err := s.Enviar(context.Background(), NotificacaoDestinatario{UsuarioID: 1, Email: "ana@example.com"},
+		NotificacaoMensagem{Titulo: "🔴 Tratamento vencido", Corpo: "Vaca 12", URL: "/alertas?tipo=TRATAMENTO_VENCIDO"})
+	if err != nil {
+		t.Fatalf("Enviar: %v", err)
+	}
+	select {
+	case msg := <-recebido:
+		for _, want := range []string{"To: ana@example.com", "Subject: =?utf-8?q?", "Vaca 12", "https://app.example.com/alertas?tipo=3DTRATAMENTO_VENCIDO"} {
+			if !strings.Contains(msg, want) {
+				t.Fatalf("mensagem sem %q:\n%s", want, msg)
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("servidor SMTP não recebeu a mensagem")
+	}
+}
+
+func TestSMTPSender_SemEmail(t *testing.T) {
+	s := &SMTPSender{host: "127.0.0.1", port: 1, from: "a@example.com"}
+	if err := s.Enviar(context.Background(), NotificacaoDestinatario{UsuarioID: 1}, NotificacaoMensagem{}); !errors.Is(err, ErrNotificacaoSemContato) {
+		t.Fatalf("got %v", err)
+	}
+}
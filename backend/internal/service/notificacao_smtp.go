@@ -0,0 +1,156 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ceialmilk/api/internal/config"
+	"github.com/ceialmilk/api/internal/models"
+)
+
+// SMTPSender entrega notificações por e-mail (canal EMAIL). Sem SMTP_USERNAME não autentica, o que
+// serve servidores locais de desenvolvimento como o Mailpit.
+type SMTPSender struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+	baseURL  string
+	timeout  time.Duration
+}
+
+func NewSMTPSender(cfg *config.Config) *SMTPSender {
+	return &SMTPSender{
+		host:     cfg.SMTPHost,
+		port:     cfg.SMTPPort,
+		username: cfg.SMTPUsername,
+		password: cfg.SMTPPassword,
+		from:     cfg.SMTPFrom,
+		baseURL:  cfg.AppBaseURL,
+		timeout:  15 * time.Second,
+	}
+}
+
+func (s *SMTPSender) Canal() string {
+	return models.NotificacaoCanalEmail
+}
+
+func (s *SMTPSender) Enabled() bool {
+	return s.host != "" && s.from != ""
+}
+
+func (s *SMTPSender) Enviar(ctx context.Context, dest NotificacaoDestinatario, msg NotificacaoMensagem) error {
+	if !s.Enabled() {
+		return ErrNotificacaoCanalIndisponivel
+	}
+	if strings.TrimSpace(dest.Email) == "" {
+		return ErrNotificacaoSemContato
+	}
+	corpo, err := montarEmailNotificacao(s.from, dest, msg, s.baseURL)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	addr := net.JoinHostPort(s.host, strconv.Itoa(s.port))
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("smtp: conectar: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	c, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("smtp: handshake: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: s.host, MinVersion: tls.VersionTLS12}); err != nil {
+			return fmt.Errorf("smtp: starttls: %w", err)
+		}
+	}
+	if s.username != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			return fmt.Errorf("smtp: autenticação: %w", err)
+		}
+	}
+	if err := c.Mail(s.from); err != nil {
+		return fmt.Errorf("smtp: MAIL FROM: %w", err)
+	}
+	if err := c.Rcpt(dest.Email); err != nil {
+		return fmt.Errorf("smtp: RCPT TO: %w", err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("smtp: DATA: %w", err)
+	}
+	if _, err := w.Write(corpo); err != nil {
+		_ = w.Close()
+		return fmt.Errorf("smtp: escrever mensagem: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp: concluir mensagem: %w", err)
+	}
+	return c.Quit()
+}
+
+// montarEmailNotificacao gera a mensagem RFC 5322 em texto simples UTF-8 (quoted-printable).
+func montarEmailNotificacao(from string, dest NotificacaoDestinatario, msg NotificacaoMensagem, baseURL string) ([]byte, error) {
+	if strings.ContainsAny(dest.Email, "\r\n") || strings.ContainsAny(from, "\r\n") {
+		return nil, errors.New("smtp: endereço inválido")
+	}
+	assunto := strings.Join(strings.Fields(msg.Titulo), " ")
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", dest.Email)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", assunto))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	texto := msg.Corpo
+	if link := linkNotificacao(baseURL, msg.URL); link != "" {
+		texto += "\n\n" + link
+	}
+	qp := quotedprintable.NewWriter(&b)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(texto, "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+	b.WriteString("\r\n")
+	return b.Bytes(), nil
+}
+
+// linkNotificacao torna absoluta a URL relativa do frontend (sem APP_BASE_URL o link é omitido).
+func linkNotificacao(baseURL, url string) string {
+	if url == "" {
+		return ""
+	}
+	if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+		return url
+	}
+	if baseURL == "" {
+		return ""
+	}
+	return strings.TrimRight(baseURL, "/") + "/" + strings.TrimLeft(url, "/")
+}
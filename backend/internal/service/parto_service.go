@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 
 	"github.com/ceialmilk/api/internal/models"
@@ -16,6 +17,7 @@ var ErrPartoNotFound = errors.New("parto nao encontrado")
 var ErrPartoCriasCountMismatch = errors.New("quantidade de crias diverge de numero_crias")
 
 type PartoService struct {
+	auditavel
 	pool         *pgxpool.Pool
 	repo         *repository.PartoRepository
 	animalRepo   *repository.AnimalRepository
@@ -172,6 +174,11 @@ func (s *PartoService) applyAfterPartoCreateTx(ctx context.Context, tx pgx.Tx, p
 	if err := s.resolveGestacaoIDTx(ctx, tx, p); err != nil {
 		return err
 	}
+	return s.abrirLactacaoDoPartoTx(ctx, tx, p)
+}
+
+// abrirLactacaoDoPartoTx encerra a lactação em andamento na data do parto e abre a nova (BR-CICLO-007).
+func (s *PartoService) abrirLactacaoDoPartoTx(ctx context.Context, tx pgx.Tx, p *models.Parto) error {
 	if err := EncerrarLactacaoAtivaTx(ctx, tx, s.lactacaoRepo, p.AnimalID, p.Data); err != nil {
 		return err
 	}
@@ -196,6 +203,7 @@ func (s *PartoService) Create(ctx context.Context, p *models.Parto) error {
 	if err := s.repo.Create(ctx, p); err != nil {
 		return err
 	}
+	s.auditar(ctx, models.AuditoriaAcaoCreate, models.AuditoriaEntidadeParto, p.ID, p.FazendaID, p.AnimalID, nil, p)
 	return s.applyAfterPartoCreate(ctx, p, animal)
 }
 
@@ -229,16 +237,23 @@ func (s *PartoService) CreateWithCrias(ctx context.Context, p *models.Parto, cri
 	if err := s.applyAfterPartoCreateTx(ctx, tx, p, animal); err != nil {
 		return err
 	}
-	for _, c := range crias {
+	gerados := make([]*models.Animal, len(crias))
+	for i, c := range crias {
 		c.PartoID = p.ID
-		if err := s.criaSvc.createCriaAsPartOfPartoTx(ctx, tx, p, c); err != nil {
+		gerado, err := s.criaSvc.createCriaAsPartOfPartoTx(ctx, tx, p, c)
+		if err != nil {
 			return err
 		}
+		gerados[i] = gerado
 	}
 	if err := tx.Commit(ctx); err != nil {
 		return err
 	}
 	committed = true
+	s.auditar(ctx, models.AuditoriaAcaoCreate, models.AuditoriaEntidadeParto, p.ID, p.FazendaID, p.AnimalID, nil, p)
+	for i, c := range crias {
+		s.criaSvc.auditarCriaCriada(ctx, p, c, gerados[i])
+	}
 	return nil
 }
 
@@ -327,7 +342,11 @@ func (s *PartoService) Update(ctx context.Context, p *models.Parto) error {
 	if err := ValidatePartoAposGestacao(ctx, s.gestacaoRepo, p); err != nil {
 		return err
 	}
-	return s.repo.Update(ctx, p)
+	if err := s.repo.Update(ctx, p); err != nil {
+		return err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeParto, p.ID, p.FazendaID, p.AnimalID, existing, p)
+	return nil
 }
 
 func (s *PartoService) Delete(ctx context.Context, id int64) error {
@@ -360,10 +379,17 @@ func (s *PartoService) Delete(ctx context.Context, id int64) error {
 		}
 		return err
 	}
-	if err := s.criaSvc.DeleteAnimaisGeradosPorCriasDoPartoTx(ctx, tx, p); err != nil {
+	removidos, err := s.criaSvc.DeleteAnimaisGeradosPorCriasDoPartoTx(ctx, tx, p)
+	if err != nil {
 		return err
 	}
-	if err := s.repo.DeleteTx(ctx, tx, p.ID); err != nil {
+	var snapshot []byte
+	if len(removidos) > 0 {
+		if snapshot, err = json.Marshal(removidos); err != nil {
+			return err
+		}
+	}
+	if err := s.repo.DeleteTx(ctx, tx, p.ID, usuarioExclusao(ctx), snapshot); err != nil {
 		if errors.Is(err, repository.ErrPartoDeleteNoRows) {
 			return ErrPartoNotFound
 		}
@@ -373,5 +399,109 @@ func (s *PartoService) Delete(ctx context.Context, id int64) error {
 		return err
 	}
 	committed = true
+	s.auditar(ctx, models.AuditoriaAcaoDelete, models.AuditoriaEntidadeParto, p.ID, p.FazendaID, p.AnimalID, p, nil)
+	for _, r := range removidos {
+		s.auditar(ctx, models.AuditoriaAcaoDelete, models.AuditoriaEntidadeAnimal, r.Animal.ID, r.Animal.FazendaID, r.Animal.ID, r.Animal, nil)
+	}
 	return nil
 }
+
+// Restaurar retira o parto da lixeira: revalida as regras de registo (integridade e temporais),
+// recria os animais gerados pelas crias, marca a gestação como PARTO_REALIZADO, reabre a lactação
+// quando o parto é o mais recente e ainda não há lactação ligada a ele, e recalcula o status reprodutivo.
+func (s *PartoService) Restaurar(ctx context.Context, id int64) (*models.Parto, error) {
+	p, err := s.repo.GetExcluidoByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPartoNotFound
+		}
+		return nil, err
+	}
+	animal, err := s.validatePartoAnimalForCreate(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	p, snapshot, err := s.repo.GetExcluidoByIDForUpdateTx(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPartoNotFound
+		}
+		return nil, err
+	}
+	if err := s.repo.RestaurarTx(ctx, tx, p.ID); err != nil {
+		return nil, err
+	}
+	var removidos []criaAnimalRemovido
+	if len(snapshot) > 0 {
+		if err := json.Unmarshal(snapshot, &removidos); err != nil {
+			return nil, err
+		}
+	}
+	restaurados, err := s.criaSvc.RestaurarAnimaisGeradosTx(ctx, tx, p, removidos)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.reaplicarPartoRestauradoTx(ctx, tx, p, animal); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	committed = true
+	s.auditar(ctx, models.AuditoriaAcaoRestore, models.AuditoriaEntidadeParto, p.ID, p.FazendaID, p.AnimalID, nil, p)
+	for _, a := range restaurados {
+		s.auditar(ctx, models.AuditoriaAcaoRestore, models.AuditoriaEntidadeAnimal, a.ID, a.FazendaID, a.ID, nil, a)
+	}
+	if err := RecalcularStatusReprodutivo(ctx, s.animalRepo, p.AnimalID); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// reaplicarPartoRestauradoTx refaz os efeitos do parto restaurado; qualquer falha desfaz o restauro
+// (a vaca não pode ficar com categoria ou lactação fora de sincronia com o parto).
+func (s *PartoService) reaplicarPartoRestauradoTx(ctx context.Context, tx pgx.Tx, p *models.Parto, animal *models.Animal) error {
+	partos, err := s.repo.GetByAnimalIDTx(ctx, tx, p.AnimalID)
+	if err != nil {
+		return err
+	}
+	if len(partos) == 1 && !animal.IsMatriz() {
+		matriz := models.CategoriaMatriz
+		if err := s.animalRepo.UpdateCategoriaTx(ctx, tx, p.AnimalID, &matriz); err != nil {
+			return err
+		}
+	}
+	if p.GestacaoID != nil {
+		g, err := s.gestacaoRepo.GetByIDTx(ctx, tx, *p.GestacaoID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+		if g != nil && g.Status != models.GestacaoStatusPartoRealizado {
+			g.Status = models.GestacaoStatusPartoRealizado
+			if err := s.gestacaoRepo.UpdateTx(ctx, tx, g); err != nil {
+				return err
+			}
+		}
+	}
+	temLactacao, err := s.lactacaoRepo.ExistsByPartoIDTx(ctx, tx, p.ID)
+	if err != nil || temLactacao {
+		return err
+	}
+	maisRecente, err := s.repo.IsMaisRecenteTx(ctx, tx, p)
+	if err != nil || !maisRecente {
+		return err
+	}
+	return s.abrirLactacaoDoPartoTx(ctx, tx, p)
+}
@@ -16,6 +16,8 @@ var (
 )
 
 type ProducaoService struct {
+	auditavel
+	publicaEventos
 	repo         *repository.ProducaoRepository
 	animalRepo   *repository.AnimalRepository
 	lactacaoRepo *repository.LactacaoRepository
@@ -44,55 +46,70 @@ func (s *ProducaoService) resolveLactacaoID(
 	return &lact.ID, nil
 }
 
-func (s *ProducaoService) Create(ctx context.Context, producao *models.ProducaoLeite) error {
-	// Validações básicas
-	if producao.AnimalID <= 0 {
-		return errors.New("animal_id é obrigatório")
-	}
-	if producao.Quantidade <= 0 {
-		return errors.New("quantidade deve ser maior que zero")
-	}
-	if producao.DataHora.IsZero() {
-		producao.DataHora = time.Now()
-	}
-
+// validateProducaoParaRegistro aplica as regras de registo (criação e restauração da lixeira)
+// e resolve a lactação que cobre a data do registo.
+func (s *ProducaoService) validateProducaoParaRegistro(ctx context.Context, producao *models.ProducaoLeite) (*models.Animal, error) {
 	// Verificar se o animal existe
 	animal, err := s.animalRepo.GetByID(ctx, producao.AnimalID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return ErrAnimalNotFound
+			return nil, ErrAnimalNotFound
 		}
-		return err
+		return nil, err
 	}
 	if err := EnsureAnimalNoRebanho(animal); err != nil {
-		return err
+		return nil, err
 	}
 	if err := ValidateElegibilidadeReprodutiva(animal, producao.DataHora); err != nil {
-		return err
+		return nil, err
 	}
 	if err := ValidateEventoDateTimeTemporal(animal, producao.DataHora); err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := ValidateLactacaoAtivaParaProducao(ctx, s.lactacaoRepo, animal.FazendaID, producao.AnimalID, producao.DataHora); err != nil {
-		return err
+		return nil, err
 	}
 	if err := ValidateProducaoDentroLactacao(ctx, s.lactacaoRepo, animal.FazendaID, producao.AnimalID, producao.DataHora); err != nil {
-		return err
+		return nil, err
 	}
 
 	lactacaoID, err := s.resolveLactacaoID(ctx, animal.FazendaID, producao.AnimalID, producao.DataHora)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	producao.LactacaoID = lactacaoID
 
 	// Validar qualidade se fornecida (1-10)
 	if producao.Qualidade != nil && (*producao.Qualidade < 1 || *producao.Qualidade > 10) {
-		return errors.New("qualidade deve estar entre 1 e 10")
+		return nil, errors.New("qualidade deve estar entre 1 e 10")
+	}
+	return animal, nil
+}
+
+func (s *ProducaoService) Create(ctx context.Context, producao *models.ProducaoLeite) error {
+	// Validações básicas
+	if producao.AnimalID <= 0 {
+		return errors.New("animal_id é obrigatório")
+	}
+	if producao.Quantidade <= 0 {
+		return errors.New("quantidade deve ser maior que zero")
+	}
+	if producao.DataHora.IsZero() {
+		producao.DataHora = time.Now()
 	}
 
-	return s.repo.Create(ctx, producao)
+	animal, err := s.validateProducaoParaRegistro(ctx, producao)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Create(ctx, producao); err != nil {
+		return err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoCreate, models.AuditoriaEntidadeProducaoLeite, producao.ID, animal.FazendaID, producao.AnimalID, nil, producao)
+	s.publicarEvento(ctx, models.EventoEntidadeProducao, models.AuditoriaAcaoCreate, animal.FazendaID, producao.ID, producao.AnimalID)
+	return nil
 }
 
 func (s *ProducaoService) GetByID(ctx context.Context, id int64) (*models.ProducaoLeite, error) {
@@ -208,12 +225,17 @@ func (s *ProducaoService) Update(ctx context.Context, producao *models.ProducaoL
 		return errors.New("qualidade deve estar entre 1 e 10")
 	}
 
-	return s.repo.Update(ctx, producao)
+	if err := s.repo.Update(ctx, producao); err != nil {
+		return err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeProducaoLeite, producao.ID, animal.FazendaID, producao.AnimalID, existing, producao)
+	s.publicarEvento(ctx, models.EventoEntidadeProducao, models.AuditoriaAcaoUpdate, animal.FazendaID, producao.ID, producao.AnimalID)
+	return nil
 }
 
 func (s *ProducaoService) Delete(ctx context.Context, id int64) error {
 	// Verificar se existe
-	_, err := s.repo.GetByID(ctx, id)
+	existing, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return ErrProducaoNotFound
@@ -221,7 +243,44 @@ func (s *ProducaoService) Delete(ctx context.Context, id int64) error {
 		return err
 	}
 
-	return s.repo.Delete(ctx, id)
+	if err := s.repo.Delete(ctx, id, usuarioExclusao(ctx)); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrProducaoNotFound
+		}
+		return err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoDelete, models.AuditoriaEntidadeProducaoLeite, id, 0, existing.AnimalID, existing, nil)
+	if s.eventos != nil {
+		if animal, err := s.animalRepo.GetByID(ctx, existing.AnimalID); err == nil {
+			s.publicarEvento(ctx, models.EventoEntidadeProducao, models.AuditoriaAcaoDelete, animal.FazendaID, id, existing.AnimalID)
+		}
+	}
+	return nil
+}
+
+// Restaurar retira o registo da lixeira após revalidar a lactação na data (BR-CICLO-007);
+// o vínculo lactacao_id é recalculado, pois a lactação pode ter mudado desde a exclusão.
+func (s *ProducaoService) Restaurar(ctx context.Context, id int64) (*models.ProducaoLeite, error) {
+	producao, err := s.repo.GetExcluidoByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrProducaoNotFound
+		}
+		return nil, err
+	}
+	animal, err := s.validateProducaoParaRegistro(ctx, producao)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Restaurar(ctx, id, producao.LactacaoID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrProducaoNotFound
+		}
+		return nil, err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoRestore, models.AuditoriaEntidadeProducaoLeite, producao.ID, animal.FazendaID, producao.AnimalID, nil, producao)
+	s.publicarEvento(ctx, models.EventoEntidadeProducao, models.AuditoriaAcaoRestore, animal.FazendaID, producao.ID, producao.AnimalID)
+	return producao, nil
 }
 
 func (s *ProducaoService) Count(ctx context.Context) (int64, error) {
@@ -46,6 +46,7 @@ type PushNotificationService struct {
 	fazendaRepo *repository.FazendaRepository
 	alertaRepo  *repository.AlertaRepository
 	enabled     bool
+	notificacao *NotificacaoService
 }
 
 func NewPushNotificationService(
@@ -71,6 +72,36 @@ func (s *PushNotificationService) Enabled() bool {
 	return s.enabled
 }
 
+// SetNotificacaoService passa a entrega de alertas para o despacho multi-canal (BR-ALERTA-021): a
+// severidade mínima e o canal deixam de ser fixos e seguem as preferências de cada utilizador.
+func (s *PushNotificationService) SetNotificacaoService(n *NotificacaoService) {
+	s.notificacao = n
+}
+
+// Canal identifica o Web Push como canal de notificação.
+func (s *PushNotificationService) Canal() string {
+	return models.NotificacaoCanalWebPush
+}
+
+// Enviar entrega a mensagem em todas as subscrições do utilizador.
+func (s *PushNotificationService) Enviar(ctx context.Context, dest NotificacaoDestinatario, msg NotificacaoMensagem) error {
+	if !s.enabled {
+		return ErrPushNotConfigured
+	}
+	payload, err := buildPushPayload(msg)
+	if err != nil {
+		return err
+	}
+	subs, err := s.subRepo.ListByUsuarioID(ctx, dest.UsuarioID)
+	if err != nil {
+		return err
+	}
+	for _, sub := range subs {
+		s.sendOne(ctx, sub, payload)
+	}
+	return nil
+}
+
 func (s *PushNotificationService) GetVapidPublicKey() (string, error) {
 	if !s.enabled {
 		return "", ErrPushNotConfigured
@@ -115,20 +146,57 @@ func (s *PushNotificationService) UpdateFazendaAtiva(ctx context.Context, usuari
 }
 
 func (s *PushNotificationService) NotifyAlertaCreated(alerta *models.AlertaWithNames) {
-	if alerta == nil || !s.enabled {
+	if alerta == nil {
+		return
+	}
+	if s.notificacao != nil {
+		go s.despacharAlerta(alerta, nil)
+		return
+	}
+	if !s.enabled {
 		return
 	}
 	if !models.ShouldNotifyPushForSeveridade(alerta.Severidade) {
 		return
 	}
-	go s.dispatchAlertaPush(alerta)
+	go s.dispatchAlertaPush(alerta, nil)
+}
+
+// NotifyAlertaCreatedParaPerfis envia o push do alerta apenas a utilizadores com os perfis indicados
+// (configuração da regra, BR-ALERTA-019). Lista vazia = sem push.
+func (s *PushNotificationService) NotifyAlertaCreatedParaPerfis(alerta *models.AlertaWithNames, perfis []string) {
+	if alerta == nil || len(perfis) == 0 {
+		return
+	}
+	if s.notificacao != nil {
+		go s.despacharAlerta(alerta, perfis)
+		return
+	}
+	if !s.enabled {
+		return
+	}
+	if !models.ShouldNotifyPushForSeveridade(alerta.Severidade) {
+		return
+	}
+	go s.dispatchAlertaPush(alerta, perfis)
+}
+
+// NotifyAlertaEscalonado avisa os perfis do nível de escalonamento de que o alerta passou do prazo
+// (BR-ALERTA-023); segue as mesmas preferências de canal do alerta original.
+func (s *PushNotificationService) NotifyAlertaEscalonado(alerta *models.AlertaWithNames, perfis []string) {
+	if alerta == nil {
+		return
+	}
+	escalonado := *alerta
+	escalonado.Titulo = "Prazo ultrapassado: " + alerta.Titulo
+	s.NotifyAlertaCreatedParaPerfis(&escalonado, perfis)
 }
 
-func (s *PushNotificationService) dispatchAlertaPush(alerta *models.AlertaWithNames) {
+func (s *PushNotificationService) dispatchAlertaPush(alerta *models.AlertaWithNames, perfis []string) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	userIDs, err := s.fazendaRepo.ListUsuarioIDsForAlertaPush(ctx, alerta.FazendaID)
+	userIDs, err := s.fazendaRepo.ListUsuarioIDsForAlertaPush(ctx, alerta.FazendaID, perfis)
 	if err != nil {
 		slog.Warn("push: listar destinatários", "error", err, "fazenda_id", alerta.FazendaID)
 		return
@@ -143,7 +211,7 @@ func (s *PushNotificationService) dispatchAlertaPush(alerta *models.AlertaWithNa
 		badgeCount = 0
 	}
 
-	payload, err := s.buildPayload(alerta, badgeCount)
+	payload, err := buildPushPayload(alertaNotificacaoMensagem(alerta, badgeCount))
 	if err != nil {
 		slog.Warn("push: montar payload", "error", err)
 		return
@@ -161,7 +229,53 @@ func (s *PushNotificationService) dispatchAlertaPush(alerta *models.AlertaWithNa
 	}
 }
 
-func (s *PushNotificationService) buildPayload(alerta *models.AlertaWithNames, badgeCount int64) ([]byte, error) {
+func (s *PushNotificationService) despacharAlerta(alerta *models.AlertaWithNames, perfis []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	userIDs, err := s.fazendaRepo.ListUsuarioIDsForAlertaNotificacao(ctx, alerta.FazendaID, perfis)
+	if err != nil {
+		slog.Warn("notificacao: listar destinatários", "error", err, "fazenda_id", alerta.FazendaID)
+		return
+	}
+	if len(userIDs) == 0 {
+		return
+	}
+	badgeCount, err := s.alertaRepo.CountCriticosAbertosByFazenda(ctx, alerta.FazendaID)
+	if err != nil {
+		slog.Warn("push: contar críticos abertos", "error", err)
+		badgeCount = 0
+	}
+	s.notificacao.DespacharAlerta(ctx, alerta, userIDs, alertaNotificacaoMensagem(alerta, badgeCount))
+}
+
+// NotifyUsuarios envia um push genérico (fora do fluxo de alertas por fazenda) aos utilizadores indicados.
+func (s *PushNotificationService) NotifyUsuarios(userIDs []int64, title, body, url string) {
+	if !s.enabled || len(userIDs) == 0 {
+		return
+	}
+	payload, err := buildPushPayload(NotificacaoMensagem{Titulo: title, Corpo: body, URL: url})
+	if err != nil {
+		slog.Warn("push: montar payload", "error", err)
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		for _, uid := range userIDs {
+			subs, err := s.subRepo.ListByUsuarioID(ctx, uid)
+			if err != nil {
+				slog.Warn("push: listar subscriptions", "error", err, "usuario_id", uid)
+				continue
+			}
+			for _, sub := range subs {
+				s.sendOne(ctx, sub, payload)
+			}
+		}
+	}()
+}
+
+func alertaNotificacaoMensagem(alerta *models.AlertaWithNames, badgeCount int64) NotificacaoMensagem {
 	prefix := models.SeveridadePushPrefix(alerta.Severidade)
 	title := alerta.Titulo
 	if prefix != "" {
@@ -173,13 +287,22 @@ func (s *PushNotificationService) buildPayload(alerta *models.AlertaWithNames, b
 		body = body + " — " + strings.TrimSpace(*alerta.AnimalIdentificacao)
 	}
 
+	return NotificacaoMensagem{
+		Titulo:     title,
+		Corpo:      body,
+		URL:        fmt.Sprintf("/alertas?tipo=%s", alerta.Tipo),
+		BadgeCount: badgeCount,
+	}
+}
+
+func buildPushPayload(msg NotificacaoMensagem) ([]byte, error) {
 	var p pushPayload
-	p.Title = title
-	p.Body = body
+	p.Title = msg.Titulo
+	p.Body = msg.Corpo
 	p.Icon = "/icons/icon-192.svg"
 	p.Badge = "/icons/icon-192.svg"
-	p.Data.URL = fmt.Sprintf("/alertas?tipo=%s", alerta.Tipo)
-	p.Data.BadgeCount = badgeCount
+	p.Data.URL = msg.URL
+	p.Data.BadgeCount = msg.BadgeCount
 	return json.Marshal(p)
 }
 
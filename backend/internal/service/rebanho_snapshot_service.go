@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/repository"
+)
+
+var ErrRebanhoSnapshotDataFutura = errors.New("a data do snapshot não pode ser futura")
+
+type rebanhoSnapshotStore interface {
+	ListByFazendaNaData(ctx context.Context, fazendaID int64, data time.Time) ([]*repository.RebanhoSnapshotLinha, error)
+}
+
+type rebanhoSnapshotProducaoStore interface {
+	SumLitrosByFazendaBetween(ctx context.Context, fazendaID int64, start, end time.Time) (float64, error)
+}
+
+// RebanhoSnapshotService reconstrói o rebanho de uma fazenda numa data passada a partir dos eventos
+// (BR-CICLO-021): categoria, status reprodutivo e de saúde, lote e lactação por animal, mais agregados.
+type RebanhoSnapshotService struct {
+	repo         rebanhoSnapshotStore
+	producaoRepo rebanhoSnapshotProducaoStore
+}
+
+func NewRebanhoSnapshotService(repo *repository.RebanhoSnapshotRepository, producaoRepo *repository.ProducaoRepository) *RebanhoSnapshotService {
+	return &RebanhoSnapshotService{repo: repo, producaoRepo: producaoRepo}
+}
+
+// Build devolve o rebanho no fim do dia civil data (hoje ou anterior).
+func (s *RebanhoSnapshotService) Build(ctx context.Context, fazendaID int64, data time.Time) (*models.RebanhoSnapshot, error) {
+	y, m, d := data.Date()
+	dia := time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+	if dia.After(CivilToday()) {
+		return nil, ErrRebanhoSnapshotDataFutura
+	}
+	linhas, err := s.repo.ListByFazendaNaData(ctx, fazendaID, dia)
+	if err != nil {
+		return nil, err
+	}
+
+	fimDia := dia.AddDate(0, 0, 1)
+	producaoDia, err := s.producaoRepo.SumLitrosByFazendaBetween(ctx, fazendaID, dia, fimDia)
+	if err != nil {
+		return nil, err
+	}
+	producaoSemana, err := s.producaoRepo.SumLitrosByFazendaBetween(ctx, fazendaID, dia.AddDate(0, 0, -6), fimDia)
+	if err != nil {
+		return nil, err
+	}
+
+	animais := make([]*models.RebanhoSnapshotAnimal, 0, len(linhas))
+	for _, ln := range linhas {
+		animais = append(animais, snapshotAnimalNaData(ln, dia))
+	}
+	resumo := resumirRebanhoSnapshot(animais, dia)
+	resumo.ProducaoDiaLitros = producaoDia
+	resumo.ProducaoSemanaLitros = producaoSemana
+
+	return &models.RebanhoSnapshot{
+		FazendaID: fazendaID,
+		Data:      dia.Format("2006-01-02"),
+		Animais:   animais,
+		Resumo:    resumo,
+	}, nil
+}
+
+// snapshotAnimalNaData deriva o estado do animal na data a partir dos factos lidos do repositório.
+func snapshotAnimalNaData(ln *repository.RebanhoSnapshotLinha, dia time.Time) *models.RebanhoSnapshotAnimal {
+	casos := make([]*models.AnimalSaude, 0, len(ln.CasosSaudeTipos))
+	for _, tipo := range ln.CasosSaudeTipos {
+		casos = append(casos, &models.AnimalSaude{TipoCaso: tipo})
+	}
+	a := &models.RebanhoSnapshotAnimal{
+		AnimalID:         ln.AnimalID,
+		Identificacao:    ln.Identificacao,
+		Sexo:             ln.Sexo,
+		Categoria:        categoriaNaData(ln.Sexo, ln.CategoriaAtual, ln.DataNascimento, ln.PrimeiroParto, dia),
+		StatusSaude:      deriveAnimalStatusSaudeFromCasosAtivos(casos),
+		LoteID:           ln.LoteID,
+		LoteNome:         ln.LoteNome,
+		EmLactacao:       ln.EmLactacao,
+		EmRestricaoLeite: ln.EmRestricaoLeite,
+		GestacaoAtiva:    ln.GestacaoAtiva,
+	}
+	if ln.GestacaoAtiva {
+		a.DataPrevistaParto = ln.DataPrevistaParto
+	}
+	if !snapshotIsMacho(ln.Sexo, ln.CategoriaAtual) {
+		marco := ln.Marco
+		if marco == nil && ln.GestacaoAtiva {
+			// Gestação registada sem marcos (ex.: matriz comprada prenhe).
+			marco = &repository.MarcoReprodutivo{GestacaoAtiva: true}
+		}
+		a.StatusReprodutivo = derivarStatusReprodutivo(marco)
+	}
+	return a
+}
+
+func snapshotIsMacho(sexo, categoria *string) bool {
+	if sexo != nil {
+		return *sexo == models.SexoMacho
+	}
+	if categoria == nil {
+		return false
+	}
+	switch *categoria {
+	case models.CategoriaBezerro, models.CategoriaTouro, models.CategoriaBoi:
+		return true
+	}
+	return false
+}
+
+// categoriaNaData estima a categoria na data (não há histórico de categoria):
+// fêmea com parto até a data é MATRIZ; sem parto até a data é BEZERRA/NOVILHA pela idade
+// (IdadeMinimaMesesBezerraNovilha); MATRIZ sem partos registados continua MATRIZ (compra).
+// Machos adultos mantêm a categoria atual e antes da idade mínima são BEZERRO.
+func categoriaNaData(sexo, categoriaAtual *string, nascimento, primeiroParto *time.Time, dia time.Time) *string {
+	adulto := func() (bool, bool) {
+		if nascimento == nil {
+			return false, false
+		}
+		limite := TruncateToCivilDate(*nascimento).AddDate(0, IdadeMinimaMesesBezerraNovilha, 0)
+		return !dia.Before(limite), true
+	}
+	cat := func(c string) *string { return &c }
+
+	if snapshotIsMacho(sexo, categoriaAtual) {
+		if ok, conhecido := adulto(); conhecido && !ok {
+			return cat(models.CategoriaBezerro)
+		}
+		return categoriaAtual
+	}
+	if sexo == nil && categoriaAtual == nil {
+		return nil
+	}
+	if primeiroParto != nil && !civilAfter(*primeiroParto, dia) {
+		return cat(models.CategoriaMatriz)
+	}
+	if primeiroParto == nil && categoriaAtual != nil && *categoriaAtual == models.CategoriaMatriz {
+		return categoriaAtual
+	}
+	ok, conhecido := adulto()
+	switch {
+	case conhecido && ok:
+		return cat(models.CategoriaNovilha)
+	case conhecido:
+		return cat(models.CategoriaBezerra)
+	case categoriaAtual != nil && *categoriaAtual == models.CategoriaBezerra:
+		return categoriaAtual
+	default:
+		return cat(models.CategoriaNovilha)
+	}
+}
+
+// resumirRebanhoSnapshot agrega os animais da data (produção é preenchida pelo chamador).
+func resumirRebanhoSnapshot(animais []*models.RebanhoSnapshotAnimal, dia time.Time) models.RebanhoSnapshotResumo {
+	r := models.RebanhoSnapshotResumo{
+		TotalAnimais:         len(animais),
+		PorCategoria:         map[string]int{},
+		PorStatusReprodutivo: map[string]int{},
+		PorStatusSaude:       map[string]int{},
+		PorLote:              []models.RebanhoSnapshotLote{},
+	}
+	ate7d := dia.AddDate(0, 0, 7)
+	lotes := map[int64]int{} // lote_id -> índice em PorLote; 0 = sem lote
+	for _, a := range animais {
+		if a.GestacaoAtiva {
+			r.PrenhesTotal++
+			if a.DataPrevistaParto != nil && !civilBefore(*a.DataPrevistaParto, dia) && civilBefore(*a.DataPrevistaParto, ate7d) {
+				r.PartosProximos7dTotal++
+			}
+		}
+		if a.EmLactacao {
+			r.LactacaoAtivaTotal++
+		}
+		if a.EmRestricaoLeite {
+			r.RestricoesAtivasTotal++
+		}
+		if a.Categoria != nil {
+			r.PorCategoria[*a.Categoria]++
+		}
+		if a.StatusReprodutivo != nil {
+			r.PorStatusReprodutivo[*a.StatusReprodutivo]++
+		}
+		r.PorStatusSaude[a.StatusSaude]++
+
+		var chave int64
+		if a.LoteID != nil {
+			chave = *a.LoteID
+		}
+		idx, ok := lotes[chave]
+		if !ok {
+			idx = len(r.PorLote)
+			lotes[chave] = idx
+			r.PorLote = append(r.PorLote, models.RebanhoSnapshotLote{LoteID: a.LoteID, LoteNome: a.LoteNome})
+		}
+		r.PorLote[idx].Total++
+	}
+	sort.SliceStable(r.PorLote, func(i, j int) bool {
+		return r.PorLote[i].Total > r.PorLote[j].Total
+	})
+	return r
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/repository"
+)
+
+type fakeRebanhoSnapshotStore struct {
+	linhas []*repository.RebanhoSnapshotLinha
+	data   time.Time
+}
+
+func (f *fakeRebanhoSnapshotStore) ListByFazendaNaData(_ context.Context, _ int64, data time.Time) ([]*repository.RebanhoSnapshotLinha, error) {
+	f.data = data
+	return f.linhas, nil
+}
+
+type fakeRebanhoSnapshotProducao struct{ chamadas [][2]time.Time }
+
+func (f *fakeRebanhoSnapshotProducao) SumLitrosByFazendaBetween(_ context.Context, _ int64, start, end time.Time) (float64, error) {
+	f.chamadas = append(f.chamadas, [2]time.Time{start, end})
+	return float64(len(f.chamadas)) * 10, nil
+}
+
+func strp(s string) *string { return &s }
+
+func TestCategoriaNaData(t *testing.T) {
+	dia := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+	nasc := func(y int, m time.Month) *time.Time { t := time.Date(y, m, 1, 0, 0, 0, 0, time.Local); return &t }
+	parto := time.Date(2025, 3, 10, 0, 0, 0, 0, time.Local)
+	partoDepois := time.Date(2025, 8, 10, 0, 0, 0, 0, time.Local)
+
+	cases := []struct {
+		nome          string
+		sexo, atual   *string
+		nascimento    *time.Time
+		primeiroParto *time.Time
+		want          *string
+	}{
+		{"parto até a data vira matriz", strp(models.SexoFemea), strp(models.CategoriaMatriz), nasc(2022, 1), &parto, strp(models.CategoriaMatriz)},
+		{"parto depois da data era novilha", strp(models.SexoFemea), strp(models.CategoriaMatriz), nasc(2023, 1), &partoDepois, strp(models.CategoriaNovilha)},
+		{"menos de 12 meses era bezerra", strp(models.SexoFemea), strp(models.CategoriaNovilha), nasc(2024, 9), nil, strp(models.CategoriaBezerra)},
+		{"matriz comprada sem partos", strp(models.SexoFemea), strp(models.CategoriaMatriz), nil, nil, strp(models.CategoriaMatriz)},
+		{"macho jovem era bezerro", strp(models.SexoMacho), strp(models.CategoriaTouro), nasc(2025, 1), nil, strp(models.CategoriaBezerro)},
+		{"macho adulto mantém categoria", strp(models.SexoMacho), strp(models.CategoriaBoi), nasc(2020, 1), nil, strp(models.CategoriaBoi)},
+		{"sem sexo nem categoria", nil, nil, nil, nil, nil},
+	}
+	for _, tc := range cases {
+		got := categoriaNaData(tc.sexo, tc.atual, tc.nascimento, tc.primeiroParto, dia)
+		if (got == nil) != (tc.want == nil) || (got != nil && *got != *tc.want) {
+			t.Fatalf("%s: esperado %v, got %v", tc.nome, tc.want, got)
+		}
+	}
+}
+
+func TestRebanhoSnapshotBuild_DerivaEstadoEResumo(t *testing.T) {
+	ctx := context.Background()
+	dia := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+	lote := int64(7)
+	previsto := dia.AddDate(0, 0, 3)
+	partoAntigo := dia.AddDate(-1, 0, 0)
+	store := &fakeRebanhoSnapshotStore{linhas: []*repository.RebanhoSnapshotLinha{
+		{
+			AnimalID: 1, Identificacao: "V1", Sexo: strp(models.SexoFemea), CategoriaAtual: strp(models.CategoriaMatriz),
+			LoteID: &lote, LoteNome: strp("Lactação"), PrimeiroParto: &partoAntigo,
+			Marco:         &repository.MarcoReprodutivo{Tipo: "TOQUE", Resultado: models.DiagnosticoResultadoPositivo, GestacaoAtiva: true},
+			GestacaoAtiva: true, DataPrevistaParto: &previsto, EmLactacao: true,
+			CasosSaudeTipos: []string{models.AnimalSaudeTipoTratamento},
+		},
+		{
+			AnimalID: 2, Identificacao: "V2", Sexo: strp(models.SexoFemea), CategoriaAtual: strp(models.CategoriaMatriz),
+			LoteID: &lote, LoteNome: strp("Lactação"), PrimeiroParto: &partoAntigo,
+			Marco:      &repository.MarcoReprodutivo{Tipo: "COBERTURA"},
+			EmLactacao: true, EmRestricaoLeite: true,
+		},
+		{AnimalID: 3, Identificacao: "T1", Sexo: strp(models.SexoMacho), CategoriaAtual: strp(models.CategoriaTouro)},
+	}}
+	producao := &fakeRebanhoSnapshotProducao{}
+	svc := &RebanhoSnapshotService{repo: store, producaoRepo: producao}
+
+	snap, err := svc.Build(ctx, 1, dia)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if snap.Data != "2025-06-01" || !store.data.Equal(dia) {
+		t.Fatalf("data: %s / %v", snap.Data, store.data)
+	}
+	v1, v2, t1 := snap.Animais[0], snap.Animais[1], snap.Animais[2]
+	if v1.StatusReprodutivo == nil || *v1.StatusReprodutivo != models.StatusReprodutivoPrenhe || v1.StatusSaude != models.StatusTratamento {
+		t.Fatalf("V1: %+v", v1)
+	}
+	if v2.StatusReprodutivo == nil || *v2.StatusReprodutivo != models.StatusReprodutivoServida || v2.DataPrevistaParto != nil {
+		t.Fatalf("V2: %+v", v2)
+	}
+	if t1.StatusReprodutivo != nil || t1.StatusSaude != models.StatusSaudavel {
+		t.Fatalf("macho sem status reprodutivo: %+v", t1)
+	}
+
+	r := snap.Resumo
+	if r.TotalAnimais != 3 || r.PrenhesTotal != 1 || r.PartosProximos7dTotal != 1 || r.LactacaoAtivaTotal != 2 || r.RestricoesAtivasTotal != 1 {
+		t.Fatalf("resumo: %+v", r)
+	}
+	if r.PorCategoria[models.CategoriaMatriz] != 2 || r.PorCategoria[models.CategoriaTouro] != 1 {
+		t.Fatalf("por categoria: %v", r.PorCategoria)
+	}
+	if len(r.PorLote) != 2 || r.PorLote[0].Total != 2 || r.PorLote[1].LoteID != nil {
+		t.Fatalf("por lote: %+v", r.PorLote)
+	}
+	if len(producao.chamadas) != 2 || !producao.chamadas[1][0].Equal(dia.AddDate(0, 0, -6)) || !producao.chamadas[0][1].Equal(dia.AddDate(0, 0, 1)) {
+		t.Fatalf("janelas de produção: %v", producao.chamadas)
+	}
+	if r.ProducaoDiaLitros != 10 || r.ProducaoSemanaLitros != 20 {
+		t.Fatalf("produção: %v / %v", r.ProducaoDiaLitros, r.ProducaoSemanaLitros)
+	}
+}
+
+func TestRebanhoSnapshotBuild_DataFutura(t *testing.T) {
+	svc := &RebanhoSnapshotService{repo: &fakeRebanhoSnapshotStore{}, producaoRepo: &fakeRebanhoSnapshotProducao{}}
+	_, err := svc.Build(context.Background(), 1, time.Now().AddDate(0, 0, 2))
+	if !errors.Is(err, ErrRebanhoSnapshotDataFutura) {
+		t.Fatalf("esperado ErrRebanhoSnapshotDataFutura, got %v", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/ceialmilk/api/internal/models"
@@ -22,6 +23,27 @@ func NewRefreshTokenService(repo *repository.RefreshTokenRepository) *RefreshTok
 	return &RefreshTokenService{repo: repo}
 }
 
+// userAgentMaxLen corta user agents anómalos antes de gravar.
+const userAgentMaxLen = 512
+
+// OrigemSessao dispositivo que abriu ou renovou a sessão, exibido na lista de sessões (BR-ACESSO-028).
+type OrigemSessao struct {
+	UserAgent string
+	IP        string
+}
+
+func (o OrigemSessao) aplicar(rt *models.RefreshToken) {
+	if ua := strings.TrimSpace(o.UserAgent); ua != "" {
+		if len(ua) > userAgentMaxLen {
+			ua = strings.ToValidUTF8(ua[:userAgentMaxLen], "")
+		}
+		rt.UserAgent = &ua
+	}
+	if ip := strings.TrimSpace(o.IP); ip != "" {
+		rt.IP = &ip
+	}
+}
+
 // GenerateRefreshToken gera um token aleatório seguro
 func (s *RefreshTokenService) GenerateRefreshToken() (string, error) {
 	b := make([]byte, 32)
@@ -38,27 +60,30 @@ func hashRefreshToken(token string) string {
 	return hex.EncodeToString(sum[:])
 }
 
-// Create cria um novo refresh token para o usuário. O banco guarda só o hash;
-// o campo Token do retorno contém o valor em claro para ser enviado ao cliente (cookie).
-func (s *RefreshTokenService) Create(ctx context.Context, userID int64) (*models.RefreshToken, error) {
+// Create abre uma sessão nova (login) com um refresh token para o usuário, com o nível de garantia (aal).
+// O banco guarda só o hash; o campo Token do retorno contém o valor em claro para ser enviado ao cliente (cookie).
+func (s *RefreshTokenService) Create(ctx context.Context, userID int64, aal int, origem OrigemSessao) (*models.RefreshToken, error) {
+	return s.emitir(ctx, &models.RefreshToken{UserID: userID, AAL: aal}, origem)
+}
+
+// emitir grava o token de rt (sessão nova se FamiliaID zero) com validade de 7 dias.
+func (s *RefreshTokenService) emitir(ctx context.Context, rt *models.RefreshToken, origem OrigemSessao) (*models.RefreshToken, error) {
 	tokenStr, err := s.GenerateRefreshToken()
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken := &models.RefreshToken{
-		Token:     hashRefreshToken(tokenStr),
-		UserID:    userID,
-		ExpiresAt: time.Now().Add(7 * 24 * time.Hour), // 7 dias
-	}
+	rt.Token = hashRefreshToken(tokenStr)
+	rt.ExpiresAt = time.Now().Add(7 * 24 * time.Hour) // 7 dias
+	origem.aplicar(rt)
 
-	if err := s.repo.Create(ctx, refreshToken); err != nil {
+	if err := s.repo.Create(ctx, rt); err != nil {
 		return nil, err
 	}
 
 	// Devolver o valor em claro ao chamador (handler) — nunca persistido.
-	refreshToken.Token = tokenStr
-	return refreshToken, nil
+	rt.Token = tokenStr
+	return rt, nil
 }
 
 // Validate valida um refresh token (comparação por hash)
@@ -78,12 +103,18 @@ func (s *RefreshTokenService) Validate(ctx context.Context, token string) (*mode
 	return rt, nil
 }
 
-// Rotate revoga o token usado e emite um novo (rotação a cada refresh).
-func (s *RefreshTokenService) Rotate(ctx context.Context, usedToken string, userID int64) (*models.RefreshToken, error) {
+// Rotate revoga o token usado e emite um novo na mesma sessão (família, início e aal de atual),
+// com a origem da renovação (rotação a cada refresh).
+func (s *RefreshTokenService) Rotate(ctx context.Context, usedToken string, atual *models.RefreshToken, origem OrigemSessao) (*models.RefreshToken, error) {
 	if err := s.repo.Revoke(ctx, hashRefreshToken(usedToken)); err != nil {
 		return nil, err
 	}
-	return s.Create(ctx, userID)
+	return s.emitir(ctx, &models.RefreshToken{
+		UserID:           atual.UserID,
+		AAL:              atual.AAL,
+		FamiliaID:        atual.FamiliaID,
+		SessaoIniciadaEm: atual.SessaoIniciadaEm,
+	}, origem)
 }
 
 // Revoke revoga um refresh token
@@ -21,6 +21,8 @@ var (
 )
 
 type RestricaoLeiteService struct {
+	auditavel
+	publicaEventos
 	repo           *repository.RestricaoLeiteRepository
 	animalRepo     *repository.AnimalRepository
 	lactacaoRepo   *repository.LactacaoRepository
@@ -106,6 +108,8 @@ func (s *RestricaoLeiteService) Create(ctx context.Context, in CreateRestricaoLe
 		}
 		return nil, err
 	}
+	s.auditar(ctx, models.AuditoriaAcaoCreate, models.AuditoriaEntidadeRestricaoLeite, row.ID, row.FazendaID, row.AnimalID, nil, row)
+	s.publicarEvento(ctx, models.EventoEntidadeRestricaoLeite, models.AuditoriaAcaoCreate, row.FazendaID, row.ID, row.AnimalID)
 	return row, nil
 }
 
@@ -141,6 +145,8 @@ func (s *RestricaoLeiteService) Liberar(ctx context.Context, fazendaID, restrica
 	if err != nil || out == nil {
 		return nil, err
 	}
+	s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeRestricaoLeite, restricaoID, fazendaID, out.AnimalID, existing, out)
+	s.publicarEvento(ctx, models.EventoEntidadeRestricaoLeite, models.AuditoriaAcaoUpdate, fazendaID, restricaoID, out.AnimalID)
 	resolveAlertaSilencioso(ctx, s.alertaResolver, fazendaID, out.AnimalID, models.AlertaTipoRestricaoLeiteAtiva)
 	return out, nil
 }
@@ -0,0 +1,344 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/ceialmilk/api/internal/config"
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/repository"
+)
+
+var (
+	ErrResumoAlertasFrequencia = errors.New("frequência inválida (DIARIO ou SEMANAL)")
+	ErrResumoAlertasHora       = errors.New("hora inválida (0 a 23)")
+	ErrResumoAlertasDiaSemana  = errors.New("dia_semana é obrigatório no resumo semanal (0 = domingo a 6 = sábado)")
+	ErrResumoAlertasCanais     = errors.New("informe ao menos um canal válido para o resumo")
+)
+
+// resumoAlertasMaxItensAgenda limita os itens de cada secção da agenda no texto da mensagem.
+const resumoAlertasMaxItensAgenda = 5
+
+type resumoAlertasStore interface {
+	GetConfig(ctx context.Context, usuarioID int64) (*models.ResumoAlertasConfig, error)
+	UpsertConfig(ctx context.Context, c *models.ResumoAlertasConfig) error
+	ListDevidos(ctx context.Context, hora, diaSemana int, inicioDia time.Time) ([]*models.ResumoAlertasConfig, error)
+	MarcarEnviado(ctx context.Context, usuarioID int64, em time.Time) error
+}
+
+type resumoAlertasAlertaStore interface {
+	CountAbertosPorTipoByFazenda(ctx context.Context, fazendaID int64) ([]models.ResumoAlertasTipo, error)
+	ListMaisUrgentesAbertosByFazenda(ctx context.Context, fazendaID int64, limit int) ([]models.AlertaWithNames, error)
+}
+
+type resumoAlertasFazendaStore interface {
+	GetFazendasByUsuarioID(ctx context.Context, usuarioID int64) ([]*models.Fazenda, error)
+}
+
+type resumoAlertasPecuarioFonte interface {
+	Build(ctx context.Context, fazendaID int64, diasParto int) (*models.ResumoPecuario, error)
+}
+
+type resumoAlertasHormonioStore interface {
+	ListPendentesByFazendaID(ctx context.Context, fazendaID int64, refDate time.Time) ([]*models.HormonioLactacaoPendente, error)
+}
+
+type resumoAlertasVacinaStore interface {
+	ListPrevistasAteByFazendaID(ctx context.Context, fazendaID int64, ate time.Time) ([]models.VacinaPrevistaResumo, error)
+}
+
+type resumoAlertasEnvio interface {
+	EnviarParaUsuario(ctx context.Context, usuarioID int64, canal string, msg NotificacaoMensagem) error
+}
+
+// ResumoAlertasService monta e envia o resumo diário/semanal de alertas abertos e agenda do dia, na hora
+// escolhida por cada utilizador (BR-ALERTA-022).
+type ResumoAlertasService struct {
+	repo        resumoAlertasStore
+	alertaRepo  resumoAlertasAlertaStore
+	fazendaRepo resumoAlertasFazendaStore
+	pecuario    resumoAlertasPecuarioFonte
+	hormonio    resumoAlertasHormonioStore
+	vacinas     resumoAlertasVacinaStore
+	envio       resumoAlertasEnvio
+	loc         *time.Location
+	now         func() time.Time
+}
+
+func NewResumoAlertasService(
+	cfg *config.Config,
+	repo *repository.ResumoAlertasRepository,
+	alertaRepo *repository.AlertaRepository,
+	fazendaRepo *repository.FazendaRepository,
+	pecuario *ResumoPecuarioService,
+	hormonioRepo *repository.AnimalHormonioLactacaoRepository,
+	vacinaRepo *repository.AnimalVacinaRepository,
+	notificacao *NotificacaoService,
+) *ResumoAlertasService {
+	tzName := "America/Sao_Paulo"
+	if cfg != nil && cfg.AlertasTZ != "" {
+		tzName = cfg.AlertasTZ
+	}
+	loc, err := time.LoadLocation(tzName)
+	if err != nil {
+		loc = time.UTC
+	}
+	return &ResumoAlertasService{
+		repo:        repo,
+		alertaRepo:  alertaRepo,
+		fazendaRepo: fazendaRepo,
+		pecuario:    pecuario,
+		hormonio:    hormonioRepo,
+		vacinas:     vacinaRepo,
+		envio:       notificacao,
+		loc:         loc,
+		now:         time.Now,
+	}
+}
+
+// GetConfig devolve o agendamento do utilizador (padrão desligado quando nunca configurado).
+func (s *ResumoAlertasService) GetConfig(ctx context.Context, usuarioID int64) (*models.ResumoAlertasConfig, error) {
+	c, err := s.repo.GetConfig(ctx, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		padrao := models.ResumoAlertasConfigPadrao(usuarioID)
+		return &padrao, nil
+	}
+	return c, nil
+}
+
+// ResumoAlertasConfigInput corpo de PUT /me/resumo-alertas.
+type ResumoAlertasConfigInput struct {
+	Ativo      bool
+	Frequencia string // vazio = DIARIO
+	Hora       int
+	DiaSemana  *int
+	Canais     []string // vazio = WEB_PUSH
+}
+
+func (s *ResumoAlertasService) PutConfig(ctx context.Context, usuarioID int64, in ResumoAlertasConfigInput) (*models.ResumoAlertasConfig, error) {
+	c := models.ResumoAlertasConfig{UsuarioID: usuarioID, Ativo: in.Ativo, Frequencia: in.Frequencia, Hora: in.Hora}
+	if c.Frequencia == "" {
+		c.Frequencia = models.ResumoAlertasDiario
+	}
+	if !models.IsValidResumoAlertasFrequencia(c.Frequencia) {
+		return nil, ErrResumoAlertasFrequencia
+	}
+	if c.Hora < 0 || c.Hora > 23 {
+		return nil, ErrResumoAlertasHora
+	}
+	if c.Frequencia == models.ResumoAlertasSemanal {
+		if in.DiaSemana == nil || *in.DiaSemana < 0 || *in.DiaSemana > 6 {
+			return nil, ErrResumoAlertasDiaSemana
+		}
+		d := *in.DiaSemana
+		c.DiaSemana = &d
+	}
+	c.Canais = dedupPerfis(in.Canais)
+	if len(c.Canais) == 0 {
+		c.Canais = []string{models.NotificacaoCanalWebPush}
+	}
+	for _, canal := range c.Canais {
+		if !models.IsValidNotificacaoCanal(canal) {
+			return nil, fmt.Errorf("%w: %s", ErrResumoAlertasCanais, canal)
+		}
+	}
+	if err := s.repo.UpsertConfig(ctx, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Previa monta o resumo do utilizador agora, com a frequência configurada (nada é enviado).
+func (s *ResumoAlertasService) Previa(ctx context.Context, usuarioID int64) (*models.ResumoAlertas, error) {
+	c, err := s.GetConfig(ctx, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	return s.Montar(ctx, usuarioID, c.Frequencia, s.now().In(s.loc))
+}
+
+// janelaResumoAlertas devolve o último dia civil da agenda: o próprio dia (diário) ou 7 dias (semanal).
+func janelaResumoAlertas(frequencia string, hoje time.Time) time.Time {
+	if frequencia == models.ResumoAlertasSemanal {
+		return hoje.AddDate(0, 0, 6)
+	}
+	return hoje
+}
+
+// Montar agrega alertas abertos e agenda de todas as fazendas vinculadas ao utilizador.
+func (s *ResumoAlertasService) Montar(ctx context.Context, usuarioID int64, frequencia string, ref time.Time) (*models.ResumoAlertas, error) {
+	hoje := TruncateToCivilDate(ref)
+	ate := janelaResumoAlertas(frequencia, hoje)
+	fazendas, err := s.fazendaRepo.GetFazendasByUsuarioID(ctx, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	out := &models.ResumoAlertas{
+		Data:       hoje.Format("2006-01-02"),
+		AgendaAte:  ate.Format("2006-01-02"),
+		Frequencia: frequencia,
+		Fazendas:   []models.ResumoAlertasFazenda{},
+	}
+	for _, f := range fazendas {
+		item, err := s.montarFazenda(ctx, f, hoje, ate)
+		if err != nil {
+			return nil, fmt.Errorf("fazenda %d: %w", f.ID, err)
+		}
+		out.Fazendas = append(out.Fazendas, *item)
+	}
+	return out, nil
+}
+
+func (s *ResumoAlertasService) montarFazenda(ctx context.Context, f *models.Fazenda, hoje, ate time.Time) (*models.ResumoAlertasFazenda, error) {
+	item := &models.ResumoAlertasFazenda{FazendaID: f.ID, FazendaNome: f.Nome}
+	porTipo, err := s.alertaRepo.CountAbertosPorTipoByFazenda(ctx, f.ID)
+	if err != nil {
+		return nil, err
+	}
+	item.PorTipo = porTipo
+	for _, t := range porTipo {
+		item.AlertasAbertos += t.Total
+	}
+	item.MaisUrgentes = []models.AlertaWithNames{}
+	if item.AlertasAbertos > 0 {
+		if item.MaisUrgentes, err = s.alertaRepo.ListMaisUrgentesAbertosByFazenda(ctx, f.ID, models.ResumoAlertasMaisUrgentes); err != nil {
+			return nil, err
+		}
+	}
+
+	dias := int(ate.Sub(hoje).Round(24*time.Hour).Hours()/24) + 1
+	pecuario, err := s.pecuario.Build(ctx, f.ID, dias)
+	if err != nil {
+		return nil, err
+	}
+	// Datas DATE chegam em UTC; a comparação é pela data civil (YYYY-MM-DD).
+	limite := ate.Format("2006-01-02")
+	item.PartosPrevistos = []models.PartoPrevistoResumo{}
+	for _, p := range pecuario.PartosPrevistos {
+		if p.DataPrevistaParto != nil && p.DataPrevistaParto.Format("2006-01-02") <= limite {
+			item.PartosPrevistos = append(item.PartosPrevistos, p)
+		}
+	}
+
+	pendentes, err := s.hormonio.ListPendentesByFazendaID(ctx, f.ID, hoje)
+	if err != nil {
+		return nil, err
+	}
+	item.HormonioPendentes = []models.HormonioLactacaoPendente{}
+	for _, p := range pendentes {
+		if p != nil {
+			item.HormonioPendentes = append(item.HormonioPendentes, *p)
+		}
+	}
+
+	if item.VacinasPrevistas, err = s.vacinas.ListPrevistasAteByFazendaID(ctx, f.ID, ate); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// EnviarDevidos envia os resumos agendados para a hora de ref (hora local). Chamado de hora a hora pelo
+// cron; um resumo já enviado no mesmo dia não é repetido. Resumo vazio não é enviado, mas conta como feito.
+func (s *ResumoAlertasService) EnviarDevidos(ctx context.Context, ref time.Time) (enviados int, err error) {
+	ref = ref.In(s.loc)
+	inicioDia := time.Date(ref.Year(), ref.Month(), ref.Day(), 0, 0, 0, 0, s.loc)
+	devidos, err := s.repo.ListDevidos(ctx, ref.Hour(), int(ref.Weekday()), inicioDia)
+	if err != nil {
+		return 0, err
+	}
+	for _, c := range devidos {
+		resumo, err := s.Montar(ctx, c.UsuarioID, c.Frequencia, ref)
+		if err != nil {
+			slog.Warn("resumo alertas: montar", "usuario_id", c.UsuarioID, "error", err)
+			continue
+		}
+		entregue := resumo.Vazio()
+		if !entregue {
+			msg := montarResumoAlertasMensagem(resumo)
+			for _, canal := range c.Canais {
+				if err := s.envio.EnviarParaUsuario(ctx, c.UsuarioID, canal, msg); err != nil {
+					slog.Warn("resumo alertas: envio falhou", "usuario_id", c.UsuarioID, "canal", canal, "error", err)
+					continue
+				}
+				entregue = true
+			}
+			if entregue {
+				enviados++
+			}
+		}
+		if entregue {
+			if err := s.repo.MarcarEnviado(ctx, c.UsuarioID, ref); err != nil {
+				slog.Warn("resumo alertas: marcar envio", "usuario_id", c.UsuarioID, "error", err)
+			}
+		}
+	}
+	return enviados, nil
+}
+
+func montarResumoAlertasMensagem(r *models.ResumoAlertas) NotificacaoMensagem {
+	hoje, _ := time.Parse("2006-01-02", r.Data)
+	titulo := "Resumo diário de alertas — " + hoje.Format("02/01")
+	if r.Frequencia == models.ResumoAlertasSemanal {
+		titulo = "Resumo semanal de alertas — " + hoje.Format("02/01")
+	}
+
+	var b strings.Builder
+	for _, f := range r.Fazendas {
+		if f.AlertasAbertos == 0 && len(f.PartosPrevistos) == 0 && len(f.HormonioPendentes) == 0 && len(f.VacinasPrevistas) == 0 {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%s — %d alerta(s) aberto(s)\n", f.FazendaNome, f.AlertasAbertos)
+		for _, t := range f.PorTipo {
+			fmt.Fprintf(&b, "  %s (%s): %d\n", t.Label, t.Severidade, t.Total)
+		}
+		if len(f.MaisUrgentes) > 0 {
+			b.WriteString("  Mais urgentes:\n")
+			for _, a := range f.MaisUrgentes {
+				linha := a.Titulo
+				if prefix := models.SeveridadePushPrefix(a.Severidade); prefix != "" {
+					linha = prefix + " " + linha
+				}
+				fmt.Fprintf(&b, "  • %s\n", linha)
+			}
+		}
+		if len(f.PartosPrevistos) > 0 {
+			nomes := make([]string, 0, len(f.PartosPrevistos))
+			for _, p := range f.PartosPrevistos {
+				nomes = append(nomes, fmt.Sprintf("%s (%s)", p.Identificacao, p.DataPrevistaParto.Format("02/01")))
+			}
+			fmt.Fprintf(&b, "  Partos previstos: %s\n", resumoAlertasLista(nomes))
+		}
+		if len(f.HormonioPendentes) > 0 {
+			nomes := make([]string, 0, len(f.HormonioPendentes))
+			for _, p := range f.HormonioPendentes {
+				nomes = append(nomes, p.AnimalIdentificacao)
+			}
+			fmt.Fprintf(&b, "  Hormônio pendente: %s\n", resumoAlertasLista(nomes))
+		}
+		if len(f.VacinasPrevistas) > 0 {
+			nomes := make([]string, 0, len(f.VacinasPrevistas))
+			for _, v := range f.VacinasPrevistas {
+				nomes = append(nomes, fmt.Sprintf("%s %s (%s)", v.Identificacao, v.TipoVacina, v.DataPrevista.Format("02/01")))
+			}
+			fmt.Fprintf(&b, "  Vacinas previstas: %s\n", resumoAlertasLista(nomes))
+		}
+	}
+	return NotificacaoMensagem{Titulo: titulo, Corpo: strings.TrimRight(b.String(), "\n"), URL: "/alertas"}
+}
+
+func resumoAlertasLista(nomes []string) string {
+	if len(nomes) <= resumoAlertasMaxItensAgenda {
+		return strings.Join(nomes, ", ")
+	}
+	return fmt.Sprintf("%s e mais %d", strings.Join(nomes[:resumoAlertasMaxItensAgenda], ", "), len(nomes)-resumoAlertasMaxItensAgenda)
+}
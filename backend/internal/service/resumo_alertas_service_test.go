@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+)
+
+type fakeResumoAlertasStore struct {
+	configs  map[int64]*models.ResumoAlertasConfig
+	marcados map[int64]time.Time
+}
+
+func (f *fakeResumoAlertasStore) GetConfig(_ context.Context, uid int64) (*models.ResumoAlertasConfig, error) {
+	return f.configs[uid], nil
+}
+
+func (f *fakeResumoAlertasStore) UpsertConfig(_ context.Context, c *models.ResumoAlertasConfig) error {
+	cp := *c
+	f.configs[c.UsuarioID] = &cp
+	return nil
+}
+
+func (f *fakeResumoAlertasStore) ListDevidos(_ context.Context, hora, dia int, _ time.Time) ([]*models.ResumoAlertasConfig, error) {
+	var out []*models.ResumoAlertasConfig
+	for _, c := range f.configs {
+		if c.Ativo && c.Hora == hora && (c.Frequencia == models.ResumoAlertasDiario || (c.DiaSemana != nil && *c.DiaSemana == dia)) {
+			if _, ok := f.marcados[c.UsuarioID]; !ok {
+				out = append(out, c)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeResumoAlertasStore) MarcarEnviado(_ context.Context, uid int64, em time.Time) error {
+	f.marcados[uid] = em
+	return nil
+}
+
+type fakeResumoAlertasDados struct {
+	porTipo map[int64][]models.ResumoAlertasTipo
+	partos  map[int64][]models.PartoPrevistoResumo
+	vacinas map[int64][]models.VacinaPrevistaResumo
+}
+
+func (f *fakeResumoAlertasDados) CountAbertosPorTipoByFazenda(_ context.Context, fid int64) ([]models.ResumoAlertasTipo, error) {
+	return f.porTipo[fid], nil
+}
+
+func (f *fakeResumoAlertasDados) ListMaisUrgentesAbertosByFazenda(_ context.Context, fid int64, _ int) ([]models.AlertaWithNames, error) {
+	var out []models.AlertaWithNames
+	for _, t := range f.porTipo[fid] {
+		out = append(out, models.AlertaWithNames{Alerta: models.Alerta{FazendaID: fid, Tipo: t.Tipo, Severidade: t.Severidade, Titulo: t.Label + " — Vaca 7"}})
+	}
+	return out, nil
+}
+
+func (f *fakeResumoAlertasDados) GetFazendasByUsuarioID(context.Context, int64) ([]*models.Fazenda, error) {
+	return []*models.Fazenda{{ID: 1, Nome: "Santa Rita"}, {ID: 2, Nome: "Boa Vista"}}, nil
+}
+
+func (f *fakeResumoAlertasDados) Build(_ context.Context, fid int64, _ int) (*models.ResumoPecuario, error) {
+	return &models.ResumoPecuario{PartosPrevistos: f.partos[fid]}, nil
+}
+
+func (f *fakeResumoAlertasDados) ListPendentesByFazendaID(context.Context, int64, time.Time) ([]*models.HormonioLactacaoPendente, error) {
+	return nil, nil
+}
+
+func (f *fakeResumoAlertasDados) ListPrevistasAteByFazendaID(_ context.Context, fid int64, _ time.Time) ([]models.VacinaPrevistaResumo, error) {
+	return f.vacinas[fid], nil
+}
+
+type fakeResumoAlertasEnvio struct {
+	msgs   map[string][]NotificacaoMensagem
+	falhar map[string]bool
+}
+
+func (f *fakeResumoAlertasEnvio) EnviarParaUsuario(_ context.Context, _ int64, canal string, msg NotificacaoMensagem) error {
+	if f.falhar[canal] {
+		return errors.New("falha simulada")
+	}
+	f.msgs[canal] = append(f.msgs[canal], msg)
+	return nil
+}
+
+func newTestResumoAlertasService(dados *fakeResumoAlertasDados) (*ResumoAlertasService, *fakeResumoAlertasStore, *fakeResumoAlertasEnvio) {
+	store := &fakeResumoAlertasStore{configs: map[int64]*models.ResumoAlertasConfig{}, marcados: map[int64]time.Time{}}
+	envio := &fakeResumoAlertasEnvio{msgs: map[string][]NotificacaoMensagem{}, falhar: map[string]bool{}}
+	return &ResumoAlertasService{
+		repo: store, alertaRepo: dados, fazendaRepo: dados, pecuario: dados, hormonio: dados, vacinas: dados,
+		envio: envio, loc: time.UTC, now: time.Now,
+	}, store, envio
+}
+
+func TestResumoAlertasPutConfig_Validacao(t *testing.T) {
+	svc, _, _ := newTestResumoAlertasService(&fakeResumoAlertasDados{})
+	ctx := context.Background()
+	if _, err := svc.PutConfig(ctx, 1, ResumoAlertasConfigInput{Hora: 24}); !errors.Is(err, ErrResumoAlertasHora) {
+		t.Fatalf("hora: %v", err)
+	}
+	if _, err := svc.PutConfig(ctx, 1, ResumoAlertasConfigInput{Frequencia: models.ResumoAlertasSemanal, Hora: 7}); !errors.Is(err, ErrResumoAlertasDiaSemana) {
+		t.Fatalf("semanal sem dia: %v", err)
+	}
+	if _, err := svc.PutConfig(ctx, 1, ResumoAlertasConfigInput{Hora: 7, Canais: []string{"FAX"}}); !errors.Is(err, ErrResumoAlertasCanais) {
+		t.Fatalf("canal: %v", err)
+	}
+	c, err := svc.PutConfig(ctx, 1, ResumoAlertasConfigInput{Ativo: true, Hora: 7, DiaSemana: intPtrResumo(3)})
+	if err != nil {
+		t.Fatalf("PutConfig: %v", err)
+	}
+	if c.Frequencia != models.ResumoAlertasDiario || c.DiaSemana != nil || len(c.Canais) != 1 || c.Canais[0] != models.NotificacaoCanalWebPush {
+		t.Fatalf("padrões: %+v", c)
+	}
+}
+
+func intPtrResumo(v int) *int { return &v }
+
+func TestResumoAlertasEnviarDevidos(t *testing.T) {
+	ref := time.Date(2026, 10, 18, 7, 0, 0, 0, time.UTC) // domingo
+	hoje := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	depois := hoje.AddDate(0, 0, 3)
+	dados := &fakeResumoAlertasDados{
+		porTipo: map[int64][]models.ResumoAlertasTipo{
+			1: {{Tipo: models.AlertaTipoTratamentoVencido, Label: "Tratamento vencido", Severidade: models.AlertaSeveridadeCritica, Total: 2}},
+		},
+		partos: map[int64][]models.PartoPrevistoResumo{
+			1: {{Identificacao: "Vaca 12", DataPrevistaParto: &hoje}, {Identificacao: "Vaca 30", DataPrevistaParto: &depois}},
+		},
+		vacinas: map[int64][]models.VacinaPrevistaResumo{
+			2: {{Identificacao: "Novilha 4", TipoVacina: "BRUCELOSE", DataPrevista: hoje}},
+		},
+	}
+	svc, store, envio := newTestResumoAlertasService(dados)
+	store.configs[10] = &models.ResumoAlertasConfig{UsuarioID: 10, Ativo: true, Frequencia: models.ResumoAlertasDiario, Hora: 7,
+		Canais: []string{models.NotificacaoCanalWebPush, models.NotificacaoCanalEmail}}
+	store.configs[11] = &models.ResumoAlertasConfig{UsuarioID: 11, Ativo: true, Frequencia: models.ResumoAlertasDiario, Hora: 8,
+		Canais: []string{models.NotificacaoCanalEmail}}
+	envio.falhar[models.NotificacaoCanalEmail] = true
+
+	enviados, err := svc.EnviarDevidos(context.Background(), ref)
+	if err != nil {
+		t.Fatalf("EnviarDevidos: %v", err)
+	}
+	if enviados != 1 || len(envio.msgs[models.NotificacaoCanalWebPush]) != 1 {
+		t.Fatalf("enviados=%d push=%d", enviados, len(envio.msgs[models.NotificacaoCanalWebPush]))
+	}
+	if _, ok := store.marcados[10]; !ok {
+		t.Fatal("resumo entregue por um canal deve ser marcado como enviado")
+	}
+	if _, ok := store.marcados[11]; ok {
+		t.Fatal("utilizador de outra hora não deve ser processado")
+	}
+
+	msg := envio.msgs[models.NotificacaoCanalWebPush][0]
+	if !strings.HasPrefix(msg.Titulo, "Resumo diário de alertas — 18/10") {
+		t.Fatalf("título: %q", msg.Titulo)
+	}
+	for _, want := range []string{"Santa Rita — 2 alerta(s)", "Tratamento vencido (CRITICA): 2", "Partos previstos: Vaca 12 (18/10)", "Boa Vista", "Novilha 4 BRUCELOSE"} {
+		if !strings.Contains(msg.Corpo, want) {
+			t.Fatalf("corpo sem %q:\n%s", want, msg.Corpo)
+		}
+	}
+	if strings.Contains(msg.Corpo, "Vaca 30") {
+		t.Fatalf("parto fora da janela diária não deve aparecer:\n%s", msg.Corpo)
+	}
+
+	if again, _ := svc.EnviarDevidos(context.Background(), ref); again != 0 {
+		t.Fatalf("resumo não deve repetir no mesmo dia: %d", again)
+	}
+}
+
+func TestResumoAlertasSemanal_JanelaEVazio(t *testing.T) {
+	hoje := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	depois := hoje.AddDate(0, 0, 6)
+	fora := hoje.AddDate(0, 0, 7)
+	dados := &fakeResumoAlertasDados{partos: map[int64][]models.PartoPrevistoResumo{
+		1: {{Identificacao: "Vaca 1", DataPrevistaParto: &depois}, {Identificacao: "Vaca 2", DataPrevistaParto: &fora}},
+	}}
+	svc, _, _ := newTestResumoAlertasService(dados)
+	r, err := svc.Montar(context.Background(), 10, models.ResumoAlertasSemanal, hoje.Add(9*time.Hour))
+	if err != nil {
+		t.Fatalf("Montar: %v", err)
+	}
+	if r.AgendaAte != "2026-10-24" || len(r.Fazendas[0].PartosPrevistos) != 1 || r.Vazio() {
+		t.Fatalf("janela semanal: ate=%s partos=%d", r.AgendaAte, len(r.Fazendas[0].PartosPrevistos))
+	}
+
+	vazioSvc, store, envio := newTestResumoAlertasService(&fakeResumoAlertasDados{})
+	store.configs[10] = &models.ResumoAlertasConfig{UsuarioID: 10, Ativo: true, Frequencia: models.ResumoAlertasDiario, Hora: 9,
+		Canais: []string{models.NotificacaoCanalWebPush}}
+	if n, _ := vazioSvc.EnviarDevidos(context.Background(), hoje.Add(9*time.Hour)); n != 0 || len(envio.msgs) != 0 {
+		t.Fatalf("resumo vazio não deve ser enviado: n=%d", n)
+	}
+	if _, ok := store.marcados[10]; !ok {
+		t.Fatal("resumo vazio conta como feito")
+	}
+}
@@ -16,6 +16,7 @@ var ErrSecagemNotFound = errors.New("secagem nao encontrada")
 var ErrSecagemJaRegistrada = errors.New("animal ja possui secagem registrada para o ciclo atual")
 
 type SecagemService struct {
+	auditavel
 	pool           *pgxpool.Pool
 	repo           *repository.SecagemRepository
 	lactacaoRepo   *repository.LactacaoRepository
@@ -129,6 +130,7 @@ func (s *SecagemService) Create(ctx context.Context, sec *models.Secagem) error
 		return err
 	}
 	committed = true
+	s.auditar(ctx, models.AuditoriaAcaoCreate, models.AuditoriaEntidadeSecagem, sec.ID, sec.FazendaID, sec.AnimalID, nil, sec)
 	resolveAlertaSilencioso(ctx, s.alertaResolver, sec.FazendaID, sec.AnimalID, models.AlertaTipoGestacaoSemSecagem)
 	return nil
 }
@@ -173,12 +175,16 @@ func (s *SecagemService) GetByFazendaID(ctx context.Context, fazendaID int64) ([
 }
 
 func (s *SecagemService) Delete(ctx context.Context, id int64) error {
-	_, err := s.repo.GetByID(ctx, id)
+	existing, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return ErrSecagemNotFound
 		}
 		return err
 	}
-	return s.repo.Delete(ctx, id)
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoDelete, models.AuditoriaEntidadeSecagem, id, existing.FazendaID, existing.AnimalID, existing, nil)
+	return nil
 }
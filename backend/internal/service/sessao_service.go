@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/repository"
+	"github.com/ceialmilk/api/internal/requestctx"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrSessaoNotFound = errors.New("sessão não encontrada ou já encerrada")
+	// ErrSessaoUsuarioProtegido só um DEVELOPER encerra sessões de outro DEVELOPER (mesma proteção da edição).
+	ErrSessaoUsuarioProtegido = errors.New("apenas DEVELOPER pode encerrar sessões de um usuário DEVELOPER")
+)
+
+type sessaoStore interface {
+	ListSessoesAtivas(ctx context.Context, userID int64, em time.Time) ([]models.Sessao, error)
+	RevokeFamilia(ctx context.Context, userID, familiaID int64) (bool, error)
+	RevokeAllForUserExceto(ctx context.Context, userID, excetoFamiliaID int64) (int64, error)
+}
+
+type sessaoUsuarioStore interface {
+	GetByID(ctx context.Context, id int64) (*models.Usuario, error)
+}
+
+// SessaoService sessões ativas por dispositivo — famílias de refresh tokens (BR-ACESSO-028). Encerrar uma
+// sessão revoga o refresh token; o access token já emitido vale até expirar (no máximo 15 minutos).
+type SessaoService struct {
+	auditavel
+	repo     sessaoStore
+	usuarios sessaoUsuarioStore
+	now      func() time.Time
+}
+
+func NewSessaoService(repo *repository.RefreshTokenRepository, usuarios *repository.UsuarioRepository) *SessaoService {
+	return &SessaoService{repo: repo, usuarios: usuarios, now: time.Now}
+}
+
+// List sessões ativas do usuário; atualFamiliaID marca a do próprio pedido (0 = nenhuma).
+func (s *SessaoService) List(ctx context.Context, userID, atualFamiliaID int64) ([]models.Sessao, error) {
+	list, err := s.repo.ListSessoesAtivas(ctx, userID, s.now())
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		list[i].Atual = atualFamiliaID != 0 && list[i].ID == atualFamiliaID
+	}
+	return list, nil
+}
+
+// Revogar encerra uma sessão do próprio usuário e registra na auditoria.
+func (s *SessaoService) Revogar(ctx context.Context, userID, sessaoID int64) error {
+	if err := s.revogar(ctx, userID, sessaoID); err != nil {
+		return err
+	}
+	s.auditarRevogacao(ctx, userID, 1)
+	return nil
+}
+
+func (s *SessaoService) revogar(ctx context.Context, userID, sessaoID int64) error {
+	ok, err := s.repo.RevokeFamilia(ctx, userID, sessaoID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrSessaoNotFound
+	}
+	return nil
+}
+
+// RevogarTodas encerra as sessões do usuário menos excetoSessaoID (0 = todas); devolve quantas e
+// registra na auditoria.
+func (s *SessaoService) RevogarTodas(ctx context.Context, userID, excetoSessaoID int64) (int64, error) {
+	n, err := s.repo.RevokeAllForUserExceto(ctx, userID, excetoSessaoID)
+	if err != nil {
+		return 0, err
+	}
+	s.auditarRevogacao(ctx, userID, n)
+	return n, nil
+}
+
+// ListUsuario sessões ativas de outro usuário (painel admin).
+func (s *SessaoService) ListUsuario(ctx context.Context, usuarioID int64) ([]models.Sessao, error) {
+	if _, err := s.checarUsuario(ctx, usuarioID); err != nil {
+		return nil, err
+	}
+	return s.List(ctx, usuarioID, 0)
+}
+
+// RevogarUsuario encerra, pelo administrador, uma sessão (sessaoID) ou todas (sessaoID 0) do usuário;
+// devolve quantas e registra na auditoria. Sessões de DEVELOPER só por outro DEVELOPER.
+func (s *SessaoService) RevogarUsuario(ctx context.Context, usuarioID, sessaoID int64) (int64, error) {
+	u, err := s.checarUsuario(ctx, usuarioID)
+	if err != nil {
+		return 0, err
+	}
+	if u.Perfil == models.PerfilDeveloper {
+		if ator, _ := requestctx.AtorFromContext(ctx); ator.Perfil != models.PerfilDeveloper {
+			return 0, ErrSessaoUsuarioProtegido
+		}
+	}
+	var n int64
+	if sessaoID != 0 {
+		if err := s.revogar(ctx, usuarioID, sessaoID); err != nil {
+			return 0, err
+		}
+		n = 1
+	} else if n, err = s.repo.RevokeAllForUserExceto(ctx, usuarioID, 0); err != nil {
+		return 0, err
+	}
+	s.auditarRevogacao(ctx, usuarioID, n)
+	return n, nil
+}
+
+// auditarRevogacao registra em USUARIO quantas sessões foram encerradas; o ator distingue o próprio
+// usuário do administrador (BR-AUDIT-012).
+func (s *SessaoService) auditarRevogacao(ctx context.Context, usuarioID, n int64) {
+	if n == 0 {
+		return
+	}
+	s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeUsuario, usuarioID, 0, 0,
+		map[string]int64{}, map[string]int64{"sessoes_revogadas": n})
+}
+
+func (s *SessaoService) checarUsuario(ctx context.Context, usuarioID int64) (*models.Usuario, error) {
+	u, err := s.usuarios.GetByID(ctx, usuarioID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUsuarioNotFound
+		}
+		return nil, err
+	}
+	return u, nil
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/requestctx"
+	"github.com/jackc/pgx/v5"
+)
+
+type fakeSessaoStore struct {
+	sessoes   map[int64][]models.Sessao
+	revogadas []int64
+}
+
+func (f *fakeSessaoStore) ListSessoesAtivas(_ context.Context, userID int64, _ time.Time) ([]models.Sessao, error) {
+	return append([]models.Sessao(nil), f.sessoes[userID]...), nil
+}
+
+func (f *fakeSessaoStore) RevokeFamilia(_ context.Context, userID, familiaID int64) (bool, error) {
+	lista := f.sessoes[userID]
+	for i, s := range lista {
+		if s.ID == familiaID {
+			f.sessoes[userID] = append(lista[:i:i], lista[i+1:]...)
+			f.revogadas = append(f.revogadas, familiaID)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeSessaoStore) RevokeAllForUserExceto(_ context.Context, userID, exceto int64) (int64, error) {
+	var n int64
+	restantes := []models.Sessao{}
+	for _, s := range f.sessoes[userID] {
+		if s.ID == exceto {
+			restantes = append(restantes, s)
+			continue
+		}
+		f.revogadas = append(f.revogadas, s.ID)
+		n++
+	}
+	f.sessoes[userID] = restantes
+	return n, nil
+}
+
+type fakeSessaoUsuarios struct{}
+
+func (fakeSessaoUsuarios) GetByID(_ context.Context, id int64) (*models.Usuario, error) {
+	switch id {
+	case 404:
+		return nil, pgx.ErrNoRows
+	case 77:
+		return &models.Usuario{ID: id, Perfil: models.PerfilDeveloper}, nil
+	}
+	return &models.Usuario{ID: id, Perfil: models.PerfilGerente}, nil
+}
+
+func TestSessaoService(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeSessaoStore{sessoes: map[int64][]models.Sessao{
+		9: {{ID: 1}, {ID: 2}, {ID: 3}},
+		5: {{ID: 7}},
+	}}
+	s := &SessaoService{repo: store, usuarios: fakeSessaoUsuarios{}, now: time.Now}
+
+	list, err := s.List(ctx, 9, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 3 || list[0].Atual || !list[1].Atual || list[2].Atual {
+		t.Errorf("atual deve marcar só a família 2: %+v", list)
+	}
+
+	if err := s.Revogar(ctx, 9, 7); !errors.Is(err, ErrSessaoNotFound) {
+		t.Errorf("sessão de outro usuário: err = %v", err)
+	}
+	if err := s.Revogar(ctx, 9, 1); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := s.RevogarTodas(ctx, 9, 2); n != 1 || len(store.sessoes[9]) != 1 || store.sessoes[9][0].ID != 2 {
+		t.Errorf("revogar outras: n = %d restantes %+v", n, store.sessoes[9])
+	}
+
+	if _, err := s.ListUsuario(ctx, 404); !errors.Is(err, ErrUsuarioNotFound) {
+		t.Errorf("usuário inexistente: err = %v", err)
+	}
+	admin, _ := s.ListUsuario(ctx, 5)
+	if len(admin) != 1 || admin[0].Atual {
+		t.Errorf("visão admin = %+v", admin)
+	}
+	if n, err := s.RevogarUsuario(ctx, 5, 0); err != nil || n != 1 || len(store.sessoes[5]) != 0 {
+		t.Errorf("admin revoga todas: n = %d err %v", n, err)
+	}
+	if _, err := s.RevogarUsuario(ctx, 5, 7); !errors.Is(err, ErrSessaoNotFound) {
+		t.Errorf("sessão já encerrada: err = %v", err)
+	}
+}
+
+func TestSessaoService_AuditoriaEProtecaoDeveloper(t *testing.T) {
+	store := &fakeSessaoStore{sessoes: map[int64][]models.Sessao{
+		9:  {{ID: 1}, {ID: 2}, {ID: 3}},
+		77: {{ID: 8}},
+	}}
+	s := &SessaoService{repo: store, usuarios: fakeSessaoUsuarios{}, now: time.Now}
+	auditoria := &fakeAuditoriaStore{}
+	s.SetAuditoria(NewAuditoriaService(auditoria))
+	proprio := requestctx.WithAtor(context.Background(), requestctx.Ator{UsuarioID: 9, Perfil: models.PerfilGerente})
+
+	if err := s.Revogar(proprio, 9, 1); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := s.RevogarTodas(proprio, 9, 2); err != nil || n != 1 {
+		t.Fatalf("revogar outras: n = %d err %v", n, err)
+	}
+	if n, _ := s.RevogarTodas(proprio, 9, 2); n != 0 {
+		t.Fatalf("nada a revogar: n = %d", n)
+	}
+	if len(auditoria.eventos) != 2 {
+		t.Fatalf("eventos = %d, want 2 (sem evento quando nada foi encerrado)", len(auditoria.eventos))
+	}
+	for i, e := range auditoria.eventos {
+		if e.Entidade != models.AuditoriaEntidadeUsuario || e.EntidadeID != 9 || e.UsuarioID == nil || *e.UsuarioID != 9 {
+			t.Errorf("evento %d = %+v", i, e)
+		}
+		if !strings.Contains(string(e.Diff), `"sessoes_revogadas"`) {
+			t.Errorf("evento %d: diff = %s", i, e.Diff)
+		}
+	}
+
+	admin := requestctx.WithAtor(context.Background(), requestctx.Ator{UsuarioID: 1, Perfil: models.PerfilAdmin})
+	if _, err := s.RevogarUsuario(admin, 77, 0); !errors.Is(err, ErrSessaoUsuarioProtegido) {
+		t.Errorf("admin revoga developer: err = %v", err)
+	}
+	if len(store.sessoes[77]) != 1 {
+		t.Errorf("sessões do developer não podem ser encerradas por admin: %+v", store.sessoes[77])
+	}
+	dev := requestctx.WithAtor(context.Background(), requestctx.Ator{UsuarioID: 2, Perfil: models.PerfilDeveloper})
+	if n, err := s.RevogarUsuario(dev, 77, 8); err != nil || n != 1 {
+		t.Errorf("developer revoga developer: n = %d err %v", n, err)
+	}
+}
+
+func TestOrigemSessao(t *testing.T) {
+	var rt models.RefreshToken
+	OrigemSessao{UserAgent: "  ", IP: "10.0.0.1"}.aplicar(&rt)
+	if rt.UserAgent != nil || rt.IP == nil || *rt.IP != "10.0.0.1" {
+		t.Errorf("origem = %+v", rt)
+	}
+	longo := make([]byte, userAgentMaxLen+50)
+	for i := range longo {
+		longo[i] = 'a'
+	}
+	OrigemSessao{UserAgent: string(longo)}.aplicar(&rt)
+	if rt.UserAgent == nil || len(*rt.UserAgent) != userAgentMaxLen {
+		t.Errorf("user agent deve ser cortado em %d", userAgentMaxLen)
+	}
+}
@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/oidc"
+	"github.com/ceialmilk/api/internal/repository"
+	"github.com/ceialmilk/api/internal/requestctx"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrSSOProvedor            = errors.New("o provedor de identidade não concluiu o login")
+	ErrSSOEmailAusente        = errors.New("o provedor de identidade não informou um e-mail")
+	ErrSSOEmailNaoVerificado  = errors.New("o e-mail não está verificado no provedor de identidade")
+	ErrSSOUsuarioDesativado   = errors.New("usuário desativado")
+	ErrSSOContaPrivilegiada   = errors.New("contas administrativas entram com e-mail e senha")
+	ErrSSOGrupoPerfilInvalido = errors.New("mapeamento grupo=perfil inválido")
+)
+
+// ssoNomeMaxLen limite da coluna usuarios.nome.
+const ssoNomeMaxLen = 255
+
+type ssoProvedor interface {
+	Issuer() string
+	AuthURL(f oidc.Fluxo) string
+	Trocar(ctx context.Context, code string, f oidc.Fluxo) (jwt.MapClaims, error)
+}
+
+type ssoIdentidadeStore interface {
+	GetUsuarioID(ctx context.Context, emissor, sujeito string) (int64, error)
+	RegistrarLogin(ctx context.Context, emissor, sujeito, email string, em time.Time) error
+	Vincular(ctx context.Context, usuarioID int64, emissor, sujeito, email string, em time.Time) error
+	CriarUsuario(ctx context.Context, u *models.Usuario, emissor, sujeito string) error
+	ElevarPerfil(ctx context.Context, usuarioID int64, perfil string) (bool, error)
+}
+
+type ssoUsuarioStore interface {
+	GetByID(ctx context.Context, id int64) (*models.Usuario, error)
+	GetByEmail(ctx context.Context, email string) (*models.Usuario, error)
+}
+
+// GrupoPerfil grupo do provedor que concede um perfil operacional.
+type GrupoPerfil struct {
+	Grupo  string
+	Perfil string
+}
+
+// MapeamentoSSO claims lidas do ID token e grupos→perfil (OIDC_CLAIM_*, OIDC_GRUPOS_PERFIS).
+type MapeamentoSSO struct {
+	ClaimEmail  string
+	ClaimNome   string
+	ClaimGrupos string
+	// GruposPerfis em ordem de prioridade: vale o primeiro grupo que o usuário tiver.
+	GruposPerfis []GrupoPerfil
+}
+
+// ParseGruposPerfis lê "grupo=PERFIL,grupo2=PERFIL2". Só perfis operacionais: o provedor nunca concede
+// ADMIN/DEVELOPER.
+func ParseGruposPerfis(csv string) ([]GrupoPerfil, error) {
+	var out []GrupoPerfil
+	for _, par := range strings.Split(csv, ",") {
+		par = strings.TrimSpace(par)
+		if par == "" {
+			continue
+		}
+		grupo, perfil, ok := strings.Cut(par, "=")
+		grupo, perfil = strings.TrimSpace(grupo), strings.ToUpper(strings.TrimSpace(perfil))
+		if !ok || grupo == "" {
+			return nil, fmt.Errorf("%w: %q", ErrSSOGrupoPerfilInvalido, par)
+		}
+		switch perfil {
+		case models.PerfilFuncionario, models.PerfilGerente, models.PerfilGestao, models.PerfilProprietario:
+		default:
+			return nil, fmt.Errorf("%w: perfil %q não pode ser concedido pelo provedor", ErrSSOGrupoPerfilInvalido, perfil)
+		}
+		out = append(out, GrupoPerfil{Grupo: grupo, Perfil: perfil})
+	}
+	return out, nil
+}
+
+// IdentidadeSSO o que o ID token diz sobre o usuário, já com o mapeamento aplicado.
+type IdentidadeSSO struct {
+	Sujeito         string
+	Email           string
+	EmailVerificado bool
+	Nome            string
+	Grupos          []string
+}
+
+// ResultadoSSO conta do login; Criado quando foi provisionada agora.
+type ResultadoSSO struct {
+	Usuario *models.Usuario
+	Criado  bool
+}
+
+// SSOService login por OpenID Connect (BR-ACESSO-029): resolve a conta pela identidade vinculada
+// (emissor + sub), vincula por e-mail verificado ou provisiona um USER pendente de provisão.
+type SSOService struct {
+	auditavel
+	provedor   ssoProvedor
+	repo       ssoIdentidadeStore
+	usuarios   ssoUsuarioStore
+	mapeamento MapeamentoSSO
+	now        func() time.Time
+}
+
+func NewSSOService(provedor *oidc.Provider, repo *repository.IdentidadeOIDCRepository, usuarios *repository.UsuarioRepository, m MapeamentoSSO) *SSOService {
+	return &SSOService{provedor: provedor, repo: repo, usuarios: usuarios, mapeamento: normalizarMapeamento(m), now: time.Now}
+}
+
+func normalizarMapeamento(m MapeamentoSSO) MapeamentoSSO {
+	if m.ClaimEmail == "" {
+		m.ClaimEmail = "email"
+	}
+	if m.ClaimNome == "" {
+		m.ClaimNome = "name"
+	}
+	if m.ClaimGrupos == "" {
+		m.ClaimGrupos = "groups"
+	}
+	return m
+}
+
+// Iniciar fluxo novo (state, nonce, PKCE) e o endereço do provedor.
+func (s *SSOService) Iniciar(redirect string) (oidc.Fluxo, string, error) {
+	f, err := oidc.NovoFluxo(redirect)
+	if err != nil {
+		return oidc.Fluxo{}, "", err
+	}
+	return f, s.provedor.AuthURL(f), nil
+}
+
+// Concluir troca o código, valida o ID token e devolve a conta (vinculada, existente ou provisionada).
+func (s *SSOService) Concluir(ctx context.Context, code string, f oidc.Fluxo) (*ResultadoSSO, error) {
+	claims, err := s.provedor.Trocar(ctx, code, f)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSSOProvedor, err)
+	}
+	id := s.mapear(claims)
+	emissor := s.provedor.Issuer()
+	agora := s.now()
+
+	usuarioID, err := s.repo.GetUsuarioID(ctx, emissor, id.Sujeito)
+	switch {
+	case err == nil:
+		u, err := s.usuarios.GetByID(ctx, usuarioID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.repo.RegistrarLogin(ctx, emissor, id.Sujeito, id.Email, agora); err != nil {
+			return nil, err
+		}
+		return s.finalizar(ctx, u, id, false)
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, err
+	}
+
+	if id.Email == "" {
+		return nil, ErrSSOEmailAusente
+	}
+	// Sem e-mail verificado não se vincula nem se cria conta: outro provedor (ou outra conta nele) poderia
+	// reclamar o e-mail de um usuário existente.
+	if !id.EmailVerificado {
+		return nil, ErrSSOEmailNaoVerificado
+	}
+	existente, err := s.usuarios.GetByEmail(ctx, id.Email)
+	if err == nil {
+		// Quem administra o provedor não deve conseguir assumir contas ADMIN/DEVELOPER pelo e-mail.
+		if existente.Perfil == models.PerfilAdmin || existente.Perfil == models.PerfilDeveloper {
+			return nil, ErrSSOContaPrivilegiada
+		}
+		if !existente.Enabled {
+			return nil, ErrSSOUsuarioDesativado
+		}
+		if err := s.repo.Vincular(ctx, existente.ID, emissor, id.Sujeito, id.Email, agora); err != nil {
+			return nil, err
+		}
+		if existente.EmailVerificadoEm == nil {
+			existente.EmailVerificadoEm = &agora
+		}
+		return s.finalizar(ctx, existente, id, false)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	senha, err := senhaInutilizavel()
+	if err != nil {
+		return nil, err
+	}
+	u := &models.Usuario{
+		Nome:              id.Nome,
+		Email:             id.Email,
+		Senha:             senha,
+		Perfil:            models.PerfilUser,
+		Enabled:           true,
+		EmailVerificadoEm: &agora,
+	}
+	if err := s.repo.CriarUsuario(ctx, u, emissor, id.Sujeito); err != nil {
+		return nil, err
+	}
+	return s.finalizar(ctx, u, id, true)
+}
+
+// finalizar recusa conta desativada e aplica o perfil dos grupos a quem ainda é USER.
+func (s *SSOService) finalizar(ctx context.Context, u *models.Usuario, id IdentidadeSSO, criado bool) (*ResultadoSSO, error) {
+	if !u.Enabled {
+		return nil, ErrSSOUsuarioDesativado
+	}
+	if perfil := s.perfilDosGrupos(id.Grupos); perfil != "" && u.Perfil == models.PerfilUser {
+		mudou, err := s.repo.ElevarPerfil(ctx, u.ID, perfil)
+		if err != nil {
+			return nil, err
+		}
+		if mudou {
+			if _, ok := requestctx.AtorFromContext(ctx); !ok {
+				ctx = requestctx.WithAtor(ctx, requestctx.Ator{UsuarioID: u.ID})
+			}
+			s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeUsuario, u.ID, 0, 0,
+				map[string]string{"perfil": u.Perfil}, map[string]string{"perfil": perfil, "origem": "OIDC_GRUPO"})
+			u.Perfil = perfil
+		}
+	}
+	return &ResultadoSSO{Usuario: u, Criado: criado}, nil
+}
+
+// perfilDosGrupos primeiro mapeamento cujo grupo o usuário tem; "" se nenhum.
+func (s *SSOService) perfilDosGrupos(grupos []string) string {
+	tem := make(map[string]bool, len(grupos))
+	for _, g := range grupos {
+		tem[g] = true
+	}
+	for _, gp := range s.mapeamento.GruposPerfis {
+		if tem[gp.Grupo] {
+			return gp.Perfil
+		}
+	}
+	return ""
+}
+
+func (s *SSOService) mapear(claims jwt.MapClaims) IdentidadeSSO {
+	id := IdentidadeSSO{
+		Sujeito: claimString(claims, "sub"),
+		Email:   strings.ToLower(claimString(claims, s.mapeamento.ClaimEmail)),
+		Nome:    claimString(claims, s.mapeamento.ClaimNome),
+	}
+	switch v := claims["email_verified"].(type) {
+	case bool:
+		id.EmailVerificado = v
+	case string:
+		id.EmailVerificado = v == "true"
+	}
+	if id.Nome == "" {
+		id.Nome = strings.TrimSpace(claimString(claims, "given_name") + " " + claimString(claims, "family_name"))
+	}
+	if id.Nome == "" {
+		id.Nome, _, _ = strings.Cut(id.Email, "@")
+	}
+	if r := []rune(id.Nome); len(r) > ssoNomeMaxLen {
+		id.Nome = string(r[:ssoNomeMaxLen])
+	}
+	switch v := claims[s.mapeamento.ClaimGrupos].(type) {
+	case []interface{}:
+		for _, g := range v {
+			if str, ok := g.(string); ok {
+				id.Grupos = append(id.Grupos, str)
+			}
+		}
+	case string:
+		id.Grupos = strings.Fields(strings.ReplaceAll(v, ",", " "))
+	}
+	return id
+}
+
+func claimString(claims jwt.MapClaims, nome string) string {
+	v, _ := claims[nome].(string)
+	return strings.TrimSpace(v)
+}
+
+// senhaInutilizavel hash bcrypt de um segredo aleatório descartado: a conta SSO não entra por senha até
+// o usuário definir uma em "esqueci a senha" (BR-ACESSO-026).
+func senhaInutilizavel() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(base64.RawStdEncoding.EncodeToString(b)), bcryptCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
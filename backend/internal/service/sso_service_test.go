@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/oidc"
+	"github.com/ceialmilk/api/internal/oidc/oidctest"
+	"github.com/jackc/pgx/v5"
+)
+
+type fakeSSOStore struct {
+	identidades map[string]int64
+	usuarios    map[int64]*models.Usuario
+	proximoID   int64
+}
+
+func (f *fakeSSOStore) GetUsuarioID(_ context.Context, emissor, sujeito string) (int64, error) {
+	if id, ok := f.identidades[emissor+"|"+sujeito]; ok {
+		return id, nil
+	}
+	return 0, pgx.ErrNoRows
+}
+
+func (f *fakeSSOStore) RegistrarLogin(context.Context, string, string, string, time.Time) error {
+	return nil
+}
+
+func (f *fakeSSOStore) Vincular(_ context.Context, usuarioID int64, emissor, sujeito, _ string, em time.Time) error {
+	f.identidades[emissor+"|"+sujeito] = usuarioID
+	if u := f.usuarios[usuarioID]; u.EmailVerificadoEm == nil {
+		u.EmailVerificadoEm = &em
+	}
+	return nil
+}
+
+func (f *fakeSSOStore) CriarUsuario(_ context.Context, u *models.Usuario, emissor, sujeito string) error {
+	f.proximoID++
+	u.ID = f.proximoID
+	cp := *u
+	f.usuarios[u.ID] = &cp
+	f.identidades[emissor+"|"+sujeito] = u.ID
+	return nil
+}
+
+func (f *fakeSSOStore) ElevarPerfil(_ context.Context, usuarioID int64, perfil string) (bool, error) {
+	u := f.usuarios[usuarioID]
+	if u.Perfil != models.PerfilUser {
+		return false, nil
+	}
+	u.Perfil = perfil
+	return true, nil
+}
+
+func (f *fakeSSOStore) GetByID(_ context.Context, id int64) (*models.Usuario, error) {
+	if u, ok := f.usuarios[id]; ok {
+		cp := *u
+		return &cp, nil
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (f *fakeSSOStore) GetByEmail(_ context.Context, email string) (*models.Usuario, error) {
+	for _, u := range f.usuarios {
+		if u.Email == email {
+			cp := *u
+			return &cp, nil
+		}
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func novoSSOService(t *testing.T, m MapeamentoSSO) (*SSOService, *oidctest.Issuer, *fakeSSOStore) {
+	t.Helper()
+	iss, srv := oidctest.NewServer("ceialmilk", "segredo")
+	t.Cleanup(srv.Close)
+	p, err := oidc.NewProvider(context.Background(), oidc.Config{
+		Issuer: iss.URL, ClientID: "ceialmilk", ClientSecret: "segredo", RedirectURL: "http://localhost:8080/api/auth/oidc/callback",
+	}, srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &fakeSSOStore{identidades: map[string]int64{}, usuarios: map[int64]*models.Usuario{}, proximoID: 100}
+	s := &SSOService{provedor: p, repo: store, usuarios: store, mapeamento: normalizarMapeamento(m), now: time.Now}
+	return s, iss, store
+}
+
+// loginSSO percorre o fluxo inteiro contra o emissor falso.
+func loginSSO(t *testing.T, s *SSOService, iss *oidctest.Issuer, claims map[string]interface{}) (*ResultadoSSO, error) {
+	t.Helper()
+	iss.SetClaims(claims)
+	f, authURL, err := s.Iniciar("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, state, err := iss.Authorize(authURL)
+	if err != nil || state != f.State {
+		t.Fatalf("authorize: state %q err %v", state, err)
+	}
+	return s.Concluir(context.Background(), code, f)
+}
+
+func TestSSO_ProvisionaUsuarioPendente(t *testing.T) {
+	s, iss, store := novoSSOService(t, MapeamentoSSO{})
+	res, err := loginSSO(t, s, iss, map[string]interface{}{
+		"sub": "abc", "email": "Joao@Coop.example", "email_verified": true, "name": "João Produtor",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := res.Usuario
+	if !res.Criado || u.Perfil != models.PerfilUser || u.Email != "joao@coop.example" || u.Nome != "João Produtor" ||
+		u.EmailVerificadoEm == nil || !u.Enabled || u.Senha == "" {
+		t.Errorf("provisionado = %+v criado %v", u, res.Criado)
+	}
+
+	// Segundo login: mesma conta pela identidade, mesmo que o e-mail mude no provedor.
+	res, err = loginSSO(t, s, iss, map[string]interface{}{"sub": "abc", "email": "novo@coop.example", "email_verified": true})
+	if err != nil || res.Criado || res.Usuario.ID != u.ID {
+		t.Errorf("segundo login: %+v err %v", res, err)
+	}
+	if len(store.usuarios) != 1 {
+		t.Errorf("usuários = %d", len(store.usuarios))
+	}
+}
+
+func TestSSO_VinculaContaExistente(t *testing.T) {
+	s, iss, store := novoSSOService(t, MapeamentoSSO{})
+	store.usuarios[7] = &models.Usuario{ID: 7, Email: "ana@coop.example", Perfil: models.PerfilGerente, Enabled: true}
+	store.usuarios[8] = &models.Usuario{ID: 8, Email: "admin@coop.example", Perfil: models.PerfilAdmin, Enabled: true}
+	store.usuarios[9] = &models.Usuario{ID: 9, Email: "off@coop.example", Perfil: models.PerfilUser}
+
+	if _, err := loginSSO(t, s, iss, map[string]interface{}{"sub": "x1", "email": "ana@coop.example"}); !errors.Is(err, ErrSSOEmailNaoVerificado) {
+		t.Errorf("e-mail não verificado: err = %v", err)
+	}
+	res, err := loginSSO(t, s, iss, map[string]interface{}{"sub": "x1", "email": "ana@coop.example", "email_verified": true})
+	if err != nil || res.Criado || res.Usuario.ID != 7 || res.Usuario.Perfil != models.PerfilGerente {
+		t.Errorf("vínculo por e-mail: %+v err %v", res, err)
+	}
+	if store.identidades[iss.URL+"|x1"] != 7 || store.usuarios[7].EmailVerificadoEm == nil {
+		t.Errorf("vínculo não gravado: %v", store.identidades)
+	}
+
+	casos := []struct {
+		nome   string
+		claims map[string]interface{}
+		want   error
+	}{
+		{"conta administrativa", map[string]interface{}{"sub": "x2", "email": "admin@coop.example", "email_verified": true}, ErrSSOContaPrivilegiada},
+		{"conta desativada", map[string]interface{}{"sub": "x3", "email": "off@coop.example", "email_verified": true}, ErrSSOUsuarioDesativado},
+		{"sem e-mail", map[string]interface{}{"sub": "x4"}, ErrSSOEmailAusente},
+	}
+	for _, c := range casos {
+		if _, err := loginSSO(t, s, iss, c.claims); !errors.Is(err, c.want) {
+			t.Errorf("%s: err = %v, want %v", c.nome, err, c.want)
+		}
+	}
+}
+
+func TestSSO_GruposPerfis(t *testing.T) {
+	mapa, err := ParseGruposPerfis(" coop-gestores=gestao, coop-produtores=PROPRIETARIO ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, iss, store := novoSSOService(t, MapeamentoSSO{ClaimGrupos: "roles", GruposPerfis: mapa})
+
+	res, err := loginSSO(t, s, iss, map[string]interface{}{
+		"sub": "p1", "email": "p1@coop.example", "email_verified": true, "roles": []string{"outros", "coop-produtores", "coop-gestores"},
+	})
+	if err != nil || res.Usuario.Perfil != models.PerfilGestao {
+		t.Fatalf("primeiro mapeamento vence: %+v err %v", res, err)
+	}
+
+	// Perfil já operacional não é trocado por outro grupo.
+	res, _ = loginSSO(t, s, iss, map[string]interface{}{"sub": "p1", "roles": "coop-produtores"})
+	if res.Usuario.Perfil != models.PerfilGestao || store.usuarios[res.Usuario.ID].Perfil != models.PerfilGestao {
+		t.Errorf("perfil não deve mudar: %+v", res.Usuario)
+	}
+
+	for _, invalido := range []string{"admins=ADMIN", "dev=DEVELOPER", "semperfil", "=GERENTE"} {
+		if _, err := ParseGruposPerfis(invalido); !errors.Is(err, ErrSSOGrupoPerfilInvalido) {
+			t.Errorf("%q: err = %v", invalido, err)
+		}
+	}
+}
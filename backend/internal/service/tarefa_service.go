@@ -0,0 +1,578 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	ErrTarefaNotFound              = errors.New("tarefa não encontrada")
+	ErrTarefaTituloInvalido        = errors.New("título é obrigatório e deve ter até 200 caracteres")
+	ErrTarefaDataObrigatoria       = errors.New("data prevista é obrigatória")
+	ErrTarefaRecorrenciaInvalida   = errors.New("recorrência inválida: use NENHUMA, DIARIA ou SEMANAL")
+	ErrTarefaChecklistInvalida     = errors.New("checklist aceita até 30 itens de até 200 caracteres")
+	ErrTarefaStatusInvalido        = errors.New("status inválido")
+	ErrTarefaTransicaoInvalida     = errors.New("transição de status inválida")
+	ErrTarefaForbidden             = errors.New("perfil não autorizado para esta operação")
+	ErrTarefaResponsavelInvalido   = errors.New("responsável deve ser um utilizador ativo vinculado à fazenda")
+	ErrTarefaVinculoFazenda        = errors.New("animal, lote ou área não pertence à fazenda informada")
+	ErrTarefaEncerrada             = errors.New("tarefa concluída ou cancelada não pode ser alterada")
+	ErrTarefaChecklistItemNotFound = errors.New("item de checklist não encontrado")
+	ErrTarefaAlertaEncerrado       = errors.New("alerta resolvido ou ignorado não pode virar tarefa")
+	ErrTarefaAlertaJaConvertido    = errors.New("o alerta já tem uma tarefa em aberto")
+)
+
+type tarefaStore interface {
+	ListByFazenda(ctx context.Context, fazendaID int64, f repository.TarefaListFilters) ([]models.TarefaWithNames, int64, error)
+	GetByID(ctx context.Context, fazendaID, id int64) (*models.TarefaWithNames, error)
+	Create(ctx context.Context, t *models.Tarefa, checklist []string) error
+	Update(ctx context.Context, t *models.Tarefa, checklist []string) error
+	UpdateStatus(ctx context.Context, fazendaID, id int64, status string, actorID int64, assumir bool) error
+	Concluir(ctx context.Context, fazendaID, id, actorID int64, proxima *models.Tarefa) error
+	Delete(ctx context.Context, fazendaID, id int64) error
+	MarcarChecklistItem(ctx context.Context, tarefaID, itemID int64, feito bool, actorID int64) error
+	VinculosDaFazenda(ctx context.Context, fazendaID int64, animalID, loteID, areaID *int64) (bool, error)
+}
+
+type tarefaFolgasStore interface {
+	ListFolgasUsuarioOnDate(ctx context.Context, fazendaID int64, d time.Time) ([]models.EscalaFolga, error)
+	ListAusenciasRange(ctx context.Context, fazendaID int64, inicio, fim time.Time, usuarioID *int64) ([]models.FolgaAusencia, error)
+}
+
+type tarefaAlertaStore interface {
+	GetByID(ctx context.Context, fazendaID, alertaID int64) (*models.AlertaWithNames, error)
+}
+
+type TarefaService struct {
+	repo        tarefaStore
+	folgas      tarefaFolgasStore
+	alertas     tarefaAlertaStore
+	atividades  alertaAtividadeStore
+	usuariosFaz alertaUsuariosFazendaStore
+	pushSvc     *PushNotificationService
+	now         func() time.Time
+}
+
+func NewTarefaService(repo *repository.TarefaRepository, folgasRepo *repository.FolgasRepository, alertaRepo *repository.AlertaRepository, atividadeRepo *repository.AlertaAtividadeRepository, fazendaRepo *repository.FazendaRepository) *TarefaService {
+	return &TarefaService{
+		repo:        repo,
+		folgas:      folgasRepo,
+		alertas:     alertaRepo,
+		atividades:  atividadeRepo,
+		usuariosFaz: fazendaRepo,
+		now:         time.Now,
+	}
+}
+
+func (s *TarefaService) SetPushNotificationService(pushSvc *PushNotificationService) {
+	s.pushSvc = pushSvc
+}
+
+type TarefaListQuery struct {
+	Status         string
+	Abertas        bool
+	ResponsavelID  *int64
+	SemResponsavel bool
+	Inicio         *time.Time
+	Fim            *time.Time
+	Limit          int
+	Offset         int
+}
+
+func (s *TarefaService) ListByFazenda(ctx context.Context, fazendaID int64, q TarefaListQuery) ([]models.TarefaWithNames, int64, error) {
+	if q.Status != "" && !models.IsValidTarefaStatus(q.Status) {
+		return nil, 0, ErrTarefaStatusInvalido
+	}
+	return s.repo.ListByFazenda(ctx, fazendaID, repository.TarefaListFilters{
+		Status:         q.Status,
+		Abertas:        q.Abertas,
+		ResponsavelID:  q.ResponsavelID,
+		SemResponsavel: q.SemResponsavel,
+		Inicio:         q.Inicio,
+		Fim:            q.Fim,
+		Limit:          q.Limit,
+		Offset:         q.Offset,
+	})
+}
+
+func (s *TarefaService) GetByID(ctx context.Context, fazendaID, id int64) (*models.TarefaWithNames, error) {
+	t, err := s.repo.GetByID(ctx, fazendaID, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTarefaNotFound
+	}
+	return t, err
+}
+
+// TarefaInput campos editáveis; Checklist nil na edição mantém os itens atuais.
+type TarefaInput struct {
+	Titulo        string
+	Descricao     *string
+	AnimalID      *int64
+	LoteID        *int64
+	AreaID        *int64
+	ResponsavelID *int64
+	DataPrevista  *time.Time
+	Recorrencia   string
+	Checklist     []string
+	ActorUserID   int64
+	Perfil        string
+}
+
+// normalizarTarefaInput apara textos, aplica padrões e valida limites (BR-TAREFA-001).
+func normalizarTarefaInput(in *TarefaInput) error {
+	in.Titulo = strings.TrimSpace(in.Titulo)
+	if in.Titulo == "" || utf8.RuneCountInString(in.Titulo) > models.TarefaTituloMaxLen {
+		return ErrTarefaTituloInvalido
+	}
+	if in.Descricao != nil {
+		d := strings.TrimSpace(*in.Descricao)
+		if d == "" {
+			in.Descricao = nil
+		} else {
+			in.Descricao = &d
+		}
+	}
+	if in.DataPrevista == nil || in.DataPrevista.IsZero() {
+		return ErrTarefaDataObrigatoria
+	}
+	d := truncateDateUTC(*in.DataPrevista)
+	in.DataPrevista = &d
+	if in.Recorrencia == "" {
+		in.Recorrencia = models.TarefaRecorrenciaNenhuma
+	}
+	if !models.IsValidTarefaRecorrencia(in.Recorrencia) {
+		return ErrTarefaRecorrenciaInvalida
+	}
+	if in.Checklist != nil {
+		itens := make([]string, 0, len(in.Checklist))
+		for _, it := range in.Checklist {
+			it = strings.TrimSpace(it)
+			if it == "" {
+				continue
+			}
+			if utf8.RuneCountInString(it) > models.TarefaChecklistMaxLen {
+				return ErrTarefaChecklistInvalida
+			}
+			itens = append(itens, it)
+		}
+		if len(itens) > models.TarefaChecklistMax {
+			return ErrTarefaChecklistInvalida
+		}
+		in.Checklist = itens
+	}
+	return nil
+}
+
+// podeExecutarTarefa gestão executa qualquer tarefa; os demais perfis operacionais, a própria ou
+// uma sem responsável (quem inicia assume).
+func podeExecutarTarefa(t *models.Tarefa, actorID int64, perfil string) bool {
+	if models.PodeGerenciarTarefas(perfil) {
+		return true
+	}
+	if !models.PodeExecutarTarefa(perfil) {
+		return false
+	}
+	return t.ResponsavelID == nil || *t.ResponsavelID == actorID
+}
+
+// validarResponsavel confere o vínculo com a fazenda e devolve o nome para o histórico.
+func (s *TarefaService) validarResponsavel(ctx context.Context, fazendaID int64, responsavelID *int64) (string, error) {
+	if responsavelID == nil {
+		return "", nil
+	}
+	usuarios, err := s.usuariosFaz.ListUsuariosPublicosByFazendaID(ctx, fazendaID)
+	if err != nil {
+		return "", err
+	}
+	for _, u := range usuarios {
+		if u.ID == *responsavelID {
+			return u.Nome, nil
+		}
+	}
+	return "", ErrTarefaResponsavelInvalido
+}
+
+func (s *TarefaService) validarVinculos(ctx context.Context, fazendaID int64, in TarefaInput) error {
+	if in.AnimalID == nil && in.LoteID == nil && in.AreaID == nil {
+		return nil
+	}
+	ok, err := s.repo.VinculosDaFazenda(ctx, fazendaID, in.AnimalID, in.LoteID, in.AreaID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrTarefaVinculoFazenda
+	}
+	return nil
+}
+
+func (s *TarefaService) Create(ctx context.Context, fazendaID int64, in TarefaInput) (*models.TarefaWithNames, error) {
+	if !models.PodeGerenciarTarefas(in.Perfil) {
+		return nil, ErrTarefaForbidden
+	}
+	if err := normalizarTarefaInput(&in); err != nil {
+		return nil, err
+	}
+	if err := s.validarVinculos(ctx, fazendaID, in); err != nil {
+		return nil, err
+	}
+	if _, err := s.validarResponsavel(ctx, fazendaID, in.ResponsavelID); err != nil {
+		return nil, err
+	}
+	t := &models.Tarefa{
+		FazendaID:     fazendaID,
+		Titulo:        in.Titulo,
+		Descricao:     in.Descricao,
+		AnimalID:      in.AnimalID,
+		LoteID:        in.LoteID,
+		AreaID:        in.AreaID,
+		ResponsavelID: in.ResponsavelID,
+		DataPrevista:  *in.DataPrevista,
+		Recorrencia:   in.Recorrencia,
+		Status:        models.TarefaStatusAberta,
+		CreatedBy:     &in.ActorUserID,
+	}
+	if err := s.repo.Create(ctx, t, in.Checklist); err != nil {
+		return nil, err
+	}
+	s.notificarResponsavel(t, in.ActorUserID)
+	return s.GetByID(ctx, fazendaID, t.ID)
+}
+
+func (s *TarefaService) Update(ctx context.Context, fazendaID, id int64, in TarefaInput) (*models.TarefaWithNames, error) {
+	if !models.PodeGerenciarTarefas(in.Perfil) {
+		return nil, ErrTarefaForbidden
+	}
+	if err := normalizarTarefaInput(&in); err != nil {
+		return nil, err
+	}
+	existing, err := s.GetByID(ctx, fazendaID, id)
+	if err != nil {
+		return nil, err
+	}
+	if models.IsTarefaStatusTerminal(existing.Status) {
+		return nil, ErrTarefaEncerrada
+	}
+	if err := s.validarVinculos(ctx, fazendaID, in); err != nil {
+		return nil, err
+	}
+	if _, err := s.validarResponsavel(ctx, fazendaID, in.ResponsavelID); err != nil {
+		return nil, err
+	}
+	t := existing.Tarefa
+	t.Titulo = in.Titulo
+	t.Descricao = in.Descricao
+	t.AnimalID = in.AnimalID
+	t.LoteID = in.LoteID
+	t.AreaID = in.AreaID
+	t.ResponsavelID = in.ResponsavelID
+	t.DataPrevista = *in.DataPrevista
+	t.Recorrencia = in.Recorrencia
+	if err := s.repo.Update(ctx, &t, in.Checklist); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTarefaNotFound
+		}
+		return nil, err
+	}
+	if !sameInt64Ptr(existing.ResponsavelID, t.ResponsavelID) {
+		s.notificarResponsavel(&t, in.ActorUserID)
+	}
+	return s.GetByID(ctx, fazendaID, id)
+}
+
+type UpdateTarefaStatusInput struct {
+	Status      string
+	ActorUserID int64
+	Perfil      string
+}
+
+// UpdateStatus segue o fluxo do alerta (BR-TAREFA-002): iniciar e concluir cabem ao responsável (ou a
+// quem assume uma tarefa sem responsável) e à gestão; cancelar, só à gestão. Concluir uma tarefa
+// recorrente cria a próxima ocorrência.
+func (s *TarefaService) UpdateStatus(ctx context.Context, fazendaID, id int64, in UpdateTarefaStatusInput) (*models.TarefaWithNames, error) {
+	if !models.IsValidTarefaStatus(in.Status) {
+		return nil, ErrTarefaStatusInvalido
+	}
+	existing, err := s.GetByID(ctx, fazendaID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !models.IsTransicaoTarefaStatusValida(existing.Status, in.Status) {
+		return nil, ErrTarefaTransicaoInvalida
+	}
+	if in.Status == models.TarefaStatusCancelada {
+		if !models.PodeGerenciarTarefas(in.Perfil) {
+			return nil, ErrTarefaForbidden
+		}
+	} else if !podeExecutarTarefa(&existing.Tarefa, in.ActorUserID, in.Perfil) {
+		return nil, ErrTarefaForbidden
+	}
+
+	if in.Status == models.TarefaStatusConcluida {
+		proxima := proximaOcorrencia(&existing.Tarefa, in.ActorUserID)
+		if err := s.repo.Concluir(ctx, fazendaID, id, in.ActorUserID, proxima); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrTarefaTransicaoInvalida
+			}
+			return nil, err
+		}
+		if existing.AlertaID != nil {
+			s.registrarAtividadeAlerta(ctx, fazendaID, *existing.AlertaID, in.ActorUserID,
+				fmt.Sprintf("Tarefa #%d concluída", id), map[string]interface{}{"tarefa_id": id, "status": in.Status})
+		}
+	} else {
+		// Quem inicia uma tarefa sem responsável passa a ser o responsável.
+		assumir := in.Status == models.TarefaStatusEmAndamento
+		if err := s.repo.UpdateStatus(ctx, fazendaID, id, in.Status, in.ActorUserID, assumir); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrTarefaNotFound
+			}
+			return nil, err
+		}
+		if in.Status == models.TarefaStatusCancelada && existing.AlertaID != nil {
+			s.registrarAtividadeAlerta(ctx, fazendaID, *existing.AlertaID, in.ActorUserID,
+				fmt.Sprintf("Tarefa #%d cancelada", id), map[string]interface{}{"tarefa_id": id, "status": in.Status})
+		}
+	}
+	return s.GetByID(ctx, fazendaID, id)
+}
+
+// proximaOcorrencia tarefa seguinte da série recorrente; nil sem recorrência (BR-TAREFA-001).
+func proximaOcorrencia(t *models.Tarefa, actorID int64) *models.Tarefa {
+	data, ok := models.ProximaDataTarefa(t.Recorrencia, t.DataPrevista)
+	if !ok {
+		return nil
+	}
+	serie := t.SerieID
+	if serie == nil {
+		id := t.ID
+		serie = &id
+	}
+	return &models.Tarefa{
+		FazendaID:     t.FazendaID,
+		Titulo:        t.Titulo,
+		Descricao:     t.Descricao,
+		AnimalID:      t.AnimalID,
+		LoteID:        t.LoteID,
+		AreaID:        t.AreaID,
+		ResponsavelID: t.ResponsavelID,
+		DataPrevista:  data,
+		Recorrencia:   t.Recorrencia,
+		SerieID:       serie,
+		Status:        models.TarefaStatusAberta,
+		CreatedBy:     &actorID,
+	}
+}
+
+func (s *TarefaService) Delete(ctx context.Context, fazendaID, id int64, perfil string) error {
+	if !models.PodeGerenciarTarefas(perfil) {
+		return ErrTarefaForbidden
+	}
+	if err := s.repo.Delete(ctx, fazendaID, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrTarefaNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+type MarcarChecklistInput struct {
+	Feito       bool
+	ActorUserID int64
+	Perfil      string
+}
+
+func (s *TarefaService) MarcarChecklist(ctx context.Context, fazendaID, id, itemID int64, in MarcarChecklistInput) (*models.TarefaWithNames, error) {
+	existing, err := s.GetByID(ctx, fazendaID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !podeExecutarTarefa(&existing.Tarefa, in.ActorUserID, in.Perfil) {
+		return nil, ErrTarefaForbidden
+	}
+	if models.IsTarefaStatusTerminal(existing.Status) {
+		return nil, ErrTarefaEncerrada
+	}
+	if err := s.repo.MarcarChecklistItem(ctx, id, itemID, in.Feito, in.ActorUserID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTarefaChecklistItemNotFound
+		}
+		return nil, err
+	}
+	return s.GetByID(ctx, fazendaID, id)
+}
+
+type ConverterAlertaInput struct {
+	// ResponsavelID e DataPrevista nil herdam do alerta (data: a prevista dele ou hoje).
+	ResponsavelID *int64
+	DataPrevista  *time.Time
+	Checklist     []string
+	ActorUserID   int64
+	Perfil        string
+}
+
+// tarefaDeAlerta monta a tarefa a partir do alerta (BR-TAREFA-003); datas passadas viram hoje.
+func tarefaDeAlerta(a *models.AlertaWithNames, in ConverterAlertaInput, hoje time.Time) TarefaInput {
+	titulo := strings.TrimSpace(a.Titulo)
+	if utf8.RuneCountInString(titulo) > models.TarefaTituloMaxLen {
+		titulo = string([]rune(titulo)[:models.TarefaTituloMaxLen])
+	}
+	data := in.DataPrevista
+	if data == nil {
+		data = a.DataPrevista
+	}
+	if data == nil || truncateDateUTC(*data).Before(hoje) {
+		data = &hoje
+	}
+	responsavel := in.ResponsavelID
+	if responsavel == nil {
+		responsavel = a.ResponsavelID
+	}
+	return TarefaInput{
+		Titulo:        titulo,
+		Descricao:     a.Descricao,
+		AnimalID:      a.AnimalID,
+		ResponsavelID: responsavel,
+		DataPrevista:  data,
+		Recorrencia:   models.TarefaRecorrenciaNenhuma,
+		Checklist:     in.Checklist,
+		ActorUserID:   in.ActorUserID,
+		Perfil:        in.Perfil,
+	}
+}
+
+// ConverterAlerta cria uma tarefa a partir de um alerta em aberto (manual ou gerado) e registra a
+// conversão no histórico do alerta; o alerta segue o próprio fluxo (BR-TAREFA-003).
+func (s *TarefaService) ConverterAlerta(ctx context.Context, fazendaID, alertaID int64, in ConverterAlertaInput) (*models.TarefaWithNames, error) {
+	if !models.PodeGerenciarTarefas(in.Perfil) {
+		return nil, ErrTarefaForbidden
+	}
+	a, err := s.alertas.GetByID(ctx, fazendaID, alertaID)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && a == nil) {
+		return nil, ErrAlertaNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if a.Status == models.AlertaStatusResolvido || a.Status == models.AlertaStatusIgnorado {
+		return nil, ErrTarefaAlertaEncerrado
+	}
+	tin := tarefaDeAlerta(a, in, truncateDateUTC(s.now()))
+	if err := normalizarTarefaInput(&tin); err != nil {
+		return nil, err
+	}
+	nome, err := s.validarResponsavel(ctx, fazendaID, tin.ResponsavelID)
+	if err != nil {
+		return nil, err
+	}
+	t := &models.Tarefa{
+		FazendaID:     fazendaID,
+		Titulo:        tin.Titulo,
+		Descricao:     tin.Descricao,
+		AnimalID:      tin.AnimalID,
+		AlertaID:      &alertaID,
+		ResponsavelID: tin.ResponsavelID,
+		DataPrevista:  *tin.DataPrevista,
+		Recorrencia:   tin.Recorrencia,
+		Status:        models.TarefaStatusAberta,
+		CreatedBy:     &in.ActorUserID,
+	}
+	if err := s.repo.Create(ctx, t, tin.Checklist); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrTarefaAlertaJaConvertido
+		}
+		return nil, err
+	}
+	texto := fmt.Sprintf("Convertido na tarefa #%d", t.ID)
+	if nome != "" {
+		texto += " para " + nome
+	}
+	s.registrarAtividadeAlerta(ctx, fazendaID, alertaID, in.ActorUserID, texto,
+		map[string]interface{}{"tarefa_id": t.ID, "responsavel_id": t.ResponsavelID})
+	s.notificarResponsavel(t, in.ActorUserID)
+	return s.GetByID(ctx, fazendaID, t.ID)
+}
+
+// motivoFolgaNoDia indica se o usuário está de folga (escala) ou ausente na data; motivo é FOLGA ou
+// o tipo da ausência (BR-TAREFA-004).
+func motivoFolgaNoDia(usuarioID int64, folgas []models.EscalaFolga, ausencias []models.FolgaAusencia) (bool, string) {
+	for _, a := range ausencias {
+		if a.UsuarioID == usuarioID {
+			return true, a.Tipo
+		}
+	}
+	for _, f := range folgas {
+		if f.UsuarioID == usuarioID {
+			return true, "FOLGA"
+		}
+	}
+	return false, ""
+}
+
+// MinhasHoje tarefas abertas do usuário previstas até a data (atrasadas incluídas). De folga ou
+// ausente na escala, a lista vem vazia e Pendentes conta o que ficou para a gestão (BR-TAREFA-004).
+func (s *TarefaService) MinhasHoje(ctx context.Context, fazendaID, usuarioID int64, data *time.Time) (*models.MinhasTarefasHoje, error) {
+	dia := truncateDateUTC(s.now())
+	if data != nil {
+		dia = truncateDateUTC(*data)
+	}
+	folgas, err := s.folgas.ListFolgasUsuarioOnDate(ctx, fazendaID, dia)
+	if err != nil {
+		return nil, err
+	}
+	ausencias, err := s.folgas.ListAusenciasRange(ctx, fazendaID, dia, dia, &usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	tarefas, total, err := s.repo.ListByFazenda(ctx, fazendaID, repository.TarefaListFilters{
+		Abertas:       true,
+		ResponsavelID: &usuarioID,
+		Fim:           &dia,
+		Limit:         200,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := &models.MinhasTarefasHoje{Data: dia, Pendentes: int(total), Tarefas: tarefas}
+	out.DeFolga, out.Motivo = motivoFolgaNoDia(usuarioID, folgas, ausencias)
+	if out.DeFolga {
+		out.Tarefas = []models.TarefaWithNames{}
+	}
+	return out, nil
+}
+
+func (s *TarefaService) registrarAtividadeAlerta(ctx context.Context, fazendaID, alertaID, actorID int64, texto string, dados map[string]interface{}) {
+	if s.atividades == nil {
+		return
+	}
+	if err := s.atividades.Create(ctx, &models.AlertaAtividade{
+		AlertaID:  alertaID,
+		FazendaID: fazendaID,
+		UsuarioID: &actorID,
+		Tipo:      models.AlertaAtividadeTarefa,
+		Texto:     texto,
+		Dados:     alertaAtividadeDados(dados),
+	}); err != nil {
+		slog.Warn("tarefas: registrar atividade no alerta", "error", err, "alerta_id", alertaID)
+	}
+}
+
+func (s *TarefaService) notificarResponsavel(t *models.Tarefa, actorID int64) {
+	if s.pushSvc == nil || t.ResponsavelID == nil || *t.ResponsavelID == actorID {
+		return
+	}
+	s.pushSvc.NotifyUsuarios([]int64{*t.ResponsavelID}, "Tarefa atribuída a você", t.Titulo,
+		fmt.Sprintf("/tarefas?id=%d", t.ID))
+}
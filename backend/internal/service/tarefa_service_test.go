@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ceialmilk/api/internal/models"
+	"github.com/ceialmilk/api/internal/repository"
+)
+
+func tarefaData(s string) *time.Time {
+	d, _ := time.Parse("2006-01-02", s)
+	return &d
+}
+
+func TestNormalizarTarefaInput(t *testing.T) {
+	desc := "  "
+	in := TarefaInput{
+		Titulo:       "  Limpar bebedouros ",
+		Descricao:    &desc,
+		DataPrevista: tarefaData("2026-10-20"),
+		Checklist:    []string{" Esvaziar ", "", "Escovar"},
+	}
+	if err := normalizarTarefaInput(&in); err != nil {
+		t.Fatal(err)
+	}
+	if in.Titulo != "Limpar bebedouros" || in.Descricao != nil || in.Recorrencia != models.TarefaRecorrenciaNenhuma {
+		t.Errorf("normalização = %+v", in)
+	}
+	if len(in.Checklist) != 2 || in.Checklist[0] != "Esvaziar" {
+		t.Errorf("checklist = %q", in.Checklist)
+	}
+
+	casos := []struct {
+		nome string
+		in   TarefaInput
+		want error
+	}{
+		{"sem título", TarefaInput{Titulo: " ", DataPrevista: tarefaData("2026-10-20")}, ErrTarefaTituloInvalido},
+		{"título longo", TarefaInput{Titulo: strings.Repeat("a", 201), DataPrevista: tarefaData("2026-10-20")}, ErrTarefaTituloInvalido},
+		{"sem data", TarefaInput{Titulo: "x"}, ErrTarefaDataObrigatoria},
+		{"recorrência", TarefaInput{Titulo: "x", DataPrevista: tarefaData("2026-10-20"), Recorrencia: "MENSAL"}, ErrTarefaRecorrenciaInvalida},
+		{"checklist grande", TarefaInput{Titulo: "x", DataPrevista: tarefaData("2026-10-20"), Checklist: make31Itens()}, ErrTarefaChecklistInvalida},
+	}
+	for _, c := range casos {
+		if err := normalizarTarefaInput(&c.in); !errors.Is(err, c.want) {
+			t.Errorf("%s: err = %v, want %v", c.nome, err, c.want)
+		}
+	}
+}
+
+func make31Itens() []string {
+	out := make([]string, 31)
+	for i := range out {
+		out[i] = "item"
+	}
+	return out
+}
+
+func TestTransicaoTarefaStatus(t *testing.T) {
+	validas := [][2]string{
+		{models.TarefaStatusAberta, models.TarefaStatusEmAndamento},
+		{models.TarefaStatusAberta, models.TarefaStatusConcluida},
+		{models.TarefaStatusEmAndamento, models.TarefaStatusConcluida},
+		{models.TarefaStatusEmAndamento, models.TarefaStatusCancelada},
+	}
+	for _, v := range validas {
+		if !models.IsTransicaoTarefaStatusValida(v[0], v[1]) {
+			t.Errorf("%s -> %s deveria ser válida", v[0], v[1])
+		}
+	}
+	invalidas := [][2]string{
+		{models.TarefaStatusEmAndamento, models.TarefaStatusAberta},
+		{models.TarefaStatusConcluida, models.TarefaStatusAberta},
+		{models.TarefaStatusCancelada, models.TarefaStatusConcluida},
+	}
+	for _, v := range invalidas {
+		if models.IsTransicaoTarefaStatusValida(v[0], v[1]) {
+			t.Errorf("%s -> %s deveria ser inválida", v[0], v[1])
+		}
+	}
+}
+
+func TestPodeExecutarTarefa(t *testing.T) {
+	outro := int64(9)
+	proprio := int64(3)
+	if !podeExecutarTarefa(&models.Tarefa{ResponsavelID: &outro}, 3, models.PerfilGerente) {
+		t.Error("gestão executa qualquer tarefa")
+	}
+	if podeExecutarTarefa(&models.Tarefa{ResponsavelID: &outro}, 3, models.PerfilFuncionario) {
+		t.Error("funcionário não executa tarefa de outro")
+	}
+	if !podeExecutarTarefa(&models.Tarefa{ResponsavelID: &proprio}, 3, models.PerfilFuncionario) {
+		t.Error("funcionário executa a própria")
+	}
+	if !podeExecutarTarefa(&models.Tarefa{}, 3, models.PerfilFuncionario) {
+		t.Error("funcionário assume tarefa sem responsável")
+	}
+	if podeExecutarTarefa(&models.Tarefa{}, 3, models.PerfilUser) {
+		t.Error("USER não executa")
+	}
+}
+
+func TestProximaOcorrencia(t *testing.T) {
+	base := models.Tarefa{ID: 10, FazendaID: 1, Titulo: "Ordenha", DataPrevista: *tarefaData("2026-10-20"), Recorrencia: models.TarefaRecorrenciaSemanal}
+	p := proximaOcorrencia(&base, 5)
+	if p == nil || !p.DataPrevista.Equal(*tarefaData("2026-10-27")) || p.SerieID == nil || *p.SerieID != 10 {
+		t.Fatalf("próxima = %+v", p)
+	}
+	if p.Status != models.TarefaStatusAberta || *p.CreatedBy != 5 {
+		t.Errorf("próxima = %+v", p)
+	}
+	serie := int64(4)
+	diaria := models.Tarefa{ID: 12, DataPrevista: *tarefaData("2026-12-31"), Recorrencia: models.TarefaRecorrenciaDiaria, SerieID: &serie}
+	if p := proximaOcorrencia(&diaria, 5); !p.DataPrevista.Equal(*tarefaData("2027-01-01")) || *p.SerieID != 4 {
+		t.Errorf("diária = %+v", p)
+	}
+	if proximaOcorrencia(&models.Tarefa{Recorrencia: models.TarefaRecorrenciaNenhuma}, 5) != nil {
+		t.Error("sem recorrência não gera próxima")
+	}
+}
+
+func TestTarefaDeAlerta(t *testing.T) {
+	hoje := *tarefaData("2026-10-18")
+	animal := int64(7)
+	resp := int64(2)
+	a := &models.AlertaWithNames{Alerta: models.Alerta{
+		ID: 1, Titulo: "Vacina vencida: 123", AnimalID: &animal, ResponsavelID: &resp, DataPrevista: tarefaData("2026-10-01"),
+	}}
+	in := tarefaDeAlerta(a, ConverterAlertaInput{}, hoje)
+	if in.Titulo != a.Titulo || *in.AnimalID != 7 || *in.ResponsavelID != 2 {
+		t.Errorf("input = %+v", in)
+	}
+	if !in.DataPrevista.Equal(hoje) {
+		t.Errorf("data passada deveria virar hoje, veio %v", in.DataPrevista)
+	}
+	outro := int64(5)
+	in = tarefaDeAlerta(a, ConverterAlertaInput{ResponsavelID: &outro, DataPrevista: tarefaData("2026-10-25")}, hoje)
+	if *in.ResponsavelID != 5 || !in.DataPrevista.Equal(*tarefaData("2026-10-25")) {
+		t.Errorf("input = %+v", in)
+	}
+}
+
+type fakeTarefaStore struct {
+	tarefaStore
+	filtros repository.TarefaListFilters
+	lista   []models.TarefaWithNames
+}
+
+func (f *fakeTarefaStore) ListByFazenda(_ context.Context, _ int64, fl repository.TarefaListFilters) ([]models.TarefaWithNames, int64, error) {
+	f.filtros = fl
+	return f.lista, int64(len(f.lista)), nil
+}
+
+type fakeTarefaFolgas struct {
+	folgas    []models.EscalaFolga
+	ausencias []models.FolgaAusencia
+}
+
+func (f fakeTarefaFolgas) ListFolgasUsuarioOnDate(context.Context, int64, time.Time) ([]models.EscalaFolga, error) {
+	return f.folgas, nil
+}
+
+func (f fakeTarefaFolgas) ListAusenciasRange(context.Context, int64, time.Time, time.Time, *int64) ([]models.FolgaAusencia, error) {
+	return f.ausencias, nil
+}
+
+func TestMinhasHojeRespeitaFolga(t *testing.T) {
+	agora := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	store := &fakeTarefaStore{lista: []models.TarefaWithNames{{Tarefa: models.Tarefa{ID: 1}}, {Tarefa: models.Tarefa{ID: 2}}}}
+	s := &TarefaService{repo: store, folgas: fakeTarefaFolgas{}, now: func() time.Time { return agora }}
+
+	r, err := s.MinhasHoje(context.Background(), 1, 3, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.DeFolga || len(r.Tarefas) != 2 || r.Pendentes != 2 {
+		t.Errorf("dia de trabalho = %+v", r)
+	}
+	if !store.filtros.Abertas || *store.filtros.ResponsavelID != 3 || !store.filtros.Fim.Equal(*tarefaData("2026-10-18")) {
+		t.Errorf("filtros = %+v", store.filtros)
+	}
+
+	s.folgas = fakeTarefaFolgas{folgas: []models.EscalaFolga{{UsuarioID: 3}}}
+	r, _ = s.MinhasHoje(context.Background(), 1, 3, nil)
+	if !r.DeFolga || r.Motivo != "FOLGA" || len(r.Tarefas) != 0 || r.Pendentes != 2 {
+		t.Errorf("de folga = %+v", r)
+	}
+
+	s.folgas = fakeTarefaFolgas{folgas: []models.EscalaFolga{{UsuarioID: 4}}, ausencias: []models.FolgaAusencia{{UsuarioID: 3, Tipo: models.FolgaAusenciaFerias}}}
+	r, _ = s.MinhasHoje(context.Background(), 1, 3, nil)
+	if !r.DeFolga || r.Motivo != models.FolgaAusenciaFerias {
+		t.Errorf("de férias = %+v", r)
+	}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TOTP (RFC 6238) com os parâmetros que os apps autenticadores assumem por omissão:
+// HMAC-SHA1, 6 dígitos, passo de 30 s (BR-ACESSO-027).
+const (
+	totpDigitos = 6
+	totpPeriodo = 30
+	// totpJanela passos aceitos antes/depois do atual (tolerância de relógio do telemóvel).
+	totpJanela = 1
+)
+
+var totpBase32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// gerarSegredoTOTP 160 bits aleatórios em base32 sem padding (formato do parâmetro secret do otpauth).
+func gerarSegredoTOTP() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return totpBase32.EncodeToString(b), nil
+}
+
+// totpURI URI otpauth:// para o QR code de inscrição.
+func totpURI(emissor, conta, segredo string) string {
+	rotulo := url.PathEscape(emissor + ":" + conta)
+	q := url.Values{}
+	q.Set("secret", segredo)
+	q.Set("issuer", emissor)
+	q.Set("algorithm", "SHA1")
+	q.Set("digits", fmt.Sprint(totpDigitos))
+	q.Set("period", fmt.Sprint(totpPeriodo))
+	return "otpauth://totp/" + rotulo + "?" + q.Encode()
+}
+
+// totpPasso contador de tempo do instante em.
+func totpPasso(em time.Time) int64 {
+	return em.Unix() / totpPeriodo
+}
+
+// codigoTOTP código de 6 dígitos do passo (HOTP com truncagem dinâmica, RFC 4226).
+func codigoTOTP(segredo string, passo int64) (string, error) {
+	chave, err := totpBase32.DecodeString(strings.ToUpper(strings.TrimRight(segredo, "=")))
+	if err != nil {
+		return "", err
+	}
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(passo))
+	mac := hmac.New(sha1.New, chave)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	off := sum[len(sum)-1] & 0x0f
+	bin := binary.BigEndian.Uint32(sum[off:off+4]) & 0x7fffffff
+	return fmt.Sprintf("%06d", bin%1_000_000), nil
+}
+
+// verificarTOTP procura o código na janela em torno de em e devolve o passo que casou. Passos até
+// ultimoPasso (inclusive) são recusados: cada código só autentica uma vez.
+func verificarTOTP(segredo, codigo string, em time.Time, ultimoPasso int64) (int64, bool) {
+	codigo = strings.ReplaceAll(strings.TrimSpace(codigo), " ", "")
+	if len(codigo) != totpDigitos {
+		return 0, false
+	}
+	atual := totpPasso(em)
+	for d := -totpJanela; d <= totpJanela; d++ {
+		passo := atual + int64(d)
+		if passo <= ultimoPasso {
+			continue
+		}
+		esperado, err := codigoTOTP(segredo, passo)
+		if err != nil {
+			return 0, false
+		}
+		if hmac.Equal([]byte(esperado), []byte(codigo)) {
+			return passo, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"encoding/base32"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Vetores do RFC 6238 (apêndice B, SHA1) truncados para 6 dígitos.
+func TestCodigoTOTP_RFC6238(t *testing.T) {
+	segredo := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+	casos := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, c := range casos {
+		got, err := codigoTOTP(segredo, totpPasso(time.Unix(c.unix, 0)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.want {
+			t.Errorf("T=%d: código %s, want %s", c.unix, got, c.want)
+		}
+	}
+}
+
+func TestVerificarTOTP(t *testing.T) {
+	segredo, err := gerarSegredoTOTP()
+	if err != nil {
+		t.Fatal(err)
+	}
+	em := time.Unix(1_800_000_000, 0)
+	passo := totpPasso(em)
+	anterior, _ := codigoTOTP(segredo, passo-1)
+	if got, ok := verificarTOTP(segredo, anterior, em, 0); !ok || got != passo-1 {
+		t.Errorf("passo anterior dentro da janela: ok=%v passo=%d", ok, got)
+	}
+	if _, ok := verificarTOTP(segredo, anterior, em, passo-1); ok {
+		t.Error("código já usado não pode autenticar de novo")
+	}
+	velho, _ := codigoTOTP(segredo, passo-3)
+	if _, ok := verificarTOTP(segredo, velho, em, 0); ok {
+		t.Error("código fora da janela aceito")
+	}
+	atual, _ := codigoTOTP(segredo, passo)
+	if _, ok := verificarTOTP(segredo, atual[:3]+" "+atual[3:], em, 0); !ok {
+		t.Error("espaços digitados devem ser ignorados")
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri := totpURI("CeialMilk", "ana@exemplo.pt", "ABC")
+	u, err := url.Parse(uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Scheme != "otpauth" || u.Host != "totp" || !strings.HasPrefix(u.Path, "/CeialMilk:ana@exemplo.pt") {
+		t.Errorf("uri = %s", uri)
+	}
+	if q := u.Query(); q.Get("secret") != "ABC" || q.Get("issuer") != "CeialMilk" || q.Get("digits") != "6" {
+		t.Errorf("query = %v", q)
+	}
+}
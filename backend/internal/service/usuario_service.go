@@ -33,14 +33,25 @@ func normalizarPerfilAPI(p string) string {
 	return models.PerfilUser
 }
 
+type sessaoRevoker interface {
+	RevokeAllForUser(ctx context.Context, userID int64) error
+}
+
 type UsuarioService struct {
+	auditavel
 	repo *repository.UsuarioRepository
+	// sessoes opcional: troca de senha pelo admin encerra as sessões do usuário (BR-ACESSO-026).
+	sessoes sessaoRevoker
 }
 
 func NewUsuarioService(repo *repository.UsuarioRepository) *UsuarioService {
 	return &UsuarioService{repo: repo}
 }
 
+func (s *UsuarioService) SetSessaoRevoker(r sessaoRevoker) {
+	s.sessoes = r
+}
+
 func (s *UsuarioService) List(ctx context.Context, limit, offset int) ([]*models.Usuario, error) {
 	if limit <= 0 {
 		limit = 20
@@ -109,7 +120,11 @@ func (s *UsuarioService) Create(ctx context.Context, u *models.Usuario) error {
 	u.Senha = string(hash)
 	u.Enabled = true
 
-	return s.repo.Create(ctx, u)
+	if err := s.repo.Create(ctx, u); err != nil {
+		return err
+	}
+	s.auditar(ctx, models.AuditoriaAcaoCreate, models.AuditoriaEntidadeUsuario, u.ID, 0, 0, nil, u)
+	return nil
 }
 
 func (s *UsuarioService) Update(ctx context.Context, u *models.Usuario) error {
@@ -148,14 +163,26 @@ func (s *UsuarioService) Update(ctx context.Context, u *models.Usuario) error {
 	// Manter senha se não estiver alterando
 	if u.Senha == "" {
 		u.Senha = atual.Senha
-		return s.repo.Update(ctx, u)
-	}
-	hash, err := bcrypt.GenerateFromPassword([]byte(u.Senha), bcryptCost)
-	if err != nil {
-		return err
+		if err := s.repo.Update(ctx, u); err != nil {
+			return err
+		}
+	} else {
+		hash, err := bcrypt.GenerateFromPassword([]byte(u.Senha), bcryptCost)
+		if err != nil {
+			return err
+		}
+		u.Senha = string(hash)
+		if err := s.repo.UpdateWithPassword(ctx, u); err != nil {
+			return err
+		}
+		if s.sessoes != nil {
+			if err := s.sessoes.RevokeAllForUser(ctx, u.ID); err != nil {
+				return err
+			}
+		}
 	}
-	u.Senha = string(hash)
-	return s.repo.UpdateWithPassword(ctx, u)
+	s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeUsuario, u.ID, 0, 0, atual, u)
+	return nil
 }
 
 func (s *UsuarioService) ToggleEnabled(ctx context.Context, id int64) error {
@@ -166,5 +193,11 @@ func (s *UsuarioService) ToggleEnabled(ctx context.Context, id int64) error {
 		}
 		return err
 	}
-	return s.repo.ToggleEnabled(ctx, id, !u.Enabled)
+	if err := s.repo.ToggleEnabled(ctx, id, !u.Enabled); err != nil {
+		return err
+	}
+	depois := *u
+	depois.Enabled = !u.Enabled
+	s.auditar(ctx, models.AuditoriaAcaoUpdate, models.AuditoriaEntidadeUsuario, id, 0, 0, u, &depois)
+	return nil
 }
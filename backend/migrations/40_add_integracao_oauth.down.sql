@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_integracao_clientes_oauth_client_id;
+
+ALTER TABLE integracao_clientes
+    DROP COLUMN IF EXISTS tokens_validos_desde,
+    DROP COLUMN IF EXISTS oauth_secret_hash,
+    DROP COLUMN IF EXISTS oauth_client_id;
@@ -0,0 +1,11 @@
+-- OAuth2 client credentials para clientes de integração (BR-INTEG-015).
+-- tokens_validos_desde: access tokens emitidos antes deste instante são rejeitados (revogação).
+
+ALTER TABLE integracao_clientes
+    ADD COLUMN oauth_client_id VARCHAR(64),
+    ADD COLUMN oauth_secret_hash TEXT,
+    ADD COLUMN tokens_validos_desde TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX idx_integracao_clientes_oauth_client_id
+    ON integracao_clientes (oauth_client_id)
+    WHERE oauth_client_id IS NOT NULL;
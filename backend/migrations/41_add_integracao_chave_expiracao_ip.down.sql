@@ -0,0 +1,19 @@
+DROP INDEX IF EXISTS idx_integracao_chamadas_rejeicoes;
+
+DELETE FROM integracao_chamadas WHERE cliente_id IS NULL;
+
+ALTER TABLE integracao_chamadas
+    DROP COLUMN IF EXISTS ip,
+    DROP COLUMN IF EXISTS motivo_rejeicao,
+    ALTER COLUMN cliente_id SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_integracao_clientes_chave_expira_em;
+DROP INDEX IF EXISTS idx_integracao_clientes_key_prefix_anterior;
+
+ALTER TABLE integracao_clientes
+    DROP COLUMN IF EXISTS aviso_expiracao_ref,
+    DROP COLUMN IF EXISTS ip_allowlist,
+    DROP COLUMN IF EXISTS chave_anterior_expira_em,
+    DROP COLUMN IF EXISTS key_hash_anterior,
+    DROP COLUMN IF EXISTS key_prefix_anterior,
+    DROP COLUMN IF EXISTS chave_expira_em;
@@ -0,0 +1,24 @@
+-- Expiração agendada da API key, período de graça na rotação, allowlist de IPs e motivo de rejeição na auditoria.
+
+ALTER TABLE integracao_clientes
+    ADD COLUMN chave_expira_em TIMESTAMPTZ,
+    ADD COLUMN key_prefix_anterior VARCHAR(32),
+    ADD COLUMN key_hash_anterior TEXT,
+    ADD COLUMN chave_anterior_expira_em TIMESTAMPTZ,
+    ADD COLUMN ip_allowlist TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN aviso_expiracao_ref TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX idx_integracao_clientes_key_prefix_anterior
+    ON integracao_clientes (key_prefix_anterior) WHERE key_prefix_anterior IS NOT NULL;
+
+CREATE INDEX idx_integracao_clientes_chave_expira_em
+    ON integracao_clientes (chave_expira_em) WHERE chave_expira_em IS NOT NULL AND revogado_em IS NULL;
+
+-- Rejeições de clientes não identificados (prefixo desconhecido, token inválido) não têm cliente_id.
+ALTER TABLE integracao_chamadas
+    ALTER COLUMN cliente_id DROP NOT NULL,
+    ADD COLUMN motivo_rejeicao VARCHAR(40),
+    ADD COLUMN ip VARCHAR(64);
+
+CREATE INDEX idx_integracao_chamadas_rejeicoes
+    ON integracao_chamadas (created_at DESC) WHERE motivo_rejeicao IS NOT NULL;
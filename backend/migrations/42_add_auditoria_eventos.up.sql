@@ -0,0 +1,23 @@
+-- Trilha de auditoria genérica: quem alterou o quê (diff JSON antes/depois), correlacionada ao pedido HTTP.
+-- Sem FKs para fazenda/animal/entidade: o histórico sobrevive à exclusão do registo auditado.
+
+CREATE TABLE auditoria_eventos (
+    id BIGSERIAL PRIMARY KEY,
+    usuario_id BIGINT REFERENCES usuarios(id) ON DELETE SET NULL,
+    perfil VARCHAR(32) NOT NULL,
+    fazenda_id BIGINT,
+    animal_id BIGINT,
+    entidade VARCHAR(40) NOT NULL,
+    entidade_id BIGINT NOT NULL,
+    acao VARCHAR(10) NOT NULL,
+    diff JSONB NOT NULL DEFAULT '{}'::jsonb,
+    correlation_id VARCHAR(64),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT auditoria_eventos_acao_check CHECK (acao IN ('CREATE', 'UPDATE', 'DELETE'))
+);
+
+CREATE INDEX idx_auditoria_eventos_animal ON auditoria_eventos (animal_id, created_at DESC) WHERE animal_id IS NOT NULL;
+CREATE INDEX idx_auditoria_eventos_usuario ON auditoria_eventos (usuario_id, created_at DESC);
+CREATE INDEX idx_auditoria_eventos_fazenda ON auditoria_eventos (fazenda_id, created_at DESC);
+CREATE INDEX idx_auditoria_eventos_entidade ON auditoria_eventos (entidade, entidade_id, created_at DESC);
+CREATE INDEX idx_auditoria_eventos_correlation ON auditoria_eventos (correlation_id) WHERE correlation_id IS NOT NULL;
@@ -0,0 +1,20 @@
+DELETE FROM auditoria_eventos WHERE acao = 'RESTORE';
+ALTER TABLE auditoria_eventos DROP CONSTRAINT auditoria_eventos_acao_check;
+ALTER TABLE auditoria_eventos
+    ADD CONSTRAINT auditoria_eventos_acao_check CHECK (acao IN ('CREATE', 'UPDATE', 'DELETE'));
+
+-- Itens ainda na lixeira são apagados definitivamente ao reverter.
+DELETE FROM producao_leite WHERE excluido_em IS NOT NULL;
+DELETE FROM coberturas WHERE excluido_em IS NOT NULL;
+DELETE FROM cios WHERE excluido_em IS NOT NULL;
+DELETE FROM partos WHERE excluido_em IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_producao_leite_excluido;
+DROP INDEX IF EXISTS idx_coberturas_excluido;
+DROP INDEX IF EXISTS idx_cios_excluido;
+DROP INDEX IF EXISTS idx_partos_excluido;
+
+ALTER TABLE producao_leite DROP COLUMN excluido_por, DROP COLUMN excluido_em;
+ALTER TABLE coberturas DROP COLUMN excluido_por, DROP COLUMN excluido_em;
+ALTER TABLE cios DROP COLUMN excluido_por, DROP COLUMN excluido_em;
+ALTER TABLE partos DROP COLUMN excluido_animais_gerados, DROP COLUMN excluido_por, DROP COLUMN excluido_em;
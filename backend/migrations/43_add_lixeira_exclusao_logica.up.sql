@@ -0,0 +1,31 @@
+-- Exclusão lógica (lixeira) de partos, cios, coberturas e produção de leite.
+-- Registos com excluido_em preenchido ficam fora das leituras e podem ser restaurados dentro da retenção;
+-- depois disso são apagados definitivamente pelo cron da lixeira.
+
+ALTER TABLE partos
+    ADD COLUMN excluido_em TIMESTAMPTZ,
+    ADD COLUMN excluido_por BIGINT REFERENCES usuarios(id) ON DELETE SET NULL,
+    -- Animais gerados pelas crias (removidos na exclusão) para recriar na restauração.
+    ADD COLUMN excluido_animais_gerados JSONB;
+
+ALTER TABLE cios
+    ADD COLUMN excluido_em TIMESTAMPTZ,
+    ADD COLUMN excluido_por BIGINT REFERENCES usuarios(id) ON DELETE SET NULL;
+
+ALTER TABLE coberturas
+    ADD COLUMN excluido_em TIMESTAMPTZ,
+    ADD COLUMN excluido_por BIGINT REFERENCES usuarios(id) ON DELETE SET NULL;
+
+ALTER TABLE producao_leite
+    ADD COLUMN excluido_em TIMESTAMPTZ,
+    ADD COLUMN excluido_por BIGINT REFERENCES usuarios(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_partos_excluido ON partos (fazenda_id, excluido_em) WHERE excluido_em IS NOT NULL;
+CREATE INDEX idx_cios_excluido ON cios (fazenda_id, excluido_em) WHERE excluido_em IS NOT NULL;
+CREATE INDEX idx_coberturas_excluido ON coberturas (fazenda_id, excluido_em) WHERE excluido_em IS NOT NULL;
+CREATE INDEX idx_producao_leite_excluido ON producao_leite (excluido_em) WHERE excluido_em IS NOT NULL;
+
+-- Restauração é registada na trilha de auditoria.
+ALTER TABLE auditoria_eventos DROP CONSTRAINT auditoria_eventos_acao_check;
+ALTER TABLE auditoria_eventos
+    ADD CONSTRAINT auditoria_eventos_acao_check CHECK (acao IN ('CREATE', 'UPDATE', 'DELETE', 'RESTORE'));
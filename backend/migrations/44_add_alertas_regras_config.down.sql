@@ -0,0 +1 @@
+DROP TABLE IF EXISTS alertas_regras_config;
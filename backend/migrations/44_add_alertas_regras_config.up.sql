@@ -0,0 +1,19 @@
+-- Configuração por fazenda das regras de geração automática de alertas (BR-ALERTA-019).
+-- Sem linha para (fazenda, tipo) = regra ativa com os valores padrão; colunas NULL = padrão.
+
+CREATE TABLE IF NOT EXISTS alertas_regras_config (
+    fazenda_id BIGINT NOT NULL REFERENCES fazendas(id) ON DELETE CASCADE,
+    tipo VARCHAR(40) NOT NULL,
+    ativo BOOLEAN NOT NULL DEFAULT true,
+    dias INT,
+    severidade VARCHAR(10),
+    push_perfis TEXT[],
+    updated_by BIGINT REFERENCES usuarios(id) ON DELETE SET NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (fazenda_id, tipo),
+    CONSTRAINT alertas_regras_config_severidade_check
+        CHECK (severidade IS NULL OR severidade IN ('CRITICA', 'ALTA', 'MEDIA', 'BAIXA')),
+    CONSTRAINT alertas_regras_config_dias_check CHECK (dias IS NULL OR dias >= 0)
+);
+
+ALTER TABLE alertas_regras_config ENABLE ROW LEVEL SECURITY;
@@ -0,0 +1,29 @@
+ALTER TABLE alertas_geracao_estado DROP COLUMN IF EXISTS custom_chaves;
+
+DELETE FROM alertas WHERE tipo = 'CUSTOM';
+
+DROP INDEX IF EXISTS uq_alertas_aberto_custom_animal;
+DROP INDEX IF EXISTS uq_alertas_aberto_tipo_animal;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_alertas_aberto_tipo_animal
+    ON alertas (fazenda_id, tipo, animal_id)
+    WHERE status IN ('ABERTO', 'EM_ANDAMENTO')
+      AND tipo <> 'MANUAL'
+      AND animal_id IS NOT NULL;
+
+ALTER TABLE alertas DROP CONSTRAINT IF EXISTS alertas_tipo_check;
+ALTER TABLE alertas ADD CONSTRAINT alertas_tipo_check CHECK (tipo IN (
+    'TRATAMENTO_VENCIDO',
+    'PARTO_PREVISTO',
+    'RESTRICAO_LEITE_ATIVA',
+    'NAO_CONFORMIDADE',
+    'GESTACAO_SEM_SECAGEM',
+    'CIO_DETECTADO',
+    'VACINA_VENCIDA',
+    'VACINA_REFORCO_VENCIDA',
+    'HORMONIO_LACTACAO_PENDENTE',
+    'MANUAL'
+));
+
+ALTER TABLE alertas DROP COLUMN IF EXISTS regra_custom_id;
+
+DROP TABLE IF EXISTS alertas_regras_custom;
@@ -0,0 +1,59 @@
+-- Regras de alerta personalizadas por fazenda (BR-ALERTA-020): expressão avaliada pela geração diária,
+-- alertas do tipo CUSTOM ligados à regra e chaves de deduplicação em alertas_geracao_estado.
+
+CREATE TABLE IF NOT EXISTS alertas_regras_custom (
+    id BIGSERIAL PRIMARY KEY,
+    fazenda_id BIGINT NOT NULL REFERENCES fazendas(id) ON DELETE CASCADE,
+    nome VARCHAR(120) NOT NULL,
+    descricao TEXT,
+    expressao TEXT NOT NULL,
+    severidade VARCHAR(10) NOT NULL DEFAULT 'MEDIA',
+    ativo BOOLEAN NOT NULL DEFAULT true,
+    push_perfis TEXT[],
+    created_by BIGINT REFERENCES usuarios(id) ON DELETE SET NULL,
+    updated_by BIGINT REFERENCES usuarios(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT alertas_regras_custom_severidade_check
+        CHECK (severidade IN ('CRITICA', 'ALTA', 'MEDIA', 'BAIXA'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_alertas_regras_custom_fazenda ON alertas_regras_custom (fazenda_id);
+
+ALTER TABLE alertas_regras_custom ENABLE ROW LEVEL SECURITY;
+
+ALTER TABLE alertas
+    ADD COLUMN IF NOT EXISTS regra_custom_id BIGINT REFERENCES alertas_regras_custom(id) ON DELETE SET NULL;
+
+ALTER TABLE alertas DROP CONSTRAINT IF EXISTS alertas_tipo_check;
+ALTER TABLE alertas ADD CONSTRAINT alertas_tipo_check CHECK (tipo IN (
+    'TRATAMENTO_VENCIDO',
+    'PARTO_PREVISTO',
+    'RESTRICAO_LEITE_ATIVA',
+    'NAO_CONFORMIDADE',
+    'GESTACAO_SEM_SECAGEM',
+    'CIO_DETECTADO',
+    'VACINA_VENCIDA',
+    'VACINA_REFORCO_VENCIDA',
+    'HORMONIO_LACTACAO_PENDENTE',
+    'CUSTOM',
+    'MANUAL'
+));
+
+-- Vários alertas CUSTOM abertos por animal (um por regra).
+DROP INDEX IF EXISTS uq_alertas_aberto_tipo_animal;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_alertas_aberto_tipo_animal
+    ON alertas (fazenda_id, tipo, animal_id)
+    WHERE status IN ('ABERTO', 'EM_ANDAMENTO')
+      AND tipo NOT IN ('MANUAL', 'CUSTOM')
+      AND animal_id IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_alertas_aberto_custom_animal
+    ON alertas (regra_custom_id, animal_id)
+    WHERE status IN ('ABERTO', 'EM_ANDAMENTO')
+      AND tipo = 'CUSTOM'
+      AND regra_custom_id IS NOT NULL
+      AND animal_id IS NOT NULL;
+
+ALTER TABLE alertas_geracao_estado
+    ADD COLUMN IF NOT EXISTS custom_chaves JSONB NOT NULL DEFAULT '[]'::jsonb;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS notificacoes_digest_fila;
+DROP TABLE IF EXISTS notificacao_preferencias;
+DROP TABLE IF EXISTS notificacao_config_usuario;
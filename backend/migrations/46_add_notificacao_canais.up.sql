@@ -0,0 +1,50 @@
+-- Canais de notificação de alertas além do Web Push (BR-ALERTA-021): preferências por utilizador e canal,
+-- horário de silêncio e fila do resumo (digest).
+
+CREATE TABLE IF NOT EXISTS notificacao_config_usuario (
+    usuario_id BIGINT PRIMARY KEY REFERENCES usuarios(id) ON DELETE CASCADE,
+    telefone VARCHAR(20),
+    silencio_inicio VARCHAR(5),
+    silencio_fim VARCHAR(5),
+    silencio_permite_critica BOOLEAN NOT NULL DEFAULT true,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT notificacao_config_silencio_check CHECK (
+        (silencio_inicio IS NULL AND silencio_fim IS NULL)
+        OR (silencio_inicio ~ '^([01][0-9]|2[0-3]):[0-5][0-9]$' AND silencio_fim ~ '^([01][0-9]|2[0-3]):[0-5][0-9]$')
+    ),
+    CONSTRAINT notificacao_config_telefone_check CHECK (telefone IS NULL OR telefone ~ '^\+[1-9][0-9]{7,14}$')
+);
+
+-- Sem linha para (utilizador, canal) = padrão: só WEB_PUSH, severidade ≥ ALTA, imediato.
+CREATE TABLE IF NOT EXISTS notificacao_preferencias (
+    usuario_id BIGINT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    canal VARCHAR(20) NOT NULL CHECK (canal IN ('WEB_PUSH', 'EMAIL', 'SMS', 'WHATSAPP')),
+    ativo BOOLEAN NOT NULL DEFAULT true,
+    severidade_minima VARCHAR(10) NOT NULL DEFAULT 'ALTA'
+        CHECK (severidade_minima IN ('CRITICA', 'ALTA', 'MEDIA', 'BAIXA')),
+    tipos TEXT[],
+    modo VARCHAR(10) NOT NULL DEFAULT 'IMEDIATO' CHECK (modo IN ('IMEDIATO', 'DIGEST')),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (usuario_id, canal)
+);
+
+CREATE TABLE IF NOT EXISTS notificacoes_digest_fila (
+    id BIGSERIAL PRIMARY KEY,
+    usuario_id BIGINT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    canal VARCHAR(20) NOT NULL,
+    alerta_id BIGINT REFERENCES alertas(id) ON DELETE CASCADE,
+    fazenda_id BIGINT REFERENCES fazendas(id) ON DELETE CASCADE,
+    titulo TEXT NOT NULL,
+    corpo TEXT NOT NULL,
+    url TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    enviado_em TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_notificacoes_digest_pendentes
+    ON notificacoes_digest_fila (usuario_id, canal, created_at)
+    WHERE enviado_em IS NULL;
+
+ALTER TABLE notificacao_config_usuario ENABLE ROW LEVEL SECURITY;
+ALTER TABLE notificacao_preferencias ENABLE ROW LEVEL SECURITY;
+ALTER TABLE notificacoes_digest_fila ENABLE ROW LEVEL SECURITY;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS resumo_alertas_usuario;
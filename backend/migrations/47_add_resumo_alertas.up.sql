@@ -0,0 +1,20 @@
+-- Resumo periódico de alertas por utilizador (BR-ALERTA-022): frequência, hora local e canais de entrega.
+-- Sem linha = utilizador não recebe resumo.
+
+CREATE TABLE IF NOT EXISTS resumo_alertas_usuario (
+    usuario_id BIGINT PRIMARY KEY REFERENCES usuarios(id) ON DELETE CASCADE,
+    ativo BOOLEAN NOT NULL DEFAULT true,
+    frequencia VARCHAR(10) NOT NULL DEFAULT 'DIARIO' CHECK (frequencia IN ('DIARIO', 'SEMANAL')),
+    hora SMALLINT NOT NULL DEFAULT 7 CHECK (hora BETWEEN 0 AND 23),
+    dia_semana SMALLINT CHECK (dia_semana BETWEEN 0 AND 6),
+    canais TEXT[] NOT NULL DEFAULT ARRAY['WEB_PUSH'],
+    ultimo_envio_em TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT resumo_alertas_semanal_dia_check CHECK (frequencia <> 'SEMANAL' OR dia_semana IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_resumo_alertas_usuario_hora
+    ON resumo_alertas_usuario (hora)
+    WHERE ativo;
+
+ALTER TABLE resumo_alertas_usuario ENABLE ROW LEVEL SECURITY;
@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS alertas_atividades;
+
+DROP INDEX IF EXISTS idx_alertas_escalonar_em;
+DROP INDEX IF EXISTS idx_alertas_responsavel_abertos;
+
+ALTER TABLE alertas
+    DROP COLUMN IF EXISTS escalonar_em,
+    DROP COLUMN IF EXISTS escalonamento_nivel,
+    DROP COLUMN IF EXISTS prazo_em,
+    DROP COLUMN IF EXISTS atribuido_em,
+    DROP COLUMN IF EXISTS responsavel_id;
@@ -0,0 +1,43 @@
+-- Atribuição, prazo (SLA) e escalonamento de alertas + histórico de atividade (BR-ALERTA-023).
+
+ALTER TABLE alertas
+    ADD COLUMN IF NOT EXISTS responsavel_id BIGINT REFERENCES usuarios(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS atribuido_em TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS prazo_em TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS escalonamento_nivel SMALLINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS escalonar_em TIMESTAMPTZ;
+
+-- Prazo dos alertas existentes a partir da criação: CRITICA 4h, ALTA 24h, MEDIA 72h, BAIXA 7 dias.
+UPDATE alertas
+SET prazo_em = created_at + CASE severidade
+        WHEN 'CRITICA' THEN INTERVAL '4 hours'
+        WHEN 'ALTA' THEN INTERVAL '24 hours'
+        WHEN 'MEDIA' THEN INTERVAL '72 hours'
+        ELSE INTERVAL '7 days'
+    END
+WHERE prazo_em IS NULL;
+
+UPDATE alertas SET escalonar_em = prazo_em WHERE status = 'ABERTO' AND escalonar_em IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_alertas_responsavel_abertos
+    ON alertas (responsavel_id)
+    WHERE status IN ('ABERTO', 'EM_ANDAMENTO');
+
+CREATE INDEX IF NOT EXISTS idx_alertas_escalonar_em
+    ON alertas (escalonar_em)
+    WHERE status = 'ABERTO' AND escalonar_em IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS alertas_atividades (
+    id BIGSERIAL PRIMARY KEY,
+    alerta_id BIGINT NOT NULL REFERENCES alertas(id) ON DELETE CASCADE,
+    fazenda_id BIGINT NOT NULL REFERENCES fazendas(id) ON DELETE CASCADE,
+    usuario_id BIGINT REFERENCES usuarios(id) ON DELETE SET NULL,
+    tipo VARCHAR(20) NOT NULL CHECK (tipo IN ('COMENTARIO', 'STATUS', 'ATRIBUICAO', 'ESCALONAMENTO')),
+    texto TEXT NOT NULL,
+    dados JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_alertas_atividades_alerta ON alertas_atividades (alerta_id, created_at);
+
+ALTER TABLE alertas_atividades ENABLE ROW LEVEL SECURITY;
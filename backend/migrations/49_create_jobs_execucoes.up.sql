@@ -0,0 +1,32 @@
+-- Agendador de tarefas (BR-JOBS-001): histórico de execuções e eleição por slot entre réplicas.
+
+CREATE TABLE IF NOT EXISTS jobs_execucoes (
+    id BIGSERIAL PRIMARY KEY,
+    job VARCHAR(60) NOT NULL,
+    -- Slot da agenda (hora prevista); NULL em execuções manuais.
+    agendado_para TIMESTAMPTZ,
+    origem VARCHAR(12) NOT NULL CHECK (origem IN ('AGENDADA', 'RECUPERADA', 'MANUAL')),
+    status VARCHAR(12) NOT NULL CHECK (status IN ('EM_EXECUCAO', 'SUCESSO', 'ERRO')),
+    instancia VARCHAR(120) NOT NULL,
+    disparado_por BIGINT REFERENCES usuarios(id) ON DELETE SET NULL,
+    iniciado_em TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    -- Sinal de vida da réplica que executa; sem ele há mais de 3 min a execução é dada como abandonada.
+    ultimo_sinal_em TIMESTAMPTZ,
+    finalizado_em TIMESTAMPTZ,
+    resultado JSONB,
+    erro TEXT
+);
+
+-- Cada slot corre uma única vez, seja qual for a réplica que o reivindica primeiro.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_jobs_execucoes_slot
+    ON jobs_execucoes (job, agendado_para)
+    WHERE agendado_para IS NOT NULL;
+
+-- No máximo uma execução em curso por job (agendada ou manual).
+CREATE UNIQUE INDEX IF NOT EXISTS uq_jobs_execucoes_em_execucao
+    ON jobs_execucoes (job)
+    WHERE status = 'EM_EXECUCAO';
+
+CREATE INDEX IF NOT EXISTS idx_jobs_execucoes_job_inicio ON jobs_execucoes (job, iniciado_em DESC);
+
+ALTER TABLE jobs_execucoes ENABLE ROW LEVEL SECURITY;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS movimentacoes_lote_propostas;
+DROP TABLE IF EXISTS ciclo_vida_config;
+DROP TABLE IF EXISTS animal_pesagens;
@@ -0,0 +1,60 @@
+-- Motor de ciclo de vida (BR-LOTE-005–007): pesagens, configuração por fazenda e propostas de movimentação.
+
+CREATE TABLE IF NOT EXISTS animal_pesagens (
+    id BIGSERIAL PRIMARY KEY,
+    animal_id BIGINT NOT NULL REFERENCES animais(id) ON DELETE CASCADE,
+    fazenda_id BIGINT NOT NULL REFERENCES fazendas(id) ON DELETE CASCADE,
+    data DATE NOT NULL,
+    peso_kg NUMERIC(7,2) NOT NULL CHECK (peso_kg > 0),
+    observacao TEXT,
+    created_by BIGINT REFERENCES usuarios(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_animal_pesagens_animal_data ON animal_pesagens (animal_id, data DESC, id DESC);
+
+ALTER TABLE animal_pesagens ENABLE ROW LEVEL SECURITY;
+
+-- Sem linha = valores padrão (CicloVidaConfigPadrao).
+CREATE TABLE IF NOT EXISTS ciclo_vida_config (
+    fazenda_id BIGINT PRIMARY KEY REFERENCES fazendas(id) ON DELETE CASCADE,
+    novilha_idade_meses INTEGER NOT NULL CHECK (novilha_idade_meses BETWEEN 1 AND 60),
+    novilha_peso_kg NUMERIC(7,2) CHECK (novilha_peso_kg > 0),
+    macho_idade_meses INTEGER NOT NULL CHECK (macho_idade_meses BETWEEN 1 AND 60),
+    macho_categoria VARCHAR(20) NOT NULL CHECK (macho_categoria IN ('BOI', 'TOURO')),
+    pre_parto_dias INTEGER NOT NULL CHECK (pre_parto_dias BETWEEN 1 AND 120),
+    updated_by BIGINT REFERENCES usuarios(id) ON DELETE SET NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE ciclo_vida_config ENABLE ROW LEVEL SECURITY;
+
+CREATE TABLE IF NOT EXISTS movimentacoes_lote_propostas (
+    id BIGSERIAL PRIMARY KEY,
+    fazenda_id BIGINT NOT NULL REFERENCES fazendas(id) ON DELETE CASCADE,
+    animal_id BIGINT NOT NULL REFERENCES animais(id) ON DELETE CASCADE,
+    lote_origem_id BIGINT REFERENCES lotes(id) ON DELETE SET NULL,
+    lote_destino_id BIGINT NOT NULL REFERENCES lotes(id) ON DELETE CASCADE,
+    motivo VARCHAR(20) NOT NULL CHECK (motivo IN ('PRE_PARTO', 'RECRIA', 'ENGORDA')),
+    -- Situação que originou a proposta (ex.: gestacao:42); decisão tomada não volta a ser proposta.
+    referencia VARCHAR(60) NOT NULL,
+    descricao TEXT NOT NULL,
+    status VARCHAR(12) NOT NULL DEFAULT 'PENDENTE' CHECK (status IN ('PENDENTE', 'APROVADA', 'REJEITADA', 'OBSOLETA')),
+    movimentacao_id BIGINT REFERENCES movimentacoes_lote(id) ON DELETE SET NULL,
+    decidido_por BIGINT REFERENCES usuarios(id) ON DELETE SET NULL,
+    decidido_em TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_mov_lote_propostas_pendente_animal
+    ON movimentacoes_lote_propostas (animal_id)
+    WHERE status = 'PENDENTE';
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_mov_lote_propostas_referencia
+    ON movimentacoes_lote_propostas (animal_id, motivo, referencia)
+    WHERE status <> 'OBSOLETA';
+
+CREATE INDEX IF NOT EXISTS idx_mov_lote_propostas_fazenda_status
+    ON movimentacoes_lote_propostas (fazenda_id, status, created_at DESC);
+
+ALTER TABLE movimentacoes_lote_propostas ENABLE ROW LEVEL SECURITY;
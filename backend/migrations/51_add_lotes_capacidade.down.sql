@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_movimentacoes_lote_origem;
+ALTER TABLE lotes DROP CONSTRAINT IF EXISTS lotes_capacidade_check;
+ALTER TABLE lotes DROP COLUMN IF EXISTS capacidade;
@@ -0,0 +1,6 @@
+-- BR-LOTE-008/010: capacidade opcional do lote e índice para o histórico de ocupação (saídas por lote de origem).
+ALTER TABLE lotes ADD COLUMN IF NOT EXISTS capacidade INTEGER;
+ALTER TABLE lotes DROP CONSTRAINT IF EXISTS lotes_capacidade_check;
+ALTER TABLE lotes ADD CONSTRAINT lotes_capacidade_check CHECK (capacidade IS NULL OR capacidade > 0);
+
+CREATE INDEX IF NOT EXISTS idx_movimentacoes_lote_origem ON movimentacoes_lote(lote_origem_id);
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS folgas_escala_config (
+    fazenda_id BIGINT PRIMARY KEY REFERENCES fazendas(id) ON DELETE CASCADE,
+    data_anchor DATE NOT NULL,
+    usuario_slot_0 BIGINT NOT NULL REFERENCES usuarios(id) ON DELETE RESTRICT,
+    usuario_slot_1 BIGINT NOT NULL REFERENCES usuarios(id) ON DELETE RESTRICT,
+    usuario_slot_2 BIGINT NOT NULL REFERENCES usuarios(id) ON DELETE RESTRICT,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_folgas_escala_config_slots ON folgas_escala_config (usuario_slot_0, usuario_slot_1, usuario_slot_2);
+ALTER TABLE folgas_escala_config ENABLE ROW LEVEL SECURITY;
+
+-- Só equipes no formato 5x1 de 3 participantes voltam ao modelo antigo (a primeira por fazenda).
+INSERT INTO folgas_escala_config (fazenda_id, data_anchor, usuario_slot_0, usuario_slot_1, usuario_slot_2, updated_at)
+SELECT DISTINCT ON (e.fazenda_id) e.fazenda_id, e.data_anchor, p0.usuario_id, p1.usuario_id, p2.usuario_id, e.updated_at
+FROM folgas_equipes e
+JOIN folgas_equipe_participantes p0 ON p0.equipe_id = e.id AND p0.posicao = 0 AND p0.deslocamento = 0
+JOIN folgas_equipe_participantes p1 ON p1.equipe_id = e.id AND p1.posicao = 1 AND p1.deslocamento = 1
+JOIN folgas_equipe_participantes p2 ON p2.equipe_id = e.id AND p2.posicao = 2 AND p2.deslocamento = 2
+WHERE e.ciclo_dias = 6 AND e.folgas_por_ciclo = 1
+AND (SELECT COUNT(*) FROM folgas_equipe_participantes p WHERE p.equipe_id = e.id) = 3
+ORDER BY e.fazenda_id, e.id;
+
+DROP TABLE IF EXISTS folgas_equipe_participantes;
+DROP TABLE IF EXISTS folgas_equipes;
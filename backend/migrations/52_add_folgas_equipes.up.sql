@@ -0,0 +1,44 @@
+-- Rodízio de folgas definido como dados (BR-FOLGAS-008): uma ou mais equipes por fazenda, cada uma com
+-- ciclo de N dias, folgas consecutivas por ciclo e deslocamento de cada participante dentro do ciclo.
+CREATE TABLE IF NOT EXISTS folgas_equipes (
+    id BIGSERIAL PRIMARY KEY,
+    fazenda_id BIGINT NOT NULL REFERENCES fazendas(id) ON DELETE CASCADE,
+    nome VARCHAR(100) NOT NULL,
+    data_anchor DATE NOT NULL,
+    ciclo_dias INTEGER NOT NULL CHECK (ciclo_dias BETWEEN 2 AND 60),
+    folgas_por_ciclo INTEGER NOT NULL CHECK (folgas_por_ciclo >= 1),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT folgas_equipes_folgas_ciclo_check CHECK (folgas_por_ciclo < ciclo_dias),
+    UNIQUE (fazenda_id, nome)
+);
+
+-- Participante em no máximo uma equipe por fazenda; deslocamento = primeiro dia de folga no ciclo.
+CREATE TABLE IF NOT EXISTS folgas_equipe_participantes (
+    equipe_id BIGINT NOT NULL REFERENCES folgas_equipes(id) ON DELETE CASCADE,
+    fazenda_id BIGINT NOT NULL REFERENCES fazendas(id) ON DELETE CASCADE,
+    usuario_id BIGINT NOT NULL REFERENCES usuarios(id) ON DELETE RESTRICT,
+    posicao INTEGER NOT NULL CHECK (posicao >= 0),
+    deslocamento INTEGER NOT NULL CHECK (deslocamento >= 0),
+    PRIMARY KEY (equipe_id, usuario_id),
+    UNIQUE (equipe_id, posicao),
+    UNIQUE (fazenda_id, usuario_id)
+);
+
+-- Configurações 5x1 existentes viram uma equipe de 3 (ciclo 6, 1 folga, slots nos dias 0, 1 e 2).
+INSERT INTO folgas_equipes (fazenda_id, nome, data_anchor, ciclo_dias, folgas_por_ciclo, updated_at)
+SELECT fazenda_id, 'Rodízio 5x1', data_anchor, 6, 1, updated_at
+FROM folgas_escala_config;
+
+INSERT INTO folgas_equipe_participantes (equipe_id, fazenda_id, usuario_id, posicao, deslocamento)
+SELECT e.id, c.fazenda_id, s.usuario_id, s.posicao, s.posicao
+FROM folgas_escala_config c
+JOIN folgas_equipes e ON e.fazenda_id = c.fazenda_id
+CROSS JOIN LATERAL (
+    VALUES (c.usuario_slot_0, 0), (c.usuario_slot_1, 1), (c.usuario_slot_2, 2)
+) AS s (usuario_id, posicao);
+
+DROP TABLE folgas_escala_config;
+
+ALTER TABLE folgas_equipes ENABLE ROW LEVEL SECURITY;
+ALTER TABLE folgas_equipe_participantes ENABLE ROW LEVEL SECURITY;
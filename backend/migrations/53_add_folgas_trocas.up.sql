@@ -0,0 +1,28 @@
+-- Troca de folga entre funcionários (BR-FOLGAS-009): solicitante cede a folga de data_solicitante e assume a
+-- folga do colega em data_colega. Fluxo: PENDENTE_COLEGA -> PENDENTE_GESTAO -> APROVADA (ou RECUSADA,
+-- REJEITADA, CANCELADA).
+CREATE TABLE IF NOT EXISTS folgas_trocas (
+    id BIGSERIAL PRIMARY KEY,
+    fazenda_id BIGINT NOT NULL REFERENCES fazendas(id) ON DELETE CASCADE,
+    solicitante_id BIGINT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    colega_id BIGINT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    data_solicitante DATE NOT NULL,
+    data_colega DATE NOT NULL,
+    motivo TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDENTE_COLEGA'
+        CHECK (status IN ('PENDENTE_COLEGA', 'PENDENTE_GESTAO', 'APROVADA', 'RECUSADA', 'REJEITADA', 'CANCELADA')),
+    respondido_em TIMESTAMP,
+    decidido_por BIGINT REFERENCES usuarios(id) ON DELETE SET NULL,
+    decidido_em TIMESTAMP,
+    observacao_decisao TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT folgas_trocas_usuarios_check CHECK (solicitante_id <> colega_id),
+    CONSTRAINT folgas_trocas_datas_check CHECK (data_solicitante <> data_colega)
+);
+
+CREATE INDEX IF NOT EXISTS idx_folgas_trocas_fazenda_status ON folgas_trocas (fazenda_id, status);
+CREATE INDEX IF NOT EXISTS idx_folgas_trocas_solicitante ON folgas_trocas (solicitante_id);
+CREATE INDEX IF NOT EXISTS idx_folgas_trocas_colega ON folgas_trocas (colega_id);
+
+ALTER TABLE folgas_trocas ENABLE ROW LEVEL SECURITY;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS folgas_ferias_direito;
+DROP TABLE IF EXISTS folgas_ausencias;
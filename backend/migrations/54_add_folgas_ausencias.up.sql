@@ -0,0 +1,32 @@
+-- Ausências por funcionário (BR-FOLGAS-010): férias, atestado (com referência do anexo) e licença não
+-- remunerada, em intervalo de datas. A geração automática não põe folga nesses dias.
+CREATE TABLE IF NOT EXISTS folgas_ausencias (
+    id BIGSERIAL PRIMARY KEY,
+    fazenda_id BIGINT NOT NULL REFERENCES fazendas(id) ON DELETE CASCADE,
+    usuario_id BIGINT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    tipo VARCHAR(30) NOT NULL CHECK (tipo IN ('FERIAS', 'ATESTADO', 'LICENCA_NAO_REMUNERADA')),
+    data_inicio DATE NOT NULL,
+    data_fim DATE NOT NULL,
+    anexo_ref TEXT,
+    observacao TEXT,
+    created_by BIGINT REFERENCES usuarios(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT folgas_ausencias_periodo_check CHECK (data_fim >= data_inicio)
+);
+
+CREATE INDEX IF NOT EXISTS idx_folgas_ausencias_fazenda_periodo ON folgas_ausencias (fazenda_id, data_inicio, data_fim);
+CREATE INDEX IF NOT EXISTS idx_folgas_ausencias_usuario ON folgas_ausencias (usuario_id, data_inicio);
+
+-- Dias de férias a que o funcionário tem direito no ano; sem linha vale o padrão de 30 dias.
+CREATE TABLE IF NOT EXISTS folgas_ferias_direito (
+    fazenda_id BIGINT NOT NULL REFERENCES fazendas(id) ON DELETE CASCADE,
+    usuario_id BIGINT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    ano INTEGER NOT NULL CHECK (ano BETWEEN 2000 AND 2100),
+    dias INTEGER NOT NULL CHECK (dias BETWEEN 0 AND 60),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (fazenda_id, usuario_id, ano)
+);
+
+ALTER TABLE folgas_ausencias ENABLE ROW LEVEL SECURITY;
+ALTER TABLE folgas_ferias_direito ENABLE ROW LEVEL SECURITY;
@@ -0,0 +1,16 @@
+-- Assinaturas iCal (.ics) da escala (BR-FOLGAS-011): cada linha é um token de leitura ligado ao
+-- usuário e a uma fazenda. Só o hash (SHA-256) é guardado; revogar = apagar a linha.
+CREATE TABLE IF NOT EXISTS calendario_feeds (
+    id BIGSERIAL PRIMARY KEY,
+    usuario_id BIGINT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    fazenda_id BIGINT NOT NULL REFERENCES fazendas(id) ON DELETE CASCADE,
+    escopo VARCHAR(20) NOT NULL CHECK (escopo IN ('PESSOAL', 'FAZENDA')),
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    token_prefixo VARCHAR(12) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    ultimo_uso_em TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_calendario_feeds_usuario ON calendario_feeds (usuario_id);
+
+ALTER TABLE calendario_feeds ENABLE ROW LEVEL SECURITY;
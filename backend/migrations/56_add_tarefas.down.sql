@@ -0,0 +1,7 @@
+DELETE FROM alertas_atividades WHERE tipo = 'TAREFA';
+ALTER TABLE alertas_atividades DROP CONSTRAINT IF EXISTS alertas_atividades_tipo_check;
+ALTER TABLE alertas_atividades ADD CONSTRAINT alertas_atividades_tipo_check
+    CHECK (tipo IN ('COMENTARIO', 'STATUS', 'ATRIBUICAO', 'ESCALONAMENTO'));
+
+DROP TABLE IF EXISTS tarefas_checklist_itens;
+DROP TABLE IF EXISTS tarefas;
@@ -0,0 +1,50 @@
+-- Tarefas / ordens de serviço por fazenda (BR-TAREFA-001..004): responsável, prazo em dia,
+-- vínculo opcional com animal, lote, área ou alerta de origem, recorrência e checklist.
+CREATE TABLE IF NOT EXISTS tarefas (
+    id BIGSERIAL PRIMARY KEY,
+    fazenda_id BIGINT NOT NULL REFERENCES fazendas(id) ON DELETE CASCADE,
+    titulo VARCHAR(200) NOT NULL,
+    descricao TEXT,
+    animal_id BIGINT REFERENCES animais(id) ON DELETE SET NULL,
+    lote_id BIGINT REFERENCES lotes(id) ON DELETE SET NULL,
+    area_id BIGINT REFERENCES areas(id) ON DELETE SET NULL,
+    alerta_id BIGINT REFERENCES alertas(id) ON DELETE SET NULL,
+    responsavel_id BIGINT REFERENCES usuarios(id) ON DELETE SET NULL,
+    data_prevista DATE NOT NULL,
+    recorrencia VARCHAR(10) NOT NULL DEFAULT 'NENHUMA' CHECK (recorrencia IN ('NENHUMA', 'DIARIA', 'SEMANAL')),
+    -- Primeira tarefa da série recorrente; NULL na própria primeira e nas avulsas.
+    serie_id BIGINT REFERENCES tarefas(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'ABERTA' CHECK (status IN ('ABERTA', 'EM_ANDAMENTO', 'CONCLUIDA', 'CANCELADA')),
+    concluida_por BIGINT REFERENCES usuarios(id) ON DELETE SET NULL,
+    concluida_em TIMESTAMPTZ,
+    created_by BIGINT REFERENCES usuarios(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_tarefas_fazenda_data ON tarefas (fazenda_id, data_prevista);
+CREATE INDEX IF NOT EXISTS idx_tarefas_responsavel_abertas ON tarefas (responsavel_id, data_prevista)
+    WHERE status IN ('ABERTA', 'EM_ANDAMENTO');
+-- Um alerta vira no máximo uma tarefa em aberto.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_tarefas_alerta_aberta ON tarefas (alerta_id)
+    WHERE alerta_id IS NOT NULL AND status IN ('ABERTA', 'EM_ANDAMENTO');
+
+CREATE TABLE IF NOT EXISTS tarefas_checklist_itens (
+    id BIGSERIAL PRIMARY KEY,
+    tarefa_id BIGINT NOT NULL REFERENCES tarefas(id) ON DELETE CASCADE,
+    ordem INTEGER NOT NULL,
+    texto VARCHAR(200) NOT NULL,
+    feito BOOLEAN NOT NULL DEFAULT FALSE,
+    feito_por BIGINT REFERENCES usuarios(id) ON DELETE SET NULL,
+    feito_em TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_tarefas_checklist_tarefa ON tarefas_checklist_itens (tarefa_id, ordem);
+
+-- Histórico do alerta registra a conversão em tarefa e a conclusão dela.
+ALTER TABLE alertas_atividades DROP CONSTRAINT IF EXISTS alertas_atividades_tipo_check;
+ALTER TABLE alertas_atividades ADD CONSTRAINT alertas_atividades_tipo_check
+    CHECK (tipo IN ('COMENTARIO', 'STATUS', 'ATRIBUICAO', 'ESCALONAMENTO', 'TAREFA'));
+
+ALTER TABLE tarefas ENABLE ROW LEVEL SECURITY;
+ALTER TABLE tarefas_checklist_itens ENABLE ROW LEVEL SECURITY;
@@ -0,0 +1,22 @@
+-- Convites de acesso a uma fazenda (BR-ACESSO-010): código de uso único com perfil e papel alvo.
+-- Só o hash (SHA-256) do código é guardado; revogar marca revogado_em para manter o histórico.
+CREATE TABLE IF NOT EXISTS convites (
+    id BIGSERIAL PRIMARY KEY,
+    fazenda_id BIGINT NOT NULL REFERENCES fazendas(id) ON DELETE CASCADE,
+    perfil VARCHAR(20) NOT NULL CHECK (perfil IN ('FUNCIONARIO', 'GERENTE', 'GESTAO', 'PROPRIETARIO')),
+    papel VARCHAR(20) NOT NULL CHECK (papel IN ('TITULAR', 'OPERACIONAL')),
+    codigo_hash VARCHAR(64) NOT NULL UNIQUE,
+    codigo_prefixo VARCHAR(12) NOT NULL,
+    observacao VARCHAR(200),
+    expira_em TIMESTAMP NOT NULL,
+    criado_por BIGINT REFERENCES usuarios(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    usado_por BIGINT REFERENCES usuarios(id) ON DELETE SET NULL,
+    usado_em TIMESTAMP,
+    revogado_por BIGINT REFERENCES usuarios(id) ON DELETE SET NULL,
+    revogado_em TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_convites_fazenda ON convites (fazenda_id, created_at DESC);
+
+ALTER TABLE convites ENABLE ROW LEVEL SECURITY;
@@ -0,0 +1,2 @@
+ALTER TABLE usuarios DROP COLUMN IF EXISTS email_verificado_em;
+DROP TABLE IF EXISTS tokens_conta;
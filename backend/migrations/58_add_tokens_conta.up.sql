@@ -0,0 +1,20 @@
+-- Tokens de uso único da conta (BR-ACESSO-026): redefinição de senha e verificação de e-mail.
+-- Só o hash (SHA-256) do token é guardado; email registra o endereço a que a verificação se refere.
+CREATE TABLE IF NOT EXISTS tokens_conta (
+    id BIGSERIAL PRIMARY KEY,
+    usuario_id BIGINT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    tipo VARCHAR(20) NOT NULL CHECK (tipo IN ('RESET_SENHA', 'VERIFICACAO_EMAIL')),
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    email VARCHAR(255) NOT NULL,
+    expira_em TIMESTAMP NOT NULL,
+    usado_em TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_tokens_conta_usuario_tipo ON tokens_conta (usuario_id, tipo, created_at DESC);
+
+ALTER TABLE tokens_conta ENABLE ROW LEVEL SECURITY;
+
+-- Contas anteriores ao fluxo de verificação ficam como verificadas (não recebem o aviso retroativamente).
+ALTER TABLE usuarios ADD COLUMN IF NOT EXISTS email_verificado_em TIMESTAMP;
+UPDATE usuarios SET email_verificado_em = COALESCE(created_at, CURRENT_TIMESTAMP) WHERE email_verificado_em IS NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS aal;
+DROP TABLE IF EXISTS usuarios_dois_fatores_recuperacao;
+DROP TABLE IF EXISTS usuarios_dois_fatores;
@@ -0,0 +1,29 @@
+-- Autenticação em dois fatores por TOTP (BR-ACESSO-027).
+-- O segredo fica cifrado (AES-GCM) porque precisa ser lido para validar códigos; ativado_em NULL = inscrição
+-- iniciada e ainda não confirmada. ultimo_passo impede reutilizar o mesmo código dentro da janela de 30 s.
+CREATE TABLE IF NOT EXISTS usuarios_dois_fatores (
+    usuario_id BIGINT PRIMARY KEY REFERENCES usuarios(id) ON DELETE CASCADE,
+    segredo_cifrado TEXT NOT NULL,
+    ativado_em TIMESTAMP,
+    ultimo_passo BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE usuarios_dois_fatores ENABLE ROW LEVEL SECURITY;
+
+-- Códigos de recuperação de uso único; só o hash SHA-256 é guardado.
+CREATE TABLE IF NOT EXISTS usuarios_dois_fatores_recuperacao (
+    id BIGSERIAL PRIMARY KEY,
+    usuario_id BIGINT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    codigo_hash VARCHAR(64) NOT NULL,
+    usado_em TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_dois_fatores_recuperacao_usuario ON usuarios_dois_fatores_recuperacao (usuario_id);
+
+ALTER TABLE usuarios_dois_fatores_recuperacao ENABLE ROW LEVEL SECURITY;
+
+-- Nível de garantia da sessão (1 = senha, 2 = senha + TOTP), herdado na rotação do refresh token.
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS aal SMALLINT NOT NULL DEFAULT 1;
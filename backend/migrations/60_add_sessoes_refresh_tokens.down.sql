@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_refresh_tokens_familia;
+ALTER TABLE refresh_tokens
+    DROP COLUMN IF EXISTS ip,
+    DROP COLUMN IF EXISTS user_agent,
+    DROP COLUMN IF EXISTS sessao_iniciada_em,
+    DROP COLUMN IF EXISTS familia_id;
+DROP SEQUENCE IF EXISTS refresh_tokens_familia_seq;
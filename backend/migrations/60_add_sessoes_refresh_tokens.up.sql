@@ -0,0 +1,19 @@
+-- Sessões ativas por dispositivo (BR-ACESSO-028).
+-- familia_id identifica a sessão: nasce no login e é herdada a cada rotação do refresh token, assim revogar
+-- a família encerra o dispositivo. sessao_iniciada_em também é herdada; user_agent e ip são os da última
+-- renovação e created_at do token vivo é o último uso.
+CREATE SEQUENCE IF NOT EXISTS refresh_tokens_familia_seq;
+
+ALTER TABLE refresh_tokens
+    ADD COLUMN IF NOT EXISTS familia_id BIGINT NOT NULL DEFAULT nextval('refresh_tokens_familia_seq'),
+    ADD COLUMN IF NOT EXISTS sessao_iniciada_em TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS user_agent TEXT,
+    ADD COLUMN IF NOT EXISTS ip VARCHAR(64);
+
+UPDATE refresh_tokens SET sessao_iniciada_em = created_at WHERE sessao_iniciada_em IS NULL;
+
+ALTER TABLE refresh_tokens
+    ALTER COLUMN sessao_iniciada_em SET DEFAULT CURRENT_TIMESTAMP,
+    ALTER COLUMN sessao_iniciada_em SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_familia ON refresh_tokens (user_id, familia_id) WHERE revoked = FALSE;
@@ -0,0 +1,16 @@
+-- Login SSO por OpenID Connect (BR-ACESSO-029): vínculo entre a conta e a identidade no provedor.
+-- (emissor, sujeito) é a chave estável do OIDC; o e-mail é só informativo (pode mudar no provedor).
+CREATE TABLE IF NOT EXISTS usuarios_identidades_oidc (
+    id BIGSERIAL PRIMARY KEY,
+    usuario_id BIGINT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    emissor VARCHAR(255) NOT NULL,
+    sujeito VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ultimo_login_em TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_usuarios_identidades_oidc UNIQUE (emissor, sujeito)
+);
+
+CREATE INDEX IF NOT EXISTS idx_usuarios_identidades_oidc_usuario ON usuarios_identidades_oidc (usuario_id);
+
+ALTER TABLE usuarios_identidades_oidc ENABLE ROW LEVEL SECURITY;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS usuarios_dois_fatores_desafios;
@@ -0,0 +1,14 @@
+-- Desafios do segundo passo do login (BR-ACESSO-027): o JWT do desafio só vale enquanto a linha do seu jti
+-- existir sem consumido_em, antes de expira_em e com tentativas abaixo do limite (5).
+CREATE TABLE IF NOT EXISTS usuarios_dois_fatores_desafios (
+    jti UUID PRIMARY KEY,
+    usuario_id BIGINT NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    tentativas INT NOT NULL DEFAULT 0,
+    consumido_em TIMESTAMP,
+    expira_em TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_dois_fatores_desafios_usuario ON usuarios_dois_fatores_desafios (usuario_id, expira_em);
+
+ALTER TABLE usuarios_dois_fatores_desafios ENABLE ROW LEVEL SECURITY;
@@ -7,6 +7,7 @@ import {
   updateUsuario,
   getFazendasByUsuario,
   setFazendasForUsuario,
+  redefinirDoisFatoresUsuario,
 } from "@/services/admin";
 import type { UsuarioUpdate } from "@/services/admin";
 import { list as listFazendas } from "@/services/fazendas";
@@ -14,6 +15,7 @@ import { RequireAdminRoute } from "@/components/layout/RequireAdminRoute";
 import { PageContainer } from "@/components/layout/PageContainer";
 import { BackLink } from "@/components/layout/BackLink";
 import { UsuarioForm } from "@/components/admin/UsuarioForm";
+import { UsuarioSessoesCard } from "@/components/admin/UsuarioSessoesCard";
 import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card";
 import { Button } from "@/components/ui/button";
 import { Label } from "@/components/ui/label";
@@ -83,6 +85,20 @@ function AdminUsuarioEditarContent({ id }: { id: number }) {
     },
   });
 
+  // BR-ACESSO-027: para quem perdeu o autenticador e os códigos de recuperação.
+  const redefinirDoisFatoresMutation = useMutation({
+    mutationFn: () => redefinirDoisFatoresUsuario(id),
+    onSuccess: () => {
+      toast.success(
+        "Autenticação em dois fatores redefinida",
+        "As sessões do utilizador foram encerradas."
+      );
+    },
+    onError: (err: unknown) => {
+      toast.error(getApiErrorMessage(err, "Erro ao redefinir o 2FA."));
+    },
+  });
+
   const handleSubmit = async (
     payload: UsuarioUpdate & { senha?: string; enabled?: boolean }
   ) => {
@@ -180,6 +196,35 @@ function AdminUsuarioEditarContent({ id }: { id: number }) {
             </Button>
           </CardContent>
         </Card>
+        <Card>
+          <CardHeader>
+            <CardTitle>Autenticação em dois fatores</CardTitle>
+            <p className="text-sm text-muted-foreground">
+              Remove o autenticador e os códigos de recuperação do utilizador e
+              encerra as sessões dele. No próximo login entra só com a senha e,
+              se o perfil exigir, terá de configurar o 2FA de novo.
+            </p>
+          </CardHeader>
+          <CardContent>
+            <Button
+              type="button"
+              variant="outline"
+              onClick={() => {
+                if (
+                  confirm(
+                    "Redefinir a autenticação em dois fatores deste utilizador? Confirme a identidade dele antes."
+                  )
+                ) {
+                  redefinirDoisFatoresMutation.mutate();
+                }
+              }}
+              disabled={redefinirDoisFatoresMutation.isPending}
+            >
+              Redefinir 2FA
+            </Button>
+          </CardContent>
+        </Card>
+        <UsuarioSessoesCard usuarioId={id} />
       </div>
     </PageContainer>
   );
@@ -47,6 +47,7 @@ function AlertasContent() {
     canResolve,
     canEmAndamento,
     canDelete,
+    canConverterTarefa,
     statusMutation,
     onStatusChange,
     invalidate,
@@ -124,6 +125,7 @@ function AlertasContent() {
               canEmAndamento={canEmAndamento}
               canResolve={canResolve}
               canDelete={canDelete}
+              canConverterTarefa={canConverterTarefa}
               onStatusChange={onStatusChange}
               onDeleteSuccess={invalidate}
             />
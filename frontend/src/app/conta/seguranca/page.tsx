@@ -0,0 +1,46 @@
+'use client'
+
+import { useEffect } from 'react'
+import { useRouter } from 'next/navigation'
+import Link from 'next/link'
+import { useAuth } from '@/contexts/AuthContext'
+import { PageContainer } from '@/components/layout/PageContainer'
+import { DoisFatoresCard } from '@/components/conta/DoisFatoresCard'
+import { SessoesCard } from '@/components/conta/SessoesCard'
+import { Button } from '@/components/ui/button'
+
+/** Segurança da conta: autenticação em dois fatores (BR-ACESSO-027) e sessões ativas (BR-ACESSO-028). */
+export default function ContaSegurancaPage() {
+  const { user, isReady, isAuthenticated, logout } = useAuth()
+  const router = useRouter()
+
+  useEffect(() => {
+    if (isReady && !isAuthenticated) router.replace('/login?redirect=/conta/seguranca')
+  }, [isReady, isAuthenticated, router])
+
+  if (!isReady || !isAuthenticated) {
+    return (
+      <PageContainer variant="centered">
+        <p className="text-muted-foreground">Carregando…</p>
+      </PageContainer>
+    )
+  }
+
+  return (
+    <PageContainer variant="centered">
+      <div className="flex w-full max-w-lg flex-col gap-4">
+        <DoisFatoresCard />
+        <SessoesCard />
+        {user?.doisFatoresPendente ? (
+          <Button variant="ghost" onClick={() => void logout()}>
+            Terminar sessão
+          </Button>
+        ) : (
+          <Button variant="ghost" asChild>
+            <Link href="/">Voltar ao início</Link>
+          </Button>
+        )}
+      </div>
+    </PageContainer>
+  )
+}
@@ -0,0 +1,125 @@
+'use client'
+
+import { useState } from 'react'
+import Link from 'next/link'
+import { forgotPassword } from '@/services/auth'
+import { Button } from '@/components/ui/button'
+import { Input } from '@/components/ui/input'
+import { Label } from '@/components/ui/label'
+import {
+  Card,
+  CardContent,
+  CardDescription,
+  CardHeader,
+  CardTitle,
+} from '@/components/ui/card'
+import { PageContainer } from '@/components/layout/PageContainer'
+import { FormFieldError } from '@/components/ui/form-field-error'
+import { FormValidationAlert } from '@/components/ui/form-validation-alert'
+import { getApiErrorMessage } from '@/lib/errors'
+
+/** Pedido do link de redefinição de senha (BR-ACESSO-026). */
+export default function EsqueciSenhaPage() {
+  const [email, setEmail] = useState('')
+  const [error, setError] = useState('')
+  const [fieldError, setFieldError] = useState<string | undefined>()
+  const [enviado, setEnviado] = useState(false)
+  const [loading, setLoading] = useState(false)
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault()
+    setError('')
+    setFieldError(undefined)
+    if (!email.trim()) {
+      setFieldError('Email é obrigatório.')
+      return
+    }
+    setLoading(true)
+    try {
+      await forgotPassword(email.trim())
+      setEnviado(true)
+    } catch (err: unknown) {
+      setError(
+        getApiErrorMessage(err, 'Não foi possível enviar o link. Tente novamente.')
+      )
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  if (enviado) {
+    return (
+      <PageContainer variant="centered">
+        <Card className="w-full max-w-sm">
+          <CardHeader>
+            <CardTitle>Verifique o seu email</CardTitle>
+            <CardDescription>
+              Se <strong>{email.trim()}</strong> estiver cadastrado, enviamos um
+              link para redefinir a senha. O link vale por 1 hora e só pode ser
+              usado uma vez.
+            </CardDescription>
+          </CardHeader>
+          <CardContent>
+            <p className="text-center text-sm text-muted-foreground">
+              Não recebeu? Confira a pasta de spam ou{' '}
+              <button
+                type="button"
+                className="underline hover:text-foreground"
+                onClick={() => setEnviado(false)}
+              >
+                peça outro link
+              </button>
+              .
+            </p>
+            <p className="mt-2 text-center text-sm text-muted-foreground">
+              <Link href="/login" className="underline hover:text-foreground">
+                Voltar para login
+              </Link>
+            </p>
+          </CardContent>
+        </Card>
+      </PageContainer>
+    )
+  }
+
+  return (
+    <PageContainer variant="centered">
+      <Card className="w-full max-w-sm">
+        <CardHeader>
+          <CardTitle>Esqueceu a senha?</CardTitle>
+          <CardDescription>
+            Informe o email da sua conta para receber um link de redefinição.
+          </CardDescription>
+        </CardHeader>
+        <CardContent>
+          <form onSubmit={handleSubmit} className="space-y-4">
+            {error?.trim() ? (
+              <FormValidationAlert message={error} isValidation={false} />
+            ) : null}
+            <div className="space-y-2">
+              <Label htmlFor="email">Email</Label>
+              <Input
+                id="email"
+                type="email"
+                autoComplete="email"
+                value={email}
+                onChange={(e) => setEmail(e.target.value)}
+                aria-invalid={fieldError ? true : undefined}
+                required
+              />
+              <FormFieldError message={fieldError} />
+            </div>
+            <Button type="submit" className="w-full" disabled={loading}>
+              {loading ? 'Enviando…' : 'Enviar link'}
+            </Button>
+          </form>
+          <p className="mt-4 text-center text-sm text-muted-foreground">
+            <Link href="/login" className="underline hover:text-foreground">
+              Voltar para login
+            </Link>
+          </p>
+        </CardContent>
+      </Card>
+    </PageContainer>
+  )
+}
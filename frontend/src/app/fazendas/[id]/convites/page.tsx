@@ -0,0 +1,53 @@
+"use client";
+
+import { useParams } from "next/navigation";
+import { useAuth } from "@/contexts/AuthContext";
+import { canGerenciarConvites } from "@/config/appAccess";
+import { ProtectedRoute } from "@/components/layout/ProtectedRoute";
+import { PageContainer } from "@/components/layout/PageContainer";
+import { BackLink } from "@/components/layout/BackLink";
+import { ConvitesFazendaPanel } from "@/components/convites/ConvitesFazendaPanel";
+
+function ConvitesFazendaContent() {
+  const params = useParams();
+  const id = Number(params.id);
+  const { user } = useAuth();
+  const isAdmin = user?.perfil === "ADMIN" || user?.perfil === "DEVELOPER";
+
+  if (Number.isNaN(id)) {
+    return (
+      <PageContainer variant="narrow">
+        <p className="text-destructive">ID inválido.</p>
+        <BackLink href="/fazendas">Voltar</BackLink>
+      </PageContainer>
+    );
+  }
+
+  if (!canGerenciarConvites(user?.perfil)) {
+    return (
+      <PageContainer variant="centered">
+        <p className="text-muted-foreground text-center">
+          Convites são emitidos por administradores da plataforma e pelo{" "}
+          <strong>Proprietário</strong> titular da fazenda.
+        </p>
+      </PageContainer>
+    );
+  }
+
+  return (
+    <PageContainer variant="narrow">
+      <div className="mb-4">
+        <BackLink href={isAdmin ? `/fazendas/${id}` : "/"}>Voltar</BackLink>
+      </div>
+      <ConvitesFazendaPanel fazendaId={id} perfilEmissor={user?.perfil} />
+    </PageContainer>
+  );
+}
+
+export default function ConvitesFazendaPage() {
+  return (
+    <ProtectedRoute>
+      <ConvitesFazendaContent />
+    </ProtectedRoute>
+  );
+}
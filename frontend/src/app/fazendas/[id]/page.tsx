@@ -10,7 +10,7 @@ import { PageContainer } from "@/components/layout/PageContainer";
 import { BackLink } from "@/components/layout/BackLink";
 import { Button } from "@/components/ui/button";
 import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card";
-import { Building2, Edit, List, Droplets } from "lucide-react";
+import { Building2, Edit, List, Droplets, Ticket } from "lucide-react";
 
 function FazendaDetailContent() {
   const params = useParams();
@@ -105,6 +105,12 @@ function FazendaDetailContent() {
                   Ver produção
                 </Link>
               </Button>
+              <Button variant="outline" size="default" asChild>
+                <Link href={`/fazendas/${id}/convites`}>
+                  <Ticket className="mr-2 h-4 w-4" />
+                  Convites
+                </Link>
+              </Button>
             </div>
           </CardContent>
         </Card>
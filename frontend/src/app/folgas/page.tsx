@@ -7,10 +7,13 @@ import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card";
 import { Button } from "@/components/ui/button";
 import { Input } from "@/components/ui/input";
 import { Label } from "@/components/ui/label";
-import { DatePicker } from "@/components/ui/date-picker";
 import { FolgasCalendarioDia } from "@/components/folgas/FolgasCalendarioDia";
 import { FolgasHistoricoTable } from "@/components/folgas/FolgasHistoricoTable";
 import { FolgasDiaDetalhesDialog } from "@/components/folgas/FolgasDiaDetalhesDialog";
+import { FolgasEquipesEditor } from "@/components/folgas/FolgasEquipesEditor";
+import { FolgasTrocasPanel } from "@/components/folgas/FolgasTrocasPanel";
+import { FolgasAusenciasPanel } from "@/components/folgas/FolgasAusenciasPanel";
+import { FolgasCalendarioDialog } from "@/components/folgas/FolgasCalendarioDialog";
 import {
   Select,
   SelectContent,
@@ -29,6 +32,8 @@ import {
 import { TooltipProvider } from "@/components/ui/tooltip";
 import { FormValidationAlert } from "@/components/ui/form-validation-alert";
 import { useFolgasPage } from "@/hooks/useFolgasPage";
+import { useFolgasTrocas } from "@/hooks/useFolgasTrocas";
+import { useFolgasAusencias } from "@/hooks/useFolgasAusencias";
 import {
   CalendarDays,
   ChevronLeft,
@@ -57,6 +62,8 @@ function FolgasContent() {
     fimMes,
     calendarioDias,
     config,
+    escala,
+    ausencias: ausenciasEscala,
     loadingEscala,
     rodizioPorDiaMap,
     historico,
@@ -70,6 +77,8 @@ function FolgasContent() {
     setCfgOpen,
     gerarOpen,
     setGerarOpen,
+    calendarioOpen,
+    setCalendarioOpen,
     alterOpen,
     setAlterOpen,
     justOpen,
@@ -79,14 +88,10 @@ function FolgasContent() {
     diaDetalhes,
     diaDetalhesOpen,
     setDiaDetalhesOpen,
-    cfgAnchor,
-    setCfgAnchor,
-    cfgS0,
-    setCfgS0,
-    cfgS1,
-    setCfgS1,
-    cfgS2,
-    setCfgS2,
+    cfgEquipes,
+    setCfgEquipes,
+    abrirConfiguracao,
+    equipesMultiplas,
     altUsuario,
     setAltUsuario,
     altMotivo,
@@ -129,6 +134,15 @@ function FolgasContent() {
     ptBR,
     labelRodizioPrevisto,
   } = useFolgasPage();
+  const trocas = useFolgasTrocas({ fazendaId, userId: user?.id, canManage, config, escala });
+  const ausencias = useFolgasAusencias({
+    fazendaId,
+    canManage,
+    ano: month.getFullYear(),
+    inicioMes,
+    fimMes,
+    ausencias: ausenciasEscala,
+  });
 
   return (
     <PageContainer variant="default">
@@ -176,9 +190,10 @@ function FolgasContent() {
               </CardHeader>
               <CardContent className="text-base space-y-2">
                 {alertasNoMesCorrente.map((a) => (
-                  <p key={a.data}>
-                    <strong>{parseApiDate(a.data)}</strong>: {a.motivo_alerta} (
-                    {a.quantidade_folga} folgas)
+                  <p key={`${a.data}-${a.troca_id ?? a.equipe_id ?? "sem-equipe"}`}>
+                    <strong>{parseApiDate(a.data)}</strong>
+                    {a.equipe_nome ? ` (${a.equipe_nome})` : ""}: {a.motivo_alerta}
+                    {a.tipo === "CONFLITO" ? ` (${a.quantidade_folga} folgas)` : ""}
                   </p>
                 ))}
               </CardContent>
@@ -192,8 +207,10 @@ function FolgasContent() {
               </summary>
               <div className="mt-2 space-y-2 text-base">
                 {alertasNoMesCorrente.map((a) => (
-                  <p key={a.data}>
-                    <strong>{parseApiDate(a.data)}</strong>: {a.motivo_alerta} ({a.quantidade_folga} folgas)
+                  <p key={`${a.data}-${a.troca_id ?? a.equipe_id ?? "sem-equipe"}`}>
+                    <strong>{parseApiDate(a.data)}</strong>
+                    {a.equipe_nome ? ` (${a.equipe_nome})` : ""}: {a.motivo_alerta}
+                    {a.tipo === "CONFLITO" ? ` (${a.quantidade_folga} folgas)` : ""}
                   </p>
                 ))}
               </div>
@@ -213,7 +230,7 @@ function FolgasContent() {
               <CardContent className="space-y-3 text-base">
                 <p className="text-muted-foreground">
                   Comparativo entre folgas <strong>registradas</strong> no período e dias em que o
-                  rodízio 5x1 <strong>prevê</strong> folga para cada slot. Não bloqueia alterações.
+                  rodízio <strong>prevê</strong> folga para cada participante. Não bloqueia alterações.
                 </p>
                 {equidadeComDesvio && (
                   <p className="rounded-md border border-feedback-warning/40 bg-feedback-warning/10 px-3 py-2 text-feedback-warning-foreground">
@@ -232,9 +249,11 @@ function FolgasContent() {
                       }
                     >
                       <strong>{r.usuario_nome || `Usuário #${r.usuario_id}`}</strong>
+                      {equipesMultiplas ? ` (${r.equipe_nome})` : ""}
                       {" — "}
                       {r.folgas_registradas} registrada(s) vs {r.folgas_teoricas_auto} prevista(s)
                       {r.delta !== 0 ? ` (Δ ${r.delta > 0 ? "+" : ""}${r.delta})` : ""}
+                      {r.dias_ausente > 0 ? ` · ${r.dias_ausente} dia(s) ausente` : ""}
                     </li>
                   ))}
                 </ul>
@@ -252,14 +271,16 @@ function FolgasContent() {
               <div className="mt-3 space-y-3 text-base">
                 <p className="text-muted-foreground">
                   Comparativo entre folgas <strong>registradas</strong> e o que o rodízio
-                  <strong> prevê</strong> para cada slot.
+                  <strong> prevê</strong> para cada participante.
                 </p>
                 {resumoEquidade.map((r) => (
                   <p key={r.usuario_id}>
                     <strong>{r.usuario_nome || `Usuário #${r.usuario_id}`}</strong>
+                    {equipesMultiplas ? ` (${r.equipe_nome})` : ""}
                     {" — "}
                     {r.folgas_registradas} registrada(s) vs {r.folgas_teoricas_auto} prevista(s)
                     {r.delta !== 0 ? ` (Δ ${r.delta > 0 ? "+" : ""}${r.delta})` : ""}
+                    {r.dias_ausente > 0 ? ` · ${r.dias_ausente} dia(s) ausente` : ""}
                   </p>
                 ))}
               </div>
@@ -271,7 +292,7 @@ function FolgasContent() {
               <div className="space-y-1">
                 <CardTitle className="flex items-center gap-2 text-xl font-semibold">
                   <CalendarDays className="h-5 w-5 shrink-0" aria-hidden />
-                  Escala de folgas (5x1)
+                  Escala de folgas
                 </CardTitle>
                 {fazendaNomeVisivel ? (
                   <p className="text-base text-muted-foreground">
@@ -346,21 +367,7 @@ function FolgasContent() {
                     <Button
                       type="button"
                       className="min-h-[44px] w-full"
-                      onClick={() => {
-                        setFormError("");
-                        if (config) {
-                          setCfgAnchor(parseApiDate(config.data_anchor));
-                          setCfgS0(String(config.usuario_slot_0));
-                          setCfgS1(String(config.usuario_slot_1));
-                          setCfgS2(String(config.usuario_slot_2));
-                        } else {
-                          setCfgAnchor(toYMD(new Date()));
-                          setCfgS0("");
-                          setCfgS1("");
-                          setCfgS2("");
-                        }
-                        setCfgOpen(true);
-                      }}
+                      onClick={abrirConfiguracao}
                     >
                       Configurar escala
                     </Button>
@@ -378,6 +385,16 @@ function FolgasContent() {
                     </Button>
                   </div>
                 )}
+                {fazendaId && (
+                  <Button
+                    type="button"
+                    variant="outline"
+                    className="min-h-[44px] w-full"
+                    onClick={() => setCalendarioOpen(true)}
+                  >
+                    Assinar no calendário do celular
+                  </Button>
+                )}
               </div>
 
               {canManage && (
@@ -430,8 +447,8 @@ function FolgasContent() {
 
               {!config && canManage && (
                 <p className="text-base text-muted-foreground">
-                  Defina primeiro a configuração (âncora e os três funcionários
-                  no rodízio).
+                  Defina primeiro a configuração (equipes de rodízio com âncora,
+                  ciclo e participantes).
                 </p>
               )}
 
@@ -441,7 +458,7 @@ function FolgasContent() {
                   role="status"
                 >
                   Aviso: no mês há diferença entre folgas registradas e o previsto pelo rodízio
-                  5x1 para algum profissional. Veja o painel &quot;Equidade&quot; acima — as ações de
+                  para algum profissional. Veja o painel &quot;Equidade&quot; acima — as ações de
                   alterar e gerar continuam permitidas.
                 </p>
               )}
@@ -496,6 +513,10 @@ function FolgasContent() {
             </CardContent>
           </Card>
 
+          <FolgasTrocasPanel trocas={trocas} />
+
+          <FolgasAusenciasPanel ausencias={ausencias} usuarios={usuariosFolgasPermitidos} />
+
           {canManage && historico.length > 0 && (
             <Card className="mt-6">
               <CardHeader>
@@ -512,47 +533,21 @@ function FolgasContent() {
       )}
 
       <Dialog open={cfgOpen} onOpenChange={setCfgOpen}>
-        <DialogContent className="max-w-md">
+        <DialogContent className="max-h-[90vh] max-w-lg overflow-y-auto">
           <DialogHeader>
-            <DialogTitle>Configuração 5x1</DialogTitle>
+            <DialogTitle>Configuração do rodízio</DialogTitle>
             <DialogDescription className="text-base text-muted-foreground">
-              Data âncora (ex.: primeira quarta do João). Ordene os três usuários vinculados
-              à fazenda: slot 1 folga no 1º dia do ciclo, slot 2 no 2º, slot 3 no 3º; depois
-              três dias sem folga no rodízio.
+              Uma ou mais equipes, cada uma com ciclo e folgas por ciclo próprios (ex.: 5x1 com
+              três pessoas = ciclo 6 e 1 folga; 6x1 = ciclo 7 e 1 folga; fim de semana alternado =
+              ciclo 14, 2 folgas, âncora num sábado e deslocamentos 0 e 7).
             </DialogDescription>
           </DialogHeader>
           <div className="space-y-5">
-            <div className="space-y-2">
-              <Label htmlFor="folgas-cfg-anchor">Data âncora</Label>
-              <DatePicker
-                id="folgas-cfg-anchor"
-                value={cfgAnchor}
-                onChange={setCfgAnchor}
-                placeholder="Selecione a data âncora"
-              />
-            </div>
-            {["Slot 1 (1º dia folga)", "Slot 2", "Slot 3"].map((label, i) => {
-              const val = [cfgS0, cfgS1, cfgS2][i];
-              const set = [setCfgS0, setCfgS1, setCfgS2][i];
-              const slotId = `folgas-cfg-slot-${i}`;
-              return (
-                <div key={label} className="space-y-2">
-                  <Label htmlFor={slotId}>{label}</Label>
-                  <Select value={val} onValueChange={set}>
-                    <SelectTrigger id={slotId} className="min-h-[44px]">
-                      <SelectValue placeholder="Usuário" />
-                    </SelectTrigger>
-                    <SelectContent>
-                      {usuariosFolgasPermitidos.map((u) => (
-                        <SelectItem key={u.id} value={String(u.id)}>
-                          {u.nome} ({u.email})
-                        </SelectItem>
-                      ))}
-                    </SelectContent>
-                  </Select>
-                </div>
-              );
-            })}
+            <FolgasEquipesEditor
+              equipes={cfgEquipes}
+              onChange={setCfgEquipes}
+              usuarios={usuariosFolgasPermitidos}
+            />
             {formError ? (
               <FormValidationAlert message={formError} />
             ) : null}
@@ -577,6 +572,15 @@ function FolgasContent() {
         </DialogContent>
       </Dialog>
 
+      {fazendaId && (
+        <FolgasCalendarioDialog
+          open={calendarioOpen}
+          onOpenChange={setCalendarioOpen}
+          fazendaId={fazendaId}
+          canManage={canManage}
+        />
+      )}
+
       <Dialog open={gerarOpen} onOpenChange={setGerarOpen}>
         <DialogContent>
           <DialogHeader>
@@ -622,8 +626,8 @@ function FolgasContent() {
               <DialogHeader>
                 <DialogTitle>Alterar dia {diaAlter}</DialogTitle>
                 <DialogDescription className="text-base text-muted-foreground">
-                  Substitui todos os registros do dia ou adiciona uma segunda folga (exige
-                  motivo de exceção do dia).
+                  Substitui as folgas da equipe do funcionário neste dia ou adiciona uma folga
+                  extra (exige motivo de exceção do dia se a equipe passar do previsto).
                 </DialogDescription>
               </DialogHeader>
               <div className="space-y-5">
@@ -716,7 +720,7 @@ function FolgasContent() {
               <DialogHeader>
                 <DialogTitle>Confirmar alteração fora do rodízio</DialogTitle>
                 <DialogDescription className="text-base text-muted-foreground">
-                  Você está registrando uma folga diferente do previsto pelo ciclo 5x1 nesta data.
+                  Você está registrando uma folga diferente do previsto pelo rodízio nesta data.
                   Deseja continuar?
                 </DialogDescription>
               </DialogHeader>
@@ -3,7 +3,7 @@
 import { Suspense, useState, useEffect, useRef } from 'react'
 import { useRouter, useSearchParams, usePathname } from 'next/navigation'
 import Link from 'next/link'
-import { useAuth } from '@/contexts/AuthContext'
+import { useAuth, type LoginResult } from '@/contexts/AuthContext'
 import { Button } from '@/components/ui/button'
 import { Input } from '@/components/ui/input'
 import { Label } from '@/components/ui/label'
@@ -26,6 +26,11 @@ import {
   isSafeInternalPath,
 } from '@/config/appAccess'
 import { getMinhasFazendas } from '@/services/fazendas'
+import { toast } from '@/hooks/use-toast'
+import { getPerfilLabel } from '@/lib/perfilLabels'
+import { ConvitePreviewNotice } from '@/components/convites/ConvitePreviewNotice'
+import { getOidcConfig, oidcLoginUrl, type OidcConfig } from '@/services/auth'
+import { desafioDoFragmento, mensagemErroSSO } from '@/lib/sso'
 
 function resolvePostLoginTarget(
   perfil: string | undefined,
@@ -84,13 +89,41 @@ function LoginForm() {
   const [isValidationError, setIsValidationError] = useState(false)
   const [fieldErrors, setFieldErrors] = useState<FieldErrors>({})
   const [loading, setLoading] = useState(false)
-  const { login, user, isAuthenticated, isReady } = useAuth()
+  // Segundo passo (BR-ACESSO-027): desafio devolvido pela senha e código do autenticador.
+  const [desafio, setDesafio] = useState('')
+  const [codigo, setCodigo] = useState('')
+  // Login único (BR-ACESSO-029): botão só aparece com o provedor configurado na API.
+  const [oidc, setOidc] = useState<OidcConfig | null>(null)
+  const { login, loginDoisFatores, user, isAuthenticated, isReady } = useAuth()
   const router = useRouter()
   const pathname = usePathname()
   const searchParams = useSearchParams()
   const explicitRedirect = searchParams.get('redirect')
+  // Convite recebido por link (BR-ACESSO-010): resgatado junto com o login.
+  const convite = searchParams.get('convite')?.trim() ?? ''
   const hasRedirected = useRef(false)
 
+  useEffect(() => {
+    getOidcConfig()
+      .then(setOidc)
+      .catch(() => setOidc(null))
+  }, [])
+
+  // Volta do provedor: erro em ?sso_erro= ou, com 2FA ativo, o desafio em #desafio=.
+  useEffect(() => {
+    const erroSSO = mensagemErroSSO(searchParams.get('sso_erro'))
+    if (erroSSO) {
+      setError(erroSSO)
+      setIsValidationError(false)
+    }
+    const desafioSSO = desafioDoFragmento(window.location.hash)
+    if (desafioSSO) {
+      setDesafio(desafioSSO)
+      window.history.replaceState(null, '', window.location.pathname + window.location.search)
+    }
+    // eslint-disable-next-line react-hooks/exhaustive-deps
+  }, [])
+
   // Redirecionar usuário já autenticado que acessa /login (apenas uma vez)
   useEffect(() => {
     if (hasRedirected.current) return
@@ -98,6 +131,11 @@ function LoginForm() {
     if (pathname !== '/login') return
 
     hasRedirected.current = true
+    // Sessão aberta pelo login único de um perfil obrigado a 2FA sem inscrição.
+    if (user.doisFatoresPendente) {
+      window.location.href = '/conta/seguranca'
+      return
+    }
     const target = resolvePostLoginTarget(user.perfil, explicitRedirect)
     if (target !== '/login') {
       // Usar window.location para evitar loops do Next.js router
@@ -122,16 +160,13 @@ function LoginForm() {
 
     setLoading(true)
     try {
-      const logged = await login(email, password)
-      hasRedirected.current = true
-      const onboardingTarget = await maybeRedirectToOnboarding(
-        logged?.perfil,
-        explicitRedirect
-      )
-      const target =
-        onboardingTarget ??
-        resolvePostLoginTarget(logged?.perfil, explicitRedirect)
-      router.replace(target)
+      const result = await login(email, password, convite || undefined)
+      if (result.desafio) {
+        setDesafio(result.desafio)
+        setLoading(false)
+        return
+      }
+      await finalizarLogin(result)
     } catch (err: unknown) {
       setError(
         getApiErrorMessage(err, 'Erro ao fazer login. Verifique email e senha.')
@@ -141,6 +176,68 @@ function LoginForm() {
     }
   }
 
+  const handleSubmitCodigo = async (e: React.FormEvent) => {
+    e.preventDefault()
+    setError('')
+    setIsValidationError(false)
+    if (!codigo.trim()) {
+      setError('Informe o código do autenticador.')
+      setIsValidationError(true)
+      return
+    }
+    setLoading(true)
+    try {
+      await finalizarLogin(
+        await loginDoisFatores(desafio, codigo.trim(), convite || undefined)
+      )
+    } catch (err: unknown) {
+      setError(getApiErrorMessage(err, 'Código inválido.'))
+      setIsValidationError(false)
+      setCodigo('')
+      setLoading(false)
+    }
+  }
+
+  const voltarParaSenha = () => {
+    setDesafio('')
+    setCodigo('')
+    setPassword('')
+    setError('')
+  }
+
+  const finalizarLogin = async ({
+    user: logged,
+    convite: conviteAceito,
+    convite_erro,
+  }: LoginResult) => {
+    if (conviteAceito) {
+      toast.success(
+        'Convite aceito',
+        `${conviteAceito.fazenda_nome} — ${getPerfilLabel(conviteAceito.perfil)}`
+      )
+    } else if (convite_erro) {
+      toast.warning('Convite não aplicado', convite_erro)
+    }
+    hasRedirected.current = true
+    // Perfil obrigado a 2FA sem inscrição: a sessão só serve para configurá-lo.
+    if (logged?.doisFatoresPendente) {
+      toast.info(
+        'Autenticação em dois fatores obrigatória',
+        'Configure o app autenticador para continuar.'
+      )
+      router.replace('/conta/seguranca')
+      return
+    }
+    const onboardingTarget = await maybeRedirectToOnboarding(
+      logged?.perfil,
+      explicitRedirect
+    )
+    const target =
+      onboardingTarget ??
+      resolvePostLoginTarget(logged?.perfil, explicitRedirect)
+    router.replace(target)
+  }
+
   if (isReady && isAuthenticated) {
     return (
       <PageContainer variant="centered">
@@ -154,45 +251,122 @@ function LoginForm() {
       <Card className="w-full max-w-sm">
         <CardHeader>
           <CardTitle>CeialMilk</CardTitle>
-          <CardDescription>Entre com seu email e senha</CardDescription>
+          <CardDescription>
+            {desafio
+              ? 'Informe o código de 6 dígitos do app autenticador'
+              : 'Entre com seu email e senha'}
+          </CardDescription>
         </CardHeader>
         <CardContent>
-          <form onSubmit={handleSubmit} className="space-y-4">
-            {error?.trim() ? (
-              <FormValidationAlert message={error} isValidation={isValidationError} />
-            ) : null}
-            <div className="space-y-2">
-              <Label htmlFor="email">Email</Label>
-              <Input
-                id="email"
-                type="email"
-                placeholder="admin@ceialmilk.com"
-                value={email}
-                onChange={(e) => setEmail(e.target.value)}
-                aria-invalid={fieldErrors.email ? true : undefined}
-                required
-              />
-              <FormFieldError message={fieldErrors.email} />
-            </div>
-            <div className="space-y-2">
-              <Label htmlFor="password">Senha</Label>
-              <Input
-                id="password"
-                type="password"
-                value={password}
-                onChange={(e) => setPassword(e.target.value)}
-                aria-invalid={fieldErrors.password ? true : undefined}
-                required
-              />
-              <FormFieldError message={fieldErrors.password} />
-            </div>
-            <Button type="submit" className="w-full" disabled={loading}>
-              {loading ? 'Entrando…' : 'Entrar'}
-            </Button>
-          </form>
+          {desafio ? (
+            <form onSubmit={handleSubmitCodigo} className="space-y-4">
+              {error?.trim() ? (
+                <FormValidationAlert message={error} isValidation={isValidationError} />
+              ) : null}
+              <div className="space-y-2">
+                <Label htmlFor="codigo">Código</Label>
+                <Input
+                  id="codigo"
+                  inputMode="numeric"
+                  autoComplete="one-time-code"
+                  autoFocus
+                  placeholder="123456"
+                  value={codigo}
+                  onChange={(e) => setCodigo(e.target.value)}
+                  required
+                />
+                <p className="text-xs text-muted-foreground">
+                  Sem acesso ao autenticador? Use um dos códigos de recuperação
+                  (formato XXXXX-XXXXX).
+                </p>
+              </div>
+              <Button type="submit" className="w-full" disabled={loading}>
+                {loading ? 'Verificando…' : 'Confirmar'}
+              </Button>
+              <Button
+                type="button"
+                variant="ghost"
+                className="w-full"
+                onClick={voltarParaSenha}
+                disabled={loading}
+              >
+                Voltar
+              </Button>
+            </form>
+          ) : (
+            <form onSubmit={handleSubmit} className="space-y-4">
+              {error?.trim() ? (
+                <FormValidationAlert message={error} isValidation={isValidationError} />
+              ) : null}
+              {convite ? <ConvitePreviewNotice codigo={convite} /> : null}
+              <div className="space-y-2">
+                <Label htmlFor="email">Email</Label>
+                <Input
+                  id="email"
+                  type="email"
+                  placeholder="admin@ceialmilk.com"
+                  value={email}
+                  onChange={(e) => setEmail(e.target.value)}
+                  aria-invalid={fieldErrors.email ? true : undefined}
+                  required
+                />
+                <FormFieldError message={fieldErrors.email} />
+              </div>
+              <div className="space-y-2">
+                <div className="flex items-center justify-between">
+                  <Label htmlFor="password">Senha</Label>
+                  <Link
+                    href="/esqueci-senha"
+                    className="text-sm text-muted-foreground underline hover:text-foreground"
+                  >
+                    Esqueceu a senha?
+                  </Link>
+                </div>
+                <Input
+                  id="password"
+                  type="password"
+                  value={password}
+                  onChange={(e) => setPassword(e.target.value)}
+                  aria-invalid={fieldErrors.password ? true : undefined}
+                  required
+                />
+                <FormFieldError message={fieldErrors.password} />
+              </div>
+              <Button type="submit" className="w-full" disabled={loading}>
+                {loading ? 'Entrando…' : 'Entrar'}
+              </Button>
+              {oidc?.habilitado ? (
+                <>
+                  <div className="flex items-center gap-2 text-xs text-muted-foreground">
+                    <span className="h-px flex-1 bg-border" />
+                    ou
+                    <span className="h-px flex-1 bg-border" />
+                  </div>
+                  <Button
+                    type="button"
+                    variant="outline"
+                    className="w-full"
+                    disabled={loading}
+                    onClick={() => {
+                      window.location.href = oidcLoginUrl(explicitRedirect)
+                    }}
+                  >
+                    Entrar com {oidc.nome || 'SSO'}
+                  </Button>
+                </>
+              ) : null}
+            </form>
+          )}
           <p className="mt-4 text-center text-sm text-muted-foreground">
             Não tem uma conta?{' '}
-            <Link href="/registro" className="underline hover:text-foreground">
+            <Link
+              href={
+                convite
+                  ? `/registro?convite=${encodeURIComponent(convite)}`
+                  : '/registro'
+              }
+              className="underline hover:text-foreground"
+            >
               Registre-se
             </Link>
           </p>
@@ -19,9 +19,16 @@ function NovoLoteContent() {
   const queryClient = useQueryClient();
   const [nome, setNome] = useState("");
   const [tipo, setTipo] = useState("");
+  const [capacidade, setCapacidade] = useState("");
 
   const createMutation = useMutation({
-    mutationFn: () => create({ nome, fazenda_id: fazendaAtiva!.id, tipo: tipo || undefined }),
+    mutationFn: () =>
+      create({
+        nome,
+        fazenda_id: fazendaAtiva!.id,
+        tipo: tipo || undefined,
+        capacidade: capacidade ? Number(capacidade) : undefined,
+      }),
     onSuccess: () => {
       queryClient.invalidateQueries({ queryKey: ["lotes", fazendaAtiva?.id] });
       router.push("/lotes");
@@ -53,6 +60,17 @@ function NovoLoteContent() {
             <Label htmlFor="tipo">Tipo (opcional)</Label>
             <Input id="tipo" value={tipo} onChange={(e) => setTipo(e.target.value)} placeholder="LACTACAO, SECAS, etc." />
           </div>
+          <div>
+            <Label htmlFor="capacidade">Capacidade (opcional)</Label>
+            <Input
+              id="capacidade"
+              type="number"
+              min={1}
+              value={capacidade}
+              onChange={(e) => setCapacidade(e.target.value)}
+              placeholder="Nº máximo de animais"
+            />
+          </div>
           <Button onClick={() => createMutation.mutate()} disabled={!nome.trim() || createMutation.isPending}>
             {createMutation.isPending ? "Salvando…" : "Criar lote"}
           </Button>
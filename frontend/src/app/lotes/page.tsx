@@ -1,6 +1,10 @@
 "use client";
 
 import { useFazendaAtiva } from "@/contexts/FazendaContext";
+import { useAuth } from "@/contexts/AuthContext";
+import { canDecidirPropostasLote } from "@/config/appAccess";
+import { PropostasMovimentacaoPanel } from "@/components/lotes/PropostasMovimentacaoPanel";
+import { DesempenhoLotesCard } from "@/components/lotes/DesempenhoLotesCard";
 import { useQuery } from "@tanstack/react-query";
 import { listByFazenda } from "@/services/lotes";
 import { ProtectedRoute } from "@/components/layout/ProtectedRoute";
@@ -13,6 +17,7 @@ import { Plus } from "lucide-react";
 
 function LotesContent() {
   const { fazendaAtiva } = useFazendaAtiva();
+  const { user } = useAuth();
   const fazendaId = fazendaAtiva?.id ?? 0;
 
   const { data: items = [], isLoading, error } = useQuery({
@@ -31,6 +36,7 @@ function LotesContent() {
 
   return (
     <PageContainer variant="default">
+      {canDecidirPropostasLote(user?.perfil) && <PropostasMovimentacaoPanel fazendaId={fazendaId} />}
       <ListCardLayout
         title={`Lotes – ${fazendaAtiva.nome}`}
         action={
@@ -54,13 +60,19 @@ function LotesContent() {
               {items.map((l) => (
                 <li key={l.id} className="flex items-center justify-between border-b pb-2">
                   <span className="font-medium">{l.nome}</span>
-                  <span className="text-muted-foreground text-sm">{l.tipo ?? "—"}</span>
+                  <span className="text-muted-foreground text-sm">
+                    {l.tipo ?? "—"} ·{" "}
+                    <span className={l.capacidade != null && l.ocupacao > l.capacidade ? "font-medium text-destructive" : undefined}>
+                      {l.capacidade != null ? `${l.ocupacao}/${l.capacidade}` : l.ocupacao} animais
+                    </span>
+                  </span>
                 </li>
               ))}
             </ul>
           )}
         </QueryListContent>
       </ListCardLayout>
+      <DesempenhoLotesCard fazendaId={fazendaId} />
     </PageContainer>
   );
 }
@@ -20,6 +20,7 @@ import { OnboardingColdSummary } from '@/components/onboarding/OnboardingColdSum
 import { PassosPerfilSoPendente } from '@/components/onboarding/PassosPerfilSoPendente'
 import { FaqOnboarding } from '@/components/onboarding/FaqOnboarding'
 import { isOnboardingWizardCompleted } from '@/lib/onboardingStorage'
+import { ResgatarConviteCard } from '@/components/convites/ResgatarConviteCard'
 
 function OnboardingContent() {
   const { user, isReady, isAuthenticated, logout } = useAuth()
@@ -70,20 +71,23 @@ function OnboardingContent() {
     const wizardDone = isOnboardingWizardCompleted(user.id)
     return (
       <PageContainer variant="centered">
-        {wizardDone ? (
-          <OnboardingColdSummary
-            userName={user.nome ?? ''}
-            onLogout={() => {
-              logout()
-            }}
-          />
-        ) : (
-          <OnboardingColdWizard
-            userId={user.id}
-            userName={user.nome ?? ''}
-            userEmail={user.email ?? ''}
-          />
-        )}
+        <div className="flex w-full max-w-2xl flex-col gap-4">
+          {wizardDone ? (
+            <OnboardingColdSummary
+              userName={user.nome ?? ''}
+              onLogout={() => {
+                logout()
+              }}
+            />
+          ) : (
+            <OnboardingColdWizard
+              userId={user.id}
+              userName={user.nome ?? ''}
+              userEmail={user.email ?? ''}
+            />
+          )}
+          <ResgatarConviteCard />
+        </div>
       </PageContainer>
     )
   }
@@ -129,9 +133,9 @@ function OnboardingContent() {
                   <ol className="mt-3 list-decimal space-y-2 pl-5 text-sm text-muted-foreground">
                     <li>Crie a primeira fazenda no sistema (dados da exploração).</li>
                     <li>
-                      Convide a equipa: crie utilizadores ou oriente-os a
-                      registarem-se; depois vincule fazendas e perfis no painel
-                      de utilizadores.
+                      Convide a equipa: gere códigos de convite na página da
+                      fazenda ou crie utilizadores e vincule fazendas e perfis no
+                      painel de utilizadores.
                     </li>
                   </ol>
                 </section>
@@ -172,6 +176,7 @@ function OnboardingContent() {
                   </div>
                 </div>
                 <PassosPerfilSoPendente />
+                <ResgatarConviteCard />
                 <FaqOnboarding />
                 <div className="flex flex-col sm:flex-row gap-3 justify-center">
                   <Button variant="outline" size="lg" asChild>
@@ -0,0 +1,155 @@
+'use client'
+
+import { Suspense, useState } from 'react'
+import { useRouter, useSearchParams } from 'next/navigation'
+import Link from 'next/link'
+import { useAuth } from '@/contexts/AuthContext'
+import { resetPassword } from '@/services/auth'
+import { Button } from '@/components/ui/button'
+import { Input } from '@/components/ui/input'
+import { Label } from '@/components/ui/label'
+import {
+  Card,
+  CardContent,
+  CardDescription,
+  CardHeader,
+  CardTitle,
+} from '@/components/ui/card'
+import { PageContainer } from '@/components/layout/PageContainer'
+import { FormFieldError } from '@/components/ui/form-field-error'
+import { FormValidationAlert } from '@/components/ui/form-validation-alert'
+import { toast } from '@/hooks/use-toast'
+import { getApiErrorMessage } from '@/lib/errors'
+import { validateNovaSenhaForm, type FieldErrors } from '@/lib/form-validation'
+
+/** Nova senha a partir do link do email (BR-ACESSO-026); encerra todas as sessões da conta. */
+function RedefinirSenhaForm() {
+  const searchParams = useSearchParams()
+  const token = searchParams.get('token')?.trim() ?? ''
+  const [password, setPassword] = useState('')
+  const [confirmPassword, setConfirmPassword] = useState('')
+  const [error, setError] = useState('')
+  const [isValidationError, setIsValidationError] = useState(false)
+  const [fieldErrors, setFieldErrors] = useState<FieldErrors>({})
+  const [loading, setLoading] = useState(false)
+  const { isAuthenticated, reloadUser } = useAuth()
+  const router = useRouter()
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault()
+    setError('')
+    setIsValidationError(false)
+    setFieldErrors({})
+
+    const validation = validateNovaSenhaForm({ password, confirmPassword })
+    if (!validation.valid) {
+      setFieldErrors(validation.fields)
+      setError(validation.summary ?? 'Corrija os campos assinalados.')
+      setIsValidationError(true)
+      return
+    }
+
+    setLoading(true)
+    try {
+      await resetPassword(token, password)
+      toast.success('Senha redefinida', 'Entre com a nova senha.')
+      // O backend limpou os cookies; a sessão local também acabou.
+      if (isAuthenticated) await reloadUser()
+      router.replace('/login')
+    } catch (err: unknown) {
+      setError(getApiErrorMessage(err, 'Não foi possível redefinir a senha.'))
+      setIsValidationError(false)
+      setLoading(false)
+    }
+  }
+
+  if (!token) {
+    return (
+      <PageContainer variant="centered">
+        <Card className="w-full max-w-sm">
+          <CardHeader>
+            <CardTitle>Link incompleto</CardTitle>
+            <CardDescription>
+              Abra o link completo enviado por email ou peça um novo.
+            </CardDescription>
+          </CardHeader>
+          <CardContent>
+            <Button asChild className="w-full">
+              <Link href="/esqueci-senha">Pedir novo link</Link>
+            </Button>
+          </CardContent>
+        </Card>
+      </PageContainer>
+    )
+  }
+
+  return (
+    <PageContainer variant="centered">
+      <Card className="w-full max-w-sm">
+        <CardHeader>
+          <CardTitle>Redefinir senha</CardTitle>
+          <CardDescription>
+            Escolha uma nova senha. Todas as sessões abertas da conta serão
+            encerradas.
+          </CardDescription>
+        </CardHeader>
+        <CardContent>
+          <form onSubmit={handleSubmit} className="space-y-4">
+            {error?.trim() ? (
+              <FormValidationAlert message={error} isValidation={isValidationError} />
+            ) : null}
+            <div className="space-y-2">
+              <Label htmlFor="password">Nova senha</Label>
+              <Input
+                id="password"
+                type="password"
+                autoComplete="new-password"
+                value={password}
+                onChange={(e) => setPassword(e.target.value)}
+                aria-invalid={fieldErrors.senha ? true : undefined}
+                required
+              />
+              <FormFieldError message={fieldErrors.senha} />
+            </div>
+            <div className="space-y-2">
+              <Label htmlFor="confirmPassword">Confirmar nova senha</Label>
+              <Input
+                id="confirmPassword"
+                type="password"
+                autoComplete="new-password"
+                value={confirmPassword}
+                onChange={(e) => setConfirmPassword(e.target.value)}
+                aria-invalid={fieldErrors.confirmPassword ? true : undefined}
+                required
+              />
+              <FormFieldError message={fieldErrors.confirmPassword} />
+            </div>
+            <Button type="submit" className="w-full" disabled={loading}>
+              {loading ? 'Salvando…' : 'Redefinir senha'}
+            </Button>
+          </form>
+          <p className="mt-4 text-center text-sm text-muted-foreground">
+            Link expirado?{' '}
+            <Link href="/esqueci-senha" className="underline hover:text-foreground">
+              Peça outro
+            </Link>
+          </p>
+        </CardContent>
+      </Card>
+    </PageContainer>
+  )
+}
+
+export default function RedefinirSenhaPage() {
+  return (
+    <Suspense
+      fallback={
+        <PageContainer variant="centered">
+          <p className="text-muted-foreground">Carregando…</p>
+        </PageContainer>
+      }
+    >
+      <RedefinirSenhaForm />
+    </Suspense>
+  )
+}
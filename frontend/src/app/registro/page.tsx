@@ -22,6 +22,9 @@ import { toast } from '@/hooks/use-toast'
 import { getApiErrorMessage } from '@/lib/errors'
 import { validateRegistroForm, type FieldErrors } from '@/lib/form-validation'
 import { getAreasMode, getDefaultLandingPath } from '@/config/appAccess'
+import { getPerfilLabel } from '@/lib/perfilLabels'
+import { ConvitePreviewNotice } from '@/components/convites/ConvitePreviewNotice'
+import type { ConviteResgate } from '@/services/convites'
 
 function RegistroForm() {
   const [nome, setNome] = useState('')
@@ -36,6 +39,8 @@ function RegistroForm() {
   const { user, isAuthenticated, isReady } = useAuth()
   const router = useRouter()
   const searchParams = useSearchParams()
+  const [convite, setConvite] = useState(searchParams.get('convite') ?? '')
+  const [conviteAceito, setConviteAceito] = useState<ConviteResgate | null>(null)
   const redirect = searchParams.get('redirect') ?? '/login'
 
   // Redirecionar se já estiver autenticado, respeitando o perfil
@@ -68,8 +73,24 @@ function RegistroForm() {
 
     setLoading(true)
     try {
-      await register({ nome, email, password })
-      toast.success('Conta criada')
+      const codigo = convite.trim()
+      const created = await register({
+        nome,
+        email,
+        password,
+        ...(codigo ? { convite: codigo } : {}),
+      })
+      toast.success(
+        'Conta criada',
+        created.email_verificacao_enviada
+          ? `Enviamos um link para confirmar ${created.email}.`
+          : undefined
+      )
+      if (created.convite) {
+        setConviteAceito(created.convite)
+      } else if (created.convite_erro) {
+        toast.warning('Convite não aplicado', created.convite_erro)
+      }
       setSuccess(true)
       // Redirecionar para login após 2 segundos
       setTimeout(() => {
@@ -93,6 +114,31 @@ function RegistroForm() {
     )
   }
 
+  if (success && conviteAceito) {
+    return (
+      <PageContainer variant="centered">
+        <Card className="w-full max-w-lg">
+          <CardHeader>
+            <CardTitle>Conta criada</CardTitle>
+            <CardDescription>
+              O convite foi aceito: a sua conta está vinculada a{' '}
+              <strong>{conviteAceito.fazenda_nome}</strong> com o perfil{' '}
+              <strong>{getPerfilLabel(conviteAceito.perfil)}</strong>. Faça login
+              com seu email e senha para começar.
+            </CardDescription>
+          </CardHeader>
+          <CardContent>
+            <p className="text-center text-sm text-muted-foreground">
+              <Link href="/login" className="underline hover:text-foreground">
+                Ir para login agora
+              </Link>
+            </p>
+          </CardContent>
+        </Card>
+      </PageContainer>
+    )
+  }
+
   if (success) {
     return (
       <PageContainer variant="centered">
@@ -214,6 +260,19 @@ function RegistroForm() {
               />
               <FormFieldError message={fieldErrors.confirmPassword} />
             </div>
+            <div className="space-y-2">
+              <Label htmlFor="convite">Código de convite (opcional)</Label>
+              <Input
+                id="convite"
+                type="text"
+                placeholder="XXXX-XXXX-XXXX"
+                value={convite}
+                onChange={(e) => setConvite(e.target.value)}
+                autoComplete="off"
+                autoCapitalize="characters"
+              />
+              <ConvitePreviewNotice codigo={convite} />
+            </div>
             <Button type="submit" className="w-full" disabled={loading}>
               {loading ? 'Criando conta…' : 'Criar conta'}
             </Button>
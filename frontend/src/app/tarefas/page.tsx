@@ -0,0 +1,166 @@
+"use client";
+
+import { ProtectedRoute } from "@/components/layout/ProtectedRoute";
+import { PageContainer } from "@/components/layout/PageContainer";
+import { ListCardLayout } from "@/components/layout/ListCardLayout";
+import { QueryListContent } from "@/components/layout/QueryListContent";
+import { DeleteRecordDialog } from "@/components/layout/list/DeleteRecordDialog";
+import { MinhasTarefasHojeCard } from "@/components/tarefas/MinhasTarefasHojeCard";
+import { TarefaCard } from "@/components/tarefas/TarefaCard";
+import { TarefaFormDialog } from "@/components/tarefas/TarefaFormDialog";
+import { Button } from "@/components/ui/button";
+import { Label } from "@/components/ui/label";
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from "@/components/ui/select";
+import {
+  TAREFAS_FILTRO_LABEL,
+  useTarefasPage,
+  type TarefasFiltro,
+} from "@/hooks/useTarefasPage";
+import type { Tarefa } from "@/services/tarefas";
+import { Plus } from "lucide-react";
+
+function TarefasContent() {
+  const {
+    fazendaReady,
+    fazendaAtiva,
+    fazendaId,
+    canManage,
+    podeExecutar,
+    filtro,
+    setFiltro,
+    minhasQuery,
+    listaQuery,
+    usuarios,
+    statusMutation,
+    checklistMutation,
+    deleteMutation,
+    formOpen,
+    setFormOpen,
+    editando,
+    excluirId,
+    setExcluirId,
+    abrirNova,
+    abrirEdicao,
+    invalidate,
+  } = useTarefasPage();
+
+  if (fazendaReady && !fazendaAtiva) {
+    return (
+      <PageContainer variant="default">
+        <p className="text-muted-foreground">
+          Selecione uma fazenda no topo da página para ver as tarefas.
+        </p>
+      </PageContainer>
+    );
+  }
+
+  if (!fazendaReady || !fazendaAtiva) {
+    return (
+      <PageContainer variant="default">
+        <p className="text-muted-foreground">A carregar…</p>
+      </PageContainer>
+    );
+  }
+
+  const pending = statusMutation.isPending || checklistMutation.isPending;
+  const renderTarefa = (t: Tarefa) => (
+    <TarefaCard
+      key={t.id}
+      tarefa={t}
+      podeExecutar={podeExecutar(t)}
+      canManage={canManage}
+      pending={pending}
+      onStatus={(status) => statusMutation.mutate({ id: t.id, status })}
+      onChecklist={(itemId, feito) => checklistMutation.mutate({ tarefaId: t.id, itemId, feito })}
+      onEdit={() => abrirEdicao(t)}
+      onDelete={() => setExcluirId(t.id)}
+    />
+  );
+  const tarefas = listaQuery.data?.tarefas ?? [];
+
+  return (
+    <PageContainer variant="default">
+      <MinhasTarefasHojeCard data={minhasQuery.data} isLoading={minhasQuery.isLoading}>
+        {minhasQuery.data?.tarefas.map(renderTarefa)}
+      </MinhasTarefasHojeCard>
+
+      <ListCardLayout
+        title={`Tarefas – ${fazendaAtiva.nome}`}
+        action={
+          canManage ? (
+            <Button type="button" onClick={abrirNova}>
+              <Plus className="h-4 w-4 mr-2" aria-hidden />
+              Nova tarefa
+            </Button>
+          ) : null
+        }
+      >
+        <div className="mb-4 max-w-xs space-y-2">
+          <Label htmlFor="tarefas-filtro">Mostrar</Label>
+          <Select value={filtro} onValueChange={(v) => setFiltro(v as TarefasFiltro)}>
+            <SelectTrigger id="tarefas-filtro" className="min-h-[44px]">
+              <SelectValue />
+            </SelectTrigger>
+            <SelectContent>
+              {(Object.keys(TAREFAS_FILTRO_LABEL) as TarefasFiltro[]).map((f) => (
+                <SelectItem key={f} value={f}>
+                  {TAREFAS_FILTRO_LABEL[f]}
+                </SelectItem>
+              ))}
+            </SelectContent>
+          </Select>
+        </div>
+        <QueryListContent
+          isLoading={listaQuery.isLoading}
+          error={listaQuery.error}
+          errorFallback="Erro ao carregar tarefas."
+          onRetry={() => void listaQuery.refetch()}
+        >
+          {tarefas.length === 0 ? (
+            <p className="text-muted-foreground">Nenhuma tarefa neste filtro.</p>
+          ) : (
+            <ul className="space-y-3">{tarefas.map(renderTarefa)}</ul>
+          )}
+        </QueryListContent>
+      </ListCardLayout>
+
+      {canManage && (
+        <TarefaFormDialog
+          fazendaId={fazendaId}
+          open={formOpen}
+          onOpenChange={setFormOpen}
+          tarefa={editando}
+          usuarios={usuarios}
+          onSaved={invalidate}
+        />
+      )}
+
+      <DeleteRecordDialog
+        open={excluirId != null}
+        onOpenChange={(open) => {
+          if (!open) setExcluirId(null);
+        }}
+        title="Excluir tarefa"
+        description="A tarefa e a checklist serão removidas permanentemente. Para encerrar uma série recorrente mantendo o histórico, prefira cancelar."
+        onConfirm={() => {
+          if (excluirId != null) deleteMutation.mutate(excluirId);
+        }}
+        isPending={deleteMutation.isPending}
+      />
+    </PageContainer>
+  );
+}
+
+export default function TarefasPage() {
+  return (
+    <ProtectedRoute>
+      <TarefasContent />
+    </ProtectedRoute>
+  );
+}
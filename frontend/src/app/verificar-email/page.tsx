@@ -0,0 +1,92 @@
+'use client'
+
+import { Suspense, useEffect, useRef, useState } from 'react'
+import { useSearchParams } from 'next/navigation'
+import Link from 'next/link'
+import { useAuth } from '@/contexts/AuthContext'
+import { verifyEmail } from '@/services/auth'
+import { Button } from '@/components/ui/button'
+import {
+  Card,
+  CardContent,
+  CardDescription,
+  CardHeader,
+  CardTitle,
+} from '@/components/ui/card'
+import { PageContainer } from '@/components/layout/PageContainer'
+import { getApiErrorMessage } from '@/lib/errors'
+
+type Estado = 'verificando' | 'ok' | 'erro'
+
+/** Confirmação do email pelo link (BR-ACESSO-026); funciona com ou sem sessão. */
+function VerificarEmailContent() {
+  const searchParams = useSearchParams()
+  const token = searchParams.get('token')?.trim() ?? ''
+  const { isAuthenticated, reloadUser } = useAuth()
+  const [estado, setEstado] = useState<Estado>(token ? 'verificando' : 'erro')
+  const [erro, setErro] = useState(token ? '' : 'Link incompleto.')
+  const started = useRef(false)
+
+  useEffect(() => {
+    // O token é de uso único: não repetir a chamada em re-render.
+    if (!token || started.current) return
+    started.current = true
+    verifyEmail(token)
+      .then(() => {
+        setEstado('ok')
+        void reloadUser()
+      })
+      .catch((err: unknown) => {
+        setErro(getApiErrorMessage(err, 'Link inválido ou expirado.'))
+        setEstado('erro')
+      })
+  }, [token, reloadUser])
+
+  const destino = isAuthenticated ? '/' : '/login'
+
+  return (
+    <PageContainer variant="centered">
+      <Card className="w-full max-w-sm">
+        <CardHeader>
+          <CardTitle>
+            {estado === 'verificando'
+              ? 'Confirmando email…'
+              : estado === 'ok'
+                ? 'Email confirmado'
+                : 'Não foi possível confirmar'}
+          </CardTitle>
+          <CardDescription>
+            {estado === 'ok'
+              ? 'Obrigado! O email da sua conta está confirmado.'
+              : estado === 'erro'
+                ? `${erro} Entre na sua conta e peça um novo link no menu da conta.`
+                : 'Aguarde um instante.'}
+          </CardDescription>
+        </CardHeader>
+        {estado !== 'verificando' ? (
+          <CardContent>
+            <Button asChild className="w-full">
+              <Link href={destino}>
+                {isAuthenticated ? 'Ir para o início' : 'Ir para login'}
+              </Link>
+            </Button>
+          </CardContent>
+        ) : null}
+      </Card>
+    </PageContainer>
+  )
+}
+
+export default function VerificarEmailPage() {
+  return (
+    <Suspense
+      fallback={
+        <PageContainer variant="centered">
+          <p className="text-muted-foreground">Carregando…</p>
+        </PageContainer>
+      }
+    >
+      <VerificarEmailContent />
+    </Suspense>
+  )
+}
@@ -0,0 +1,76 @@
+"use client";
+
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
+import { Button } from "@/components/ui/button";
+import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card";
+import { SessaoItem } from "@/components/conta/SessaoItem";
+import { toast } from "@/hooks/use-toast";
+import { getApiErrorMessage } from "@/lib/errors";
+import { listSessoesUsuario, revogarSessoesUsuario } from "@/services/admin";
+
+/** Sessões ativas de um utilizador no painel admin — ex.: telemóvel perdido na ordenha (BR-ACESSO-028). */
+export function UsuarioSessoesCard({ usuarioId }: { usuarioId: number }) {
+  const queryClient = useQueryClient();
+  const queryKey = ["admin", "usuarios", usuarioId, "sessoes"];
+  const sessoes = useQuery({ queryKey, queryFn: () => listSessoesUsuario(usuarioId) });
+
+  const revogar = useMutation({
+    mutationFn: (sessaoId?: number) => revogarSessoesUsuario(usuarioId, sessaoId),
+    onSuccess: (n) => {
+      toast.success(n === 1 ? "1 sessão encerrada" : `${n} sessões encerradas`);
+      void queryClient.invalidateQueries({ queryKey });
+    },
+    onError: (err: unknown) => {
+      toast.error(getApiErrorMessage(err, "Erro ao encerrar sessões."));
+    },
+  });
+
+  const lista = sessoes.data ?? [];
+
+  return (
+    <Card>
+      <CardHeader>
+        <CardTitle>Sessões ativas</CardTitle>
+        <p className="text-sm text-muted-foreground">
+          Encerrar obriga o dispositivo a entrar de novo com a senha; o acesso já aberto expira em até 15
+          minutos.
+        </p>
+      </CardHeader>
+      <CardContent className="space-y-3">
+        {sessoes.isLoading ? <p className="text-sm text-muted-foreground">Carregando…</p> : null}
+        {sessoes.isError ? (
+          <p className="text-sm text-destructive">
+            {getApiErrorMessage(sessoes.error, "Erro ao carregar sessões.")}
+          </p>
+        ) : null}
+        {!sessoes.isLoading && !sessoes.isError && lista.length === 0 ? (
+          <p className="text-sm text-muted-foreground">Nenhuma sessão ativa.</p>
+        ) : null}
+        {lista.length > 0 ? (
+          <>
+            <ul className="divide-y">
+              {lista.map((s) => (
+                <SessaoItem
+                  key={s.id}
+                  sessao={s}
+                  onEncerrar={() => revogar.mutate(s.id)}
+                  disabled={revogar.isPending}
+                />
+              ))}
+            </ul>
+            <Button
+              type="button"
+              variant="outline"
+              onClick={() => {
+                if (confirm("Encerrar todas as sessões deste utilizador?")) revogar.mutate(undefined);
+              }}
+              disabled={revogar.isPending}
+            >
+              Encerrar todas
+            </Button>
+          </>
+        ) : null}
+      </CardContent>
+    </Card>
+  );
+}
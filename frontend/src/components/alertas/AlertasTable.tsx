@@ -5,6 +5,7 @@ import { useMemo, useState } from "react";
 import { useMutation } from "@tanstack/react-query";
 import type { Alerta, StatusAlerta } from "@/services/alertas";
 import { deleteAlerta } from "@/services/alertas";
+import { converterAlertaEmTarefa } from "@/services/tarefas";
 import { Badge } from "@/components/ui/badge";
 import { DeleteRecordDialog } from "@/components/layout/list/DeleteRecordDialog";
 import { MobileListCard } from "@/components/layout/list/MobileListCard";
@@ -44,6 +45,8 @@ type Props = {
   canEmAndamento: boolean;
   canResolve: boolean;
   canDelete: boolean;
+  /** Gestão converte alertas em aberto em tarefas (BR-TAREFA-003). */
+  canConverterTarefa?: boolean;
   onStatusChange: (alertaId: number, status: StatusAlerta) => void;
   onDeleteSuccess: () => void;
 };
@@ -66,7 +69,8 @@ function buildMenuItems(
   canResolve: boolean,
   canDelete: boolean,
   onStatusChange: Props["onStatusChange"],
-  onDeleteRequest: (id: number) => void
+  onDeleteRequest: (id: number) => void,
+  onConverterTarefa?: (id: number) => void
 ): ListRowActionItem[] {
   const actions: ListRowActionItem[] = [];
   if (canEmAndamento && item.status === "ABERTO") {
@@ -85,6 +89,12 @@ function buildMenuItems(
       onSelect: () => onStatusChange(item.id, "IGNORADO"),
     });
   }
+  if (onConverterTarefa && !["RESOLVIDO", "IGNORADO"].includes(item.status)) {
+    actions.push({
+      label: "Converter em tarefa",
+      onSelect: () => onConverterTarefa(item.id),
+    });
+  }
   if (canDelete && item.tipo === "MANUAL") {
     actions.push({
       label: "Excluir",
@@ -103,6 +113,7 @@ export function AlertasTable({
   canEmAndamento,
   canResolve,
   canDelete,
+  canConverterTarefa = false,
   onStatusChange,
   onDeleteSuccess,
 }: Props) {
@@ -132,6 +143,17 @@ export function AlertasTable({
     },
   });
 
+  const converterMutation = useMutation({
+    mutationFn: (alertaId: number) => converterAlertaEmTarefa(fazendaId, alertaId),
+    onSuccess: (t) => {
+      toast.success(`Tarefa #${t.id} criada a partir do alerta`);
+    },
+    onError: (err: unknown) => {
+      toast.error(getApiErrorMessage(err, "Erro ao converter alerta em tarefa."));
+    },
+  });
+  const converterTarefa = converterMutation.mutate;
+
   const rowActionsById = useMemo(() => {
     const map = new Map<number, ListRowActionItem[]>();
     for (const item of items) {
@@ -143,7 +165,8 @@ export function AlertasTable({
           canResolve,
           canDelete,
           onStatusChange,
-          setDeleteDialogOpenId
+          setDeleteDialogOpenId,
+          canConverterTarefa ? converterTarefa : undefined
         )
       );
     }
@@ -153,6 +176,8 @@ export function AlertasTable({
     canEmAndamento,
     canResolve,
     canDelete,
+    canConverterTarefa,
+    converterTarefa,
     onStatusChange,
   ]);
 
@@ -0,0 +1,137 @@
+"use client";
+
+import { useEffect, useState } from "react";
+import { useMutation } from "@tanstack/react-query";
+import { Button } from "@/components/ui/button";
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogFooter,
+  DialogHeader,
+  DialogTitle,
+} from "@/components/ui/dialog";
+import { FormFieldError } from "@/components/ui/form-field-error";
+import { FormValidationAlert } from "@/components/ui/form-validation-alert";
+import { Input } from "@/components/ui/input";
+import { Label } from "@/components/ui/label";
+import { toast } from "@/hooks/use-toast";
+import { getApiErrorMessage } from "@/lib/errors";
+import { validateNovaSenhaForm, type FieldErrors } from "@/lib/form-validation";
+import { alterarSenha } from "@/services/auth";
+
+type Props = {
+  open: boolean;
+  onOpenChange: (open: boolean) => void;
+};
+
+/** Troca de senha da conta logada (BR-ACESSO-026): as outras sessões são encerradas. */
+export function AlterarSenhaDialog({ open, onOpenChange }: Props) {
+  const [senhaAtual, setSenhaAtual] = useState("");
+  const [novaSenha, setNovaSenha] = useState("");
+  const [confirmPassword, setConfirmPassword] = useState("");
+  const [formError, setFormError] = useState<string | null>(null);
+  const [fieldErrors, setFieldErrors] = useState<FieldErrors>({});
+
+  useEffect(() => {
+    if (!open) return;
+    setSenhaAtual("");
+    setNovaSenha("");
+    setConfirmPassword("");
+    setFormError(null);
+    setFieldErrors({});
+  }, [open]);
+
+  const mutation = useMutation({
+    mutationFn: () => alterarSenha(senhaAtual, novaSenha),
+    onSuccess: () => {
+      toast.success("Senha alterada", "As sessões noutros dispositivos foram encerradas.");
+      onOpenChange(false);
+    },
+    onError: (e) => setFormError(getApiErrorMessage(e, "Erro ao alterar a senha.")),
+  });
+
+  function handleSubmit(e: React.FormEvent) {
+    e.preventDefault();
+    setFormError(null);
+    const validation = validateNovaSenhaForm({
+      senhaAtual,
+      password: novaSenha,
+      confirmPassword,
+    });
+    setFieldErrors(validation.fields);
+    if (!validation.valid) {
+      setFormError(validation.summary ?? "Corrija os campos assinalados.");
+      return;
+    }
+    mutation.mutate();
+  }
+
+  return (
+    <Dialog open={open} onOpenChange={onOpenChange}>
+      <DialogContent className="max-h-[90vh] max-w-md overflow-y-auto">
+        <form onSubmit={handleSubmit} className="space-y-4">
+          <DialogHeader>
+            <DialogTitle>Alterar senha</DialogTitle>
+            <DialogDescription className="text-base text-muted-foreground">
+              Este dispositivo continua com sessão; os outros terão de entrar de novo.
+            </DialogDescription>
+          </DialogHeader>
+          {formError ? <FormValidationAlert message={formError} /> : null}
+          <div className="space-y-2">
+            <Label htmlFor="senha-atual">Senha atual</Label>
+            <Input
+              id="senha-atual"
+              type="password"
+              autoComplete="current-password"
+              value={senhaAtual}
+              onChange={(e) => setSenhaAtual(e.target.value)}
+              aria-invalid={fieldErrors.senhaAtual ? true : undefined}
+              className="min-h-[44px]"
+            />
+            <FormFieldError message={fieldErrors.senhaAtual} />
+          </div>
+          <div className="space-y-2">
+            <Label htmlFor="senha-nova">Nova senha</Label>
+            <Input
+              id="senha-nova"
+              type="password"
+              autoComplete="new-password"
+              value={novaSenha}
+              onChange={(e) => setNovaSenha(e.target.value)}
+              aria-invalid={fieldErrors.senha ? true : undefined}
+              className="min-h-[44px]"
+            />
+            <FormFieldError message={fieldErrors.senha} />
+          </div>
+          <div className="space-y-2">
+            <Label htmlFor="senha-confirmar">Confirmar nova senha</Label>
+            <Input
+              id="senha-confirmar"
+              type="password"
+              autoComplete="new-password"
+              value={confirmPassword}
+              onChange={(e) => setConfirmPassword(e.target.value)}
+              aria-invalid={fieldErrors.confirmPassword ? true : undefined}
+              className="min-h-[44px]"
+            />
+            <FormFieldError message={fieldErrors.confirmPassword} />
+          </div>
+          <DialogFooter>
+            <Button
+              type="button"
+              variant="outline"
+              className="min-h-[44px]"
+              onClick={() => onOpenChange(false)}
+            >
+              Cancelar
+            </Button>
+            <Button type="submit" className="min-h-[44px]" disabled={mutation.isPending}>
+              {mutation.isPending ? "A guardar…" : "Alterar senha"}
+            </Button>
+          </DialogFooter>
+        </form>
+      </DialogContent>
+    </Dialog>
+  );
+}
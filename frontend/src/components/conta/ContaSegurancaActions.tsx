@@ -0,0 +1,64 @@
+"use client";
+
+import Link from "next/link";
+import { useMutation } from "@tanstack/react-query";
+import { MailWarning } from "lucide-react";
+import { Button } from "@/components/ui/button";
+import { useAuth } from "@/contexts/AuthContext";
+import { toast } from "@/hooks/use-toast";
+import { getApiErrorMessage } from "@/lib/errors";
+import { reenviarVerificacaoEmail } from "@/services/auth";
+
+type Props = {
+  onAlterarSenha: () => void;
+};
+
+/**
+ * Aviso de e-mail por confirmar e atalhos para trocar a senha (BR-ACESSO-026) e configurar a
+ * autenticação em dois fatores (BR-ACESSO-027), no menu da conta.
+ */
+export function ContaSegurancaActions({ onAlterarSenha }: Props) {
+  const { user } = useAuth();
+  const reenviar = useMutation({
+    mutationFn: reenviarVerificacaoEmail,
+    onSuccess: () => toast.success("Link enviado", `Confira a caixa de entrada de ${user?.email ?? "seu email"}.`),
+    onError: (e) => toast.error(getApiErrorMessage(e, "Não foi possível enviar o link.")),
+  });
+
+  if (!user) return null;
+
+  return (
+    <div className="flex flex-col gap-2">
+      {!user.emailVerificado ? (
+        <div className="flex gap-2 rounded-md border border-amber-500/40 bg-amber-500/10 p-3 text-sm">
+          <MailWarning className="mt-0.5 h-4 w-4 shrink-0 text-amber-600" aria-hidden />
+          <div className="min-w-0 flex-1 space-y-2">
+            <p>Email ainda não confirmado.</p>
+            <Button
+              type="button"
+              variant="outline"
+              size="sm"
+              className="h-9 w-full"
+              disabled={reenviar.isPending}
+              onClick={() => reenviar.mutate()}
+            >
+              {reenviar.isPending ? "Enviando…" : "Reenviar link de confirmação"}
+            </Button>
+          </div>
+        </div>
+      ) : null}
+      <Button
+        type="button"
+        variant="ghost"
+        size="sm"
+        className="h-10 w-full justify-center text-muted-foreground"
+        onClick={onAlterarSenha}
+      >
+        Alterar senha
+      </Button>
+      <Button asChild variant="ghost" size="sm" className="h-10 w-full justify-center text-muted-foreground">
+        <Link href="/conta/seguranca">Dois fatores e sessões</Link>
+      </Button>
+    </div>
+  );
+}
@@ -0,0 +1,321 @@
+"use client";
+
+import { useState } from "react";
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
+import { ShieldCheck, ShieldAlert } from "lucide-react";
+import { Button } from "@/components/ui/button";
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from "@/components/ui/card";
+import { FormValidationAlert } from "@/components/ui/form-validation-alert";
+import { Input } from "@/components/ui/input";
+import { Label } from "@/components/ui/label";
+import { useAuth } from "@/contexts/AuthContext";
+import { toast } from "@/hooks/use-toast";
+import { getApiErrorMessage } from "@/lib/errors";
+import {
+  ativarDoisFatores,
+  desativarDoisFatores,
+  getDoisFatores,
+  iniciarDoisFatores,
+  regenerarCodigosRecuperacao,
+  type InscricaoDoisFatores,
+} from "@/services/doisFatores";
+
+type Etapa = "status" | "senha" | "inscricao" | "codigos" | "regenerar" | "desativar";
+
+const QUERY_KEY = ["me", "dois-fatores"];
+
+/** Segredo em grupos de 4 para digitar no autenticador sem QR code. */
+function formatarSegredo(segredo: string): string {
+  return segredo.replace(/(.{4})/g, "$1 ").trim();
+}
+
+/** Inscrição, códigos de recuperação e desativação do 2FA por TOTP (BR-ACESSO-027). */
+export function DoisFatoresCard() {
+  const queryClient = useQueryClient();
+  const { reloadUser } = useAuth();
+  const status = useQuery({ queryKey: QUERY_KEY, queryFn: getDoisFatores });
+  const [etapa, setEtapa] = useState<Etapa>("status");
+  const [senha, setSenha] = useState("");
+  const [codigo, setCodigo] = useState("");
+  const [inscricao, setInscricao] = useState<InscricaoDoisFatores | null>(null);
+  const [codigosRecuperacao, setCodigosRecuperacao] = useState<string[]>([]);
+  const [erro, setErro] = useState<string | null>(null);
+
+  function irPara(proxima: Etapa) {
+    setEtapa(proxima);
+    setSenha("");
+    setCodigo("");
+    setErro(null);
+  }
+
+  const iniciar = useMutation({
+    mutationFn: () => iniciarDoisFatores(senha),
+    onSuccess: (data) => {
+      setInscricao(data);
+      irPara("inscricao");
+    },
+    onError: (e) => setErro(getApiErrorMessage(e, "Não foi possível iniciar a configuração.")),
+  });
+
+  const mostrarCodigos = (codigos: string[]) => {
+    setCodigosRecuperacao(codigos);
+    setInscricao(null);
+    irPara("codigos");
+    void queryClient.invalidateQueries({ queryKey: QUERY_KEY });
+  };
+
+  const ativar = useMutation({
+    mutationFn: () => ativarDoisFatores(codigo.trim()),
+    onSuccess: (codigos) => {
+      toast.success("Autenticação em dois fatores ativada", "As sessões noutros dispositivos foram encerradas.");
+      mostrarCodigos(codigos);
+      void reloadUser();
+    },
+    onError: (e) => setErro(getApiErrorMessage(e, "Código inválido.")),
+  });
+
+  const regenerar = useMutation({
+    mutationFn: () => regenerarCodigosRecuperacao(codigo.trim()),
+    onSuccess: mostrarCodigos,
+    onError: (e) => setErro(getApiErrorMessage(e, "Código inválido.")),
+  });
+
+  const desativar = useMutation({
+    mutationFn: () => desativarDoisFatores(senha, codigo.trim()),
+    onSuccess: () => {
+      toast.success("Autenticação em dois fatores desativada");
+      irPara("status");
+      void queryClient.invalidateQueries({ queryKey: QUERY_KEY });
+    },
+    onError: (e) => setErro(getApiErrorMessage(e, "Não foi possível desativar.")),
+  });
+
+  const copiarCodigos = async () => {
+    try {
+      await navigator.clipboard.writeText(codigosRecuperacao.join("\n"));
+      toast.success("Códigos copiados");
+    } catch {
+      toast.error("Não foi possível copiar; anote os códigos manualmente.");
+    }
+  };
+
+  const st = status.data;
+
+  return (
+    <Card className="w-full max-w-lg">
+      <CardHeader>
+        <CardTitle className="flex items-center gap-2">
+          {st?.ativo ? (
+            <ShieldCheck className="h-5 w-5 text-green-600" aria-hidden />
+          ) : (
+            <ShieldAlert className="h-5 w-5 text-amber-600" aria-hidden />
+          )}
+          Autenticação em dois fatores
+        </CardTitle>
+        <CardDescription>
+          Além da senha, o login pede um código de 6 dígitos gerado por um app autenticador (Google
+          Authenticator, Microsoft Authenticator, 1Password…).
+        </CardDescription>
+      </CardHeader>
+      <CardContent className="space-y-4">
+        {erro ? <FormValidationAlert message={erro} isValidation={false} /> : null}
+
+        {status.isLoading ? <p className="text-sm text-muted-foreground">Carregando…</p> : null}
+        {status.isError ? (
+          <FormValidationAlert
+            message={getApiErrorMessage(status.error, "Não foi possível carregar o estado do 2FA.")}
+            isValidation={false}
+          />
+        ) : null}
+
+        {st && etapa === "status" ? (
+          <div className="space-y-3 text-sm">
+            {st.ativo ? (
+              <>
+                <p>
+                  Ativa desde {st.ativado_em ? new Date(st.ativado_em).toLocaleDateString("pt-BR") : "—"}.{" "}
+                  {st.codigos_recuperacao_restantes} código(s) de recuperação disponíveis.
+                </p>
+                <div className="flex flex-col gap-2 sm:flex-row">
+                  <Button type="button" variant="outline" onClick={() => irPara("regenerar")}>
+                    Gerar novos códigos de recuperação
+                  </Button>
+                  {!st.obrigatorio ? (
+                    <Button type="button" variant="ghost" onClick={() => irPara("desativar")}>
+                      Desativar
+                    </Button>
+                  ) : null}
+                </div>
+                {st.obrigatorio ? (
+                  <p className="text-muted-foreground">O seu perfil exige autenticação em dois fatores.</p>
+                ) : null}
+              </>
+            ) : (
+              <>
+                {st.obrigatorio ? (
+                  <p className="rounded-md border border-amber-500/40 bg-amber-500/10 p-3">
+                    O seu perfil exige autenticação em dois fatores. Configure-a para continuar a usar o sistema.
+                  </p>
+                ) : (
+                  <p className="text-muted-foreground">Recomendado para contas com acesso administrativo.</p>
+                )}
+                <Button type="button" onClick={() => irPara("senha")}>
+                  Configurar
+                </Button>
+              </>
+            )}
+          </div>
+        ) : null}
+
+        {etapa === "senha" || etapa === "desativar" ? (
+          <form
+            className="space-y-3"
+            onSubmit={(e) => {
+              e.preventDefault();
+              setErro(null);
+              if (etapa === "senha") iniciar.mutate();
+              else desativar.mutate();
+            }}
+          >
+            <div className="space-y-2">
+              <Label htmlFor="dois-fatores-senha">Senha atual</Label>
+              <Input
+                id="dois-fatores-senha"
+                type="password"
+                autoComplete="current-password"
+                value={senha}
+                onChange={(e) => setSenha(e.target.value)}
+                required
+              />
+            </div>
+            {etapa === "desativar" ? (
+              <div className="space-y-2">
+                <Label htmlFor="dois-fatores-codigo-desativar">Código do autenticador ou de recuperação</Label>
+                <Input
+                  id="dois-fatores-codigo-desativar"
+                  autoComplete="one-time-code"
+                  value={codigo}
+                  onChange={(e) => setCodigo(e.target.value)}
+                  required
+                />
+              </div>
+            ) : null}
+            <div className="flex gap-2">
+              <Button type="submit" disabled={iniciar.isPending || desativar.isPending}>
+                {etapa === "senha" ? "Continuar" : "Desativar"}
+              </Button>
+              <Button type="button" variant="ghost" onClick={() => irPara("status")}>
+                Cancelar
+              </Button>
+            </div>
+          </form>
+        ) : null}
+
+        {etapa === "inscricao" && inscricao ? (
+          <form
+            className="space-y-3 text-sm"
+            onSubmit={(e) => {
+              e.preventDefault();
+              setErro(null);
+              ativar.mutate();
+            }}
+          >
+            <ol className="list-decimal space-y-2 pl-5">
+              <li>
+                No telemóvel,{" "}
+                <a href={inscricao.otpauth_uri} className="underline">
+                  abra este link no app autenticador
+                </a>{" "}
+                ou adicione a conta manualmente com a chave:
+                <code className="mt-1 block break-all rounded bg-muted px-2 py-1 font-mono text-sm">
+                  {formatarSegredo(inscricao.segredo)}
+                </code>
+              </li>
+              <li>Digite o código de 6 dígitos que o app mostra.</li>
+            </ol>
+            <div className="space-y-2">
+              <Label htmlFor="dois-fatores-codigo">Código</Label>
+              <Input
+                id="dois-fatores-codigo"
+                inputMode="numeric"
+                autoComplete="one-time-code"
+                placeholder="123456"
+                value={codigo}
+                onChange={(e) => setCodigo(e.target.value)}
+                required
+              />
+            </div>
+            <div className="flex gap-2">
+              <Button type="submit" disabled={ativar.isPending}>
+                {ativar.isPending ? "Ativando…" : "Ativar"}
+              </Button>
+              <Button type="button" variant="ghost" onClick={() => irPara("status")}>
+                Cancelar
+              </Button>
+            </div>
+          </form>
+        ) : null}
+
+        {etapa === "regenerar" ? (
+          <form
+            className="space-y-3 text-sm"
+            onSubmit={(e) => {
+              e.preventDefault();
+              setErro(null);
+              regenerar.mutate();
+            }}
+          >
+            <p className="text-muted-foreground">Os códigos anteriores deixam de valer.</p>
+            <div className="space-y-2">
+              <Label htmlFor="dois-fatores-codigo-regenerar">Código do autenticador</Label>
+              <Input
+                id="dois-fatores-codigo-regenerar"
+                inputMode="numeric"
+                autoComplete="one-time-code"
+                value={codigo}
+                onChange={(e) => setCodigo(e.target.value)}
+                required
+              />
+            </div>
+            <div className="flex gap-2">
+              <Button type="submit" disabled={regenerar.isPending}>
+                Gerar
+              </Button>
+              <Button type="button" variant="ghost" onClick={() => irPara("status")}>
+                Cancelar
+              </Button>
+            </div>
+          </form>
+        ) : null}
+
+        {etapa === "codigos" ? (
+          <div className="space-y-3 text-sm">
+            <p>
+              Guarde estes códigos de recuperação num lugar seguro. Cada um entra uma única vez se perder o acesso ao
+              autenticador; <strong>eles não serão exibidos de novo</strong>.
+            </p>
+            <ul className="grid grid-cols-2 gap-2 rounded-md bg-muted p-3 font-mono">
+              {codigosRecuperacao.map((c) => (
+                <li key={c}>{c}</li>
+              ))}
+            </ul>
+            <div className="flex gap-2">
+              <Button type="button" variant="outline" onClick={() => void copiarCodigos()}>
+                Copiar
+              </Button>
+              <Button
+                type="button"
+                onClick={() => {
+                  setCodigosRecuperacao([]);
+                  irPara("status");
+                }}
+              >
+                Já guardei
+              </Button>
+            </div>
+          </div>
+        ) : null}
+      </CardContent>
+    </Card>
+  );
+}
@@ -0,0 +1,43 @@
+"use client";
+
+import { Monitor, Smartphone } from "lucide-react";
+import { Button } from "@/components/ui/button";
+import { descreverDispositivo } from "@/lib/dispositivo";
+import { formatDateTimePtBr } from "@/lib/format";
+import type { Sessao } from "@/services/sessoes";
+
+type SessaoItemProps = {
+  sessao: Sessao;
+  onEncerrar: () => void;
+  disabled?: boolean;
+};
+
+/** Linha da lista de sessões: dispositivo, IP, início e último uso (BR-ACESSO-028). */
+export function SessaoItem({ sessao, onEncerrar, disabled }: SessaoItemProps) {
+  const movel = /Android|iPhone|iPad|Mobile/.test(sessao.user_agent ?? "");
+  const Icone = movel ? Smartphone : Monitor;
+  return (
+    <li className="flex items-start gap-3 py-3">
+      <Icone className="mt-0.5 h-5 w-5 shrink-0 text-muted-foreground" aria-hidden />
+      <div className="min-w-0 flex-1 space-y-0.5 text-sm">
+        <p className="font-medium">
+          {descreverDispositivo(sessao.user_agent)}
+          {sessao.atual ? (
+            <span className="ml-2 rounded bg-primary/10 px-1.5 py-0.5 text-xs text-primary">Este dispositivo</span>
+          ) : null}
+        </p>
+        <p className="text-muted-foreground">
+          {sessao.ip ? `IP ${sessao.ip} · ` : ""}
+          Último uso {formatDateTimePtBr(sessao.ultimo_uso_em)}
+        </p>
+        <p className="text-xs text-muted-foreground">
+          Entrou em {formatDateTimePtBr(sessao.iniciada_em)}
+          {sessao.aal >= 2 ? " · com código de verificação" : ""}
+        </p>
+      </div>
+      <Button type="button" variant="ghost" size="sm" onClick={onEncerrar} disabled={disabled}>
+        {sessao.atual ? "Sair" : "Encerrar"}
+      </Button>
+    </li>
+  );
+}
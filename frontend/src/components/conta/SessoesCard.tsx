@@ -0,0 +1,85 @@
+"use client";
+
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
+import { Button } from "@/components/ui/button";
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from "@/components/ui/card";
+import { FormValidationAlert } from "@/components/ui/form-validation-alert";
+import { SessaoItem } from "@/components/conta/SessaoItem";
+import { useAuth } from "@/contexts/AuthContext";
+import { toast } from "@/hooks/use-toast";
+import { getApiErrorMessage } from "@/lib/errors";
+import { listSessoes, revogarOutrasSessoes, revogarSessao } from "@/services/sessoes";
+
+const QUERY_KEY = ["me", "sessoes"];
+
+/** Dispositivos com sessão ativa; encerrar um ou todos os outros (BR-ACESSO-028). */
+export function SessoesCard() {
+  const queryClient = useQueryClient();
+  const { logout } = useAuth();
+  const sessoes = useQuery({ queryKey: QUERY_KEY, queryFn: listSessoes });
+
+  const encerrar = useMutation({
+    mutationFn: (id: number) => revogarSessao(id),
+    onSuccess: ({ atual }) => {
+      if (atual) {
+        void logout();
+        return;
+      }
+      toast.success("Sessão encerrada", "O dispositivo terá de entrar de novo em até 15 minutos.");
+      void queryClient.invalidateQueries({ queryKey: QUERY_KEY });
+    },
+    onError: (e) => toast.error(getApiErrorMessage(e, "Não foi possível encerrar a sessão.")),
+  });
+
+  const encerrarOutras = useMutation({
+    mutationFn: revogarOutrasSessoes,
+    onSuccess: (n) => {
+      toast.success(n === 1 ? "1 sessão encerrada" : `${n} sessões encerradas`);
+      void queryClient.invalidateQueries({ queryKey: QUERY_KEY });
+    },
+    onError: (e) => toast.error(getApiErrorMessage(e, "Não foi possível encerrar as sessões.")),
+  });
+
+  const lista = sessoes.data ?? [];
+  const outras = lista.filter((s) => !s.atual).length;
+  const pendente = encerrar.isPending || encerrarOutras.isPending;
+
+  return (
+    <Card className="w-full max-w-lg">
+      <CardHeader>
+        <CardTitle>Sessões ativas</CardTitle>
+        <CardDescription>
+          Dispositivos com a sua conta aberta. Encerre os que não reconhece ou que perdeu.
+        </CardDescription>
+      </CardHeader>
+      <CardContent className="space-y-3">
+        {sessoes.isLoading ? <p className="text-sm text-muted-foreground">Carregando…</p> : null}
+        {sessoes.isError ? (
+          <FormValidationAlert
+            message={getApiErrorMessage(sessoes.error, "Não foi possível carregar as sessões.")}
+            isValidation={false}
+          />
+        ) : null}
+        {lista.length > 0 ? (
+          <ul className="divide-y">
+            {lista.map((s) => (
+              <SessaoItem key={s.id} sessao={s} onEncerrar={() => encerrar.mutate(s.id)} disabled={pendente} />
+            ))}
+          </ul>
+        ) : null}
+        {outras > 0 ? (
+          <Button
+            type="button"
+            variant="outline"
+            onClick={() => {
+              if (confirm("Encerrar as sessões de todos os outros dispositivos?")) encerrarOutras.mutate();
+            }}
+            disabled={pendente}
+          >
+            Encerrar as outras sessões
+          </Button>
+        ) : null}
+      </CardContent>
+    </Card>
+  );
+}
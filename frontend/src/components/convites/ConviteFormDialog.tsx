@@ -0,0 +1,232 @@
+"use client";
+
+import { useEffect, useState } from "react";
+import { useMutation } from "@tanstack/react-query";
+import { Check, Copy } from "lucide-react";
+import { Button } from "@/components/ui/button";
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogFooter,
+  DialogHeader,
+  DialogTitle,
+} from "@/components/ui/dialog";
+import { FormValidationAlert } from "@/components/ui/form-validation-alert";
+import { Input } from "@/components/ui/input";
+import { Label } from "@/components/ui/label";
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from "@/components/ui/select";
+import { canConvidarTitular, perfisConvidaveis } from "@/config/appAccess";
+import { getApiErrorMessage } from "@/lib/errors";
+import { getPerfilLabel } from "@/lib/perfilLabels";
+import {
+  CONVITE_VALIDADE_DIAS_MAX,
+  CONVITE_VALIDADE_DIAS_PADRAO,
+  PAPEL_CONVITE_LABELS,
+  criarConvite,
+  type ConviteCriado,
+  type PapelConvite,
+} from "@/services/convites";
+
+type Props = {
+  fazendaId: number;
+  perfilEmissor: string | undefined;
+  open: boolean;
+  onOpenChange: (open: boolean) => void;
+  onCreated: () => void;
+};
+
+function conviteLinkUrl(linkPath: string): string {
+  return typeof window === "undefined" ? linkPath : `${window.location.origin}${linkPath}`;
+}
+
+/** Emissão de convite (BR-ACESSO-010); o código e o link só aparecem logo após criar. */
+export function ConviteFormDialog({ fazendaId, perfilEmissor, open, onOpenChange, onCreated }: Props) {
+  const perfis = perfisConvidaveis(perfilEmissor);
+  const [perfil, setPerfil] = useState(perfis[0] ?? "");
+  const [papel, setPapel] = useState<PapelConvite>("OPERACIONAL");
+  const [validadeDias, setValidadeDias] = useState(String(CONVITE_VALIDADE_DIAS_PADRAO));
+  const [observacao, setObservacao] = useState("");
+  const [formError, setFormError] = useState<string | null>(null);
+  const [criado, setCriado] = useState<ConviteCriado | null>(null);
+  const [copied, setCopied] = useState(false);
+
+  useEffect(() => {
+    if (!open) return;
+    setPerfil(perfisConvidaveis(perfilEmissor)[0] ?? "");
+    setPapel("OPERACIONAL");
+    setValidadeDias(String(CONVITE_VALIDADE_DIAS_PADRAO));
+    setObservacao("");
+    setFormError(null);
+    setCriado(null);
+    setCopied(false);
+  }, [open, perfilEmissor]);
+
+  const createMutation = useMutation({
+    mutationFn: () =>
+      criarConvite(fazendaId, {
+        perfil,
+        papel,
+        validade_dias: Number(validadeDias) || CONVITE_VALIDADE_DIAS_PADRAO,
+        observacao: observacao.trim() || null,
+      }),
+    onSuccess: (res) => {
+      setCriado(res);
+      onCreated();
+    },
+    onError: (e) => setFormError(getApiErrorMessage(e, "Erro ao criar convite.")),
+  });
+
+  async function handleCopy() {
+    if (!criado) return;
+    try {
+      await navigator.clipboard.writeText(conviteLinkUrl(criado.link_path));
+      setCopied(true);
+      setTimeout(() => setCopied(false), 2000);
+    } catch {
+      /* ignore */
+    }
+  }
+
+  return (
+    <Dialog open={open} onOpenChange={onOpenChange}>
+      <DialogContent className="max-h-[90vh] max-w-lg overflow-y-auto">
+        <DialogHeader>
+          <DialogTitle>{criado ? "Convite criado" : "Novo convite"}</DialogTitle>
+          <DialogDescription className="text-base text-muted-foreground">
+            {criado
+              ? "O código só aparece agora. Envie o link ou o código a quem vai entrar na fazenda."
+              : "Quem usar o código no registro ou no login fica vinculado a esta fazenda."}
+          </DialogDescription>
+        </DialogHeader>
+        {criado ? (
+          <div className="space-y-4">
+            <div className="space-y-2">
+              <Label>Código</Label>
+              <p className="rounded-md border bg-muted/40 p-3 text-center font-mono text-lg tracking-widest">
+                {criado.codigo}
+              </p>
+            </div>
+            <div className="space-y-2">
+              <Label htmlFor="convite-link">Link de registro</Label>
+              <div className="flex gap-2">
+                <Input
+                  id="convite-link"
+                  readOnly
+                  value={conviteLinkUrl(criado.link_path)}
+                  className="font-mono text-sm"
+                />
+                <Button
+                  type="button"
+                  variant="outline"
+                  className="min-h-[44px]"
+                  onClick={handleCopy}
+                  aria-label="Copiar link"
+                >
+                  {copied ? <Check className="h-4 w-4" /> : <Copy className="h-4 w-4" />}
+                </Button>
+              </div>
+            </div>
+          </div>
+        ) : (
+          <div className="space-y-4">
+            {formError ? <FormValidationAlert message={formError} /> : null}
+            <div className="space-y-2">
+              <Label htmlFor="convite-perfil">Perfil</Label>
+              <Select value={perfil} onValueChange={setPerfil}>
+                <SelectTrigger id="convite-perfil" className="min-h-[44px]">
+                  <SelectValue placeholder="Selecione" />
+                </SelectTrigger>
+                <SelectContent>
+                  {perfis.map((p) => (
+                    <SelectItem key={p} value={p}>
+                      {getPerfilLabel(p)}
+                    </SelectItem>
+                  ))}
+                </SelectContent>
+              </Select>
+              <p className="text-sm text-muted-foreground">
+                Aplicado a contas ainda pendentes (USER); quem já tem perfil operacional mantém o seu.
+              </p>
+            </div>
+            {canConvidarTitular(perfilEmissor) ? (
+              <div className="space-y-2">
+                <Label htmlFor="convite-papel">Papel na fazenda</Label>
+                <Select value={papel} onValueChange={(v) => setPapel(v as PapelConvite)}>
+                  <SelectTrigger id="convite-papel" className="min-h-[44px]">
+                    <SelectValue />
+                  </SelectTrigger>
+                  <SelectContent>
+                    {(Object.keys(PAPEL_CONVITE_LABELS) as PapelConvite[]).map((p) => (
+                      <SelectItem key={p} value={p}>
+                        {PAPEL_CONVITE_LABELS[p]}
+                      </SelectItem>
+                    ))}
+                  </SelectContent>
+                </Select>
+              </div>
+            ) : null}
+            <div className="space-y-2">
+              <Label htmlFor="convite-validade">Validade (dias)</Label>
+              <Input
+                id="convite-validade"
+                type="number"
+                min={1}
+                max={CONVITE_VALIDADE_DIAS_MAX}
+                value={validadeDias}
+                onChange={(e) => setValidadeDias(e.target.value)}
+                className="min-h-[44px]"
+              />
+            </div>
+            <div className="space-y-2">
+              <Label htmlFor="convite-observacao">Observação</Label>
+              <Input
+                id="convite-observacao"
+                value={observacao}
+                maxLength={200}
+                placeholder="Ex.: nome de quem vai receber"
+                onChange={(e) => setObservacao(e.target.value)}
+                className="min-h-[44px]"
+              />
+            </div>
+          </div>
+        )}
+        <DialogFooter>
+          {criado ? (
+            <Button type="button" className="min-h-[44px]" onClick={() => onOpenChange(false)}>
+              Fechar
+            </Button>
+          ) : (
+            <>
+              <Button
+                type="button"
+                variant="outline"
+                className="min-h-[44px]"
+                onClick={() => onOpenChange(false)}
+              >
+                Cancelar
+              </Button>
+              <Button
+                type="button"
+                className="min-h-[44px]"
+                disabled={!perfil || createMutation.isPending}
+                onClick={() => {
+                  setFormError(null);
+                  createMutation.mutate();
+                }}
+              >
+                {createMutation.isPending ? "A criar…" : "Criar convite"}
+              </Button>
+            </>
+          )}
+        </DialogFooter>
+      </DialogContent>
+    </Dialog>
+  );
+}
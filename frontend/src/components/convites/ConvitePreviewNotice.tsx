@@ -0,0 +1,46 @@
+"use client";
+
+import { useQuery } from "@tanstack/react-query";
+import { MailOpen } from "lucide-react";
+import { getApiErrorMessage } from "@/lib/errors";
+import { formatDatePtBr } from "@/lib/format";
+import { getPerfilLabel } from "@/lib/perfilLabels";
+import { CONVITE_CODIGO_LEN, PAPEL_CONVITE_LABELS, previewConvite } from "@/services/convites";
+
+type Props = {
+  codigo: string;
+};
+
+/** Resumo do convite no registro e no login: fazenda, perfil e validade antes de aceitar. */
+export function ConvitePreviewNotice({ codigo }: Props) {
+  // Só consulta com o código completo (12 símbolos, ignorando hífens e espaços).
+  const normalizado = codigo.replace(/[^0-9a-z]/gi, "").toUpperCase();
+  const completo = normalizado.length === CONVITE_CODIGO_LEN;
+  const { data, error, isLoading } = useQuery({
+    queryKey: ["convite-preview", normalizado],
+    queryFn: () => previewConvite(normalizado),
+    enabled: completo,
+    retry: false,
+  });
+
+  if (!completo || isLoading) return null;
+
+  if (error || !data) {
+    return (
+      <p role="alert" className="rounded-md border border-destructive/40 bg-destructive/5 p-3 text-sm text-destructive">
+        {getApiErrorMessage(error, "Convite inválido, usado ou expirado.")}
+      </p>
+    );
+  }
+
+  return (
+    <div className="flex gap-3 rounded-md border border-primary/30 bg-primary/5 p-3 text-sm">
+      <MailOpen className="mt-0.5 h-4 w-4 shrink-0 text-primary" aria-hidden />
+      <p>
+        Convite para <strong>{data.fazenda_nome}</strong> como{" "}
+        <strong>{getPerfilLabel(data.perfil)}</strong> ({PAPEL_CONVITE_LABELS[data.papel] ?? data.papel}). Válido
+        até {formatDatePtBr(data.expira_em)}.
+      </p>
+    </div>
+  );
+}
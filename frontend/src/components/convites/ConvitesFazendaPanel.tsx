@@ -0,0 +1,144 @@
+"use client";
+
+import { useState } from "react";
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
+import { Plus } from "lucide-react";
+import { DeleteRecordDialog } from "@/components/layout/list/DeleteRecordDialog";
+import { Badge } from "@/components/ui/badge";
+import { Button } from "@/components/ui/button";
+import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card";
+import { EmptyState } from "@/components/ui/empty-state";
+import { toast } from "@/hooks/use-toast";
+import { getApiErrorMessage } from "@/lib/errors";
+import { formatDatePtBr } from "@/lib/format";
+import { getPerfilLabel } from "@/lib/perfilLabels";
+import {
+  PAPEL_CONVITE_LABELS,
+  STATUS_CONVITE_LABELS,
+  listConvites,
+  revogarConvite,
+  type Convite,
+} from "@/services/convites";
+import { ConviteFormDialog } from "./ConviteFormDialog";
+
+type Props = {
+  fazendaId: number;
+  perfilEmissor: string | undefined;
+};
+
+function statusVariant(status: Convite["status"]): "default" | "secondary" | "outline" {
+  if (status === "PENDENTE") return "default";
+  if (status === "USADO") return "secondary";
+  return "outline";
+}
+
+/** Convites da fazenda: emitir, acompanhar uso e revogar pendentes (BR-ACESSO-010). */
+export function ConvitesFazendaPanel({ fazendaId, perfilEmissor }: Props) {
+  const queryClient = useQueryClient();
+  const [formOpen, setFormOpen] = useState(false);
+  const [revogarAlvo, setRevogarAlvo] = useState<Convite | null>(null);
+  const [revogarError, setRevogarError] = useState("");
+
+  const queryKey = ["convites", fazendaId];
+  const { data: convites = [], isLoading, error, refetch } = useQuery({
+    queryKey,
+    queryFn: () => listConvites(fazendaId),
+    enabled: fazendaId > 0,
+  });
+
+  const revogarMutation = useMutation({
+    mutationFn: (id: number) => revogarConvite(fazendaId, id),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey });
+      toast.success("Convite revogado");
+      setRevogarAlvo(null);
+    },
+    onError: (e) => setRevogarError(getApiErrorMessage(e, "Erro ao revogar convite.")),
+  });
+
+  return (
+    <Card>
+      <CardHeader className="flex flex-row items-center justify-between space-y-0 pb-4">
+        <CardTitle className="text-lg">Convites</CardTitle>
+        <Button className="min-h-[44px]" onClick={() => setFormOpen(true)}>
+          <Plus className="mr-2 h-4 w-4" />
+          Novo convite
+        </Button>
+      </CardHeader>
+      <CardContent>
+        {isLoading ? (
+          <p className="text-muted-foreground">Carregando…</p>
+        ) : error ? (
+          <EmptyState
+            variant="error"
+            title="Não foi possível carregar os convites"
+            description={getApiErrorMessage(error, "Erro ao carregar convites.")}
+            primaryAction={{ label: "Tentar novamente", onClick: () => void refetch() }}
+          />
+        ) : convites.length === 0 ? (
+          <p className="text-sm text-muted-foreground">
+            Nenhum convite emitido. Crie um código para a equipa se registar já vinculada à fazenda.
+          </p>
+        ) : (
+          <ul className="divide-y">
+            {convites.map((c) => (
+              <li key={c.id} className="flex flex-wrap items-center justify-between gap-3 py-3">
+                <div className="min-w-0 space-y-1">
+                  <p className="flex flex-wrap items-center gap-2 font-medium">
+                    <span className="font-mono">{c.codigo_prefixo}-••••-••••</span>
+                    <Badge variant={statusVariant(c.status)}>{STATUS_CONVITE_LABELS[c.status] ?? c.status}</Badge>
+                  </p>
+                  <p className="text-sm text-muted-foreground">
+                    {getPerfilLabel(c.perfil)} · {PAPEL_CONVITE_LABELS[c.papel] ?? c.papel}
+                    {c.observacao ? ` · ${c.observacao}` : ""}
+                  </p>
+                  <p className="text-xs text-muted-foreground">
+                    {c.status === "USADO"
+                      ? `Usado por ${c.usado_por_nome ?? "—"} em ${formatDatePtBr(c.usado_em)}`
+                      : c.status === "REVOGADO"
+                        ? `Revogado em ${formatDatePtBr(c.revogado_em)}`
+                        : `Expira em ${formatDatePtBr(c.expira_em)}`}
+                    {c.criado_por_nome ? ` · criado por ${c.criado_por_nome}` : ""}
+                  </p>
+                </div>
+                {c.status === "PENDENTE" ? (
+                  <Button
+                    variant="outline"
+                    className="min-h-[44px]"
+                    onClick={() => {
+                      setRevogarError("");
+                      setRevogarAlvo(c);
+                    }}
+                  >
+                    Revogar
+                  </Button>
+                ) : null}
+              </li>
+            ))}
+          </ul>
+        )}
+      </CardContent>
+      <ConviteFormDialog
+        fazendaId={fazendaId}
+        perfilEmissor={perfilEmissor}
+        open={formOpen}
+        onOpenChange={setFormOpen}
+        onCreated={() => queryClient.invalidateQueries({ queryKey })}
+      />
+      <DeleteRecordDialog
+        open={revogarAlvo != null}
+        onOpenChange={(v) => {
+          if (!v) setRevogarAlvo(null);
+        }}
+        title="Revogar convite"
+        description="O código deixa de funcionar imediatamente. Quem já o usou mantém o vínculo."
+        confirmLabel="Revogar"
+        isPending={revogarMutation.isPending}
+        error={revogarError}
+        onConfirm={() => {
+          if (revogarAlvo) revogarMutation.mutate(revogarAlvo.id);
+        }}
+      />
+    </Card>
+  );
+}
@@ -0,0 +1,83 @@
+"use client";
+
+import { useState } from "react";
+import { useMutation } from "@tanstack/react-query";
+import { Ticket } from "lucide-react";
+import { Button } from "@/components/ui/button";
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from "@/components/ui/card";
+import { FormValidationAlert } from "@/components/ui/form-validation-alert";
+import { Input } from "@/components/ui/input";
+import { Label } from "@/components/ui/label";
+import { toast } from "@/hooks/use-toast";
+import { getApiErrorMessage } from "@/lib/errors";
+import { getPerfilLabel } from "@/lib/perfilLabels";
+import { refresh } from "@/services/auth";
+import { resgatarConvite } from "@/services/convites";
+import { ConvitePreviewNotice } from "./ConvitePreviewNotice";
+
+/**
+ * Resgate de convite por quem já tem conta (BR-ACESSO-010). Quando o perfil muda, renova a sessão
+ * para o token refletir o novo perfil e recarrega a app para recalcular áreas e fazendas.
+ */
+export function ResgatarConviteCard() {
+  const [codigo, setCodigo] = useState("");
+  const [formError, setFormError] = useState<string | null>(null);
+
+  const resgatarMutation = useMutation({
+    mutationFn: () => resgatarConvite(codigo),
+    onSuccess: async (res) => {
+      toast.success("Convite aceito", `${res.fazenda_nome} — ${getPerfilLabel(res.perfil)}`);
+      if (res.perfil_alterado) {
+        await refresh();
+      }
+      window.location.href = "/fazendas";
+    },
+    onError: (e) => setFormError(getApiErrorMessage(e, "Não foi possível aceitar o convite.")),
+  });
+
+  return (
+    <Card className="w-full text-left">
+      <CardHeader>
+        <CardTitle className="flex items-center gap-2 text-base">
+          <Ticket className="h-4 w-4 text-primary" aria-hidden />
+          Recebeu um convite?
+        </CardTitle>
+        <CardDescription>
+          Introduza o código enviado pelo administrador ou proprietário para se vincular à fazenda.
+        </CardDescription>
+      </CardHeader>
+      <CardContent>
+        <form
+          className="space-y-3"
+          onSubmit={(e) => {
+            e.preventDefault();
+            setFormError(null);
+            resgatarMutation.mutate();
+          }}
+        >
+          {formError ? <FormValidationAlert message={formError} title="Convite não aceito" /> : null}
+          <div className="space-y-2">
+            <Label htmlFor="resgatar-convite-codigo">Código de convite</Label>
+            <Input
+              id="resgatar-convite-codigo"
+              value={codigo}
+              onChange={(e) => setCodigo(e.target.value)}
+              placeholder="XXXX-XXXX-XXXX"
+              autoComplete="off"
+              autoCapitalize="characters"
+              className="min-h-[44px]"
+            />
+          </div>
+          <ConvitePreviewNotice codigo={codigo} />
+          <Button
+            type="submit"
+            className="min-h-[44px]"
+            disabled={!codigo.trim() || resgatarMutation.isPending}
+          >
+            {resgatarMutation.isPending ? "A aceitar…" : "Aceitar convite"}
+          </Button>
+        </form>
+      </CardContent>
+    </Card>
+  );
+}
@@ -3,6 +3,7 @@
 import Link from "next/link";
 import { useAuth } from "@/contexts/AuthContext";
 import { useMinhasFazendas } from "@/hooks/useMinhasFazendas";
+import { useFazendaAtiva } from "@/contexts/FazendaContext";
 import { getAreasMode, isPathAllowedForPerfil } from "@/config/appAccess";
 import { PageContainer } from "@/components/layout/PageContainer";
 import {
@@ -21,6 +22,7 @@ import {
   ClipboardList,
   Search,
   Plus,
+  Ticket,
   Users,
   type LucideIcon,
 } from "lucide-react";
@@ -55,6 +57,7 @@ type Atalho = {
 export function Dashboard() {
   const { user } = useAuth();
   const { isSingleFazenda, fazendaUnica } = useMinhasFazendas();
+  const { fazendaAtiva } = useFazendaAtiva();
   const animalSearch = useAnimalSearchDialog();
   const areasMode = getAreasMode(user?.perfil);
   const isUserPending = areasMode === "pending";
@@ -166,6 +169,16 @@ export function Dashboard() {
             description: "Registar outra exploração na sua conta",
             icon: Plus,
           },
+          ...(fazendaAtiva
+            ? [
+                {
+                  href: `/fazendas/${fazendaAtiva.id}/convites`,
+                  title: "Convidar equipa",
+                  description: "Gerar códigos de convite para a fazenda ativa",
+                  icon: Ticket,
+                },
+              ]
+            : []),
           ...baseAtalhos.slice(1),
         ]
       : baseAtalhos;
@@ -0,0 +1,216 @@
+"use client";
+
+import { Badge } from "@/components/ui/badge";
+import { Button } from "@/components/ui/button";
+import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card";
+import { Input } from "@/components/ui/input";
+import { Label } from "@/components/ui/label";
+import { DatePicker } from "@/components/ui/date-picker";
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogFooter,
+  DialogHeader,
+  DialogTitle,
+} from "@/components/ui/dialog";
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from "@/components/ui/select";
+import { FormValidationAlert } from "@/components/ui/form-validation-alert";
+import {
+  AUSENCIA_TIPO_LABEL,
+  type AusenciaTipoRegistro,
+  type FolgasAusenciasState,
+} from "@/hooks/useFolgasAusencias";
+import type { UsuarioVinculado } from "@/services/folgas";
+import { format, parseISO } from "date-fns";
+import { Plane, Trash2 } from "lucide-react";
+import { parseApiDate } from "./folgas-utils";
+
+const dataCurta = (s: string) => format(parseISO(parseApiDate(s)), "dd/MM");
+
+const TIPOS_REGISTRO: AusenciaTipoRegistro[] = ["FERIAS", "ATESTADO", "LICENCA_NAO_REMUNERADA"];
+
+type Props = {
+  ausencias: FolgasAusenciasState;
+  usuarios: UsuarioVinculado[];
+};
+
+/** Ausências do mês e saldo de férias (BR-FOLGAS-010). */
+export function FolgasAusenciasPanel({ ausencias, usuarios }: Props) {
+  const {
+    canManage,
+    ano,
+    ausenciasNoMes,
+    saldos,
+    registrarOpen,
+    setRegistrarOpen,
+    abrirRegistrar,
+    usuarioId,
+    setUsuarioId,
+    tipo,
+    setTipo,
+    dataInicio,
+    setDataInicio,
+    dataFim,
+    setDataFim,
+    anexoRef,
+    setAnexoRef,
+    observacao,
+    setObservacao,
+    formError,
+    registrarMutation,
+    excluirMutation,
+  } = ausencias;
+
+  return (
+    <>
+      <Card className="mt-6">
+        <CardHeader className="flex flex-row items-center justify-between gap-2 space-y-0 pb-2">
+          <CardTitle className="flex items-center gap-2 text-base">
+            <Plane className="h-5 w-5 shrink-0" aria-hidden />
+            Ausências no mês
+          </CardTitle>
+          {canManage && (
+            <Button variant="outline" className="min-h-[44px]" onClick={abrirRegistrar}>
+              Registrar ausência
+            </Button>
+          )}
+        </CardHeader>
+        <CardContent className="space-y-4 text-base">
+          {ausenciasNoMes.length === 0 ? (
+            <p className="text-muted-foreground">Nenhuma férias, atestado ou licença no mês.</p>
+          ) : (
+            <ul className="space-y-2">
+              {ausenciasNoMes.map((a) => (
+                <li key={a.id} className="flex flex-wrap items-center gap-2">
+                  <Badge variant="outline">{AUSENCIA_TIPO_LABEL[a.tipo]}</Badge>
+                  <span>
+                    <strong>{a.usuario_nome || `#${a.usuario_id}`}</strong> —{" "}
+                    {dataCurta(a.data_inicio)} a {dataCurta(a.data_fim)}
+                    {a.anexo_ref ? ` · anexo: ${a.anexo_ref}` : ""}
+                  </span>
+                  {canManage && (
+                    <Button
+                      variant="ghost"
+                      size="icon"
+                      className="min-h-[44px] min-w-[44px]"
+                      disabled={excluirMutation.isPending}
+                      onClick={() => excluirMutation.mutate(a.id)}
+                      aria-label={`Excluir ausência de ${a.usuario_nome || a.usuario_id}`}
+                    >
+                      <Trash2 className="h-5 w-5" />
+                    </Button>
+                  )}
+                </li>
+              ))}
+            </ul>
+          )}
+          {saldos.length > 0 && (
+            <div className="space-y-1.5">
+              <p className="font-medium">Saldo de férias em {ano}</p>
+              {saldos.map((s) => (
+                <p key={s.usuario_id} className={s.saldo < 0 ? "text-destructive" : ""}>
+                  {s.usuario_nome || `#${s.usuario_id}`}: {s.saldo} de {s.dias_direito} dia(s)
+                  {s.dias_usados > 0 ? ` (${s.dias_usados} usado(s) ou agendado(s))` : ""}
+                </p>
+              ))}
+            </div>
+          )}
+        </CardContent>
+      </Card>
+
+      <Dialog open={registrarOpen} onOpenChange={setRegistrarOpen}>
+        <DialogContent className="max-h-[90vh] max-w-lg overflow-y-auto">
+          <DialogHeader>
+            <DialogTitle>Registrar ausência</DialogTitle>
+            <DialogDescription className="text-base text-muted-foreground">
+              A geração automática não põe folga nesses dias e as folgas automáticas já geradas no
+              período são removidas.
+            </DialogDescription>
+          </DialogHeader>
+          <div className="space-y-4">
+            <div className="space-y-2">
+              <Label htmlFor="ausencia-usuario">Funcionário</Label>
+              <Select value={usuarioId} onValueChange={setUsuarioId}>
+                <SelectTrigger id="ausencia-usuario" className="min-h-[44px]">
+                  <SelectValue placeholder="Usuário" />
+                </SelectTrigger>
+                <SelectContent>
+                  {usuarios.map((u) => (
+                    <SelectItem key={u.id} value={String(u.id)}>
+                      {u.nome} ({u.email})
+                    </SelectItem>
+                  ))}
+                </SelectContent>
+              </Select>
+            </div>
+            <div className="space-y-2">
+              <Label htmlFor="ausencia-tipo">Tipo</Label>
+              <Select value={tipo} onValueChange={(v) => setTipo(v as AusenciaTipoRegistro)}>
+                <SelectTrigger id="ausencia-tipo" className="min-h-[44px]">
+                  <SelectValue />
+                </SelectTrigger>
+                <SelectContent>
+                  {TIPOS_REGISTRO.map((t) => (
+                    <SelectItem key={t} value={t}>
+                      {AUSENCIA_TIPO_LABEL[t]}
+                    </SelectItem>
+                  ))}
+                </SelectContent>
+              </Select>
+            </div>
+            <div className="grid grid-cols-2 gap-3">
+              <div className="space-y-2">
+                <Label htmlFor="ausencia-inicio">Início</Label>
+                <DatePicker id="ausencia-inicio" value={dataInicio} onChange={setDataInicio} />
+              </div>
+              <div className="space-y-2">
+                <Label htmlFor="ausencia-fim">Fim</Label>
+                <DatePicker id="ausencia-fim" value={dataFim} onChange={setDataFim} />
+              </div>
+            </div>
+            {tipo === "ATESTADO" && (
+              <div className="space-y-2">
+                <Label htmlFor="ausencia-anexo">Referência do atestado (arquivo ou protocolo)</Label>
+                <Input
+                  id="ausencia-anexo"
+                  value={anexoRef}
+                  onChange={(e) => setAnexoRef(e.target.value)}
+                  className="min-h-[44px]"
+                />
+              </div>
+            )}
+            <div className="space-y-2">
+              <Label htmlFor="ausencia-obs">Observação</Label>
+              <Input
+                id="ausencia-obs"
+                value={observacao}
+                onChange={(e) => setObservacao(e.target.value)}
+                className="min-h-[44px]"
+              />
+            </div>
+            {formError ? <FormValidationAlert message={formError} /> : null}
+          </div>
+          <DialogFooter>
+            <Button variant="outline" size="lg" onClick={() => setRegistrarOpen(false)}>
+              Cancelar
+            </Button>
+            <Button
+              size="lg"
+              disabled={!usuarioId || !dataInicio || !dataFim || registrarMutation.isPending}
+              onClick={() => registrarMutation.mutate()}
+            >
+              Registrar
+            </Button>
+          </DialogFooter>
+        </DialogContent>
+      </Dialog>
+    </>
+  );
+}
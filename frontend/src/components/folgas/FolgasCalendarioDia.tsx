@@ -11,7 +11,11 @@ import {
 import { Badge } from "@/components/ui/badge";
 import type { EscalaFolga, FolgasRodizioDia } from "@/services/folgas";
 import { buildFolgasCellTooltipText } from "./folgas-cell-tooltip";
-import { divergeRegistradoDoRodizio, labelRodizioPrevisto } from "./folgas-rodizio-utils";
+import {
+  divergeRegistradoDoRodizio,
+  labelRodizioPrevisto,
+  nomesPrevistosRodizio,
+} from "./folgas-rodizio-utils";
 import { toYMD } from "./folgas-utils";
 
 export type FolgasCalendarioDiaProps = {
@@ -76,10 +80,9 @@ export function FolgasCalendarioDia({
     canManage && divergeRegistradoDoRodizio(lista, rodizioDia ?? undefined);
   const rodizioCurto = (() => {
     if (!rodizioDia?.tem_folga) return null;
-    if (rodizioDia.usuario_nome?.trim()) {
-      return rodizioDia.usuario_nome.trim();
-    }
-    if (rodizioDia.usuario_id != null) return `#${rodizioDia.usuario_id}`;
+    const nomes = nomesPrevistosRodizio(rodizioDia);
+    if (nomes.length === 1) return nomes[0];
+    if (nomes.length > 1) return `${nomes.length} previstas`;
     return "Folga prevista";
   })();
 
@@ -0,0 +1,178 @@
+"use client";
+
+import { Button } from "@/components/ui/button";
+import { Input } from "@/components/ui/input";
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogFooter,
+  DialogHeader,
+  DialogTitle,
+} from "@/components/ui/dialog";
+import { toast } from "@/hooks/use-toast";
+import { getApiErrorMessage } from "@/lib/errors";
+import {
+  calendarioFeedUrl,
+  calendarioWebcalUrl,
+  deleteCalendarioFeed,
+  getCalendarioFeeds,
+  postCalendarioFeed,
+  type CalendarioEscopo,
+} from "@/services/calendario";
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
+import { format, parseISO } from "date-fns";
+import { Check, Copy, Trash2 } from "lucide-react";
+import { useState } from "react";
+
+type Props = {
+  open: boolean;
+  onOpenChange: (open: boolean) => void;
+  fazendaId: number;
+  canManage: boolean;
+};
+
+const ESCOPO_LABEL: Record<CalendarioEscopo, string> = {
+  PESSOAL: "Minhas folgas",
+  FAZENDA: "Escala completa",
+};
+
+/** Assinatura iCal da escala no app de calendário do celular (BR-FOLGAS-011). */
+export function FolgasCalendarioDialog({ open, onOpenChange, fazendaId, canManage }: Props) {
+  const queryClient = useQueryClient();
+  const [urlPath, setUrlPath] = useState("");
+  const [copied, setCopied] = useState(false);
+
+  const { data: feeds = [] } = useQuery({
+    queryKey: ["calendario-feeds"],
+    queryFn: getCalendarioFeeds,
+    enabled: open,
+  });
+  const feedsDaFazenda = feeds.filter((f) => f.fazenda_id === fazendaId);
+
+  const criarMutation = useMutation({
+    mutationFn: (escopo: CalendarioEscopo) => postCalendarioFeed({ fazenda_id: fazendaId, escopo }),
+    onSuccess: (r) => {
+      queryClient.invalidateQueries({ queryKey: ["calendario-feeds"] });
+      setUrlPath(r.url_path);
+      setCopied(false);
+    },
+    onError: (e) => toast.error(getApiErrorMessage(e, "Erro ao criar link do calendário.")),
+  });
+
+  const revogarMutation = useMutation({
+    mutationFn: deleteCalendarioFeed,
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ["calendario-feeds"] });
+      toast.success("Link revogado; o calendário deixa de atualizar");
+    },
+    onError: (e) => toast.error(getApiErrorMessage(e, "Erro ao revogar link.")),
+  });
+
+  /** Ao fechar, o link some: ele só é exibido logo após a criação. */
+  const handleOpenChange = (v: boolean) => {
+    if (!v) setUrlPath("");
+    onOpenChange(v);
+  };
+
+  async function handleCopy() {
+    try {
+      await navigator.clipboard.writeText(calendarioFeedUrl(urlPath));
+      setCopied(true);
+      setTimeout(() => setCopied(false), 2000);
+    } catch {
+      /* ignore */
+    }
+  }
+
+  return (
+    <Dialog open={open} onOpenChange={handleOpenChange}>
+      <DialogContent className="max-h-[90vh] max-w-lg overflow-y-auto">
+        <DialogHeader>
+          <DialogTitle>Assinar no calendário</DialogTitle>
+          <DialogDescription className="text-base text-muted-foreground">
+            Gera um link secreto para o app de calendário do celular (Google, Apple, Outlook) mostrar
+            as folgas e ausências, atualizando sozinho. Quem tiver o link vê a escala: revogue se
+            perder o aparelho.
+          </DialogDescription>
+        </DialogHeader>
+        <div className="space-y-4 text-base">
+          {urlPath ? (
+            <div className="space-y-2">
+              <p>Link criado. Ele só aparece agora: copie ou abra no calendário.</p>
+              <div className="flex gap-2">
+                <Input readOnly value={calendarioFeedUrl(urlPath)} className="font-mono text-sm" />
+                <Button
+                  type="button"
+                  variant="outline"
+                  className="min-h-[44px]"
+                  onClick={handleCopy}
+                  aria-label="Copiar link"
+                >
+                  {copied ? <Check className="h-4 w-4" /> : <Copy className="h-4 w-4" />}
+                </Button>
+              </div>
+              <Button asChild variant="secondary" className="min-h-[44px] w-full">
+                <a href={calendarioWebcalUrl(urlPath)}>Abrir no app de calendário</a>
+              </Button>
+            </div>
+          ) : (
+            <div className="grid grid-cols-1 gap-2 sm:grid-cols-2">
+              <Button
+                className="min-h-[44px]"
+                disabled={criarMutation.isPending}
+                onClick={() => criarMutation.mutate("PESSOAL")}
+              >
+                Link das minhas folgas
+              </Button>
+              {canManage && (
+                <Button
+                  variant="outline"
+                  className="min-h-[44px]"
+                  disabled={criarMutation.isPending}
+                  onClick={() => criarMutation.mutate("FAZENDA")}
+                >
+                  Link da escala completa
+                </Button>
+              )}
+            </div>
+          )}
+          {feedsDaFazenda.length > 0 && (
+            <div className="space-y-2">
+              <p className="font-medium">Links ativos nesta fazenda</p>
+              <ul className="space-y-2">
+                {feedsDaFazenda.map((f) => (
+                  <li key={f.id} className="flex items-center justify-between gap-2">
+                    <span>
+                      {ESCOPO_LABEL[f.escopo]} · <span className="font-mono">{f.token_prefixo}…</span>
+                      <span className="block text-sm text-muted-foreground">
+                        {f.ultimo_uso_em
+                          ? `Último acesso ${format(parseISO(f.ultimo_uso_em), "dd/MM/yyyy HH:mm")}`
+                          : "Ainda não acessado"}
+                      </span>
+                    </span>
+                    <Button
+                      variant="ghost"
+                      size="icon"
+                      className="min-h-[44px] min-w-[44px]"
+                      disabled={revogarMutation.isPending}
+                      onClick={() => revogarMutation.mutate(f.id)}
+                      aria-label={`Revogar link ${f.token_prefixo}`}
+                    >
+                      <Trash2 className="h-5 w-5" />
+                    </Button>
+                  </li>
+                ))}
+              </ul>
+            </div>
+          )}
+        </div>
+        <DialogFooter>
+          <Button variant="outline" size="lg" onClick={() => handleOpenChange(false)}>
+            Fechar
+          </Button>
+        </DialogFooter>
+      </DialogContent>
+    </Dialog>
+  );
+}
@@ -0,0 +1,252 @@
+"use client";
+
+import { Button } from "@/components/ui/button";
+import { Input } from "@/components/ui/input";
+import { Label } from "@/components/ui/label";
+import { DatePicker } from "@/components/ui/date-picker";
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from "@/components/ui/select";
+import type {
+  FolgasEquipePayload,
+  FolgasEscalaConfig,
+  UsuarioVinculado,
+} from "@/services/folgas";
+import { Plus, Trash2 } from "lucide-react";
+import { parseApiDate, toYMD } from "./folgas-utils";
+
+/** Estado editável de uma equipe de rodízio (campos numéricos como texto de input). */
+export type EquipeForm = {
+  id?: number;
+  nome: string;
+  dataAnchor: string;
+  cicloDias: string;
+  folgasPorCiclo: string;
+  participantes: { usuarioId: string; deslocamento: string }[];
+};
+
+export function novaEquipeForm(nome = "Rodízio 5x1"): EquipeForm {
+  return {
+    nome,
+    dataAnchor: toYMD(new Date()),
+    cicloDias: "6",
+    folgasPorCiclo: "1",
+    participantes: [],
+  };
+}
+
+export function equipesParaForm(config: FolgasEscalaConfig | null | undefined): EquipeForm[] {
+  if (!config?.equipes?.length) return [novaEquipeForm()];
+  return config.equipes.map((e) => ({
+    id: e.id,
+    nome: e.nome,
+    dataAnchor: parseApiDate(e.data_anchor),
+    cicloDias: String(e.ciclo_dias),
+    folgasPorCiclo: String(e.folgas_por_ciclo),
+    participantes: e.participantes.map((p) => ({
+      usuarioId: String(p.usuario_id),
+      deslocamento: String(p.deslocamento),
+    })),
+  }));
+}
+
+/** Payload do PUT; deslocamento vazio deixa o backend usar posição × folgas por ciclo. */
+export function formParaPayload(equipes: EquipeForm[]): FolgasEquipePayload[] {
+  return equipes.map((e) => ({
+    ...(e.id ? { id: e.id } : {}),
+    nome: e.nome.trim(),
+    data_anchor: e.dataAnchor,
+    ciclo_dias: Number(e.cicloDias),
+    folgas_por_ciclo: Number(e.folgasPorCiclo),
+    participantes: e.participantes
+      .filter((p) => p.usuarioId !== "")
+      .map((p) => ({
+        usuario_id: Number(p.usuarioId),
+        ...(p.deslocamento.trim() !== "" ? { deslocamento: Number(p.deslocamento) } : {}),
+      })),
+  }));
+}
+
+type FolgasEquipesEditorProps = {
+  equipes: EquipeForm[];
+  onChange: (equipes: EquipeForm[]) => void;
+  usuarios: UsuarioVinculado[];
+};
+
+export function FolgasEquipesEditor({ equipes, onChange, usuarios }: FolgasEquipesEditorProps) {
+  const update = (i: number, patch: Partial<EquipeForm>) =>
+    onChange(equipes.map((e, j) => (j === i ? { ...e, ...patch } : e)));
+
+  const emOutraEquipe = (i: number, usuarioId: string) =>
+    equipes.some((e, j) => j !== i && e.participantes.some((p) => p.usuarioId === usuarioId));
+
+  return (
+    <div className="space-y-6">
+      {equipes.map((eq, i) => {
+        const prefix = `folgas-eq-${i}`;
+        const ciclo = Number(eq.cicloDias) || 0;
+        const folgas = Number(eq.folgasPorCiclo) || 0;
+        return (
+          <fieldset key={eq.id ?? `nova-${i}`} className="space-y-4 rounded-md border p-3">
+            <div className="flex items-end gap-2">
+              <div className="flex-1 space-y-2">
+                <Label htmlFor={`${prefix}-nome`}>Nome da equipe</Label>
+                <Input
+                  id={`${prefix}-nome`}
+                  value={eq.nome}
+                  onChange={(e) => update(i, { nome: e.target.value })}
+                  className="min-h-[44px]"
+                />
+              </div>
+              {equipes.length > 1 && (
+                <Button
+                  type="button"
+                  variant="ghost"
+                  size="icon"
+                  className="min-h-[44px] min-w-[44px]"
+                  onClick={() => onChange(equipes.filter((_, j) => j !== i))}
+                  aria-label={`Remover equipe ${eq.nome}`}
+                >
+                  <Trash2 className="h-5 w-5" />
+                </Button>
+              )}
+            </div>
+            <div className="space-y-2">
+              <Label htmlFor={`${prefix}-anchor`}>Data âncora (1º dia do ciclo)</Label>
+              <DatePicker
+                id={`${prefix}-anchor`}
+                value={eq.dataAnchor}
+                onChange={(v) => update(i, { dataAnchor: v })}
+                placeholder="Selecione a data âncora"
+              />
+            </div>
+            <div className="grid grid-cols-2 gap-3">
+              <div className="space-y-2">
+                <Label htmlFor={`${prefix}-ciclo`}>Ciclo (dias)</Label>
+                <Input
+                  id={`${prefix}-ciclo`}
+                  type="number"
+                  min={2}
+                  max={60}
+                  value={eq.cicloDias}
+                  onChange={(e) => update(i, { cicloDias: e.target.value })}
+                  className="min-h-[44px]"
+                />
+              </div>
+              <div className="space-y-2">
+                <Label htmlFor={`${prefix}-folgas`}>Folgas por ciclo</Label>
+                <Input
+                  id={`${prefix}-folgas`}
+                  type="number"
+                  min={1}
+                  value={eq.folgasPorCiclo}
+                  onChange={(e) => update(i, { folgasPorCiclo: e.target.value })}
+                  className="min-h-[44px]"
+                />
+              </div>
+            </div>
+            {ciclo > 0 && folgas > 0 && (
+              <p className="text-base text-muted-foreground">
+                Cada participante folga {folgas} dia(s) a cada {ciclo}. Deslocamento = dia do ciclo
+                em que a folga começa (vazio: em sequência, pela ordem).
+              </p>
+            )}
+            <div className="space-y-3">
+              {eq.participantes.map((p, k) => (
+                <div key={k} className="flex items-end gap-2">
+                  <div className="flex-1 space-y-2">
+                    <Label htmlFor={`${prefix}-p-${k}`}>Participante {k + 1}</Label>
+                    <Select
+                      value={p.usuarioId}
+                      onValueChange={(v) =>
+                        update(i, {
+                          participantes: eq.participantes.map((x, m) =>
+                            m === k ? { ...x, usuarioId: v } : x
+                          ),
+                        })
+                      }
+                    >
+                      <SelectTrigger id={`${prefix}-p-${k}`} className="min-h-[44px]">
+                        <SelectValue placeholder="Usuário" />
+                      </SelectTrigger>
+                      <SelectContent>
+                        {usuarios
+                          .filter(
+                            (u) =>
+                              String(u.id) === p.usuarioId ||
+                              (!emOutraEquipe(i, String(u.id)) &&
+                                !eq.participantes.some((x) => x.usuarioId === String(u.id)))
+                          )
+                          .map((u) => (
+                            <SelectItem key={u.id} value={String(u.id)}>
+                              {u.nome} ({u.email})
+                            </SelectItem>
+                          ))}
+                      </SelectContent>
+                    </Select>
+                  </div>
+                  <div className="w-24 space-y-2">
+                    <Label htmlFor={`${prefix}-d-${k}`}>Desloc.</Label>
+                    <Input
+                      id={`${prefix}-d-${k}`}
+                      type="number"
+                      min={0}
+                      max={ciclo > 0 ? ciclo - 1 : undefined}
+                      value={p.deslocamento}
+                      onChange={(e) =>
+                        update(i, {
+                          participantes: eq.participantes.map((x, m) =>
+                            m === k ? { ...x, deslocamento: e.target.value } : x
+                          ),
+                        })
+                      }
+                      className="min-h-[44px]"
+                    />
+                  </div>
+                  <Button
+                    type="button"
+                    variant="ghost"
+                    size="icon"
+                    className="min-h-[44px] min-w-[44px]"
+                    onClick={() =>
+                      update(i, { participantes: eq.participantes.filter((_, m) => m !== k) })
+                    }
+                    aria-label={`Remover participante ${k + 1}`}
+                  >
+                    <Trash2 className="h-5 w-5" />
+                  </Button>
+                </div>
+              ))}
+              <Button
+                type="button"
+                variant="outline"
+                className="min-h-[44px]"
+                onClick={() =>
+                  update(i, {
+                    participantes: [...eq.participantes, { usuarioId: "", deslocamento: "" }],
+                  })
+                }
+              >
+                <Plus className="mr-2 h-4 w-4" />
+                Adicionar participante
+              </Button>
+            </div>
+          </fieldset>
+        );
+      })}
+      <Button
+        type="button"
+        variant="secondary"
+        className="min-h-[44px] w-full"
+        onClick={() => onChange([...equipes, novaEquipeForm(`Equipe ${equipes.length + 1}`)])}
+      >
+        <Plus className="mr-2 h-4 w-4" />
+        Adicionar equipe
+      </Button>
+    </div>
+  );
+}
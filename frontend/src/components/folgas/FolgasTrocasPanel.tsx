@@ -0,0 +1,237 @@
+"use client";
+
+import { Badge } from "@/components/ui/badge";
+import { Button } from "@/components/ui/button";
+import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card";
+import { Input } from "@/components/ui/input";
+import { Label } from "@/components/ui/label";
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogFooter,
+  DialogHeader,
+  DialogTitle,
+} from "@/components/ui/dialog";
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from "@/components/ui/select";
+import { FormValidationAlert } from "@/components/ui/form-validation-alert";
+import type { FolgasTrocasState } from "@/hooks/useFolgasTrocas";
+import type { FolgaTroca } from "@/services/folgas";
+import { format, parseISO } from "date-fns";
+import { ArrowLeftRight } from "lucide-react";
+import { parseApiDate } from "./folgas-utils";
+
+const dataCurta = (s: string) => format(parseISO(parseApiDate(s)), "dd/MM/yyyy");
+
+function statusLabel(t: FolgaTroca): string {
+  return t.status === "PENDENTE_COLEGA" ? "Aguardando colega" : "Aguardando gestão";
+}
+
+type Props = { trocas: FolgasTrocasState };
+
+/** Trocas pendentes e pedido de troca (BR-FOLGAS-009). */
+export function FolgasTrocasPanel({ trocas }: Props) {
+  const {
+    userId,
+    canManage,
+    trocasPendentes,
+    colegas,
+    minhasDatas,
+    datasColega,
+    solicitarOpen,
+    setSolicitarOpen,
+    abrirSolicitar,
+    dataSolicitante,
+    setDataSolicitante,
+    colegaId,
+    setColegaId,
+    dataColega,
+    setDataColega,
+    motivo,
+    setMotivo,
+    formError,
+    solicitarMutation,
+    responderMutation,
+    decidirMutation,
+    cancelarMutation,
+  } = trocas;
+  const ocupado =
+    responderMutation.isPending || decidirMutation.isPending || cancelarMutation.isPending;
+
+  return (
+    <>
+      <Card className="mt-6">
+        <CardHeader className="flex flex-row items-center justify-between gap-2 space-y-0 pb-2">
+          <CardTitle className="flex items-center gap-2 text-base">
+            <ArrowLeftRight className="h-5 w-5 shrink-0" aria-hidden />
+            Trocas de folga
+          </CardTitle>
+          {minhasDatas.length > 0 && colegas.length > 0 && (
+            <Button variant="outline" className="min-h-[44px]" onClick={abrirSolicitar}>
+              Propor troca
+            </Button>
+          )}
+        </CardHeader>
+        <CardContent className="space-y-3 text-base">
+          {trocasPendentes.length === 0 && (
+            <p className="text-muted-foreground">Nenhuma troca pendente.</p>
+          )}
+          {trocasPendentes.map((t) => (
+            <div key={t.id} className="space-y-2 rounded-md border p-3">
+              <div className="flex flex-wrap items-center gap-2">
+                <Badge variant="outline">{statusLabel(t)}</Badge>
+                <span>
+                  <strong>{t.solicitante_nome || `#${t.solicitante_id}`}</strong> (
+                  {dataCurta(t.data_solicitante)}) ↔ <strong>{t.colega_nome || `#${t.colega_id}`}</strong>{" "}
+                  ({dataCurta(t.data_colega)})
+                </span>
+              </div>
+              <p className="text-muted-foreground">{t.motivo}</p>
+              <div className="flex flex-wrap gap-2">
+                {t.status === "PENDENTE_COLEGA" && t.colega_id === userId && (
+                  <>
+                    <Button
+                      className="min-h-[44px]"
+                      disabled={ocupado}
+                      onClick={() => responderMutation.mutate({ id: t.id, aceitar: true })}
+                    >
+                      Aceitar
+                    </Button>
+                    <Button
+                      variant="outline"
+                      className="min-h-[44px]"
+                      disabled={ocupado}
+                      onClick={() => responderMutation.mutate({ id: t.id, aceitar: false })}
+                    >
+                      Recusar
+                    </Button>
+                  </>
+                )}
+                {t.status === "PENDENTE_GESTAO" && canManage && (
+                  <>
+                    <Button
+                      className="min-h-[44px]"
+                      disabled={ocupado}
+                      onClick={() => decidirMutation.mutate({ id: t.id, aprovar: true })}
+                    >
+                      Aprovar
+                    </Button>
+                    <Button
+                      variant="outline"
+                      className="min-h-[44px]"
+                      disabled={ocupado}
+                      onClick={() => decidirMutation.mutate({ id: t.id, aprovar: false })}
+                    >
+                      Rejeitar
+                    </Button>
+                  </>
+                )}
+                {t.solicitante_id === userId && (
+                  <Button
+                    variant="ghost"
+                    className="min-h-[44px]"
+                    disabled={ocupado}
+                    onClick={() => cancelarMutation.mutate(t.id)}
+                  >
+                    Cancelar pedido
+                  </Button>
+                )}
+              </div>
+            </div>
+          ))}
+        </CardContent>
+      </Card>
+
+      <Dialog open={solicitarOpen} onOpenChange={setSolicitarOpen}>
+        <DialogContent className="max-w-lg">
+          <DialogHeader>
+            <DialogTitle>Propor troca de folga</DialogTitle>
+            <DialogDescription className="text-base text-muted-foreground">
+              Você cede uma folga sua e assume a folga do colega em outra data. O colega precisa
+              aceitar e a gestão, aprovar.
+            </DialogDescription>
+          </DialogHeader>
+          <div className="space-y-4">
+            <div className="space-y-2">
+              <Label htmlFor="troca-minha-data">Minha folga a ceder</Label>
+              <Select value={dataSolicitante} onValueChange={setDataSolicitante}>
+                <SelectTrigger id="troca-minha-data" className="min-h-[44px]">
+                  <SelectValue placeholder="Data" />
+                </SelectTrigger>
+                <SelectContent>
+                  {minhasDatas.map((d) => (
+                    <SelectItem key={d} value={d}>
+                      {dataCurta(d)}
+                    </SelectItem>
+                  ))}
+                </SelectContent>
+              </Select>
+            </div>
+            <div className="space-y-2">
+              <Label htmlFor="troca-colega">Colega</Label>
+              <Select value={colegaId} onValueChange={setColegaId}>
+                <SelectTrigger id="troca-colega" className="min-h-[44px]">
+                  <SelectValue placeholder="Colega" />
+                </SelectTrigger>
+                <SelectContent>
+                  {colegas.map((c) => (
+                    <SelectItem key={c.id} value={String(c.id)}>
+                      {c.nome}
+                    </SelectItem>
+                  ))}
+                </SelectContent>
+              </Select>
+            </div>
+            <div className="space-y-2">
+              <Label htmlFor="troca-data-colega">Folga do colega que você assume</Label>
+              <Select value={dataColega} onValueChange={setDataColega} disabled={!colegaId}>
+                <SelectTrigger id="troca-data-colega" className="min-h-[44px]">
+                  <SelectValue placeholder={colegaId && datasColega.length === 0 ? "Sem folgas no período" : "Data"} />
+                </SelectTrigger>
+                <SelectContent>
+                  {datasColega
+                    .filter((d) => d !== dataSolicitante)
+                    .map((d) => (
+                      <SelectItem key={d} value={d}>
+                        {dataCurta(d)}
+                      </SelectItem>
+                    ))}
+                </SelectContent>
+              </Select>
+            </div>
+            <div className="space-y-2">
+              <Label htmlFor="troca-motivo">Motivo</Label>
+              <Input
+                id="troca-motivo"
+                value={motivo}
+                onChange={(e) => setMotivo(e.target.value)}
+                className="min-h-[44px]"
+              />
+            </div>
+            {formError ? <FormValidationAlert message={formError} /> : null}
+          </div>
+          <DialogFooter>
+            <Button variant="outline" size="lg" onClick={() => setSolicitarOpen(false)}>
+              Cancelar
+            </Button>
+            <Button
+              size="lg"
+              disabled={
+                !dataSolicitante || !colegaId || !dataColega || !motivo.trim() || solicitarMutation.isPending
+              }
+              onClick={() => solicitarMutation.mutate()}
+            >
+              Enviar pedido
+            </Button>
+          </DialogFooter>
+        </DialogContent>
+      </Dialog>
+    </>
+  );
+}
@@ -1,13 +1,36 @@
 import type { EscalaFolga, FolgasRodizioDia } from "@/services/folgas";
 
+/** IDs com folga prevista no dia (todas as equipes, BR-FOLGAS-008). */
+export function idsPrevistosRodizio(r: FolgasRodizioDia | undefined): number[] {
+  if (!r?.tem_folga) return [];
+  if (r.previstos?.length) return r.previstos.map((p) => p.usuario_id);
+  return r.usuario_id != null ? [r.usuario_id] : [];
+}
+
+/** Nomes curtos dos previstos no dia (nome ou `#id`). */
+export function nomesPrevistosRodizio(r: FolgasRodizioDia | undefined): string[] {
+  if (!r?.tem_folga) return [];
+  if (r.previstos?.length) {
+    return r.previstos.map((p) => p.usuario_nome?.trim() || `#${p.usuario_id}`);
+  }
+  const nome = r.usuario_nome?.trim();
+  if (nome) return [nome];
+  return r.usuario_id != null ? [`#${r.usuario_id}`] : [];
+}
+
 export function labelRodizioPrevisto(r: FolgasRodizioDia | undefined): string | null {
   if (!r) return null;
-  if (!r.tem_folga) return "Rodízio 5x1: sem folga neste dia do ciclo";
-  const nome = r.usuario_nome?.trim();
-  const id = r.usuario_id;
-  if (nome) return `Rodízio 5x1: folga prevista para ${nome}`;
-  if (id != null) return `Rodízio 5x1: folga prevista para usuário #${id}`;
-  return "Rodízio 5x1: folga prevista";
+  if (!r.tem_folga) return "Rodízio: sem folga prevista neste dia do ciclo";
+  const multiEquipe = new Set((r.previstos ?? []).map((p) => p.equipe_id)).size > 1;
+  if (multiEquipe && r.previstos) {
+    const partes = r.previstos.map(
+      (p) => `${p.usuario_nome?.trim() || `usuário #${p.usuario_id}`} (${p.equipe_nome})`
+    );
+    return `Rodízio: folga prevista para ${partes.join(", ")}`;
+  }
+  const nomes = nomesPrevistosRodizio(r);
+  if (nomes.length > 0) return `Rodízio: folga prevista para ${nomes.join(", ")}`;
+  return "Rodízio: folga prevista";
 }
 
 /** Indica diferença entre o registrado na escala e o previsto pelo ciclo (informativo). */
@@ -18,12 +41,11 @@ export function divergeRegistradoDoRodizio(
   if (!r) return false;
   if (!r.tem_folga) return lista.length > 0;
   if (lista.length === 0) return true;
-  const exp = r.usuario_id;
-  if (exp == null) return lista.length > 0;
-  const ids = new Set(lista.map((e) => e.usuario_id));
-  if (lista.length === 1 && ids.size === 1) return lista[0].usuario_id !== exp;
-  if (!ids.has(exp)) return true;
-  return lista.length > 1;
+  const previstos = new Set(idsPrevistosRodizio(r));
+  if (previstos.size === 0) return lista.length > 0;
+  const registrados = new Set(lista.map((e) => e.usuario_id));
+  if (registrados.size !== previstos.size) return true;
+  return [...registrados].some((id) => !previstos.has(id));
 }
 
 /** Se, em modo substituir, gravar com esse usuario_id diverge do previsto. */
@@ -34,5 +56,5 @@ export function substituirDivergeDoRodizio(
 ): boolean {
   if (modo !== "substituir" || !r) return false;
   if (!r.tem_folga) return usuarioId > 0;
-  return r.usuario_id == null || usuarioId !== r.usuario_id;
+  return !idsPrevistosRodizio(r).includes(usuarioId);
 }
@@ -7,13 +7,21 @@ import { Header } from './Header'
 import { AssistenteFab } from './AssistenteFab'
 import { AssistenteDialog } from './AssistenteDialog'
 
+const AUTH_PAGES = new Set([
+  '/login',
+  '/registro',
+  '/esqueci-senha',
+  '/redefinir-senha',
+  '/verificar-email',
+])
+
 export function ConditionalHeader() {
   const pathname = usePathname()
   const { user } = useAuth()
   const showAssistente = showAssistenteForPerfil(user?.perfil)
 
   // Não mostrar header nas páginas de autenticação
-  if (pathname === '/login' || pathname === '/registro') {
+  if (AUTH_PAGES.has(pathname)) {
     return null
   }
 
@@ -0,0 +1,9 @@
+"use client";
+
+import { useEventosTempoReal } from "@/hooks/useEventosTempoReal";
+
+/** Montado no Providers (dentro do FazendaProvider) para ligar o stream de eventos da fazenda ativa. */
+export function EventosTempoRealSync() {
+  useEventosTempoReal();
+  return null;
+}
@@ -1,5 +1,6 @@
 "use client";
 
+import { useState } from "react";
 import Link from "next/link";
 import { useRouter } from "next/navigation";
 import { Button } from "@/components/ui/button";
@@ -20,6 +21,8 @@ import {
   userIdentityInitials,
 } from "@/components/layout/UserIdentitySummary";
 import type { IdentityUser } from "@/components/layout/UserIdentitySummary";
+import { AlterarSenhaDialog } from "@/components/conta/AlterarSenhaDialog";
+import { ContaSegurancaActions } from "@/components/conta/ContaSegurancaActions";
 import { ChevronDown, Plus } from "lucide-react";
 
 type HeaderAccountPopoverProps = {
@@ -42,109 +45,121 @@ export function HeaderAccountPopover({
   const router = useRouter();
   const showTourReset =
     user.id != null && getAreasMode(user.perfil) !== "pending";
+  const [popoverOpen, setPopoverOpen] = useState(false);
+  const [alterarSenhaOpen, setAlterarSenhaOpen] = useState(false);
 
   return (
-    <Popover>
-      <PopoverTrigger asChild>
-        <Button
-          type="button"
-          variant="outline"
-          size="sm"
-          className="inline-flex h-9 max-w-[min(240px,28vw)] shrink-0 items-center gap-2 px-2 font-normal min-w-0"
-          aria-label={userIdentityAriaLabel(user, fazendaNomeResumo)}
-          aria-haspopup="dialog"
-        >
-          <span
-            className="flex h-7 w-7 shrink-0 items-center justify-center rounded-full bg-muted text-[10px] font-semibold uppercase text-foreground"
-            aria-hidden
-          >
-            {userIdentityInitials(user)}
-          </span>
-          <span className="min-w-0 truncate text-left text-sm">
-            {user.nome?.trim() || user.email}
-          </span>
-          <ChevronDown
-            className="h-4 w-4 shrink-0 opacity-60"
-            aria-hidden
-          />
-        </Button>
-      </PopoverTrigger>
-      <PopoverContent
-        align="end"
-        sideOffset={8}
-        className="z-50 w-[min(20rem,calc(100vw-2rem))] max-h-[min(32rem,72dvh)] overflow-y-auto p-4"
-      >
-        <h2 className="sr-only">Conta e fazenda</h2>
-        <div className="flex flex-col gap-4">
-          <UserIdentitySummary
-            user={user}
-            variant="panel"
-            fazendaAtivaNome={fazendaAtivaNomePainel}
-            withAccessibleLabel={false}
-          />
-          {showFazendaSelectorBlock ? (
-            <>
-              <FazendaSelector density="drawer" />
-              {isProprietario ? (
-                <Button
-                  asChild
-                  variant="outline"
-                  size="sm"
-                  className="h-10 w-full justify-center gap-1.5"
-                >
-                  <Link href="/fazendas/criar-minha">
-                    <Plus className="h-4 w-4 shrink-0" aria-hidden />
-                    Nova fazenda
-                  </Link>
-                </Button>
-              ) : null}
-            </>
-          ) : null}
-          {showTourReset ? (
-            <>
-              <Button
-                type="button"
-                variant="ghost"
-                size="sm"
-                className="h-10 w-full justify-center text-muted-foreground"
-                onClick={() => {
-                  resetDashboardTour(user.id);
-                  router.push("/");
-                }}
-              >
-                Ver tour do início novamente
-              </Button>
-              <Button
-                type="button"
-                variant="ghost"
-                size="sm"
-                className="h-10 w-full justify-center text-muted-foreground"
-                onClick={() => {
-                  resetAnimalFichaTour(user.id);
-                  const onAnimalFicha = /^\/animais\/\d+/.test(
-                    window.location.pathname,
-                  );
-                  if (!onAnimalFicha) {
-                    router.push("/animais");
-                  }
-                }}
-              >
-                Ver tour da ficha novamente
-              </Button>
-            </>
-          ) : null}
+    <>
+      <Popover open={popoverOpen} onOpenChange={setPopoverOpen}>
+        <PopoverTrigger asChild>
           <Button
+            type="button"
             variant="outline"
             size="sm"
-            className="h-10 w-full"
-            onClick={() => {
-              void onLogout();
-            }}
+            className="inline-flex h-9 max-w-[min(240px,28vw)] shrink-0 items-center gap-2 px-2 font-normal min-w-0"
+            aria-label={userIdentityAriaLabel(user, fazendaNomeResumo)}
+            aria-haspopup="dialog"
           >
-            Sair
+            <span
+              className="flex h-7 w-7 shrink-0 items-center justify-center rounded-full bg-muted text-[10px] font-semibold uppercase text-foreground"
+              aria-hidden
+            >
+              {userIdentityInitials(user)}
+            </span>
+            <span className="min-w-0 truncate text-left text-sm">
+              {user.nome?.trim() || user.email}
+            </span>
+            <ChevronDown
+              className="h-4 w-4 shrink-0 opacity-60"
+              aria-hidden
+            />
           </Button>
-        </div>
-      </PopoverContent>
-    </Popover>
+        </PopoverTrigger>
+        <PopoverContent
+          align="end"
+          sideOffset={8}
+          className="z-50 w-[min(20rem,calc(100vw-2rem))] max-h-[min(32rem,72dvh)] overflow-y-auto p-4"
+        >
+          <h2 className="sr-only">Conta e fazenda</h2>
+          <div className="flex flex-col gap-4">
+            <UserIdentitySummary
+              user={user}
+              variant="panel"
+              fazendaAtivaNome={fazendaAtivaNomePainel}
+              withAccessibleLabel={false}
+            />
+            {showFazendaSelectorBlock ? (
+              <>
+                <FazendaSelector density="drawer" />
+                {isProprietario ? (
+                  <Button
+                    asChild
+                    variant="outline"
+                    size="sm"
+                    className="h-10 w-full justify-center gap-1.5"
+                  >
+                    <Link href="/fazendas/criar-minha">
+                      <Plus className="h-4 w-4 shrink-0" aria-hidden />
+                      Nova fazenda
+                    </Link>
+                  </Button>
+                ) : null}
+              </>
+            ) : null}
+            {showTourReset ? (
+              <>
+                <Button
+                  type="button"
+                  variant="ghost"
+                  size="sm"
+                  className="h-10 w-full justify-center text-muted-foreground"
+                  onClick={() => {
+                    resetDashboardTour(user.id);
+                    router.push("/");
+                  }}
+                >
+                  Ver tour do início novamente
+                </Button>
+                <Button
+                  type="button"
+                  variant="ghost"
+                  size="sm"
+                  className="h-10 w-full justify-center text-muted-foreground"
+                  onClick={() => {
+                    resetAnimalFichaTour(user.id);
+                    const onAnimalFicha = /^\/animais\/\d+/.test(
+                      window.location.pathname,
+                    );
+                    if (!onAnimalFicha) {
+                      router.push("/animais");
+                    }
+                  }}
+                >
+                  Ver tour da ficha novamente
+                </Button>
+              </>
+            ) : null}
+            <ContaSegurancaActions
+              onAlterarSenha={() => {
+                // O diálogo fica fora do popover: fechar o popover não o desmonta.
+                setPopoverOpen(false);
+                setAlterarSenhaOpen(true);
+              }}
+            />
+            <Button
+              variant="outline"
+              size="sm"
+              className="h-10 w-full"
+              onClick={() => {
+                void onLogout();
+              }}
+            >
+              Sair
+            </Button>
+          </div>
+        </PopoverContent>
+      </Popover>
+      <AlterarSenhaDialog open={alterarSenhaOpen} onOpenChange={setAlterarSenhaOpen} />
+    </>
   );
 }
@@ -1,5 +1,6 @@
 "use client";
 
+import { useState } from "react";
 import Link from "next/link";
 import * as DialogPrimitive from "@radix-ui/react-dialog";
 import { Button } from "@/components/ui/button";
@@ -11,6 +12,8 @@ import type { HeaderNavGroups } from "@/config/headerNav";
 import type { AppArea } from "@/config/appAccess";
 import type { IdentityUser } from "@/components/layout/UserIdentitySummary";
 import { HEADER_MOBILE_DRAWER_ID } from "@/components/layout/headerMobileDrawerIds";
+import { AlterarSenhaDialog } from "@/components/conta/AlterarSenhaDialog";
+import { ContaSegurancaActions } from "@/components/conta/ContaSegurancaActions";
 import { X, Plus } from "lucide-react";
 import type { RefObject } from "react";
 
@@ -45,130 +48,142 @@ export function HeaderMobileDrawer({
   onOpenSearch,
   onLogout,
 }: HeaderMobileDrawerProps) {
+  const [alterarSenhaOpen, setAlterarSenhaOpen] = useState(false);
   return (
-    <DialogPrimitive.Root open={open} onOpenChange={(isOpen) => { if (!isOpen) onClose(); }}>
-      <DialogPrimitive.Portal>
-        <DialogPrimitive.Overlay
-          className="fixed inset-0 bg-black/50 z-40 lg:hidden data-[state=open]:animate-in data-[state=closed]:animate-out data-[state=closed]:fade-out-0 data-[state=open]:fade-in-0 duration-300"
-        />
-        <DialogPrimitive.Content
-          id={HEADER_MOBILE_DRAWER_ID}
-          className="fixed top-0 right-0 bottom-0 w-full max-w-sm bg-card border-l shadow-lg z-50 flex flex-col lg:hidden transition-transform duration-300 ease-in-out data-[state=closed]:translate-x-full data-[state=open]:translate-x-0"
-          onCloseAutoFocus={(event) => {
-            if (menuTriggerRef?.current) {
-              event.preventDefault();
-              menuTriggerRef.current.focus();
-            }
-          }}
-        >
-          <DialogPrimitive.Description className="sr-only">
-            Navegação principal, conta, tema e sair da aplicação.
-          </DialogPrimitive.Description>
-          <div className="flex h-14 items-center justify-between px-4 border-b shrink-0">
-            <DialogPrimitive.Title className="font-semibold text-base leading-none">
-              Menu
-            </DialogPrimitive.Title>
-            <DialogPrimitive.Close asChild>
-              <Button
-                variant="ghost"
-                size="icon"
-                aria-label="Fechar menu"
-              >
-                <X className="h-5 w-5" />
-              </Button>
-            </DialogPrimitive.Close>
-          </div>
-          <nav className="flex flex-col gap-1 p-4 overflow-auto flex-1 pb-[calc(1rem+env(safe-area-inset-bottom,0px))]">
-            {user ? (
-              <section
-                className="mb-2 space-y-3 border-b border-border pb-4"
-                aria-label="Conta e fazenda"
-              >
-                <UserIdentitySummary
-                  user={user}
-                  variant="panel"
-                  fazendaAtivaNome={fazendaNomeResumo}
-                />
-                {showFazendaSelectorBlock ? (
-                  <FazendaSelector density="drawer" />
-                ) : null}
-                {isProprietario ? (
-                  <Link
-                    href="/fazendas/criar-minha"
-                    className="flex min-h-[44px] w-full items-center gap-2 rounded-md py-3 px-3 text-left text-foreground hover:bg-accent"
-                    onClick={onClose}
-                  >
-                    <Plus
-                      className="h-5 w-5 shrink-0 text-muted-foreground"
-                      aria-hidden
-                    />
-                    Nova fazenda
-                  </Link>
-                ) : null}
-              </section>
-            ) : null}
-
-            <div className="flex items-center gap-2 py-2 px-3">
-              <ThemeToggle />
-              <span className="text-sm text-muted-foreground">
-                Alternar tema
-              </span>
-            </div>
-            {showBuscaAnimal && onOpenSearch ? (
-              <button
-                type="button"
-                className="flex min-h-[44px] w-full items-center gap-2 rounded-md py-3 px-3 text-left text-foreground hover:bg-accent"
-                onClick={() => {
-                  onClose();
-                  onOpenSearch();
-                }}
-              >
-                Ir para busca no topo
-              </button>
-            ) : null}
-            {showNavLinks ? (
-              <HeaderMobileNavSections
-                groups={groups}
-                getAreaLabel={getAreaLabel}
-                onNavigate={onClose}
-              />
-            ) : null}
-            {user ? (
-              <Button
-                variant="outline"
-                size="sm"
-                className="mt-2 justify-center"
-                onClick={() => {
-                  onClose();
-                  onLogout();
-                }}
-              >
-                Sair
-              </Button>
-            ) : (
-              <div className="mt-2 flex flex-col gap-2">
+    <>
+      <DialogPrimitive.Root open={open} onOpenChange={(isOpen) => { if (!isOpen) onClose(); }}>
+        <DialogPrimitive.Portal>
+          <DialogPrimitive.Overlay
+            className="fixed inset-0 bg-black/50 z-40 lg:hidden data-[state=open]:animate-in data-[state=closed]:animate-out data-[state=closed]:fade-out-0 data-[state=open]:fade-in-0 duration-300"
+          />
+          <DialogPrimitive.Content
+            id={HEADER_MOBILE_DRAWER_ID}
+            className="fixed top-0 right-0 bottom-0 w-full max-w-sm bg-card border-l shadow-lg z-50 flex flex-col lg:hidden transition-transform duration-300 ease-in-out data-[state=closed]:translate-x-full data-[state=open]:translate-x-0"
+            onCloseAutoFocus={(event) => {
+              if (menuTriggerRef?.current) {
+                event.preventDefault();
+                menuTriggerRef.current.focus();
+              }
+            }}
+          >
+            <DialogPrimitive.Description className="sr-only">
+              Navegação principal, conta, tema e sair da aplicação.
+            </DialogPrimitive.Description>
+            <div className="flex h-14 items-center justify-between px-4 border-b shrink-0">
+              <DialogPrimitive.Title className="font-semibold text-base leading-none">
+                Menu
+              </DialogPrimitive.Title>
+              <DialogPrimitive.Close asChild>
                 <Button
-                  size="sm"
-                  className="justify-center"
-                  asChild
-                  onClick={onClose}
+                  variant="ghost"
+                  size="icon"
+                  aria-label="Fechar menu"
                 >
-                  <Link href="/registro">Criar conta</Link>
+                  <X className="h-5 w-5" />
                 </Button>
-                <Button
-                  variant="outline"
-                  size="sm"
-                  className="justify-center"
-                  asChild
-                  onClick={onClose}
+              </DialogPrimitive.Close>
+            </div>
+            <nav className="flex flex-col gap-1 p-4 overflow-auto flex-1 pb-[calc(1rem+env(safe-area-inset-bottom,0px))]">
+              {user ? (
+                <section
+                  className="mb-2 space-y-3 border-b border-border pb-4"
+                  aria-label="Conta e fazenda"
                 >
-                  <Link href="/login">Entrar</Link>
-                </Button>
+                  <UserIdentitySummary
+                    user={user}
+                    variant="panel"
+                    fazendaAtivaNome={fazendaNomeResumo}
+                  />
+                  {showFazendaSelectorBlock ? (
+                    <FazendaSelector density="drawer" />
+                  ) : null}
+                  {isProprietario ? (
+                    <Link
+                      href="/fazendas/criar-minha"
+                      className="flex min-h-[44px] w-full items-center gap-2 rounded-md py-3 px-3 text-left text-foreground hover:bg-accent"
+                      onClick={onClose}
+                    >
+                      <Plus
+                        className="h-5 w-5 shrink-0 text-muted-foreground"
+                        aria-hidden
+                      />
+                      Nova fazenda
+                    </Link>
+                  ) : null}
+                </section>
+              ) : null}
+
+              <div className="flex items-center gap-2 py-2 px-3">
+                <ThemeToggle />
+                <span className="text-sm text-muted-foreground">
+                  Alternar tema
+                </span>
               </div>
-            )}
-          </nav>
-        </DialogPrimitive.Content>
-      </DialogPrimitive.Portal>
-    </DialogPrimitive.Root>
+              {showBuscaAnimal && onOpenSearch ? (
+                <button
+                  type="button"
+                  className="flex min-h-[44px] w-full items-center gap-2 rounded-md py-3 px-3 text-left text-foreground hover:bg-accent"
+                  onClick={() => {
+                    onClose();
+                    onOpenSearch();
+                  }}
+                >
+                  Ir para busca no topo
+                </button>
+              ) : null}
+              {showNavLinks ? (
+                <HeaderMobileNavSections
+                  groups={groups}
+                  getAreaLabel={getAreaLabel}
+                  onNavigate={onClose}
+                />
+              ) : null}
+              {user ? (
+                <>
+                  <ContaSegurancaActions
+                    onAlterarSenha={() => {
+                      onClose();
+                      setAlterarSenhaOpen(true);
+                    }}
+                  />
+                  <Button
+                    variant="outline"
+                    size="sm"
+                    className="mt-2 justify-center"
+                    onClick={() => {
+                      onClose();
+                      onLogout();
+                    }}
+                  >
+                    Sair
+                  </Button>
+                </>
+              ) : (
+                <div className="mt-2 flex flex-col gap-2">
+                  <Button
+                    size="sm"
+                    className="justify-center"
+                    asChild
+                    onClick={onClose}
+                  >
+                    <Link href="/registro">Criar conta</Link>
+                  </Button>
+                  <Button
+                    variant="outline"
+                    size="sm"
+                    className="justify-center"
+                    asChild
+                    onClick={onClose}
+                  >
+                    <Link href="/login">Entrar</Link>
+                  </Button>
+                </div>
+              )}
+            </nav>
+          </DialogPrimitive.Content>
+        </DialogPrimitive.Portal>
+      </DialogPrimitive.Root>
+      <AlterarSenhaDialog open={alterarSenhaOpen} onOpenChange={setAlterarSenhaOpen} />
+    </>
   );
 }
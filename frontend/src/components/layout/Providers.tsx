@@ -8,6 +8,7 @@ import { FazendaProvider } from '@/contexts/FazendaContext'
 import { ThemeProvider } from '@/contexts/ThemeContext'
 import { RouteAccessGuard } from '@/components/layout/RouteAccessGuard'
 import { ServiceWorkerRegistration } from './ServiceWorkerRegistration'
+import { EventosTempoRealSync } from './EventosTempoRealSync'
 import { AnimalSearchDialogProvider } from '@/contexts/AnimalSearchDialogContext'
 import { Toaster } from '@/components/ui/sonner'
 
@@ -29,6 +30,7 @@ export function Providers({ children }: { children: ReactNode }) {
           <RouteAccessGuard>
             <AssistenteProvider>
               <FazendaProvider>
+                <EventosTempoRealSync />
                 <AnimalSearchDialogProvider>
                   {children}
                   <Toaster />
@@ -5,6 +5,7 @@ import {
   Droplets,
   Layers,
   ClipboardList,
+  ListChecks,
   Wheat,
   CalendarDays,
   Users,
@@ -23,6 +24,7 @@ export const AREA_ICON: Record<AppArea, LucideIcon> = {
   agricultura: Wheat,
   gestao: ClipboardList,
   folgas: CalendarDays,
+  tarefas: ListChecks,
 };
 
 export const SYSTEM_ICON: Record<HeaderNavSystemId, LucideIcon> = {
@@ -0,0 +1,67 @@
+"use client";
+
+import { useQuery } from "@tanstack/react-query";
+import { getDesempenhoFazenda } from "@/services/lotes";
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from "@/components/ui/card";
+
+type Props = {
+  fazendaId: number;
+};
+
+const fmt = (v: number) => v.toLocaleString("pt-BR", { maximumFractionDigits: 1 });
+
+/** Produção dos últimos 30 dias e indicadores atuais por lote (BR-LOTE-011/012). */
+export function DesempenhoLotesCard({ fazendaId }: Props) {
+  const { data, isLoading } = useQuery({
+    queryKey: ["lotes", "desempenho", fazendaId],
+    queryFn: () => getDesempenhoFazenda(fazendaId),
+    enabled: fazendaId > 0,
+  });
+
+  if (isLoading || !data || data.lotes.length === 0) return null;
+
+  return (
+    <Card className="mt-4">
+      <CardHeader>
+        <CardTitle>Desempenho por lote</CardTitle>
+        <CardDescription>
+          Produção de {data.de} a {data.ate}, pelo lote em que cada vaca estava na ordenha. Indicadores referem-se
+          aos animais hoje no lote.
+        </CardDescription>
+      </CardHeader>
+      <CardContent className="overflow-x-auto">
+        <table className="w-full text-sm">
+          <thead>
+            <tr className="text-muted-foreground border-b text-left">
+              <th className="py-2 pr-2">Lote</th>
+              <th className="py-2 pr-2 text-right">Litros</th>
+              <th className="py-2 pr-2 text-right">L/vaca/dia</th>
+              <th className="py-2 pr-2 text-right">DEL médio</th>
+              <th className="py-2 pr-2 text-right">Casos abertos</th>
+              <th className="py-2 text-right">Prenhes</th>
+            </tr>
+          </thead>
+          <tbody>
+            {data.lotes.map((l) => (
+              <tr key={l.lote_id ?? "sem-lote"} className="border-b">
+                <td className="py-2 pr-2">
+                  {l.lote_nome ?? "Sem lote"}
+                  {l.lote_id != null && !l.ativo && <span className="text-muted-foreground"> (inativo)</span>}
+                </td>
+                <td className="py-2 pr-2 text-right">{fmt(l.producao.litros_total)}</td>
+                <td className="py-2 pr-2 text-right">{fmt(l.producao.media_por_animal_dia)}</td>
+                <td className="py-2 pr-2 text-right">
+                  {l.indicadores.del_medio != null ? fmt(l.indicadores.del_medio) : "—"}
+                </td>
+                <td className="py-2 pr-2 text-right">{l.indicadores.casos_saude_abertos}</td>
+                <td className="py-2 text-right">
+                  {l.indicadores.prenhes}/{l.indicadores.animais}
+                </td>
+              </tr>
+            ))}
+          </tbody>
+        </table>
+      </CardContent>
+    </Card>
+  );
+}
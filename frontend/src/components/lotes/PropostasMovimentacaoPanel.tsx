@@ -0,0 +1,123 @@
+"use client";
+
+import { useState } from "react";
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
+import {
+  aprovarPropostas,
+  listPropostas,
+  rejeitarPropostas,
+  type ResultadoDecisaoPropostas,
+} from "@/services/lotes";
+import { Button } from "@/components/ui/button";
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from "@/components/ui/card";
+import { getApiErrorMessage } from "@/lib/errors";
+import { toast } from "@/hooks/use-toast";
+
+const MOTIVO_LABEL: Record<string, string> = {
+  PRE_PARTO: "Pré-parto",
+  RECRIA: "Recria",
+  ENGORDA: "Engorda",
+};
+
+type Props = {
+  fazendaId: number;
+};
+
+/** Propostas do motor de ciclo de vida (BR-LOTE-006): a gestão aprova ou rejeita em lote. */
+export function PropostasMovimentacaoPanel({ fazendaId }: Props) {
+  const queryClient = useQueryClient();
+  const [selecionadas, setSelecionadas] = useState<number[]>([]);
+
+  const { data: propostas = [], isLoading } = useQuery({
+    queryKey: ["lotes", "propostas", fazendaId],
+    queryFn: () => listPropostas(fazendaId),
+    enabled: fazendaId > 0,
+  });
+
+  const concluir = (acao: string) => (res: ResultadoDecisaoPropostas) => {
+    setSelecionadas([]);
+    queryClient.invalidateQueries({ queryKey: ["lotes", "propostas", fazendaId] });
+    queryClient.invalidateQueries({ queryKey: ["animais"] });
+    if (res.falhas.length > 0) {
+      toast.error(`${res.processadas} ${acao}; ${res.falhas.length} com erro: ${res.falhas[0].erro}`);
+    } else {
+      toast.success(`${res.processadas} proposta(s) ${acao}`);
+    }
+    res.avisos?.forEach((aviso) => toast.warning(aviso));
+  };
+
+  const aprovarMutation = useMutation({
+    mutationFn: (ids: number[]) => aprovarPropostas(fazendaId, ids),
+    onSuccess: concluir("aprovada(s)"),
+    onError: (err) => toast.error(getApiErrorMessage(err, "Erro ao aprovar propostas.")),
+  });
+
+  const rejeitarMutation = useMutation({
+    mutationFn: (ids: number[]) => rejeitarPropostas(fazendaId, ids),
+    onSuccess: concluir("rejeitada(s)"),
+    onError: (err) => toast.error(getApiErrorMessage(err, "Erro ao rejeitar propostas.")),
+  });
+
+  if (isLoading || propostas.length === 0) return null;
+
+  const todas = selecionadas.length === propostas.length;
+  const pendente = aprovarMutation.isPending || rejeitarMutation.isPending;
+  const toggle = (id: number) =>
+    setSelecionadas((atual) => (atual.includes(id) ? atual.filter((x) => x !== id) : [...atual, id]));
+
+  return (
+    <Card className="mb-4">
+      <CardHeader>
+        <CardTitle>Movimentações sugeridas ({propostas.length})</CardTitle>
+        <CardDescription>
+          Geradas diariamente pelo ciclo de vida do rebanho. Nenhum animal muda de lote sem aprovação.
+        </CardDescription>
+      </CardHeader>
+      <CardContent className="space-y-3">
+        <label className="flex items-center gap-2 text-sm">
+          <input
+            type="checkbox"
+            checked={todas}
+            onChange={() => setSelecionadas(todas ? [] : propostas.map((p) => p.id))}
+          />
+          Selecionar todas
+        </label>
+        <ul className="space-y-2">
+          {propostas.map((p) => (
+            <li key={p.id} className="flex items-start gap-2 border-b pb-2 text-sm">
+              <input
+                type="checkbox"
+                className="mt-1"
+                checked={selecionadas.includes(p.id)}
+                onChange={() => toggle(p.id)}
+              />
+              <div>
+                <p className="font-medium">
+                  {p.animal_identificacao}: {p.lote_origem_nome ?? "sem lote"} → {p.lote_destino_nome}
+                </p>
+                <p className="text-muted-foreground">
+                  {MOTIVO_LABEL[p.motivo] ?? p.motivo} · {p.descricao}
+                </p>
+              </div>
+            </li>
+          ))}
+        </ul>
+        <div className="flex gap-2">
+          <Button
+            disabled={selecionadas.length === 0 || pendente}
+            onClick={() => aprovarMutation.mutate(selecionadas)}
+          >
+            Aprovar selecionadas
+          </Button>
+          <Button
+            variant="outline"
+            disabled={selecionadas.length === 0 || pendente}
+            onClick={() => rejeitarMutation.mutate(selecionadas)}
+          >
+            Rejeitar
+          </Button>
+        </div>
+      </CardContent>
+    </Card>
+  );
+}
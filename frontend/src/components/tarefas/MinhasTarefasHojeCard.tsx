@@ -0,0 +1,50 @@
+"use client";
+
+import type { ReactNode } from "react";
+import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card";
+import type { MinhasTarefasHoje } from "@/services/tarefas";
+import { CalendarCheck } from "lucide-react";
+
+const MOTIVO_LABEL: Record<string, string> = {
+  FOLGA: "de folga",
+  FERIAS: "de férias",
+  ATESTADO: "com atestado",
+  LICENCA_NAO_REMUNERADA: "de licença",
+};
+
+type Props = {
+  data?: MinhasTarefasHoje;
+  isLoading: boolean;
+  /** Renderiza cada tarefa (TarefaCard com as ações da página). */
+  children: ReactNode;
+};
+
+/** "Minhas tarefas de hoje" respeitando a escala de folgas (BR-TAREFA-004). */
+export function MinhasTarefasHojeCard({ data, isLoading, children }: Props) {
+  return (
+    <Card className="mb-6">
+      <CardHeader className="pb-2">
+        <CardTitle className="flex items-center gap-2 text-base">
+          <CalendarCheck className="h-5 w-5 shrink-0" aria-hidden />
+          Minhas tarefas de hoje
+        </CardTitle>
+      </CardHeader>
+      <CardContent className="space-y-3 text-base">
+        {isLoading ? (
+          <p className="text-muted-foreground">Carregando…</p>
+        ) : !data ? null : data.de_folga ? (
+          <p className="text-muted-foreground">
+            Você está {MOTIVO_LABEL[data.motivo ?? "FOLGA"] ?? "de folga"} hoje.
+            {data.pendentes > 0
+              ? ` ${data.pendentes} tarefa(s) sua(s) segue(m) em aberto para quando voltar.`
+              : ""}
+          </p>
+        ) : data.tarefas.length === 0 ? (
+          <p className="text-muted-foreground">Nenhuma tarefa para hoje.</p>
+        ) : (
+          <ul className="space-y-3">{children}</ul>
+        )}
+      </CardContent>
+    </Card>
+  );
+}
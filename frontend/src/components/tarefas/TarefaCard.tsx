@@ -0,0 +1,144 @@
+"use client";
+
+import { Badge } from "@/components/ui/badge";
+import { Button } from "@/components/ui/button";
+import { formatDatePtBr } from "@/lib/format";
+import {
+  RECORRENCIA_TAREFA_LABELS,
+  STATUS_TAREFA_LABELS,
+  type StatusTarefa,
+  type Tarefa,
+} from "@/services/tarefas";
+import { Repeat } from "lucide-react";
+
+type Props = {
+  tarefa: Tarefa;
+  podeExecutar: boolean;
+  canManage: boolean;
+  pending?: boolean;
+  onStatus: (status: StatusTarefa) => void;
+  onChecklist: (itemId: number, feito: boolean) => void;
+  onEdit?: () => void;
+  onDelete?: () => void;
+};
+
+const STATUS_VARIANT: Record<StatusTarefa, "default" | "secondary" | "destructive" | "outline"> = {
+  ABERTA: "default",
+  EM_ANDAMENTO: "secondary",
+  CONCLUIDA: "outline",
+  CANCELADA: "outline",
+};
+
+/** Tarefa com vínculos, checklist e ações conforme BR-TAREFA-002. */
+export function TarefaCard({
+  tarefa: t,
+  podeExecutar,
+  canManage,
+  pending,
+  onStatus,
+  onChecklist,
+  onEdit,
+  onDelete,
+}: Props) {
+  const aberta = t.status === "ABERTA" || t.status === "EM_ANDAMENTO";
+  const vinculos = [
+    t.animal_identificacao && `Animal ${t.animal_identificacao}`,
+    t.lote_nome && `Lote ${t.lote_nome}`,
+    t.area_nome && `Área ${t.area_nome}`,
+    t.alerta_id && `Alerta #${t.alerta_id}`,
+  ].filter(Boolean);
+  const feitos = t.checklist.filter((i) => i.feito).length;
+
+  return (
+    <li id={`tarefa-${t.id}`} className="rounded-lg border p-4 space-y-3">
+      <div className="flex flex-wrap items-center gap-2">
+        <span className="font-medium text-base">{t.titulo}</span>
+        <Badge variant={STATUS_VARIANT[t.status]}>{STATUS_TAREFA_LABELS[t.status]}</Badge>
+        {aberta && t.atrasada && <Badge variant="destructive">Atrasada</Badge>}
+        {aberta && t.responsavel_de_folga && (
+          <Badge variant="outline">Responsável de folga no dia</Badge>
+        )}
+        {t.recorrencia !== "NENHUMA" && (
+          <Badge variant="outline" className="gap-1">
+            <Repeat className="h-3.5 w-3.5" aria-hidden />
+            {RECORRENCIA_TAREFA_LABELS[t.recorrencia]}
+          </Badge>
+        )}
+      </div>
+      <p className="text-sm text-muted-foreground">
+        {formatDatePtBr(t.data_prevista)} · {t.responsavel_nome || "Sem responsável"}
+        {vinculos.length > 0 ? ` · ${vinculos.join(" · ")}` : ""}
+      </p>
+      {t.descricao && <p className="text-base whitespace-pre-line">{t.descricao}</p>}
+      {t.checklist.length > 0 && (
+        <div className="space-y-1">
+          <p className="text-sm text-muted-foreground">
+            Checklist: {feitos}/{t.checklist.length}
+          </p>
+          <ul className="space-y-1">
+            {t.checklist.map((item) => (
+              <li key={item.id}>
+                <label className="flex min-h-[44px] items-center gap-3 text-base">
+                  <input
+                    type="checkbox"
+                    className="rounded border-input min-w-[18px] min-h-[18px]"
+                    checked={item.feito}
+                    disabled={!aberta || !podeExecutar || pending}
+                    onChange={(e) => onChecklist(item.id, e.target.checked)}
+                  />
+                  <span className={item.feito ? "line-through text-muted-foreground" : ""}>
+                    {item.texto}
+                  </span>
+                </label>
+              </li>
+            ))}
+          </ul>
+        </div>
+      )}
+      {(aberta || canManage) && (
+        <div className="flex flex-wrap gap-2">
+          {podeExecutar && t.status === "ABERTA" && (
+            <Button
+              variant="outline"
+              className="min-h-[44px]"
+              disabled={pending}
+              onClick={() => onStatus("EM_ANDAMENTO")}
+            >
+              Iniciar
+            </Button>
+          )}
+          {podeExecutar && aberta && (
+            <Button className="min-h-[44px]" disabled={pending} onClick={() => onStatus("CONCLUIDA")}>
+              Concluir
+            </Button>
+          )}
+          {canManage && aberta && (
+            <>
+              <Button variant="outline" className="min-h-[44px]" disabled={pending} onClick={onEdit}>
+                Editar
+              </Button>
+              <Button
+                variant="outline"
+                className="min-h-[44px]"
+                disabled={pending}
+                onClick={() => onStatus("CANCELADA")}
+              >
+                Cancelar tarefa
+              </Button>
+            </>
+          )}
+          {canManage && (
+            <Button
+              variant="ghost"
+              className="min-h-[44px] text-destructive"
+              disabled={pending}
+              onClick={onDelete}
+            >
+              Excluir
+            </Button>
+          )}
+        </div>
+      )}
+    </li>
+  );
+}
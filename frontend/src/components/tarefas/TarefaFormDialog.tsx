@@ -0,0 +1,270 @@
+"use client";
+
+import { useEffect, useState } from "react";
+import { useMutation, useQuery } from "@tanstack/react-query";
+import { AnimalSelect } from "@/components/animais/AnimalSelect";
+import { useAnimaisOperacionalList } from "@/components/gestao/useAnimaisMap";
+import { parseApiDate, toYMD } from "@/components/folgas/folgas-utils";
+import { Button } from "@/components/ui/button";
+import { DatePicker } from "@/components/ui/date-picker";
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogFooter,
+  DialogHeader,
+  DialogTitle,
+} from "@/components/ui/dialog";
+import { FormValidationAlert } from "@/components/ui/form-validation-alert";
+import { Input } from "@/components/ui/input";
+import { Label } from "@/components/ui/label";
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from "@/components/ui/select";
+import { Textarea } from "@/components/ui/textarea";
+import { toast } from "@/hooks/use-toast";
+import { getApiErrorMessage } from "@/lib/errors";
+import { listAreasByFazenda } from "@/services/agricultura";
+import type { UsuarioVinculado } from "@/services/folgas";
+import { listByFazenda as listLotesByFazenda } from "@/services/lotes";
+import {
+  createTarefa,
+  RECORRENCIAS_TAREFA,
+  RECORRENCIA_TAREFA_LABELS,
+  updateTarefa,
+  type RecorrenciaTarefa,
+  type Tarefa,
+  type TarefaPayload,
+} from "@/services/tarefas";
+
+const NENHUM = "__nenhum__";
+
+type Props = {
+  fazendaId: number;
+  open: boolean;
+  onOpenChange: (open: boolean) => void;
+  /** null = nova tarefa. */
+  tarefa: Tarefa | null;
+  usuarios: UsuarioVinculado[];
+  onSaved: () => void;
+};
+
+const idOuNull = (v: string) => (v && v !== NENHUM ? Number(v) : null);
+const idOuVazio = (v?: number | null) => (v != null ? String(v) : "");
+
+/** Criação e edição de tarefa (BR-TAREFA-001); checklist com um item por linha. */
+export function TarefaFormDialog({ fazendaId, open, onOpenChange, tarefa, usuarios, onSaved }: Props) {
+  const [titulo, setTitulo] = useState("");
+  const [descricao, setDescricao] = useState("");
+  const [responsavelId, setResponsavelId] = useState("");
+  const [dataPrevista, setDataPrevista] = useState("");
+  const [recorrencia, setRecorrencia] = useState<RecorrenciaTarefa>("NENHUMA");
+  const [animalId, setAnimalId] = useState("");
+  const [loteId, setLoteId] = useState("");
+  const [areaId, setAreaId] = useState("");
+  const [checklist, setChecklist] = useState("");
+  const [formError, setFormError] = useState<string | null>(null);
+
+  const { data: animais = [] } = useAnimaisOperacionalList(open ? fazendaId : undefined);
+  const { data: lotes = [] } = useQuery({
+    queryKey: ["lotes", fazendaId],
+    queryFn: () => listLotesByFazenda(fazendaId),
+    enabled: open && fazendaId > 0,
+  });
+  const { data: areas = [] } = useQuery({
+    queryKey: ["areas", fazendaId],
+    queryFn: () => listAreasByFazenda(fazendaId),
+    enabled: open && fazendaId > 0,
+  });
+
+  useEffect(() => {
+    if (!open) return;
+    setTitulo(tarefa?.titulo ?? "");
+    setDescricao(tarefa?.descricao ?? "");
+    setResponsavelId(idOuVazio(tarefa?.responsavel_id));
+    setDataPrevista(tarefa ? parseApiDate(tarefa.data_prevista) : toYMD(new Date()));
+    setRecorrencia(tarefa?.recorrencia ?? "NENHUMA");
+    setAnimalId(idOuVazio(tarefa?.animal_id));
+    setLoteId(idOuVazio(tarefa?.lote_id));
+    setAreaId(idOuVazio(tarefa?.area_id));
+    setChecklist(tarefa?.checklist.map((i) => i.texto).join("\n") ?? "");
+    setFormError(null);
+  }, [open, tarefa]);
+
+  const saveMutation = useMutation({
+    mutationFn: () => {
+      const payload: TarefaPayload = {
+        titulo: titulo.trim(),
+        descricao: descricao.trim() || null,
+        responsavel_id: idOuNull(responsavelId),
+        data_prevista: dataPrevista,
+        recorrencia,
+        animal_id: idOuNull(animalId),
+        lote_id: idOuNull(loteId),
+        area_id: idOuNull(areaId),
+        checklist: checklist
+          .split("\n")
+          .map((l) => l.trim())
+          .filter(Boolean),
+      };
+      return tarefa ? updateTarefa(fazendaId, tarefa.id, payload) : createTarefa(fazendaId, payload);
+    },
+    onSuccess: () => {
+      toast.success(tarefa ? "Tarefa atualizada" : "Tarefa criada");
+      onOpenChange(false);
+      onSaved();
+    },
+    onError: (e) => setFormError(getApiErrorMessage(e, "Erro ao salvar tarefa.")),
+  });
+
+  return (
+    <Dialog open={open} onOpenChange={onOpenChange}>
+      <DialogContent className="max-h-[90vh] max-w-lg overflow-y-auto">
+        <DialogHeader>
+          <DialogTitle>{tarefa ? "Editar tarefa" : "Nova tarefa"}</DialogTitle>
+          <DialogDescription className="text-base text-muted-foreground">
+            Tarefas recorrentes geram a próxima ocorrência quando concluídas.
+          </DialogDescription>
+        </DialogHeader>
+        <div className="space-y-4">
+          <div className="space-y-2">
+            <Label htmlFor="tarefa-titulo">Título</Label>
+            <Input
+              id="tarefa-titulo"
+              value={titulo}
+              maxLength={200}
+              onChange={(e) => setTitulo(e.target.value)}
+              className="min-h-[44px]"
+            />
+          </div>
+          <div className="space-y-2">
+            <Label htmlFor="tarefa-descricao">Descrição</Label>
+            <Textarea
+              id="tarefa-descricao"
+              value={descricao}
+              onChange={(e) => setDescricao(e.target.value)}
+              rows={3}
+            />
+          </div>
+          <div className="space-y-2">
+            <Label htmlFor="tarefa-responsavel">Responsável</Label>
+            <Select value={responsavelId || NENHUM} onValueChange={setResponsavelId}>
+              <SelectTrigger id="tarefa-responsavel" className="min-h-[44px]">
+                <SelectValue />
+              </SelectTrigger>
+              <SelectContent>
+                <SelectItem value={NENHUM}>Sem responsável</SelectItem>
+                {usuarios.map((u) => (
+                  <SelectItem key={u.id} value={String(u.id)}>
+                    {u.nome}
+                  </SelectItem>
+                ))}
+              </SelectContent>
+            </Select>
+          </div>
+          <div className="grid grid-cols-2 gap-3">
+            <div className="space-y-2">
+              <Label htmlFor="tarefa-data">Data prevista</Label>
+              <DatePicker id="tarefa-data" value={dataPrevista} onChange={setDataPrevista} />
+            </div>
+            <div className="space-y-2">
+              <Label htmlFor="tarefa-recorrencia">Repetição</Label>
+              <Select
+                value={recorrencia}
+                onValueChange={(v) => setRecorrencia(v as RecorrenciaTarefa)}
+              >
+                <SelectTrigger id="tarefa-recorrencia" className="min-h-[44px]">
+                  <SelectValue />
+                </SelectTrigger>
+                <SelectContent>
+                  {RECORRENCIAS_TAREFA.map((r) => (
+                    <SelectItem key={r} value={r}>
+                      {RECORRENCIA_TAREFA_LABELS[r]}
+                    </SelectItem>
+                  ))}
+                </SelectContent>
+              </Select>
+            </div>
+          </div>
+          <div className="space-y-2">
+            <AnimalSelect
+              id="tarefa-animal"
+              animais={animais}
+              value={animalId}
+              onValueChange={setAnimalId}
+              label="Animal (opcional)"
+              placeholder="Nenhum"
+              semDataSaida
+              preserveSelected={!!tarefa}
+            />
+            {animalId && (
+              <Button variant="ghost" className="min-h-[44px]" onClick={() => setAnimalId("")}>
+                Remover animal
+              </Button>
+            )}
+          </div>
+          <div className="grid grid-cols-2 gap-3">
+            <div className="space-y-2">
+              <Label htmlFor="tarefa-lote">Lote</Label>
+              <Select value={loteId || NENHUM} onValueChange={setLoteId}>
+                <SelectTrigger id="tarefa-lote" className="min-h-[44px]">
+                  <SelectValue />
+                </SelectTrigger>
+                <SelectContent>
+                  <SelectItem value={NENHUM}>Nenhum</SelectItem>
+                  {lotes.map((l) => (
+                    <SelectItem key={l.id} value={String(l.id)}>
+                      {l.nome}
+                    </SelectItem>
+                  ))}
+                </SelectContent>
+              </Select>
+            </div>
+            <div className="space-y-2">
+              <Label htmlFor="tarefa-area">Área</Label>
+              <Select value={areaId || NENHUM} onValueChange={setAreaId}>
+                <SelectTrigger id="tarefa-area" className="min-h-[44px]">
+                  <SelectValue />
+                </SelectTrigger>
+                <SelectContent>
+                  <SelectItem value={NENHUM}>Nenhuma</SelectItem>
+                  {areas.map((a) => (
+                    <SelectItem key={a.id} value={String(a.id)}>
+                      {a.nome}
+                    </SelectItem>
+                  ))}
+                </SelectContent>
+              </Select>
+            </div>
+          </div>
+          <div className="space-y-2">
+            <Label htmlFor="tarefa-checklist">Checklist (um item por linha)</Label>
+            <Textarea
+              id="tarefa-checklist"
+              value={checklist}
+              onChange={(e) => setChecklist(e.target.value)}
+              rows={4}
+            />
+          </div>
+          {formError ? <FormValidationAlert message={formError} /> : null}
+        </div>
+        <DialogFooter>
+          <Button variant="outline" size="lg" onClick={() => onOpenChange(false)}>
+            Cancelar
+          </Button>
+          <Button
+            size="lg"
+            disabled={!titulo.trim() || !dataPrevista || saveMutation.isPending}
+            onClick={() => saveMutation.mutate()}
+          >
+            Salvar
+          </Button>
+        </DialogFooter>
+      </DialogContent>
+    </Dialog>
+  );
+}
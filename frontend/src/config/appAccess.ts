@@ -17,12 +17,14 @@ export type AppArea =
   | "lotes"
   | "agricultura"
   | "gestao"
-  | "folgas";
+  | "folgas"
+  | "tarefas";
 
 /** Ordem dos itens no menu principal (exceto Fazendas/Admin/Dev, tratados à parte). */
 export const MAIN_NAV_AREA_ORDER: AppArea[] = [
   "animais",
   "alertas",
+  "tarefas",
   "producao",
   "lotes",
   "agricultura",
@@ -39,6 +41,7 @@ const AREA_PATH_PREFIX: Record<AppArea, string> = {
   agricultura: "/agricultura",
   gestao: "/gestao",
   folgas: "/folgas",
+  tarefas: "/tarefas",
 };
 
 export function getAreaHref(area: AppArea): string {
@@ -55,13 +58,14 @@ export const AREA_LABEL: Record<AppArea, string> = {
   agricultura: "Agricultura",
   gestao: "Gestão",
   folgas: "Folgas",
+  tarefas: "Tarefas",
 };
 
 /**
  * Perfis com lista explícita: apenas essas áreas. Ausente = acesso a todas as áreas.
  */
 export const PERFIL_AREAS: Partial<Record<string, AppArea[]>> = {
-  FUNCIONARIO: ["animais", "alertas", "gestao", "folgas"],
+  FUNCIONARIO: ["animais", "alertas", "tarefas", "gestao", "folgas"],
 };
 
 export type AreasMode = AppArea[] | "full" | "pending";
@@ -121,6 +125,7 @@ function isFuncionarioAllowedPath(path: string): boolean {
   if (/^\/producao\/\d+\/editar$/.test(path)) return true;
   if (path === "/folgas" || path.startsWith("/folgas/")) return true;
   if (path === "/alertas" || path.startsWith("/alertas/")) return true;
+  if (path === "/tarefas") return true;
   return FUNCIONARIO_GESTAO_PATHS.some(
     (base) => path === base || path.startsWith(`${base}/`)
   );
@@ -139,16 +144,23 @@ export function isSafeInternalPath(path: string | null | undefined): boolean {
   return true;
 }
 
-const PUBLIC_PATHS = new Set(["/login", "/registro"]);
+const PUBLIC_PATHS = new Set([
+  "/login",
+  "/registro",
+  "/esqueci-senha",
+  "/redefinir-senha",
+  "/verificar-email",
+]);
 
 function isPublicPath(pathname: string): boolean {
   if (PUBLIC_PATHS.has(pathname)) return true;
   return false;
 }
 
-/** Rotas autenticadas úteis fora das áreas (onboarding, seleção de fazenda). */
+/** Rotas autenticadas úteis fora das áreas (onboarding, seleção de fazenda, segurança da conta). */
 function isAuthUtilityPath(pathname: string): boolean {
   if (pathname === "/onboarding") return true;
+  if (pathname === "/conta/seguranca") return true;
   if (pathname.startsWith("/fazendas/selecionar")) return true;
   return false;
 }
@@ -295,6 +307,47 @@ export function canMarcarAlertaEmAndamento(perfil: string | undefined): boolean
   return true;
 }
 
+/**
+ * Criar, editar, cancelar e excluir tarefas; converter alerta em tarefa (BR-TAREFA-002/003).
+ * Iniciar, concluir e marcar checklist ficam com quem pode marcar alerta em andamento.
+ */
+export function canGerenciarTarefas(perfil: string | undefined): boolean {
+  return canDecidirPropostasLote(perfil);
+}
+
+/**
+ * Emitir e revogar convites da fazenda (BR-ACESSO-010). PROPRIETARIO só na fazenda em que é
+ * titular — o backend valida o vínculo.
+ */
+export function canGerenciarConvites(perfil: string | undefined): boolean {
+  return perfil === "PROPRIETARIO" || perfil === "ADMIN" || perfil === "DEVELOPER";
+}
+
+/** Perfis que o emissor pode oferecer no convite; espelha models.PerfisConvidaveis. */
+export function perfisConvidaveis(perfil: string | undefined): string[] {
+  if (perfil === "ADMIN" || perfil === "DEVELOPER") {
+    return ["FUNCIONARIO", "GERENTE", "GESTAO", "PROPRIETARIO"];
+  }
+  if (perfil === "PROPRIETARIO") return ["FUNCIONARIO", "GERENTE"];
+  return [];
+}
+
+/** Papel TITULAR no convite só para ADMIN/DEVELOPER. */
+export function canConvidarTitular(perfil: string | undefined): boolean {
+  return perfil === "ADMIN" || perfil === "DEVELOPER";
+}
+
+/** Aprovar/rejeitar propostas de movimentação de lote (BR-LOTE-006) — mesma matriz da gestão de folgas. */
+export function canDecidirPropostasLote(perfil: string | undefined): boolean {
+  return (
+    perfil === "GERENTE" ||
+    perfil === "GESTAO" ||
+    perfil === "PROPRIETARIO" ||
+    perfil === "ADMIN" ||
+    perfil === "DEVELOPER"
+  );
+}
+
 export function motivosBaixaParaPerfil(
   perfil: string | undefined
 ): MotivoSaida[] {
@@ -12,6 +12,7 @@ import {
 const PRINCIPAL_AREA_ORDER: AppArea[] = [
   "animais",
   "alertas",
+  "tarefas",
   "producao",
   "gestao",
   "folgas",
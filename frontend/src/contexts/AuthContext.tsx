@@ -11,14 +11,32 @@ import {
 } from 'react'
 import * as authService from '@/services/auth'
 
-type User = { id: number; email: string; perfil: string; nome: string }
+type User = {
+  id: number
+  email: string
+  perfil: string
+  nome: string
+  /** E-mail confirmado pelo link (BR-ACESSO-026). */
+  emailVerificado: boolean
+  /** Perfil obrigado a 2FA ainda sem segundo fator nesta sessão (BR-ACESSO-027). */
+  doisFatoresPendente: boolean
+}
+
+/**
+ * Utilizador autenticado e, quando um código foi enviado, o resultado do convite (BR-ACESSO-010).
+ * Com 2FA ativo, `desafio` vem preenchido e `user` é null até o segundo passo (BR-ACESSO-027).
+ */
+export type LoginResult = { user: User | null; desafio?: string } & authService.ConviteAuthResult
 
 type AuthContextValue = {
   user: User | null
   isAuthenticated: boolean
   isReady: boolean
-  login: (email: string, password: string) => Promise<User | null>
+  login: (email: string, password: string, convite?: string) => Promise<LoginResult>
+  loginDoisFatores: (desafio: string, codigo: string, convite?: string) => Promise<LoginResult>
   logout: () => Promise<void>
+  /** Relê a sessão (ex.: depois de confirmar o e-mail ou trocar a senha). */
+  reloadUser: () => Promise<void>
 }
 
 const AuthContext = createContext<AuthContextValue | null>(null)
@@ -28,12 +46,17 @@ function toUser(data: {
   email: string
   perfil: string
   nome?: string
+  email_verificado?: boolean
+  dois_fatores_pendente?: boolean
 }): User {
   return {
     id: data.user_id,
     email: data.email,
     perfil: data.perfil,
     nome: data.nome ?? '',
+    // Ausente em backends antigos: não mostrar aviso de confirmação.
+    emailVerificado: data.email_verificado ?? true,
+    doisFatoresPendente: data.dois_fatores_pendente ?? false,
   }
 }
 
@@ -89,14 +112,36 @@ export function AuthProvider({ children }: { children: ReactNode }) {
     return () => document.removeEventListener('visibilitychange', onVisibility)
   }, [])
 
-  const login = useCallback(async (email: string, password: string) => {
-    await authService.login(email, password)
-    const full = await authService.validate()
-    if (!full) return null
-    const next = toUser(full)
-    setUser(next)
-    return next
-  }, [])
+  const concluirLogin = useCallback(
+    async (data: authService.LoginResponse['data']): Promise<LoginResult> => {
+      const conviteResult = { convite: data.convite, convite_erro: data.convite_erro }
+      const full = await authService.validate()
+      if (!full) return { user: null, ...conviteResult }
+      const next = toUser(full)
+      setUser(next)
+      return { user: next, ...conviteResult }
+    },
+    []
+  )
+
+  const login = useCallback(
+    async (email: string, password: string, convite?: string): Promise<LoginResult> => {
+      const data = await authService.login(email, password, convite)
+      if (data.segundo_fator && data.desafio) {
+        return { user: null, desafio: data.desafio }
+      }
+      return concluirLogin(data)
+    },
+    [concluirLogin]
+  )
+
+  const loginDoisFatores = useCallback(
+    async (desafio: string, codigo: string, convite?: string): Promise<LoginResult> => {
+      const data = await authService.loginDoisFatores(desafio, codigo, convite)
+      return concluirLogin(data)
+    },
+    [concluirLogin]
+  )
 
   const logout = useCallback(async () => {
     await authService.logout()
@@ -104,12 +149,18 @@ export function AuthProvider({ children }: { children: ReactNode }) {
     window.location.href = '/login'
   }, [])
 
+  const reloadUser = useCallback(async () => {
+    await applySession()
+  }, [applySession])
+
   const value: AuthContextValue = {
     user,
     isAuthenticated: !!user,
     isReady,
     login,
+    loginDoisFatores,
     logout,
+    reloadUser,
   }
 
   return <AuthContext.Provider value={value}>{children}</AuthContext.Provider>
@@ -7,6 +7,7 @@ import { useFazendaAtiva } from "@/contexts/FazendaContext";
 import {
   canCriarAlertaManual,
   canExcluirAlerta,
+  canGerenciarTarefas,
   canMarcarAlertaEmAndamento,
   canResolverAlerta,
 } from "@/config/appAccess";
@@ -170,6 +171,7 @@ export function useAlertasPage() {
     canResolve: canResolverAlerta(perfil),
     canEmAndamento: canMarcarAlertaEmAndamento(perfil),
     canDelete: canExcluirAlerta(perfil),
+    canConverterTarefa: canGerenciarTarefas(perfil),
     statusMutation,
     onStatusChange: handleStatusChange,
     invalidate,
@@ -0,0 +1,72 @@
+"use client";
+
+import { useEffect } from "react";
+import { useQueryClient } from "@tanstack/react-query";
+import { useFazendaAtiva } from "@/contexts/FazendaContext";
+import api from "@/services/api";
+import {
+  EVENTO_ENTIDADES,
+  eventosStreamUrl,
+  queryKeysParaEvento,
+} from "@/services/eventos";
+
+/**
+ * Mantém aberto o stream SSE da fazenda ativa e invalida as queries afetadas (badge de alertas,
+ * dashboard, produção, restrições) quando outro utilizador ou o servidor altera dados.
+ * O EventSource reconecta sozinho; ao reconectar, invalida tudo para recuperar avisos perdidos.
+ * O servidor encerra o stream com `reautenticar` antes de o access token expirar: renova a sessão e
+ * reabre (o EventSource não passa pelo refresh do axios e desistiria no 401).
+ */
+export function useEventosTempoReal() {
+  const queryClient = useQueryClient();
+  const { fazendaAtiva } = useFazendaAtiva();
+  const fazendaId = fazendaAtiva?.id ?? 0;
+
+  useEffect(() => {
+    if (fazendaId <= 0 || typeof EventSource === "undefined") return;
+
+    let source: EventSource | null = null;
+    let conectouAntes = false;
+    let encerrado = false;
+
+    const abrir = () => {
+      const atual = new EventSource(eventosStreamUrl(fazendaId), {
+        withCredentials: true,
+      });
+      source = atual;
+
+      atual.addEventListener("conectado", () => {
+        if (conectouAntes) {
+          for (const entidade of EVENTO_ENTIDADES) {
+            for (const queryKey of queryKeysParaEvento(entidade)) {
+              queryClient.invalidateQueries({ queryKey });
+            }
+          }
+        }
+        conectouAntes = true;
+      });
+      atual.addEventListener("reautenticar", () => {
+        atual.close();
+        api
+          .post("/api/auth/refresh")
+          .catch(() => undefined)
+          .finally(() => {
+            if (!encerrado) abrir();
+          });
+      });
+      for (const entidade of EVENTO_ENTIDADES) {
+        atual.addEventListener(entidade, () => {
+          for (const queryKey of queryKeysParaEvento(entidade)) {
+            queryClient.invalidateQueries({ queryKey });
+          }
+        });
+      }
+    };
+    abrir();
+
+    return () => {
+      encerrado = true;
+      source?.close();
+    };
+  }, [fazendaId, queryClient]);
+}
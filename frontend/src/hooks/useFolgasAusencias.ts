@@ -0,0 +1,127 @@
+"use client";
+
+import { parseApiDate } from "@/components/folgas/folgas-utils";
+import {
+  deleteFolgasAusencia,
+  getFolgasFeriasSaldo,
+  postFolgasAusencia,
+  type FolgaAusencia,
+  type FolgaAusenciaTipo,
+} from "@/services/folgas";
+import { getApiErrorMessage } from "@/lib/errors";
+import { toast } from "@/hooks/use-toast";
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
+import { useMemo, useState } from "react";
+
+export type AusenciaTipoRegistro = Exclude<FolgaAusenciaTipo, "AUSENCIA">;
+
+export const AUSENCIA_TIPO_LABEL: Record<FolgaAusenciaTipo, string> = {
+  FERIAS: "Férias",
+  ATESTADO: "Atestado",
+  LICENCA_NAO_REMUNERADA: "Licença não remunerada",
+  AUSENCIA: "Ausente",
+};
+
+type Params = {
+  fazendaId: number | null;
+  canManage: boolean;
+  ano: number;
+  inicioMes: string;
+  fimMes: string;
+  ausencias: FolgaAusencia[];
+};
+
+/** Ausências do mês e saldo de férias (BR-FOLGAS-010); o registro é da gestão. */
+export function useFolgasAusencias({ fazendaId, canManage, ano, inicioMes, fimMes, ausencias }: Params) {
+  const queryClient = useQueryClient();
+  const [registrarOpen, setRegistrarOpen] = useState(false);
+  const [usuarioId, setUsuarioId] = useState("");
+  const [tipo, setTipo] = useState<AusenciaTipoRegistro>("FERIAS");
+  const [dataInicio, setDataInicio] = useState("");
+  const [dataFim, setDataFim] = useState("");
+  const [anexoRef, setAnexoRef] = useState("");
+  const [observacao, setObservacao] = useState("");
+  const [formError, setFormError] = useState("");
+
+  /** A escala traz a grade inteira; o painel mostra só o que toca o mês navegado. */
+  const ausenciasNoMes = useMemo(
+    () =>
+      ausencias.filter(
+        (a) => parseApiDate(a.data_inicio) <= fimMes && parseApiDate(a.data_fim) >= inicioMes
+      ),
+    [ausencias, inicioMes, fimMes]
+  );
+
+  const { data: saldos = [] } = useQuery({
+    queryKey: ["folgas", "ferias-saldo", fazendaId, ano],
+    queryFn: () => getFolgasFeriasSaldo(fazendaId!, ano),
+    enabled: !!fazendaId,
+  });
+
+  const invalidate = () => queryClient.invalidateQueries({ queryKey: ["folgas"] });
+
+  const abrirRegistrar = () => {
+    setUsuarioId("");
+    setTipo("FERIAS");
+    setDataInicio(inicioMes);
+    setDataFim(inicioMes);
+    setAnexoRef("");
+    setObservacao("");
+    setFormError("");
+    setRegistrarOpen(true);
+  };
+
+  const registrarMutation = useMutation({
+    mutationFn: () =>
+      postFolgasAusencia(fazendaId!, {
+        usuario_id: Number(usuarioId),
+        tipo,
+        data_inicio: dataInicio,
+        data_fim: dataFim,
+        anexo_ref: anexoRef.trim() || undefined,
+        observacao: observacao.trim() || undefined,
+      }),
+    onSuccess: () => {
+      invalidate();
+      setRegistrarOpen(false);
+      toast.success("Ausência registrada; folgas automáticas do período removidas");
+    },
+    onError: (e) => setFormError(getApiErrorMessage(e, "Erro ao registrar ausência.")),
+  });
+
+  const excluirMutation = useMutation({
+    mutationFn: (id: number) => deleteFolgasAusencia(fazendaId!, id),
+    onSuccess: () => {
+      invalidate();
+      toast.success("Ausência excluída; gere o mês de novo para recompor as folgas");
+    },
+    onError: (e) => toast.error(getApiErrorMessage(e, "Erro ao excluir ausência.")),
+  });
+
+  return {
+    canManage,
+    ano,
+    ausenciasNoMes,
+    saldos,
+    registrarOpen,
+    setRegistrarOpen,
+    abrirRegistrar,
+    usuarioId,
+    setUsuarioId,
+    tipo,
+    setTipo,
+    dataInicio,
+    setDataInicio,
+    dataFim,
+    setDataFim,
+    anexoRef,
+    setAnexoRef,
+    observacao,
+    setObservacao,
+    formError,
+    registrarMutation,
+    excluirMutation,
+  };
+}
+
+export type FolgasAusenciasState = ReturnType<typeof useFolgasAusencias>;
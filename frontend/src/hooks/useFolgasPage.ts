@@ -7,6 +7,11 @@ import {
   labelRodizioPrevisto,
   substituirDivergeDoRodizio,
 } from "@/components/folgas/folgas-rodizio-utils";
+import {
+  equipesParaForm,
+  formParaPayload,
+  type EquipeForm,
+} from "@/components/folgas/FolgasEquipesEditor";
 import {
   podeGerenciarFolgas,
   getFolgasConfig,
@@ -20,6 +25,7 @@ import {
   getFolgasAlteracoes,
   listUsuariosVinculados,
   type EscalaFolga,
+  type FolgaAusencia,
   type FolgasRodizioDia,
 } from "@/services/folgas";
 import { getApiErrorMessage } from "@/lib/errors";
@@ -45,6 +51,7 @@ import { useEffect, useMemo, useRef, useState } from "react";
 import { useRouter } from "next/navigation";
 
 const ESCALA_VAZIA: EscalaFolga[] = [];
+const AUSENCIAS_VAZIA: FolgaAusencia[] = [];
 
 export function useFolgasPage() {
   const { user } = useAuth();
@@ -124,6 +131,10 @@ export function useFolgasPage() {
     () => escalaPayload?.linhas ?? ESCALA_VAZIA,
     [escalaPayload]
   );
+  const ausencias = useMemo(
+    () => escalaPayload?.ausencias ?? AUSENCIAS_VAZIA,
+    [escalaPayload]
+  );
 
   const rodizioPorDiaMap = useMemo(() => {
     const m = new Map<string, FolgasRodizioDia>();
@@ -206,8 +217,11 @@ export function useFolgasPage() {
     [resumoEquidade]
   );
 
+  const equipesMultiplas = (config?.equipes?.length ?? 0) > 1;
+
   const [cfgOpen, setCfgOpen] = useState(false);
   const [gerarOpen, setGerarOpen] = useState(false);
+  const [calendarioOpen, setCalendarioOpen] = useState(false);
   const [alterOpen, setAlterOpen] = useState(false);
   const [justOpen, setJustOpen] = useState(false);
   const [diaAlter, setDiaAlter] = useState<string | null>(null);
@@ -215,16 +229,13 @@ export function useFolgasPage() {
   const [diaDetalhes, setDiaDetalhes] = useState<string | null>(null);
   const [diaDetalhesOpen, setDiaDetalhesOpen] = useState(false);
 
-  const [cfgAnchor, setCfgAnchor] = useState(toYMD(new Date()));
-  const [cfgS0, setCfgS0] = useState<string>("");
-  const [cfgS1, setCfgS1] = useState<string>("");
-  const [cfgS2, setCfgS2] = useState<string>("");
+  const [cfgEquipes, setCfgEquipes] = useState<EquipeForm[]>(() => equipesParaForm(null));
 
   const [altUsuario, setAltUsuario] = useState<string>("");
   const [altMotivo, setAltMotivo] = useState("");
   const [altModo, setAltModo] = useState<"substituir" | "adicionar">("substituir");
   const [altExcecao, setAltExcecao] = useState("");
-  /** Confirmação extra ao substituir divergindo do 5x1 previsto (só front). */
+  /** Confirmação extra ao substituir divergindo do rodízio previsto (só front). */
   const [alterRodizioStep, setAlterRodizioStep] = useState<"form" | "confirm">(
     "form"
   );
@@ -277,12 +288,7 @@ export function useFolgasPage() {
 
   const saveCfgMutation = useMutation({
     mutationFn: () =>
-      putFolgasConfig(fazendaId!, {
-        data_anchor: cfgAnchor,
-        usuario_slot_0: Number(cfgS0),
-        usuario_slot_1: Number(cfgS1),
-        usuario_slot_2: Number(cfgS2),
-      }),
+      putFolgasConfig(fazendaId!, { equipes: formParaPayload(cfgEquipes) }),
     onSuccess: () => {
       invalidateFolgas();
       setCfgOpen(false);
@@ -292,6 +298,12 @@ export function useFolgasPage() {
     onError: (e) => setFormError(getApiErrorMessage(e, "Erro ao salvar.")),
   });
 
+  const abrirConfiguracao = () => {
+    setFormError("");
+    setCfgEquipes(equipesParaForm(config));
+    setCfgOpen(true);
+  };
+
   const gerarMutation = useMutation({
     mutationFn: () => postFolgasGerar(fazendaId!, inicioMes, fimMes),
     onSuccess: () => {
@@ -448,6 +460,8 @@ export function useFolgasPage() {
     fimGrade,
     calendarioDias,
     config,
+    escala,
+    ausencias,
     loadingEscala,
     rodizioPorDiaMap,
     historico,
@@ -461,6 +475,8 @@ export function useFolgasPage() {
     setCfgOpen,
     gerarOpen,
     setGerarOpen,
+    calendarioOpen,
+    setCalendarioOpen,
     alterOpen,
     setAlterOpen,
     justOpen,
@@ -471,14 +487,10 @@ export function useFolgasPage() {
     setDiaDetalhes,
     diaDetalhesOpen,
     setDiaDetalhesOpen,
-    cfgAnchor,
-    setCfgAnchor,
-    cfgS0,
-    setCfgS0,
-    cfgS1,
-    setCfgS1,
-    cfgS2,
-    setCfgS2,
+    cfgEquipes,
+    setCfgEquipes,
+    abrirConfiguracao,
+    equipesMultiplas,
     altUsuario,
     setAltUsuario,
     altMotivo,
@@ -0,0 +1,152 @@
+"use client";
+
+import { parseApiDate, toYMD } from "@/components/folgas/folgas-utils";
+import {
+  getFolgasTrocas,
+  postFolgasTroca,
+  postFolgasTrocaCancelar,
+  postFolgasTrocaDecisao,
+  postFolgasTrocaResposta,
+  type EscalaFolga,
+  type FolgasEscalaConfig,
+} from "@/services/folgas";
+import { getApiErrorMessage } from "@/lib/errors";
+import { toast } from "@/hooks/use-toast";
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
+import { useMemo, useState } from "react";
+
+type Params = {
+  fazendaId: number | null;
+  userId: number | undefined;
+  canManage: boolean;
+  config: FolgasEscalaConfig | null | undefined;
+  escala: EscalaFolga[];
+};
+
+/** Trocas de folga entre colegas (BR-FOLGAS-009): pedido, resposta do colega e decisão da gestão. */
+export function useFolgasTrocas({ fazendaId, userId, canManage, config, escala }: Params) {
+  const queryClient = useQueryClient();
+  const [solicitarOpen, setSolicitarOpen] = useState(false);
+  const [dataSolicitante, setDataSolicitante] = useState("");
+  const [colegaId, setColegaId] = useState("");
+  const [dataColega, setDataColega] = useState("");
+  const [motivo, setMotivo] = useState("");
+  const [formError, setFormError] = useState("");
+
+  const { data: trocas = [] } = useQuery({
+    queryKey: ["folgas", "trocas", fazendaId],
+    queryFn: () => getFolgasTrocas(fazendaId!),
+    enabled: !!fazendaId,
+  });
+
+  const trocasPendentes = useMemo(
+    () => trocas.filter((t) => t.status === "PENDENTE_COLEGA" || t.status === "PENDENTE_GESTAO"),
+    [trocas]
+  );
+
+  /** Colegas: participantes das equipes de rodízio (o FUNCIONARIO não lista usuários vinculados). */
+  const colegas = useMemo(() => {
+    const m = new Map<number, string>();
+    for (const e of config?.equipes ?? []) {
+      for (const p of e.participantes) {
+        if (p.usuario_id !== userId) m.set(p.usuario_id, p.usuario_nome?.trim() || `#${p.usuario_id}`);
+      }
+    }
+    return [...m.entries()].map(([id, nome]) => ({ id, nome }));
+  }, [config, userId]);
+
+  const hoje = toYMD(new Date());
+  const datasFolga = (usuarioId: number | undefined) =>
+    [...new Set(escala.filter((e) => e.usuario_id === usuarioId).map((e) => parseApiDate(e.data)))]
+      .filter((d) => d >= hoje)
+      .sort();
+  const minhasDatas = datasFolga(userId);
+  const datasColega = colegaId ? datasFolga(Number(colegaId)) : [];
+
+  const invalidate = () => queryClient.invalidateQueries({ queryKey: ["folgas"] });
+  const onError = (fallback: string) => (e: unknown) => toast.error(getApiErrorMessage(e, fallback));
+
+  const abrirSolicitar = () => {
+    setDataSolicitante("");
+    setColegaId("");
+    setDataColega("");
+    setMotivo("");
+    setFormError("");
+    setSolicitarOpen(true);
+  };
+
+  const solicitarMutation = useMutation({
+    mutationFn: () =>
+      postFolgasTroca(fazendaId!, {
+        colega_id: Number(colegaId),
+        data_solicitante: dataSolicitante,
+        data_colega: dataColega,
+        motivo,
+      }),
+    onSuccess: () => {
+      invalidate();
+      setSolicitarOpen(false);
+      toast.success("Troca solicitada; aguardando o colega");
+    },
+    onError: (e) => setFormError(getApiErrorMessage(e, "Erro ao solicitar troca.")),
+  });
+
+  const responderMutation = useMutation({
+    mutationFn: ({ id, aceitar }: { id: number; aceitar: boolean }) =>
+      postFolgasTrocaResposta(fazendaId!, id, aceitar),
+    onSuccess: (_, { aceitar }) => {
+      invalidate();
+      toast.success(aceitar ? "Troca aceita; aguardando a gestão" : "Troca recusada");
+    },
+    onError: onError("Erro ao responder troca."),
+  });
+
+  const decidirMutation = useMutation({
+    mutationFn: ({ id, aprovar }: { id: number; aprovar: boolean }) =>
+      postFolgasTrocaDecisao(fazendaId!, id, { aprovar }),
+    onSuccess: (_, { aprovar }) => {
+      invalidate();
+      toast.success(aprovar ? "Troca aprovada e aplicada na escala" : "Troca rejeitada");
+    },
+    onError: onError("Erro ao decidir troca."),
+  });
+
+  const cancelarMutation = useMutation({
+    mutationFn: (id: number) => postFolgasTrocaCancelar(fazendaId!, id),
+    onSuccess: () => {
+      invalidate();
+      toast.success("Troca cancelada");
+    },
+    onError: onError("Erro ao cancelar troca."),
+  });
+
+  return {
+    userId,
+    canManage,
+    trocasPendentes,
+    colegas,
+    minhasDatas,
+    datasColega,
+    solicitarOpen,
+    setSolicitarOpen,
+    abrirSolicitar,
+    dataSolicitante,
+    setDataSolicitante,
+    colegaId,
+    setColegaId: (v: string) => {
+      setColegaId(v);
+      setDataColega("");
+    },
+    dataColega,
+    setDataColega,
+    motivo,
+    setMotivo,
+    formError,
+    solicitarMutation,
+    responderMutation,
+    decidirMutation,
+    cancelarMutation,
+  };
+}
+
+export type FolgasTrocasState = ReturnType<typeof useFolgasTrocas>;
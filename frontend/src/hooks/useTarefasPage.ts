@@ -0,0 +1,151 @@
+"use client";
+
+import { useState } from "react";
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
+import { useAuth } from "@/contexts/AuthContext";
+import { useFazendaAtiva } from "@/contexts/FazendaContext";
+import { canGerenciarTarefas, canMarcarAlertaEmAndamento } from "@/config/appAccess";
+import { toYMD } from "@/components/folgas/folgas-utils";
+import { toast } from "@/hooks/use-toast";
+import { getApiErrorMessage } from "@/lib/errors";
+import { listUsuariosVinculados } from "@/services/folgas";
+import {
+  deleteTarefa,
+  getMinhasTarefasHoje,
+  listTarefas,
+  marcarChecklistTarefa,
+  updateTarefaStatus,
+  type StatusTarefa,
+  type Tarefa,
+} from "@/services/tarefas";
+
+/** Filtro da lista geral: abertas (padrão), minhas abertas, sem responsável ou encerradas. */
+export type TarefasFiltro = "abertas" | "minhas" | "sem-responsavel" | "concluidas";
+
+export const TAREFAS_FILTRO_LABEL: Record<TarefasFiltro, string> = {
+  abertas: "Em aberto",
+  minhas: "Minhas",
+  "sem-responsavel": "Sem responsável",
+  concluidas: "Concluídas",
+};
+
+function filtroParams(filtro: TarefasFiltro) {
+  switch (filtro) {
+    case "minhas":
+      return { abertas: true, responsavel: "me" };
+    case "sem-responsavel":
+      return { abertas: true, responsavel: "nenhum" };
+    case "concluidas":
+      return { status: "CONCLUIDA" as const };
+    default:
+      return { abertas: true };
+  }
+}
+
+export function useTarefasPage() {
+  const { user } = useAuth();
+  const perfil = user?.perfil;
+  const { fazendaAtiva, isReady: fazendaReady } = useFazendaAtiva();
+  const fazendaId = fazendaAtiva?.id ?? 0;
+  const queryClient = useQueryClient();
+
+  const canManage = canGerenciarTarefas(perfil);
+  const canExecutar = canMarcarAlertaEmAndamento(perfil);
+  const hoje = toYMD(new Date());
+
+  const [filtro, setFiltro] = useState<TarefasFiltro>("abertas");
+  const [formOpen, setFormOpen] = useState(false);
+  const [editando, setEditando] = useState<Tarefa | null>(null);
+  const [excluirId, setExcluirId] = useState<number | null>(null);
+
+  const minhasQuery = useQuery({
+    queryKey: ["tarefas", "minhas-hoje", fazendaId, hoje],
+    queryFn: () => getMinhasTarefasHoje(fazendaId, hoje),
+    enabled: fazendaId > 0,
+  });
+
+  const listaQuery = useQuery({
+    queryKey: ["tarefas", "lista", fazendaId, filtro],
+    queryFn: () => listTarefas(fazendaId, { ...filtroParams(filtro), limit: 200 }),
+    enabled: fazendaId > 0,
+  });
+
+  const { data: usuarios = [] } = useQuery({
+    queryKey: ["folgas", "usuarios", fazendaId],
+    queryFn: () => listUsuariosVinculados(fazendaId),
+    enabled: fazendaId > 0 && canManage,
+  });
+
+  const invalidate = () => queryClient.invalidateQueries({ queryKey: ["tarefas"] });
+
+  const statusMutation = useMutation({
+    mutationFn: ({ id, status }: { id: number; status: StatusTarefa }) =>
+      updateTarefaStatus(fazendaId, id, status),
+    onSuccess: (t) => {
+      invalidate();
+      toast.success(
+        t.status === "CONCLUIDA"
+          ? t.recorrencia !== "NENHUMA"
+            ? "Tarefa concluída; a próxima já foi agendada"
+            : "Tarefa concluída"
+          : "Status da tarefa atualizado"
+      );
+    },
+    onError: (e) => toast.error(getApiErrorMessage(e, "Erro ao atualizar tarefa.")),
+  });
+
+  const checklistMutation = useMutation({
+    mutationFn: ({ tarefaId, itemId, feito }: { tarefaId: number; itemId: number; feito: boolean }) =>
+      marcarChecklistTarefa(fazendaId, tarefaId, itemId, feito),
+    onSuccess: invalidate,
+    onError: (e) => toast.error(getApiErrorMessage(e, "Erro ao marcar item.")),
+  });
+
+  const deleteMutation = useMutation({
+    mutationFn: (id: number) => deleteTarefa(fazendaId, id),
+    onSuccess: () => {
+      invalidate();
+      setExcluirId(null);
+      toast.success("Tarefa excluída");
+    },
+    onError: (e) => toast.error(getApiErrorMessage(e, "Erro ao excluir tarefa.")),
+  });
+
+  /** Responsável (ou qualquer um, se sem responsável) executa; gestão executa tudo. */
+  const podeExecutar = (t: Tarefa) =>
+    canManage || (canExecutar && (t.responsavel_id == null || t.responsavel_id === user?.id));
+
+  const abrirNova = () => {
+    setEditando(null);
+    setFormOpen(true);
+  };
+  const abrirEdicao = (t: Tarefa) => {
+    setEditando(t);
+    setFormOpen(true);
+  };
+
+  return {
+    fazendaReady,
+    fazendaAtiva,
+    fazendaId,
+    canManage,
+    podeExecutar,
+    hoje,
+    filtro,
+    setFiltro,
+    minhasQuery,
+    listaQuery,
+    usuarios,
+    statusMutation,
+    checklistMutation,
+    deleteMutation,
+    formOpen,
+    setFormOpen,
+    editando,
+    excluirId,
+    setExcluirId,
+    abrirNova,
+    abrirEdicao,
+    invalidate,
+  };
+}
@@ -0,0 +1,27 @@
+import { describe, expect, it } from "vitest";
+import { descreverDispositivo } from "@/lib/dispositivo";
+
+describe("descreverDispositivo", () => {
+  it("identifica navegador e sistema", () => {
+    expect(
+      descreverDispositivo(
+        "Mozilla/5.0 (Linux; Android 14; SM-A546B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.0.0 Mobile Safari/537.36"
+      )
+    ).toBe("Chrome · Android");
+    expect(
+      descreverDispositivo(
+        "Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Mobile/15E148 Safari/604.1"
+      )
+    ).toBe("Safari · iOS");
+    expect(
+      descreverDispositivo(
+        "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.0.0 Safari/537.36 Edg/129.0.0.0"
+      )
+    ).toBe("Edge · Windows");
+  });
+
+  it("trata user agent ausente ou desconhecido", () => {
+    expect(descreverDispositivo(undefined)).toBe("Dispositivo desconhecido");
+    expect(descreverDispositivo("curl/8.5.0")).toBe("Navegador");
+  });
+});
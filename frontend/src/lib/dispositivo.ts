@@ -0,0 +1,26 @@
+/**
+ * Resumo legível do user agent para a lista de sessões (BR-ACESSO-028), ex.: "Chrome · Android".
+ * Heurística simples: a ordem dos testes importa porque Edge e Opera também anunciam "Chrome".
+ */
+export function descreverDispositivo(userAgent?: string | null): string {
+  if (!userAgent) return "Dispositivo desconhecido";
+  const ua = userAgent;
+
+  let navegador = "Navegador";
+  if (/Edg\//.test(ua)) navegador = "Edge";
+  else if (/OPR\/|Opera/.test(ua)) navegador = "Opera";
+  else if (/SamsungBrowser\//.test(ua)) navegador = "Samsung Internet";
+  else if (/Firefox\/|FxiOS\//.test(ua)) navegador = "Firefox";
+  else if (/Chrome\/|CriOS\//.test(ua)) navegador = "Chrome";
+  else if (/Safari\//.test(ua)) navegador = "Safari";
+
+  let sistema: string | null = null;
+  if (/Android/.test(ua)) sistema = "Android";
+  else if (/iPhone|iPad|iPod/.test(ua)) sistema = "iOS";
+  else if (/Windows/.test(ua)) sistema = "Windows";
+  else if (/Mac OS X|Macintosh/.test(ua)) sistema = "macOS";
+  else if (/CrOS/.test(ua)) sistema = "ChromeOS";
+  else if (/Linux/.test(ua)) sistema = "Linux";
+
+  return sistema ? `${navegador} · ${sistema}` : navegador;
+}
@@ -599,6 +599,30 @@ export function validateRegistroForm(input: {
   return valid();
 }
 
+/** Nova senha na redefinição por e-mail e na troca autenticada (BR-ACESSO-024/026). */
+export function validateNovaSenhaForm(input: {
+  senhaAtual?: string;
+  password: string;
+  confirmPassword: string;
+}): FormValidationResult {
+  const fields: FieldErrors = {};
+  if (input.senhaAtual !== undefined && !input.senhaAtual.trim()) {
+    fields.senhaAtual = "Senha atual é obrigatória.";
+  }
+  if (!input.password.trim()) {
+    fields.senha = "Nova senha é obrigatória.";
+  } else if (input.password.length < 8) {
+    fields.senha = "A senha deve ter pelo menos 8 caracteres.";
+  } else if (input.senhaAtual && input.password === input.senhaAtual) {
+    fields.senha = "A nova senha deve ser diferente da atual.";
+  }
+  if (input.password !== input.confirmPassword) {
+    fields.confirmPassword = "As senhas não coincidem.";
+  }
+  if (Object.keys(fields).length > 0) return invalid(fields);
+  return valid();
+}
+
 export function validateAreaForm(input: {
   nome: string;
   hectares: string;
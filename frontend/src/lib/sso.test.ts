@@ -0,0 +1,17 @@
+import { describe, expect, it } from "vitest";
+import { desafioDoFragmento, mensagemErroSSO } from "@/lib/sso";
+
+describe("mensagemErroSSO", () => {
+  it("traduz os códigos do callback", () => {
+    expect(mensagemErroSSO("conta_privilegiada")).toBe("Contas administrativas entram com e-mail e senha.");
+    expect(mensagemErroSSO("outro")).toBe("Não foi possível entrar pelo provedor de identidade.");
+    expect(mensagemErroSSO(null)).toBe("");
+  });
+});
+
+describe("desafioDoFragmento", () => {
+  it("lê o desafio do fragmento", () => {
+    expect(desafioDoFragmento("#desafio=abc.def-ghi")).toBe("abc.def-ghi");
+    expect(desafioDoFragmento("")).toBe("");
+  });
+});
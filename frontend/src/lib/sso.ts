@@ -0,0 +1,22 @@
+// Login único OpenID Connect (BR-ACESSO-029): mensagens dos códigos que o callback da API devolve
+// em /login?sso_erro= e leitura do desafio de 2FA no fragmento da URL.
+
+const MENSAGENS_SSO: Record<string, string> = {
+  sessao_expirada: "O login pelo provedor expirou ou foi aberto em outro navegador. Tente novamente.",
+  negado: "O login foi cancelado no provedor de identidade.",
+  provedor: "O provedor de identidade não concluiu o login. Tente novamente.",
+  email_ausente: "O provedor de identidade não informou o seu e-mail.",
+  email_nao_verificado: "O seu e-mail não está verificado no provedor de identidade.",
+  usuario_desativado: "Usuário desativado. Procure o administrador.",
+  conta_privilegiada: "Contas administrativas entram com e-mail e senha.",
+};
+
+export function mensagemErroSSO(codigo: string | null): string {
+  if (!codigo) return "";
+  return MENSAGENS_SSO[codigo] ?? "Não foi possível entrar pelo provedor de identidade.";
+}
+
+/** Desafio do segundo fator enviado pelo callback em `#desafio=` (fica fora de logs e do Referer). */
+export function desafioDoFragmento(hash: string): string {
+  return new URLSearchParams(hash.replace(/^#/, "")).get("desafio") ?? "";
+}
@@ -17,7 +17,14 @@ import type { NextRequest } from 'next/server'
  * e o redirect funciona.
  */
 
-const PUBLIC_PATHS = new Set(['/', '/login', '/registro'])
+const PUBLIC_PATHS = new Set([
+  '/',
+  '/login',
+  '/registro',
+  '/esqueci-senha',
+  '/redefinir-senha',
+  '/verificar-email',
+])
 
 const API_URL = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8080'
 
@@ -68,7 +75,7 @@ export const config = {
      * - _next/static, _next/image (assets do Next)
      * - favicon.ico, sw.js, manifest.json, icons/ (PWA/estáticos)
      * - api/ (não há route handlers autenticados no frontend)
-     * Rotas públicas (/, /login, /registro) são tratadas no código acima.
+     * Rotas públicas (/, /login, /registro, recuperação de senha) são tratadas no código acima.
      */
     '/((?!_next/static|_next/image|favicon\\.ico|sw\\.js|manifest\\.json|icons/|api/).*)',
   ],
@@ -1,4 +1,5 @@
 import api, { type ApiResponse } from "./api";
+import type { Sessao } from "./sessoes";
 
 export type Usuario = {
   id: number;
@@ -105,6 +106,32 @@ export async function toggleUsuarioEnabled(id: number): Promise<Usuario> {
   return data.data;
 }
 
+/** Remove o 2FA de quem perdeu o autenticador e encerra as sessões do usuário (BR-ACESSO-027). */
+export async function redefinirDoisFatoresUsuario(id: number): Promise<void> {
+  await api.delete(`/api/v1/admin/usuarios/${id}/2fa`);
+}
+
+/** Sessões ativas de um utilizador (BR-ACESSO-028); `atual` é sempre false nesta visão. */
+export async function listSessoesUsuario(id: number): Promise<Sessao[]> {
+  const { data } = await api.get<ApiResponse<Sessao[]>>(
+    `/api/v1/admin/usuarios/${id}/sessoes`
+  );
+  return data.data;
+}
+
+/** Encerra uma sessão do utilizador ou, sem `sessaoId`, todas; devolve quantas. */
+export async function revogarSessoesUsuario(
+  id: number,
+  sessaoId?: number
+): Promise<number> {
+  const path =
+    sessaoId != null
+      ? `/api/v1/admin/usuarios/${id}/sessoes/${sessaoId}`
+      : `/api/v1/admin/usuarios/${id}/sessoes`;
+  const { data } = await api.delete<ApiResponse<{ revogadas: number }>>(path);
+  return data.data.revogadas;
+}
+
 export type FazendaResumo = { id: number; nome: string };
 
 export async function getFazendasByUsuario(
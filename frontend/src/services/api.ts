@@ -71,6 +71,21 @@ api.interceptors.response.use(
       return Promise.reject(error);
     }
 
+    // Perfil obrigado a 2FA com sessão só de senha (BR-ACESSO-027): levar à configuração.
+    if (
+      error.response?.status === 403 &&
+      (error.response.data as { error?: { code?: string } } | undefined)?.error
+        ?.code === "TWO_FACTOR_REQUIRED"
+    ) {
+      if (
+        typeof window !== "undefined" &&
+        window.location.pathname !== "/conta/seguranca"
+      ) {
+        window.location.href = "/conta/seguranca";
+      }
+      return Promise.reject(error);
+    }
+
     // Se receber 401 e não for uma tentativa de refresh já
     if (error.response?.status === 401 && !originalRequest?._retry) {
       originalRequest!._retry = true;
@@ -1,12 +1,24 @@
 import api from './api'
+import type { ConviteResgate } from './convites'
+
+/** Resultado do convite enviado no registro ou login (BR-ACESSO-010); a falha não bloqueia a conta. */
+export type ConviteAuthResult = {
+  convite?: ConviteResgate
+  convite_erro?: string
+}
 
 export type LoginResponse = {
   data: {
-    email: string
-    perfil: string
+    email?: string
+    perfil?: string
     nome?: string
+    /** Conta com 2FA: a senha só rende o desafio do segundo passo (BR-ACESSO-027). */
+    segundo_fator?: boolean
+    desafio?: string
+    /** Perfil obrigado a 2FA ainda sem inscrição: a sessão só acede à configuração. */
+    dois_fatores_pendente?: boolean
     // Tokens nunca vêm no JSON: o backend usa apenas cookies HttpOnly.
-  }
+  } & ConviteAuthResult
   message: string
   timestamp: string
 }
@@ -17,6 +29,10 @@ export type ValidateResponse = {
     perfil: string
     user_id: number
     nome?: string
+    /** false até o link de confirmação ser aberto (BR-ACESSO-026). */
+    email_verificado?: boolean
+    /** Perfil obrigado a 2FA numa sessão só de senha (BR-ACESSO-027). */
+    dois_fatores_pendente?: boolean
   }
   message: string
   timestamp: string
@@ -27,7 +43,9 @@ export type RegisterResponse = {
     id: number
     nome: string
     email: string
-  }
+    /** Link de confirmação de e-mail enviado no registro (BR-ACESSO-026). */
+    email_verificacao_enviada?: boolean
+  } & ConviteAuthResult
   message: string
   timestamp: string
 }
@@ -36,11 +54,35 @@ export type RegisterPayload = {
   nome: string
   email: string
   password: string
+  /** Código de convite opcional: vincula a conta à fazenda logo após o registro. */
+  convite?: string
 }
 
-export async function login(email: string, password: string): Promise<LoginResponse['data']> {
+export async function login(
+  email: string,
+  password: string,
+  convite?: string
+): Promise<LoginResponse['data']> {
   // O token é armazenado automaticamente em cookie HttpOnly pelo backend
-  const { data } = await api.post<LoginResponse>('/api/auth/login', { email, password })
+  const { data } = await api.post<LoginResponse>('/api/auth/login', {
+    email,
+    password,
+    ...(convite ? { convite } : {}),
+  })
+  return data.data
+}
+
+/** Segundo passo do login: código do autenticador (ou de recuperação) + desafio do primeiro passo. */
+export async function loginDoisFatores(
+  desafio: string,
+  codigo: string,
+  convite?: string
+): Promise<LoginResponse['data']> {
+  const { data } = await api.post<LoginResponse>('/api/auth/login/2fa', {
+    desafio,
+    codigo,
+    ...(convite ? { convite } : {}),
+  })
   return data.data
 }
 
@@ -117,3 +159,47 @@ export async function register(payload: RegisterPayload): Promise<RegisterRespon
   const { data } = await api.post<RegisterResponse>('/api/auth/register', payload)
   return data.data
 }
+
+// Recuperação de senha e verificação de e-mail (BR-ACESSO-026)
+
+/** Pede o link de redefinição; a resposta é a mesma exista ou não a conta. */
+export async function forgotPassword(email: string): Promise<void> {
+  await api.post('/api/auth/forgot-password', { email })
+}
+
+/** Troca a senha com o token do e-mail; todas as sessões da conta são encerradas. */
+export async function resetPassword(token: string, password: string): Promise<void> {
+  await api.post('/api/auth/reset-password', { token, password })
+}
+
+export async function verifyEmail(token: string): Promise<void> {
+  await api.post('/api/auth/verify-email', { token })
+}
+
+export async function reenviarVerificacaoEmail(): Promise<void> {
+  await api.post('/api/v1/me/verificacao-email')
+}
+
+/** Troca a senha da conta logada; as outras sessões saem no próximo refresh. */
+export async function alterarSenha(senhaAtual: string, novaSenha: string): Promise<void> {
+  await api.put('/api/v1/me/senha', { senha_atual: senhaAtual, nova_senha: novaSenha })
+}
+
+// Login único OpenID Connect (BR-ACESSO-029)
+
+export type OidcConfig = {
+  habilitado: boolean
+  /** Rótulo do botão "Entrar com …" (OIDC_NOME_PROVEDOR). */
+  nome?: string
+}
+
+export async function getOidcConfig(): Promise<OidcConfig> {
+  const { data } = await api.get<{ data: OidcConfig }>('/api/auth/oidc')
+  return data.data
+}
+
+/** Endereço da API que inicia o login no provedor; o navegador volta em /login. */
+export function oidcLoginUrl(redirect?: string | null): string {
+  const query = redirect ? `?redirect=${encodeURIComponent(redirect)}` : ''
+  return `${apiBaseURL()}/api/auth/oidc/login${query}`
+}
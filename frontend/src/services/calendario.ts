@@ -0,0 +1,48 @@
+import api, { type ApiResponse } from "./api";
+
+export type CalendarioEscopo = "PESSOAL" | "FAZENDA";
+
+/** Assinatura iCal da escala (BR-FOLGAS-011); o token em claro só vem na criação. */
+export type CalendarioFeed = {
+  id: number;
+  usuario_id: number;
+  fazenda_id: number;
+  fazenda_nome: string;
+  escopo: CalendarioEscopo;
+  token_prefixo: string;
+  created_at: string;
+  ultimo_uso_em?: string | null;
+};
+
+export type CalendarioFeedCriado = {
+  feed: CalendarioFeed;
+  token: string;
+  url_path: string;
+};
+
+/** URL completa para colar no app de calendário (a API serve o .ics). */
+export function calendarioFeedUrl(urlPath: string): string {
+  return `${api.defaults.baseURL}${urlPath}`;
+}
+
+/** Variante webcal:// que abre direto o app de calendário no celular. */
+export function calendarioWebcalUrl(urlPath: string): string {
+  return calendarioFeedUrl(urlPath).replace(/^https?:\/\//, "webcal://");
+}
+
+export async function getCalendarioFeeds(): Promise<CalendarioFeed[]> {
+  const { data } = await api.get<ApiResponse<CalendarioFeed[]>>("/api/v1/me/calendario");
+  return data.data ?? [];
+}
+
+export async function postCalendarioFeed(body: {
+  fazenda_id: number;
+  escopo: CalendarioEscopo;
+}): Promise<CalendarioFeedCriado> {
+  const { data } = await api.post<ApiResponse<CalendarioFeedCriado>>("/api/v1/me/calendario", body);
+  return data.data;
+}
+
+export async function deleteCalendarioFeed(id: number): Promise<void> {
+  await api.delete(`/api/v1/me/calendario/${id}`);
+}
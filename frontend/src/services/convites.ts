@@ -0,0 +1,113 @@
+import api, { type ApiResponse } from "./api";
+
+export const STATUS_CONVITE = ["PENDENTE", "USADO", "REVOGADO", "EXPIRADO"] as const;
+export type StatusConvite = (typeof STATUS_CONVITE)[number];
+
+export const STATUS_CONVITE_LABELS: Record<StatusConvite, string> = {
+  PENDENTE: "Pendente",
+  USADO: "Usado",
+  REVOGADO: "Revogado",
+  EXPIRADO: "Expirado",
+};
+
+export type PapelConvite = "TITULAR" | "OPERACIONAL";
+
+export const PAPEL_CONVITE_LABELS: Record<PapelConvite, string> = {
+  TITULAR: "Titular",
+  OPERACIONAL: "Operacional",
+};
+
+export const CONVITE_VALIDADE_DIAS_PADRAO = 7;
+export const CONVITE_VALIDADE_DIAS_MAX = 30;
+/** Símbolos do código sem os hífens de formatação (XXXX-XXXX-XXXX). */
+export const CONVITE_CODIGO_LEN = 12;
+
+export type Convite = {
+  id: number;
+  fazenda_id: number;
+  fazenda_nome: string;
+  perfil: string;
+  papel: PapelConvite;
+  /** Primeiros 4 caracteres do código, para reconhecer o convite na lista. */
+  codigo_prefixo: string;
+  observacao?: string | null;
+  expira_em: string;
+  criado_por?: number | null;
+  criado_por_nome?: string | null;
+  created_at: string;
+  usado_por?: number | null;
+  usado_por_nome?: string | null;
+  usado_em?: string | null;
+  revogado_por?: number | null;
+  revogado_em?: string | null;
+  status: StatusConvite;
+};
+
+export type ConvitePayload = {
+  perfil: string;
+  papel?: PapelConvite;
+  validade_dias?: number;
+  observacao?: string | null;
+};
+
+/** Resposta da criação: o código em claro só aparece aqui (BR-ACESSO-010). */
+export type ConviteCriado = {
+  convite: Convite;
+  codigo: string;
+  link_path: string;
+};
+
+export type ConvitePreview = {
+  fazenda_nome: string;
+  perfil: string;
+  papel: PapelConvite;
+  expira_em: string;
+};
+
+export type ConviteResgate = {
+  fazenda_id: number;
+  fazenda_nome: string;
+  papel: PapelConvite;
+  perfil: string;
+  /** Perfil USER elevado ao perfil do convite: renovar a sessão para o token refletir. */
+  perfil_alterado: boolean;
+};
+
+export async function listConvites(fazendaId: number): Promise<Convite[]> {
+  const { data } = await api.get<ApiResponse<Convite[]>>(`/api/v1/fazendas/${fazendaId}/convites`);
+  return data.data ?? [];
+}
+
+export async function criarConvite(fazendaId: number, payload: ConvitePayload): Promise<ConviteCriado> {
+  const { data } = await api.post<ApiResponse<ConviteCriado>>(
+    `/api/v1/fazendas/${fazendaId}/convites`,
+    payload
+  );
+  if (!data.data) throw new Error("Resposta inválida");
+  return data.data;
+}
+
+export async function revogarConvite(fazendaId: number, conviteId: number): Promise<Convite> {
+  const { data } = await api.post<ApiResponse<Convite>>(
+    `/api/v1/fazendas/${fazendaId}/convites/${conviteId}/revogar`
+  );
+  if (!data.data) throw new Error("Resposta inválida");
+  return data.data;
+}
+
+/** Prévia pública usada no registro e no login. */
+export async function previewConvite(codigo: string): Promise<ConvitePreview> {
+  const { data } = await api.get<ApiResponse<ConvitePreview>>(
+    `/api/auth/convites/${encodeURIComponent(codigo.trim())}`
+  );
+  if (!data.data) throw new Error("Resposta inválida");
+  return data.data;
+}
+
+export async function resgatarConvite(codigo: string): Promise<ConviteResgate> {
+  const { data } = await api.post<ApiResponse<ConviteResgate>>("/api/v1/me/convites/resgatar", {
+    codigo: codigo.trim(),
+  });
+  if (!data.data) throw new Error("Resposta inválida");
+  return data.data;
+}
@@ -0,0 +1,50 @@
+import api from './api'
+
+// Autenticação em dois fatores por TOTP (BR-ACESSO-027)
+
+export type DoisFatoresStatus = {
+  ativo: boolean
+  ativado_em?: string
+  /** O perfil exige 2FA: não pode ser desativado. */
+  obrigatorio: boolean
+  codigos_recuperacao_restantes: number
+  /** Nível da sessão atual: 1 = só senha, 2 = senha + código. */
+  nivel: number
+}
+
+export type InscricaoDoisFatores = {
+  segredo: string
+  otpauth_uri: string
+}
+
+export async function getDoisFatores(): Promise<DoisFatoresStatus> {
+  const { data } = await api.get<{ data: DoisFatoresStatus }>('/api/v1/me/2fa')
+  return data.data
+}
+
+/** Confirma a senha e gera o segredo a cadastrar no app autenticador. */
+export async function iniciarDoisFatores(senha: string): Promise<InscricaoDoisFatores> {
+  const { data } = await api.post<{ data: InscricaoDoisFatores }>('/api/v1/me/2fa/iniciar', { senha })
+  return data.data
+}
+
+/** Ativa com o primeiro código; devolve os códigos de recuperação (exibidos só desta vez). */
+export async function ativarDoisFatores(codigo: string): Promise<string[]> {
+  const { data } = await api.post<{ data: { codigos_recuperacao: string[] } }>(
+    '/api/v1/me/2fa/ativar',
+    { codigo }
+  )
+  return data.data.codigos_recuperacao
+}
+
+export async function regenerarCodigosRecuperacao(codigo: string): Promise<string[]> {
+  const { data } = await api.post<{ data: { codigos_recuperacao: string[] } }>(
+    '/api/v1/me/2fa/codigos-recuperacao',
+    { codigo }
+  )
+  return data.data.codigos_recuperacao
+}
+
+export async function desativarDoisFatores(senha: string, codigo: string): Promise<void> {
+  await api.post('/api/v1/me/2fa/desativar', { senha, codigo })
+}
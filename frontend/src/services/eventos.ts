@@ -0,0 +1,34 @@
+import api from "./api";
+
+export type EventoEntidade = "alerta" | "producao" | "restricao_leite";
+
+/** Aviso de mudança na fazenda enviado pelo stream SSE (`GET /api/v1/me/eventos`). */
+export type EventoTempoReal = {
+  entidade: EventoEntidade;
+  acao: "CREATE" | "UPDATE" | "DELETE" | "RESTORE";
+  fazenda_id: number;
+  id?: number;
+  animal_id?: number;
+};
+
+export const EVENTO_ENTIDADES: EventoEntidade[] = [
+  "alerta",
+  "producao",
+  "restricao_leite",
+];
+
+export function eventosStreamUrl(fazendaId: number): string {
+  return `${api.defaults.baseURL}/api/v1/me/eventos?fazenda_id=${fazendaId}`;
+}
+
+/** Prefixos de queryKey a invalidar quando chega um evento da entidade. */
+export function queryKeysParaEvento(entidade: EventoEntidade): string[][] {
+  switch (entidade) {
+    case "alerta":
+      return [["alertas"]];
+    case "producao":
+      return [["producao"], ["resumo-pecuario"]];
+    case "restricao_leite":
+      return [["restricoes-leite"], ["resumo-pecuario"], ["animais", "contexto"]];
+  }
+}
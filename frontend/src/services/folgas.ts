@@ -1,14 +1,37 @@
 import api, { type ApiResponse } from "./api";
 
-export type FolgasEscalaConfig = {
+/** Equipe de rodízio (BR-FOLGAS-008): folga de `folgas_por_ciclo` dias a cada `ciclo_dias`, a partir do deslocamento. */
+export type FolgasEquipe = {
+  id: number;
   fazenda_id: number;
+  nome: string;
   data_anchor: string;
-  usuario_slot_0: number;
-  usuario_slot_1: number;
-  usuario_slot_2: number;
+  ciclo_dias: number;
+  folgas_por_ciclo: number;
+  participantes: {
+    usuario_id: number;
+    usuario_nome?: string;
+    posicao: number;
+    deslocamento: number;
+  }[];
   updated_at: string;
 };
 
+export type FolgasEscalaConfig = {
+  fazenda_id: number;
+  equipes: FolgasEquipe[];
+};
+
+export type FolgasEquipePayload = {
+  id?: number;
+  nome: string;
+  data_anchor: string;
+  ciclo_dias: number;
+  folgas_por_ciclo: number;
+  /** Sem `deslocamento`, o backend usa posição × folgas por ciclo. */
+  participantes: { usuario_id: number; deslocamento?: number }[];
+};
+
 export type EscalaFolga = {
   id: number;
   fazenda_id: number;
@@ -28,24 +51,65 @@ export type EscalaFolga = {
   rodizio_esperado_usuario_nome?: string | null;
 };
 
+export type FolgasRodizioPrevisto = {
+  usuario_id: number;
+  usuario_nome?: string;
+  equipe_id: number;
+  equipe_nome: string;
+};
+
 export type FolgasRodizioDia = {
   data: string;
   tem_folga: boolean;
+  /** Preenchidos só quando há exatamente uma folga prevista no dia. */
   usuario_id?: number | null;
   usuario_nome?: string | null;
+  previstos?: FolgasRodizioPrevisto[];
 };
 
 export type FolgasEscalaListResponse = {
   linhas: EscalaFolga[];
   rodizio_por_dia: FolgasRodizioDia[];
+  ausencias: FolgaAusencia[];
+};
+
+/** `AUSENCIA` é o tipo exibido a colegas sem gestão (não expõe atestado). */
+export type FolgaAusenciaTipo = "FERIAS" | "ATESTADO" | "LICENCA_NAO_REMUNERADA" | "AUSENCIA";
+
+/** Férias, atestado ou licença (BR-FOLGAS-010); datas inclusivas. */
+export type FolgaAusencia = {
+  id: number;
+  fazenda_id: number;
+  usuario_id: number;
+  usuario_nome?: string;
+  tipo: FolgaAusenciaTipo;
+  data_inicio: string;
+  data_fim: string;
+  anexo_ref?: string | null;
+  observacao?: string | null;
+  created_at: string;
+  updated_at: string;
+};
+
+export type FolgaFeriasSaldo = {
+  usuario_id: number;
+  usuario_nome: string;
+  ano: number;
+  dias_direito: number;
+  dias_usados: number;
+  saldo: number;
 };
 
 export type FolgaEquidadeResumo = {
   usuario_id: number;
   usuario_nome: string;
+  equipe_id: number;
+  equipe_nome: string;
   folgas_registradas: number;
   folgas_teoricas_auto: number;
   delta: number;
+  /** Dias de férias/atestado/licença no período (já descontados das previstas). */
+  dias_ausente: number;
 };
 
 export type FolgaAlteracao = {
@@ -59,8 +123,43 @@ export type FolgaAlteracao = {
 
 export type FolgaAlertaDia = {
   data: string;
+  tipo: "CONFLITO" | "TROCA_PENDENTE" | "DESFALQUE";
   quantidade_folga: number;
   motivo_alerta: string;
+  equipe_id?: number | null;
+  equipe_nome?: string | null;
+  /** Presente quando o alerta é uma troca pendente (BR-FOLGAS-009). */
+  troca_id?: number | null;
+  /** Participantes ausentes no dia (alerta DESFALQUE, BR-FOLGAS-010). */
+  ausentes?: number;
+};
+
+export type FolgaTrocaStatus =
+  | "PENDENTE_COLEGA"
+  | "PENDENTE_GESTAO"
+  | "APROVADA"
+  | "RECUSADA"
+  | "REJEITADA"
+  | "CANCELADA";
+
+/** Troca de folga: o solicitante cede `data_solicitante` e assume a folga do colega em `data_colega`. */
+export type FolgaTroca = {
+  id: number;
+  fazenda_id: number;
+  solicitante_id: number;
+  solicitante_nome?: string;
+  colega_id: number;
+  colega_nome?: string;
+  data_solicitante: string;
+  data_colega: string;
+  motivo: string;
+  status: FolgaTrocaStatus;
+  respondido_em?: string | null;
+  decidido_por?: number | null;
+  decidido_em?: string | null;
+  observacao_decisao?: string | null;
+  created_at: string;
+  updated_at: string;
 };
 
 export type UsuarioVinculado = {
@@ -102,12 +201,7 @@ export async function getFolgasConfig(fazendaId: number): Promise<FolgasEscalaCo
 
 export async function putFolgasConfig(
   fazendaId: number,
-  payload: {
-    data_anchor: string;
-    usuario_slot_0: number;
-    usuario_slot_1: number;
-    usuario_slot_2: number;
-  }
+  payload: { equipes: FolgasEquipePayload[] }
 ): Promise<FolgasEscalaConfig> {
   const { data } = await api.put<ApiResponse<FolgasEscalaConfig>>(
     `/api/v1/fazendas/${fazendaId}/folgas/config`,
@@ -127,13 +221,14 @@ export async function getFolgasEscala(
     { params: { inicio, fim } }
   );
   const raw = data.data;
-  if (!raw) return { linhas: [], rodizio_por_dia: [] };
+  if (!raw) return { linhas: [], rodizio_por_dia: [], ausencias: [] };
   if (Array.isArray(raw)) {
-    return { linhas: raw as EscalaFolga[], rodizio_por_dia: [] };
+    return { linhas: raw as EscalaFolga[], rodizio_por_dia: [], ausencias: [] };
   }
   return {
     linhas: raw.linhas ?? [],
     rodizio_por_dia: raw.rodizio_por_dia ?? [],
+    ausencias: raw.ausencias ?? [],
   };
 }
 
@@ -192,3 +287,94 @@ export async function getFolgasAlertas(
   );
   return data.data ?? [];
 }
+
+export async function getFolgasTrocas(fazendaId: number, status?: FolgaTrocaStatus): Promise<FolgaTroca[]> {
+  const { data } = await api.get<ApiResponse<FolgaTroca[]>>(
+    `/api/v1/fazendas/${fazendaId}/folgas/trocas`,
+    { params: status ? { status } : undefined }
+  );
+  return data.data ?? [];
+}
+
+export async function postFolgasTroca(
+  fazendaId: number,
+  body: { colega_id: number; data_solicitante: string; data_colega: string; motivo: string }
+): Promise<FolgaTroca> {
+  const { data } = await api.post<ApiResponse<FolgaTroca>>(
+    `/api/v1/fazendas/${fazendaId}/folgas/trocas`,
+    body
+  );
+  if (!data.data) throw new Error("Resposta inválida");
+  return data.data;
+}
+
+export async function postFolgasTrocaResposta(
+  fazendaId: number,
+  trocaId: number,
+  aceitar: boolean
+): Promise<void> {
+  await api.post(`/api/v1/fazendas/${fazendaId}/folgas/trocas/${trocaId}/resposta`, { aceitar });
+}
+
+export async function postFolgasTrocaDecisao(
+  fazendaId: number,
+  trocaId: number,
+  body: { aprovar: boolean; observacao?: string }
+): Promise<void> {
+  await api.post(`/api/v1/fazendas/${fazendaId}/folgas/trocas/${trocaId}/decisao`, body);
+}
+
+export async function postFolgasTrocaCancelar(fazendaId: number, trocaId: number): Promise<void> {
+  await api.post(`/api/v1/fazendas/${fazendaId}/folgas/trocas/${trocaId}/cancelar`);
+}
+
+export async function getFolgasAusencias(
+  fazendaId: number,
+  inicio: string,
+  fim: string
+): Promise<FolgaAusencia[]> {
+  const { data } = await api.get<ApiResponse<FolgaAusencia[]>>(
+    `/api/v1/fazendas/${fazendaId}/folgas/ausencias`,
+    { params: { inicio, fim } }
+  );
+  return data.data ?? [];
+}
+
+export async function postFolgasAusencia(
+  fazendaId: number,
+  body: {
+    usuario_id: number;
+    tipo: Exclude<FolgaAusenciaTipo, "AUSENCIA">;
+    data_inicio: string;
+    data_fim: string;
+    anexo_ref?: string;
+    observacao?: string;
+  }
+): Promise<FolgaAusencia> {
+  const { data } = await api.post<ApiResponse<FolgaAusencia>>(
+    `/api/v1/fazendas/${fazendaId}/folgas/ausencias`,
+    body
+  );
+  if (!data.data) throw new Error("Resposta inválida");
+  return data.data;
+}
+
+export async function deleteFolgasAusencia(fazendaId: number, ausenciaId: number): Promise<void> {
+  await api.delete(`/api/v1/fazendas/${fazendaId}/folgas/ausencias/${ausenciaId}`);
+}
+
+export async function getFolgasFeriasSaldo(fazendaId: number, ano: number): Promise<FolgaFeriasSaldo[]> {
+  const { data } = await api.get<ApiResponse<FolgaFeriasSaldo[]>>(
+    `/api/v1/fazendas/${fazendaId}/folgas/ferias-saldo`,
+    { params: { ano } }
+  );
+  return data.data ?? [];
+}
+
+export async function putFolgasFeriasDireito(
+  fazendaId: number,
+  usuarioId: number,
+  body: { ano: number; dias: number }
+): Promise<void> {
+  await api.put(`/api/v1/fazendas/${fazendaId}/folgas/ferias-saldo/${usuarioId}`, body);
+}
@@ -7,6 +7,9 @@ export type Lote = {
   tipo?: string | null;
   descricao?: string | null;
   ativo: boolean;
+  /** Capacidade opcional; `ocupacao` conta só animais no rebanho (BR-LOTE-008). */
+  capacidade?: number | null;
+  ocupacao: number;
   created_at: string;
   updated_at: string;
 };
@@ -17,6 +20,7 @@ export type LoteCreate = {
   tipo?: string | null;
   descricao?: string | null;
   ativo?: boolean;
+  capacidade?: number | null;
 };
 
 export type LoteUpdate = {
@@ -24,6 +28,7 @@ export type LoteUpdate = {
   tipo?: string | null;
   descricao?: string | null;
   ativo?: boolean;
+  capacidade?: number | null;
 };
 
 export async function listByFazenda(fazendaId: number): Promise<Lote[]> {
@@ -51,3 +56,180 @@ export async function update(id: number, payload: LoteUpdate): Promise<Lote> {
 export async function remove(id: number): Promise<void> {
   await api.delete(`/api/v1/lotes/${id}`);
 }
+
+export type PropostaMovimentacaoLote = {
+  id: number;
+  fazenda_id: number;
+  animal_id: number;
+  animal_identificacao: string;
+  lote_origem_id?: number | null;
+  lote_origem_nome?: string | null;
+  lote_destino_id: number;
+  lote_destino_nome: string;
+  motivo: "PRE_PARTO" | "RECRIA" | "ENGORDA";
+  referencia: string;
+  descricao: string;
+  status: "PENDENTE" | "APROVADA" | "REJEITADA" | "OBSOLETA";
+  movimentacao_id?: number | null;
+  decidido_por?: number | null;
+  decidido_em?: string | null;
+  created_at: string;
+};
+
+export type ResultadoDecisaoPropostas = {
+  processadas: number;
+  obsoletas: number;
+  falhas: { id: number; erro: string }[];
+  avisos?: string[];
+};
+
+/** GET /api/v1/fazendas/:id/movimentacoes-lote/propostas (BR-LOTE-006). */
+export async function listPropostas(fazendaId: number, status = "PENDENTE"): Promise<PropostaMovimentacaoLote[]> {
+  const { data } = await api.get<ApiResponse<PropostaMovimentacaoLote[]>>(
+    `/api/v1/fazendas/${fazendaId}/movimentacoes-lote/propostas`,
+    { params: { status } }
+  );
+  return data.data ?? [];
+}
+
+export async function aprovarPropostas(fazendaId: number, ids: number[]): Promise<ResultadoDecisaoPropostas> {
+  const { data } = await api.post<ApiResponse<ResultadoDecisaoPropostas>>(
+    `/api/v1/fazendas/${fazendaId}/movimentacoes-lote/propostas/aprovar`,
+    { ids }
+  );
+  if (!data.data) throw new Error("Resposta invalida");
+  return data.data;
+}
+
+export async function rejeitarPropostas(fazendaId: number, ids: number[]): Promise<ResultadoDecisaoPropostas> {
+  const { data } = await api.post<ApiResponse<ResultadoDecisaoPropostas>>(
+    `/api/v1/fazendas/${fazendaId}/movimentacoes-lote/propostas/rejeitar`,
+    { ids }
+  );
+  if (!data.data) throw new Error("Resposta invalida");
+  return data.data;
+}
+
+export type MovimentarEmLotePayload = {
+  animal_ids?: number[];
+  filtro?: {
+    lote_origem_id?: number;
+    categoria?: string;
+    sexo?: string;
+    status_reprodutivo?: string;
+    status_saude?: string;
+  };
+  data?: string;
+  motivo?: string;
+};
+
+export type ResultadoMovimentacaoEmLote = {
+  movimentados: number;
+  ignorados: number[];
+  movimentacoes: { id: number; animal_id: number }[];
+  lote?: Lote;
+  avisos?: string[];
+};
+
+/** POST /api/v1/lotes/:id/movimentar (BR-LOTE-009). Falhas por animal vêm no erro 400 (`details`). */
+export async function movimentarEmLote(loteId: number, payload: MovimentarEmLotePayload): Promise<ResultadoMovimentacaoEmLote> {
+  const { data } = await api.post<ApiResponse<ResultadoMovimentacaoEmLote>>(`/api/v1/lotes/${loteId}/movimentar`, payload);
+  if (!data.data) throw new Error("Resposta invalida");
+  return data.data;
+}
+
+export type LoteOcupacaoHistorico = {
+  lote_id: number;
+  capacidade?: number | null;
+  ocupacao_atual: number;
+  de: string;
+  ate: string;
+  serie: { data: string; ocupacao: number; entradas: number; saidas: number }[];
+  eventos: {
+    tipo: "ENTRADA" | "SAIDA" | "BAIXA";
+    data: string;
+    animal_id: number;
+    animal_identificacao: string;
+    movimentacao_id?: number | null;
+    outro_lote_id?: number | null;
+    outro_lote_nome?: string | null;
+    motivo?: string | null;
+  }[];
+};
+
+/** GET /api/v1/lotes/:id/ocupacao (BR-LOTE-010). */
+export async function getOcupacao(loteId: number, params?: { de?: string; ate?: string }): Promise<LoteOcupacaoHistorico> {
+  const { data } = await api.get<ApiResponse<LoteOcupacaoHistorico>>(`/api/v1/lotes/${loteId}/ocupacao`, { params });
+  if (!data.data) throw new Error("Resposta invalida");
+  return data.data;
+}
+
+export type LoteProducaoResumo = {
+  litros_total: number;
+  dias_com_producao: number;
+  media_diaria_litros: number;
+  media_por_animal_dia: number;
+};
+
+export type LoteIndicadores = {
+  animais: number;
+  em_lactacao: number;
+  del_medio?: number | null;
+  casos_saude_abertos: number;
+  animais_com_caso_aberto: number;
+  prenhes: number;
+  por_status_reprodutivo: Record<string, number>;
+};
+
+export type LoteDesempenho = {
+  lote_id: number;
+  lote_nome: string;
+  tipo?: string | null;
+  de: string;
+  ate: string;
+  producao: LoteProducaoResumo;
+  serie: { data: string; litros: number; animais_ordenhados: number; media_por_animal: number }[];
+  indicadores: LoteIndicadores;
+  comparacao?: {
+    marco: string;
+    antes: LoteProducaoResumo;
+    depois: LoteProducaoResumo;
+    variacao_pct?: number | null;
+  } | null;
+};
+
+export type LotesDesempenho = {
+  fazenda_id: number;
+  de: string;
+  ate: string;
+  lotes: {
+    lote_id?: number | null;
+    lote_nome?: string | null;
+    tipo?: string | null;
+    ativo: boolean;
+    producao: LoteProducaoResumo;
+    indicadores: LoteIndicadores;
+  }[];
+};
+
+/** GET /api/v1/lotes/:id/desempenho (BR-LOTE-011/012); `marco` compara antes/depois. */
+export async function getDesempenho(
+  loteId: number,
+  params?: { de?: string; ate?: string; marco?: string }
+): Promise<LoteDesempenho> {
+  const { data } = await api.get<ApiResponse<LoteDesempenho>>(`/api/v1/lotes/${loteId}/desempenho`, { params });
+  if (!data.data) throw new Error("Resposta invalida");
+  return data.data;
+}
+
+/** GET /api/v1/fazendas/:id/lotes/desempenho (BR-LOTE-011/012). */
+export async function getDesempenhoFazenda(
+  fazendaId: number,
+  params?: { de?: string; ate?: string }
+): Promise<LotesDesempenho> {
+  const { data } = await api.get<ApiResponse<LotesDesempenho>>(`/api/v1/fazendas/${fazendaId}/lotes/desempenho`, {
+    params,
+  });
+  if (!data.data) throw new Error("Resposta invalida");
+  return data.data;
+}
@@ -0,0 +1,35 @@
+import api from './api'
+
+// Sessões ativas por dispositivo (BR-ACESSO-028)
+
+export type Sessao = {
+  /** Identificador da sessão (família de refresh tokens). */
+  id: number
+  iniciada_em: string
+  /** Última renovação da sessão (o access token renova a cada 15 min de uso). */
+  ultimo_uso_em: string
+  expira_em: string
+  user_agent?: string
+  ip?: string
+  /** 1 = só senha, 2 = senha + código (BR-ACESSO-027). */
+  aal: number
+  /** Sessão deste navegador. */
+  atual: boolean
+}
+
+export async function listSessoes(): Promise<Sessao[]> {
+  const { data } = await api.get<{ data: Sessao[] }>('/api/v1/me/sessoes')
+  return data.data
+}
+
+/** Encerra uma sessão; `atual` true quando era a deste navegador (cookies já apagados). */
+export async function revogarSessao(id: number): Promise<{ atual: boolean }> {
+  const { data } = await api.delete<{ data: { atual: boolean } }>(`/api/v1/me/sessoes/${id}`)
+  return data.data
+}
+
+/** Encerra as sessões dos outros dispositivos; devolve quantas. */
+export async function revogarOutrasSessoes(): Promise<number> {
+  const { data } = await api.delete<{ data: { revogadas: number } }>('/api/v1/me/sessoes')
+  return data.data.revogadas
+}
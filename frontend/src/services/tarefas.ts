@@ -0,0 +1,192 @@
+import api, { type ApiResponse } from "./api";
+
+export const STATUS_TAREFA = ["ABERTA", "EM_ANDAMENTO", "CONCLUIDA", "CANCELADA"] as const;
+export type StatusTarefa = (typeof STATUS_TAREFA)[number];
+
+export const STATUS_TAREFA_LABELS: Record<StatusTarefa, string> = {
+  ABERTA: "Aberta",
+  EM_ANDAMENTO: "Em andamento",
+  CONCLUIDA: "Concluída",
+  CANCELADA: "Cancelada",
+};
+
+export const RECORRENCIAS_TAREFA = ["NENHUMA", "DIARIA", "SEMANAL"] as const;
+export type RecorrenciaTarefa = (typeof RECORRENCIAS_TAREFA)[number];
+
+export const RECORRENCIA_TAREFA_LABELS: Record<RecorrenciaTarefa, string> = {
+  NENHUMA: "Não repete",
+  DIARIA: "Todo dia",
+  SEMANAL: "Toda semana",
+};
+
+export type TarefaChecklistItem = {
+  id: number;
+  tarefa_id: number;
+  ordem: number;
+  texto: string;
+  feito: boolean;
+  feito_por?: number | null;
+  feito_em?: string | null;
+};
+
+export type Tarefa = {
+  id: number;
+  fazenda_id: number;
+  titulo: string;
+  descricao?: string | null;
+  animal_id?: number | null;
+  lote_id?: number | null;
+  area_id?: number | null;
+  alerta_id?: number | null;
+  responsavel_id?: number | null;
+  data_prevista: string;
+  recorrencia: RecorrenciaTarefa;
+  serie_id?: number | null;
+  status: StatusTarefa;
+  concluida_por?: number | null;
+  concluida_em?: string | null;
+  created_by?: number | null;
+  created_at: string;
+  updated_at: string;
+  animal_identificacao?: string | null;
+  lote_nome?: string | null;
+  area_nome?: string | null;
+  responsavel_nome?: string | null;
+  checklist: TarefaChecklistItem[];
+  atrasada: boolean;
+  /** Responsável de folga ou ausente na data prevista (BR-TAREFA-004). */
+  responsavel_de_folga: boolean;
+};
+
+export type TarefasListParams = {
+  status?: StatusTarefa;
+  abertas?: boolean;
+  /** "me" | "nenhum" | id do utilizador */
+  responsavel?: string;
+  inicio?: string;
+  fim?: string;
+  limit?: number;
+  offset?: number;
+};
+
+export type TarefasListResponse = {
+  tarefas: Tarefa[];
+  total: number;
+};
+
+export type MinhasTarefasHoje = {
+  data: string;
+  de_folga: boolean;
+  /** FOLGA ou o tipo da ausência (FERIAS, ATESTADO, LICENCA_NAO_REMUNERADA). */
+  motivo?: string;
+  pendentes: number;
+  tarefas: Tarefa[];
+};
+
+export type TarefaPayload = {
+  titulo: string;
+  descricao?: string | null;
+  animal_id?: number | null;
+  lote_id?: number | null;
+  area_id?: number | null;
+  responsavel_id?: number | null;
+  data_prevista: string;
+  recorrencia: RecorrenciaTarefa;
+  /** Na edição, omitir mantém os itens atuais. */
+  checklist?: string[];
+};
+
+export type ConverterAlertaPayload = {
+  responsavel_id?: number | null;
+  data_prevista?: string | null;
+  checklist?: string[];
+};
+
+export async function listTarefas(
+  fazendaId: number,
+  params: TarefasListParams = {}
+): Promise<TarefasListResponse> {
+  const { data } = await api.get<ApiResponse<TarefasListResponse>>(
+    `/api/v1/fazendas/${fazendaId}/tarefas`,
+    { params }
+  );
+  return data.data ?? { tarefas: [], total: 0 };
+}
+
+export async function getMinhasTarefasHoje(
+  fazendaId: number,
+  dataYmd: string
+): Promise<MinhasTarefasHoje> {
+  const { data } = await api.get<ApiResponse<MinhasTarefasHoje>>(
+    `/api/v1/fazendas/${fazendaId}/tarefas/minhas-hoje`,
+    { params: { data: dataYmd } }
+  );
+  if (!data.data) throw new Error("Resposta inválida");
+  return data.data;
+}
+
+export async function createTarefa(fazendaId: number, payload: TarefaPayload): Promise<Tarefa> {
+  const { data } = await api.post<ApiResponse<Tarefa>>(
+    `/api/v1/fazendas/${fazendaId}/tarefas`,
+    payload
+  );
+  if (!data.data) throw new Error("Resposta inválida");
+  return data.data;
+}
+
+export async function updateTarefa(
+  fazendaId: number,
+  tarefaId: number,
+  payload: TarefaPayload
+): Promise<Tarefa> {
+  const { data } = await api.put<ApiResponse<Tarefa>>(
+    `/api/v1/fazendas/${fazendaId}/tarefas/${tarefaId}`,
+    payload
+  );
+  if (!data.data) throw new Error("Resposta inválida");
+  return data.data;
+}
+
+export async function updateTarefaStatus(
+  fazendaId: number,
+  tarefaId: number,
+  status: StatusTarefa
+): Promise<Tarefa> {
+  const { data } = await api.patch<ApiResponse<Tarefa>>(
+    `/api/v1/fazendas/${fazendaId}/tarefas/${tarefaId}/status`,
+    { status }
+  );
+  if (!data.data) throw new Error("Resposta inválida");
+  return data.data;
+}
+
+export async function marcarChecklistTarefa(
+  fazendaId: number,
+  tarefaId: number,
+  itemId: number,
+  feito: boolean
+): Promise<Tarefa> {
+  const { data } = await api.patch<ApiResponse<Tarefa>>(
+    `/api/v1/fazendas/${fazendaId}/tarefas/${tarefaId}/checklist/${itemId}`,
+    { feito }
+  );
+  if (!data.data) throw new Error("Resposta inválida");
+  return data.data;
+}
+
+export async function deleteTarefa(fazendaId: number, tarefaId: number): Promise<void> {
+  await api.delete(`/api/v1/fazendas/${fazendaId}/tarefas/${tarefaId}`);
+}
+
+export async function converterAlertaEmTarefa(
+  fazendaId: number,
+  alertaId: number,
+  payload: ConverterAlertaPayload = {}
+): Promise<Tarefa> {
+  const { data } = await api.post<ApiResponse<Tarefa>>(
+    `/api/v1/fazendas/${fazendaId}/alertas/${alertaId}/tarefa`,
+    payload
+  );
+  if (!data.data) throw new Error("Resposta inválida");
+  return data.data;
+}